@@ -0,0 +1,415 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Users with this path prefix are service accounts, the only ones that can have API keys
+	SERVICE_ACCOUNT_PATH = "/serviceaccount/"
+
+	// Random bytes of API key secrets
+	API_KEY_SECRET_LENGTH = 32
+
+	// Last use of an API key is only stored when previous one is older
+	API_KEY_LAST_USED_PRECISION = time.Minute
+)
+
+// TYPE DEFINITIONS
+
+// ApiKey domain. Key, with format <id>.<secret>, is only returned when API key is created or rotated,
+// the secret is stored hashed.
+type ApiKey struct {
+	ID         string     `json:"id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	UserID     string     `json:"-"`
+	ExternalID string     `json:"externalId,omitempty"`
+	Key        string     `json:"key,omitempty"`
+	SecretHash string     `json:"-"`
+	CreateAt   time.Time  `json:"createAt,omitempty"`
+	UpdateAt   time.Time  `json:"updateAt,omitempty"`
+	ExpireAt   *time.Time `json:"expireAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func (k ApiKey) String() string {
+	return fmt.Sprintf("[id: %v, name: %v, externalId: %v, createAt: %v, updateAt: %v, expireAt: %v]",
+		k.ID, k.Name, k.ExternalID, k.CreateAt.Format("2006-01-02 15:04:05 MST"),
+		k.UpdateAt.Format("2006-01-02 15:04:05 MST"), formatOptionalTime(k.ExpireAt))
+}
+
+// IsExpired checks if API key has an expiration time before now
+func (k ApiKey) IsExpired(now time.Time) bool {
+	return k.ExpireAt != nil && !now.Before(*k.ExpireAt)
+}
+
+// API KEY API IMPLEMENTATION
+
+func (api WorkerAPI) AddApiKey(requestInfo RequestInfo, externalId string, name string, expireAt *time.Time) (*ApiKey, error) {
+	api, span := api.startSpan(&requestInfo, "AddApiKey")
+	defer span.End()
+
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	if expireAt != nil && !expireAt.After(time.Now()) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: expireAt %v, it must be a future time", expireAt.UTC()),
+		}
+	}
+
+	// Retrieve service account and check restrictions
+	user, err := api.getAuthorizedServiceAccount(requestInfo, externalId, API_KEY_ACTION_CREATE_API_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, secret, err := createApiKey(user, name, expireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create API key
+	createdApiKey, err := api.ApiKeyRepo.AddApiKey(apiKey)
+
+	// Check if there is an unexpected error in DB
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("API key created %v", createdApiKey))
+	createdApiKey.ExternalID = user.ExternalID
+	createdApiKey.Key = formatApiKey(createdApiKey.ID, secret)
+	return createdApiKey, nil
+}
+
+func (api WorkerAPI) GetApiKey(requestInfo RequestInfo, externalId string, id string) (*ApiKey, error) {
+	api, span := api.startSpan(&requestInfo, "GetApiKey")
+	defer span.End()
+
+	// Retrieve service account and check restrictions
+	user, err := api.getAuthorizedServiceAccount(requestInfo, externalId, API_KEY_ACTION_GET_API_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.getApiKeyOfUser(user, id)
+}
+
+func (api WorkerAPI) ListApiKeys(requestInfo RequestInfo, filter *Filter) ([]ApiKey, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListApiKeys")
+	defer span.End()
+
+	// Validate fields
+	var total int
+	orderByValidColumns := api.ApiKeyRepo.OrderByValidColumns(API_KEY_ACTION_LIST_API_KEYS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Retrieve service account and check restrictions
+	user, err := api.getAuthorizedServiceAccount(requestInfo, filter.ExternalID, API_KEY_ACTION_LIST_API_KEYS)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the API keys
+	apiKeys, total, err := api.ApiKeyRepo.GetApiKeysByUserID(user.ID, filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	for i := range apiKeys {
+		apiKeys[i].ExternalID = user.ExternalID
+	}
+
+	return apiKeys, total, nil
+}
+
+func (api WorkerAPI) RotateApiKey(requestInfo RequestInfo, externalId string, id string) (*ApiKey, error) {
+	api, span := api.startSpan(&requestInfo, "RotateApiKey")
+	defer span.End()
+
+	// Retrieve service account and check restrictions
+	user, err := api.getAuthorizedServiceAccount(requestInfo, externalId, API_KEY_ACTION_ROTATE_API_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	oldApiKey, err := api.getApiKeyOfUser(user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateApiKeySecret()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := *oldApiKey
+	apiKey.SecretHash = hashApiKeySecret(secret)
+	apiKey.UpdateAt = time.Now().UTC()
+	apiKey.LastUsedAt = nil
+
+	// Update API key
+	updatedApiKey, err := api.ApiKeyRepo.UpdateApiKey(apiKey)
+
+	// Check unexpected DB error
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("API key rotated %v", updatedApiKey))
+	updatedApiKey.ExternalID = user.ExternalID
+	updatedApiKey.Key = formatApiKey(updatedApiKey.ID, secret)
+	return updatedApiKey, nil
+}
+
+func (api WorkerAPI) RemoveApiKey(requestInfo RequestInfo, externalId string, id string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveApiKey")
+	defer span.End()
+
+	// Retrieve service account and check restrictions
+	user, err := api.getAuthorizedServiceAccount(requestInfo, externalId, API_KEY_ACTION_DELETE_API_KEY)
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := api.getApiKeyOfUser(user, id)
+	if err != nil {
+		return err
+	}
+
+	err = api.ApiKeyRepo.RemoveApiKey(apiKey.ID)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("API key deleted %v", apiKey))
+	return nil
+}
+
+func (api WorkerAPI) AuthenticateApiKey(id string, secret string) (string, error) {
+	// Same error for any invalid key, to not reveal which part is wrong
+	authError := &Error{
+		Code:    AUTHENTICATION_API_ERROR,
+		Message: fmt.Sprintf("Invalid API key %v", id),
+	}
+	if _, err := uuid.FromString(id); err != nil {
+		return "", authError
+	}
+
+	apiKey, err := api.ApiKeyRepo.GetApiKeyByID(id)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if dbError.Code == database.API_KEY_NOT_FOUND {
+			return "", authError
+		}
+		return "", &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	if subtle.ConstantTimeCompare([]byte(hashApiKeySecret(secret)), []byte(apiKey.SecretHash)) != 1 {
+		return "", authError
+	}
+
+	now := time.Now().UTC()
+	if apiKey.IsExpired(now) {
+		return "", &Error{
+			Code:    AUTHENTICATION_API_ERROR,
+			Message: fmt.Sprintf("API key %v expired at %v", id, apiKey.ExpireAt.UTC()),
+		}
+	}
+
+	// Check owner is still a service account
+	user, err := api.UserRepo.GetUserByExternalID(apiKey.ExternalID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return "", &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	if !user.IsServiceAccount() {
+		return "", &Error{
+			Code:    AUTHENTICATION_API_ERROR,
+			Message: fmt.Sprintf("API key %v belongs to user %v, that isn't a service account", id, user.ExternalID),
+		}
+	}
+
+	// Store last use, skipping writes if it was recently used
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= API_KEY_LAST_USED_PRECISION {
+		if err := api.ApiKeyRepo.UpdateApiKeyLastUsed(apiKey.ID, now); err != nil {
+			Log.Warnf("Couldn't store last use of API key %v: %v", apiKey.ID, err)
+		}
+	}
+
+	return user.ExternalID, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// getAuthorizedServiceAccount retrieves user, checking it's a service account and requester is
+// allowed to do action over it
+func (api WorkerAPI) getAuthorizedServiceAccount(requestInfo RequestInfo, externalId string, action string) (*User, error) {
+	user, err := api.GetUserByExternalID(requestInfo, externalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsServiceAccount() {
+		return nil, &Error{
+			Code: INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("User with externalId %v isn't a service account, its path must start with %v",
+				externalId, SERVICE_ACCOUNT_PATH),
+		}
+	}
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, action, []User{*user})
+	if err != nil {
+		return nil, err
+	}
+	if len(usersFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, user.Urn),
+		}
+	}
+
+	return user, nil
+}
+
+// getApiKeyOfUser retrieves API key, checking it belongs to user
+func (api WorkerAPI) getApiKeyOfUser(user *User, id string) (*ApiKey, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: id %v", id),
+		}
+	}
+
+	// Call repo to retrieve the API key
+	apiKey, err := api.ApiKeyRepo.GetApiKeyByID(id)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		// API key doesn't exist in DB
+		if dbError.Code == database.API_KEY_NOT_FOUND {
+			return nil, &Error{
+				Code:    API_KEY_BY_ID_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	if apiKey.UserID != user.ID {
+		return nil, &Error{
+			Code:    API_KEY_BY_ID_NOT_FOUND,
+			Message: fmt.Sprintf("API key with id %v not found for user %v", id, user.ExternalID),
+		}
+	}
+
+	return apiKey, nil
+}
+
+func createApiKey(user *User, name string, expireAt *time.Time) (ApiKey, string, error) {
+	secret, err := generateApiKeySecret()
+	if err != nil {
+		return ApiKey{}, "", err
+	}
+	if expireAt != nil {
+		utcExpireAt := expireAt.UTC()
+		expireAt = &utcExpireAt
+	}
+
+	apiKey := ApiKey{
+		ID:         uuid.NewV4().String(),
+		Name:       name,
+		UserID:     user.ID,
+		ExternalID: user.ExternalID,
+		SecretHash: hashApiKeySecret(secret),
+		CreateAt:   time.Now().UTC(),
+		UpdateAt:   time.Now().UTC(),
+		ExpireAt:   expireAt,
+	}
+
+	return apiKey, secret, nil
+}
+
+func generateApiKeySecret() (string, error) {
+	secret := make([]byte, API_KEY_SECRET_LENGTH)
+	if _, err := rand.Read(secret); err != nil {
+		return "", &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: fmt.Sprintf("Couldn't generate API key secret: %v", err),
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashApiKeySecret returns hex SHA-256 of secret. Secrets are random, so a slow hash isn't needed.
+func hashApiKeySecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func formatApiKey(id string, secret string) string {
+	return id + "." + secret
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format("2006-01-02 15:04:05 MST")
+}
@@ -0,0 +1,704 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testApiKeyID = "7c2f2ec1-65b0-4bd3-a1b2-1f4e4d2b3c4a"
+)
+
+var testServiceAccount = &User{
+	ID:         "SA-ID",
+	ExternalID: "ci-job",
+	Path:       "/serviceaccount/ci/",
+	Urn:        CreateUrn("", RESOURCE_USER, "/serviceaccount/ci/", "ci-job"),
+	Enabled:    true,
+}
+
+func TestWorkerAPI_AddApiKey(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC()
+	past := time.Now().Add(-time.Hour).UTC()
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		name        string
+		expireAt    *time.Time
+
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getUserByExternalIDResult *User
+		getUserByExternalIDErr    error
+
+		addApiKeyMethodResult *ApiKey
+		addApiKeyMethodErr    error
+		wantError             error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			name:                      "deploy",
+			getUserByExternalIDResult: testServiceAccount,
+			addApiKeyMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				Name:   "deploy",
+				UserID: "SA-ID",
+			},
+		},
+		"OKCaseWithExpiration": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			name:                      "deploy",
+			expireAt:                  &future,
+			getUserByExternalIDResult: testServiceAccount,
+			addApiKeyMethodResult: &ApiKey{
+				ID:       testApiKeyID,
+				Name:     "deploy",
+				UserID:   "SA-ID",
+				ExpireAt: &future,
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "ci-job",
+			name:       "**!^#~",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseExpireAtInPast": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "ci-job",
+			name:       "deploy",
+			expireAt:   &past,
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: expireAt " + past.String() + ", it must be a future time",
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "ci-job",
+			name:       "deploy",
+			getUserByExternalIDErr: &database.Error{
+				Code:    database.USER_NOT_FOUND,
+				Message: "User with externalId ci-job not found",
+			},
+			wantError: &Error{
+				Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User with externalId ci-job not found",
+			},
+		},
+		"ErrorCaseNotServiceAccount": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "user1",
+			name:       "deploy",
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "user1",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled:    true,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "User with externalId user1 isn't a service account, its path must start with /serviceaccount/",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "ci-job",
+				Admin:      false,
+			},
+			externalID:                "ci-job",
+			name:                      "deploy",
+			getUserByExternalIDResult: testServiceAccount,
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId ci-job is not allowed to access to resource urn:iws:iam::user/serviceaccount/ci/ci-job",
+			},
+		},
+		"ErrorCaseAddApiKeyErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			name:                      "deploy",
+			getUserByExternalIDResult: testServiceAccount,
+			addApiKeyMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[AddApiKeyMethod][0] = testcase.addApiKeyMethodResult
+		testRepo.ArgsOut[AddApiKeyMethod][1] = testcase.addApiKeyMethodErr
+		apiKey, err := testAPI.AddApiKey(testcase.requestInfo, testcase.externalID, testcase.name, testcase.expireAt)
+		if testcase.wantError != nil {
+			checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+			continue
+		}
+		if assert.Nil(t, err, "Error in test case %v", x) {
+			// Check stored API key
+			storedApiKey := testRepo.ArgsIn[AddApiKeyMethod][0].(ApiKey)
+			assert.Equal(t, testcase.name, storedApiKey.Name, "Error in test case %v", x)
+			assert.Equal(t, testServiceAccount.ID, storedApiKey.UserID, "Error in test case %v", x)
+			assert.Equal(t, testcase.expireAt, storedApiKey.ExpireAt, "Error in test case %v", x)
+			// Check returned key has the secret whose hash is stored
+			assert.Equal(t, "ci-job", apiKey.ExternalID, "Error in test case %v", x)
+			assert.True(t, strings.HasPrefix(apiKey.Key, testApiKeyID+"."), "Error in test case %v", x)
+			secret := strings.TrimPrefix(apiKey.Key, testApiKeyID+".")
+			assert.Equal(t, storedApiKey.SecretHash, hashApiKeySecret(secret), "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_GetApiKey(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		id          string
+
+		getUserByExternalIDResult *User
+
+		getApiKeyByIDMethodResult *ApiKey
+		getApiKeyByIDMethodErr    error
+		wantError                 error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				Name:       "deploy",
+				UserID:     "SA-ID",
+				ExternalID: "ci-job",
+			},
+		},
+		"ErrorCaseBadID": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        "invalid",
+			getUserByExternalIDResult: testServiceAccount,
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: id invalid",
+			},
+		},
+		"ErrorCaseNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodErr: &database.Error{
+				Code:    database.API_KEY_NOT_FOUND,
+				Message: "API key with id " + testApiKeyID + " not found",
+			},
+			wantError: &Error{
+				Code:    API_KEY_BY_ID_NOT_FOUND,
+				Message: "API key with id " + testApiKeyID + " not found",
+			},
+		},
+		"ErrorCaseOtherUserKey": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				Name:       "deploy",
+				UserID:     "OTHER-ID",
+				ExternalID: "other",
+			},
+			wantError: &Error{
+				Code:    API_KEY_BY_ID_NOT_FOUND,
+				Message: "API key with id " + testApiKeyID + " not found for user ci-job",
+			},
+		},
+		"ErrorCaseGetApiKeyDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetApiKeyByIDMethod][0] = testcase.getApiKeyByIDMethodResult
+		testRepo.ArgsOut[GetApiKeyByIDMethod][1] = testcase.getApiKeyByIDMethodErr
+		apiKey, err := testAPI.GetApiKey(testcase.requestInfo, testcase.externalID, testcase.id)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.getApiKeyByIDMethodResult, apiKey)
+	}
+}
+
+func TestWorkerAPI_ListApiKeys(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		filter      *Filter
+
+		getUserByExternalIDResult *User
+
+		getApiKeysByUserIDMethodResult []ApiKey
+		getApiKeysByUserIDMethodErr    error
+		expectedApiKeys                []ApiKey
+		expectedTotal                  int
+		wantError                      error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "ci-job",
+				Limit:      20,
+			},
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeysByUserIDMethodResult: []ApiKey{
+				{
+					ID:     testApiKeyID,
+					Name:   "deploy",
+					UserID: "SA-ID",
+				},
+			},
+			expectedApiKeys: []ApiKey{
+				{
+					ID:         testApiKeyID,
+					Name:       "deploy",
+					UserID:     "SA-ID",
+					ExternalID: "ci-job",
+				},
+			},
+			expectedTotal: 1,
+		},
+		"ErrorCaseInvalidExternalID": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "*%~#@|",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalID *%~#@|",
+			},
+		},
+		"ErrorCaseGetApiKeysDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				ExternalID: "ci-job",
+				Limit:      20,
+			},
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeysByUserIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetApiKeysByUserIDMethod][0] = testcase.getApiKeysByUserIDMethodResult
+		testRepo.ArgsOut[GetApiKeysByUserIDMethod][1] = len(testcase.getApiKeysByUserIDMethodResult)
+		testRepo.ArgsOut[GetApiKeysByUserIDMethod][2] = testcase.getApiKeysByUserIDMethodErr
+		apiKeys, total, err := testAPI.ListApiKeys(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedApiKeys, apiKeys)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.expectedTotal, total, "Error in test case %v", x)
+			assert.Equal(t, "SA-ID", testRepo.ArgsIn[GetApiKeysByUserIDMethod][0], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_RotateApiKey(t *testing.T) {
+	lastUsed := time.Now().UTC()
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		id          string
+
+		getUserByExternalIDResult *User
+		getApiKeyByIDMethodResult *ApiKey
+
+		updateApiKeyMethodResult *ApiKey
+		updateApiKeyMethodErr    error
+		wantError                error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				Name:       "deploy",
+				UserID:     "SA-ID",
+				SecretHash: "oldhash",
+				LastUsedAt: &lastUsed,
+			},
+			updateApiKeyMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				Name:   "deploy",
+				UserID: "SA-ID",
+			},
+		},
+		"ErrorCaseNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				UserID: "OTHER-ID",
+			},
+			wantError: &Error{
+				Code:    API_KEY_BY_ID_NOT_FOUND,
+				Message: "API key with id " + testApiKeyID + " not found for user ci-job",
+			},
+		},
+		"ErrorCaseUpdateApiKeyErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				UserID: "SA-ID",
+			},
+			updateApiKeyMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetApiKeyByIDMethod][0] = testcase.getApiKeyByIDMethodResult
+		testRepo.ArgsOut[UpdateApiKeyMethod][0] = testcase.updateApiKeyMethodResult
+		testRepo.ArgsOut[UpdateApiKeyMethod][1] = testcase.updateApiKeyMethodErr
+		apiKey, err := testAPI.RotateApiKey(testcase.requestInfo, testcase.externalID, testcase.id)
+		if testcase.wantError != nil {
+			checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+			continue
+		}
+		if assert.Nil(t, err, "Error in test case %v", x) {
+			// Check new secret is stored and last use is reset
+			updatedApiKey := testRepo.ArgsIn[UpdateApiKeyMethod][0].(ApiKey)
+			assert.Nil(t, updatedApiKey.LastUsedAt, "Error in test case %v", x)
+			assert.NotEqual(t, "oldhash", updatedApiKey.SecretHash, "Error in test case %v", x)
+			secret := strings.TrimPrefix(apiKey.Key, testApiKeyID+".")
+			assert.Equal(t, updatedApiKey.SecretHash, hashApiKeySecret(secret), "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_RemoveApiKey(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		externalID  string
+		id          string
+
+		getUserByExternalIDResult *User
+		getApiKeyByIDMethodResult *ApiKey
+
+		removeApiKeyMethodErr error
+		wantError             error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				UserID: "SA-ID",
+			},
+		},
+		"ErrorCaseRemoveApiKeyErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID:                "ci-job",
+			id:                        testApiKeyID,
+			getUserByExternalIDResult: testServiceAccount,
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:     testApiKeyID,
+				UserID: "SA-ID",
+			},
+			removeApiKeyMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetApiKeyByIDMethod][0] = testcase.getApiKeyByIDMethodResult
+		testRepo.ArgsOut[RemoveApiKeyMethod][0] = testcase.removeApiKeyMethodErr
+		err := testAPI.RemoveApiKey(testcase.requestInfo, testcase.externalID, testcase.id)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, testApiKeyID, testRepo.ArgsIn[RemoveApiKeyMethod][0], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_AuthenticateApiKey(t *testing.T) {
+	secretHash := hashApiKeySecret("secret")
+	expired := time.Now().Add(-time.Hour).UTC()
+	recentlyUsed := time.Now().UTC()
+	testcases := map[string]struct {
+		id     string
+		secret string
+
+		getApiKeyByIDMethodResult *ApiKey
+		getApiKeyByIDMethodErr    error
+		getUserByExternalIDResult *User
+		updateApiKeyLastUsedErr   error
+		expectedExternalID        string
+		expectedLastUsedStored    bool
+		wantError                 error
+	}{
+		"OKCase": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				UserID:     "SA-ID",
+				ExternalID: "ci-job",
+				SecretHash: secretHash,
+			},
+			getUserByExternalIDResult: testServiceAccount,
+			expectedExternalID:        "ci-job",
+			expectedLastUsedStored:    true,
+		},
+		"OKCaseRecentlyUsed": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				UserID:     "SA-ID",
+				ExternalID: "ci-job",
+				SecretHash: secretHash,
+				LastUsedAt: &recentlyUsed,
+			},
+			getUserByExternalIDResult: testServiceAccount,
+			expectedExternalID:        "ci-job",
+		},
+		"OKCaseLastUsedErr": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				UserID:     "SA-ID",
+				ExternalID: "ci-job",
+				SecretHash: secretHash,
+			},
+			getUserByExternalIDResult: testServiceAccount,
+			updateApiKeyLastUsedErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			expectedExternalID:     "ci-job",
+			expectedLastUsedStored: true,
+		},
+		"ErrorCaseInvalidID": {
+			id:     "invalid",
+			secret: "secret",
+			wantError: &Error{
+				Code:    AUTHENTICATION_API_ERROR,
+				Message: "Invalid API key invalid",
+			},
+		},
+		"ErrorCaseNotFound": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodErr: &database.Error{
+				Code: database.API_KEY_NOT_FOUND,
+			},
+			wantError: &Error{
+				Code:    AUTHENTICATION_API_ERROR,
+				Message: "Invalid API key " + testApiKeyID,
+			},
+		},
+		"ErrorCaseInvalidSecret": {
+			id:     testApiKeyID,
+			secret: "other",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				ExternalID: "ci-job",
+				SecretHash: secretHash,
+			},
+			wantError: &Error{
+				Code:    AUTHENTICATION_API_ERROR,
+				Message: "Invalid API key " + testApiKeyID,
+			},
+		},
+		"ErrorCaseExpired": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				ExternalID: "ci-job",
+				SecretHash: secretHash,
+				ExpireAt:   &expired,
+			},
+			wantError: &Error{
+				Code:    AUTHENTICATION_API_ERROR,
+				Message: "API key " + testApiKeyID + " expired at " + expired.String(),
+			},
+		},
+		"ErrorCaseNotServiceAccount": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodResult: &ApiKey{
+				ID:         testApiKeyID,
+				ExternalID: "user1",
+				SecretHash: secretHash,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "USER-ID",
+				ExternalID: "user1",
+				Path:       "/path/",
+				Enabled:    true,
+			},
+			wantError: &Error{
+				Code:    AUTHENTICATION_API_ERROR,
+				Message: "API key " + testApiKeyID + " belongs to user user1, that isn't a service account",
+			},
+		},
+		"ErrorCaseGetApiKeyDBErr": {
+			id:     testApiKeyID,
+			secret: "secret",
+			getApiKeyByIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsIn[UpdateApiKeyLastUsedMethod][0] = nil
+		testRepo.ArgsOut[GetApiKeyByIDMethod][0] = testcase.getApiKeyByIDMethodResult
+		testRepo.ArgsOut[GetApiKeyByIDMethod][1] = testcase.getApiKeyByIDMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[UpdateApiKeyLastUsedMethod][0] = testcase.updateApiKeyLastUsedErr
+		externalID, err := testAPI.AuthenticateApiKey(testcase.id, testcase.secret)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedExternalID, externalID)
+		if testcase.expectedLastUsedStored {
+			assert.Equal(t, testApiKeyID, testRepo.ArgsIn[UpdateApiKeyLastUsedMethod][0], "Error in test case %v", x)
+		} else {
+			assert.Nil(t, testRepo.ArgsIn[UpdateApiKeyLastUsedMethod][0], "Error in test case %v", x)
+		}
+	}
+}
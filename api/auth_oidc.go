@@ -3,12 +3,20 @@ package api
 import (
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/satori/go.uuid"
 )
 
+const (
+	// Group mapping modes. Mapped groups are effective only for the request, or synced to user memberships
+	GROUP_MAPPING_MODE_REQUEST = "request"
+	GROUP_MAPPING_MODE_SYNC    = "sync"
+)
+
 // TYPE DEFINITIONS
 
 // Authenticator OIDC domain
@@ -21,16 +29,105 @@ type OidcProvider struct {
 	UpdateAt    time.Time    `json:"updateAt,omitempty"`
 	IssuerURL   string       `json:"issuerUrl,omitempty"`
 	OidcClients []OidcClient `json:"clients,omitempty"`
+	// Users authenticated by this provider are created on first authentication if it isn't nil
+	UserProvisioning *UserProvisioning `json:"userProvisioning,omitempty"`
+	// Groups of users authenticated by this provider, from token claims
+	GroupMappings    []GroupMapping `json:"groupMappings,omitempty"`
+	GroupMappingMode string         `json:"groupMappingMode,omitempty"`
 }
 
 type OidcClient struct {
 	Name string `json:"name,omitempty"`
 }
 
+// UserProvisioning represents how users of an OIDC provider are created
+type UserProvisioning struct {
+	// Path of created users, where {claim} placeholders are replaced by token claims. Empty means "/".
+	PathTemplate string `json:"pathTemplate,omitempty"`
+}
+
+var rClaimPlaceholder, _ = regexp.Compile(`\{([\w.-]+)\}`)
+
+// UserPath returns path for a user with the token claims, failing if a claim in template isn't
+// a non-empty string or the resulting path is invalid
+func (up UserProvisioning) UserPath(claims map[string]interface{}) (string, error) {
+	if up.PathTemplate == "" {
+		return "/", nil
+	}
+	var missingClaim string
+	path := rClaimPlaceholder.ReplaceAllStringFunc(up.PathTemplate, func(placeholder string) string {
+		claim := rClaimPlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := claims[claim].(string)
+		if !ok || value == "" {
+			if missingClaim == "" {
+				missingClaim = claim
+			}
+			return ""
+		}
+		return value
+	})
+	if missingClaim != "" {
+		return "", &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Claim %v of user path template %v not found in token", missingClaim, up.PathTemplate),
+		}
+	}
+	if !IsValidPath(path) || strings.HasPrefix(path, SERVICE_ACCOUNT_PATH) {
+		return "", &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid user path %v from template %v", path, up.PathTemplate),
+		}
+	}
+	return path, nil
+}
+
+func (up UserProvisioning) String() string {
+	return fmt.Sprintf("pathTemplate: %v", up.PathTemplate)
+}
+
+// GroupMapping represents a group of users with a value in a token claim
+type GroupMapping struct {
+	Claim string `json:"claim,omitempty"`
+	Value string `json:"value,omitempty"`
+	Org   string `json:"org,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+func (gm GroupMapping) String() string {
+	return fmt.Sprintf("claim: %v, value: %v, org: %v, group: %v", gm.Claim, gm.Value, gm.Org, gm.Group)
+}
+
+// MappedGroups returns groups mapped from token claims, without duplicates. A claim matches a mapping
+// if it's the mapping value or a list of strings with it.
+func (op OidcProvider) MappedGroups(claims map[string]interface{}) []GroupIdentity {
+	groups := []GroupIdentity{}
+	for _, gm := range op.GroupMappings {
+		group := GroupIdentity{Org: gm.Org, Name: gm.Group}
+		if !isClaimValue(claims[gm.Claim], gm.Value) || isContainedInGroups(group, groups) {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// ManagedGroups returns groups of group mappings, without duplicates
+func (op OidcProvider) ManagedGroups() []GroupIdentity {
+	groups := []GroupIdentity{}
+	for _, gm := range op.GroupMappings {
+		group := GroupIdentity{Org: gm.Org, Name: gm.Group}
+		if !isContainedInGroups(group, groups) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
 func (op OidcProvider) String() string {
-	return fmt.Sprintf("[id: %v, name: %v, path: %v, urn: %v, createAt: %v, updateAt: %v, issuerUrl: %v, clients: %v]",
+	return fmt.Sprintf("[id: %v, name: %v, path: %v, urn: %v, createAt: %v, updateAt: %v, issuerUrl: %v, clients: %v, userProvisioning: %v, groupMappings: %v, groupMappingMode: %v]",
 		op.ID, op.Name, op.Path, op.Urn, op.CreateAt.Format("2006-01-02 15:04:05 MST"),
-		op.UpdateAt.Format("2006-01-02 15:04:05 MST"), op.IssuerURL, op.OidcClients)
+		op.UpdateAt.Format("2006-01-02 15:04:05 MST"), op.IssuerURL, op.OidcClients, op.UserProvisioning,
+		op.GroupMappings, op.GroupMappingMode)
 }
 
 func (op OidcClient) String() string {
@@ -43,7 +140,11 @@ func (op OidcProvider) GetUrn() string {
 
 // AUTHENTICATOR OIDC API IMPLEMENTATION
 
-func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*OidcProvider, error) {
+func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string,
+	userProvisioning *UserProvisioning, groupMappings []GroupMapping, groupMappingMode string) (*OidcProvider, error) {
+	api, span := api.startSpan(&requestInfo, "AddOidcProvider")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -73,8 +174,15 @@ func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path
 		}
 
 	}
+	if err := isValidUserProvisioning(userProvisioning); err != nil {
+		return nil, err
+	}
+	if err := areValidGroupMappings(groupMappings, groupMappingMode); err != nil {
+		return nil, err
+	}
 
-	oidcProvider := createOidcProvider(name, path, issuerURL, oidcClients)
+	oidcProvider := createOidcProvider(name, path, issuerURL, oidcClients, userProvisioning)
+	oidcProvider.GroupMappings, oidcProvider.GroupMappingMode = groupMappings, getGroupMappingMode(groupMappings, groupMappingMode)
 
 	// Check restrictions
 	oidcProvidersFiltered, err := api.GetAuthorizedOidcProviders(requestInfo, oidcProvider.Urn, AUTH_OIDC_ACTION_CREATE_PROVIDER, []OidcProvider{oidcProvider})
@@ -129,6 +237,9 @@ func (api WorkerAPI) AddOidcProvider(requestInfo RequestInfo, name string, path
 }
 
 func (api WorkerAPI) GetOidcProviderByName(requestInfo RequestInfo, name string) (*OidcProvider, error) {
+	api, span := api.startSpan(&requestInfo, "GetOidcProviderByName")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -175,6 +286,9 @@ func (api WorkerAPI) GetOidcProviderByName(requestInfo RequestInfo, name string)
 }
 
 func (api WorkerAPI) ListOidcProviders(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListOidcProviders")
+	defer span.End()
+
 	// Validate fields
 	var total int
 	orderByValidColumns := api.AuthOidcRepo.OrderByValidColumns(AUTH_OIDC_ACTION_LIST_PROVIDERS)
@@ -217,7 +331,10 @@ func (api WorkerAPI) ListOidcProviders(requestInfo RequestInfo, filter *Filter)
 }
 
 func (api WorkerAPI) UpdateOidcProvider(requestInfo RequestInfo, oidcProviderName string, newName string, newPath string, newIssuerUrl string,
-	newClients []string) (*OidcProvider, error) {
+	newClients []string, newUserProvisioning *UserProvisioning, newGroupMappings []GroupMapping, newGroupMappingMode string) (*OidcProvider, error) {
+	api, span := api.startSpan(&requestInfo, "UpdateOidcProvider")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(newName) {
 		return nil, &Error{
@@ -247,6 +364,12 @@ func (api WorkerAPI) UpdateOidcProvider(requestInfo RequestInfo, oidcProviderNam
 		}
 
 	}
+	if err := isValidUserProvisioning(newUserProvisioning); err != nil {
+		return nil, err
+	}
+	if err := areValidGroupMappings(newGroupMappings, newGroupMappingMode); err != nil {
+		return nil, err
+	}
 
 	// Call repo to retrieve the old OIDC Provider
 	oldOidcProvider, err := api.GetOidcProviderByName(requestInfo, oidcProviderName)
@@ -310,14 +433,17 @@ func (api WorkerAPI) UpdateOidcProvider(requestInfo RequestInfo, oidcProviderNam
 	}
 
 	oidcProvider := OidcProvider{
-		ID:          oldOidcProvider.ID,
-		Name:        newName,
-		Path:        newPath,
-		Urn:         auxOidcProvider.Urn,
-		CreateAt:    oldOidcProvider.CreateAt,
-		UpdateAt:    time.Now().UTC(),
-		IssuerURL:   newIssuerUrl,
-		OidcClients: oidcClients,
+		ID:               oldOidcProvider.ID,
+		Name:             newName,
+		Path:             newPath,
+		Urn:              auxOidcProvider.Urn,
+		CreateAt:         oldOidcProvider.CreateAt,
+		UpdateAt:         time.Now().UTC(),
+		IssuerURL:        newIssuerUrl,
+		OidcClients:      oidcClients,
+		UserProvisioning: newUserProvisioning,
+		GroupMappings:    newGroupMappings,
+		GroupMappingMode: getGroupMappingMode(newGroupMappings, newGroupMappingMode),
 	}
 
 	// Update OIDC Provider
@@ -339,6 +465,9 @@ func (api WorkerAPI) UpdateOidcProvider(requestInfo RequestInfo, oidcProviderNam
 }
 
 func (api WorkerAPI) RemoveOidcProvider(requestInfo RequestInfo, name string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveOidcProvider")
+	defer span.End()
+
 	// Call repo to retrieve the OIDC provider
 	oidcProvider, err := api.GetOidcProviderByName(requestInfo, name)
 	if err != nil {
@@ -374,24 +503,256 @@ func (api WorkerAPI) RemoveOidcProvider(requestInfo RequestInfo, name string) er
 	return nil
 }
 
+func (api WorkerAPI) ProvisionOidcUser(requestInfo RequestInfo, oidcProvider OidcProvider, externalId string,
+	claims map[string]interface{}) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "ProvisionOidcUser")
+	defer span.End()
+
+	if oidcProvider.UserProvisioning == nil {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("OIDC provider %v doesn't provision users", oidcProvider.Name),
+		}
+	}
+
+	// Check if user already exists
+	_, err := api.UserRepo.GetUserByExternalID(externalId)
+	if err == nil {
+		return nil, nil
+	}
+	// Transform to DB error
+	if dbError := err.(*database.Error); dbError.Code != database.USER_NOT_FOUND {
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Validate fields
+	if !IsValidUserExternalID(externalId) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: externalId %v", externalId),
+		}
+	}
+	path, err := oidcProvider.UserProvisioning.UserPath(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create user, without restrictions because user is authenticated by a trusted provider
+	createdUser, err := api.UserRepo.AddUser(createUser(externalId, path, nil))
+	if err != nil {
+		// User may have been created by a concurrent request
+		if _, getErr := api.UserRepo.GetUserByExternalID(externalId); getErr == nil {
+			return nil, nil
+		}
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User provisioned by OIDC provider %v %+v",
+		oidcProvider.Name, createdUser))
+	api.notifyEvent(requestInfo, EVENT_USER_CREATED, createdUser.Urn, createdUser)
+	return createdUser, nil
+}
+
+func (api WorkerAPI) SyncOidcUserGroups(requestInfo RequestInfo, oidcProvider OidcProvider, externalId string,
+	claims map[string]interface{}) error {
+	api, span := api.startSpan(&requestInfo, "SyncOidcUserGroups")
+	defer span.End()
+
+	// Retrieve user, users that don't exist have nothing to sync
+	user, err := api.UserRepo.GetUserByExternalID(externalId)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if dbError.Code == database.USER_NOT_FOUND {
+			return nil
+		}
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	currentGroups, err := api.getGroupsByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	currentIdentities := []GroupIdentity{}
+	for _, g := range currentGroups {
+		currentIdentities = append(currentIdentities, GroupIdentity{Org: g.Org, Name: g.Name})
+	}
+	mappedGroups := oidcProvider.MappedGroups(claims)
+
+	// Add user to mapped groups it isn't member of
+	for _, mappedGroup := range mappedGroups {
+		if isContainedInGroups(mappedGroup, currentIdentities) {
+			continue
+		}
+		group, err := api.GroupRepo.GetGroupByName(mappedGroup.Org, mappedGroup.Name)
+		if err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			if dbError.Code == database.GROUP_NOT_FOUND {
+				Log.Warnf("Group %v/%v mapped by OIDC provider %v not found", mappedGroup.Org, mappedGroup.Name, oidcProvider.Name)
+				continue
+			}
+			return &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		if err := api.GroupRepo.AddMember(user.ID, group.ID); err != nil {
+			// User may have been added by a concurrent request
+			if isMember, memberErr := api.GroupRepo.IsMemberOfGroup(user.ID, group.ID); memberErr == nil && isMember {
+				continue
+			}
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v added to group %+v by OIDC provider %v",
+			user, group, oidcProvider.Name))
+		api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_ADDED, group.Urn, EventRelation{Group: group, User: user})
+	}
+
+	// Remove user from groups managed by mappings that aren't mapped anymore
+	managedGroups := oidcProvider.ManagedGroups()
+	for i, identity := range currentIdentities {
+		if !isContainedInGroups(identity, managedGroups) || isContainedInGroups(identity, mappedGroups) {
+			continue
+		}
+		group := &currentGroups[i]
+		if err := api.GroupRepo.RemoveMember(user.ID, group.ID); err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v removed from group %+v by OIDC provider %v",
+			user, group, oidcProvider.Name))
+		api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_REMOVED, group.Urn, EventRelation{Group: group, User: user})
+	}
+
+	return nil
+}
+
 // PRIVATE HELPER METHODS
 
-func createOidcProvider(name string, path string, issuerURL string, oidcClients []string) OidcProvider {
+func createOidcProvider(name string, path string, issuerURL string, oidcClients []string, userProvisioning *UserProvisioning) OidcProvider {
 	urn := CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, path, name)
 	oidcClientsApi := []OidcClient{}
 	for _, oc := range oidcClients {
 		oidcClientsApi = append(oidcClientsApi, OidcClient{Name: oc})
 	}
 	oidcProvider := OidcProvider{
-		ID:          uuid.NewV4().String(),
-		Name:        name,
-		Path:        path,
-		CreateAt:    time.Now().UTC(),
-		UpdateAt:    time.Now().UTC(),
-		Urn:         urn,
-		IssuerURL:   issuerURL,
-		OidcClients: oidcClientsApi,
+		ID:               uuid.NewV4().String(),
+		Name:             name,
+		Path:             path,
+		CreateAt:         time.Now().UTC(),
+		UpdateAt:         time.Now().UTC(),
+		Urn:              urn,
+		IssuerURL:        issuerURL,
+		OidcClients:      oidcClientsApi,
+		UserProvisioning: userProvisioning,
 	}
 
 	return oidcProvider
 }
+
+// isValidUserProvisioning checks user paths from template are valid, replacing claims with a sample value
+func isValidUserProvisioning(userProvisioning *UserProvisioning) error {
+	if userProvisioning == nil || userProvisioning.PathTemplate == "" {
+		return nil
+	}
+	path := rClaimPlaceholder.ReplaceAllString(userProvisioning.PathTemplate, "x")
+	if !IsValidPath(path) || strings.HasPrefix(path, SERVICE_ACCOUNT_PATH) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: userProvisioning pathTemplate %v", userProvisioning.PathTemplate),
+		}
+	}
+	return nil
+}
+
+// areValidGroupMappings checks group mappings have claim and value, valid groups, and mode is valid
+func areValidGroupMappings(groupMappings []GroupMapping, groupMappingMode string) error {
+	switch groupMappingMode {
+	case "", GROUP_MAPPING_MODE_REQUEST, GROUP_MAPPING_MODE_SYNC:
+	default:
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: groupMappingMode %v", groupMappingMode),
+		}
+	}
+	for _, gm := range groupMappings {
+		if gm.Claim == "" || gm.Value == "" {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: groupMapping %v must have claim and value", gm),
+			}
+		}
+		if !IsValidOrg(gm.Org) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: groupMapping org %v", gm.Org),
+			}
+		}
+		if !IsValidName(gm.Group) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: groupMapping group %v", gm.Group),
+			}
+		}
+	}
+	return nil
+}
+
+// getGroupMappingMode returns mode of group mappings, request by default. It's empty without mappings.
+func getGroupMappingMode(groupMappings []GroupMapping, groupMappingMode string) string {
+	if len(groupMappings) < 1 {
+		return ""
+	}
+	if groupMappingMode == "" {
+		return GROUP_MAPPING_MODE_REQUEST
+	}
+	return groupMappingMode
+}
+
+// isClaimValue checks if a claim is the value, or a list with it
+func isClaimValue(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []string:
+		return isContainedInSlice(value, c)
+	case []interface{}:
+		for _, v := range c {
+			if v == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isContainedInGroups checks if a group is in a slice of groups
+func isContainedInGroups(group GroupIdentity, groups []GroupIdentity) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
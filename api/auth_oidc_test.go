@@ -14,6 +14,11 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 		path             string
 		issuerURL        string
 		oidcClients      []string
+		userProvisioning *UserProvisioning
+		groupMappings    []GroupMapping
+		groupMappingMode string
+
+		expectedGroupMappingMode string
 
 		getGroupsByUserIDResult   []TestUserGroupRelation
 		getAttachedPoliciesResult []TestPolicyGroupRelation
@@ -53,6 +58,39 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 				},
 			},
 		},
+		"OKCaseGroupMappingsDefaultMode": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName: "test",
+			path:             "/path/",
+			issuerURL:        "https://test.com",
+			oidcClients:      []string{"client"},
+			groupMappings: []GroupMapping{
+				{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+			},
+			expectedGroupMappingMode: GROUP_MAPPING_MODE_REQUEST,
+			getOidcProviderByNameMethodErr: &database.Error{
+				Code: database.AUTH_OIDC_PROVIDER_NOT_FOUND,
+			},
+			addOidcProviderMethodResult: &OidcProvider{
+				ID:        "test1",
+				Name:      "test",
+				Path:      "/path/",
+				Urn:       CreateUrn("123", RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "test"),
+				IssuerURL: "https://test.com",
+				OidcClients: []OidcClient{
+					{
+						Name: "client",
+					},
+				},
+				GroupMappings: []GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+				},
+				GroupMappingMode: GROUP_MAPPING_MODE_REQUEST,
+			},
+		},
 		"ErrorCaseOidcProviderAlreadyExists": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -111,6 +149,75 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 				Message: "Invalid parameter: client name ~$",
 			},
 		},
+		"ErrorCaseInvalidUserPathTemplate": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName: "test",
+			path:             "/path/",
+			issuerURL:        "https://test.com",
+			oidcClients:      []string{"client"},
+			userProvisioning: &UserProvisioning{
+				PathTemplate: "/serviceaccount/{department}/",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: userProvisioning pathTemplate /serviceaccount/{department}/",
+			},
+		},
+		"ErrorCaseInvalidGroupMappingMode": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName: "test",
+			path:             "/path/",
+			issuerURL:        "https://test.com",
+			oidcClients:      []string{"client"},
+			groupMappings: []GroupMapping{
+				{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+			},
+			groupMappingMode: "always",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: groupMappingMode always",
+			},
+		},
+		"ErrorCaseGroupMappingWithoutValue": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName: "test",
+			path:             "/path/",
+			issuerURL:        "https://test.com",
+			oidcClients:      []string{"client"},
+			groupMappings: []GroupMapping{
+				{Claim: "groups", Org: "acme", Group: "backend"},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: groupMapping claim: groups, value: , org: acme, group: backend must have claim and value",
+			},
+		},
+		"ErrorCaseInvalidGroupMappingGroup": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName: "test",
+			path:             "/path/",
+			issuerURL:        "https://test.com",
+			oidcClients:      []string{"client"},
+			groupMappings: []GroupMapping{
+				{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "back end"},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: groupMapping group back end",
+			},
+		},
 		"ErrorCaseBadPath": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -160,6 +267,7 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -192,6 +300,7 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -301,8 +410,13 @@ func TestWorkerAPI_AddOidcProvider(t *testing.T) {
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 		oidcProvider, err := testAPI.AddOidcProvider(testcase.requestInfo, testcase.oidcProviderName,
-			testcase.path, testcase.issuerURL, testcase.oidcClients)
+			testcase.path, testcase.issuerURL, testcase.oidcClients, testcase.userProvisioning, testcase.groupMappings, testcase.groupMappingMode)
 		checkMethodResponse(t, x, testcase.wantError, err, oidcProvider, testcase.addOidcProviderMethodResult)
+		if testcase.expectedGroupMappingMode != "" {
+			if createdOidcProvider, ok := testRepo.ArgsIn[AddOidcProviderMethod][0].(OidcProvider); assert.True(t, ok, "Error in test case %v", x) {
+				assert.Equal(t, testcase.expectedGroupMappingMode, createdOidcProvider.GroupMappingMode, "Error in test case %v", x)
+			}
+		}
 	}
 }
 
@@ -399,6 +513,7 @@ func TestWorkerAPI_GetOidcProviderByName(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -438,6 +553,7 @@ func TestWorkerAPI_GetOidcProviderByName(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -603,6 +719,7 @@ func TestWorkerAPI_ListOidcProviders(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -750,6 +867,9 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 		newPath             string
 		newIssuerUrl        string
 		newClients          []string
+		newUserProvisioning *UserProvisioning
+		newGroupMappings    []GroupMapping
+		newGroupMappingMode string
 		// Expected result
 		expectedOidcProvider *OidcProvider
 		wantError            error
@@ -813,6 +933,24 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				},
 			},
 		},
+		"ErrorCaseInvalidUserPathTemplate": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			oidcProviderName:    "oidcProvider1",
+			newOidcProviderName: "oidcProviderNewName",
+			newPath:             "/new/",
+			newIssuerUrl:        "http://oidcProvider1.com",
+			newClients:          []string{"newClient1"},
+			newUserProvisioning: &UserProvisioning{
+				PathTemplate: "/employees/{department}",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: userProvisioning pathTemplate /employees/{department}",
+			},
+		},
 		"OKCase": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -897,6 +1035,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseInvalidName": {
@@ -1083,6 +1222,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyUpdateGroup": {
@@ -1151,6 +1291,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissionsToUpdateTarget": {
@@ -1218,6 +1359,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyToUpdateTarget": {
@@ -1294,6 +1436,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermission": {
@@ -1350,6 +1493,7 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUpdateOidcProviderDBErr": {
@@ -1391,7 +1535,8 @@ func TestWorkerAPI_UpdateOidcProvider(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 
 		oidcProvider, err := testAPI.UpdateOidcProvider(testcase.requestInfo, testcase.oidcProviderName, testcase.newOidcProviderName,
-			testcase.newPath, testcase.newIssuerUrl, testcase.newClients)
+			testcase.newPath, testcase.newIssuerUrl, testcase.newClients, testcase.newUserProvisioning, testcase.newGroupMappings,
+			testcase.newGroupMappingMode)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedOidcProvider, oidcProvider)
 	}
 }
@@ -1431,6 +1576,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 		},
 		"OkCase": {
@@ -1450,6 +1596,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
@@ -1543,6 +1690,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1599,6 +1747,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1665,6 +1814,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1710,6 +1860,7 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			removeOidcProviderMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -1734,3 +1885,369 @@ func TestWorkerAPI_RemoveOidcProvider(t *testing.T) {
 		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
 	}
 }
+
+func TestWorkerAPI_ProvisionOidcUser(t *testing.T) {
+	provisioningProvider := OidcProvider{
+		Name: "google",
+		UserProvisioning: &UserProvisioning{
+			PathTemplate: "/employees/{department}/",
+		},
+	}
+	testcases := map[string]struct {
+		// API method args
+		oidcProvider OidcProvider
+		externalID   string
+		claims       map[string]interface{}
+		// Expected result
+		expectedPath string
+		wantError    error
+		// Manager Results
+		addUserResult                        *User
+		getUserByExternalIDResult            *User
+		getUserByExternalIDMethodSpecialFunc func(string) (*User, error)
+		// Manager Errors
+		getUserByExternalIDMethodErr error
+		addUserMethodErr             error
+	}{
+		"OKCase": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			claims:       map[string]interface{}{"department": "sales"},
+			expectedPath: "/employees/sales/",
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			addUserResult: &User{
+				ID:         "UserID",
+				ExternalID: "user1",
+				Path:       "/employees/sales/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/employees/sales/", "user1"),
+				Enabled:    true,
+			},
+		},
+		"OKCaseDefaultPath": {
+			oidcProvider: OidcProvider{
+				Name:             "google",
+				UserProvisioning: &UserProvisioning{},
+			},
+			externalID:   "user1",
+			expectedPath: "/",
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			addUserResult: &User{
+				ID:         "UserID",
+				ExternalID: "user1",
+				Path:       "/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/", "user1"),
+				Enabled:    true,
+			},
+		},
+		"OKCaseUserAlreadyExists": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			getUserByExternalIDResult: &User{
+				ID:         "UserID",
+				ExternalID: "user1",
+				Enabled:    true,
+			},
+		},
+		"OKCaseUserCreatedConcurrently": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			claims:       map[string]interface{}{"department": "sales"},
+			getUserByExternalIDMethodSpecialFunc: func() func(string) (*User, error) {
+				calls := 0
+				return func(id string) (*User, error) {
+					calls++
+					if calls == 1 {
+						return nil, &database.Error{Code: database.USER_NOT_FOUND}
+					}
+					return &User{ID: "UserID", ExternalID: id}, nil
+				}
+			}(),
+			addUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseProviderWithoutUserProvisioning": {
+			oidcProvider: OidcProvider{
+				Name: "google",
+			},
+			externalID: "user1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "OIDC provider google doesn't provision users",
+			},
+		},
+		"ErrorCaseInvalidExternalID": {
+			oidcProvider: provisioningProvider,
+			externalID:   "*%~#@|",
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId *%~#@|",
+			},
+		},
+		"ErrorCaseClaimNotFound": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			claims:       map[string]interface{}{"department": 1},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Claim department of user path template /employees/{department}/ not found in token",
+			},
+		},
+		"ErrorCaseGetUserDBErr": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			getUserByExternalIDMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseAddUserDBErr": {
+			oidcProvider: provisioningProvider,
+			externalID:   "user1",
+			claims:       map[string]interface{}{"department": "sales"},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+			addUserMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsIn[AddUserMethod][0] = nil
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.SpecialFuncs[GetUserByExternalIDMethod] = testcase.getUserByExternalIDMethodSpecialFunc
+		testRepo.ArgsOut[AddUserMethod][0] = testcase.addUserResult
+		testRepo.ArgsOut[AddUserMethod][1] = testcase.addUserMethodErr
+
+		user, err := testAPI.ProvisionOidcUser(RequestInfo{Identifier: testcase.externalID}, testcase.oidcProvider,
+			testcase.externalID, testcase.claims)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.addUserResult, user)
+		if testcase.addUserResult != nil {
+			if createdUser, ok := testRepo.ArgsIn[AddUserMethod][0].(User); assert.True(t, ok, "Error in test case %v", x) {
+				assert.Equal(t, testcase.expectedPath, createdUser.Path, "Error in test case %v", x)
+			}
+		}
+	}
+}
+
+func TestUserProvisioning_UserPath(t *testing.T) {
+	testcases := map[string]struct {
+		pathTemplate string
+		claims       map[string]interface{}
+		// Expected result
+		expectedPath string
+		wantError    error
+	}{
+		"OKCaseEmptyTemplate": {
+			expectedPath: "/",
+		},
+		"OKCaseStaticTemplate": {
+			pathTemplate: "/employees/",
+			expectedPath: "/employees/",
+		},
+		"OKCaseClaims": {
+			pathTemplate: "/{org}/{department}/",
+			claims:       map[string]interface{}{"org": "acme", "department": "sales"},
+			expectedPath: "/acme/sales/",
+		},
+		"ErrorCaseMissingClaim": {
+			pathTemplate: "/{org}/{department}/",
+			claims:       map[string]interface{}{"org": "acme"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Claim department of user path template /{org}/{department}/ not found in token",
+			},
+		},
+		"ErrorCaseInvalidPath": {
+			pathTemplate: "/{department}/",
+			claims:       map[string]interface{}{"department": "sales team"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid user path /sales team/ from template /{department}/",
+			},
+		},
+		"ErrorCaseServiceAccountPath": {
+			pathTemplate: "/{kind}/",
+			claims:       map[string]interface{}{"kind": "serviceaccount"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid user path /serviceaccount/ from template /{kind}/",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		userProvisioning := UserProvisioning{PathTemplate: testcase.pathTemplate}
+		path, err := userProvisioning.UserPath(testcase.claims)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedPath, path)
+	}
+}
+
+func TestWorkerAPI_SyncOidcUserGroups(t *testing.T) {
+	oidcProvider := OidcProvider{
+		Name: "google",
+		GroupMappings: []GroupMapping{
+			{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+			{Claim: "groups", Value: "eng-frontend", Org: "acme", Group: "frontend"},
+		},
+		GroupMappingMode: GROUP_MAPPING_MODE_SYNC,
+	}
+	user := &User{ID: "UserID", ExternalID: "user1"}
+	backendGroup := &Group{ID: "BackendID", Org: "acme", Name: "backend"}
+	frontendGroup := &Group{ID: "FrontendID", Org: "acme", Name: "frontend"}
+	otherGroup := &Group{ID: "OtherID", Org: "acme", Name: "other"}
+	testcases := map[string]struct {
+		// API method args
+		claims map[string]interface{}
+		// Expected result
+		expectedAddedGroupID   interface{}
+		expectedRemovedGroupID interface{}
+		wantError              error
+		// Manager Results
+		getUserByExternalIDResult *User
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getGroupByNameResult      *Group
+		// Manager Errors
+		getUserByExternalIDMethodErr error
+		getGroupByNameMethodErr      error
+		addMemberMethodErr           error
+	}{
+		"OKCaseAddMappedGroup": {
+			claims:                    map[string]interface{}{"groups": []interface{}{"eng-backend", "eng-unknown"}},
+			getUserByExternalIDResult: user,
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{User: user, Group: otherGroup},
+			},
+			getGroupByNameResult: backendGroup,
+			expectedAddedGroupID: "BackendID",
+		},
+		"OKCaseRemoveGroupNotMapped": {
+			claims:                    map[string]interface{}{"groups": []interface{}{"eng-backend"}},
+			getUserByExternalIDResult: user,
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{User: user, Group: backendGroup},
+				{User: user, Group: frontendGroup},
+				{User: user, Group: otherGroup},
+			},
+			expectedRemovedGroupID: "FrontendID",
+		},
+		"OKCaseUserNotFound": {
+			claims: map[string]interface{}{"groups": "eng-backend"},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"OKCaseMappedGroupNotFound": {
+			claims:                    map[string]interface{}{"groups": "eng-backend"},
+			getUserByExternalIDResult: user,
+			getGroupByNameMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetUserDBErr": {
+			getUserByExternalIDMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseAddMemberDBErr": {
+			claims:                    map[string]interface{}{"groups": "eng-backend"},
+			getUserByExternalIDResult: user,
+			getGroupByNameResult:      backendGroup,
+			addMemberMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsIn[AddMemberMethod][1] = nil
+		testRepo.ArgsIn[RemoveMemberMethod][1] = nil
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = testcase.getGroupByNameMethodErr
+		testRepo.ArgsOut[AddMemberMethod][0] = testcase.addMemberMethodErr
+
+		err := testAPI.SyncOidcUserGroups(RequestInfo{Identifier: "user1"}, oidcProvider, "user1", testcase.claims)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.expectedAddedGroupID, testRepo.ArgsIn[AddMemberMethod][1], "Error in test case %v", x)
+			assert.Equal(t, testcase.expectedRemovedGroupID, testRepo.ArgsIn[RemoveMemberMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestOidcProvider_MappedGroups(t *testing.T) {
+	oidcProvider := OidcProvider{
+		GroupMappings: []GroupMapping{
+			{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+			{Claim: "groups", Value: "eng", Org: "acme", Group: "engineering"},
+			{Claim: "department", Value: "eng", Org: "acme", Group: "engineering"},
+		},
+	}
+	testcases := map[string]struct {
+		claims map[string]interface{}
+		// Expected result
+		expectedGroups []GroupIdentity
+	}{
+		"OKCaseListClaim": {
+			claims: map[string]interface{}{"groups": []interface{}{"eng-backend", "sales"}},
+			expectedGroups: []GroupIdentity{
+				{Org: "acme", Name: "backend"},
+			},
+		},
+		"OKCaseStringClaimWithoutDuplicates": {
+			claims: map[string]interface{}{"groups": "eng", "department": "eng"},
+			expectedGroups: []GroupIdentity{
+				{Org: "acme", Name: "engineering"},
+			},
+		},
+		"OKCaseNoMatchingClaims": {
+			claims:         map[string]interface{}{"groups": 1, "department": "sales"},
+			expectedGroups: []GroupIdentity{},
+		},
+	}
+
+	for x, testcase := range testcases {
+		assert.Equal(t, testcase.expectedGroups, oidcProvider.MappedGroups(testcase.claims), "Error in test case %v", x)
+	}
+}
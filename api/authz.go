@@ -1,9 +1,12 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 )
@@ -14,6 +17,12 @@ type RequestInfo struct {
 	Identifier string
 	Admin      bool
 	RequestID  string
+
+	// Groups mapped from identity provider claims, effective only for this request
+	MappedGroups []GroupIdentity
+
+	// Context of the request, parent of API call spans
+	Context context.Context
 }
 
 type EffectRestriction struct {
@@ -29,17 +38,25 @@ type Restrictions struct {
 }
 
 type ExternalResource struct {
-	Urn string `json:"urn,omitempty"`
+	Urn  string            `json:"urn,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 func (e ExternalResource) GetUrn() string {
 	return e.Urn
 }
 
+func (e ExternalResource) GetTags() map[string]string {
+	return e.Tags
+}
+
 // AUTHZ API IMPLEMENTATION
 
 // GetAuthorizedUsers returns authorized users for specified resource+action
 func (api WorkerAPI) GetAuthorizedUsers(requestInfo RequestInfo, resourceUrn string, action string, users []User) ([]User, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedUsers")
+	defer span.End()
+
 	resourcesToAuthorize := []Resource{}
 	for _, usr := range users {
 		resourcesToAuthorize = append(resourcesToAuthorize, usr)
@@ -57,6 +74,9 @@ func (api WorkerAPI) GetAuthorizedUsers(requestInfo RequestInfo, resourceUrn str
 
 // GetAuthorizedGroups returns authorized users for specified user combined with resource+action
 func (api WorkerAPI) GetAuthorizedGroups(requestInfo RequestInfo, resourceUrn string, action string, groups []Group) ([]Group, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedGroups")
+	defer span.End()
+
 	resourcesToAuthorize := []Resource{}
 	for _, group := range groups {
 		resourcesToAuthorize = append(resourcesToAuthorize, group)
@@ -74,6 +94,9 @@ func (api WorkerAPI) GetAuthorizedGroups(requestInfo RequestInfo, resourceUrn st
 
 // GetAuthorizedPolicies returns authorized policies for specified user combined with resource+action
 func (api WorkerAPI) GetAuthorizedPolicies(requestInfo RequestInfo, resourceUrn string, action string, policies []Policy) ([]Policy, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedPolicies")
+	defer span.End()
+
 	resourcesToAuthorize := []Resource{}
 	for _, policy := range policies {
 		resourcesToAuthorize = append(resourcesToAuthorize, policy)
@@ -91,6 +114,9 @@ func (api WorkerAPI) GetAuthorizedPolicies(requestInfo RequestInfo, resourceUrn
 
 // GetAuthorizedProxyResources returns authorized proxy resources for specified user combined with resource+action
 func (api WorkerAPI) GetAuthorizedProxyResources(requestInfo RequestInfo, resourceUrn string, action string, proxyResources []ProxyResource) ([]ProxyResource, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedProxyResources")
+	defer span.End()
+
 	resourcesToAuthorize := []Resource{}
 	for _, proxyResource := range proxyResources {
 		resourcesToAuthorize = append(resourcesToAuthorize, proxyResource)
@@ -108,6 +134,9 @@ func (api WorkerAPI) GetAuthorizedProxyResources(requestInfo RequestInfo, resour
 
 // GetAuthorizedOidcProviders returns authorized OIDC providers for specified user combined with resource+action
 func (api WorkerAPI) GetAuthorizedOidcProviders(requestInfo RequestInfo, resourceUrn string, action string, oidcProviders []OidcProvider) ([]OidcProvider, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedOidcProviders")
+	defer span.End()
+
 	resourcesToAuthorize := []Resource{}
 	for _, oidcProvider := range oidcProviders {
 		resourcesToAuthorize = append(resourcesToAuthorize, oidcProvider)
@@ -123,8 +152,51 @@ func (api WorkerAPI) GetAuthorizedOidcProviders(requestInfo RequestInfo, resourc
 	return oidcProvidersFiltered, nil
 }
 
+// GetAuthorizedWebhooks returns authorized webhooks for specified user combined with resource+action
+func (api WorkerAPI) GetAuthorizedWebhooks(requestInfo RequestInfo, resourceUrn string, action string, webhooks []Webhook) ([]Webhook, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedWebhooks")
+	defer span.End()
+
+	resourcesToAuthorize := []Resource{}
+	for _, webhook := range webhooks {
+		resourcesToAuthorize = append(resourcesToAuthorize, webhook)
+	}
+	resources, err := api.getAuthorizedResources(requestInfo, resourceUrn, action, resourcesToAuthorize)
+	if err != nil {
+		return nil, err
+	}
+	webhooksFiltered := []Webhook{}
+	for _, res := range resources {
+		webhooksFiltered = append(webhooksFiltered, res.(Webhook))
+	}
+	return webhooksFiltered, nil
+}
+
+// GetAuthorizedChanges returns authorized changes for specified user combined with resource+action
+func (api WorkerAPI) GetAuthorizedChanges(requestInfo RequestInfo, resourceUrn string, action string, changes []Change) ([]Change, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedChanges")
+	defer span.End()
+
+	resourcesToAuthorize := []Resource{}
+	for _, change := range changes {
+		resourcesToAuthorize = append(resourcesToAuthorize, change)
+	}
+	resources, err := api.getAuthorizedResources(requestInfo, resourceUrn, action, resourcesToAuthorize)
+	if err != nil {
+		return nil, err
+	}
+	changesFiltered := []Change{}
+	for _, res := range resources {
+		changesFiltered = append(changesFiltered, res.(Change))
+	}
+	return changesFiltered, nil
+}
+
 // GetAuthorizedExternalResources returns the resources where the specified user has the action granted
-func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []string) ([]string, error) {
+func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []ExternalResource) ([]string, error) {
+	api, span := api.startSpan(&requestInfo, "GetAuthorizedExternalResources")
+	defer span.End()
+
 	// Validate parameters
 	if err := AreValidActions([]string{action}); err != nil {
 		// Transform to API error
@@ -142,13 +214,13 @@ func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, act
 	}
 	externalResources := []Resource{}
 	for _, res := range resources {
-		if !isFullUrn(res) {
+		if !isFullUrn(res.Urn) {
 			return nil, &Error{
 				Code:    INVALID_PARAMETER_ERROR,
-				Message: fmt.Sprintf("Invalid parameter resource %v. Urn prefixes are not allowed here", res),
+				Message: fmt.Sprintf("Invalid parameter resource %v. Urn prefixes are not allowed here", res.Urn),
 			}
 		}
-		if err := AreValidResources([]string{res}, RESOURCE_EXTERNAL); err != nil {
+		if err := AreValidResources([]string{res.Urn}, RESOURCE_EXTERNAL); err != nil {
 			// Transform to API error
 			apiError := err.(*Error)
 			return nil, &Error{
@@ -156,7 +228,15 @@ func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, act
 				Message: apiError.Message,
 			}
 		}
-		externalResources = append(externalResources, ExternalResource{Urn: res})
+		if err := AreValidTags(res.Tags); err != nil {
+			// Transform to API error
+			apiError := err.(*Error)
+			return nil, &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: apiError.Message,
+			}
+		}
+		externalResources = append(externalResources, res)
 	}
 	if strings.Contains(action, "*") {
 		return nil, &Error{
@@ -187,37 +267,76 @@ func (api WorkerAPI) GetAuthorizedExternalResources(requestInfo RequestInfo, act
 
 // PRIVATE HELPER METHODS
 
-// getAuthorizedResources retrieves filtered resources where the authenticated user has permissions
+// getAuthorizedResources retrieves filtered resources where the authenticated user has permissions, logging the decision taken
 func (api WorkerAPI) getAuthorizedResources(requestInfo RequestInfo, resourceUrn string, action string, resources []Resource) ([]Resource, error) {
+	start := time.Now()
+	resourcesFiltered, policies, err := api.authorizeResources(requestInfo, resourceUrn, action, resources)
+
+	decision := &AuthorizationDecision{
+		Source:        DECISION_SOURCE_WORKER,
+		RequestID:     requestInfo.RequestID,
+		User:          requestInfo.Identifier,
+		Admin:         requestInfo.Admin,
+		Action:        action,
+		Resource:      resourceUrn,
+		RequestedUrns: getResourceUrns(resources),
+		AllowedUrns:   getResourceUrns(resourcesFiltered),
+		Policies:      policies,
+		Latency:       time.Since(start),
+	}
+	if err != nil {
+		// Only denied access is a decision, other errors aren't logged
+		if apiError, ok := err.(*Error); !ok || apiError.Code != UNAUTHORIZED_RESOURCES_ERROR {
+			return nil, err
+		}
+		decision.Effect = DECISION_DENY
+	} else {
+		decision.Effect = getDecisionEffect(len(decision.RequestedUrns), len(decision.AllowedUrns))
+	}
+	LogAuthorizationDecision(decision)
+
+	return resourcesFiltered, err
+}
+
+// authorizeResources filters resources where the authenticated user has permissions, returning policies that matched
+func (api WorkerAPI) authorizeResources(requestInfo RequestInfo, resourceUrn string, action string, resources []Resource) ([]Resource, []string, error) {
 	// If user is an admin return all resources without restriction
 	if requestInfo.Admin {
-		return resources, nil
+		return resources, nil, nil
 	}
 
 	// Check authorization for this user
-	restrictions, err := api.getRestrictions(requestInfo.Identifier, action, resourceUrn)
+	restrictionsByTags, policies, err := api.getRestrictions(requestInfo.Identifier, requestInfo.MappedGroups, action, resourceUrn, resources)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	Log.Debugf("Restrictions: %v", *restrictions)
-
 	// Check if there are some restrictions for this urn resource
-	if len(restrictions.AllowedFullUrns) < 1 && len(restrictions.AllowedUrnPrefixes) < 1 {
-		return nil, &Error{
+	allowed := false
+	for tagsKey, restrictions := range restrictionsByTags {
+		Log.Debugf("Restrictions for tags %v: %v", tagsKey, *restrictions)
+		if len(restrictions.AllowedFullUrns) > 0 || len(restrictions.AllowedUrnPrefixes) > 0 {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, policies, &Error{
 			Code:    UNAUTHORIZED_RESOURCES_ERROR,
 			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v", requestInfo.Identifier, resourceUrn),
 		}
 	}
 
 	// Filter resources
-	resourcesFiltered := filterResources(resources, restrictions)
+	resourcesFiltered := filterResources(resources, restrictionsByTags)
 
-	return resourcesFiltered, nil
+	return resourcesFiltered, policies, nil
 }
 
 // Get restrictions for this action and full resource or prefix resource, attached to this authenticated user
-func (api WorkerAPI) getRestrictions(externalID string, action string, resource string) (*Restrictions, error) {
+// or its mapped groups, by tags of the resources to authorize. Restrictions for resources without tags are always
+// returned. It also returns the URNs of policies with statements that apply to them
+func (api WorkerAPI) getRestrictions(externalID string, mappedGroups []GroupIdentity, action string, resource string,
+	resources []Resource) (map[string]*Restrictions, []string, error) {
 	// Get user if exists
 	user, err := api.UserRepo.GetUserByExternalID(externalID)
 
@@ -227,36 +346,59 @@ func (api WorkerAPI) getRestrictions(externalID string, action string, resource
 		dbError := err.(*database.Error)
 		switch dbError.Code {
 		case database.USER_NOT_FOUND:
-			return nil, &Error{
+			return nil, nil, &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
 				Message: fmt.Sprintf("Authenticated user with externalId %v not found. Unable to retrieve permissions.", externalID),
 			}
 		default:
-			return nil, &Error{
+			return nil, nil, &Error{
 				Code:    UNKNOWN_API_ERROR,
 				Message: dbError.Message,
 			}
 		}
 	}
 
+	// Disabled users are denied everything, regardless of policies
+	if !user.Enabled {
+		return nil, nil, &Error{
+			Code:    UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("Authenticated user with externalId %v is disabled. Unable to retrieve permissions.", externalID),
+		}
+	}
+
 	groups, err := api.getGroupsByUser(user.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	groups, err = api.addMappedGroups(groups, mappedGroups)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	policies, err := api.getPoliciesByGroups(groups)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	// Discard statements with conditions that user attributes don't meet
+	policies = filterStatementsByConditions(policies, *user, groups)
+
 	// Retrieve valid statements
 	statements := getStatementsByRequestedAction(policies, action)
 
-	// Retrieve restrictions
-	var authResources *Restrictions
-	authResources = getRestrictions(statements, resource, isFullUrn(resource))
+	// Retrieve restrictions, once per each different set of tags
+	restrictionsByTags := map[string]*Restrictions{
+		"": getRestrictions(filterStatementsByTags(statements, nil), resource, isFullUrn(resource)),
+	}
+	for _, res := range resources {
+		tags := getResourceTags(res)
+		tagsKey := getTagsKey(tags)
+		if _, ok := restrictionsByTags[tagsKey]; !ok {
+			restrictionsByTags[tagsKey] = getRestrictions(filterStatementsByTags(statements, tags), resource, isFullUrn(resource))
+		}
+	}
 
-	return authResources, nil
+	return restrictionsByTags, getMatchingPolicies(policies, action, resource), nil
 }
 
 func (api WorkerAPI) getGroupsByUser(userID string) ([]Group, error) {
@@ -279,6 +421,38 @@ func (api WorkerAPI) getGroupsByUser(userID string) ([]Group, error) {
 	return groups, nil
 }
 
+// Add mapped groups to groups of user, ignoring groups that don't exist
+func (api WorkerAPI) addMappedGroups(groups []Group, mappedGroups []GroupIdentity) ([]Group, error) {
+	for _, mappedGroup := range mappedGroups {
+		isMember := false
+		for _, g := range groups {
+			if g.Org == mappedGroup.Org && g.Name == mappedGroup.Name {
+				isMember = true
+				break
+			}
+		}
+		if isMember {
+			continue
+		}
+		group, err := api.GroupRepo.GetGroupByName(mappedGroup.Org, mappedGroup.Name)
+		if err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			if dbError.Code == database.GROUP_NOT_FOUND {
+				Log.Debugf("Mapped group %v/%v not found", mappedGroup.Org, mappedGroup.Name)
+				continue
+			}
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		groups = append(groups, *group)
+	}
+
+	return groups, nil
+}
+
 // Retrieve policies attached to a slice of groups
 func (api WorkerAPI) getPoliciesByGroups(groups []Group) ([]Policy, error) {
 	if groups == nil || len(groups) < 1 {
@@ -309,6 +483,115 @@ func (api WorkerAPI) getPoliciesByGroups(groups []Group) ([]Policy, error) {
 	return policies, nil
 }
 
+// Filter statements of policies, keeping only statements whose conditions are all met by the user or its groups
+func filterStatementsByConditions(policies []Policy, user User, groups []Group) []Policy {
+	policiesFiltered := []Policy{}
+	for _, policy := range policies {
+		statements := []Statement{}
+		if policy.Statements != nil {
+			for _, statement := range *policy.Statements {
+				if areConditionsMatched(statement.Conditions, user, groups) {
+					statements = append(statements, statement)
+				}
+			}
+		}
+		policy.Statements = &statements
+		policiesFiltered = append(policiesFiltered, policy)
+	}
+
+	return policiesFiltered
+}
+
+// Returns true if all conditions are met by the user or its groups
+func areConditionsMatched(conditions []Condition, user User, groups []Group) bool {
+	for _, condition := range conditions {
+		if !isConditionMatched(condition, user, groups) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns true if the attribute of the user, or the attribute of any of its groups, meets the condition
+func isConditionMatched(condition Condition, user User, groups []Group) bool {
+	source, key := condition.getAttributeSourceAndKey()
+	match := false
+	switch source {
+	case CONDITION_SOURCE_USER:
+		value, ok := user.Attributes[key]
+		match = ok && isContainedInSlice(value, condition.Values)
+	case CONDITION_SOURCE_GROUP:
+		for _, group := range groups {
+			value, ok := group.Attributes[key]
+			if ok && isContainedInSlice(value, condition.Values) {
+				match = true
+				break
+			}
+		}
+	case CONDITION_SOURCE_RESOURCE:
+		// Conditions on resource tags are checked later, for each resource
+		return true
+	default:
+		return false
+	}
+
+	if condition.Operator == CONDITION_OPERATOR_NOT_EQUALS {
+		return !match
+	}
+	return match
+}
+
+// Filter statements, keeping only statements whose conditions on resource tags are all met by the tags
+func filterStatementsByTags(statements []Statement, tags map[string]string) []Statement {
+	statementsFiltered := []Statement{}
+	for _, statement := range statements {
+		if areTagConditionsMatched(statement.Conditions, tags) {
+			statementsFiltered = append(statementsFiltered, statement)
+		}
+	}
+
+	return statementsFiltered
+}
+
+// Returns true if all conditions on resource tags are met by the tags
+func areTagConditionsMatched(conditions []Condition, tags map[string]string) bool {
+	for _, condition := range conditions {
+		source, key := condition.getAttributeSourceAndKey()
+		if source != CONDITION_SOURCE_RESOURCE {
+			continue
+		}
+		value, ok := tags[key]
+		match := ok && isContainedInSlice(value, condition.Values)
+		if condition.Operator == CONDITION_OPERATOR_NOT_EQUALS {
+			match = !match
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Retrieve tags of a resource, nil if it hasn't tags
+func getResourceTags(resource Resource) map[string]string {
+	if taggedResource, ok := resource.(TaggedResource); ok {
+		return taggedResource.GetTags()
+	}
+	return nil
+}
+
+// Retrieve a key that identifies a set of tags, empty for no tags
+func getTagsKey(tags map[string]string) string {
+	if len(tags) < 1 {
+		return ""
+	}
+	// Map keys are sorted when marshalled
+	key, _ := json.Marshal(tags)
+	return string(key)
+}
+
 // Filter a slice of statements for a specified action
 func getStatementsByRequestedAction(policies []Policy, requestedAction string) []Statement {
 	// Check received policies
@@ -328,6 +611,49 @@ func getStatementsByRequestedAction(policies []Policy, requestedAction string) [
 	return statements
 }
 
+// Retrieve URNs of policies with statements for the requested action that apply to a resource
+func getMatchingPolicies(policies []Policy, requestedAction string, resource string) []string {
+	matchingPolicies := []string{}
+	for _, policy := range policies {
+		// Skip policies already matched, attached to several groups
+		if isContainedInSlice(policy.Urn, matchingPolicies) {
+			continue
+		}
+		for _, statement := range getStatementsByRequestedAction([]Policy{policy}, requestedAction) {
+			if isStatementApplicable(statement, resource) {
+				matchingPolicies = append(matchingPolicies, policy.Urn)
+				break
+			}
+		}
+	}
+
+	return matchingPolicies
+}
+
+// Returns true if any statement resource contains the resource, or it is contained in the resource when it is a prefix
+func isStatementApplicable(statement Statement, resource string) bool {
+	resourceIsFullUrn := isFullUrn(resource)
+	for _, statementResource := range statement.Resources {
+		if isContainedOrEqual(resource, statementResource) ||
+			(!resourceIsFullUrn && isContainedOrEqual(statementResource, resource)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if a value is in a slice
+func isContainedInSlice(value string, slice []string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Returns true if an action is contained inside a slice of statements
 func isActionContained(actionRequested string, statementActions []string) bool {
 	match := false
@@ -521,11 +847,12 @@ func getRestrictions(statements []Statement, resource string, resourceIsFullUrn
 	return restrictions
 }
 
-// Remove resources that are not allowed by the restrictions
-func filterResources(resources []Resource, restrictions *Restrictions) []Resource {
+// Remove resources that are not allowed by the restrictions for their tags
+func filterResources(resources []Resource, restrictionsByTags map[string]*Restrictions) []Resource {
 	filteredResource := []Resource{}
 	for _, r := range resources {
-		if isAllowedResource(r, *restrictions) {
+		restrictions, ok := restrictionsByTags[getTagsKey(getResourceTags(r))]
+		if ok && isAllowedResource(r, *restrictions) {
 			filteredResource = append(filteredResource, r)
 		}
 	}
@@ -304,8 +304,8 @@ func TestWorkerAPI_GetAuthorizedOidcProviders(t *testing.T) {
 				Identifier: "123456",
 				Admin:      true,
 			},
-			resourceUrn: CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "keycloak"),
-			action:      AUTH_OIDC_ACTION_GET_PROVIDER,
+			resourceUrn:              CreateUrn("", RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "keycloak"),
+			action:                   AUTH_OIDC_ACTION_GET_PROVIDER,
 			oidcProvidersToAuthorize: []OidcProvider{},
 			oidcProvidersAuthorized:  []OidcProvider{},
 		},
@@ -351,6 +351,8 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 		requestInfo RequestInfo
 		// Resource urns that user wants to access
 		resourceUrns []string
+		// Tags of resources by urn
+		resourceTags map[string]map[string]string
 		// Action to do
 		action string
 		// Expected allowed resources
@@ -403,6 +405,24 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 				Message: "Invalid parameter resource urn:*. Urn prefixes are not allowed here",
 			},
 		},
+		"ErrortestCaseInvalidTag": {
+			requestInfo: RequestInfo{
+				Admin: true,
+			},
+			action: "product:DoSomething",
+			resourceUrns: []string{
+				"urn:ews:product:instance:resource/path1/resource",
+			},
+			resourceTags: map[string]map[string]string{
+				"urn:ews:product:instance:resource/path1/resource": {
+					"env:invalid": "prod",
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: tag key env:invalid",
+			},
+		},
 		"ErrortestCaseEmptyResources": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -469,8 +489,9 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 				Message: "User with externalId 123456 is not allowed to access to any resource",
 			},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -526,8 +547,9 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 				CreateUrn("example", RESOURCE_POLICY, "/path/", "policy2"),
 			},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -571,8 +593,9 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 			},
 			expectedResources: []string{CreateUrn("example2", RESOURCE_POLICY, "/path/path2/", "policy3")},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -629,8 +652,9 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 				"urn:ews:product:instance:resource/path2/resourceAllow",
 			},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -675,6 +699,89 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 				},
 			},
 		},
+		"OktestCaseWithTagConditions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			resourceUrns: []string{
+				"urn:ews:product:instance:resource/path1/resourceProd",
+				"urn:ews:product:instance:resource/path1/resourceDev",
+				"urn:ews:product:instance:resource/path1/resourceNoTags",
+				"urn:ews:product:instance:resource/path1/resourceProdSecret",
+			},
+			resourceTags: map[string]map[string]string{
+				"urn:ews:product:instance:resource/path1/resourceProd": {
+					"env": "prod",
+				},
+				"urn:ews:product:instance:resource/path1/resourceDev": {
+					"env": "dev",
+				},
+				"urn:ews:product:instance:resource/path1/resourceProdSecret": {
+					"env":            "prod",
+					"classification": "secret",
+				},
+			},
+			action: "product:DoAction",
+			expectedResources: []string{
+				"urn:ews:product:instance:resource/path1/resourceProd",
+			},
+			getUserByExternalIDResult: &User{
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:  "GROUP-USER-ID",
+						Urn: CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:  "POLICY-USER-ID",
+						Urn: CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									"product:DoAction",
+								},
+								Resources: []string{
+									"urn:ews:product:instance:resource/path1*",
+								},
+								Conditions: []Condition{
+									{
+										Operator:  CONDITION_OPERATOR_EQUALS,
+										Attribute: "resource:env",
+										Values:    []string{"prod"},
+									},
+								},
+							},
+							{
+								Effect: "deny",
+								Actions: []string{
+									"product:DoAction",
+								},
+								Resources: []string{
+									"urn:ews:product:instance:resource/path1*",
+								},
+								Conditions: []Condition{
+									{
+										Operator:  CONDITION_OPERATOR_EQUALS,
+										Attribute: "resource:classification",
+										Values:    []string{"secret"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for n, test := range testcases {
@@ -691,7 +798,11 @@ func TestGetAuthorizedExternalResources(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][1] = test.getAttachedPoliciesError
 
-		resources, err := testAPI.GetAuthorizedExternalResources(test.requestInfo, test.action, test.resourceUrns)
+		externalResources := []ExternalResource{}
+		for _, urn := range test.resourceUrns {
+			externalResources = append(externalResources, ExternalResource{Urn: urn, Tags: test.resourceTags[urn]})
+		}
+		resources, err := testAPI.GetAuthorizedExternalResources(test.requestInfo, test.action, externalResources)
 		checkMethodResponse(t, n, test.wantError, err, test.expectedResources, resources)
 		if !test.requestInfo.Admin {
 			// Check received authenticated user in method GetUserByExternalID
@@ -794,8 +905,9 @@ func TestGetAuthorizedResources(t *testing.T) {
 				},
 			},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			wantError: &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
@@ -826,8 +938,9 @@ func TestGetAuthorizedResources(t *testing.T) {
 				},
 			},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -878,8 +991,9 @@ func TestGetAuthorizedResources(t *testing.T) {
 			},
 			resourcesAuthorized: []Resource{},
 			getUserByExternalIDResult: &User{
-				ID:  "123456",
-				Urn: CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				ID:      "123456",
+				Urn:     CreateUrn("", RESOURCE_USER, "/path/", "user1"),
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -979,6 +1093,19 @@ func TestGetRestrictions(t *testing.T) {
 				Code: database.INTERNAL_ERROR,
 			},
 		},
+		"ErrortestCaseGetUserAuthenticatedDisabled": {
+			authUserID:  "Disabled",
+			resourceUrn: "urn:resource",
+			action:      USER_ACTION_GET_USER,
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Authenticated user with externalId Disabled is disabled. Unable to retrieve permissions.",
+			},
+			getUserByExternalIDResult: &User{
+				ID:             "UserID",
+				DisabledReason: "Offboarded",
+			},
+		},
 		"ErrortestCaseGetGroupsError": {
 			authUserID:  "InternalError",
 			resourceUrn: "urn:resource",
@@ -987,7 +1114,8 @@ func TestGetRestrictions(t *testing.T) {
 				Code: UNKNOWN_API_ERROR,
 			},
 			getUserByExternalIDResult: &User{
-				ID: "UserID",
+				ID:      "UserID",
+				Enabled: true,
 			},
 			getGroupsByUserIDError: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -1001,7 +1129,8 @@ func TestGetRestrictions(t *testing.T) {
 				Code: UNKNOWN_API_ERROR,
 			},
 			getUserByExternalIDResult: &User{
-				ID: "UserID",
+				ID:      "UserID",
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1025,7 +1154,8 @@ func TestGetRestrictions(t *testing.T) {
 				DeniedFullUrns:     []string{},
 			},
 			getUserByExternalIDResult: &User{
-				ID: "AuthUserID",
+				ID:      "AuthUserID",
+				Enabled: true,
 			},
 		},
 		"OktestCaseEmptyRelationsPrefixUrn": {
@@ -1039,7 +1169,8 @@ func TestGetRestrictions(t *testing.T) {
 				DeniedFullUrns:     []string{},
 			},
 			getUserByExternalIDResult: &User{
-				ID: "AuthUserID",
+				ID:      "AuthUserID",
+				Enabled: true,
 			},
 		},
 		"OktestCaseFullUrn": {
@@ -1055,7 +1186,8 @@ func TestGetRestrictions(t *testing.T) {
 				DeniedUrnPrefixes: []string{},
 			},
 			getUserByExternalIDResult: &User{
-				ID: "AuthUserID",
+				ID:      "AuthUserID",
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1119,7 +1251,8 @@ func TestGetRestrictions(t *testing.T) {
 				},
 			},
 			getUserByExternalIDResult: &User{
-				ID: "AuthUserID",
+				ID:      "AuthUserID",
+				Enabled: true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1178,7 +1311,8 @@ func TestGetRestrictions(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = test.getAttachedPoliciesResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][2] = test.getAttachedPoliciesError
 
-		restrictions, err := testAPI.getRestrictions(test.authUserID, test.action, test.resourceUrn)
+		restrictionsByTags, _, err := testAPI.getRestrictions(test.authUserID, nil, test.action, test.resourceUrn, nil)
+		restrictions := restrictionsByTags[""]
 		checkMethodResponse(t, n, test.wantError, err, test.expectedRestrictions, restrictions)
 		if test.wantError == nil {
 			assert.Equal(t, test.authUserID, testRepo.ArgsIn[GetUserByExternalIDMethod][0], "Error in test case %v", n)
@@ -1250,6 +1384,73 @@ func TestGetGroupsByUser(t *testing.T) {
 	}
 }
 
+func TestAddMappedGroups(t *testing.T) {
+	testcases := map[string]struct {
+		// Groups of user and mapped groups
+		groups       []Group
+		mappedGroups []GroupIdentity
+		// Expected Groups
+		expectedGroups []Group
+		// Error to compare when we expect an error
+		wantError error
+		// GetGroupByName Method Out Arguments
+		getGroupByNameResult *Group
+		getGroupByNameError  error
+	}{
+		"OktestCase": {
+			groups: []Group{
+				{ID: "GROUP-USER-ID1", Org: "acme", Name: "group1"},
+			},
+			mappedGroups: []GroupIdentity{
+				{Org: "acme", Name: "group1"},
+				{Org: "acme", Name: "group2"},
+			},
+			expectedGroups: []Group{
+				{ID: "GROUP-USER-ID1", Org: "acme", Name: "group1"},
+				{ID: "GROUP-USER-ID2", Org: "acme", Name: "group2"},
+			},
+			getGroupByNameResult: &Group{ID: "GROUP-USER-ID2", Org: "acme", Name: "group2"},
+		},
+		"OktestCaseGroupNotFound": {
+			groups: []Group{
+				{ID: "GROUP-USER-ID1", Org: "acme", Name: "group1"},
+			},
+			mappedGroups: []GroupIdentity{
+				{Org: "acme", Name: "group2"},
+			},
+			expectedGroups: []Group{
+				{ID: "GROUP-USER-ID1", Org: "acme", Name: "group1"},
+			},
+			getGroupByNameError: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+		},
+		"ErrortestCase": {
+			mappedGroups: []GroupIdentity{
+				{Org: "acme", Name: "group2"},
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getGroupByNameError: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = test.getGroupByNameResult
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = test.getGroupByNameError
+
+		groups, err := testAPI.addMappedGroups(test.groups, test.mappedGroups)
+		checkMethodResponse(t, n, test.wantError, err, test.expectedGroups, groups)
+	}
+}
+
 func TestGetPoliciesByGroups(t *testing.T) {
 	testcases := map[string]struct {
 		groups           []Group
@@ -1439,6 +1640,352 @@ func TestGetStatementsByRequestedAction(t *testing.T) {
 	}
 }
 
+func TestFilterStatementsByConditions(t *testing.T) {
+	user := User{
+		ID:         "UserID",
+		ExternalID: "user",
+		Attributes: map[string]string{
+			"clearance": "high",
+		},
+	}
+	groups := []Group{
+		{
+			ID:   "GroupID1",
+			Name: "group1",
+			Attributes: map[string]string{
+				"department": "backend",
+			},
+		},
+		{
+			ID:   "GroupID2",
+			Name: "group2",
+		},
+	}
+	unconditionalStatement := Statement{
+		Effect:    "allow",
+		Actions:   []string{"vault:read"},
+		Resources: []string{"urn:ews:vault:*"},
+	}
+	testcases := map[string]struct {
+		// Method args
+		conditions []Condition
+		// Expected result
+		matched bool
+	}{
+		"OKCaseUserAttributeEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"medium", "high"},
+				},
+			},
+			matched: true,
+		},
+		"OKCaseUserAttributeNotEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+			},
+		},
+		"OKCaseUserAttributeNotFound": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:department",
+					Values:    []string{"backend"},
+				},
+			},
+		},
+		"OKCaseUserAttributeNotFoundNotEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "user:department",
+					Values:    []string{"backend"},
+				},
+			},
+			matched: true,
+		},
+		"OKCaseGroupAttributeEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "group:department",
+					Values:    []string{"backend"},
+				},
+			},
+			matched: true,
+		},
+		"OKCaseGroupAttributeNotEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "group:department",
+					Values:    []string{"backend"},
+				},
+			},
+		},
+		"OKCaseAllConditionsMatched": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "group:department",
+					Values:    []string{"backend"},
+				},
+			},
+			matched: true,
+		},
+		"OKCaseAnyConditionNotMatched": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "group:department",
+					Values:    []string{"sales"},
+				},
+			},
+		},
+		"OKCaseResourceTagConditionSkipped": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"prod"},
+				},
+			},
+			matched: true,
+		},
+	}
+
+	for n, test := range testcases {
+		conditionalStatement := Statement{
+			Effect:     "allow",
+			Actions:    []string{"vault:read"},
+			Resources:  []string{"urn:ews:vault:secrets"},
+			Conditions: test.conditions,
+		}
+		policies := []Policy{
+			{
+				ID:         "PolicyID",
+				Statements: &[]Statement{unconditionalStatement, conditionalStatement},
+			},
+		}
+		expectedStatements := []Statement{unconditionalStatement}
+		if test.matched {
+			expectedStatements = append(expectedStatements, conditionalStatement)
+		}
+
+		filteredPolicies := filterStatementsByConditions(policies, user, groups)
+		assert.Equal(t, 1, len(filteredPolicies), "Error in test case %v", n)
+		assert.Equal(t, expectedStatements, *filteredPolicies[0].Statements, "Error in test case %v", n)
+	}
+}
+
+func TestFilterStatementsByTags(t *testing.T) {
+	unconditionalStatement := Statement{
+		Effect:    "allow",
+		Actions:   []string{"vault:read"},
+		Resources: []string{"urn:ews:vault:*"},
+	}
+	testcases := map[string]struct {
+		// Method args
+		conditions []Condition
+		tags       map[string]string
+		// Expected result
+		matched bool
+	}{
+		"OKCaseTagEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"pre", "prod"},
+				},
+			},
+			tags: map[string]string{
+				"env": "prod",
+			},
+			matched: true,
+		},
+		"OKCaseTagNotEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"prod"},
+				},
+			},
+			tags: map[string]string{
+				"env": "prod",
+			},
+		},
+		"OKCaseTagNotFound": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"prod"},
+				},
+			},
+		},
+		"OKCaseTagNotFoundNotEquals": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"prod"},
+				},
+			},
+			matched: true,
+		},
+		"OKCaseUserAttributeConditionSkipped": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+			},
+			matched: true,
+		},
+	}
+
+	for n, test := range testcases {
+		conditionalStatement := Statement{
+			Effect:     "allow",
+			Actions:    []string{"vault:read"},
+			Resources:  []string{"urn:ews:vault:secrets"},
+			Conditions: test.conditions,
+		}
+		expectedStatements := []Statement{unconditionalStatement}
+		if test.matched {
+			expectedStatements = append(expectedStatements, conditionalStatement)
+		}
+
+		filteredStatements := filterStatementsByTags([]Statement{unconditionalStatement, conditionalStatement}, test.tags)
+		assert.Equal(t, expectedStatements, filteredStatements, "Error in test case %v", n)
+	}
+}
+
+func TestGetMatchingPolicies(t *testing.T) {
+	policies := []Policy{
+		{
+			Urn: CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllow"),
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						GROUP_ACTION_GET_GROUP,
+					},
+					Resources: []string{
+						GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
+					},
+				},
+			},
+		},
+		{
+			Urn: CreateUrn("example", RESOURCE_POLICY, "/path/", "policyDeny"),
+			Statements: &[]Statement{
+				{
+					Effect: "deny",
+					Actions: []string{
+						"iam:*",
+					},
+					Resources: []string{
+						CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupDeny"),
+					},
+				},
+			},
+		},
+		{
+			Urn: CreateUrn("example", RESOURCE_POLICY, "/path/", "policyOtherAction"),
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						GROUP_ACTION_DELETE_GROUP,
+					},
+					Resources: []string{
+						GetUrnPrefix("example", RESOURCE_GROUP, "/path1/"),
+					},
+				},
+			},
+		},
+	}
+	testcases := map[string]struct {
+		// Policies to check
+		policies []Policy
+		action   string
+		resource string
+		// Expected data
+		expectedPolicies []string
+	}{
+		"OktestCaseNilPolicies": {
+			action:           GROUP_ACTION_GET_GROUP,
+			resource:         CreateUrn("example", RESOURCE_GROUP, "/path1/", "group"),
+			expectedPolicies: []string{},
+		},
+		"OktestCaseFullUrn": {
+			policies: policies,
+			action:   GROUP_ACTION_GET_GROUP,
+			resource: CreateUrn("example", RESOURCE_GROUP, "/path1/", "group"),
+			expectedPolicies: []string{
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllow"),
+			},
+		},
+		"OktestCaseFullUrnDenied": {
+			policies: policies,
+			action:   GROUP_ACTION_GET_GROUP,
+			resource: CreateUrn("example", RESOURCE_GROUP, "/path1/", "groupDeny"),
+			expectedPolicies: []string{
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllow"),
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyDeny"),
+			},
+		},
+		"OktestCasePrefix": {
+			policies: policies,
+			action:   GROUP_ACTION_GET_GROUP,
+			resource: GetUrnPrefix("example", RESOURCE_GROUP, "/"),
+			expectedPolicies: []string{
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllow"),
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyDeny"),
+			},
+		},
+		"OktestCaseNoMatchingResource": {
+			policies:         policies,
+			action:           GROUP_ACTION_GET_GROUP,
+			resource:         CreateUrn("example", RESOURCE_GROUP, "/path2/", "group"),
+			expectedPolicies: []string{},
+		},
+		"OktestCaseRepeatedPolicies": {
+			policies: []Policy{policies[0], policies[0]},
+			action:   GROUP_ACTION_GET_GROUP,
+			resource: CreateUrn("example", RESOURCE_GROUP, "/path1/", "group"),
+			expectedPolicies: []string{
+				CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllow"),
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		matchingPolicies := getMatchingPolicies(test.policies, test.action, test.resource)
+		checkMethodResponse(t, n, nil, nil, test.expectedPolicies, matchingPolicies)
+	}
+}
+
 func TestIsActionContained(t *testing.T) {
 	testcases := map[string]struct {
 		actionRequested  string
@@ -2288,7 +2835,7 @@ func TestFilterResources(t *testing.T) {
 	}
 
 	for n, test := range testcases {
-		filteredResources := filterResources(test.resources, test.restrictions)
+		filteredResources := filterResources(test.resources, map[string]*Restrictions{"": test.restrictions})
 		checkMethodResponse(t, n, nil, nil, test.expectedResources, filteredResources)
 	}
 }
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+)
+
+const (
+	// Resource used to authorize the change feed, that contains all IAM resources
+	CHANGE_RESOURCE_URN = "urn:iws:iam:*"
+)
+
+// TYPE DEFINITIONS
+
+// Change is an entry of the change feed. Its sequence is assigned in the same transaction
+// that modifies the resource, so changes are ordered as they were committed.
+// Type is one of the event types. For membership and policy attachment changes,
+// resource is the group and related resource is the user or the policy.
+type Change struct {
+	Seq        int64     `json:"seq"`
+	Type       string    `json:"type,omitempty"`
+	ResourceID string    `json:"resourceId,omitempty"`
+	RelatedID  string    `json:"relatedId,omitempty"`
+	Urn        string    `json:"urn,omitempty"`
+	CreateAt   time.Time `json:"createAt,omitempty"`
+}
+
+func (c Change) GetUrn() string {
+	return c.Urn
+}
+
+// Cursor returns the cursor to resume the change feed after this change
+func (c Change) Cursor() string {
+	return formatChangeCursor(c.Seq)
+}
+
+// CHANGE API IMPLEMENTATION
+
+func (api WorkerAPI) ListChanges(requestInfo RequestInfo, cursor string, limit int) ([]Change, string, error) {
+	api, span := api.startSpan(&requestInfo, "ListChanges")
+	defer span.End()
+
+	// Validate fields
+	since, err := parseChangeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit == 0 {
+		limit = DEFAULT_LIMIT_SIZE
+	} else if limit < 0 || limit > MAX_LIMIT_SIZE {
+		return nil, "", &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: limit %v, max limit allowed: %v", limit, MAX_LIMIT_SIZE),
+		}
+	}
+
+	// Retrieve changes
+	changes, err := api.ChangeRepo.GetChanges(since, limit)
+	if err != nil {
+		return nil, "", changeRepoError(err)
+	}
+	nextCursor := nextChangeCursor(since, changes)
+
+	// Filter changes. Cursor skips changes that user isn't allowed to see too
+	changesFiltered, err := api.GetAuthorizedChanges(requestInfo, CHANGE_RESOURCE_URN, CHANGE_ACTION_LIST_CHANGES, changes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return changesFiltered, nextCursor, nil
+}
+
+// GetChanges returns changes after cursor without authorization, for internal use of proxy
+func (api ProxyAPI) GetChanges(cursor string) ([]Change, string, error) {
+	since, err := parseChangeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	changes, err := api.ChangeRepo.GetChanges(since, MAX_LIMIT_SIZE)
+	if err != nil {
+		return nil, "", changeRepoError(err)
+	}
+
+	return changes, nextChangeCursor(since, changes), nil
+}
+
+// GetLastChangeCursor returns the cursor of the last change
+func (api ProxyAPI) GetLastChangeCursor() (string, error) {
+	seq, err := api.ChangeRepo.GetLastChangeSeq()
+	if err != nil {
+		return "", changeRepoError(err)
+	}
+
+	return formatChangeCursor(seq), nil
+}
+
+// PRIVATE HELPER METHODS
+
+// nextChangeCursor returns the cursor after the last change read, or the same cursor if there aren't changes
+func nextChangeCursor(since int64, changes []Change) string {
+	if len(changes) > 0 {
+		return changes[len(changes)-1].Cursor()
+	}
+	return formatChangeCursor(since)
+}
+
+// Empty cursor starts the change feed from the beginning
+func parseChangeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: cursor %v", cursor),
+		}
+	}
+	return seq, nil
+}
+
+func formatChangeCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+func changeRepoError(err error) error {
+	//Transform to DB error
+	dbError := err.(*database.Error)
+	return &Error{
+		Code:    UNKNOWN_API_ERROR,
+		Message: dbError.Message,
+	}
+}
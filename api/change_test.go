@@ -0,0 +1,292 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_ListChanges(t *testing.T) {
+	groupUrn := CreateUrn("example", RESOURCE_GROUP, "/path/", "group1")
+	userUrn := CreateUrn("", RESOURCE_USER, "/path/", "user1")
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		cursor      string
+		limit       int
+		// Expected result
+		expectedChanges []Change
+		expectedCursor  string
+		expectedSince   int64
+		expectedLimit   int
+		wantError       error
+		// Manager Results
+		getChangesResult          []Change
+		getUserByExternalIDResult *User
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		// Manager Errors
+		getChangesErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			cursor: "10",
+			limit:  2,
+			getChangesResult: []Change{
+				{Seq: 11, Type: EVENT_GROUP_CREATED, ResourceID: "GROUP1", Urn: groupUrn},
+				{Seq: 13, Type: EVENT_GROUP_MEMBER_ADDED, ResourceID: "GROUP1", RelatedID: "USER1", Urn: groupUrn},
+			},
+			expectedChanges: []Change{
+				{Seq: 11, Type: EVENT_GROUP_CREATED, ResourceID: "GROUP1", Urn: groupUrn},
+				{Seq: 13, Type: EVENT_GROUP_MEMBER_ADDED, ResourceID: "GROUP1", RelatedID: "USER1", Urn: groupUrn},
+			},
+			expectedCursor: "13",
+			expectedSince:  10,
+			expectedLimit:  2,
+		},
+		"OKCaseNoChanges": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			cursor:          "10",
+			expectedChanges: []Change{},
+			expectedCursor:  "10",
+			expectedSince:   10,
+			expectedLimit:   DEFAULT_LIMIT_SIZE,
+		},
+		"OKCaseEmptyCursor": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			getChangesResult: []Change{
+				{Seq: 1, Type: EVENT_USER_CREATED, ResourceID: "USER1", Urn: userUrn},
+			},
+			expectedChanges: []Change{
+				{Seq: 1, Type: EVENT_USER_CREATED, ResourceID: "USER1", Urn: userUrn},
+			},
+			expectedCursor: "1",
+			expectedSince:  0,
+			expectedLimit:  DEFAULT_LIMIT_SIZE,
+		},
+		"OKCaseFilteredChanges": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			cursor: "10",
+			getChangesResult: []Change{
+				{Seq: 11, Type: EVENT_GROUP_CREATED, ResourceID: "GROUP1", Urn: groupUrn},
+				{Seq: 12, Type: EVENT_USER_CREATED, ResourceID: "USER1", Urn: userUrn},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policy",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									CHANGE_ACTION_LIST_CHANGES,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_GROUP, "/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedChanges: []Change{
+				{Seq: 11, Type: EVENT_GROUP_CREATED, ResourceID: "GROUP1", Urn: groupUrn},
+			},
+			expectedCursor: "12",
+			expectedSince:  10,
+			expectedLimit:  DEFAULT_LIMIT_SIZE,
+		},
+		"ErrorCaseInvalidCursor": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			cursor: "abc",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor abc",
+			},
+		},
+		"ErrorCaseNegativeCursor": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			cursor: "-1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor -1",
+			},
+		},
+		"ErrorCaseInvalidLimit": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			limit: 10000,
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: limit 10000, max limit allowed: 1000",
+			},
+		},
+		"ErrorCaseDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			getChangesErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetChangesMethod][0] = testcase.getChangesResult
+		testRepo.ArgsOut[GetChangesMethod][1] = testcase.getChangesErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		changes, cursor, err := testAPI.ListChanges(testcase.requestInfo, testcase.cursor, testcase.limit)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedChanges, changes)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.expectedCursor, cursor, "Error in test case %v", x)
+			assert.Equal(t, testcase.expectedSince, testRepo.ArgsIn[GetChangesMethod][0], "Error in test case %v", x)
+			assert.Equal(t, testcase.expectedLimit, testRepo.ArgsIn[GetChangesMethod][1], "Error in test case %v", x)
+		}
+	}
+}
+
+func TestProxyAPI_GetChanges(t *testing.T) {
+	testcases := map[string]struct {
+		cursor string
+
+		expectedCursor string
+		wantError      error
+
+		getChangesResult []Change
+		getChangesErr    error
+	}{
+		"OKCase": {
+			cursor: "5",
+			getChangesResult: []Change{
+				{Seq: 6, Type: EVENT_PROXY_RESOURCE_CREATED, ResourceID: "PR1"},
+				{Seq: 8, Type: EVENT_PROXY_RESOURCE_DELETED, ResourceID: "PR1"},
+			},
+			expectedCursor: "8",
+		},
+		"OKCaseNoChanges": {
+			cursor:         "5",
+			expectedCursor: "5",
+		},
+		"ErrorCaseInvalidCursor": {
+			cursor: "abc",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor abc",
+			},
+		},
+		"ErrorCaseInternalError": {
+			cursor: "5",
+			getChangesErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for n, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeProxyTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetChangesMethod][0] = testcase.getChangesResult
+		testRepo.ArgsOut[GetChangesMethod][1] = testcase.getChangesErr
+
+		changes, cursor, err := testAPI.GetChanges(testcase.cursor)
+		checkMethodResponse(t, n, testcase.wantError, err, testcase.getChangesResult, changes)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.expectedCursor, cursor, "Error in test case %v", n)
+			assert.Equal(t, MAX_LIMIT_SIZE, testRepo.ArgsIn[GetChangesMethod][1], "Error in test case %v", n)
+		}
+	}
+}
+
+func TestProxyAPI_GetLastChangeCursor(t *testing.T) {
+	testcases := map[string]struct {
+		expectedCursor string
+		wantError      error
+
+		getLastChangeSeqResult int64
+		getLastChangeSeqErr    error
+	}{
+		"OKCase": {
+			getLastChangeSeqResult: 25,
+			expectedCursor:         "25",
+		},
+		"OKCaseNoChanges": {
+			expectedCursor: "0",
+		},
+		"ErrorCaseInternalError": {
+			getLastChangeSeqErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	for n, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeProxyTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetLastChangeSeqMethod][0] = testcase.getLastChangeSeqResult
+		testRepo.ArgsOut[GetLastChangeSeqMethod][1] = testcase.getLastChangeSeqErr
+
+		cursor, err := testAPI.GetLastChangeCursor()
+		checkMethodResponse(t, n, testcase.wantError, err, testcase.expectedCursor, cursor)
+	}
+}
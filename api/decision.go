@@ -0,0 +1,106 @@
+package api
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/Tecsisa/foulkon/metrics"
+)
+
+const (
+	// Decision effects
+	DECISION_ALLOW   = "allow"
+	DECISION_PARTIAL = "partial"
+	DECISION_DENY    = "deny"
+
+	// Decision sources
+	DECISION_SOURCE_WORKER = "worker"
+	DECISION_SOURCE_PROXY  = "proxy"
+)
+
+// DecisionLog is API global logger for authorization decisions. Decisions aren't logged if it is nil
+var DecisionLog *logrus.Logger
+
+// DecisionLogSampleRate is the ratio (between 0 and 1) of allowed decisions that are logged.
+// Decisions with any denied resource are always logged
+var DecisionLogSampleRate = 1.0
+
+// aux func to sample decisions, replaced in tests
+var decisionSampler = rand.Float64
+
+// AuthorizationDecision holds the outcome of an authorization request
+type AuthorizationDecision struct {
+	Source          string
+	RequestID       string
+	WorkerRequestID string
+	User            string
+	Admin           bool
+	Action          string
+	Effect          string
+	Resource        string
+	RequestedUrns   []string
+	AllowedUrns     []string
+	Policies        []string
+	Latency         time.Duration
+}
+
+// getDecisionEffect returns the effect of a granted decision according to requested and allowed resources
+func getDecisionEffect(requested int, allowed int) string {
+	switch {
+	case requested > 0 && allowed < 1:
+		return DECISION_DENY
+	case allowed < requested:
+		return DECISION_PARTIAL
+	default:
+		return DECISION_ALLOW
+	}
+}
+
+// LogAuthorizationDecision counts an authorization decision in metrics and logs it in decision logger,
+// applying sample rate to allowed decisions
+func LogAuthorizationDecision(decision *AuthorizationDecision) {
+	metrics.AuthzDecisions.WithLabelValues(decision.Source, decision.Effect).Inc()
+	if DecisionLog == nil {
+		return
+	}
+	if decision.Effect == DECISION_ALLOW && decisionSampler() >= DecisionLogSampleRate {
+		return
+	}
+
+	fields := logrus.Fields{
+		"source":        decision.Source,
+		"effect":        decision.Effect,
+		"action":        decision.Action,
+		"resource":      decision.Resource,
+		"requestedUrns": decision.RequestedUrns,
+		"allowedUrns":   decision.AllowedUrns,
+		"latencyMs":     float64(decision.Latency) / float64(time.Millisecond),
+	}
+	if decision.RequestID != "" {
+		fields["requestID"] = decision.RequestID
+	}
+	if decision.WorkerRequestID != "" {
+		fields["workerRequestID"] = decision.WorkerRequestID
+	}
+	if decision.User != "" {
+		fields["user"] = decision.User
+	}
+	if decision.Admin {
+		fields["admin"] = true
+	}
+	if len(decision.Policies) > 0 {
+		fields["policies"] = decision.Policies
+	}
+
+	DecisionLog.WithFields(fields).Info("Authorization decision")
+}
+
+// getResourceUrns returns URNs of a slice of resources
+func getResourceUrns(resources []Resource) []string {
+	urns := []string{}
+	for _, res := range resources {
+		urns = append(urns, res.GetUrn())
+	}
+	return urns
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/Sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogAuthorizationDecision(t *testing.T) {
+	testcases := map[string]struct {
+		decision   *AuthorizationDecision
+		sampleRate float64
+		sample     float64
+		// Expected result
+		expectedLogged bool
+	}{
+		"OKCaseAllowLogged": {
+			decision: &AuthorizationDecision{
+				Source:        DECISION_SOURCE_WORKER,
+				RequestID:     "123",
+				User:          "user123",
+				Action:        USER_ACTION_GET_USER,
+				Effect:        DECISION_ALLOW,
+				Resource:      CreateUrn("", RESOURCE_USER, "/path/", "user"),
+				RequestedUrns: []string{CreateUrn("", RESOURCE_USER, "/path/", "user")},
+				AllowedUrns:   []string{CreateUrn("", RESOURCE_USER, "/path/", "user")},
+				Policies:      []string{CreateUrn("example", RESOURCE_POLICY, "/path/", "policy")},
+				Latency:       2 * time.Millisecond,
+			},
+			sampleRate:     0.5,
+			sample:         0.2,
+			expectedLogged: true,
+		},
+		"OKCaseAllowNotSampled": {
+			decision: &AuthorizationDecision{
+				Source:        DECISION_SOURCE_WORKER,
+				RequestID:     "123",
+				User:          "user123",
+				Action:        USER_ACTION_GET_USER,
+				Effect:        DECISION_ALLOW,
+				Resource:      CreateUrn("", RESOURCE_USER, "/path/", "user"),
+				RequestedUrns: []string{CreateUrn("", RESOURCE_USER, "/path/", "user")},
+				AllowedUrns:   []string{CreateUrn("", RESOURCE_USER, "/path/", "user")},
+			},
+			sampleRate:     0.5,
+			sample:         0.7,
+			expectedLogged: false,
+		},
+		"OKCaseDenyAlwaysLogged": {
+			decision: &AuthorizationDecision{
+				Source:        DECISION_SOURCE_PROXY,
+				RequestID:     "123",
+				Action:        "example:get",
+				Effect:        DECISION_DENY,
+				Resource:      "urn:ews:example:instance1:resource/get",
+				RequestedUrns: []string{"urn:ews:example:instance1:resource/get"},
+				AllowedUrns:   []string{},
+			},
+			sampleRate:     0,
+			sample:         0.7,
+			expectedLogged: true,
+		},
+		"OKCasePartialAlwaysLogged": {
+			decision: &AuthorizationDecision{
+				Source:    DECISION_SOURCE_WORKER,
+				RequestID: "123",
+				User:      "user123",
+				Action:    USER_ACTION_LIST_USERS,
+				Effect:    DECISION_PARTIAL,
+				Resource:  GetUrnPrefix("", RESOURCE_USER, "/"),
+				RequestedUrns: []string{
+					CreateUrn("", RESOURCE_USER, "/path/", "user"),
+					CreateUrn("", RESOURCE_USER, "/path2/", "user"),
+				},
+				AllowedUrns: []string{CreateUrn("", RESOURCE_USER, "/path/", "user")},
+			},
+			sampleRate:     0,
+			sample:         0.7,
+			expectedLogged: true,
+		},
+	}
+
+	testLogger, hook := test.NewNullLogger()
+	DecisionLog = testLogger
+	for n, test := range testcases {
+		hook.Reset()
+		DecisionLogSampleRate = test.sampleRate
+		sample := test.sample
+		decisionSampler = func() float64 { return sample }
+
+		LogAuthorizationDecision(test.decision)
+		if !test.expectedLogged {
+			assert.Equal(t, 0, len(hook.Entries), "Error in test case %v", n)
+			continue
+		}
+		assert.Equal(t, 1, len(hook.Entries), "Error in test case %v", n)
+		entry := hook.LastEntry()
+		assert.Equal(t, logrus.InfoLevel, entry.Level, "Error in test case %v", n)
+		assert.Equal(t, test.decision.Source, entry.Data["source"], "Error in test case %v", n)
+		assert.Equal(t, test.decision.Effect, entry.Data["effect"], "Error in test case %v", n)
+		assert.Equal(t, test.decision.RequestID, entry.Data["requestID"], "Error in test case %v", n)
+		assert.Equal(t, test.decision.Action, entry.Data["action"], "Error in test case %v", n)
+		assert.Equal(t, test.decision.RequestedUrns, entry.Data["requestedUrns"], "Error in test case %v", n)
+		assert.Equal(t, test.decision.AllowedUrns, entry.Data["allowedUrns"], "Error in test case %v", n)
+		if len(test.decision.Policies) > 0 {
+			assert.Equal(t, test.decision.Policies, entry.Data["policies"], "Error in test case %v", n)
+		}
+		if test.decision.User != "" {
+			assert.Equal(t, test.decision.User, entry.Data["user"], "Error in test case %v", n)
+		}
+	}
+
+	DecisionLog = nil
+	DecisionLogSampleRate = 1.0
+}
+
+func TestGetDecisionEffect(t *testing.T) {
+	testcases := map[string]struct {
+		requested int
+		allowed   int
+		// Expected result
+		expectedEffect string
+	}{
+		"OKCaseAllow": {
+			requested:      2,
+			allowed:        2,
+			expectedEffect: DECISION_ALLOW,
+		},
+		"OKCaseAllowEmpty": {
+			requested:      0,
+			allowed:        0,
+			expectedEffect: DECISION_ALLOW,
+		},
+		"OKCasePartial": {
+			requested:      2,
+			allowed:        1,
+			expectedEffect: DECISION_PARTIAL,
+		},
+		"OKCaseDeny": {
+			requested:      2,
+			allowed:        0,
+			expectedEffect: DECISION_DENY,
+		},
+	}
+
+	for n, test := range testcases {
+		assert.Equal(t, test.expectedEffect, getDecisionEffect(test.requested, test.allowed), "Error in test case %v", n)
+	}
+}
@@ -8,15 +8,21 @@ const (
 	INVALID_PARAMETER_ERROR      = "InvalidParameterError"
 	UNAUTHORIZED_RESOURCES_ERROR = "UnauthorizedResourcesError"
 
-	// Authentication API error code
-	AUTHENTICATION_API_ERROR = "AuthenticationApiError"
+	// Authentication API error codes
+	AUTHENTICATION_API_ERROR   = "AuthenticationApiError"
+	ADMIN_AUTHENTICATION_ERROR = "AdminAuthenticationError"
+	ADMIN_LOCKED_OUT_ERROR     = "AdminLockedOutError"
 
 	// User API error codes
 	USER_BY_EXTERNAL_ID_NOT_FOUND = "UserWithExternalIDNotFound"
 	USER_ALREADY_EXIST            = "UserAlreadyExist"
 
+	// API key API error codes
+	API_KEY_BY_ID_NOT_FOUND = "ApiKeyWithIDNotFound"
+
 	// Group API error codes
 	GROUP_BY_ORG_AND_NAME_NOT_FOUND = "GroupWithOrgAndNameNotFound"
+	GROUP_BY_ID_NOT_FOUND           = "GroupWithIDNotFound"
 	GROUP_ALREADY_EXIST             = "GroupAlreadyExist"
 
 	// GroupMembers error codes
@@ -27,6 +33,9 @@ const (
 	POLICY_IS_ALREADY_ATTACHED_TO_GROUP = "PolicyIsAlreadyAttachedToGroup"
 	POLICY_IS_NOT_ATTACHED_TO_GROUP     = "PolicyIsNotAttachedToGroup"
 
+	// Bulk operations error codes
+	BULK_OPERATION_ABORTED = "BulkOperationAborted"
+
 	// Policy API error codes
 	POLICY_ALREADY_EXIST             = "PolicyAlreadyExist"
 	POLICY_BY_ORG_AND_NAME_NOT_FOUND = "PolicyWithOrgAndNameNotFound"
@@ -40,6 +49,10 @@ const (
 	AUTH_OIDC_PROVIDER_ALREADY_EXIST     = "AuthOidcProviderAlreadyExist"
 	AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND = "AuthOidcProviderWithNameNotFound"
 
+	// Webhook API error codes
+	WEBHOOK_ALREADY_EXIST     = "WebhookAlreadyExist"
+	WEBHOOK_BY_NAME_NOT_FOUND = "WebhookWithNameNotFound"
+
 	// Regex error
 	REGEX_NO_MATCH = "RegexNoMatch"
 )
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Event types
+	EVENT_USER_CREATED     = "user.created"
+	EVENT_USER_UPDATED     = "user.updated"
+	EVENT_USER_DELETED     = "user.deleted"
+	EVENT_USER_SUSPENDED   = "user.suspended"
+	EVENT_USER_REACTIVATED = "user.reactivated"
+
+	EVENT_GROUP_CREATED         = "group.created"
+	EVENT_GROUP_UPDATED         = "group.updated"
+	EVENT_GROUP_DELETED         = "group.deleted"
+	EVENT_GROUP_MEMBER_ADDED    = "group.member.added"
+	EVENT_GROUP_MEMBER_REMOVED  = "group.member.removed"
+	EVENT_GROUP_POLICY_ATTACHED = "group.policy.attached"
+	EVENT_GROUP_POLICY_DETACHED = "group.policy.detached"
+
+	EVENT_POLICY_CREATED = "policy.created"
+	EVENT_POLICY_UPDATED = "policy.updated"
+	EVENT_POLICY_DELETED = "policy.deleted"
+
+	EVENT_PROXY_RESOURCE_CREATED = "proxyresource.created"
+	EVENT_PROXY_RESOURCE_UPDATED = "proxyresource.updated"
+	EVENT_PROXY_RESOURCE_DELETED = "proxyresource.deleted"
+
+	// Wildcard to subscribe to all event types
+	EVENT_ALL = "*"
+)
+
+// EventTypes contains all event types emitted by the API
+var EventTypes = []string{
+	EVENT_USER_CREATED, EVENT_USER_UPDATED, EVENT_USER_DELETED, EVENT_USER_SUSPENDED, EVENT_USER_REACTIVATED,
+	EVENT_GROUP_CREATED, EVENT_GROUP_UPDATED, EVENT_GROUP_DELETED,
+	EVENT_GROUP_MEMBER_ADDED, EVENT_GROUP_MEMBER_REMOVED,
+	EVENT_GROUP_POLICY_ATTACHED, EVENT_GROUP_POLICY_DETACHED,
+	EVENT_POLICY_CREATED, EVENT_POLICY_UPDATED, EVENT_POLICY_DELETED,
+	EVENT_PROXY_RESOURCE_CREATED, EVENT_PROXY_RESOURCE_UPDATED, EVENT_PROXY_RESOURCE_DELETED,
+}
+
+// TYPE DEFINITIONS
+
+// Event describes a change in IAM resources
+type Event struct {
+	ID         string      `json:"id,omitempty"`
+	Type       string      `json:"type,omitempty"`
+	CreateAt   time.Time   `json:"createAt,omitempty"`
+	RequestID  string      `json:"requestId,omitempty"`
+	Identifier string      `json:"identifier,omitempty"`
+	Urn        string      `json:"urn,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// EventRelation is the data of membership and policy attachment events
+type EventRelation struct {
+	Group  *Group  `json:"group,omitempty"`
+	User   *User   `json:"user,omitempty"`
+	Policy *Policy `json:"policy,omitempty"`
+}
+
+// EventNotifier interface that receives events when resources change
+type EventNotifier interface {
+	// Notify an event. It mustn't block the caller.
+	Notify(event Event)
+}
+
+// IsValidEventType checks if event type exists or it is the wildcard
+func IsValidEventType(eventType string) bool {
+	if eventType == EVENT_ALL {
+		return true
+	}
+	for _, et := range EventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// PRIVATE HELPER METHODS
+
+// notifyEvent sends an event to notifier, if any
+func (api WorkerAPI) notifyEvent(requestInfo RequestInfo, eventType string, urn string, data interface{}) {
+	if api.Notifier == nil {
+		return
+	}
+	api.Notifier.Notify(Event{
+		ID:         uuid.NewV4().String(),
+		Type:       eventType,
+		CreateAt:   time.Now().UTC(),
+		RequestID:  requestInfo.RequestID,
+		Identifier: requestInfo.Identifier,
+		Urn:        urn,
+		Data:       data,
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNotifier struct {
+	events []Event
+}
+
+func (n *testNotifier) Notify(event Event) {
+	n.events = append(n.events, event)
+}
+
+func TestIsValidEventType(t *testing.T) {
+	testcases := map[string]struct {
+		eventType string
+		// Expected result
+		expectedResult bool
+	}{
+		"OKCase": {
+			eventType:      EVENT_GROUP_MEMBER_ADDED,
+			expectedResult: true,
+		},
+		"OKCaseWildcard": {
+			eventType:      EVENT_ALL,
+			expectedResult: true,
+		},
+		"ErrorCaseUnknownEvent": {
+			eventType:      "group.member.unknown",
+			expectedResult: false,
+		},
+		"ErrorCaseEmptyEvent": {
+			eventType:      "",
+			expectedResult: false,
+		},
+	}
+
+	for x, testcase := range testcases {
+		assert.Equal(t, testcase.expectedResult, IsValidEventType(testcase.eventType), "Error in test case %v", x)
+	}
+}
+
+func TestWorkerAPI_notifyEvent(t *testing.T) {
+	requestInfo := RequestInfo{
+		Identifier: "123456",
+		RequestID:  "request1",
+	}
+	data := &User{ID: "1", ExternalID: "user1"}
+
+	// Without notifier events are discarded
+	testAPI := makeTestAPI(makeTestRepo())
+	testAPI.notifyEvent(requestInfo, EVENT_USER_CREATED, "urn", data)
+
+	notifier := &testNotifier{}
+	testAPI.Notifier = notifier
+	testAPI.notifyEvent(requestInfo, EVENT_USER_CREATED, "urn", data)
+
+	assert.Len(t, notifier.events, 1)
+	event := notifier.events[0]
+	assert.NotEmpty(t, event.ID)
+	assert.False(t, event.CreateAt.IsZero())
+	assert.Equal(t, EVENT_USER_CREATED, event.Type)
+	assert.Equal(t, "request1", event.RequestID)
+	assert.Equal(t, "123456", event.Identifier)
+	assert.Equal(t, "urn", event.Urn)
+	assert.Equal(t, data, event.Data)
+}
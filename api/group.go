@@ -12,18 +12,19 @@ import (
 
 // Group domain
 type Group struct {
-	ID       string    `json:"id,omitempty"`
-	Name     string    `json:"name,omitempty"`
-	Path     string    `json:"path,omitempty"`
-	Org      string    `json:"org,omitempty"`
-	Urn      string    `json:"urn,omitempty"`
-	CreateAt time.Time `json:"createAt,omitempty"`
-	UpdateAt time.Time `json:"updateAt,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Org        string            `json:"org,omitempty"`
+	Urn        string            `json:"urn,omitempty"`
+	CreateAt   time.Time         `json:"createAt,omitempty"`
+	UpdateAt   time.Time         `json:"updateAt,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 func (g Group) String() string {
-	return fmt.Sprintf("[id: %v, name: %v, path: %v, org: %v, urn: %v, createAt: %v]",
-		g.ID, g.Name, g.Path, g.Org, g.Urn, g.CreateAt.Format("2006-01-02 15:04:05 MST"))
+	return fmt.Sprintf("[id: %v, name: %v, path: %v, org: %v, urn: %v, createAt: %v, attributes: %v]",
+		g.ID, g.Name, g.Path, g.Org, g.Urn, g.CreateAt.Format("2006-01-02 15:04:05 MST"), g.Attributes)
 }
 
 func (g Group) GetUrn() string {
@@ -46,9 +47,18 @@ type GroupPolicies struct {
 	CreateAt time.Time `json:"attached,omitempty"`
 }
 
+// Result of an item of a bulk operation, with the error that prevented applying it, if any
+type BulkResult struct {
+	ID    string `json:"id"`
+	Error *Error `json:"error,omitempty"`
+}
+
 // GROUP API IMPLEMENTATION
 
-func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string, path string) (*Group, error) {
+func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string, path string, attributes map[string]string) (*Group, error) {
+	api, span := api.startSpan(&requestInfo, "AddGroup")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -68,8 +78,11 @@ func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string,
 			Message: fmt.Sprintf("Invalid parameter: path %v", path),
 		}
 	}
+	if err := AreValidAttributes(attributes); err != nil {
+		return nil, err
+	}
 
-	group := createGroup(org, name, path)
+	group := createGroup(org, name, path, attributes)
 
 	// Check restrictions
 	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_CREATE_GROUP, []Group{group})
@@ -107,6 +120,7 @@ func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string,
 				}
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group created %+v", createdGroup))
+			api.notifyEvent(requestInfo, EVENT_GROUP_CREATED, createdGroup.Urn, createdGroup)
 			return createdGroup, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -124,6 +138,9 @@ func (api WorkerAPI) AddGroup(requestInfo RequestInfo, org string, name string,
 }
 
 func (api WorkerAPI) GetGroupByName(requestInfo RequestInfo, org string, name string) (*Group, error) {
+	api, span := api.startSpan(&requestInfo, "GetGroupByName")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -178,36 +195,63 @@ func (api WorkerAPI) GetGroupByName(requestInfo RequestInfo, org string, name st
 	}
 }
 
-func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, error) {
+func (api WorkerAPI) GetGroupByID(requestInfo RequestInfo, id string) (*Group, error) {
+	api, span := api.startSpan(&requestInfo, "GetGroupByID")
+	defer span.End()
+
 	// Validate fields
-	var total int
-	orderByValidColumns := api.GroupRepo.OrderByValidColumns(GROUP_ACTION_LIST_GROUPS)
-	err := validateFilter(filter, orderByValidColumns)
-	if err != nil {
-		return nil, total, err
+	if _, err := uuid.FromString(id); err != nil {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: id %v", id),
+		}
 	}
 
-	// Call repo to retrieve the groups
-	groups, total, err := api.GroupRepo.GetGroupsFiltered(filter)
+	// Call repo to retrieve the group
+	group, err := api.GroupRepo.GetGroupById(id)
 
 	// Error handling
 	if err != nil {
 		//Transform to DB error
 		dbError := err.(*database.Error)
-		return nil, total, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
+		// Group doesn't exist in DB
+		switch dbError.Code {
+		case database.GROUP_NOT_FOUND:
+			return nil, &Error{
+				Code:    GROUP_BY_ID_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		default: // Unexpected error
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
 		}
 	}
 
-	// Check restrictions to list
-	var urnPrefix string
-	if len(filter.Org) == 0 {
-		urnPrefix = "*"
-	} else {
-		urnPrefix = GetUrnPrefix(filter.Org, RESOURCE_GROUP, filter.PathPrefix)
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, GROUP_ACTION_GET_GROUP, []Group{*group})
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if we have our user authorized
+	if len(groupsFiltered) > 0 {
+		groupsFiltered := groupsFiltered[0]
+		return &groupsFiltered, nil
 	}
-	filteredGroups, err := api.GetAuthorizedGroups(requestInfo, urnPrefix, GROUP_ACTION_LIST_GROUPS, groups)
+	return nil, &Error{
+		Code: UNAUTHORIZED_RESOURCES_ERROR,
+		Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+			requestInfo.Identifier, group.Urn),
+	}
+}
+
+func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListGroups")
+	defer span.End()
+
+	filteredGroups, total, err := api.listGroups(requestInfo, filter, GROUP_ACTION_LIST_GROUPS)
 	if err != nil {
 		return nil, total, err
 	}
@@ -224,7 +268,19 @@ func (api WorkerAPI) ListGroups(requestInfo RequestInfo, filter *Filter) ([]Grou
 	return groupIDs, total, nil
 }
 
-func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name string, newName string, newPath string) (*Group, error) {
+func (api WorkerAPI) ListGroupsExpanded(requestInfo RequestInfo, filter *Filter) ([]Group, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListGroupsExpanded")
+	defer span.End()
+
+	// Full groups are only returned when they can be retrieved too
+	return api.listGroups(requestInfo, filter, GROUP_ACTION_LIST_GROUPS, GROUP_ACTION_GET_GROUP)
+}
+
+func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name string, newName string, newPath string,
+	newAttributes map[string]string) (*Group, error) {
+	api, span := api.startSpan(&requestInfo, "UpdateGroup")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(newName) {
 		return nil, &Error{
@@ -238,6 +294,9 @@ func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name strin
 			Message: fmt.Sprintf("Invalid parameter: new path %v", newPath),
 		}
 	}
+	if err := AreValidAttributes(newAttributes); err != nil {
+		return nil, err
+	}
 
 	// Call repo to retrieve the old group
 	oldGroup, err := api.GetGroupByName(requestInfo, org, name)
@@ -292,15 +351,21 @@ func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name strin
 		}
 	}
 
+	// Attributes are kept if new attributes aren't specified
+	if newAttributes == nil {
+		newAttributes = oldGroup.Attributes
+	}
+
 	// Update group
 	group := Group{
-		ID:       oldGroup.ID,
-		Name:     newName,
-		Path:     newPath,
-		Org:      oldGroup.Org,
-		Urn:      auxGroup.Urn,
-		CreateAt: oldGroup.CreateAt,
-		UpdateAt: time.Now().UTC(),
+		ID:         oldGroup.ID,
+		Name:       newName,
+		Path:       newPath,
+		Org:        oldGroup.Org,
+		Urn:        auxGroup.Urn,
+		CreateAt:   oldGroup.CreateAt,
+		UpdateAt:   time.Now().UTC(),
+		Attributes: newAttributes,
 	}
 
 	updatedGroup, err := api.GroupRepo.UpdateGroup(group)
@@ -316,11 +381,15 @@ func (api WorkerAPI) UpdateGroup(requestInfo RequestInfo, org string, name strin
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group updated from %+v to %+v", oldGroup, updatedGroup))
+	api.notifyEvent(requestInfo, EVENT_GROUP_UPDATED, updatedGroup.Urn, updatedGroup)
 	return updatedGroup, nil
 
 }
 
 func (api WorkerAPI) RemoveGroup(requestInfo RequestInfo, org string, name string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveGroup")
+	defer span.End()
+
 	// Call repo to retrieve the group
 	group, err := api.GetGroupByName(requestInfo, org, name)
 	if err != nil {
@@ -353,10 +422,14 @@ func (api WorkerAPI) RemoveGroup(requestInfo RequestInfo, org string, name strin
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Group deleted %v", group))
+	api.notifyEvent(requestInfo, EVENT_GROUP_DELETED, group.Urn, group)
 	return nil
 }
 
 func (api WorkerAPI) AddMember(requestInfo RequestInfo, externalId string, name string, org string) error {
+	api, span := api.startSpan(&requestInfo, "AddMember")
+	defer span.End()
+
 	// Call repo to retrieve the group
 	groupDB, err := api.GetGroupByName(requestInfo, org, name)
 	if err != nil {
@@ -414,10 +487,14 @@ func (api WorkerAPI) AddMember(requestInfo RequestInfo, externalId string, name
 		}
 	}
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v added to group %+v", userDB, groupDB))
+	api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_ADDED, groupDB.Urn, EventRelation{Group: groupDB, User: userDB})
 	return nil
 }
 
 func (api WorkerAPI) RemoveMember(requestInfo RequestInfo, externalId string, name string, org string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveMember")
+	defer span.End()
+
 	// Call repo to retrieve the group
 	groupDB, err := api.GetGroupByName(requestInfo, org, name)
 	if err != nil {
@@ -476,10 +553,14 @@ func (api WorkerAPI) RemoveMember(requestInfo RequestInfo, externalId string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v removed from group %+v", userDB, groupDB))
+	api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_REMOVED, groupDB.Urn, EventRelation{Group: groupDB, User: userDB})
 	return nil
 }
 
 func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListMembers")
+	defer span.End()
+
 	// Validate fields
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(GROUP_ACTION_LIST_MEMBERS)
@@ -535,6 +616,8 @@ func (api WorkerAPI) ListMembers(requestInfo RequestInfo, filter *Filter) ([]Gro
 }
 
 func (api WorkerAPI) AttachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
+	api, span := api.startSpan(&requestInfo, "AttachPolicyToGroup")
+	defer span.End()
 
 	// Check if group exists
 	group, err := api.GetGroupByName(requestInfo, org, name)
@@ -591,10 +674,13 @@ func (api WorkerAPI) AttachPolicyToGroup(requestInfo RequestInfo, org string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v attached to group %+v", policy, group))
+	api.notifyEvent(requestInfo, EVENT_GROUP_POLICY_ATTACHED, group.Urn, EventRelation{Group: group, Policy: policy})
 	return nil
 }
 
 func (api WorkerAPI) DetachPolicyToGroup(requestInfo RequestInfo, org string, name string, policyName string) error {
+	api, span := api.startSpan(&requestInfo, "DetachPolicyToGroup")
+	defer span.End()
 
 	// Check if group exists
 	group, err := api.GetGroupByName(requestInfo, org, name)
@@ -652,10 +738,272 @@ func (api WorkerAPI) DetachPolicyToGroup(requestInfo RequestInfo, org string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v detached from group %+v", policy, group))
+	api.notifyEvent(requestInfo, EVENT_GROUP_POLICY_DETACHED, group.Urn, EventRelation{Group: group, Policy: policy})
 	return nil
 }
 
+func (api WorkerAPI) AddMembers(requestInfo RequestInfo, externalIds []string, name string, org string, atomic bool) ([]BulkResult, error) {
+	api, span := api.startSpan(&requestInfo, "AddMembers")
+	defer span.End()
+
+	// Call repo to retrieve the group, checking restrictions once for all the users
+	groupDB, err := api.getGroupForBulk(requestInfo, org, name, GROUP_ACTION_ADD_MEMBER, len(externalIds))
+	if err != nil {
+		return nil, err
+	}
+
+	// Call repo to retrieve the users
+	results := newBulkResults(externalIds)
+	users, err := api.getUsersForBulk(requestInfo, results)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call repo to retrieve the GroupUserRelations
+	members, err := api.GroupRepo.FilterMembersOfGroup(getBulkUserIDs(users), groupDB.ID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	isMember := make(map[string]bool, len(members))
+	for _, id := range members {
+		isMember[id] = true
+	}
+	for i, user := range users {
+		if user != nil && isMember[user.ID] {
+			results[i].Error = &Error{
+				Code:    USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: fmt.Sprintf("User: %v is already a member of Group: %v", user.ExternalID, name),
+			}
+			users[i] = nil
+		}
+	}
+	if abortBulk(results, atomic) {
+		return results, nil
+	}
+
+	// Add members
+	userIDs := getBulkUserIDs(users)
+	if len(userIDs) > 0 {
+		if err := api.GroupRepo.AddMembers(userIDs, groupDB.ID); err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	}
+	for _, user := range users {
+		if user != nil {
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v added to group %+v", user, groupDB))
+			api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_ADDED, groupDB.Urn, EventRelation{Group: groupDB, User: user})
+		}
+	}
+	return results, nil
+}
+
+func (api WorkerAPI) RemoveMembers(requestInfo RequestInfo, externalIds []string, name string, org string, atomic bool) ([]BulkResult, error) {
+	api, span := api.startSpan(&requestInfo, "RemoveMembers")
+	defer span.End()
+
+	// Call repo to retrieve the group, checking restrictions once for all the users
+	groupDB, err := api.getGroupForBulk(requestInfo, org, name, GROUP_ACTION_REMOVE_MEMBER, len(externalIds))
+	if err != nil {
+		return nil, err
+	}
+
+	// Call repo to retrieve the users
+	results := newBulkResults(externalIds)
+	users, err := api.getUsersForBulk(requestInfo, results)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call repo to check if users are members of group
+	members, err := api.GroupRepo.FilterMembersOfGroup(getBulkUserIDs(users), groupDB.ID)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	isMember := make(map[string]bool, len(members))
+	for _, id := range members {
+		isMember[id] = true
+	}
+	for i, user := range users {
+		if user != nil && !isMember[user.ID] {
+			results[i].Error = &Error{
+				Code: USER_IS_NOT_A_MEMBER_OF_GROUP,
+				Message: fmt.Sprintf("User with externalId %v is not a member of group with org %v and name %v",
+					user.ExternalID, groupDB.Org, groupDB.Name),
+			}
+			users[i] = nil
+		}
+	}
+	if abortBulk(results, atomic) {
+		return results, nil
+	}
+
+	// Remove members
+	userIDs := getBulkUserIDs(users)
+	if len(userIDs) > 0 {
+		if err := api.GroupRepo.RemoveMembers(userIDs, groupDB.ID); err != nil {
+			//Transform to DB error
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	}
+	for _, user := range users {
+		if user != nil {
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Member %+v removed from group %+v", user, groupDB))
+			api.notifyEvent(requestInfo, EVENT_GROUP_MEMBER_REMOVED, groupDB.Urn, EventRelation{Group: groupDB, User: user})
+		}
+	}
+	return results, nil
+}
+
+func (api WorkerAPI) AttachPoliciesToGroup(requestInfo RequestInfo, org string, name string, policyNames []string, atomic bool) ([]BulkResult, error) {
+	api, span := api.startSpan(&requestInfo, "AttachPoliciesToGroup")
+	defer span.End()
+
+	// Check if group exists, checking restrictions once for all the policies
+	group, err := api.getGroupForBulk(requestInfo, org, name, GROUP_ACTION_ATTACH_GROUP_POLICY, len(policyNames))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if policies exist
+	results := newBulkResults(policyNames)
+	policies, err := api.getPoliciesForBulk(requestInfo, org, results)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check existing relationships
+	attached, err := api.GroupRepo.FilterAttachedToGroup(group.ID, getBulkPolicyIDs(policies))
+	if err != nil {
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	isAttached := make(map[string]bool, len(attached))
+	for _, id := range attached {
+		isAttached[id] = true
+	}
+	for i, policy := range policies {
+		if policy != nil && isAttached[policy.ID] {
+			results[i].Error = &Error{
+				Code:    POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+				Message: fmt.Sprintf("Policy: %v is already attached to Group: %v", policy.Name, group.Name),
+			}
+			policies[i] = nil
+		}
+	}
+	if abortBulk(results, atomic) {
+		return results, nil
+	}
+
+	// Attach Policies to Group
+	policyIDs := getBulkPolicyIDs(policies)
+	if len(policyIDs) > 0 {
+		if err := api.GroupRepo.AttachPolicies(group.ID, policyIDs); err != nil {
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	}
+	for _, policy := range policies {
+		if policy != nil {
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v attached to group %+v", policy, group))
+			api.notifyEvent(requestInfo, EVENT_GROUP_POLICY_ATTACHED, group.Urn, EventRelation{Group: group, Policy: policy})
+		}
+	}
+	return results, nil
+}
+
+func (api WorkerAPI) DetachPoliciesToGroup(requestInfo RequestInfo, org string, name string, policyNames []string, atomic bool) ([]BulkResult, error) {
+	api, span := api.startSpan(&requestInfo, "DetachPoliciesToGroup")
+	defer span.End()
+
+	// Check if group exists, checking restrictions once for all the policies
+	group, err := api.getGroupForBulk(requestInfo, org, name, GROUP_ACTION_DETACH_GROUP_POLICY, len(policyNames))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if policies exist
+	results := newBulkResults(policyNames)
+	policies, err := api.getPoliciesForBulk(requestInfo, org, results)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check existing relationships
+	attached, err := api.GroupRepo.FilterAttachedToGroup(group.ID, getBulkPolicyIDs(policies))
+	if err != nil {
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+	isAttached := make(map[string]bool, len(attached))
+	for _, id := range attached {
+		isAttached[id] = true
+	}
+	for i, policy := range policies {
+		if policy != nil && !isAttached[policy.ID] {
+			results[i].Error = &Error{
+				Code: POLICY_IS_NOT_ATTACHED_TO_GROUP,
+				Message: fmt.Sprintf("Policy with org %v and name %v is not attached to group with org %v and name %v",
+					policy.Org, policy.Name, group.Org, group.Name),
+			}
+			policies[i] = nil
+		}
+	}
+	if abortBulk(results, atomic) {
+		return results, nil
+	}
+
+	// Detach Policies to Group
+	policyIDs := getBulkPolicyIDs(policies)
+	if len(policyIDs) > 0 {
+		if err := api.GroupRepo.DetachPolicies(group.ID, policyIDs); err != nil {
+			dbError := err.(*database.Error)
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	}
+	for _, policy := range policies {
+		if policy != nil {
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy %+v detached from group %+v", policy, group))
+			api.notifyEvent(requestInfo, EVENT_GROUP_POLICY_DETACHED, group.Urn, EventRelation{Group: group, Policy: policy})
+		}
+	}
+	return results, nil
+}
+
 func (api WorkerAPI) ListAttachedGroupPolicies(requestInfo RequestInfo, filter *Filter) ([]GroupPolicies, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListAttachedGroupPolicies")
+	defer span.End()
+
 	// Validate fields
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(GROUP_ACTION_LIST_ATTACHED_GROUP_POLICIES)
@@ -712,16 +1060,319 @@ func (api WorkerAPI) ListAttachedGroupPolicies(requestInfo RequestInfo, filter *
 
 // PRIVATE HELPER METHODS
 
-func createGroup(org string, name string, path string) Group {
+// listGroups retrieves groups filtered, keeping the ones authorized for all the actions
+func (api WorkerAPI) listGroups(requestInfo RequestInfo, filter *Filter, actions ...string) ([]Group, int, error) {
+	// Validate fields
+	var total int
+	orderByValidColumns := api.GroupRepo.OrderByValidColumns(GROUP_ACTION_LIST_GROUPS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the groups
+	groups, total, err := api.GroupRepo.GetGroupsFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions to list
+	var urnPrefix string
+	if len(filter.Org) == 0 {
+		urnPrefix = "*"
+	} else {
+		urnPrefix = GetUrnPrefix(filter.Org, RESOURCE_GROUP, filter.PathPrefix)
+	}
+	for i, action := range actions {
+		groups, err = api.GetAuthorizedGroups(requestInfo, urnPrefix, action, groups)
+		if err != nil {
+			// Denying a further action leaves nothing to return, instead of failing the list
+			if apiError, ok := err.(*Error); i > 0 && ok && apiError.Code == UNAUTHORIZED_RESOURCES_ERROR {
+				return []Group{}, total, nil
+			}
+			return nil, total, err
+		}
+	}
+
+	return groups, total, nil
+}
+
+// Retrieve the group of a bulk operation with the number of items, if the authenticated user is allowed to do the action
+func (api WorkerAPI) getGroupForBulk(requestInfo RequestInfo, org string, name string, action string, items int) (*Group, error) {
+	if items > MAX_BULK_SIZE {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: %v items, max items allowed: %v", items, MAX_BULK_SIZE),
+		}
+	}
+
+	// Call repo to retrieve the group
+	group, err := api.GetGroupByName(requestInfo, org, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	groupsFiltered, err := api.GetAuthorizedGroups(requestInfo, group.Urn, action, []Group{*group})
+	if err != nil {
+		return nil, err
+	}
+	if len(groupsFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, group.Urn),
+		}
+	}
+
+	return group, nil
+}
+
+// Retrieve the users of a bulk operation in a single call, like GetUserByExternalID does for each of them. The users
+// returned match the results, and they are nil when the result has an error
+func (api WorkerAPI) getUsersForBulk(requestInfo RequestInfo, results []BulkResult) ([]*User, error) {
+	users := make([]*User, len(results))
+
+	// Validate fields, external identifiers can't be repeated
+	externalIds := []string{}
+	requested := make(map[string]bool, len(results))
+	for i, result := range results {
+		if !IsValidUserExternalID(result.ID) {
+			results[i].Error = &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: externalId %v", result.ID),
+			}
+			continue
+		}
+		if requested[result.ID] {
+			results[i].Error = &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: externalId %v, repeated in request", result.ID),
+			}
+			continue
+		}
+		requested[result.ID] = true
+		externalIds = append(externalIds, result.ID)
+	}
+	if len(externalIds) == 0 {
+		return users, nil
+	}
+
+	// Retrieve users from DB
+	usersDB, err := api.UserRepo.GetUsersByExternalIDs(externalIds)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions, none of the users is allowed when the user can't get any of them
+	filteredUsers, err := api.GetAuthorizedUsers(requestInfo, GetUrnPrefix("", RESOURCE_USER, "/"), USER_ACTION_GET_USER, usersDB)
+	if err != nil {
+		if apiError, ok := err.(*Error); !ok || apiError.Code != UNAUTHORIZED_RESOURCES_ERROR {
+			return nil, err
+		}
+	}
+	found := make(map[string]User, len(usersDB))
+	for _, user := range usersDB {
+		found[user.ExternalID] = user
+	}
+	allowed := make(map[string]bool, len(filteredUsers))
+	for _, user := range filteredUsers {
+		allowed[user.ExternalID] = true
+	}
+
+	for i, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		user, ok := found[result.ID]
+		switch {
+		case !ok:
+			results[i].Error = &Error{
+				Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: fmt.Sprintf("User with externalId %v not found", result.ID),
+			}
+		case !allowed[result.ID]:
+			results[i].Error = &Error{
+				Code: UNAUTHORIZED_RESOURCES_ERROR,
+				Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+					requestInfo.Identifier, user.Urn),
+			}
+		default:
+			users[i] = &user
+		}
+	}
+
+	return users, nil
+}
+
+// Retrieve the policies of a bulk operation in a single call, like GetPolicyByName does for each of them. The policies
+// returned match the results, and they are nil when the result has an error
+func (api WorkerAPI) getPoliciesForBulk(requestInfo RequestInfo, org string, results []BulkResult) ([]*Policy, error) {
+	policies := make([]*Policy, len(results))
+
+	// Validate fields, names can't be repeated
+	if !IsValidOrg(org) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: org %v", org),
+		}
+	}
+	names := []string{}
+	requested := make(map[string]bool, len(results))
+	for i, result := range results {
+		if !IsValidName(result.ID) {
+			results[i].Error = &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: name %v", result.ID),
+			}
+			continue
+		}
+		if requested[result.ID] {
+			results[i].Error = &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: name %v, repeated in request", result.ID),
+			}
+			continue
+		}
+		requested[result.ID] = true
+		names = append(names, result.ID)
+	}
+	if len(names) == 0 {
+		return policies, nil
+	}
+
+	// Call repo to retrieve the policies
+	policiesDB, err := api.PolicyRepo.GetPoliciesByNames(org, names)
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions, none of the policies is allowed when the user can't get any of them
+	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, GetUrnPrefix(org, RESOURCE_POLICY, "/"), POLICY_ACTION_GET_POLICY, policiesDB)
+	if err != nil {
+		if apiError, ok := err.(*Error); !ok || apiError.Code != UNAUTHORIZED_RESOURCES_ERROR {
+			return nil, err
+		}
+	}
+	found := make(map[string]Policy, len(policiesDB))
+	for _, policy := range policiesDB {
+		found[policy.Name] = policy
+	}
+	allowed := make(map[string]bool, len(policiesFiltered))
+	for _, policy := range policiesFiltered {
+		allowed[policy.Name] = true
+	}
+
+	for i, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		policy, ok := found[result.ID]
+		switch {
+		case !ok:
+			results[i].Error = &Error{
+				Code:    POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: fmt.Sprintf("Policy with organization %v and name %v not found", org, result.ID),
+			}
+		case !allowed[result.ID]:
+			results[i].Error = &Error{
+				Code: UNAUTHORIZED_RESOURCES_ERROR,
+				Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+					requestInfo.Identifier, policy.Urn),
+			}
+		default:
+			policies[i] = &policy
+		}
+	}
+
+	return policies, nil
+}
+
+// Create the results of a bulk operation, one for each item identifier
+func newBulkResults(ids []string) []BulkResult {
+	results := make([]BulkResult, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+	}
+	return results
+}
+
+// Abort an atomic bulk operation if any item failed, setting an error in the rest of the results.
+// It returns true when the operation is aborted
+func abortBulk(results []BulkResult, atomic bool) bool {
+	if !atomic {
+		return false
+	}
+	failed := false
+	for _, result := range results {
+		if result.Error != nil {
+			failed = true
+			break
+		}
+	}
+	if !failed {
+		return false
+	}
+
+	for i, result := range results {
+		if result.Error == nil {
+			results[i].Error = &Error{
+				Code:    BULK_OPERATION_ABORTED,
+				Message: fmt.Sprintf("Item %v not applied, another item of the operation failed", result.ID),
+			}
+		}
+	}
+	return true
+}
+
+func getBulkUserIDs(users []*User) []string {
+	ids := []string{}
+	for _, user := range users {
+		if user != nil {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids
+}
+
+func getBulkPolicyIDs(policies []*Policy) []string {
+	ids := []string{}
+	for _, policy := range policies {
+		if policy != nil {
+			ids = append(ids, policy.ID)
+		}
+	}
+	return ids
+}
+
+func createGroup(org string, name string, path string, attributes map[string]string) Group {
 	urn := CreateUrn(org, RESOURCE_GROUP, path, name)
 	group := Group{
-		ID:       uuid.NewV4().String(),
-		Name:     name,
-		Path:     path,
-		CreateAt: time.Now().UTC(),
-		UpdateAt: time.Now().UTC(),
-		Urn:      urn,
-		Org:      org,
+		ID:         uuid.NewV4().String(),
+		Name:       name,
+		Path:       path,
+		CreateAt:   time.Now().UTC(),
+		UpdateAt:   time.Now().UTC(),
+		Urn:        urn,
+		Org:        org,
+		Attributes: attributes,
 	}
 
 	return group
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Tecsisa/foulkon/database"
@@ -14,6 +16,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 		name        string
 		org         string
 		path        string
+		attributes  map[string]string
 		// Expected results
 		expectedGroup *Group
 		wantError     error
@@ -64,6 +67,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -100,6 +104,18 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				Code: database.GROUP_NOT_FOUND,
 			},
 		},
+		"ErrorCaseInvalidAttributes": {
+			name: "group1",
+			org:  "org1",
+			path: "/example/",
+			attributes: map[string]string{
+				"": "backend",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: attribute key ",
+			},
+		},
 		"ErrorCaseInvalidName": {
 			name: "*%~#@|",
 			org:  "org1",
@@ -143,6 +159,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/path/",
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNotAuthenticatedUser": {
@@ -178,6 +195,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -240,6 +258,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -314,7 +333,7 @@ func TestAuthAPI_AddGroup(t *testing.T) {
 		testRepo.ArgsOut[AddGroupMethod][0] = testcase.expectedGroup
 		testRepo.ArgsOut[AddGroupMethod][1] = testcase.addGroupMethodErr
 
-		group, err := testAPI.AddGroup(testcase.requestInfo, testcase.org, testcase.name, testcase.path)
+		group, err := testAPI.AddGroup(testcase.requestInfo, testcase.org, testcase.name, testcase.path, testcase.attributes)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroup, group)
 	}
 }
@@ -376,6 +395,7 @@ func TestAuthAPI_GetGroupByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -496,6 +516,7 @@ func TestAuthAPI_GetGroupByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -568,6 +589,7 @@ func TestAuthAPI_GetGroupByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -616,6 +638,167 @@ func TestAuthAPI_GetGroupByName(t *testing.T) {
 	}
 }
 
+func TestAuthAPI_GetGroupByID(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		id          string
+		// Expected result
+		expectedGroup *Group
+		wantError     error
+		// Manager Results
+		getUserByExternalIDResult *User
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		getGroupByIdMethodResult  *Group
+		// Manager Errors
+		getGroupByIdMethodErr error
+	}{
+		"OKCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			id: "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+			expectedGroup: &Group{
+				ID:   "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+			},
+			getGroupByIdMethodResult: &Group{
+				ID:   "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/example/",
+			},
+		},
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			id: "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+			expectedGroup: &Group{
+				ID:   "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/test/asd/", "group1"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									GROUP_ACTION_GET_GROUP,
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_GROUP, "/test/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			getGroupByIdMethodResult: &Group{
+				ID:   "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/test/asd/", "group1"),
+			},
+		},
+		"ErrorCaseInvalidID": {
+			id: "group1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: id group1",
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			id: "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+			wantError: &Error{
+				Code: GROUP_BY_ID_NOT_FOUND,
+			},
+			getGroupByIdMethodErr: &database.Error{
+				Code: database.GROUP_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetGroupDBErr": {
+			id: "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getGroupByIdMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			id: "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:org1:group/test/asd/group1",
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "123456",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
+			},
+			getGroupByIdMethodResult: &Group{
+				ID:   "4a4b1b7e-5b2f-4c5d-8a3e-0e6c2b1f9d10",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/test/asd/", "group1"),
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByIdMethod][0] = testcase.getGroupByIdMethodResult
+		testRepo.ArgsOut[GetGroupByIdMethod][1] = testcase.getGroupByIdMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+
+		group, err := testAPI.GetGroupByID(testcase.requestInfo, testcase.id)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroup, group)
+	}
+}
+
 func TestAuthAPI_ListGroups(t *testing.T) {
 	testcases := map[string]struct {
 		// API Method args
@@ -756,6 +939,7 @@ func TestAuthAPI_ListGroups(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseMaxLimitSize": {
@@ -901,6 +1085,7 @@ func TestAuthAPI_ListGroups(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissions": {
@@ -956,6 +1141,7 @@ func TestAuthAPI_ListGroups(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 	}
@@ -981,14 +1167,16 @@ func TestAuthAPI_ListGroups(t *testing.T) {
 func TestAuthAPI_UpdateGroup(t *testing.T) {
 	testcases := map[string]struct {
 		// API Method args
-		requestInfo  RequestInfo
-		org          string
-		groupName    string
-		newGroupName string
-		newPath      string
+		requestInfo   RequestInfo
+		org           string
+		groupName     string
+		newGroupName  string
+		newPath       string
+		newAttributes map[string]string
 		// Expected result
-		expectedGroup *Group
-		wantError     error
+		expectedGroup      *Group
+		expectedAttributes map[string]string
+		wantError          error
 		// Manager Results
 		getGroupByNameResult            *Group
 		getGroupMembersResult           []User
@@ -1033,6 +1221,62 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				Urn:  CreateUrn("123", RESOURCE_GROUP, "/new/", "test"),
 			},
 		},
+		"OKCaseAdminKeepAttributes": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:          "123",
+			groupName:    "group1",
+			newGroupName: "newName",
+			newPath:      "/new/",
+			expectedGroup: &Group{
+				ID:   "12345",
+				Name: "newName",
+				Org:  "123",
+				Path: "/new/",
+				Urn:  CreateUrn("123", RESOURCE_GROUP, "/new/", "test"),
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+			},
+			expectedAttributes: map[string]string{
+				"department": "backend",
+			},
+			getGroupByNameResult: &Group{
+				ID:   "12345",
+				Name: "group1",
+				Org:  "123",
+				Path: "/path/",
+				Urn:  CreateUrn("123", RESOURCE_GROUP, "/path/", "test"),
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+			},
+			updateGroupResult: &Group{
+				ID:   "12345",
+				Name: "newName",
+				Org:  "123",
+				Path: "/new/",
+				Urn:  CreateUrn("123", RESOURCE_GROUP, "/new/", "test"),
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+			},
+		},
+		"ErrorCaseInvalidAttributes": {
+			org:          "123",
+			groupName:    "group1",
+			newGroupName: "newName",
+			newPath:      "/new/",
+			newAttributes: map[string]string{
+				"department": strings.Repeat("a", MAX_ATTRIBUTE_LENGTH+1),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: attribute department value, max length allowed: %v", MAX_ATTRIBUTE_LENGTH),
+			},
+		},
 		"OKCase": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
@@ -1101,6 +1345,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseInvalidName": {
@@ -1283,6 +1528,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyUpdateGroup": {
@@ -1352,6 +1598,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissionsToUpdateTarget": {
@@ -1419,6 +1666,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyToUpdateTarget": {
@@ -1495,6 +1743,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermission": {
@@ -1544,6 +1793,7 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUpdateGroupDBErr": {
@@ -1585,8 +1835,12 @@ func TestAuthAPI_UpdateGroup(t *testing.T) {
 		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 
-		group, err := testAPI.UpdateGroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.newGroupName, testcase.newPath)
+		group, err := testAPI.UpdateGroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.newGroupName, testcase.newPath,
+			testcase.newAttributes)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedGroup, group)
+		if testcase.expectedAttributes != nil {
+			assert.Equal(t, testcase.expectedAttributes, testRepo.ArgsIn[UpdateGroupMethod][0].(Group).Attributes, "Error in test case %v", x)
+		}
 	}
 }
 
@@ -1627,6 +1881,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 		},
 		"OkCase": {
@@ -1648,6 +1903,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
@@ -1755,6 +2011,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1813,6 +2070,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1881,6 +2139,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1927,6 +2186,7 @@ func TestAuthAPI_RemoveGroup(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			removeGroupMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -1985,6 +2245,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "12345",
 				Path:       "/test/asd/",
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "543210",
@@ -2041,6 +2302,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "GROUP-USER-ID",
@@ -2172,6 +2434,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "GROUP-USER-ID",
@@ -2241,6 +2504,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "GROUP-USER-ID",
@@ -2290,6 +2554,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "GROUP-USER-ID",
@@ -2336,6 +2601,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "12345",
 				Path:       "/test/asd/",
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "543210",
@@ -2360,6 +2626,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "12345",
 				Path:       "/test/asd/",
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "543210",
@@ -2386,6 +2653,7 @@ func TestAuthAPI_AddMember(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "12345",
 				Path:       "/test/asd/",
+				Enabled:    true,
 			},
 			getGroupByNameResult: &Group{
 				ID:   "543210",
@@ -2458,6 +2726,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ID:         "123456",
 				ExternalID: "12345",
 				Path:       "/test/",
+				Enabled:    true,
 			},
 			isMemberOfGroupResult: true,
 		},
@@ -2523,6 +2792,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isMemberOfGroupResult: true,
 		},
@@ -2653,6 +2923,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUnauthorizedGetUser": {
@@ -2721,6 +2992,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyRemoveMember": {
@@ -2789,6 +3061,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissions": {
@@ -2837,6 +3110,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUserNotFound": {
@@ -2884,6 +3158,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isMemberOfGroupMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -2913,6 +3188,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isMemberOfGroupResult: false,
 		},
@@ -2939,6 +3215,7 @@ func TestAuthAPI_RemoveMember(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isMemberOfGroupResult: true,
 			removeMemberMethodErr: &database.Error{
@@ -3017,6 +3294,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 						ID:         "12345",
 						ExternalID: "member1",
 						Path:       "/test/",
+						Enabled:    true,
 					},
 				},
 				{
@@ -3024,6 +3302,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 						ID:         "123456",
 						ExternalID: "member2",
 						Path:       "/test/",
+						Enabled:    true,
 					},
 				},
 			},
@@ -3059,6 +3338,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 						ID:         "12345",
 						ExternalID: "member1",
 						Path:       "/test/",
+						Enabled:    true,
 					},
 				},
 				{
@@ -3066,6 +3346,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 						ID:         "123456",
 						ExternalID: "member2",
 						Path:       "/test/",
+						Enabled:    true,
 					},
 				},
 			},
@@ -3107,6 +3388,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseMaxLimitSize": {
@@ -3236,6 +3518,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyListMembers": {
@@ -3305,6 +3588,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissions": {
@@ -3354,6 +3638,7 @@ func TestAuthAPI_ListMembers(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseListMembersDBErr": {
@@ -3537,6 +3822,7 @@ func TestAuthAPI_AttachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isAttachedToGroupResult: false,
 		},
@@ -3673,6 +3959,7 @@ func TestAuthAPI_AttachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyToAttach": {
@@ -3741,6 +4028,7 @@ func TestAuthAPI_AttachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissions": {
@@ -3789,6 +4077,7 @@ func TestAuthAPI_AttachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseIsAttachedDBErr": {
@@ -4093,6 +4382,7 @@ func TestAuthAPI_DetachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			isAttachedToGroupResult: true,
 		},
@@ -4221,6 +4511,7 @@ func TestAuthAPI_DetachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyToDetach": {
@@ -4289,6 +4580,7 @@ func TestAuthAPI_DetachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissions": {
@@ -4337,6 +4629,7 @@ func TestAuthAPI_DetachPolicyToGroup(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCasePolicyNotFound": {
@@ -4504,6 +4797,876 @@ func TestAuthAPI_DetachPolicyToGroup(t *testing.T) {
 	}
 }
 
+func TestAuthAPI_AddMembers(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		userIDs     []string
+		org         string
+		groupName   string
+		atomic      bool
+		// Expected result
+		expectedResponse []BulkResult
+		wantError        error
+		// Manager Results
+		getGroupsByUserIDResult     []TestUserGroupRelation
+		getAttachedPoliciesResult   []TestPolicyGroupRelation
+		getUserByExternalIDResult   *User
+		getGroupByNameResult        *Group
+		getUsersByExternalIDsResult []User
+		filterMembersOfGroupResult  []string
+		// Manager Errors
+		getGroupByNameMethodErr        error
+		getUsersByExternalIDsMethodErr error
+		filterMembersOfGroupMethodErr  error
+		addMembersMethodErr            error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345", "23456"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+				{
+					ID:         "USER-2",
+					ExternalID: "23456",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			expectedResponse: []BulkResult{
+				{ID: "12345"},
+				{ID: "23456"},
+			},
+		},
+		"OkCaseItemErrors": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345", "23456", "34567", "12345", "d*%$"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+				{
+					ID:         "USER-2",
+					ExternalID: "23456",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			filterMembersOfGroupResult: []string{"USER-2"},
+			expectedResponse: []BulkResult{
+				{ID: "12345"},
+				{
+					ID: "23456",
+					Error: &Error{
+						Code:    USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+						Message: "User: 23456 is already a member of Group: group1",
+					},
+				},
+				{
+					ID: "34567",
+					Error: &Error{
+						Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+						Message: "User with externalId 34567 not found",
+					},
+				},
+				{
+					ID: "12345",
+					Error: &Error{
+						Code:    INVALID_PARAMETER_ERROR,
+						Message: "Invalid parameter: externalId 12345, repeated in request",
+					},
+				},
+				{
+					ID: "d*%$",
+					Error: &Error{
+						Code:    INVALID_PARAMETER_ERROR,
+						Message: "Invalid parameter: externalId d*%$",
+					},
+				},
+			},
+		},
+		"OkCaseAtomicAborted": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345", "34567"},
+			org:       "org1",
+			groupName: "group1",
+			atomic:    true,
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			// The repo must not be called
+			addMembersMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			expectedResponse: []BulkResult{
+				{
+					ID: "12345",
+					Error: &Error{
+						Code:    BULK_OPERATION_ABORTED,
+						Message: "Item 12345 not applied, another item of the operation failed",
+					},
+				},
+				{
+					ID: "34567",
+					Error: &Error{
+						Code:    USER_BY_EXTERNAL_ID_NOT_FOUND,
+						Message: "User with externalId 34567 not found",
+					},
+				},
+			},
+		},
+		"OkCaseUnauthorizedUser": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			userIDs:   []string{"12345", "23456"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "org1",
+						Path: "/path/",
+						Urn:  CreateUrn("org1", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									"iam:*",
+								},
+								Resources: []string{
+									GetUrnPrefix("org1", RESOURCE_GROUP, ""),
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
+			},
+			getGroupByNameResult: &Group{
+				ID:   "GROUP-USER-ID",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/path/",
+				Urn:  CreateUrn("org1", RESOURCE_GROUP, "/path/", "group1"),
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/path/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/path/", "12345"),
+					Enabled:    true,
+				},
+				{
+					ID:         "USER-2",
+					ExternalID: "23456",
+					Path:       "/other/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/other/", "23456"),
+					Enabled:    true,
+				},
+			},
+			expectedResponse: []BulkResult{
+				{ID: "12345"},
+				{
+					ID: "23456",
+					Error: &Error{
+						Code: UNAUTHORIZED_RESOURCES_ERROR,
+						Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+							"123456", CreateUrn("", RESOURCE_USER, "/other/", "23456")),
+					},
+				},
+			},
+		},
+		"ErrorCaseMaxItems": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   make([]string, MAX_BULK_SIZE+1),
+			org:       "org1",
+			groupName: "group1",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: %v items, max items allowed: %v", MAX_BULK_SIZE+1, MAX_BULK_SIZE),
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameMethodErr: &database.Error{
+				Code:    database.GROUP_NOT_FOUND,
+				Message: "Group not found",
+			},
+			wantError: &Error{
+				Code:    GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseGetUsersByExternalIDsDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseFilterMembersOfGroupDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			filterMembersOfGroupMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseAddMembersDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			addMembersMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetGroupByNameMethod][1] = testcase.getGroupByNameMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[GetUsersByExternalIDsMethod][0] = testcase.getUsersByExternalIDsResult
+		testRepo.ArgsOut[GetUsersByExternalIDsMethod][1] = testcase.getUsersByExternalIDsMethodErr
+		testRepo.ArgsOut[FilterMembersOfGroupMethod][0] = testcase.filterMembersOfGroupResult
+		testRepo.ArgsOut[FilterMembersOfGroupMethod][1] = testcase.filterMembersOfGroupMethodErr
+		testRepo.ArgsOut[AddMembersMethod][0] = testcase.addMembersMethodErr
+
+		results, err := testAPI.AddMembers(testcase.requestInfo, testcase.userIDs, testcase.groupName, testcase.org, testcase.atomic)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, results)
+	}
+}
+
+func TestAuthAPI_RemoveMembers(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		userIDs     []string
+		org         string
+		groupName   string
+		atomic      bool
+		// Expected result
+		expectedResponse []BulkResult
+		wantError        error
+		// Manager Results
+		getGroupByNameResult        *Group
+		getUsersByExternalIDsResult []User
+		filterMembersOfGroupResult  []string
+		// Manager Errors
+		removeMembersMethodErr error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345", "23456"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+				{
+					ID:         "USER-2",
+					ExternalID: "23456",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			filterMembersOfGroupResult: []string{"USER-1", "USER-2"},
+			expectedResponse: []BulkResult{
+				{ID: "12345"},
+				{ID: "23456"},
+			},
+		},
+		"OkCaseAtomicAborted": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345", "23456"},
+			org:       "org1",
+			groupName: "group1",
+			atomic:    true,
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+				{
+					ID:         "USER-2",
+					ExternalID: "23456",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			filterMembersOfGroupResult: []string{"USER-1"},
+			expectedResponse: []BulkResult{
+				{
+					ID: "12345",
+					Error: &Error{
+						Code:    BULK_OPERATION_ABORTED,
+						Message: "Item 12345 not applied, another item of the operation failed",
+					},
+				},
+				{
+					ID: "23456",
+					Error: &Error{
+						Code:    USER_IS_NOT_A_MEMBER_OF_GROUP,
+						Message: "User with externalId 23456 is not a member of group with org org1 and name group1",
+					},
+				},
+			},
+		},
+		"ErrorCaseRemoveMembersDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			userIDs:   []string{"12345"},
+			org:       "org1",
+			groupName: "group1",
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getUsersByExternalIDsResult: []User{
+				{
+					ID:         "USER-1",
+					ExternalID: "12345",
+					Path:       "/test/asd/",
+					Enabled:    true,
+				},
+			},
+			filterMembersOfGroupResult: []string{"USER-1"},
+			removeMembersMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetUsersByExternalIDsMethod][0] = testcase.getUsersByExternalIDsResult
+		testRepo.ArgsOut[FilterMembersOfGroupMethod][0] = testcase.filterMembersOfGroupResult
+		testRepo.ArgsOut[RemoveMembersMethod][0] = testcase.removeMembersMethodErr
+
+		results, err := testAPI.RemoveMembers(testcase.requestInfo, testcase.userIDs, testcase.groupName, testcase.org, testcase.atomic)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, results)
+	}
+}
+
+func TestAuthAPI_AttachPoliciesToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		org         string
+		groupName   string
+		policyNames []string
+		atomic      bool
+		// Expected result
+		expectedResponse []BulkResult
+		wantError        error
+		// Manager Results
+		getGroupByNameResult        *Group
+		getPoliciesByNamesResult    []Policy
+		filterAttachedToGroupResult []string
+		// Manager Errors
+		getPoliciesByNamesMethodErr error
+		attachPoliciesMethodErr     error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1", "policy2"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+				{
+					ID:   "POLICY-2",
+					Name: "policy2",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			expectedResponse: []BulkResult{
+				{ID: "policy1"},
+				{ID: "policy2"},
+			},
+		},
+		"OkCaseItemErrors": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1", "policy2", "policy3", "*%~#@|"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+				{
+					ID:   "POLICY-2",
+					Name: "policy2",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			filterAttachedToGroupResult: []string{"POLICY-2"},
+			expectedResponse: []BulkResult{
+				{ID: "policy1"},
+				{
+					ID: "policy2",
+					Error: &Error{
+						Code:    POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+						Message: "Policy: policy2 is already attached to Group: group1",
+					},
+				},
+				{
+					ID: "policy3",
+					Error: &Error{
+						Code:    POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+						Message: "Policy with organization org1 and name policy3 not found",
+					},
+				},
+				{
+					ID: "*%~#@|",
+					Error: &Error{
+						Code:    INVALID_PARAMETER_ERROR,
+						Message: "Invalid parameter: name *%~#@|",
+					},
+				},
+			},
+		},
+		"OkCaseAtomicAborted": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1", "policy3"},
+			atomic:      true,
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			// The repo must not be called
+			attachPoliciesMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			expectedResponse: []BulkResult{
+				{
+					ID: "policy1",
+					Error: &Error{
+						Code:    BULK_OPERATION_ABORTED,
+						Message: "Item policy1 not applied, another item of the operation failed",
+					},
+				},
+				{
+					ID: "policy3",
+					Error: &Error{
+						Code:    POLICY_BY_ORG_AND_NAME_NOT_FOUND,
+						Message: "Policy with organization org1 and name policy3 not found",
+					},
+				},
+			},
+		},
+		"ErrorCaseMaxItems": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: make([]string, MAX_BULK_SIZE+1),
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: %v items, max items allowed: %v", MAX_BULK_SIZE+1, MAX_BULK_SIZE),
+			},
+		},
+		"ErrorCaseGetPoliciesByNamesDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseAttachPoliciesDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			attachPoliciesMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetPoliciesByNamesMethod][0] = testcase.getPoliciesByNamesResult
+		testRepo.ArgsOut[GetPoliciesByNamesMethod][1] = testcase.getPoliciesByNamesMethodErr
+		testRepo.ArgsOut[FilterAttachedToGroupMethod][0] = testcase.filterAttachedToGroupResult
+		testRepo.ArgsOut[AttachPoliciesMethod][0] = testcase.attachPoliciesMethodErr
+
+		results, err := testAPI.AttachPoliciesToGroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.policyNames, testcase.atomic)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, results)
+	}
+}
+
+func TestAuthAPI_DetachPoliciesToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		org         string
+		groupName   string
+		policyNames []string
+		atomic      bool
+		// Expected result
+		expectedResponse []BulkResult
+		wantError        error
+		// Manager Results
+		getGroupByNameResult        *Group
+		getPoliciesByNamesResult    []Policy
+		filterAttachedToGroupResult []string
+		// Manager Errors
+		detachPoliciesMethodErr error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1", "policy2"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+				{
+					ID:   "POLICY-2",
+					Name: "policy2",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			filterAttachedToGroupResult: []string{"POLICY-1"},
+			expectedResponse: []BulkResult{
+				{ID: "policy1"},
+				{
+					ID: "policy2",
+					Error: &Error{
+						Code:    POLICY_IS_NOT_ATTACHED_TO_GROUP,
+						Message: "Policy with org org1 and name policy2 is not attached to group with org org1 and name group1",
+					},
+				},
+			},
+		},
+		"ErrorCaseDetachPoliciesDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			org:         "org1",
+			groupName:   "group1",
+			policyNames: []string{"policy1"},
+			getGroupByNameResult: &Group{
+				ID:   "543210",
+				Name: "group1",
+				Org:  "org1",
+				Path: "/test/asd/",
+			},
+			getPoliciesByNamesResult: []Policy{
+				{
+					ID:   "POLICY-1",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "/test/asd/",
+				},
+			},
+			filterAttachedToGroupResult: []string{"POLICY-1"},
+			detachPoliciesMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetGroupByNameMethod][0] = testcase.getGroupByNameResult
+		testRepo.ArgsOut[GetPoliciesByNamesMethod][0] = testcase.getPoliciesByNamesResult
+		testRepo.ArgsOut[FilterAttachedToGroupMethod][0] = testcase.filterAttachedToGroupResult
+		testRepo.ArgsOut[DetachPoliciesMethod][0] = testcase.detachPoliciesMethodErr
+
+		results, err := testAPI.DetachPoliciesToGroup(testcase.requestInfo, testcase.org, testcase.groupName, testcase.policyNames, testcase.atomic)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResponse, results)
+	}
+}
+
 func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 	testcases := map[string]struct {
 		//API method args
@@ -4544,6 +5707,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			expectedPolicies: []GroupPolicies{},
 		},
@@ -4568,6 +5732,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
@@ -4699,6 +5864,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -4759,6 +5925,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -4829,6 +5996,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -4879,6 +6047,7 @@ func TestAuthAPI_ListAttachedGroupPolicies(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org1", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -27,9 +27,13 @@ func LogOperationError(requestID string, userID string, err *Error) {
 	Log.WithFields(fields).Error(err.Message)
 }
 
-// TransactionRequestLog logs a request transaction received with http request, user and request identifier
-func TransactionRequestLog(requestID string, userID string, r *http.Request) {
+// TransactionRequestLog logs a request transaction received with http request, user, authenticator connector
+// that authenticated the user and request identifier
+func TransactionRequestLog(requestID string, userID string, authConnector string, r *http.Request) {
 	fields := getLogFields(requestID, userID, "", r, 0, nil)
+	if authConnector != "" {
+		fields["authConnector"] = authConnector
+	}
 	Log.WithFields(fields).Info("")
 }
 
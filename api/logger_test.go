@@ -70,11 +70,12 @@ func TestTransactionRequestLog(t *testing.T) {
 	Log = testLogger
 	req, err := http.NewRequest(httpMethod, httpAddress+httpURI, nil)
 	assert.Equal(t, nil, err)
-	TransactionRequestLog(requestID, userID, req)
+	TransactionRequestLog(requestID, userID, "oidc", req)
 	assert.Equal(t, 1, len(hook.Entries))
 	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level, "Error in test case")
 	assert.Empty(t, hook.LastEntry().Message, "Error in test case")
 	assert.Equal(t, requestID, hook.LastEntry().Data["requestID"], "Error in test case")
+	assert.Equal(t, "oidc", hook.LastEntry().Data["authConnector"], "Error in test case")
 	assert.Equal(t, httpMethod, hook.LastEntry().Data["httpMethod"], "Error in test case")
 	assert.Equal(t, httpAddress+httpURI, hook.LastEntry().Data["httpURI"], "Error in test case")
 	assert.Empty(t, hook.LastEntry().Data["httpRemoteAddress"], "Error in test case")
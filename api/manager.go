@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"time"
 )
 
@@ -12,6 +13,13 @@ type Resource interface {
 	GetUrn() string
 }
 
+// TaggedResource interface for resources with tags, that statement conditions can match
+type TaggedResource interface {
+	Resource
+	// This method must return resource tags
+	GetTags() map[string]string
+}
+
 // UserGroupRelation interface for User-Group relationships
 type UserGroupRelation interface {
 	GetUser() *User
@@ -33,11 +41,18 @@ type WorkerAPI struct {
 	PolicyRepo   PolicyRepo
 	ProxyRepo    ProxyRepo
 	AuthOidcRepo AuthOidcRepo
+	WebhookRepo  WebhookRepo
+	ApiKeyRepo   ApiKeyRepo
+	ChangeRepo   ChangeRepo
+
+	// Notifier receives events of resource changes. Events are discarded if it is nil
+	Notifier EventNotifier
 }
 
 // ProxyAPI that implements API interfaces using repositories
 type ProxyAPI struct {
-	ProxyRepo ProxyRepo
+	ProxyRepo  ProxyRepo
+	ChangeRepo ChangeRepo
 }
 
 // Filter properties for database search
@@ -49,32 +64,77 @@ type Filter struct {
 	GroupName         string
 	ProxyResourceName string
 	AuthProviderName  string
-	// Pagination
+	WebhookName       string
+	ApiKeyID          string
+	// Attributes that users must have
+	Attributes map[string]string
+	// State that users must have, enabled or disabled
+	State string
+	// Search by name, or by external identifier for users
+	NamePrefix   string
+	NameContains string
+	// Creation and update date ranges, unbounded when zero
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// Action that policies must contain, and resource URN that they must cover, in any statement
+	Action   string
+	Resource string
+	// Pagination, by offset or by cursor when it is set
 	Offset int
 	Limit  int
+	Cursor string
 	// Sorting
 	OrderBy string
+	// Return full objects in lists instead of their identifiers
+	Expand bool
+	// Cursors of the pages next to the page returned, set when the list is retrieved from database
+	NextCursor string
+	PrevCursor string
+}
+
+// Keyset cursor of a list page, encoded as an opaque token for clients
+type Cursor struct {
+	// Order of the list where the cursor was created
+	OrderBy string `json:"o,omitempty"`
+	// Value of the order column, if any, and unique key of the item where the page starts
+	Value string `json:"v,omitempty"`
+	Key   string `json:"k"`
+	// Items before the key instead of after it
+	Prev bool `json:"p,omitempty"`
 }
 
 // API INTERFACES WITH AUTHORIZATION
 
 // UserAPI interface
 type UserAPI interface {
-	// Store user in database. Throw error when parameters are invalid,
+	// Store user in database with its attributes. Throw error when parameters are invalid,
 	// user already exists or unexpected error happen.
-	AddUser(requestInfo RequestInfo, externalId string, path string) (*User, error)
+	AddUser(requestInfo RequestInfo, externalId string, path string, attributes map[string]string) (*User, error)
 
 	// Retrieve user from database. Throw error when parameter is invalid,
 	// user doesn't exist or unexpected error happen.
 	GetUserByExternalID(requestInfo RequestInfo, externalId string) (*User, error)
 
-	// Retrieve user identifiers from database filtered by pathPrefix (optional parameter). Throw error
-	// if pathPrefix is invalid or unexpected error happen.
+	// Retrieve user identifiers from database filtered by pathPrefix, attributes and state (optional parameters).
+	// Throw error if pathPrefix, attributes or state are invalid or unexpected error happen.
 	ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, error)
 
-	// Update user stored in database with new pathPrefix. Throw error if the input parameters
-	// are invalid, user doesn't exist or unexpected error happen.
-	UpdateUser(requestInfo RequestInfo, externalId string, newPath string) (*User, error)
+	// Retrieve users like ListUsers, returning full users that are also authorized to be retrieved.
+	ListUsersExpanded(requestInfo RequestInfo, filter *Filter) ([]User, int, error)
+
+	// Update user stored in database with new pathPrefix and attributes, that are kept if nil. Throw error if
+	// the input parameters are invalid, user doesn't exist or unexpected error happen.
+	UpdateUser(requestInfo RequestInfo, externalId string, newPath string, newAttributes map[string]string) (*User, error)
+
+	// Disable user stored in database with an optional reason, keeping its group relationships. Disabled users
+	// are denied everything. Throw error if the input parameters are invalid, user doesn't exist or unexpected error happen.
+	SuspendUser(requestInfo RequestInfo, externalId string, reason string) (*User, error)
+
+	// Enable user stored in database, removing its disabled reason. Throw error if externalId parameter is invalid,
+	// user doesn't exist or unexpected error happen.
+	ReactivateUser(requestInfo RequestInfo, externalId string) (*User, error)
 
 	// Remove user stored in database with its group relationships.
 	// Throw error if externalId parameter is invalid, user doesn't exist or unexpected error happen.
@@ -87,22 +147,30 @@ type UserAPI interface {
 
 // GroupAPI interface
 type GroupAPI interface {
-	// Store group in database. Throw error when the input parameters are invalid,
+	// Store group in database with its attributes. Throw error when the input parameters are invalid,
 	// the group already exist or unexpected error happen.
-	AddGroup(requestInfo RequestInfo, org string, name string, path string) (*Group, error)
+	AddGroup(requestInfo RequestInfo, org string, name string, path string, attributes map[string]string) (*Group, error)
 
 	// Retrieve group from database. Throw error when the input parameters are invalid,
 	// group doesn't exist or unexpected error happen.
 	GetGroupByName(requestInfo RequestInfo, org string, name string) (*Group, error)
 
+	// Retrieve group from database by its identifier, that doesn't change when group is renamed.
+	// Throw error when the input parameters are invalid, group doesn't exist or unexpected error happen.
+	GetGroupByID(requestInfo RequestInfo, id string) (*Group, error)
+
 	// Retrieve group identifiers from database filtered by org and pathPrefix parameters. These input parameters are optional.
 	// Throw error if the input parameters are invalid or unexpected error happen.
 	ListGroups(requestInfo RequestInfo, filter *Filter) ([]GroupIdentity, int, error)
 
-	// Update group stored in database with new name and pathPrefix.
+	// Retrieve groups like ListGroups, returning full groups that are also authorized to be retrieved.
+	ListGroupsExpanded(requestInfo RequestInfo, filter *Filter) ([]Group, int, error)
+
+	// Update group stored in database with new name, pathPrefix and attributes, that are kept if nil.
 	// Throw error if the input parameters are invalid, group to update doesn't exist,
 	// target group already exist or unexpected error happen.
-	UpdateGroup(requestInfo RequestInfo, org string, groupName string, newName string, newPath string) (*Group, error)
+	UpdateGroup(requestInfo RequestInfo, org string, groupName string, newName string, newPath string,
+		newAttributes map[string]string) (*Group, error)
 
 	// Remove group stored in database with its user and policy relationships.
 	// Throw error if the input parameters are invalid, the group doesn't exist or unexpected error happen.
@@ -116,6 +184,15 @@ type GroupAPI interface {
 	// group doesn't exist, user isn't a member of the group or unexpected error happen.
 	RemoveMember(requestInfo RequestInfo, externalId string, groupName string, org string) error
 
+	// Add new members to group in one request, returning the result of each user. Users that don't exist,
+	// aren't allowed or are already members fail without stopping the rest, unless atomic is set: then, if
+	// any user fails, no user is added. Throw error if the input parameters are invalid, group doesn't exist
+	// or unexpected error happen.
+	AddMembers(requestInfo RequestInfo, externalIds []string, groupName string, org string, atomic bool) ([]BulkResult, error)
+
+	// Remove members from group in one request, returning the result of each user, like AddMembers.
+	RemoveMembers(requestInfo RequestInfo, externalIds []string, groupName string, org string, atomic bool) ([]BulkResult, error)
+
 	// List user identifiers that belong to the group. Throw error if the input parameters are invalid,
 	// group doesn't exist or unexpected error happen.
 	ListMembers(requestInfo RequestInfo, filter *Filter) ([]GroupMembers, int, error)
@@ -128,6 +205,15 @@ type GroupAPI interface {
 	// group doesn't exist, policy isn't attached to the group or unexpected error happen.
 	DetachPolicyToGroup(requestInfo RequestInfo, org string, groupName string, policyName string) error
 
+	// Attach policies to group in one request, returning the result of each policy. Policies that don't exist,
+	// aren't allowed or are already attached fail without stopping the rest, unless atomic is set: then, if
+	// any policy fails, no policy is attached. Throw error if the input parameters are invalid, group doesn't
+	// exist or unexpected error happen.
+	AttachPoliciesToGroup(requestInfo RequestInfo, org string, groupName string, policyNames []string, atomic bool) ([]BulkResult, error)
+
+	// Detach policies from group in one request, returning the result of each policy, like AttachPoliciesToGroup.
+	DetachPoliciesToGroup(requestInfo RequestInfo, org string, groupName string, policyNames []string, atomic bool) ([]BulkResult, error)
+
 	// Retrieve policies that are attached to the group. Throw error if the input parameters are invalid,
 	// group doesn't exist or unexpected error happen.
 	ListAttachedGroupPolicies(requestInfo RequestInfo, filter *Filter) ([]GroupPolicies, int, error)
@@ -143,10 +229,15 @@ type PolicyAPI interface {
 	// policy doesn't exist or unexpected error happen.
 	GetPolicyByName(requestInfo RequestInfo, org string, name string) (*Policy, error)
 
-	// Retrieve policy identifiers from database filtered by org and pathPrefix parameters. These input parameters are optional.
+	// Retrieve policy identifiers from database filtered by org, pathPrefix, name, dates, and the action or resource
+	// of their statements. These input parameters are optional.
 	// Throw error if the input parameters are invalid or unexpected error happen.
 	ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, error)
 
+	// Retrieve policies like ListPolicies, returning full policies with their statements that are also
+	// authorized to be retrieved.
+	ListPoliciesExpanded(requestInfo RequestInfo, filter *Filter) ([]Policy, int, error)
+
 	// Update policy stored in database with new name, new pathPrefix and new statements.
 	// It overrides older statements. Throw error if the input parameters are invalid,
 	// policy to update doesn't exist, target policy already exist or unexpected error happen.
@@ -182,13 +273,20 @@ type AuthzAPI interface {
 
 	// Retrieve list of authorized external resources filtered according to the input parameters. Throw error
 	// if requestInfo doesn't exist, requestInfo doesn't have access to any resources or unexpected error happen.
-	GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []string) ([]string, error)
+	GetAuthorizedExternalResources(requestInfo RequestInfo, action string, resources []ExternalResource) ([]string, error)
 }
 
 // InternalProxyAPI interface to manage proxy resources
 type InternalProxyAPI interface {
 	// Retrieve list of proxy resources.
 	GetProxyResources() ([]ProxyResource, error)
+
+	// Retrieve changes after cursor in commit order and the cursor to resume from.
+	// Throw error if cursor is invalid or unexpected error happen.
+	GetChanges(cursor string) ([]Change, string, error)
+
+	// Retrieve cursor of the last change, to consume only changes that happen from now.
+	GetLastChangeCursor() (string, error)
 }
 
 // WorkerProxyResourcesAPI interface to manage proxy resources
@@ -204,6 +302,10 @@ type ProxyResourcesAPI interface {
 	// Retrieve list of proxy resources.
 	ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, error)
 
+	// Retrieve proxy resources like ListProxyResources, returning full proxy resources that are also
+	// authorized to be retrieved.
+	ListProxyResourcesExpanded(requestInfo RequestInfo, filter *Filter) ([]ProxyResource, int, error)
+
 	// Update proxy resource stored in database with new name, new path and new resource.
 	// It overrides the older resource. Throw error if the input parameters are invalid,
 	// proxy resource to update doesn't exist, target proxy resource already exist or unexpected error happen.
@@ -219,7 +321,8 @@ type ProxyResourcesAPI interface {
 type AuthOidcAPI interface {
 	// Store a new OIDC provider in database. Throw error when parameters are invalid,
 	// the OIDC provider already exists or unexpected error happen.
-	AddOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*OidcProvider, error)
+	AddOidcProvider(requestInfo RequestInfo, name string, path string, issuerURL string, oidcClients []string,
+		userProvisioning *UserProvisioning, groupMappings []GroupMapping, groupMappingMode string) (*OidcProvider, error)
 
 	// Retrieve OIDC provider from database. Throw error when parameter is invalid,
 	// the OIDC provider doesn't exist or unexpected error happen.
@@ -232,11 +335,86 @@ type AuthOidcAPI interface {
 	// Update OIDC provider stored in database with new parameters. Throw error if the input parameters
 	// are invalid, the OIDC provider doesn't exist or unexpected error happen.
 	UpdateOidcProvider(requestInfo RequestInfo, oidcProviderName string, newName string, newPath string, newIssuerUrl string,
-		newClients []string) (*OidcProvider, error)
+		newClients []string, newUserProvisioning *UserProvisioning, newGroupMappings []GroupMapping, newGroupMappingMode string) (*OidcProvider, error)
 
 	// Remove OIDC provider stored in database with its client relationships.
 	// Throw error if name parameter is invalid, OIDC provider doesn't exist or unexpected error happen.
 	RemoveOidcProvider(requestInfo RequestInfo, name string) error
+
+	// Create user authenticated by an OIDC provider with user provisioning, with path from token claims.
+	// Return nil user if it already exists. Throw error if the provider doesn't provision users, externalId
+	// or path are invalid or unexpected error happen.
+	ProvisionOidcUser(requestInfo RequestInfo, oidcProvider OidcProvider, externalId string,
+		claims map[string]interface{}) (*User, error)
+
+	// Add user authenticated by an OIDC provider to groups mapped from token claims, and remove it from groups
+	// of the provider mappings that aren't mapped anymore. Mapped groups that don't exist are ignored. Throw error
+	// if unexpected error happen.
+	SyncOidcUserGroups(requestInfo RequestInfo, oidcProvider OidcProvider, externalId string,
+		claims map[string]interface{}) error
+}
+
+// WebhookAPI interface
+type WebhookAPI interface {
+	// Store a new webhook in database. Throw error when parameters are invalid,
+	// the webhook already exists or unexpected error happen.
+	AddWebhook(requestInfo RequestInfo, name string, path string, url string, secret string, events []string) (*Webhook, error)
+
+	// Retrieve webhook from database. Throw error when parameter is invalid,
+	// the webhook doesn't exist or unexpected error happen.
+	GetWebhookByName(requestInfo RequestInfo, name string) (*Webhook, error)
+
+	// Retrieve webhook names from database filtered by pathPrefix (optional parameter). Throw error
+	// if pathPrefix is invalid or unexpected error happen.
+	ListWebhooks(requestInfo RequestInfo, filter *Filter) ([]string, int, error)
+
+	// Update webhook stored in database with new parameters. If secret is empty, the old one is kept.
+	// Throw error if the input parameters are invalid, the webhook doesn't exist or unexpected error happen.
+	UpdateWebhook(requestInfo RequestInfo, webhookName string, newName string, newPath string, newURL string,
+		newSecret string, newEvents []string) (*Webhook, error)
+
+	// Remove webhook stored in database with its delivery attempts.
+	// Throw error if name parameter is invalid, webhook doesn't exist or unexpected error happen.
+	RemoveWebhook(requestInfo RequestInfo, name string) error
+
+	// Retrieve delivery attempts of a webhook. Throw error if the input parameters are invalid,
+	// webhook doesn't exist or unexpected error happen.
+	ListWebhookDeliveries(requestInfo RequestInfo, filter *Filter) ([]WebhookDelivery, int, error)
+}
+
+// ApiKeyAPI interface to manage API keys of service accounts
+type ApiKeyAPI interface {
+	// Store a new API key of a service account in database, returning it with its key. Throw error when
+	// parameters are invalid, user isn't a service account or unexpected error happen.
+	AddApiKey(requestInfo RequestInfo, externalId string, name string, expireAt *time.Time) (*ApiKey, error)
+
+	// Retrieve API key of a service account from database, without its key. Throw error when parameters
+	// are invalid, the API key doesn't exist or unexpected error happen.
+	GetApiKey(requestInfo RequestInfo, externalId string, id string) (*ApiKey, error)
+
+	// Retrieve API keys of the service account in filter, without their keys. Throw error if the
+	// parameters are invalid, user doesn't exist or unexpected error happen.
+	ListApiKeys(requestInfo RequestInfo, filter *Filter) ([]ApiKey, int, error)
+
+	// Replace secret of an API key, returning it with its new key. Previous key stops working.
+	// Throw error when parameters are invalid, the API key doesn't exist or unexpected error happen.
+	RotateApiKey(requestInfo RequestInfo, externalId string, id string) (*ApiKey, error)
+
+	// Remove API key stored in database. Throw error when parameters are invalid,
+	// the API key doesn't exist or unexpected error happen.
+	RemoveApiKey(requestInfo RequestInfo, externalId string, id string) error
+
+	// Check secret of API key, returning externalId of its service account and storing its last use.
+	// Throw error if API key doesn't exist, secret doesn't match, API key expired or unexpected error happen.
+	AuthenticateApiKey(id string, secret string) (string, error)
+}
+
+// ChangeAPI interface to consume the change feed
+type ChangeAPI interface {
+	// Retrieve changes after cursor in commit order, filtered by user permissions, and the cursor
+	// to resume from. Throw error if cursor or limit are invalid, user isn't allowed
+	// or unexpected error happen.
+	ListChanges(requestInfo RequestInfo, cursor string, limit int) ([]Change, string, error)
 }
 
 // REPOSITORY INTERFACES
@@ -253,6 +431,10 @@ type UserRepo interface {
 	// if there are problems with database.
 	GetUsersFiltered(filter *Filter) ([]User, int, error)
 
+	// Retrieve users with the external identifiers from database. Users that don't exist are
+	// not returned. Throw error if there are problems with database.
+	GetUsersByExternalIDs(ids []string) ([]User, error)
+
 	// Update user stored in database with new fields. Throw error if the database restrictions
 	// are not satisfied or unexpected error happen.
 	UpdateUser(user User) (*User, error)
@@ -277,6 +459,9 @@ type GroupRepo interface {
 	// Retrieve group from database if it exists. Otherwise it throws an error.
 	GetGroupByName(org string, name string) (*Group, error)
 
+	// Retrieve group from database by its identifier if it exists. Otherwise it throws an error.
+	GetGroupById(id string) (*Group, error)
+
 	// Retrieve groups from database filtered by org and pathPrefix optional parameters. Throw error
 	// if there are problems with database.
 	GetGroupsFiltered(filter *Filter) ([]Group, int, error)
@@ -297,10 +482,22 @@ type GroupRepo interface {
 	// errors if there are problems with database.
 	RemoveMember(userID string, groupID string) error
 
+	// Add new members to group in a transaction. It doesn't check restrictions about existence of group
+	// or users. It throws errors if there are problems with database.
+	AddMembers(userIDs []string, groupID string) error
+
+	// Remove members from group in a transaction. It doesn't check restrictions about existence of group
+	// or users. It throws errors if there are problems with database.
+	RemoveMembers(userIDs []string, groupID string) error
+
 	// Check if user is member of group. It returns true if at least one relation exists. It throws
 	// errors if there are problems with database.
 	IsMemberOfGroup(userID string, groupID string) (bool, error)
 
+	// Retrieve the users that are members of group, among the users passed. It throws errors if there
+	// are problems with database.
+	FilterMembersOfGroup(userIDs []string, groupID string) ([]string, error)
+
 	// Retrieve users that belong to the group. Throw error if there are problems with database.
 	GetGroupMembers(groupID string, filter *Filter) ([]UserGroupRelation, int, error)
 
@@ -312,10 +509,22 @@ type GroupRepo interface {
 	// errors if there are problems with database.
 	DetachPolicy(groupID string, policyID string) error
 
+	// Attach policies to group in a transaction. It doesn't check restrictions about existence of group
+	// or policies. It throws errors if there are problems with database.
+	AttachPolicies(groupID string, policyIDs []string) error
+
+	// Detach policies from group in a transaction. It doesn't check restrictions about existence of group
+	// or policies. It throws errors if there are problems with database.
+	DetachPolicies(groupID string, policyIDs []string) error
+
 	// Check if policy is attached to group. It returns true if at least one relation exists. It throws
 	// errors if there are problems with database.
 	IsAttachedToGroup(groupID string, policyID string) (bool, error)
 
+	// Retrieve the policies that are attached to group, among the policies passed. It throws errors if
+	// there are problems with database.
+	FilterAttachedToGroup(groupID string, policyIDs []string) ([]string, error)
+
 	// Retrieve policies that are attached to the group. Throw error if there are problems with database.
 	GetAttachedPolicies(groupID string, filter *Filter) ([]PolicyGroupRelation, int, error)
 
@@ -331,8 +540,12 @@ type PolicyRepo interface {
 	// Retrieve policy from database if it exists. Otherwise it throws an error.
 	GetPolicyByName(org string, name string) (*Policy, error)
 
-	// Retrieve policies from database filtered by org and pathPrefix optional parameters. Throw error
-	// if there are problems with database.
+	// Retrieve policies of the organization with the names from database, with their statements.
+	// Policies that don't exist are not returned. Throw error if there are problems with database.
+	GetPoliciesByNames(org string, names []string) ([]Policy, error)
+
+	// Retrieve policies from database filtered by org, pathPrefix, name, dates, action and resource optional
+	// parameters. Throw error if there are problems with database.
 	GetPoliciesFiltered(filter *Filter) ([]Policy, int, error)
 
 	// Update policy stored in database with new fields. Also it overrides statements if it has.
@@ -396,3 +609,76 @@ type AuthOidcRepo interface {
 	// OrderByValidColumns returns valid columns that you can use in OrderBy
 	OrderByValidColumns(action string) []string
 }
+
+// WebhookRepo contains all database operations
+type WebhookRepo interface {
+	// Store a webhook in database if there aren't errors.
+	AddWebhook(webhook Webhook) (*Webhook, error)
+
+	// Retrieve the webhook from database if it exists. Otherwise it throws an error.
+	GetWebhookByName(name string) (*Webhook, error)
+
+	// Retrieve webhooks from database filtered by pathPrefix optional parameter. Throw error
+	// if there are problems with database.
+	GetWebhooksFiltered(filter *Filter) ([]Webhook, int, error)
+
+	// Update the webhook stored in database with new fields.
+	// Throw error if there are problems with database.
+	UpdateWebhook(webhook Webhook) (*Webhook, error)
+
+	// Remove the webhook stored in database with its delivery attempts.
+	// Throw error if there are problems during transactions.
+	RemoveWebhook(id string) error
+
+	// Store a webhook delivery attempt in database. Throw error if there are problems with database.
+	AddWebhookDelivery(delivery WebhookDelivery) error
+
+	// Retrieve delivery attempts of the webhook. Throw error if there are problems with database.
+	GetWebhookDeliveries(webhookID string, filter *Filter) ([]WebhookDelivery, int, error)
+
+	// OrderByValidColumns returns valid columns that you can use in OrderBy
+	OrderByValidColumns(action string) []string
+}
+
+// ApiKeyRepo contains all database operations of API keys
+type ApiKeyRepo interface {
+	// Store an API key in database if there aren't errors.
+	AddApiKey(apiKey ApiKey) (*ApiKey, error)
+
+	// Retrieve the API key with externalId of its user from database if it exists.
+	// Otherwise it throws an error.
+	GetApiKeyByID(id string) (*ApiKey, error)
+
+	// Retrieve API keys of user from database. Throw error if there are problems with database.
+	GetApiKeysByUserID(userID string, filter *Filter) ([]ApiKey, int, error)
+
+	// Update the API key stored in database with new fields.
+	// Throw error if there are problems with database.
+	UpdateApiKey(apiKey ApiKey) (*ApiKey, error)
+
+	// Store last use of the API key. Throw error if there are problems with database.
+	UpdateApiKeyLastUsed(id string, lastUsedAt time.Time) error
+
+	// Remove the API key stored in database. Throw error if there are problems with database.
+	RemoveApiKey(id string) error
+
+	// OrderByValidColumns returns valid columns that you can use in OrderBy
+	OrderByValidColumns(action string) []string
+}
+
+// ContextRepo is implemented by repositories that trace their operations as part of a request
+type ContextRepo interface {
+	// Return a copy of the repository whose operations are traced as children of the span in ctx
+	WithContext(ctx context.Context) interface{}
+}
+
+// ChangeRepo contains all database operations of the change feed
+type ChangeRepo interface {
+	// Retrieve changes with sequence greater than since, ordered by sequence, up to limit.
+	// Throw error if there are problems with database.
+	GetChanges(since int64, limit int) ([]Change, error)
+
+	// Retrieve sequence of the last change, 0 if there aren't changes.
+	// Throw error if there are problems with database.
+	GetLastChangeSeq() (int64, error)
+}
@@ -2,12 +2,24 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/satori/go.uuid"
 )
 
+const (
+	// Condition operators
+	CONDITION_OPERATOR_EQUALS     = "equals"
+	CONDITION_OPERATOR_NOT_EQUALS = "notEquals"
+
+	// Sources of condition attributes
+	CONDITION_SOURCE_USER     = "user"
+	CONDITION_SOURCE_GROUP    = "group"
+	CONDITION_SOURCE_RESOURCE = "resource"
+)
+
 // TYPE DEFINITIONS
 
 // Policy domain
@@ -38,9 +50,18 @@ type PolicyIdentity struct {
 }
 
 type Statement struct {
-	Effect    string   `json:"effect,omitempty"`
-	Actions   []string `json:"actions,omitempty"`
-	Resources []string `json:"resources,omitempty"`
+	Effect     string      `json:"effect,omitempty"`
+	Actions    []string    `json:"actions,omitempty"`
+	Resources  []string    `json:"resources,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition that attributes of the authenticated user, or of its groups, must meet to apply a statement.
+// Attribute is the attribute key prefixed by its source, e.g. user:clearance or group:department
+type Condition struct {
+	Operator  string   `json:"operator,omitempty"`
+	Attribute string   `json:"attribute,omitempty"`
+	Values    []string `json:"values,omitempty"`
 }
 
 type PolicyGroups struct {
@@ -49,12 +70,28 @@ type PolicyGroups struct {
 }
 
 func (s Statement) String() string {
-	return fmt.Sprintf("[effect: %v, actions: %v, resources: %v]", s.Effect, s.Actions, s.Resources)
+	return fmt.Sprintf("[effect: %v, actions: %v, resources: %v, conditions: %v]", s.Effect, s.Actions, s.Resources, s.Conditions)
+}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("[operator: %v, attribute: %v, values: %v]", c.Operator, c.Attribute, c.Values)
+}
+
+// getAttributeSourceAndKey splits condition attribute into its source and attribute key
+func (c Condition) getAttributeSourceAndKey() (string, string) {
+	sourceKey := strings.SplitN(c.Attribute, ":", 2)
+	if len(sourceKey) < 2 {
+		return "", c.Attribute
+	}
+	return sourceKey[0], sourceKey[1]
 }
 
 // POLICY API IMPLEMENTATION
 
 func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string, org string, statements []Statement) (*Policy, error) {
+	api, span := api.startSpan(&requestInfo, "AddPolicy")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -124,6 +161,7 @@ func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string
 			}
 
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy created %+v", createdPolicy))
+			api.notifyEvent(requestInfo, EVENT_POLICY_CREATED, createdPolicy.Urn, createdPolicy)
 			return createdPolicy, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -140,6 +178,9 @@ func (api WorkerAPI) AddPolicy(requestInfo RequestInfo, name string, path string
 }
 
 func (api WorkerAPI) GetPolicyByName(requestInfo RequestInfo, org string, policyName string) (*Policy, error) {
+	api, span := api.startSpan(&requestInfo, "GetPolicyByName")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(policyName) {
 		return nil, &Error{
@@ -193,35 +234,10 @@ func (api WorkerAPI) GetPolicyByName(requestInfo RequestInfo, org string, policy
 }
 
 func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]PolicyIdentity, int, error) {
-	// Validate fields
-	var total int
-	orderByValidColumns := api.PolicyRepo.OrderByValidColumns(POLICY_ACTION_LIST_POLICIES)
-	err := validateFilter(filter, orderByValidColumns)
-	if err != nil {
-		return nil, total, err
-	}
+	api, span := api.startSpan(&requestInfo, "ListPolicies")
+	defer span.End()
 
-	// Call repo to retrieve the policies
-	policies, total, err := api.PolicyRepo.GetPoliciesFiltered(filter)
-
-	// Error handling
-	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, total, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
-	}
-
-	// Check restrictions to list
-	var urnPrefix string
-	if len(filter.Org) == 0 {
-		urnPrefix = "*"
-	} else {
-		urnPrefix = GetUrnPrefix(filter.Org, RESOURCE_POLICY, filter.PathPrefix)
-	}
-	policiesFiltered, err := api.GetAuthorizedPolicies(requestInfo, urnPrefix, POLICY_ACTION_LIST_POLICIES, policies)
+	policiesFiltered, total, err := api.listPolicies(requestInfo, filter, POLICY_ACTION_LIST_POLICIES)
 	if err != nil {
 		return nil, total, err
 	}
@@ -237,8 +253,19 @@ func (api WorkerAPI) ListPolicies(requestInfo RequestInfo, filter *Filter) ([]Po
 	return policyIDs, total, nil
 }
 
+func (api WorkerAPI) ListPoliciesExpanded(requestInfo RequestInfo, filter *Filter) ([]Policy, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListPoliciesExpanded")
+	defer span.End()
+
+	// Full policies are only returned when they can be retrieved too
+	return api.listPolicies(requestInfo, filter, POLICY_ACTION_LIST_POLICIES, POLICY_ACTION_GET_POLICY)
+}
+
 func (api WorkerAPI) UpdatePolicy(requestInfo RequestInfo, org string, policyName string, newName string, newPath string,
 	newStatements []Statement) (*Policy, error) {
+	api, span := api.startSpan(&requestInfo, "UpdatePolicy")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(newName) {
 		return nil, &Error{
@@ -341,10 +368,13 @@ func (api WorkerAPI) UpdatePolicy(requestInfo RequestInfo, org string, policyNam
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy updated from %+v to %+v", oldPolicy, updatedPolicy))
+	api.notifyEvent(requestInfo, EVENT_POLICY_UPDATED, updatedPolicy.Urn, updatedPolicy)
 	return updatedPolicy, nil
 }
 
 func (api WorkerAPI) RemovePolicy(requestInfo RequestInfo, org string, name string) error {
+	api, span := api.startSpan(&requestInfo, "RemovePolicy")
+	defer span.End()
 
 	// Call repo to retrieve the policy
 	policy, err := api.GetPolicyByName(requestInfo, org, name)
@@ -376,10 +406,14 @@ func (api WorkerAPI) RemovePolicy(requestInfo RequestInfo, org string, name stri
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Policy deleted %+v", policy))
+	api.notifyEvent(requestInfo, EVENT_POLICY_DELETED, policy.Urn, policy)
 	return nil
 }
 
 func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter) ([]PolicyGroups, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListAttachedGroups")
+	defer span.End()
+
 	// Validate fields
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(POLICY_ACTION_LIST_ATTACHED_GROUPS)
@@ -436,6 +470,50 @@ func (api WorkerAPI) ListAttachedGroups(requestInfo RequestInfo, filter *Filter)
 
 // PRIVATE HELPER METHODS
 
+// listPolicies retrieves policies filtered, keeping the ones authorized for all the actions
+func (api WorkerAPI) listPolicies(requestInfo RequestInfo, filter *Filter, actions ...string) ([]Policy, int, error) {
+	// Validate fields
+	var total int
+	orderByValidColumns := api.PolicyRepo.OrderByValidColumns(POLICY_ACTION_LIST_POLICIES)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the policies
+	policies, total, err := api.PolicyRepo.GetPoliciesFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions to list
+	var urnPrefix string
+	if len(filter.Org) == 0 {
+		urnPrefix = "*"
+	} else {
+		urnPrefix = GetUrnPrefix(filter.Org, RESOURCE_POLICY, filter.PathPrefix)
+	}
+	for i, action := range actions {
+		policies, err = api.GetAuthorizedPolicies(requestInfo, urnPrefix, action, policies)
+		if err != nil {
+			// Denying a further action leaves nothing to return, instead of failing the list
+			if apiError, ok := err.(*Error); i > 0 && ok && apiError.Code == UNAUTHORIZED_RESOURCES_ERROR {
+				return []Policy{}, total, nil
+			}
+			return nil, total, err
+		}
+	}
+
+	return policies, total, nil
+}
+
 func createPolicy(name string, path string, org string, statements *[]Statement) Policy {
 	urn := CreateUrn(org, RESOURCE_POLICY, path, name)
 	policy := Policy{
@@ -273,6 +273,7 @@ func TestAuthAPI_AddPolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -316,6 +317,7 @@ func TestAuthAPI_AddPolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -568,6 +570,7 @@ func TestAuthAPI_GetPolicyByName(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -614,6 +617,7 @@ func TestAuthAPI_GetPolicyByName(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -846,6 +850,7 @@ func TestAuthAPI_ListPolicies(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1003,6 +1008,424 @@ func TestAuthAPI_ListPolicies(t *testing.T) {
 	}
 }
 
+func TestAuthAPI_ListPoliciesExpanded(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedPolicies []Policy
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		getUserByExternalIDResult *User
+		getUserByExternalIDErr    error
+		// Manager Errors
+		getPoliciesFilteredMethodResult []Policy
+		getPoliciesFilteredMethodErr    error
+	}{
+		"OkCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				Org: "example",
+			},
+			expectedPolicies: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+				{
+					ID:   "PolicyDenied",
+					Name: "policyDenied",
+					Org:  "example",
+					Path: "/path2/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path2/", "policyDenied"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+			totalResult: 2,
+			getPoliciesFilteredMethodResult: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+				{
+					ID:   "PolicyDenied",
+					Name: "policyDenied",
+					Org:  "example",
+					Path: "/path2/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path2/", "policyDenied"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+		},
+		"OkCaseUser": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			filter: &Filter{
+				Org: "example",
+			},
+			expectedPolicies: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+			totalResult: 2,
+			getPoliciesFilteredMethodResult: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+				{
+					ID:   "PolicyDenied",
+					Name: "policyDenied",
+					Org:  "example",
+					Path: "/path2/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path2/", "policyDenied"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_LIST_POLICIES,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_POLICY, "/"),
+								},
+							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_GET_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"OkCaseGetNotAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			filter: &Filter{
+				Org: "example",
+			},
+			expectedPolicies: []Policy{},
+			totalResult:      2,
+			getPoliciesFilteredMethodResult: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+				{
+					ID:   "PolicyDenied",
+					Name: "policyDenied",
+					Org:  "example",
+					Path: "/path2/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path2/", "policyDenied"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_LIST_POLICIES,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_POLICY, "/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseListNotAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			filter: &Filter{
+				Org: "example",
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam:example:policy/*",
+			},
+			getPoliciesFilteredMethodResult: []Policy{
+				{
+					ID:   "PolicyAllowed",
+					Name: "policyAllowed",
+					Org:  "example",
+					Path: "/path/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyAllowed"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+				{
+					ID:   "PolicyDenied",
+					Name: "policyDenied",
+					Org:  "example",
+					Path: "/path2/",
+					Urn:  CreateUrn("example", RESOURCE_POLICY, "/path2/", "policyDenied"),
+					Statements: &[]Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								POLICY_ACTION_GET_POLICY,
+							},
+							Resources: []string{
+								GetUrnPrefix("example", RESOURCE_POLICY, "/path/"),
+							},
+						},
+					},
+				},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									POLICY_ACTION_GET_POLICY,
+								},
+								Resources: []string{
+									GetUrnPrefix("example", RESOURCE_POLICY, "/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][0] = testcase.getPoliciesFilteredMethodResult
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetPoliciesFilteredMethod][2] = testcase.getPoliciesFilteredMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		policies, total, err := testAPI.ListPoliciesExpanded(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedPolicies, policies)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
+
 func TestAuthAPI_UpdatePolicy(t *testing.T) {
 	testcases := map[string]struct {
 		requestInfo   RequestInfo
@@ -1491,6 +1914,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1601,6 +2025,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1715,6 +2140,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1838,6 +2264,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -1956,6 +2383,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2074,6 +2502,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2249,6 +2678,7 @@ func TestAuthAPI_UpdatePolicy(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2477,6 +2907,7 @@ func TestAuthAPI_RemovePolicy(t *testing.T) {
 			getUserByExternalIDResult: &User{
 				ID:         "543210",
 				ExternalID: "123456",
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2538,6 +2969,7 @@ func TestAuthAPI_RemovePolicy(t *testing.T) {
 			getUserByExternalIDResult: &User{
 				ID:         "543210",
 				ExternalID: "123456",
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2801,6 +3233,7 @@ func TestAuthAPI_ListAttachedGroups(t *testing.T) {
 			getUserByExternalIDResult: &User{
 				ID:         "543210",
 				ExternalID: "123456",
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2864,6 +3297,7 @@ func TestAuthAPI_ListAttachedGroups(t *testing.T) {
 			getUserByExternalIDResult: &User{
 				ID:         "543210",
 				ExternalID: "123456",
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
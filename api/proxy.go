@@ -36,6 +36,8 @@ type ResourceEntity struct {
 	Method string `json:"method,omitempty"`
 	Urn    string `json:"urn,omitempty"`
 	Action string `json:"action,omitempty"`
+	// Static tags sent with each authorization check of this resource
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 func (p ProxyResource) GetUrn() string {
@@ -60,6 +62,9 @@ func (api ProxyAPI) GetProxyResources() ([]ProxyResource, error) {
 }
 
 func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org string, path string, resource ResourceEntity) (*ProxyResource, error) {
+	api, span := api.startSpan(&requestInfo, "AddProxyResource")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -150,6 +155,7 @@ func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org
 				}
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("proxy resource created %+v", created))
+			api.notifyEvent(requestInfo, EVENT_PROXY_RESOURCE_CREATED, created.Urn, created)
 			return created, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -166,6 +172,9 @@ func (api WorkerAPI) AddProxyResource(requestInfo RequestInfo, name string, org
 }
 
 func (api WorkerAPI) GetProxyResourceByName(requestInfo RequestInfo, org string, name string) (*ProxyResource, error) {
+	api, span := api.startSpan(&requestInfo, "GetProxyResourceByName")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(name) {
 		return nil, &Error{
@@ -221,6 +230,9 @@ func (api WorkerAPI) GetProxyResourceByName(requestInfo RequestInfo, org string,
 }
 
 func (api WorkerAPI) UpdateProxyResource(requestInfo RequestInfo, org string, name string, newName string, newPath string, newResource ResourceEntity) (*ProxyResource, error) {
+	api, span := api.startSpan(&requestInfo, "UpdateProxyResource")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidName(newName) {
 		return nil, &Error{
@@ -352,10 +364,14 @@ func (api WorkerAPI) UpdateProxyResource(requestInfo RequestInfo, org string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Proxy resource updated from %+v to %+v", oldProxyResource, updatedProxyResource))
+	api.notifyEvent(requestInfo, EVENT_PROXY_RESOURCE_UPDATED, updatedProxyResource.Urn, updatedProxyResource)
 	return updatedProxyResource, nil
 }
 
 func (api WorkerAPI) RemoveProxyResource(requestInfo RequestInfo, org string, name string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveProxyResource")
+	defer span.End()
+
 	// Call repo to retrieve the proxy resource
 	proxyResource, err := api.GetProxyResourceByName(requestInfo, org, name)
 	if err != nil {
@@ -388,10 +404,42 @@ func (api WorkerAPI) RemoveProxyResource(requestInfo RequestInfo, org string, na
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Proxy resource deleted %+v", proxyResource))
+	api.notifyEvent(requestInfo, EVENT_PROXY_RESOURCE_DELETED, proxyResource.Urn, proxyResource)
 	return nil
 }
 
 func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter) ([]ProxyResourceIdentity, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListProxyResources")
+	defer span.End()
+
+	proxyResourcesFiltered, total, err := api.listProxyResources(requestInfo, filter, PROXY_ACTION_LIST_RESOURCES)
+	if err != nil {
+		return nil, total, err
+	}
+
+	proxyResourcesIDs := []ProxyResourceIdentity{}
+	for _, p := range proxyResourcesFiltered {
+		proxyResourcesIDs = append(proxyResourcesIDs, ProxyResourceIdentity{
+			Org:  p.Org,
+			Name: p.Name,
+		})
+	}
+
+	return proxyResourcesIDs, total, nil
+}
+
+func (api WorkerAPI) ListProxyResourcesExpanded(requestInfo RequestInfo, filter *Filter) ([]ProxyResource, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListProxyResourcesExpanded")
+	defer span.End()
+
+	// Full proxy resources are only returned when they can be retrieved too
+	return api.listProxyResources(requestInfo, filter, PROXY_ACTION_LIST_RESOURCES, PROXY_ACTION_GET_PROXY_RESOURCE)
+}
+
+// PRIVATE HELPER METHODS
+
+// listProxyResources retrieves proxy resources filtered, keeping the ones authorized for all the actions
+func (api WorkerAPI) listProxyResources(requestInfo RequestInfo, filter *Filter, actions ...string) ([]ProxyResource, int, error) {
 	// Validate fields
 	var total int
 	orderByValidColumns := api.ProxyRepo.OrderByValidColumns(PROXY_ACTION_LIST_RESOURCES)
@@ -420,24 +468,20 @@ func (api WorkerAPI) ListProxyResources(requestInfo RequestInfo, filter *Filter)
 	} else {
 		urnPrefix = GetUrnPrefix(filter.Org, RESOURCE_PROXY, filter.PathPrefix)
 	}
-	proxyResourcesFiltered, err := api.GetAuthorizedProxyResources(requestInfo, urnPrefix, PROXY_ACTION_LIST_RESOURCES, proxyResources)
-	if err != nil {
-		return nil, total, err
-	}
-
-	proxyResourcesIDs := []ProxyResourceIdentity{}
-	for _, p := range proxyResourcesFiltered {
-		proxyResourcesIDs = append(proxyResourcesIDs, ProxyResourceIdentity{
-			Org:  p.Org,
-			Name: p.Name,
-		})
+	for i, action := range actions {
+		proxyResources, err = api.GetAuthorizedProxyResources(requestInfo, urnPrefix, action, proxyResources)
+		if err != nil {
+			// Denying a further action leaves nothing to return, instead of failing the list
+			if apiError, ok := err.(*Error); i > 0 && ok && apiError.Code == UNAUTHORIZED_RESOURCES_ERROR {
+				return []ProxyResource{}, total, nil
+			}
+			return nil, total, err
+		}
 	}
 
-	return proxyResourcesIDs, total, nil
+	return proxyResources, total, nil
 }
 
-// PRIVATE HELPER METHODS
-
 // This method validates proxy routes to avoid panics when they will be instantiated
 func validateProxyRoutes(proxyResources []ProxyResource) error {
 	router := httprouter.New()
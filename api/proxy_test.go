@@ -124,6 +124,7 @@ func TestWorkerAPI_GetProxyResourceByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -261,6 +262,7 @@ func TestWorkerAPI_GetProxyResourceByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -345,6 +347,7 @@ func TestWorkerAPI_GetProxyResourceByName(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -442,6 +445,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 		},
 		"OKCase": {
@@ -469,6 +473,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
@@ -588,6 +593,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -652,6 +658,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -726,6 +733,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("org", RESOURCE_USER, "/example/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -779,6 +787,7 @@ func TestWorkerAPI_RemoveProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			removeProxyResourceMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -1031,6 +1040,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseInvalidName": {
@@ -1330,6 +1340,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyUpdateProxyGroup": {
@@ -1440,6 +1451,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermissionsToUpdateTarget": {
@@ -1521,6 +1533,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseDenyToUpdateTarget": {
@@ -1610,6 +1623,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNoPermission": {
@@ -1701,6 +1715,7 @@ func TestWorkerAPI_UpdateProxyResource(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUpdateGroupDBErr": {
@@ -1987,6 +2002,7 @@ func TestWorkerAPI_ListProxyResources(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2245,6 +2261,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2347,6 +2364,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/path/",
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseNotAuthenticatedUser": {
@@ -2396,6 +2414,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -2465,6 +2484,7 @@ func TestWorkerAPI_AddProxyResource(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
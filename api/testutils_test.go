@@ -16,22 +16,31 @@ const (
 	AddUserMethod                  = "AddUser"
 	UpdateUserMethod               = "UpdateUser"
 	GetUsersFilteredMethod         = "GetUsersFiltered"
+	GetUsersByExternalIDsMethod    = "GetUsersByExternalIDs"
 	GetGroupsByUserIDMethod        = "GetGroupsByUserID"
 	RemoveUserMethod               = "RemoveUser"
 	GetGroupByNameMethod           = "GetGroupByName"
+	GetGroupByIdMethod             = "GetGroupById"
 	IsMemberOfGroupMethod          = "IsMemberOfGroup"
+	FilterMembersOfGroupMethod     = "FilterMembersOfGroup"
 	GetGroupMembersMethod          = "GetGroupMembers"
 	IsAttachedToGroupMethod        = "IsAttachedToGroup"
+	FilterAttachedToGroupMethod    = "FilterAttachedToGroup"
 	GetAttachedPoliciesMethod      = "GetAttachedPolicies"
 	GetGroupsFilteredMethod        = "GetGroupsFiltered"
 	RemoveGroupMethod              = "RemoveGroup"
 	AddGroupMethod                 = "AddGroup"
 	AddMemberMethod                = "AddMember"
+	AddMembersMethod               = "AddMembers"
 	RemoveMemberMethod             = "RemoveMember"
+	RemoveMembersMethod            = "RemoveMembers"
 	UpdateGroupMethod              = "UpdateGroup"
 	AttachPolicyMethod             = "AttachPolicy"
+	AttachPoliciesMethod           = "AttachPolicies"
 	DetachPolicyMethod             = "DetachPolicy"
+	DetachPoliciesMethod           = "DetachPolicies"
 	GetPolicyByNameMethod          = "GetPolicyByName"
+	GetPoliciesByNamesMethod       = "GetPoliciesByNames"
 	AddPolicyMethod                = "AddPolicy"
 	UpdatePolicyMethod             = "UpdatePolicy"
 	RemovePolicyMethod             = "RemovePolicy"
@@ -48,6 +57,21 @@ const (
 	GetOidcProvidersFilteredMethod = "GetOidcProvidersFiltered"
 	UpdateOidcProviderMethod       = "UpdateOidcProvider"
 	RemoveOidcProviderMethod       = "RemoveOidcProviderMethod"
+	AddWebhookMethod               = "AddWebhook"
+	GetWebhookByNameMethod         = "GetWebhookByName"
+	GetWebhooksFilteredMethod      = "GetWebhooksFiltered"
+	UpdateWebhookMethod            = "UpdateWebhook"
+	RemoveWebhookMethod            = "RemoveWebhook"
+	AddWebhookDeliveryMethod       = "AddWebhookDelivery"
+	GetWebhookDeliveriesMethod     = "GetWebhookDeliveries"
+	AddApiKeyMethod                = "AddApiKey"
+	GetApiKeyByIDMethod            = "GetApiKeyByID"
+	GetApiKeysByUserIDMethod       = "GetApiKeysByUserID"
+	UpdateApiKeyMethod             = "UpdateApiKey"
+	UpdateApiKeyLastUsedMethod     = "UpdateApiKeyLastUsed"
+	RemoveApiKeyMethod             = "RemoveApiKey"
+	GetChangesMethod               = "GetChanges"
+	GetLastChangeSeqMethod         = "GetLastChangeSeq"
 )
 
 // TestRepo that implements all repo manager interfaces
@@ -90,22 +114,31 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsIn[AddUserMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[UpdateUserMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetUsersFilteredMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetUsersByExternalIDsMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetGroupsByUserIDMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[RemoveUserMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[GetGroupByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetGroupByIdMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[IsMemberOfGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[FilterMembersOfGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetGroupMembersMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[IsAttachedToGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[FilterAttachedToGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetAttachedPoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetGroupsFilteredMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[RemoveGroupMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[AddGroupMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[AddMemberMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[AddMembersMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[RemoveMemberMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RemoveMembersMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[UpdateGroupMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[AttachPolicyMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[AttachPoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[DetachPolicyMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[DetachPoliciesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[GetPolicyByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetPoliciesByNamesMethod] = make([]interface{}, 2)
 	testRepo.ArgsIn[AddPolicyMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[UpdatePolicyMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[RemovePolicyMethod] = make([]interface{}, 1)
@@ -122,27 +155,51 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsIn[GetOidcProvidersFilteredMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[UpdateOidcProviderMethod] = make([]interface{}, 1)
 	testRepo.ArgsIn[RemoveOidcProviderMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhookByNameMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhooksFilteredMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[UpdateWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[RemoveWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[AddWebhookDeliveryMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetWebhookDeliveriesMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[AddApiKeyMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetApiKeyByIDMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetApiKeysByUserIDMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[UpdateApiKeyMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[UpdateApiKeyLastUsedMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[RemoveApiKeyMethod] = make([]interface{}, 1)
+	testRepo.ArgsIn[GetChangesMethod] = make([]interface{}, 2)
+	testRepo.ArgsIn[GetLastChangeSeqMethod] = make([]interface{}, 0)
 
 	testRepo.ArgsOut[GetUserByExternalIDMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddUserMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[UpdateUserMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetUsersFilteredMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[GetUsersByExternalIDsMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetGroupsByUserIDMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[RemoveUserMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[GetGroupByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetGroupByIdMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[IsMemberOfGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[FilterMembersOfGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetGroupMembersMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[IsAttachedToGroupMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[FilterAttachedToGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[GetAttachedPoliciesMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[GetGroupsFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[RemoveGroupMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[AddGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddMemberMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddMembersMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[RemoveMemberMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[RemoveMembersMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[UpdateGroupMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AttachPolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AttachPoliciesMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[DetachPolicyMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[DetachPoliciesMethod] = make([]interface{}, 1)
 	testRepo.ArgsOut[GetPolicyByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetPoliciesByNamesMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[AddPolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[UpdatePolicyMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[RemovePolicyMethod] = make([]interface{}, 1)
@@ -159,6 +216,21 @@ func makeTestRepo() *TestRepo {
 	testRepo.ArgsOut[GetOidcProvidersFilteredMethod] = make([]interface{}, 3)
 	testRepo.ArgsOut[UpdateOidcProviderMethod] = make([]interface{}, 2)
 	testRepo.ArgsOut[RemoveOidcProviderMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddWebhookMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetWebhooksFilteredMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[UpdateWebhookMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[RemoveWebhookMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[AddWebhookDeliveryMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetWebhookDeliveriesMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[AddApiKeyMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetApiKeyByIDMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetApiKeysByUserIDMethod] = make([]interface{}, 3)
+	testRepo.ArgsOut[UpdateApiKeyMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[UpdateApiKeyLastUsedMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[RemoveApiKeyMethod] = make([]interface{}, 1)
+	testRepo.ArgsOut[GetChangesMethod] = make([]interface{}, 2)
+	testRepo.ArgsOut[GetLastChangeSeqMethod] = make([]interface{}, 2)
 
 	return testRepo
 }
@@ -170,6 +242,9 @@ func makeTestAPI(testRepo *TestRepo) *WorkerAPI {
 		PolicyRepo:   testRepo,
 		ProxyRepo:    testRepo,
 		AuthOidcRepo: testRepo,
+		WebhookRepo:  testRepo,
+		ApiKeyRepo:   testRepo,
+		ChangeRepo:   testRepo,
 	}
 	Log = &log.Logger{
 		Out:       bytes.NewBuffer([]byte{}),
@@ -182,7 +257,8 @@ func makeTestAPI(testRepo *TestRepo) *WorkerAPI {
 
 func makeProxyTestAPI(testRepo *TestRepo) *ProxyAPI {
 	api := &ProxyAPI{
-		ProxyRepo: testRepo,
+		ProxyRepo:  testRepo,
+		ChangeRepo: testRepo,
 	}
 	Log = &log.Logger{
 		Out:       bytes.NewBuffer([]byte{}),
@@ -289,6 +365,19 @@ func (t TestRepo) GetUsersFiltered(filter *Filter) ([]User, int, error) {
 	return users, total, err
 }
 
+func (t TestRepo) GetUsersByExternalIDs(ids []string) ([]User, error) {
+	t.ArgsIn[GetUsersByExternalIDsMethod][0] = ids
+	var users []User
+	if t.ArgsOut[GetUsersByExternalIDsMethod][0] != nil {
+		users = t.ArgsOut[GetUsersByExternalIDsMethod][0].([]User)
+	}
+	var err error
+	if t.ArgsOut[GetUsersByExternalIDsMethod][1] != nil {
+		err = t.ArgsOut[GetUsersByExternalIDsMethod][1].(error)
+	}
+	return users, err
+}
+
 func (t TestRepo) GetGroupsByUserID(id string, filter *Filter) ([]UserGroupRelation, int, error) {
 	t.ArgsIn[GetGroupsByUserIDMethod][0] = id
 	var groups []UserGroupRelation
@@ -340,6 +429,19 @@ func (t TestRepo) GetGroupByName(org string, name string) (*Group, error) {
 	return group, err
 }
 
+func (t TestRepo) GetGroupById(id string) (*Group, error) {
+	t.ArgsIn[GetGroupByIdMethod][0] = id
+	var group *Group
+	if t.ArgsOut[GetGroupByIdMethod][0] != nil {
+		group = t.ArgsOut[GetGroupByIdMethod][0].(*Group)
+	}
+	var err error
+	if t.ArgsOut[GetGroupByIdMethod][1] != nil {
+		err = t.ArgsOut[GetGroupByIdMethod][1].(error)
+	}
+	return group, err
+}
+
 func (t TestRepo) IsMemberOfGroup(userID string, groupID string) (bool, error) {
 	t.ArgsIn[IsMemberOfGroupMethod][0] = userID
 	t.ArgsIn[IsMemberOfGroupMethod][1] = groupID
@@ -354,6 +456,20 @@ func (t TestRepo) IsMemberOfGroup(userID string, groupID string) (bool, error) {
 	return isMember, err
 }
 
+func (t TestRepo) FilterMembersOfGroup(userIDs []string, groupID string) ([]string, error) {
+	t.ArgsIn[FilterMembersOfGroupMethod][0] = userIDs
+	t.ArgsIn[FilterMembersOfGroupMethod][1] = groupID
+	var ids []string
+	if t.ArgsOut[FilterMembersOfGroupMethod][0] != nil {
+		ids = t.ArgsOut[FilterMembersOfGroupMethod][0].([]string)
+	}
+	var err error
+	if t.ArgsOut[FilterMembersOfGroupMethod][1] != nil {
+		err = t.ArgsOut[FilterMembersOfGroupMethod][1].(error)
+	}
+	return ids, err
+}
+
 func (t TestRepo) GetGroupMembers(groupID string, filter *Filter) ([]UserGroupRelation, int, error) {
 	t.ArgsIn[GetGroupMembersMethod][0] = groupID
 	var members []UserGroupRelation
@@ -389,6 +505,20 @@ func (t TestRepo) IsAttachedToGroup(groupID string, policyID string) (bool, erro
 	return isAttached, err
 }
 
+func (t TestRepo) FilterAttachedToGroup(groupID string, policyIDs []string) ([]string, error) {
+	t.ArgsIn[FilterAttachedToGroupMethod][0] = groupID
+	t.ArgsIn[FilterAttachedToGroupMethod][1] = policyIDs
+	var ids []string
+	if t.ArgsOut[FilterAttachedToGroupMethod][0] != nil {
+		ids = t.ArgsOut[FilterAttachedToGroupMethod][0].([]string)
+	}
+	var err error
+	if t.ArgsOut[FilterAttachedToGroupMethod][1] != nil {
+		err = t.ArgsOut[FilterAttachedToGroupMethod][1].(error)
+	}
+	return ids, err
+}
+
 func (t TestRepo) GetAttachedPolicies(groupID string, filter *Filter) ([]PolicyGroupRelation, int, error) {
 	t.ArgsIn[GetAttachedPoliciesMethod][0] = groupID
 	var policies []PolicyGroupRelation
@@ -459,6 +589,16 @@ func (t TestRepo) AddMember(userID string, groupID string) error {
 	return err
 }
 
+func (t TestRepo) AddMembers(userIDs []string, groupID string) error {
+	t.ArgsIn[AddMembersMethod][0] = userIDs
+	t.ArgsIn[AddMembersMethod][1] = groupID
+	var err error
+	if t.ArgsOut[AddMembersMethod][0] != nil {
+		err = t.ArgsOut[AddMembersMethod][0].(error)
+	}
+	return err
+}
+
 func (t TestRepo) RemoveMember(userID string, groupID string) error {
 	t.ArgsIn[RemoveMemberMethod][0] = userID
 	t.ArgsIn[RemoveMemberMethod][1] = groupID
@@ -469,6 +609,16 @@ func (t TestRepo) RemoveMember(userID string, groupID string) error {
 	return err
 }
 
+func (t TestRepo) RemoveMembers(userIDs []string, groupID string) error {
+	t.ArgsIn[RemoveMembersMethod][0] = userIDs
+	t.ArgsIn[RemoveMembersMethod][1] = groupID
+	var err error
+	if t.ArgsOut[RemoveMembersMethod][0] != nil {
+		err = t.ArgsOut[RemoveMembersMethod][0].(error)
+	}
+	return err
+}
+
 func (t TestRepo) UpdateGroup(group Group) (*Group, error) {
 	t.ArgsIn[UpdateGroupMethod][0] = group
 
@@ -492,6 +642,16 @@ func (t TestRepo) AttachPolicy(groupID string, policyID string) error {
 	}
 	return err
 }
+
+func (t TestRepo) AttachPolicies(groupID string, policyIDs []string) error {
+	t.ArgsIn[AttachPoliciesMethod][0] = groupID
+	t.ArgsIn[AttachPoliciesMethod][1] = policyIDs
+	var err error
+	if t.ArgsOut[AttachPoliciesMethod][0] != nil {
+		err = t.ArgsOut[AttachPoliciesMethod][0].(error)
+	}
+	return err
+}
 func (t TestRepo) DetachPolicy(groupID string, policyID string) error {
 	t.ArgsIn[DetachPolicyMethod][0] = groupID
 	t.ArgsIn[DetachPolicyMethod][1] = policyID
@@ -502,6 +662,16 @@ func (t TestRepo) DetachPolicy(groupID string, policyID string) error {
 	return err
 }
 
+func (t TestRepo) DetachPolicies(groupID string, policyIDs []string) error {
+	t.ArgsIn[DetachPoliciesMethod][0] = groupID
+	t.ArgsIn[DetachPoliciesMethod][1] = policyIDs
+	var err error
+	if t.ArgsOut[DetachPoliciesMethod][0] != nil {
+		err = t.ArgsOut[DetachPoliciesMethod][0].(error)
+	}
+	return err
+}
+
 //////////////////
 // Policy repo
 //////////////////
@@ -523,6 +693,20 @@ func (t TestRepo) GetPolicyByName(org string, name string) (*Policy, error) {
 	return policy, err
 }
 
+func (t TestRepo) GetPoliciesByNames(org string, names []string) ([]Policy, error) {
+	t.ArgsIn[GetPoliciesByNamesMethod][0] = org
+	t.ArgsIn[GetPoliciesByNamesMethod][1] = names
+	var policies []Policy
+	if t.ArgsOut[GetPoliciesByNamesMethod][0] != nil {
+		policies = t.ArgsOut[GetPoliciesByNamesMethod][0].([]Policy)
+	}
+	var err error
+	if t.ArgsOut[GetPoliciesByNamesMethod][1] != nil {
+		err = t.ArgsOut[GetPoliciesByNamesMethod][1].(error)
+	}
+	return policies, err
+}
+
 func (t TestRepo) AddPolicy(policy Policy) (*Policy, error) {
 	t.ArgsIn[AddPolicyMethod][0] = policy
 	var created *Policy
@@ -756,6 +940,218 @@ func (t TestRepo) RemoveOidcProvider(id string) error {
 	return err
 }
 
+///////////////////////////
+// Webhook repo
+//////////////////////////
+
+func (t TestRepo) AddWebhook(webhook Webhook) (*Webhook, error) {
+	t.ArgsIn[AddWebhookMethod][0] = webhook
+	var created *Webhook
+	if t.ArgsOut[AddWebhookMethod][0] != nil {
+		created = t.ArgsOut[AddWebhookMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[AddWebhookMethod][1] != nil {
+		err = t.ArgsOut[AddWebhookMethod][1].(error)
+	}
+	return created, err
+}
+
+func (t TestRepo) GetWebhookByName(name string) (*Webhook, error) {
+	t.ArgsIn[GetWebhookByNameMethod][0] = name
+	if specialFunc, ok := t.SpecialFuncs[GetWebhookByNameMethod].(func(name string) (*Webhook, error)); ok && specialFunc != nil {
+		return specialFunc(name)
+	}
+	var webhook *Webhook
+	if t.ArgsOut[GetWebhookByNameMethod][0] != nil {
+		webhook = t.ArgsOut[GetWebhookByNameMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookByNameMethod][1] != nil {
+		err = t.ArgsOut[GetWebhookByNameMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestRepo) GetWebhooksFiltered(filter *Filter) ([]Webhook, int, error) {
+	t.ArgsIn[GetWebhooksFilteredMethod][0] = filter
+
+	var webhooks []Webhook
+	if t.ArgsOut[GetWebhooksFilteredMethod][0] != nil {
+		webhooks = t.ArgsOut[GetWebhooksFilteredMethod][0].([]Webhook)
+	}
+	var total int
+	if t.ArgsOut[GetWebhooksFilteredMethod][1] != nil {
+		total = t.ArgsOut[GetWebhooksFilteredMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetWebhooksFilteredMethod][2] != nil {
+		err = t.ArgsOut[GetWebhooksFilteredMethod][2].(error)
+	}
+	return webhooks, total, err
+}
+
+func (t TestRepo) UpdateWebhook(webhook Webhook) (*Webhook, error) {
+	t.ArgsIn[UpdateWebhookMethod][0] = webhook
+
+	var updated *Webhook
+	if t.ArgsOut[UpdateWebhookMethod][0] != nil {
+		updated = t.ArgsOut[UpdateWebhookMethod][0].(*Webhook)
+	}
+	var err error
+	if t.ArgsOut[UpdateWebhookMethod][1] != nil {
+		err = t.ArgsOut[UpdateWebhookMethod][1].(error)
+	}
+	return updated, err
+}
+
+func (t TestRepo) RemoveWebhook(id string) error {
+	t.ArgsIn[RemoveWebhookMethod][0] = id
+	var err error
+	if t.ArgsOut[RemoveWebhookMethod][0] != nil {
+		err = t.ArgsOut[RemoveWebhookMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) AddWebhookDelivery(delivery WebhookDelivery) error {
+	t.ArgsIn[AddWebhookDeliveryMethod][0] = delivery
+	var err error
+	if t.ArgsOut[AddWebhookDeliveryMethod][0] != nil {
+		err = t.ArgsOut[AddWebhookDeliveryMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) GetWebhookDeliveries(webhookID string, filter *Filter) ([]WebhookDelivery, int, error) {
+	t.ArgsIn[GetWebhookDeliveriesMethod][0] = webhookID
+	t.ArgsIn[GetWebhookDeliveriesMethod][1] = filter
+
+	var deliveries []WebhookDelivery
+	if t.ArgsOut[GetWebhookDeliveriesMethod][0] != nil {
+		deliveries = t.ArgsOut[GetWebhookDeliveriesMethod][0].([]WebhookDelivery)
+	}
+	var total int
+	if t.ArgsOut[GetWebhookDeliveriesMethod][1] != nil {
+		total = t.ArgsOut[GetWebhookDeliveriesMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookDeliveriesMethod][2] != nil {
+		err = t.ArgsOut[GetWebhookDeliveriesMethod][2].(error)
+	}
+	return deliveries, total, err
+}
+
+// API key repo
+
+func (t TestRepo) AddApiKey(apiKey ApiKey) (*ApiKey, error) {
+	t.ArgsIn[AddApiKeyMethod][0] = apiKey
+
+	var created *ApiKey
+	if t.ArgsOut[AddApiKeyMethod][0] != nil {
+		created = t.ArgsOut[AddApiKeyMethod][0].(*ApiKey)
+	}
+	var err error
+	if t.ArgsOut[AddApiKeyMethod][1] != nil {
+		err = t.ArgsOut[AddApiKeyMethod][1].(error)
+	}
+	return created, err
+}
+
+func (t TestRepo) GetApiKeyByID(id string) (*ApiKey, error) {
+	t.ArgsIn[GetApiKeyByIDMethod][0] = id
+
+	var apiKey *ApiKey
+	if t.ArgsOut[GetApiKeyByIDMethod][0] != nil {
+		apiKey = t.ArgsOut[GetApiKeyByIDMethod][0].(*ApiKey)
+	}
+	var err error
+	if t.ArgsOut[GetApiKeyByIDMethod][1] != nil {
+		err = t.ArgsOut[GetApiKeyByIDMethod][1].(error)
+	}
+	return apiKey, err
+}
+
+func (t TestRepo) GetApiKeysByUserID(userID string, filter *Filter) ([]ApiKey, int, error) {
+	t.ArgsIn[GetApiKeysByUserIDMethod][0] = userID
+	t.ArgsIn[GetApiKeysByUserIDMethod][1] = filter
+
+	var apiKeys []ApiKey
+	if t.ArgsOut[GetApiKeysByUserIDMethod][0] != nil {
+		apiKeys = t.ArgsOut[GetApiKeysByUserIDMethod][0].([]ApiKey)
+	}
+	var total int
+	if t.ArgsOut[GetApiKeysByUserIDMethod][1] != nil {
+		total = t.ArgsOut[GetApiKeysByUserIDMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[GetApiKeysByUserIDMethod][2] != nil {
+		err = t.ArgsOut[GetApiKeysByUserIDMethod][2].(error)
+	}
+	return apiKeys, total, err
+}
+
+func (t TestRepo) UpdateApiKey(apiKey ApiKey) (*ApiKey, error) {
+	t.ArgsIn[UpdateApiKeyMethod][0] = apiKey
+
+	var updated *ApiKey
+	if t.ArgsOut[UpdateApiKeyMethod][0] != nil {
+		updated = t.ArgsOut[UpdateApiKeyMethod][0].(*ApiKey)
+	}
+	var err error
+	if t.ArgsOut[UpdateApiKeyMethod][1] != nil {
+		err = t.ArgsOut[UpdateApiKeyMethod][1].(error)
+	}
+	return updated, err
+}
+
+func (t TestRepo) UpdateApiKeyLastUsed(id string, lastUsedAt time.Time) error {
+	t.ArgsIn[UpdateApiKeyLastUsedMethod][0] = id
+	t.ArgsIn[UpdateApiKeyLastUsedMethod][1] = lastUsedAt
+	var err error
+	if t.ArgsOut[UpdateApiKeyLastUsedMethod][0] != nil {
+		err = t.ArgsOut[UpdateApiKeyLastUsedMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestRepo) RemoveApiKey(id string) error {
+	t.ArgsIn[RemoveApiKeyMethod][0] = id
+	var err error
+	if t.ArgsOut[RemoveApiKeyMethod][0] != nil {
+		err = t.ArgsOut[RemoveApiKeyMethod][0].(error)
+	}
+	return err
+}
+
+// Change repo
+
+func (t TestRepo) GetChanges(since int64, limit int) ([]Change, error) {
+	t.ArgsIn[GetChangesMethod][0] = since
+	t.ArgsIn[GetChangesMethod][1] = limit
+	var changes []Change
+	if t.ArgsOut[GetChangesMethod][0] != nil {
+		changes = t.ArgsOut[GetChangesMethod][0].([]Change)
+	}
+	var err error
+	if t.ArgsOut[GetChangesMethod][1] != nil {
+		err = t.ArgsOut[GetChangesMethod][1].(error)
+	}
+	return changes, err
+}
+
+func (t TestRepo) GetLastChangeSeq() (int64, error) {
+	var seq int64
+	if t.ArgsOut[GetLastChangeSeqMethod][0] != nil {
+		seq = t.ArgsOut[GetLastChangeSeqMethod][0].(int64)
+	}
+	var err error
+	if t.ArgsOut[GetLastChangeSeqMethod][1] != nil {
+		err = t.ArgsOut[GetLastChangeSeqMethod][1].(error)
+	}
+	return seq, err
+}
+
 // Private helper methods
 
 func getRandomString(runeValue []rune, n int) string {
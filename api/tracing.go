@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/Tecsisa/foulkon/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts the span of a WorkerAPI method as child of the request span. Request context
+// is updated to the new span, so nested API calls are its children, and the returned API
+// has its repositories tracing their operations under it.
+func (api WorkerAPI) startSpan(requestInfo *RequestInfo, method string) (WorkerAPI, trace.Span) {
+	ctx, span := tracing.Start(requestInfo.Context, "WorkerAPI."+method)
+	span.SetAttributes(
+		attribute.String("foulkon.request_id", requestInfo.RequestID),
+		attribute.String("foulkon.user", requestInfo.Identifier),
+	)
+	requestInfo.Context = ctx
+
+	if repo, ok := api.UserRepo.(ContextRepo); ok {
+		api.UserRepo = repo.WithContext(ctx).(UserRepo)
+	}
+	if repo, ok := api.GroupRepo.(ContextRepo); ok {
+		api.GroupRepo = repo.WithContext(ctx).(GroupRepo)
+	}
+	if repo, ok := api.PolicyRepo.(ContextRepo); ok {
+		api.PolicyRepo = repo.WithContext(ctx).(PolicyRepo)
+	}
+	if repo, ok := api.ProxyRepo.(ContextRepo); ok {
+		api.ProxyRepo = repo.WithContext(ctx).(ProxyRepo)
+	}
+	if repo, ok := api.AuthOidcRepo.(ContextRepo); ok {
+		api.AuthOidcRepo = repo.WithContext(ctx).(AuthOidcRepo)
+	}
+	if repo, ok := api.WebhookRepo.(ContextRepo); ok {
+		api.WebhookRepo = repo.WithContext(ctx).(WebhookRepo)
+	}
+	if repo, ok := api.ApiKeyRepo.(ContextRepo); ok {
+		api.ApiKeyRepo = repo.WithContext(ctx).(ApiKeyRepo)
+	}
+	if repo, ok := api.ChangeRepo.(ContextRepo); ok {
+		api.ChangeRepo = repo.WithContext(ctx).(ChangeRepo)
+	}
+
+	return api, span
+}
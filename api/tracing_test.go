@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// contextTestRepo is a TestRepo that keeps the context of its traced operations
+type contextTestRepo struct {
+	*TestRepo
+	ctx context.Context
+}
+
+func (r contextTestRepo) WithContext(ctx context.Context) interface{} {
+	r.ctx = ctx
+	return r
+}
+
+func TestWorkerAPI_startSpan(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	requestCtx, requestSpan := otel.Tracer("test").Start(context.Background(), "request")
+	defer requestSpan.End()
+
+	testcases := map[string]struct {
+		contextRepo bool
+	}{
+		"OKCaseContextRepo": {
+			contextRepo: true,
+		},
+		"OKCaseRepo": {
+			contextRepo: false,
+		},
+	}
+
+	for n, test := range testcases {
+		testAPI := makeTestAPI(makeTestRepo())
+		if test.contextRepo {
+			repo := contextTestRepo{TestRepo: makeTestRepo()}
+			testAPI.UserRepo = repo
+			testAPI.ChangeRepo = repo
+		}
+		requestInfo := RequestInfo{
+			Identifier: "123456",
+			RequestID:  "requestID",
+			Context:    requestCtx,
+		}
+
+		tracedAPI, span := testAPI.startSpan(&requestInfo, "AddUser")
+		span.End()
+
+		// Check span is child of request span and request context is updated
+		assert.Equal(t, requestSpan.SpanContext().SpanID(), span.(sdktrace.ReadOnlySpan).Parent().SpanID(), "Error in test case %v", n)
+		assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(requestInfo.Context), "Error in test case %v", n)
+
+		// Check repositories
+		if test.contextRepo {
+			assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(tracedAPI.UserRepo.(contextTestRepo).ctx), "Error in test case %v", n)
+			assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(tracedAPI.ChangeRepo.(contextTestRepo).ctx), "Error in test case %v", n)
+		} else {
+			assert.Equal(t, testAPI.UserRepo, tracedAPI.UserRepo, "Error in test case %v", n)
+		}
+		assert.Equal(t, testAPI.GroupRepo, tracedAPI.GroupRepo, "Error in test case %v", n)
+	}
+}
@@ -2,22 +2,33 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Tecsisa/foulkon/database"
 	"github.com/satori/go.uuid"
 )
 
+const (
+	// States of users to filter by
+	USER_STATE_ENABLED  = "enabled"
+	USER_STATE_DISABLED = "disabled"
+)
+
 // TYPE DEFINITIONS
 
 // User domain
 type User struct {
-	ID         string    `json:"id,omitempty"`
-	ExternalID string    `json:"externalId,omitempty"`
-	Path       string    `json:"path,omitempty"`
-	Urn        string    `json:"urn,omitempty"`
-	CreateAt   time.Time `json:"createAt,omitempty"`
-	UpdateAt   time.Time `json:"updateAt,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	ExternalID string            `json:"externalId,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Urn        string            `json:"urn,omitempty"`
+	CreateAt   time.Time         `json:"createAt,omitempty"`
+	UpdateAt   time.Time         `json:"updateAt,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Disabled users keep their data and memberships, but they are denied everything
+	Enabled        bool   `json:"enabled"`
+	DisabledReason string `json:"disabledReason,omitempty"`
 }
 
 type UserGroups struct {
@@ -27,17 +38,25 @@ type UserGroups struct {
 }
 
 func (u User) String() string {
-	return fmt.Sprintf("[id: %v, externalId: %v, path: %v, urn: %v, createAt: %v]",
-		u.ID, u.ExternalID, u.Path, u.Urn, u.CreateAt.Format("2006-01-02 15:04:05 MST"))
+	return fmt.Sprintf("[id: %v, externalId: %v, path: %v, urn: %v, createAt: %v, attributes: %v, enabled: %v, disabledReason: %v]",
+		u.ID, u.ExternalID, u.Path, u.Urn, u.CreateAt.Format("2006-01-02 15:04:05 MST"), u.Attributes, u.Enabled, u.DisabledReason)
 }
 
 func (u User) GetUrn() string {
 	return u.Urn
 }
 
+// IsServiceAccount checks if user is a service account, the only users that can have API keys
+func (u User) IsServiceAccount() bool {
+	return strings.HasPrefix(u.Path, SERVICE_ACCOUNT_PATH)
+}
+
 // USER API IMPLEMENTATION
 
-func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path string) (*User, error) {
+func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path string, attributes map[string]string) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "AddUser")
+	defer span.End()
+
 	// Validate fields
 	if !IsValidUserExternalID(externalId) {
 		return nil, &Error{
@@ -51,8 +70,11 @@ func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path st
 			Message: fmt.Sprintf("Invalid parameter: path %v", path),
 		}
 	}
+	if err := AreValidAttributes(attributes); err != nil {
+		return nil, err
+	}
 
-	user := createUser(externalId, path)
+	user := createUser(externalId, path, attributes)
 
 	// Check restrictions
 	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, user.Urn, USER_ACTION_CREATE_USER, []User{user})
@@ -89,6 +111,7 @@ func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path st
 				}
 			}
 			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User created %+v", createdUser))
+			api.notifyEvent(requestInfo, EVENT_USER_CREATED, createdUser.Urn, createdUser)
 			return createdUser, nil
 		default: // Unexpected error
 			return nil, &Error{
@@ -105,6 +128,9 @@ func (api WorkerAPI) AddUser(requestInfo RequestInfo, externalId string, path st
 }
 
 func (api WorkerAPI) GetUserByExternalID(requestInfo RequestInfo, externalId string) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "GetUserByExternalID")
+	defer span.End()
+
 	if !IsValidUserExternalID(externalId) {
 		return nil, &Error{
 			Code:    INVALID_PARAMETER_ERROR,
@@ -149,30 +175,10 @@ func (api WorkerAPI) GetUserByExternalID(requestInfo RequestInfo, externalId str
 }
 
 func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
-	// Check parameters
-	var total int
-	orderByValidColumns := api.UserRepo.OrderByValidColumns(USER_ACTION_LIST_USERS)
-	err := validateFilter(filter, orderByValidColumns)
-	if err != nil {
-		return nil, total, err
-	}
+	api, span := api.startSpan(&requestInfo, "ListUsers")
+	defer span.End()
 
-	// Retrieve users with specified path prefix
-	users, total, err := api.UserRepo.GetUsersFiltered(filter)
-
-	// Error handling
-	if err != nil {
-		//Transform to DB error
-		dbError := err.(*database.Error)
-		return nil, total, &Error{
-			Code:    UNKNOWN_API_ERROR,
-			Message: dbError.Message,
-		}
-	}
-
-	// Check restrictions
-	urnPrefix := GetUrnPrefix("", RESOURCE_USER, filter.PathPrefix)
-	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, urnPrefix, USER_ACTION_LIST_USERS, users)
+	usersFiltered, total, err := api.listUsers(requestInfo, filter, USER_ACTION_LIST_USERS)
 	if err != nil {
 		return nil, total, err
 	}
@@ -186,13 +192,27 @@ func (api WorkerAPI) ListUsers(requestInfo RequestInfo, filter *Filter) ([]strin
 	return externalIds, total, nil
 }
 
-func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newPath string) (*User, error) {
+func (api WorkerAPI) ListUsersExpanded(requestInfo RequestInfo, filter *Filter) ([]User, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListUsersExpanded")
+	defer span.End()
+
+	// Full users are only returned when they can be retrieved too
+	return api.listUsers(requestInfo, filter, USER_ACTION_LIST_USERS, USER_ACTION_GET_USER)
+}
+
+func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newPath string, newAttributes map[string]string) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "UpdateUser")
+	defer span.End()
+
 	if !IsValidPath(newPath) {
 		return nil, &Error{
 			Code:    INVALID_PARAMETER_ERROR,
 			Message: fmt.Sprintf("Invalid parameter: path %v", newPath),
 		}
 	}
+	if err := AreValidAttributes(newAttributes); err != nil {
+		return nil, err
+	}
 
 	// Call repo to retrieve the user
 	oldUser, err := api.GetUserByExternalID(requestInfo, externalId)
@@ -230,13 +250,21 @@ func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newP
 		}
 	}
 
+	// Attributes are kept if new attributes aren't specified
+	if newAttributes == nil {
+		newAttributes = oldUser.Attributes
+	}
+
 	user := User{
-		ID:         oldUser.ID,
-		ExternalID: oldUser.ExternalID,
-		Path:       newPath,
-		CreateAt:   oldUser.CreateAt,
-		UpdateAt:   time.Now().UTC(),
-		Urn:        auxUser.Urn,
+		ID:             oldUser.ID,
+		ExternalID:     oldUser.ExternalID,
+		Path:           newPath,
+		CreateAt:       oldUser.CreateAt,
+		UpdateAt:       time.Now().UTC(),
+		Urn:            auxUser.Urn,
+		Attributes:     newAttributes,
+		Enabled:        oldUser.Enabled,
+		DisabledReason: oldUser.DisabledReason,
 	}
 
 	updatedUser, err := api.UserRepo.UpdateUser(user)
@@ -252,11 +280,36 @@ func (api WorkerAPI) UpdateUser(requestInfo RequestInfo, externalId string, newP
 	}
 
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User updated from %+v to %+v", oldUser, updatedUser))
+	api.notifyEvent(requestInfo, EVENT_USER_UPDATED, updatedUser.Urn, updatedUser)
 	return updatedUser, nil
 
 }
 
+func (api WorkerAPI) SuspendUser(requestInfo RequestInfo, externalId string, reason string) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "SuspendUser")
+	defer span.End()
+
+	if len(reason) > MAX_REASON_LENGTH {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: reason, max length allowed: %v", MAX_REASON_LENGTH),
+		}
+	}
+
+	return api.setUserEnabled(requestInfo, externalId, false, reason, USER_ACTION_SUSPEND_USER, EVENT_USER_SUSPENDED)
+}
+
+func (api WorkerAPI) ReactivateUser(requestInfo RequestInfo, externalId string) (*User, error) {
+	api, span := api.startSpan(&requestInfo, "ReactivateUser")
+	defer span.End()
+
+	return api.setUserEnabled(requestInfo, externalId, true, "", USER_ACTION_REACTIVATE_USER, EVENT_USER_REACTIVATED)
+}
+
 func (api WorkerAPI) RemoveUser(requestInfo RequestInfo, externalId string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveUser")
+	defer span.End()
+
 	// Call repo to retrieve the user
 	user, err := api.GetUserByExternalID(requestInfo, externalId)
 	if err != nil {
@@ -288,10 +341,14 @@ func (api WorkerAPI) RemoveUser(requestInfo RequestInfo, externalId string) erro
 		}
 	}
 	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User deleted %+v", user))
+	api.notifyEvent(requestInfo, EVENT_USER_DELETED, user.Urn, user)
 	return nil
 }
 
 func (api WorkerAPI) ListGroupsByUser(requestInfo RequestInfo, filter *Filter) ([]UserGroups, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListGroupsByUser")
+	defer span.End()
+
 	// Check parameters
 	var total int
 	orderByValidColumns := api.UserRepo.OrderByValidColumns(USER_ACTION_LIST_GROUPS_FOR_USER)
@@ -346,7 +403,46 @@ func (api WorkerAPI) ListGroupsByUser(requestInfo RequestInfo, filter *Filter) (
 
 // PRIVATE HELPER METHODS
 
-func createUser(externalId string, path string) User {
+// listUsers retrieves users filtered, keeping the ones authorized for all the actions
+func (api WorkerAPI) listUsers(requestInfo RequestInfo, filter *Filter, actions ...string) ([]User, int, error) {
+	// Check parameters
+	var total int
+	orderByValidColumns := api.UserRepo.OrderByValidColumns(USER_ACTION_LIST_USERS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Retrieve users with specified path prefix
+	users, total, err := api.UserRepo.GetUsersFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions
+	urnPrefix := GetUrnPrefix("", RESOURCE_USER, filter.PathPrefix)
+	for i, action := range actions {
+		users, err = api.GetAuthorizedUsers(requestInfo, urnPrefix, action, users)
+		if err != nil {
+			// Denying a further action leaves nothing to return, instead of failing the list
+			if apiError, ok := err.(*Error); i > 0 && ok && apiError.Code == UNAUTHORIZED_RESOURCES_ERROR {
+				return []User{}, total, nil
+			}
+			return nil, total, err
+		}
+	}
+
+	return users, total, nil
+}
+
+func createUser(externalId string, path string, attributes map[string]string) User {
 	urn := CreateUrn("", RESOURCE_USER, path, externalId)
 	user := User{
 		ID:         uuid.NewV4().String(),
@@ -355,7 +451,53 @@ func createUser(externalId string, path string) User {
 		CreateAt:   time.Now().UTC(),
 		UpdateAt:   time.Now().UTC(),
 		Urn:        urn,
+		Attributes: attributes,
+		Enabled:    true,
 	}
 
 	return user
 }
+
+// Enable or disable a user, checking restrictions of the action
+func (api WorkerAPI) setUserEnabled(requestInfo RequestInfo, externalId string, enabled bool, reason string,
+	action string, eventType string) (*User, error) {
+	// Call repo to retrieve the user
+	oldUser, err := api.GetUserByExternalID(requestInfo, externalId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	usersFiltered, err := api.GetAuthorizedUsers(requestInfo, oldUser.Urn, action, []User{*oldUser})
+	if err != nil {
+		return nil, err
+	}
+	if len(usersFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, oldUser.Urn),
+		}
+	}
+
+	user := *oldUser
+	user.Enabled = enabled
+	user.DisabledReason = reason
+	user.UpdateAt = time.Now().UTC()
+
+	updatedUser, err := api.UserRepo.UpdateUser(user)
+
+	// Check unexpected DB error
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("User updated from %+v to %+v", oldUser, updatedUser))
+	api.notifyEvent(requestInfo, eventType, updatedUser.Urn, updatedUser)
+	return updatedUser, nil
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Tecsisa/foulkon/database"
@@ -13,6 +15,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 		requestInfo RequestInfo
 		externalID  string
 		path        string
+		attributes  map[string]string
 		// Expected result
 		expectedUser *User
 		wantError    error
@@ -36,6 +39,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodErr: &database.Error{
 				Code:    database.USER_NOT_FOUND,
@@ -54,6 +58,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/example/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodSpecialFunc: func(id string) (*User, error) {
 				if id == "123456" {
@@ -62,6 +67,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 						ExternalID: "000",
 						Path:       "/path/",
 						Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+						Enabled:    true,
 					}, nil
 				}
 				return nil, &database.Error{
@@ -102,6 +108,43 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				},
 			},
 		},
+		"OKCaseAdminWithAttributes": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			path:       "/example/",
+			attributes: map[string]string{
+				"department": "backend",
+				"clearance":  "high",
+			},
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Attributes: map[string]string{
+					"department": "backend",
+					"clearance":  "high",
+				},
+				Enabled: true,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code:    database.USER_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+		"ErrorCaseInvalidAttributeKey": {
+			externalID: "1234",
+			path:       "/example/",
+			attributes: map[string]string{
+				"cost center": "1000",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: attribute key cost center",
+			},
+		},
 		"ErrorCaseInvalidExtID": {
 			externalID: "*%~#@|",
 			wantError: &Error{
@@ -162,6 +205,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -220,6 +264,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
@@ -258,6 +303,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodErr: &database.Error{
 				Code:    database.USER_NOT_FOUND,
@@ -283,6 +329,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 				ExternalID: "123456",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodErr: &database.Error{
 				Code:    database.INTERNAL_ERROR,
@@ -314,7 +361,7 @@ func TestAuthAPI_AddUser(t *testing.T) {
 		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
 		testRepo.ArgsOut[AddUserMethod][0] = testcase.expectedUser
 		testRepo.ArgsOut[AddUserMethod][1] = testcase.addUserMethodErr
-		user, err := testAPI.AddUser(testcase.requestInfo, testcase.externalID, testcase.path)
+		user, err := testAPI.AddUser(testcase.requestInfo, testcase.externalID, testcase.path, testcase.attributes)
 		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
 	}
 
@@ -346,12 +393,14 @@ func TestAuthAPI_GetUserByExternalID(t *testing.T) {
 				ExternalID: "123",
 				Path:       "/users/test/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/users/test/", "123"),
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
 				ExternalID: "123",
 				Path:       "/users/test/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/users/test/", "123"),
+				Enabled:    true,
 			},
 		},
 		"OKCase": {
@@ -365,12 +414,14 @@ func TestAuthAPI_GetUserByExternalID(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "000",
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -452,6 +503,7 @@ func TestAuthAPI_GetUserByExternalID(t *testing.T) {
 				ExternalID: "123",
 				Path:       "/users/test/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/users/test/", "123"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseGetUserNotAllowed": {
@@ -469,6 +521,7 @@ func TestAuthAPI_GetUserByExternalID(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -526,6 +579,7 @@ func TestAuthAPI_GetUserByExternalID(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -622,6 +676,7 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getUsersFilteredMethodResult: []User{
 				{
@@ -697,6 +752,7 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getUsersFilteredMethodResult: []User{
 				{
@@ -818,6 +874,7 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getUsersFilteredMethodResult: []User{
 				{
@@ -870,6 +927,7 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getUsersFilteredMethodResult: []User{
 				{
@@ -957,21 +1015,22 @@ func TestAuthAPI_ListUsers(t *testing.T) {
 
 }
 
-func TestAuthAPI_UpdateUser(t *testing.T) {
+func TestAuthAPI_ListUsersExpanded(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		requestInfo RequestInfo
-		externalID  string
-		newPath     string
+		filter      *Filter
 		// Expected result
-		expectedUser *User
-		wantError    error
+		expectedResult []User
+		totalResult    int
+		wantError      error
 		// Manager Results
-		getUserByExternalIDMethodResult *User
+		getUsersFilteredMethodResult    []User
 		getGroupsByUserIDMethodResult   []TestUserGroupRelation
 		getAttachedPoliciesMethodResult []TestPolicyGroupRelation
+		getUserByExternalIDMethodResult *User
 		// API Errors
-		updateUserMethodErr          error
+		GetUsersFilteredMethodErr    error
 		getUserByExternalIDMethodErr error
 	}{
 		"OKCaseAdmin": {
@@ -979,19 +1038,38 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				Identifier: "123456",
 				Admin:      true,
 			},
-			externalID: "1234",
-			newPath:    "/example2/",
-			expectedUser: &User{
-				ID:         "543210",
-				ExternalID: "1234",
-				Path:       "/example2/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+			filter: &Filter{
+				PathPrefix: "",
+				Limit:      0,
 			},
-			getUserByExternalIDMethodResult: &User{
-				ID:         "543210",
-				ExternalID: "1234",
-				Path:       "/example/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+			expectedResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
+			},
+			totalResult: 2,
+			getUsersFilteredMethodResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
 			},
 		},
 		"OKCase": {
@@ -999,19 +1077,28 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				Identifier: "123456",
 				Admin:      false,
 			},
-			externalID: "000",
-			newPath:    "/newpath/",
-			expectedUser: &User{
-				ID:         "000",
-				ExternalID: "000",
-				Path:       "/newpath/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/newpath/", "000"),
+			filter: &testFilter,
+			expectedResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
 			},
+			totalResult: 2,
 			getUserByExternalIDMethodResult: &User{
 				ID:         "000",
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1023,6 +1110,20 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 					},
 				},
 			},
+			getUsersFilteredMethodResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
+			},
 			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
@@ -1031,11 +1132,19 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 						Path: "/path/",
 						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
 						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_LIST_USERS,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
 							{
 								Effect: "allow",
 								Actions: []string{
 									USER_ACTION_GET_USER,
-									USER_ACTION_UPDATE_USER,
 								},
 								Resources: []string{
 									GetUrnPrefix("", RESOURCE_USER, ""),
@@ -1046,96 +1155,27 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseInvalidPath": {
-			newPath: "/**%%/*123",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: path /**%%/*123",
-			},
-		},
-		"ErrorCaseNoPath": {
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: path ",
-			},
-		},
-		"ErrorCaseInvalidExtID": {
-			externalID: "*%~#@|",
-			newPath:    "/example/",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: externalId *%~#@|",
-			},
-		},
-		"ErrorCaseNoID": {
-			newPath: "/example/",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: externalId ",
-			},
-		},
-		"ErrorCaseNoAuth": {
-			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
-			},
-			externalID: "1234",
-			newPath:    "/example/",
-			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
-			},
-			getUserByExternalIDMethodResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/example/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
-			},
-		},
-		"ErrorCaseUserNotFound": {
-			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
-			},
-			externalID: "1234",
-			newPath:    "/example/",
-			wantError: &Error{
-				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
-			},
-			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.USER_NOT_FOUND,
-			},
-		},
-		"ErrorCaseGetUserExtIDDBErr": {
-			requestInfo: RequestInfo{
-				Identifier: "123456",
-				Admin:      true,
-			},
-			externalID: "1234",
-			newPath:    "/example/",
-			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
-			},
-			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.INTERNAL_ERROR,
-			},
-		},
-		"ErrorCaseUpdateNotAllowed": {
+		"OKCaseGetAllowedForSomeUsers": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
 			},
-			externalID: "000",
-			newPath:    "/newpath/",
-			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/path/000",
+			filter: &testFilter,
+			expectedResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
 			},
+			totalResult: 2,
 			getUserByExternalIDMethodResult: &User{
 				ID:         "000",
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1147,6 +1187,20 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 					},
 				},
 			},
+			getUsersFilteredMethodResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
+			},
 			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
@@ -1155,13 +1209,22 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 						Path: "/path/",
 						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
 						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_LIST_USERS,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
 							{
 								Effect: "allow",
 								Actions: []string{
 									USER_ACTION_GET_USER,
 								},
 								Resources: []string{
-									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+									GetUrnPrefix("", RESOURCE_USER, "/example/test/"),
 								},
 							},
 						},
@@ -1169,22 +1232,20 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseNoPermissions": {
+		"OKCaseGetNotAllowed": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
 			},
-			externalID: "000",
-			newPath:    "/newpath/",
-			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/path/000",
-			},
+			filter:         &testFilter,
+			expectedResult: []User{},
+			totalResult:    2,
 			getUserByExternalIDMethodResult: &User{
 				ID:         "000",
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1196,16 +1257,543 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 					},
 				},
 			},
-			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+			getUsersFilteredMethodResult: []User{
 				{
-					Policy: &Policy{
-						ID:   "POLICY-USER-ID",
-						Name: "policyUser",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
-						Statements: &[]Statement{
-							{
-								Effect: "allow",
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_LIST_USERS,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseListNotAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			filter: &testFilter,
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/*",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getUsersFilteredMethodResult: []User{
+				{
+					ID:         "123",
+					ExternalID: "123",
+					Path:       "/example/test/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test/", "123"),
+				},
+				{
+					ID:         "321",
+					ExternalID: "321",
+					Path:       "/example/test2/",
+					Urn:        CreateUrn("", RESOURCE_USER, "/example/test2/", "321"),
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseFilterUsersDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				PathPrefix: "/example/",
+				Offset:     0,
+				Limit:      0,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			GetUsersFilteredMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDMethodResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesMethodResult
+		testRepo.ArgsOut[GetUsersFilteredMethod][0] = testcase.getUsersFilteredMethodResult
+		testRepo.ArgsOut[GetUsersFilteredMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetUsersFilteredMethod][2] = testcase.GetUsersFilteredMethodErr
+		users, total, err := testAPI.ListUsersExpanded(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedResult, users)
+		assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+	}
+}
+
+func TestAuthAPI_UpdateUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		requestInfo   RequestInfo
+		externalID    string
+		newPath       string
+		newAttributes map[string]string
+		// Expected result
+		expectedUser       *User
+		expectedAttributes map[string]string
+		wantError          error
+		// Manager Results
+		getUserByExternalIDMethodResult *User
+		getGroupsByUserIDMethodResult   []TestUserGroupRelation
+		getAttachedPoliciesMethodResult []TestPolicyGroupRelation
+		// API Errors
+		updateUserMethodErr          error
+		getUserByExternalIDMethodErr error
+	}{
+		"OKCaseAdminKeepAttributes": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			newPath:    "/example2/",
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example2/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example2/", "1234"),
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+				Enabled: true,
+			},
+			expectedAttributes: map[string]string{
+				"clearance": "high",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+				Enabled: true,
+			},
+		},
+		"OKCaseAdminNewAttributes": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			newPath:    "/example/",
+			newAttributes: map[string]string{
+				"clearance": "low",
+			},
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Attributes: map[string]string{
+					"clearance": "low",
+				},
+				Enabled: true,
+			},
+			expectedAttributes: map[string]string{
+				"clearance": "low",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+				Enabled: true,
+			},
+		},
+		"ErrorCaseInvalidAttributes": {
+			externalID: "1234",
+			newPath:    "/example/",
+			newAttributes: map[string]string{
+				"clearance:level": "high",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: attribute key clearance:level",
+			},
+		},
+		"OKCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			newPath:    "/example2/",
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example2/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
+			},
+		},
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			externalID: "000",
+			newPath:    "/newpath/",
+			expectedUser: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/newpath/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/newpath/", "000"),
+				Enabled:    true,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+									USER_ACTION_UPDATE_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseInvalidPath": {
+			newPath: "/**%%/*123",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: path /**%%/*123",
+			},
+		},
+		"ErrorCaseNoPath": {
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: path ",
+			},
+		},
+		"ErrorCaseInvalidExtID": {
+			externalID: "*%~#@|",
+			newPath:    "/example/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId *%~#@|",
+			},
+		},
+		"ErrorCaseNoID": {
+			newPath: "/example/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId ",
+			},
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			newPath:    "/example/",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			newPath:    "/example/",
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetUserExtIDDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			newPath:    "/example/",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseUpdateNotAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			externalID: "000",
+			newPath:    "/newpath/",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/path/000",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			externalID: "000",
+			newPath:    "/newpath/",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/path/000",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_UPDATE_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+							{
+								Effect: "deny",
+								Actions: []string{
+									USER_ACTION_UPDATE_USER,
+								},
+								Resources: []string{
+									CreateUrn("", RESOURCE_USER, "/path/", "000"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseGetNewPathNotAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			externalID: "000",
+			newPath:    "/newpath/",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/newpath/000",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "000",
+				ExternalID: "000",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
 								Actions: []string{
 									USER_ACTION_GET_USER,
 								},
@@ -1225,10 +1813,10 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 							{
 								Effect: "deny",
 								Actions: []string{
-									USER_ACTION_UPDATE_USER,
+									USER_ACTION_GET_USER,
 								},
 								Resources: []string{
-									CreateUrn("", RESOURCE_USER, "/path/", "000"),
+									CreateUrn("", RESOURCE_USER, "/newpath/", "000"),
 								},
 							},
 						},
@@ -1236,7 +1824,7 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseGetNewPathNotAllowed": {
+		"ErrorCaseNewPathNotAllowed": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
@@ -1252,6 +1840,7 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				ExternalID: "000",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1289,6 +1878,15 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
 								},
 							},
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/newpath/"),
+								},
+							},
 							{
 								Effect: "deny",
 								Actions: []string{
@@ -1303,24 +1901,197 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseNewPathNotAllowed": {
+		"ErrorCaseUpdateUserDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "123456",
+			newPath:    "/example/",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
+			},
+			updateUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDMethodResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesMethodResult
+		testRepo.ArgsOut[UpdateUserMethod][0] = testcase.expectedUser
+		testRepo.ArgsOut[UpdateUserMethod][1] = testcase.updateUserMethodErr
+		user, err := testAPI.UpdateUser(testcase.requestInfo, testcase.externalID, testcase.newPath, testcase.newAttributes)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
+		if testcase.expectedAttributes != nil {
+			assert.Equal(t, testcase.expectedAttributes, testRepo.ArgsIn[UpdateUserMethod][0].(User).Attributes, "Error in test case %v", x)
+		}
+	}
+
+}
+
+func TestAuthAPI_RemoveUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		externalID  string
+		// Expected result
+		wantError error
+		// Manager Results
+		getUserByExternalIDMethodResult *User
+		getGroupsByUserIDResult         []TestUserGroupRelation
+		getAttachedPoliciesResult       []TestPolicyGroupRelation
+		// API Errors
+		getUserByExternalIDMethodErr error
+		removeUserMethodErr          error
+	}{
+		"OKCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Enabled:    true,
+			},
+		},
+		"OKCase": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      false,
 			},
-			externalID: "000",
-			newPath:    "/newpath/",
+			externalID: "1234",
+			getUserByExternalIDMethodResult: &User{
+				ID:         "1234",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									USER_ACTION_GET_USER,
+									USER_ACTION_DELETE_USER,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseInvalidExtID": {
+			externalID: "*%~#@|",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId *%~#@|",
+			},
+		},
+		"ErrorCaseNoID": {
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId ",
+			},
+		},
+		"ErrorCaseNoAuth": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
 			wantError: &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:iam::user/newpath/000",
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
 			},
 			getUserByExternalIDMethodResult: &User{
-				ID:         "000",
-				ExternalID: "000",
+				ID:         "543210",
+				ExternalID: "12345",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
+			},
+		},
+		"ErrorCaseGetUserExtIDDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+		"ErrorCaseRemoveNotAllowedInPath": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "1234",
+				ExternalID: "1234",
 				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "000"),
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
-			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
+			getGroupsByUserIDResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
@@ -1330,7 +2101,7 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 					},
 				},
 			},
-			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
 						ID:   "POLICY-USER-ID",
@@ -1347,10 +2118,50 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
 								},
 							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "1234",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policyUser",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
 							{
 								Effect: "allow",
 								Actions: []string{
-									USER_ACTION_UPDATE_USER,
+									USER_ACTION_GET_USER,
 								},
 								Resources: []string{
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
@@ -1359,19 +2170,19 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 							{
 								Effect: "allow",
 								Actions: []string{
-									USER_ACTION_GET_USER,
+									USER_ACTION_DELETE_USER,
 								},
 								Resources: []string{
-									GetUrnPrefix("", RESOURCE_USER, "/newpath/"),
+									GetUrnPrefix("", RESOURCE_USER, "/path/"),
 								},
 							},
 							{
 								Effect: "deny",
 								Actions: []string{
-									USER_ACTION_GET_USER,
+									USER_ACTION_DELETE_USER,
 								},
 								Resources: []string{
-									CreateUrn("", RESOURCE_USER, "/newpath/", "000"),
+									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
 								},
 							},
 						},
@@ -1379,23 +2190,22 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseUpdateUserDBErr": {
+		"ErrorCaseRemoveUserDBErr": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
 			externalID: "123456",
-			newPath:    "/example/",
 			wantError: &Error{
 				Code: UNKNOWN_API_ERROR,
 			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
-				ExternalID: "1234",
+				ExternalID: "123456",
 				Path:       "/example/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
 			},
-			updateUserMethodErr: &database.Error{
+			removeUserMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
 			},
 		},
@@ -1407,30 +2217,30 @@ func TestAuthAPI_UpdateUser(t *testing.T) {
 
 		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
 		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
-		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDMethodResult
-		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesMethodResult
-		testRepo.ArgsOut[UpdateUserMethod][0] = testcase.expectedUser
-		testRepo.ArgsOut[UpdateUserMethod][1] = testcase.updateUserMethodErr
-		user, err := testAPI.UpdateUser(testcase.requestInfo, testcase.externalID, testcase.newPath)
-		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		testRepo.ArgsOut[RemoveUserMethod][0] = testcase.removeUserMethodErr
+		err := testAPI.RemoveUser(testcase.requestInfo, testcase.externalID)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
 	}
-
 }
 
-func TestAuthAPI_RemoveUser(t *testing.T) {
+func TestAuthAPI_SuspendUser(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		requestInfo RequestInfo
 		externalID  string
+		reason      string
 		// Expected result
-		wantError error
+		expectedUser *User
+		wantError    error
 		// Manager Results
 		getUserByExternalIDMethodResult *User
-		getGroupsByUserIDResult         []TestUserGroupRelation
-		getAttachedPoliciesResult       []TestPolicyGroupRelation
+		getGroupsByUserIDMethodResult   []TestUserGroupRelation
+		getAttachedPoliciesMethodResult []TestPolicyGroupRelation
 		// API Errors
+		updateUserMethodErr          error
 		getUserByExternalIDMethodErr error
-		removeUserMethodErr          error
 	}{
 		"OKCaseAdmin": {
 			requestInfo: RequestInfo{
@@ -1438,25 +2248,42 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 				Admin:      true,
 			},
 			externalID: "1234",
+			reason:     "Offboarded",
+			expectedUser: &User{
+				ID:             "543210",
+				ExternalID:     "1234",
+				Path:           "/example/",
+				Urn:            CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				DisabledReason: "Offboarded",
+			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
 			},
 		},
 		"OKCase": {
 			requestInfo: RequestInfo{
-				Identifier: "123456",
+				Identifier: "1234",
 				Admin:      false,
 			},
 			externalID: "1234",
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+			},
 			getUserByExternalIDMethodResult: &User{
-				ID:         "1234",
+				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
-			getGroupsByUserIDResult: []TestUserGroupRelation{
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
@@ -1466,7 +2293,7 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 					},
 				},
 			},
-			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
 						ID:   "POLICY-USER-ID",
@@ -1478,7 +2305,7 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 								Effect: "allow",
 								Actions: []string{
 									USER_ACTION_GET_USER,
-									USER_ACTION_DELETE_USER,
+									USER_ACTION_SUSPEND_USER,
 								},
 								Resources: []string{
 									GetUrnPrefix("", RESOURCE_USER, "/path/"),
@@ -1489,63 +2316,43 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseInvalidExtID": {
-			externalID: "*%~#@|",
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: externalId *%~#@|",
-			},
-		},
-		"ErrorCaseNoID": {
-			wantError: &Error{
-				Code:    INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: externalId ",
-			},
-		},
-		"ErrorCaseNoAuth": {
+		"ErrorCaseInvalidReason": {
 			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
+				Identifier: "123456",
+				Admin:      true,
 			},
 			externalID: "1234",
+			reason:     strings.Repeat("a", MAX_REASON_LENGTH+1),
 			wantError: &Error{
-				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/example/12345",
-			},
-			getUserByExternalIDMethodResult: &User{
-				ID:         "543210",
-				ExternalID: "12345",
-				Path:       "/example/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: reason, max length allowed: %v", MAX_REASON_LENGTH),
 			},
 		},
-		"ErrorCaseUserNotFound": {
+		"ErrorCaseInvalidExtID": {
 			requestInfo: RequestInfo{
-				Identifier: "1234",
-				Admin:      false,
+				Identifier: "123456",
+				Admin:      true,
 			},
-			externalID: "1234",
+			externalID: "*%~#@|",
 			wantError: &Error{
-				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
-			},
-			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.USER_NOT_FOUND,
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId *%~#@|",
 			},
 		},
-		"ErrorCaseGetUserExtIDDBErr": {
+		"ErrorCaseUserNotFound": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
 			externalID: "1234",
 			wantError: &Error{
-				Code: UNKNOWN_API_ERROR,
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
 			},
 			getUserByExternalIDMethodErr: &database.Error{
-				Code: database.INTERNAL_ERROR,
+				Code: database.USER_NOT_FOUND,
 			},
 		},
-		"ErrorCaseRemoveNotAllowedInPath": {
+		"ErrorCaseNoPermissions": {
 			requestInfo: RequestInfo{
 				Identifier: "1234",
 				Admin:      false,
@@ -1556,12 +2363,13 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
 			},
 			getUserByExternalIDMethodResult: &User{
-				ID:         "1234",
+				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
-			getGroupsByUserIDResult: []TestUserGroupRelation{
+			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
 					Group: &Group{
 						ID:   "GROUP-USER-ID",
@@ -1571,7 +2379,7 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 					},
 				},
 			},
-			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+			getAttachedPoliciesMethodResult: []TestPolicyGroupRelation{
 				{
 					Policy: &Policy{
 						ID:   "POLICY-USER-ID",
@@ -1593,7 +2401,84 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 				},
 			},
 		},
-		"ErrorCaseNoPermissions": {
+		"ErrorCaseUpdateUserDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
+			},
+			updateUserMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
+		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDMethodResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesMethodResult
+		testRepo.ArgsOut[UpdateUserMethod][0] = testcase.expectedUser
+		testRepo.ArgsOut[UpdateUserMethod][1] = testcase.updateUserMethodErr
+		user, err := testAPI.SuspendUser(testcase.requestInfo, testcase.externalID, testcase.reason)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
+		if testcase.wantError == nil {
+			updatedUser := testRepo.ArgsIn[UpdateUserMethod][0].(User)
+			assert.False(t, updatedUser.Enabled, "Error in test case %v", x)
+			assert.Equal(t, testcase.reason, updatedUser.DisabledReason, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestAuthAPI_ReactivateUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		externalID  string
+		// Expected result
+		expectedUser *User
+		wantError    error
+		// Manager Results
+		getUserByExternalIDMethodResult *User
+		// API Errors
+		updateUserMethodErr          error
+		getUserByExternalIDMethodErr error
+	}{
+		"OKCaseAdmin": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			externalID: "1234",
+			expectedUser: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				Enabled:    true,
+			},
+			getUserByExternalIDMethodResult: &User{
+				ID:             "543210",
+				ExternalID:     "1234",
+				Path:           "/example/",
+				Urn:            CreateUrn("", RESOURCE_USER, "/example/", "1234"),
+				DisabledReason: "Offboarded",
+			},
+		},
+		"ErrorCaseDisabledRequester": {
 			requestInfo: RequestInfo{
 				Identifier: "1234",
 				Admin:      false,
@@ -1601,79 +2486,45 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 			externalID: "1234",
 			wantError: &Error{
 				Code:    UNAUTHORIZED_RESOURCES_ERROR,
-				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:iam::user/path/1234",
+				Message: "Authenticated user with externalId 1234 is disabled. Unable to retrieve permissions.",
 			},
 			getUserByExternalIDMethodResult: &User{
-				ID:         "1234",
-				ExternalID: "1234",
-				Path:       "/path/",
-				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				ID:             "543210",
+				ExternalID:     "1234",
+				Path:           "/path/",
+				Urn:            CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				DisabledReason: "Offboarded",
 			},
-			getGroupsByUserIDResult: []TestUserGroupRelation{
-				{
-					Group: &Group{
-						ID:   "GROUP-USER-ID",
-						Name: "groupUser",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
-					},
-				},
+		},
+		"ErrorCaseUserNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
 			},
-			getAttachedPoliciesResult: []TestPolicyGroupRelation{
-				{
-					Policy: &Policy{
-						ID:   "POLICY-USER-ID",
-						Name: "policyUser",
-						Path: "/path/",
-						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
-						Statements: &[]Statement{
-							{
-								Effect: "allow",
-								Actions: []string{
-									USER_ACTION_GET_USER,
-								},
-								Resources: []string{
-									GetUrnPrefix("", RESOURCE_USER, "/path/"),
-								},
-							},
-							{
-								Effect: "allow",
-								Actions: []string{
-									USER_ACTION_DELETE_USER,
-								},
-								Resources: []string{
-									GetUrnPrefix("", RESOURCE_USER, "/path/"),
-								},
-							},
-							{
-								Effect: "deny",
-								Actions: []string{
-									USER_ACTION_DELETE_USER,
-								},
-								Resources: []string{
-									CreateUrn("", RESOURCE_USER, "/path/", "1234"),
-								},
-							},
-						},
-					},
-				},
+			externalID: "1234",
+			wantError: &Error{
+				Code: USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			getUserByExternalIDMethodErr: &database.Error{
+				Code: database.USER_NOT_FOUND,
 			},
 		},
-		"ErrorCaseRemoveUserDBErr": {
+		"ErrorCaseUpdateUserDBErr": {
 			requestInfo: RequestInfo{
 				Identifier: "123456",
 				Admin:      true,
 			},
-			externalID: "123456",
+			externalID: "1234",
 			wantError: &Error{
 				Code: UNKNOWN_API_ERROR,
 			},
 			getUserByExternalIDMethodResult: &User{
 				ID:         "543210",
-				ExternalID: "123456",
+				ExternalID: "1234",
 				Path:       "/example/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "1234"),
 			},
-			removeUserMethodErr: &database.Error{
+			updateUserMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
 			},
 		},
@@ -1685,11 +2536,15 @@ func TestAuthAPI_RemoveUser(t *testing.T) {
 
 		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDMethodResult
 		testRepo.ArgsOut[GetUserByExternalIDMethod][1] = testcase.getUserByExternalIDMethodErr
-		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
-		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
-		testRepo.ArgsOut[RemoveUserMethod][0] = testcase.removeUserMethodErr
-		err := testAPI.RemoveUser(testcase.requestInfo, testcase.externalID)
-		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+		testRepo.ArgsOut[UpdateUserMethod][0] = testcase.expectedUser
+		testRepo.ArgsOut[UpdateUserMethod][1] = testcase.updateUserMethodErr
+		user, err := testAPI.ReactivateUser(testcase.requestInfo, testcase.externalID)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedUser, user)
+		if testcase.wantError == nil {
+			updatedUser := testRepo.ArgsIn[UpdateUserMethod][0].(User)
+			assert.True(t, updatedUser.Enabled, "Error in test case %v", x)
+			assert.Empty(t, updatedUser.DisabledReason, "Error in test case %v", x)
+		}
 	}
 }
 
@@ -1735,6 +2590,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1782,6 +2638,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1865,6 +2722,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ExternalID: "12345",
 				Path:       "/example/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/example/", "12345"),
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUserNotFound": {
@@ -1912,6 +2770,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ID:         "543210",
 				ExternalID: "1234",
 				Path:       "/example/",
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodErr: &database.Error{
 				Code: database.INTERNAL_ERROR,
@@ -1935,6 +2794,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -2003,6 +2863,7 @@ func TestAuthAPI_ListGroupsByUser(t *testing.T) {
 				ExternalID: "1234",
 				Path:       "/path/",
 				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
 			},
 			getGroupsByUserIDMethodResult: []TestUserGroupRelation{
 				{
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const (
@@ -13,6 +16,7 @@ const (
 	RESOURCE_POLICY             = "policy"
 	RESOURCE_PROXY              = "proxy"
 	RESOURCE_AUTH_OIDC_PROVIDER = "oidc"
+	RESOURCE_WEBHOOK            = "webhook"
 
 	// Resource validation
 	RESOURCE_EXTERNAL = "external"
@@ -24,7 +28,11 @@ const (
 	MAX_ACTION_LENGTH      = 128
 	MAX_PATH_LENGTH        = 512
 	MAX_RESOURCE_NUMBER    = 50
+	MAX_ATTRIBUTE_NUMBER   = 50
+	MAX_ATTRIBUTE_LENGTH   = 256
+	MAX_REASON_LENGTH      = 512
 	MAX_LIMIT_SIZE         = 1000
+	MAX_BULK_SIZE          = 1000
 	DEFAULT_LIMIT_SIZE     = 20
 
 	// Actions
@@ -36,6 +44,15 @@ const (
 	USER_ACTION_LIST_USERS           = "iam:ListUsers"
 	USER_ACTION_UPDATE_USER          = "iam:UpdateUser"
 	USER_ACTION_LIST_GROUPS_FOR_USER = "iam:ListGroupsForUser"
+	USER_ACTION_SUSPEND_USER         = "iam:SuspendUser"
+	USER_ACTION_REACTIVATE_USER      = "iam:ReactivateUser"
+
+	// API key actions, over the service account that owns the key
+	API_KEY_ACTION_CREATE_API_KEY = "iam:CreateApiKey"
+	API_KEY_ACTION_DELETE_API_KEY = "iam:DeleteApiKey"
+	API_KEY_ACTION_GET_API_KEY    = "iam:GetApiKey"
+	API_KEY_ACTION_LIST_API_KEYS  = "iam:ListApiKeys"
+	API_KEY_ACTION_ROTATE_API_KEY = "iam:RotateApiKey"
 
 	// Group actions
 	GROUP_ACTION_CREATE_GROUP                 = "iam:CreateGroup"
@@ -71,11 +88,23 @@ const (
 	AUTH_OIDC_ACTION_UPDATE_PROVIDER = "auth:UpdateOidcProvider"
 	AUTH_OIDC_ACTION_LIST_PROVIDERS  = "auth:ListOidcProviders"
 	AUTH_OIDC_ACTION_GET_PROVIDER    = "auth:GetOidcProvider"
+
+	// Webhook actions
+	WEBHOOK_ACTION_CREATE_WEBHOOK  = "notify:CreateWebhook"
+	WEBHOOK_ACTION_DELETE_WEBHOOK  = "notify:DeleteWebhook"
+	WEBHOOK_ACTION_UPDATE_WEBHOOK  = "notify:UpdateWebhook"
+	WEBHOOK_ACTION_LIST_WEBHOOKS   = "notify:ListWebhooks"
+	WEBHOOK_ACTION_GET_WEBHOOK     = "notify:GetWebhook"
+	WEBHOOK_ACTION_LIST_DELIVERIES = "notify:ListWebhookDeliveries"
+
+	// Change feed actions
+	CHANGE_ACTION_LIST_CHANGES = "iam:ListChanges"
 )
 
 var (
 	rUserExtID, _          = regexp.Compile(`^[\w+.@=\-_]+$`)
 	rName, _               = regexp.Compile(`^[\w\-_]+$`)
+	rAttributeKey, _       = regexp.Compile(`^[\w\-_.]+$`)
 	rOrder, _              = regexp.Compile(`^\w+\-(asc|desc)$`)
 	rOrg, _                = regexp.Compile(`^[\w\-_]+$`)
 	rPath, _               = regexp.Compile(`^/$|^/[\w+/\-_]+\w+/$`)
@@ -97,6 +126,8 @@ func CreateUrn(org string, resource string, path string, name string) string {
 		return fmt.Sprintf("urn:iws:iam::user%v%v", path, name)
 	case RESOURCE_AUTH_OIDC_PROVIDER:
 		return fmt.Sprintf("urn:iws:auth::%v%v%v", resource, path, name)
+	case RESOURCE_WEBHOOK:
+		return fmt.Sprintf("urn:iws:notify::%v%v%v", resource, path, name)
 	default:
 		return fmt.Sprintf("urn:iws:iam:%v:%v%v%v", org, resource, path, name)
 	}
@@ -108,6 +139,8 @@ func GetUrnPrefix(org string, resource string, path string) string {
 		return fmt.Sprintf("urn:iws:iam::user%v*", path)
 	case RESOURCE_AUTH_OIDC_PROVIDER:
 		return fmt.Sprintf("urn:iws:auth::%v%v*", resource, path)
+	case RESOURCE_WEBHOOK:
+		return fmt.Sprintf("urn:iws:notify::%v%v*", resource, path)
 	default:
 		return fmt.Sprintf("urn:iws:iam:%v:%v%v*", org, resource, path)
 	}
@@ -126,6 +159,11 @@ func IsValidName(name string) bool {
 	return rName.MatchString(name) && len(name) < MAX_NAME_LENGTH
 }
 
+// IsValidAttributeKey validates keys of user and group attributes
+func IsValidAttributeKey(key string) bool {
+	return rAttributeKey.MatchString(key) && len(key) < MAX_NAME_LENGTH
+}
+
 // IsValidOrder validates the OrderBy query param
 func IsValidOrder(order string) bool {
 	return rOrder.MatchString(order) && len(order) < MAX_NAME_LENGTH
@@ -171,6 +209,10 @@ func IsValidProxyResource(resource *ResourceEntity) error {
 		return err
 	}
 
+	if err := AreValidTags(resource.Tags); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -282,6 +324,70 @@ func AreValidStatements(statements *[]Statement) error {
 		if err != nil {
 			return err
 		}
+
+		// check conditions
+		err = AreValidConditions(statement.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func AreValidAttributes(attributes map[string]string) error {
+	return areValidKeyValues("attribute", attributes)
+}
+
+func AreValidTags(tags map[string]string) error {
+	return areValidKeyValues("tag", tags)
+}
+
+func areValidKeyValues(kind string, keyValues map[string]string) error {
+	if len(keyValues) > MAX_ATTRIBUTE_NUMBER {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: %vs, max number allowed: %v", kind, MAX_ATTRIBUTE_NUMBER),
+		}
+	}
+	for key, value := range keyValues {
+		if !IsValidAttributeKey(key) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: %v key %v", kind, key),
+			}
+		}
+		if len(value) > MAX_ATTRIBUTE_LENGTH {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: %v %v value, max length allowed: %v", kind, key, MAX_ATTRIBUTE_LENGTH),
+			}
+		}
+	}
+	return nil
+}
+
+func AreValidConditions(conditions []Condition) error {
+	for _, condition := range conditions {
+		if condition.Operator != CONDITION_OPERATOR_EQUALS && condition.Operator != CONDITION_OPERATOR_NOT_EQUALS {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: condition operator %v", condition.Operator),
+			}
+		}
+		source, key := condition.getAttributeSourceAndKey()
+		if (source != CONDITION_SOURCE_USER && source != CONDITION_SOURCE_GROUP && source != CONDITION_SOURCE_RESOURCE) ||
+			!IsValidAttributeKey(key) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: condition attribute %v", condition.Attribute),
+			}
+		}
+		if len(condition.Values) < 1 {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: condition of attribute %v without values", condition.Attribute),
+			}
+		}
 	}
 	return nil
 }
@@ -336,6 +442,68 @@ func validateFilter(filter *Filter, validColumns []string) error {
 		}
 	}
 
+	if len(filter.WebhookName) > 0 && !IsValidName(filter.WebhookName) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: webhook %v", filter.WebhookName),
+		}
+	}
+
+	if err := AreValidAttributes(filter.Attributes); err != nil {
+		return err
+	}
+
+	if len(filter.State) > 0 && filter.State != USER_STATE_ENABLED && filter.State != USER_STATE_DISABLED {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: state %v", filter.State),
+		}
+	}
+
+	if len(filter.NamePrefix) > 0 && !IsValidUserExternalID(filter.NamePrefix) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: namePrefix %v", filter.NamePrefix),
+		}
+	}
+
+	if len(filter.NameContains) > 0 && !IsValidUserExternalID(filter.NameContains) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: nameContains %v", filter.NameContains),
+		}
+	}
+
+	if !filter.CreatedBefore.IsZero() && filter.CreatedAfter.After(filter.CreatedBefore) {
+		return &Error{
+			Code: INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: createdAfter %v is later than createdBefore %v",
+				filter.CreatedAfter.Format(time.RFC3339), filter.CreatedBefore.Format(time.RFC3339)),
+		}
+	}
+
+	if !filter.UpdatedBefore.IsZero() && filter.UpdatedAfter.After(filter.UpdatedBefore) {
+		return &Error{
+			Code: INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: updatedAfter %v is later than updatedBefore %v",
+				filter.UpdatedAfter.Format(time.RFC3339), filter.UpdatedBefore.Format(time.RFC3339)),
+		}
+	}
+
+	if len(filter.Action) > 0 && AreValidActions([]string{filter.Action}) != nil {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: action %v", filter.Action),
+		}
+	}
+
+	if len(filter.Resource) > 0 && (!isFullUrn(filter.Resource) || AreValidResources([]string{filter.Resource}, "") != nil) {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: resource %v", filter.Resource),
+		}
+	}
+
 	if filter.Limit == 0 {
 		filter.Limit = DEFAULT_LIMIT_SIZE
 	} else if filter.Limit > MAX_LIMIT_SIZE {
@@ -345,6 +513,26 @@ func validateFilter(filter *Filter, validColumns []string) error {
 		}
 	}
 
+	if len(filter.Cursor) > 0 {
+		cursor, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return err
+		}
+		if filter.Offset > 0 {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor and offset can't be used together",
+			}
+		}
+		// Cursors keep the order of the list where they were created
+		if cursor.OrderBy != strings.Replace(filter.OrderBy, "-", " ", 1) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: cursor %v, created with another OrderBy", filter.Cursor),
+			}
+		}
+	}
+
 	if len(filter.OrderBy) > 0 {
 		if !IsValidOrder(filter.OrderBy) {
 			return &Error{
@@ -372,6 +560,29 @@ func validateFilter(filter *Filter, validColumns []string) error {
 	return nil
 }
 
+// Encode returns the opaque token of the cursor
+func (c Cursor) Encode() string {
+	value, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// DecodeCursor returns the cursor of an opaque token. Throw error if token is invalid.
+func DecodeCursor(token string) (*Cursor, error) {
+	invalidErr := &Error{
+		Code:    INVALID_PARAMETER_ERROR,
+		Message: fmt.Sprintf("Invalid parameter: cursor %v", token),
+	}
+	value, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalidErr
+	}
+	cursor := &Cursor{}
+	if err := json.Unmarshal(value, cursor); err != nil || len(cursor.Key) == 0 {
+		return nil, invalidErr
+	}
+	return cursor, nil
+}
+
 // Private Methods
 
 func errFunc(parameter string, value string) error {
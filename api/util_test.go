@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestCreateUrn(t *testing.T) {
@@ -439,6 +440,30 @@ func TestAreValidStatements(t *testing.T) {
 				Message: "Invalid parameter urn, value: urn:iws:iam::user/path/****",
 			},
 		},
+		"ErrorCaseInvalidCondition": {
+			Statements: &[]Statement{
+				{
+					Effect: "allow",
+					Actions: []string{
+						USER_ACTION_GET_USER,
+					},
+					Resources: []string{
+						GetUrnPrefix("", RESOURCE_USER, "/path/"),
+					},
+					Conditions: []Condition{
+						{
+							Operator:  "like",
+							Attribute: "user:clearance",
+							Values:    []string{"high"},
+						},
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: condition operator like",
+			},
+		},
 	}
 
 	for x, testcase := range testcases {
@@ -447,6 +472,143 @@ func TestAreValidStatements(t *testing.T) {
 	}
 }
 
+func TestAreValidAttributes(t *testing.T) {
+	tooManyAttributes := map[string]string{}
+	for i := 0; i <= MAX_ATTRIBUTE_NUMBER; i++ {
+		tooManyAttributes[fmt.Sprintf("key%v", i)] = "value"
+	}
+	testcases := map[string]struct {
+		// Method args
+		attributes map[string]string
+		// Expected results
+		wantError error
+	}{
+		"OKCase": {
+			attributes: map[string]string{
+				"department":  "backend",
+				"cost-center": "1000",
+				"clearance":   "high",
+			},
+		},
+		"OKCaseNilAttributes": {},
+		"ErrorCaseMaxNumberExceeded": {
+			attributes: tooManyAttributes,
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: attributes, max number allowed: %v", MAX_ATTRIBUTE_NUMBER),
+			},
+		},
+		"ErrorCaseInvalidKey": {
+			attributes: map[string]string{
+				"cost:center": "1000",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: attribute key cost:center",
+			},
+		},
+		"ErrorCaseMaxLengthExceeded": {
+			attributes: map[string]string{
+				"department": getRandomString([]rune("abcdefghijklmnopqrstuvwxyz"), MAX_ATTRIBUTE_LENGTH+1),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: attribute department value, max length allowed: %v", MAX_ATTRIBUTE_LENGTH),
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		err := AreValidAttributes(testcase.attributes)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+	}
+}
+
+func TestAreValidConditions(t *testing.T) {
+	testcases := map[string]struct {
+		// Method args
+		conditions []Condition
+		// Expected results
+		wantError error
+	}{
+		"OKCase": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+				{
+					Operator:  CONDITION_OPERATOR_NOT_EQUALS,
+					Attribute: "group:department",
+					Values:    []string{"sales", "marketing"},
+				},
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "resource:env",
+					Values:    []string{"prod"},
+				},
+			},
+		},
+		"ErrorCaseInvalidOperator": {
+			conditions: []Condition{
+				{
+					Operator:  "like",
+					Attribute: "user:clearance",
+					Values:    []string{"high"},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: condition operator like",
+			},
+		},
+		"ErrorCaseInvalidSource": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "policy:clearance",
+					Values:    []string{"high"},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: condition attribute policy:clearance",
+			},
+		},
+		"ErrorCaseNoSource": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "clearance",
+					Values:    []string{"high"},
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: condition attribute clearance",
+			},
+		},
+		"ErrorCaseNoValues": {
+			conditions: []Condition{
+				{
+					Operator:  CONDITION_OPERATOR_EQUALS,
+					Attribute: "user:clearance",
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: condition of attribute user:clearance without values",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		err := AreValidConditions(testcase.conditions)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+	}
+}
+
 func TestAreValidResources(t *testing.T) {
 	testcases := map[string]struct {
 		// Method args
@@ -628,6 +790,133 @@ func TestValidateFilter(t *testing.T) {
 			},
 			OrderByValidColumns: []string{"name", "test"},
 		},
+		"OKCaseState": {
+			filter: &Filter{
+				State: USER_STATE_DISABLED,
+			},
+		},
+		"OKCaseSearch": {
+			filter: &Filter{
+				NamePrefix:    "user",
+				NameContains:  "_1",
+				CreatedAfter:  time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2017, time.February, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC),
+				Action:        USER_ACTION_DELETE_USER,
+				Resource:      CreateUrn("", RESOURCE_USER, "/path/", "user_1"),
+			},
+		},
+		"ErrorCaseInvalidNamePrefix": {
+			filter: &Filter{
+				NamePrefix: "us%",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: namePrefix us%",
+			},
+		},
+		"ErrorCaseInvalidNameContains": {
+			filter: &Filter{
+				NameContains: "us*",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: nameContains us*",
+			},
+		},
+		"ErrorCaseInvalidCreatedRange": {
+			filter: &Filter{
+				CreatedAfter:  time.Date(2017, time.February, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: createdAfter 2017-02-01T00:00:00Z is later than createdBefore 2017-01-01T00:00:00Z",
+			},
+		},
+		"ErrorCaseInvalidUpdatedRange": {
+			filter: &Filter{
+				UpdatedAfter:  time.Date(2017, time.February, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: updatedAfter 2017-02-01T00:00:00Z is later than updatedBefore 2017-01-01T00:00:00Z",
+			},
+		},
+		"ErrorCaseInvalidAction": {
+			filter: &Filter{
+				Action: "iam:**",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: action iam:**",
+			},
+		},
+		"ErrorCaseInvalidResource": {
+			filter: &Filter{
+				Resource: "urn:iws:iam::user/path//user1",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: resource urn:iws:iam::user/path//user1",
+			},
+		},
+		"ErrorCasePrefixResource": {
+			filter: &Filter{
+				Resource: "urn:iws:iam::user/path/*",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: resource urn:iws:iam::user/path/*",
+			},
+		},
+		"OKCaseCursor": {
+			filter: &Filter{
+				Cursor:  Cursor{OrderBy: "name desc", Value: "grp", Key: "123"}.Encode(),
+				OrderBy: "name-desc",
+			},
+			OrderByValidColumns: []string{"name"},
+		},
+		"ErrorCaseInvalidCursor": {
+			filter: &Filter{
+				Cursor: "cursor",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor cursor",
+			},
+		},
+		"ErrorCaseCursorWithOffset": {
+			filter: &Filter{
+				Cursor: Cursor{Key: "123"}.Encode(),
+				Offset: 20,
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor and offset can't be used together",
+			},
+		},
+		"ErrorCaseCursorWithOtherOrder": {
+			filter: &Filter{
+				Cursor:  Cursor{Key: "123"}.Encode(),
+				OrderBy: "name-desc",
+			},
+			OrderByValidColumns: []string{"name"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: cursor %v, created with another OrderBy", Cursor{Key: "123"}.Encode()),
+			},
+		},
+		"ErrorCaseInvalidState": {
+			filter: &Filter{
+				State: "suspended",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: state suspended",
+			},
+		},
 		"ErrorCaseInvalidOrg": {
 			filter: &Filter{
 				ExternalID: "123",
@@ -753,6 +1042,52 @@ func TestValidateFilter(t *testing.T) {
 	}
 }
 
+func TestDecodeCursor(t *testing.T) {
+	testcases := map[string]struct {
+		// Method args
+		token string
+		// Expected results
+		expectedCursor *Cursor
+		wantError      error
+	}{
+		"OKCase": {
+			token: Cursor{OrderBy: "create_at desc", Value: "1500000000000000000", Key: "123", Prev: true}.Encode(),
+			expectedCursor: &Cursor{
+				OrderBy: "create_at desc",
+				Value:   "1500000000000000000",
+				Key:     "123",
+				Prev:    true,
+			},
+		},
+		"ErrorCaseInvalidEncoding": {
+			token: "!cursor",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor !cursor",
+			},
+		},
+		"ErrorCaseInvalidJSON": {
+			token: "Y3Vyc29y",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor Y3Vyc29y",
+			},
+		},
+		"ErrorCaseNoKey": {
+			token: Cursor{OrderBy: "name"}.Encode(),
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: cursor %v", Cursor{OrderBy: "name"}.Encode()),
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		cursor, err := DecodeCursor(testcase.token)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedCursor, cursor)
+	}
+}
+
 func TestIsValidProxyResource(t *testing.T) {
 	testcases := map[string]struct {
 		// Method args
@@ -778,6 +1113,34 @@ func TestIsValidProxyResource(t *testing.T) {
 				Action: "action",
 			},
 		},
+		"OKCaseWithTags": {
+			resource: &ResourceEntity{
+				Host:   "http://host.com",
+				Path:   "/path",
+				Method: "GET",
+				Urn:    "urn:ews:example:instance1:resource/get",
+				Action: "action",
+				Tags: map[string]string{
+					"env": "prod",
+				},
+			},
+		},
+		"ErrorCaseInvalidTag": {
+			resource: &ResourceEntity{
+				Host:   "http://host.com",
+				Path:   "/path",
+				Method: "GET",
+				Urn:    "urn:ews:example:instance1:resource/get",
+				Action: "action",
+				Tags: map[string]string{
+					"env:prod": "true",
+				},
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: tag key env:prod",
+			},
+		},
 		"ErrorCaseInvalidHost": {
 			resource: &ResourceEntity{
 				Host: "~32&",
@@ -0,0 +1,470 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/satori/go.uuid"
+)
+
+// TYPE DEFINITIONS
+
+// Webhook domain
+type Webhook struct {
+	ID       string    `json:"id,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Urn      string    `json:"urn,omitempty"`
+	CreateAt time.Time `json:"createAt,omitempty"`
+	UpdateAt time.Time `json:"updateAt,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Secret   string    `json:"-"`
+	Events   []string  `json:"events,omitempty"`
+}
+
+// WebhookDelivery is a delivery attempt of an event to a webhook
+type WebhookDelivery struct {
+	ID         string    `json:"id,omitempty"`
+	WebhookID  string    `json:"webhookId,omitempty"`
+	EventID    string    `json:"eventId,omitempty"`
+	EventType  string    `json:"eventType,omitempty"`
+	Attempt    int       `json:"attempt,omitempty"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	CreateAt   time.Time `json:"createAt,omitempty"`
+}
+
+func (w Webhook) String() string {
+	return fmt.Sprintf("[id: %v, name: %v, path: %v, urn: %v, createAt: %v, updateAt: %v, url: %v, events: %v]",
+		w.ID, w.Name, w.Path, w.Urn, w.CreateAt.Format("2006-01-02 15:04:05 MST"),
+		w.UpdateAt.Format("2006-01-02 15:04:05 MST"), w.URL, w.Events)
+}
+
+func (w Webhook) GetUrn() string {
+	return w.Urn
+}
+
+// IsSubscribed checks if webhook has to receive the event type
+func (w Webhook) IsSubscribed(eventType string) bool {
+	for _, e := range w.Events {
+		if e == EVENT_ALL || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WEBHOOK API IMPLEMENTATION
+
+func (api WorkerAPI) AddWebhook(requestInfo RequestInfo, name string, path string, webhookURL string, secret string, events []string) (*Webhook, error) {
+	api, span := api.startSpan(&requestInfo, "AddWebhook")
+	defer span.End()
+
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+	if !IsValidPath(path) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: path %v", path),
+		}
+	}
+	if err := validateWebhookFields(webhookURL, secret, events); err != nil {
+		return nil, err
+	}
+
+	webhook := createWebhook(name, path, webhookURL, secret, events)
+
+	// Check restrictions
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, webhook.Urn, WEBHOOK_ACTION_CREATE_WEBHOOK, []Webhook{webhook})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooksFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, webhook.Urn),
+		}
+	}
+
+	// Check if webhook already exists
+	_, err = api.WebhookRepo.GetWebhookByName(name)
+
+	// Check if webhook could be retrieved
+	if err != nil {
+		// Transform to DB error
+		dbError := err.(*database.Error)
+		switch dbError.Code {
+		// Webhook doesn't exist in DB
+		case database.WEBHOOK_NOT_FOUND:
+			// Create webhook
+			createdWebhook, err := api.WebhookRepo.AddWebhook(webhook)
+
+			// Check if there is an unexpected error in DB
+			if err != nil {
+				//Transform to DB error
+				dbError := err.(*database.Error)
+				return nil, &Error{
+					Code:    UNKNOWN_API_ERROR,
+					Message: dbError.Message,
+				}
+			}
+
+			LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook created %+v", createdWebhook))
+			return createdWebhook, nil
+		default: // Unexpected error
+			return nil, &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+	} else { // Fail if webhook exists
+		return nil, &Error{
+			Code:    WEBHOOK_ALREADY_EXIST,
+			Message: fmt.Sprintf("Unable to create webhook, webhook with name %v already exist", name),
+		}
+	}
+}
+
+func (api WorkerAPI) GetWebhookByName(requestInfo RequestInfo, name string) (*Webhook, error) {
+	api, span := api.startSpan(&requestInfo, "GetWebhookByName")
+	defer span.End()
+
+	// Validate fields
+	if !IsValidName(name) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: name %v", name),
+		}
+	}
+
+	// Call repo to retrieve the webhook
+	webhook, err := api.WebhookRepo.GetWebhookByName(name)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		// Webhook doesn't exist in DB
+		if dbError.Code == database.WEBHOOK_NOT_FOUND {
+			return nil, &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: dbError.Message,
+			}
+		}
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, webhook.Urn, WEBHOOK_ACTION_GET_WEBHOOK, []Webhook{*webhook})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooksFiltered) > 0 {
+		webhookFiltered := webhooksFiltered[0]
+		return &webhookFiltered, nil
+	}
+	return nil, &Error{
+		Code: UNAUTHORIZED_RESOURCES_ERROR,
+		Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+			requestInfo.Identifier, webhook.Urn),
+	}
+}
+
+func (api WorkerAPI) ListWebhooks(requestInfo RequestInfo, filter *Filter) ([]string, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListWebhooks")
+	defer span.End()
+
+	// Validate fields
+	var total int
+	orderByValidColumns := api.WebhookRepo.OrderByValidColumns(WEBHOOK_ACTION_LIST_WEBHOOKS)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the webhooks
+	webhooks, total, err := api.WebhookRepo.GetWebhooksFiltered(filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	// Check restrictions to list
+	urnPrefix := GetUrnPrefix("", RESOURCE_WEBHOOK, filter.PathPrefix)
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, urnPrefix, WEBHOOK_ACTION_LIST_WEBHOOKS, webhooks)
+	if err != nil {
+		return nil, total, err
+	}
+
+	webhookNames := []string{}
+	for _, w := range webhooksFiltered {
+		webhookNames = append(webhookNames, w.Name)
+	}
+
+	return webhookNames, total, nil
+}
+
+func (api WorkerAPI) UpdateWebhook(requestInfo RequestInfo, webhookName string, newName string, newPath string, newURL string,
+	newSecret string, newEvents []string) (*Webhook, error) {
+	api, span := api.startSpan(&requestInfo, "UpdateWebhook")
+	defer span.End()
+
+	// Validate fields
+	if !IsValidName(newName) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: new name %v", newName),
+		}
+	}
+	if !IsValidPath(newPath) {
+		return nil, &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: new path %v", newPath),
+		}
+	}
+
+	// Call repo to retrieve the old webhook
+	oldWebhook, err := api.GetWebhookByName(requestInfo, webhookName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep old secret if a new one isn't provided
+	if newSecret == "" {
+		newSecret = oldWebhook.Secret
+	}
+	if err := validateWebhookFields(newURL, newSecret, newEvents); err != nil {
+		return nil, err
+	}
+
+	// Check restrictions
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, oldWebhook.Urn, WEBHOOK_ACTION_UPDATE_WEBHOOK, []Webhook{*oldWebhook})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooksFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, oldWebhook.Urn),
+		}
+	}
+
+	// Check if webhook with "newName" exists
+	targetWebhook, err := api.GetWebhookByName(requestInfo, newName)
+
+	if err == nil && targetWebhook.ID != oldWebhook.ID {
+		// Webhook already exists
+		return nil, &Error{
+			Code:    WEBHOOK_ALREADY_EXIST,
+			Message: fmt.Sprintf("Webhook name: %v already exists", newName),
+		}
+	}
+
+	if err != nil {
+		if apiError := err.(*Error); apiError.Code != WEBHOOK_BY_NAME_NOT_FOUND {
+			return nil, err
+		}
+	}
+
+	auxWebhook := Webhook{
+		Urn: CreateUrn("", RESOURCE_WEBHOOK, newPath, newName),
+	}
+
+	// Check restrictions
+	webhooksFiltered, err = api.GetAuthorizedWebhooks(requestInfo, auxWebhook.Urn, WEBHOOK_ACTION_UPDATE_WEBHOOK, []Webhook{auxWebhook})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooksFiltered) < 1 {
+		return nil, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, auxWebhook.Urn),
+		}
+	}
+
+	webhook := Webhook{
+		ID:       oldWebhook.ID,
+		Name:     newName,
+		Path:     newPath,
+		Urn:      auxWebhook.Urn,
+		CreateAt: oldWebhook.CreateAt,
+		UpdateAt: time.Now().UTC(),
+		URL:      newURL,
+		Secret:   newSecret,
+		Events:   newEvents,
+	}
+
+	// Update webhook
+	updatedWebhook, err := api.WebhookRepo.UpdateWebhook(webhook)
+
+	// Check unexpected DB error
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook updated from %+v to %+v",
+		oldWebhook, updatedWebhook))
+	return updatedWebhook, nil
+}
+
+func (api WorkerAPI) RemoveWebhook(requestInfo RequestInfo, name string) error {
+	api, span := api.startSpan(&requestInfo, "RemoveWebhook")
+	defer span.End()
+
+	// Call repo to retrieve the webhook
+	webhook, err := api.GetWebhookByName(requestInfo, name)
+	if err != nil {
+		return err
+	}
+
+	// Check restrictions
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, webhook.Urn, WEBHOOK_ACTION_DELETE_WEBHOOK, []Webhook{*webhook})
+	if err != nil {
+		return err
+	}
+	if len(webhooksFiltered) < 1 {
+		return &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, webhook.Urn),
+		}
+	}
+
+	err = api.WebhookRepo.RemoveWebhook(webhook.ID)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	LogOperation(requestInfo.RequestID, requestInfo.Identifier, fmt.Sprintf("Webhook deleted %v", webhook))
+	return nil
+}
+
+func (api WorkerAPI) ListWebhookDeliveries(requestInfo RequestInfo, filter *Filter) ([]WebhookDelivery, int, error) {
+	api, span := api.startSpan(&requestInfo, "ListWebhookDeliveries")
+	defer span.End()
+
+	// Validate fields
+	var total int
+	orderByValidColumns := api.WebhookRepo.OrderByValidColumns(WEBHOOK_ACTION_LIST_DELIVERIES)
+	err := validateFilter(filter, orderByValidColumns)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Call repo to retrieve the webhook
+	webhook, err := api.GetWebhookByName(requestInfo, filter.WebhookName)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Check restrictions
+	webhooksFiltered, err := api.GetAuthorizedWebhooks(requestInfo, webhook.Urn, WEBHOOK_ACTION_LIST_DELIVERIES, []Webhook{*webhook})
+	if err != nil {
+		return nil, total, err
+	}
+	if len(webhooksFiltered) < 1 {
+		return nil, total, &Error{
+			Code: UNAUTHORIZED_RESOURCES_ERROR,
+			Message: fmt.Sprintf("User with externalId %v is not allowed to access to resource %v",
+				requestInfo.Identifier, webhook.Urn),
+		}
+	}
+
+	// Call repo to retrieve the delivery attempts
+	deliveries, total, err := api.WebhookRepo.GetWebhookDeliveries(webhook.ID, filter)
+
+	// Error handling
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		return nil, total, &Error{
+			Code:    UNKNOWN_API_ERROR,
+			Message: dbError.Message,
+		}
+	}
+
+	return deliveries, total, nil
+}
+
+// PRIVATE HELPER METHODS
+
+func createWebhook(name string, path string, webhookURL string, secret string, events []string) Webhook {
+	urn := CreateUrn("", RESOURCE_WEBHOOK, path, name)
+	webhook := Webhook{
+		ID:       uuid.NewV4().String(),
+		Name:     name,
+		Path:     path,
+		CreateAt: time.Now().UTC(),
+		UpdateAt: time.Now().UTC(),
+		Urn:      urn,
+		URL:      webhookURL,
+		Secret:   secret,
+		Events:   events,
+	}
+
+	return webhook
+}
+
+func validateWebhookFields(webhookURL string, secret string, events []string) error {
+	u, err := url.ParseRequestURI(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: url %v", webhookURL),
+		}
+	}
+	if len(secret) < 1 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: empty secret",
+		}
+	}
+	if len(events) < 1 {
+		return &Error{
+			Code:    INVALID_PARAMETER_ERROR,
+			Message: "Invalid parameter: empty events",
+		}
+	}
+	for _, e := range events {
+		if !IsValidEventType(e) {
+			return &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: event %v", e),
+			}
+		}
+	}
+	return nil
+}
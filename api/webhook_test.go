@@ -0,0 +1,955 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAPI_AddWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		webhookName string
+		path        string
+		url         string
+		secret      string
+		events      []string
+
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		getUserByExternalIDResult *User
+
+		addWebhookMethodResult       *Webhook
+		getWebhookByNameMethodResult *Webhook
+		wantError                    error
+
+		getWebhookByNameMethodErr error
+		addWebhookMethodErr       error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_GROUP_MEMBER_ADDED, EVENT_GROUP_MEMBER_REMOVED},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.WEBHOOK_NOT_FOUND,
+			},
+			addWebhookMethodResult: &Webhook{
+				ID:     "test1",
+				Name:   "test",
+				Path:   "/path/",
+				Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:    "https://test.com/hook",
+				Secret: "secret",
+				Events: []string{EVENT_GROUP_MEMBER_ADDED, EVENT_GROUP_MEMBER_REMOVED},
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			getWebhookByNameMethodResult: &Webhook{
+				ID:   "test1",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_ALREADY_EXIST,
+				Message: "Unable to create webhook, webhook with name test already exist",
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "**!^#~",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseBadPath": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "*/ /**!^#~path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: path */ /**!^#~path/",
+			},
+		},
+		"ErrorCaseInvalidURL": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "ftp://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: url ftp://test.com/hook",
+			},
+		},
+		"ErrorCaseEmptySecret": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			events:      []string{EVENT_ALL},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: empty secret",
+			},
+		},
+		"ErrorCaseEmptyEvents": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: empty events",
+			},
+		},
+		"ErrorCaseInvalidEvent": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{"user.unknown"},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: event user.unknown",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      false,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.WEBHOOK_NOT_FOUND,
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "123456",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "123456"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 123456 is not allowed to access to resource urn:iws:notify::webhook/path/test",
+			},
+		},
+		"ErrorCaseAddWebhookErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.WEBHOOK_NOT_FOUND,
+			},
+			addWebhookMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+		"ErrorCaseGetWebhookDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			path:        "/path/",
+			url:         "https://test.com/hook",
+			secret:      "secret",
+			events:      []string{EVENT_ALL},
+			getWebhookByNameMethodErr: &database.Error{
+				Code: database.INTERNAL_ERROR,
+			},
+			wantError: &Error{
+				Code: UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[AddWebhookMethod][0] = testcase.addWebhookMethodResult
+		testRepo.ArgsOut[AddWebhookMethod][1] = testcase.addWebhookMethodErr
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = testcase.getWebhookByNameMethodResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = testcase.getWebhookByNameMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		webhook, err := testAPI.AddWebhook(testcase.requestInfo, testcase.webhookName,
+			testcase.path, testcase.url, testcase.secret, testcase.events)
+		checkMethodResponse(t, x, testcase.wantError, err, webhook, testcase.addWebhookMethodResult)
+	}
+}
+
+func TestWorkerAPI_GetWebhookByName(t *testing.T) {
+	testcases := map[string]struct {
+		requestInfo RequestInfo
+		webhookName string
+
+		getGroupsByUserIDResult   []TestUserGroupRelation
+		getAttachedPoliciesResult []TestPolicyGroupRelation
+		getUserByExternalIDResult *User
+
+		getWebhookByNameMethodResult *Webhook
+		wantError                    error
+
+		getWebhookByNameMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameMethodResult: &Webhook{
+				ID:     "test1",
+				Name:   "test",
+				Path:   "/path/",
+				Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:    "https://test.com/hook",
+				Events: []string{EVENT_ALL},
+			},
+		},
+		"OKCaseUserAllowed": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			webhookName: "test",
+			getWebhookByNameMethodResult: &Webhook{
+				ID:     "test1",
+				Name:   "test",
+				Path:   "/path/",
+				Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:    "https://test.com/hook",
+				Events: []string{EVENT_ALL},
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			getGroupsByUserIDResult: []TestUserGroupRelation{
+				{
+					Group: &Group{
+						ID:   "GROUP-USER-ID",
+						Name: "groupUser",
+						Path: "/path/1/",
+						Urn:  CreateUrn("example", RESOURCE_GROUP, "/path/", "groupUser"),
+					},
+				},
+			},
+			getAttachedPoliciesResult: []TestPolicyGroupRelation{
+				{
+					Policy: &Policy{
+						ID:   "POLICY-USER-ID",
+						Name: "policy",
+						Org:  "example",
+						Path: "/path/",
+						Urn:  CreateUrn("example", RESOURCE_POLICY, "/path/", "policyUser"),
+						Statements: &[]Statement{
+							{
+								Effect: "allow",
+								Actions: []string{
+									WEBHOOK_ACTION_GET_WEBHOOK,
+								},
+								Resources: []string{
+									GetUrnPrefix("", RESOURCE_WEBHOOK, "/"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ErrorCaseBadName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "**!^#~",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: name **!^#~",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+		},
+		"ErrorCaseDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseNoPermissions": {
+			requestInfo: RequestInfo{
+				Identifier: "1234",
+				Admin:      false,
+			},
+			webhookName: "test",
+			getWebhookByNameMethodResult: &Webhook{
+				ID:   "test1",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+			getUserByExternalIDResult: &User{
+				ID:         "543210",
+				ExternalID: "1234",
+				Path:       "/path/",
+				Urn:        CreateUrn("", RESOURCE_USER, "/path/", "1234"),
+				Enabled:    true,
+			},
+			wantError: &Error{
+				Code:    UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "User with externalId 1234 is not allowed to access to resource urn:iws:notify::webhook/path/test",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = testcase.getWebhookByNameMethodResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = testcase.getWebhookByNameMethodErr
+		testRepo.ArgsOut[GetUserByExternalIDMethod][0] = testcase.getUserByExternalIDResult
+		testRepo.ArgsOut[GetGroupsByUserIDMethod][0] = testcase.getGroupsByUserIDResult
+		testRepo.ArgsOut[GetAttachedPoliciesMethod][0] = testcase.getAttachedPoliciesResult
+		webhook, err := testAPI.GetWebhookByName(testcase.requestInfo, testcase.webhookName)
+		checkMethodResponse(t, x, testcase.wantError, err, webhook, testcase.getWebhookByNameMethodResult)
+	}
+}
+
+func TestWorkerAPI_ListWebhooks(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedWebhooks []string
+		totalResult      int
+		wantError        error
+		// Manager Results
+		getWebhooksFilteredMethodResult []Webhook
+		// Manager Errors
+		getWebhooksFilteredMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				PathPrefix: "/path/",
+			},
+			expectedWebhooks: []string{"test1", "test2"},
+			totalResult:      2,
+			getWebhooksFilteredMethodResult: []Webhook{
+				{
+					ID:   "1",
+					Name: "test1",
+					Path: "/path/",
+					Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test1"),
+				},
+				{
+					ID:   "2",
+					Name: "test2",
+					Path: "/path/",
+					Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test2"),
+				},
+			},
+		},
+		"ErrorCaseInvalidPath": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				PathPrefix: "/path*/",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: pathPrefix /path*/",
+			},
+		},
+		"ErrorCaseInvalidOrderBy": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				OrderBy: "secret-desc",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: OrderBy column secret",
+			},
+		},
+		"ErrorCaseDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				PathPrefix: "/path/",
+			},
+			getWebhooksFilteredMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[OrderByValidColumnsMethod][0] = []string{"name", "path"}
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][0] = testcase.getWebhooksFilteredMethodResult
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetWebhooksFilteredMethod][2] = testcase.getWebhooksFilteredMethodErr
+		webhooks, total, err := testAPI.ListWebhooks(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedWebhooks, webhooks)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_UpdateWebhook(t *testing.T) {
+	oldWebhook := &Webhook{
+		ID:     "12345",
+		Name:   "webhook1",
+		Path:   "/path/",
+		Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/path/", "webhook1"),
+		URL:    "https://old.com/hook",
+		Secret: "oldSecret",
+		Events: []string{EVENT_ALL},
+	}
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo    RequestInfo
+		webhookName    string
+		newWebhookName string
+		newPath        string
+		newURL         string
+		newSecret      string
+		newEvents      []string
+		// Expected result
+		expectedWebhook *Webhook
+		expectedSecret  string
+		wantError       error
+		// Manager Results
+		getWebhookByNameMethodSpecialFunc func(string) (*Webhook, error)
+		updateWebhookResult               *Webhook
+		// Manager Errors
+		updateWebhookMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "newName",
+			newPath:        "/new/",
+			newURL:         "https://new.com/hook",
+			newSecret:      "newSecret",
+			newEvents:      []string{EVENT_USER_CREATED},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				if name == "webhook1" {
+					return oldWebhook, nil
+				}
+				return nil, &database.Error{
+					Code: database.WEBHOOK_NOT_FOUND,
+				}
+			},
+			updateWebhookResult: &Webhook{
+				ID:     "12345",
+				Name:   "newName",
+				Path:   "/new/",
+				Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/new/", "newName"),
+				URL:    "https://new.com/hook",
+				Events: []string{EVENT_USER_CREATED},
+			},
+			expectedWebhook: &Webhook{
+				ID:     "12345",
+				Name:   "newName",
+				Path:   "/new/",
+				Urn:    CreateUrn("", RESOURCE_WEBHOOK, "/new/", "newName"),
+				URL:    "https://new.com/hook",
+				Events: []string{EVENT_USER_CREATED},
+			},
+			expectedSecret: "newSecret",
+		},
+		"OKCaseKeepSecret": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "webhook1",
+			newPath:        "/path/",
+			newURL:         "https://new.com/hook",
+			newEvents:      []string{EVENT_ALL},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				return oldWebhook, nil
+			},
+			updateWebhookResult: &Webhook{
+				ID:   "12345",
+				Name: "webhook1",
+			},
+			expectedWebhook: &Webhook{
+				ID:   "12345",
+				Name: "webhook1",
+			},
+			expectedSecret: "oldSecret",
+		},
+		"ErrorCaseInvalidName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "**!^#~",
+			newPath:        "/path/",
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: new name **!^#~",
+			},
+		},
+		"ErrorCaseInvalidURL": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "webhook1",
+			newPath:        "/path/",
+			newURL:         "invalid",
+			newEvents:      []string{EVENT_ALL},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				return oldWebhook, nil
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: url invalid",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "newName",
+			newPath:        "/path/",
+			newURL:         "https://new.com/hook",
+			newEvents:      []string{EVENT_ALL},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				return nil, &database.Error{
+					Code:    database.WEBHOOK_NOT_FOUND,
+					Message: "Webhook with name webhook1 not found",
+				}
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name webhook1 not found",
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "webhook2",
+			newPath:        "/path/",
+			newURL:         "https://new.com/hook",
+			newEvents:      []string{EVENT_ALL},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				if name == "webhook1" {
+					return oldWebhook, nil
+				}
+				return &Webhook{
+					ID:   "54321",
+					Name: "webhook2",
+					Path: "/path/",
+					Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "webhook2"),
+				}, nil
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_ALREADY_EXIST,
+				Message: "Webhook name: webhook2 already exists",
+			},
+		},
+		"ErrorCaseUpdateWebhookDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName:    "webhook1",
+			newWebhookName: "webhook1",
+			newPath:        "/path/",
+			newURL:         "https://new.com/hook",
+			newEvents:      []string{EVENT_ALL},
+			getWebhookByNameMethodSpecialFunc: func(name string) (*Webhook, error) {
+				return oldWebhook, nil
+			},
+			updateWebhookMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	for x, testcase := range testcases {
+		testRepo := makeTestRepo()
+		testAPI := makeTestAPI(testRepo)
+
+		testRepo.ArgsOut[UpdateWebhookMethod][0] = testcase.updateWebhookResult
+		testRepo.ArgsOut[UpdateWebhookMethod][1] = testcase.updateWebhookMethodErr
+		testRepo.SpecialFuncs[GetWebhookByNameMethod] = testcase.getWebhookByNameMethodSpecialFunc
+
+		webhook, err := testAPI.UpdateWebhook(testcase.requestInfo, testcase.webhookName, testcase.newWebhookName,
+			testcase.newPath, testcase.newURL, testcase.newSecret, testcase.newEvents)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedWebhook, webhook)
+		if testcase.wantError == nil {
+			updated := testRepo.ArgsIn[UpdateWebhookMethod][0].(Webhook)
+			assert.Equal(t, testcase.expectedSecret, updated.Secret, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWorkerAPI_RemoveWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API Method args
+		requestInfo RequestInfo
+		webhookName string
+		// Expected result
+		wantError error
+		// Manager Results
+		getWebhookByNameResult *Webhook
+		// Manager Errors
+		getWebhookByNameErr    error
+		removeWebhookMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameResult: &Webhook{
+				ID:   "12345",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameErr: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+		},
+		"ErrorCaseRemoveWebhookDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			webhookName: "test",
+			getWebhookByNameResult: &Webhook{
+				ID:   "12345",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+			removeWebhookMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = testcase.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = testcase.getWebhookByNameErr
+		testRepo.ArgsOut[RemoveWebhookMethod][0] = testcase.removeWebhookMethodErr
+		err := testAPI.RemoveWebhook(testcase.requestInfo, testcase.webhookName)
+		checkMethodResponse(t, x, testcase.wantError, err, nil, nil)
+	}
+}
+
+func TestWorkerAPI_ListWebhookDeliveries(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		requestInfo RequestInfo
+		filter      *Filter
+		// Expected result
+		expectedDeliveries []WebhookDelivery
+		totalResult        int
+		wantError          error
+		// Manager Results
+		getWebhookByNameResult *Webhook
+		// Manager Errors
+		getWebhookByNameErr           error
+		getWebhookDeliveriesMethodErr error
+	}{
+		"OKCase": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "test",
+			},
+			getWebhookByNameResult: &Webhook{
+				ID:   "12345",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+			expectedDeliveries: []WebhookDelivery{
+				{
+					ID:         "1",
+					WebhookID:  "12345",
+					EventID:    "event1",
+					EventType:  EVENT_USER_CREATED,
+					Attempt:    1,
+					StatusCode: 500,
+					Error:      "Unexpected status code 500",
+				},
+				{
+					ID:         "2",
+					WebhookID:  "12345",
+					EventID:    "event1",
+					EventType:  EVENT_USER_CREATED,
+					Attempt:    2,
+					StatusCode: 200,
+					Success:    true,
+				},
+			},
+			totalResult: 2,
+		},
+		"ErrorCaseInvalidWebhookName": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "**!^#~",
+			},
+			wantError: &Error{
+				Code:    INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: webhook **!^#~",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "test",
+			},
+			getWebhookByNameErr: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+			wantError: &Error{
+				Code:    WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+		},
+		"ErrorCaseDBErr": {
+			requestInfo: RequestInfo{
+				Identifier: "123456",
+				Admin:      true,
+			},
+			filter: &Filter{
+				WebhookName: "test",
+			},
+			getWebhookByNameResult: &Webhook{
+				ID:   "12345",
+				Name: "test",
+				Path: "/path/",
+				Urn:  CreateUrn("", RESOURCE_WEBHOOK, "/path/", "test"),
+			},
+			getWebhookDeliveriesMethodErr: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "Error",
+			},
+			wantError: &Error{
+				Code:    UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	testRepo := makeTestRepo()
+	testAPI := makeTestAPI(testRepo)
+
+	for x, testcase := range testcases {
+		testRepo.ArgsOut[OrderByValidColumnsMethod][0] = []string{"create_at"}
+		testRepo.ArgsOut[GetWebhookByNameMethod][0] = testcase.getWebhookByNameResult
+		testRepo.ArgsOut[GetWebhookByNameMethod][1] = testcase.getWebhookByNameErr
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][0] = testcase.expectedDeliveries
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][1] = testcase.totalResult
+		testRepo.ArgsOut[GetWebhookDeliveriesMethod][2] = testcase.getWebhookDeliveriesMethodErr
+		deliveries, total, err := testAPI.ListWebhookDeliveries(testcase.requestInfo, testcase.filter)
+		checkMethodResponse(t, x, testcase.wantError, err, testcase.expectedDeliveries, deliveries)
+		if testcase.wantError == nil {
+			assert.Equal(t, testcase.getWebhookByNameResult.ID, testRepo.ArgsIn[GetWebhookDeliveriesMethod][0], "Error in test case %v", x)
+			assert.Equal(t, testcase.totalResult, total, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWebhook_IsSubscribed(t *testing.T) {
+	testcases := map[string]struct {
+		events    []string
+		eventType string
+		// Expected result
+		expectedResult bool
+	}{
+		"OKCaseSubscribed": {
+			events:         []string{EVENT_USER_CREATED, EVENT_GROUP_MEMBER_ADDED},
+			eventType:      EVENT_GROUP_MEMBER_ADDED,
+			expectedResult: true,
+		},
+		"OKCaseWildcard": {
+			events:         []string{EVENT_ALL},
+			eventType:      EVENT_POLICY_DELETED,
+			expectedResult: true,
+		},
+		"OKCaseNotSubscribed": {
+			events:         []string{EVENT_USER_CREATED},
+			eventType:      EVENT_GROUP_MEMBER_ADDED,
+			expectedResult: false,
+		},
+	}
+
+	for x, testcase := range testcases {
+		webhook := Webhook{Events: testcase.events}
+		assert.Equal(t, testcase.expectedResult, webhook.IsSubscribed(testcase.eventType), "Error in test case %v", x)
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -37,6 +39,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	ps := internalhttp.NewProxy(proxy)
+	if err := ps.Configuration(); err != nil {
+		api.Log.Error(err.Error())
+		os.Exit(foulkon.CloseProxy())
+	}
+
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig,
 		syscall.SIGHUP,
@@ -44,13 +52,26 @@ func main() {
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
 
+	// Closed when in-flight requests are drained
+	drained := make(chan struct{})
 	go func() {
 		for {
 			sigrecv := <-sig
 			switch sigrecv {
-			case syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGQUIT:
-				api.Log.Infof("Signal '%v' received, closing proxy...", sigrecv.String())
-				os.Exit(foulkon.CloseProxy())
+			case syscall.SIGHUP:
+				api.Log.Infof("Signal '%v' received, reloading proxy configuration...", sigrecv.String())
+				if err := reload(proxy, ps, *configFile); err != nil {
+					api.Log.Errorf("Couldn't reload configuration: %v", err)
+				}
+			case syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT:
+				api.Log.Infof("Signal '%v' received, shutting down proxy...", sigrecv.String())
+				ctx, cancel := context.WithTimeout(context.Background(), proxy.ShutdownTimeout)
+				if err := ps.Shutdown(ctx); err != nil {
+					api.Log.Errorf("Couldn't drain in-flight requests: %v", err)
+				}
+				cancel()
+				close(drained)
+				return
 			default:
 				api.Log.Warnf("Unknown OS signal received, ignoring...")
 			}
@@ -58,9 +79,23 @@ func main() {
 	}()
 
 	api.Log.Infof("Server running in %v:%v", proxy.Host, proxy.Port)
-	ps := internalhttp.NewProxy(proxy)
-	ps.Configuration()
-	api.Log.Error(ps.Run().Error())
+	if err := ps.Run(); err != http.ErrServerClosed {
+		api.Log.Error(err.Error())
+		os.Exit(foulkon.CloseProxy())
+	}
+	<-drained
 
 	os.Exit(foulkon.CloseProxy())
 }
+
+// reload reads configuration file again and applies settings that can change while running
+func reload(proxy *foulkon.Proxy, ps internalhttp.Server, configFile string) error {
+	config, err := toml.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+	if err := proxy.Reload(config); err != nil {
+		return err
+	}
+	return ps.Reload()
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 
 	"os"
 
@@ -39,6 +41,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	ws := internalhttp.NewWorker(core, internalhttp.WorkerHandlerRouter(core))
+	if err := ws.Configuration(); err != nil {
+		api.Log.Error(err.Error())
+		os.Exit(foulkon.CloseWorker())
+	}
+
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig,
 		syscall.SIGHUP,
@@ -46,13 +54,26 @@ func main() {
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
 
+	// Closed when in-flight requests are drained
+	drained := make(chan struct{})
 	go func() {
 		for {
 			sigrecv := <-sig
 			switch sigrecv {
-			case syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGQUIT:
-				api.Log.Infof("Signal '%v' received, closing worker...", sigrecv.String())
-				os.Exit(foulkon.CloseWorker())
+			case syscall.SIGHUP:
+				api.Log.Infof("Signal '%v' received, reloading worker configuration...", sigrecv.String())
+				if err := reload(core, ws, *configFile); err != nil {
+					api.Log.Errorf("Couldn't reload configuration: %v", err)
+				}
+			case syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT:
+				api.Log.Infof("Signal '%v' received, shutting down worker...", sigrecv.String())
+				ctx, cancel := context.WithTimeout(context.Background(), core.GetShutdownTimeout())
+				if err := ws.Shutdown(ctx); err != nil {
+					api.Log.Errorf("Couldn't drain in-flight requests: %v", err)
+				}
+				cancel()
+				close(drained)
+				return
 			default:
 				api.Log.Warnf("Unknown OS signal received, ignoring...")
 			}
@@ -60,9 +81,23 @@ func main() {
 	}()
 
 	api.Log.Infof("Server running in %v:%v", core.Host, core.Port)
-	ws := internalhttp.NewWorker(core, internalhttp.WorkerHandlerRouter(core))
-	ws.Configuration()
-	api.Log.Error(ws.Run().Error())
+	if err := ws.Run(); err != http.ErrServerClosed {
+		api.Log.Error(err.Error())
+		os.Exit(foulkon.CloseWorker())
+	}
+	<-drained
 
 	os.Exit(foulkon.CloseWorker())
 }
+
+// reload reads configuration file again and applies settings that can change while running
+func reload(core *foulkon.Worker, ws internalhttp.Server, configFile string) error {
+	config, err := toml.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+	if err := core.Reload(config); err != nil {
+		return err
+	}
+	return ws.Reload()
+}
@@ -9,6 +9,9 @@ const (
 	// User Codes
 	USER_NOT_FOUND = "UserNotFound"
 
+	// API key Codes
+	API_KEY_NOT_FOUND = "ApiKeyNotFound"
+
 	// Group Codes
 	GROUP_NOT_FOUND = "GroupNotFound"
 
@@ -26,6 +29,9 @@ const (
 
 	// Auth Provider Codes
 	AUTH_OIDC_PROVIDER_NOT_FOUND = "AuthOidcProviderNotFound"
+
+	// Webhook Codes
+	WEBHOOK_NOT_FOUND = "WebhookNotFound"
 )
 
 type Error struct {
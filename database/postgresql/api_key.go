@@ -0,0 +1,186 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+)
+
+// API KEY REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) AddApiKey(apiKey api.ApiKey) (*api.ApiKey, error) {
+	defer pr.observe("AddApiKey")()
+	// Create API key model
+	apiKeyDB := &ApiKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		UserID:     apiKey.UserID,
+		SecretHash: apiKey.SecretHash,
+		CreateAt:   apiKey.CreateAt.UnixNano(),
+		UpdateAt:   apiKey.UpdateAt.UnixNano(),
+		ExpireAt:   timeToUnixNano(apiKey.ExpireAt),
+		LastUsedAt: timeToUnixNano(apiKey.LastUsedAt),
+	}
+
+	// Store API key
+	err := pr.Dbmap.Create(apiKeyDB).Error
+
+	// Error handling
+	if err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbApiKeyToAPIApiKey(apiKeyDB), nil
+}
+
+func (pr PostgresRepo) GetApiKeyByID(id string) (*api.ApiKey, error) {
+	defer pr.observe("GetApiKeyByID")()
+	apiKey := &ApiKey{}
+	query := pr.Dbmap.Where("id like ?", id).First(apiKey)
+
+	// Check if API key exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.API_KEY_NOT_FOUND,
+			Message: fmt.Sprintf("API key with id %v not found", id),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Retrieve externalId of its user
+	user := &User{}
+	if err := pr.Dbmap.Where("id like ?", apiKey.UserID).First(user).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	apiApiKey := dbApiKeyToAPIApiKey(apiKey)
+	apiApiKey.ExternalID = user.ExternalID
+	return apiApiKey, nil
+}
+
+func (pr PostgresRepo) GetApiKeysByUserID(userID string, filter *api.Filter) ([]api.ApiKey, int, error) {
+	defer pr.observe("GetApiKeysByUserID")()
+	var total int
+	apiKeys := []ApiKey{}
+	query := pr.Dbmap.Where("user_id like ?", userID)
+
+	// Error handling
+	total, err := findPage(query, &apiKeys, filter, filter.OrderBy, "id")
+	if err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform API keys to API domain
+	var apiApiKeys []api.ApiKey
+	if apiKeys != nil {
+		apiApiKeys = make([]api.ApiKey, len(apiKeys), cap(apiKeys))
+		for i, k := range apiKeys {
+			apiApiKeys[i] = *dbApiKeyToAPIApiKey(&k)
+		}
+	}
+
+	return apiApiKeys, total, nil
+}
+
+func (pr PostgresRepo) UpdateApiKey(apiKey api.ApiKey) (*api.ApiKey, error) {
+	defer pr.observe("UpdateApiKey")()
+	apiKeyDB := ApiKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		UserID:     apiKey.UserID,
+		SecretHash: apiKey.SecretHash,
+		CreateAt:   apiKey.CreateAt.UTC().UnixNano(),
+		UpdateAt:   apiKey.UpdateAt.UTC().UnixNano(),
+		ExpireAt:   timeToUnixNano(apiKey.ExpireAt),
+		LastUsedAt: timeToUnixNano(apiKey.LastUsedAt),
+	}
+
+	// Update API key, with a map so unset times are stored too
+	if err := pr.Dbmap.Model(&ApiKey{ID: apiKey.ID}).Updates(map[string]interface{}{
+		"name":         apiKeyDB.Name,
+		"secret_hash":  apiKeyDB.SecretHash,
+		"update_at":    apiKeyDB.UpdateAt,
+		"expire_at":    apiKeyDB.ExpireAt,
+		"last_used_at": apiKeyDB.LastUsedAt,
+	}).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbApiKeyToAPIApiKey(&apiKeyDB), nil
+}
+
+func (pr PostgresRepo) UpdateApiKeyLastUsed(id string, lastUsedAt time.Time) error {
+	defer pr.observe("UpdateApiKeyLastUsed")()
+	if err := pr.Dbmap.Model(&ApiKey{ID: id}).Update("last_used_at", lastUsedAt.UTC().UnixNano()).Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+func (pr PostgresRepo) RemoveApiKey(id string) error {
+	defer pr.observe("RemoveApiKey")()
+	if err := pr.Dbmap.Where("id like ?", id).Delete(&ApiKey{}).Error; err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// PRIVATE HELPER METHODS
+
+// Transform an API key retrieved from db into an API key for API
+func dbApiKeyToAPIApiKey(apiKey *ApiKey) *api.ApiKey {
+	return &api.ApiKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		UserID:     apiKey.UserID,
+		SecretHash: apiKey.SecretHash,
+		CreateAt:   time.Unix(0, apiKey.CreateAt).UTC(),
+		UpdateAt:   time.Unix(0, apiKey.UpdateAt).UTC(),
+		ExpireAt:   unixNanoToTime(apiKey.ExpireAt),
+		LastUsedAt: unixNanoToTime(apiKey.LastUsedAt),
+	}
+}
+
+// Transform an optional time into nanoseconds, 0 if it isn't set
+func timeToUnixNano(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UTC().UnixNano()
+}
+
+// Transform nanoseconds into an optional time, nil if they are 0
+func unixNanoToTime(nanos int64) *time.Time {
+	if nanos == 0 {
+		return nil
+	}
+	t := time.Unix(0, nanos).UTC()
+	return &t
+}
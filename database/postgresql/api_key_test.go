@@ -0,0 +1,407 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepo_AddApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	expireAt := now.Add(time.Hour)
+	testcases := map[string]struct {
+		// Previous data
+		previousApiKey *ApiKey
+		// Postgres Repo Args
+		apiKeyToCreate *api.ApiKey
+		// Expected result
+		expectedResponse *api.ApiKey
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			apiKeyToCreate: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+				ExpireAt:   &expireAt,
+			},
+			expectedResponse: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+				ExpireAt:   &expireAt,
+			},
+		},
+		"ErrorCaseAlreadyExists": {
+			previousApiKey: &ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+			},
+			apiKeyToCreate: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: duplicate key value violates unique constraint \"api_keys_pkey\"",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean API key database
+		cleanApiKeyTable(t, n)
+
+		// Insert previous data
+		if test.previousApiKey != nil {
+			insertApiKey(t, n, *test.previousApiKey)
+		}
+		// Call to repository to store the API key
+		storedApiKey, err := repoDB.AddApiKey(*test.apiKeyToCreate)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check response
+			assert.Equal(t, test.expectedResponse, storedApiKey, "Error in test case %v", n)
+			// Check database
+			apiKeyNumber := getApiKeysCountFiltered(t, n, test.apiKeyToCreate.ID, test.apiKeyToCreate.UserID,
+				test.apiKeyToCreate.SecretHash, 0)
+			assert.Equal(t, 1, apiKeyNumber, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetApiKeyByID(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUser   *User
+		previousApiKey *ApiKey
+		// Postgres Repo Args
+		id string
+		// Expected result
+		expectedResponse *api.ApiKey
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			id: "ApiKeyID",
+			previousUser: &User{
+				ID:         "UserID",
+				ExternalID: "ci-job",
+				Path:       "/serviceaccount/",
+				Urn:        api.CreateUrn("", api.RESOURCE_USER, "/serviceaccount/", "ci-job"),
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+			},
+			previousApiKey: &ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+				LastUsedAt: now.UnixNano(),
+			},
+			expectedResponse: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				ExternalID: "ci-job",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+				LastUsedAt: &now,
+			},
+		},
+		"ErrorCaseNotFound": {
+			id: "ApiKeyID",
+			expectedError: &database.Error{
+				Code:    database.API_KEY_NOT_FOUND,
+				Message: "API key with id ApiKeyID not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean database
+		cleanApiKeyTable(t, n)
+		cleanUserTable(t, n)
+
+		// Insert previous data
+		if test.previousUser != nil {
+			insertUser(t, n, *test.previousUser)
+		}
+		if test.previousApiKey != nil {
+			insertApiKey(t, n, *test.previousApiKey)
+		}
+		// Call to repository to get the API key
+		receivedApiKey, err := repoDB.GetApiKeyByID(test.id)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, receivedApiKey, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetApiKeysByUserID(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousApiKeys []ApiKey
+		// Postgres Repo Args
+		userID string
+		filter *api.Filter
+		// Expected result
+		expectedResponse []api.ApiKey
+		expectedTotal    int
+	}{
+		"OkCase": {
+			userID: "UserID",
+			filter: &api.Filter{
+				OrderBy: "name asc",
+				Limit:   20,
+			},
+			previousApiKeys: []ApiKey{
+				{
+					ID:         "ApiKeyID2",
+					Name:       "b",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "ApiKeyID1",
+					Name:       "a",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "ApiKeyID3",
+					Name:       "c",
+					UserID:     "UserID2",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			expectedResponse: []api.ApiKey{
+				{
+					ID:         "ApiKeyID1",
+					Name:       "a",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now,
+					UpdateAt:   now,
+				},
+				{
+					ID:         "ApiKeyID2",
+					Name:       "b",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now,
+					UpdateAt:   now,
+				},
+			},
+			expectedTotal: 2,
+		},
+		"OkCaseNoKeys": {
+			userID: "UserID3",
+			filter: &api.Filter{
+				Limit: 20,
+			},
+			expectedResponse: []api.ApiKey{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean API key database
+		cleanApiKeyTable(t, n)
+
+		// Insert previous data
+		for _, apiKey := range test.previousApiKeys {
+			insertApiKey(t, n, apiKey)
+		}
+		// Call to repository to get API keys
+		receivedApiKeys, total, err := repoDB.GetApiKeysByUserID(test.userID, test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, receivedApiKeys, "Error in test case %v", n)
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_UpdateApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousApiKey *ApiKey
+		// Postgres Repo Args
+		apiKeyToUpdate *api.ApiKey
+		// Expected result
+		expectedResponse *api.ApiKey
+	}{
+		"OkCase": {
+			previousApiKey: &ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+				LastUsedAt: now.UnixNano(),
+			},
+			apiKeyToUpdate: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "newhash",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+			expectedResponse: &api.ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "newhash",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean API key database
+		cleanApiKeyTable(t, n)
+
+		// Insert previous data
+		if test.previousApiKey != nil {
+			insertApiKey(t, n, *test.previousApiKey)
+		}
+		// Call to repository to update the API key
+		updatedApiKey, err := repoDB.UpdateApiKey(*test.apiKeyToUpdate)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, updatedApiKey, "Error in test case %v", n)
+
+		// Check database, last use must be reset
+		apiKeyNumber := getApiKeysCountFiltered(t, n, test.apiKeyToUpdate.ID, "", test.apiKeyToUpdate.SecretHash, 0)
+		assert.Equal(t, 1, apiKeyNumber, "Error in test case %v", n)
+		apiKeys, _, err := repoDB.GetApiKeysByUserID(test.apiKeyToUpdate.UserID, &api.Filter{Limit: 20})
+		if assert.Nil(t, err, "Error in test case %v", n) && assert.Len(t, apiKeys, 1, "Error in test case %v", n) {
+			assert.Nil(t, apiKeys[0].LastUsedAt, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_UpdateApiKeyLastUsed(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousApiKey *ApiKey
+		// Postgres Repo Args
+		id         string
+		lastUsedAt time.Time
+	}{
+		"OkCase": {
+			previousApiKey: &ApiKey{
+				ID:         "ApiKeyID",
+				Name:       "Name",
+				UserID:     "UserID",
+				SecretHash: "hash",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+			},
+			id:         "ApiKeyID",
+			lastUsedAt: now.Add(time.Minute),
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean API key database
+		cleanApiKeyTable(t, n)
+
+		// Insert previous data
+		if test.previousApiKey != nil {
+			insertApiKey(t, n, *test.previousApiKey)
+		}
+		// Call to repository to store last use
+		err := repoDB.UpdateApiKeyLastUsed(test.id, test.lastUsedAt)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		apiKeyNumber := getApiKeysCountFiltered(t, n, test.id, "", "", test.lastUsedAt.UnixNano())
+		assert.Equal(t, 1, apiKeyNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_RemoveApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousApiKeys []ApiKey
+		// Postgres Repo Args
+		apiKeyToDelete string
+	}{
+		"OkCase": {
+			previousApiKeys: []ApiKey{
+				{
+					ID:         "ApiKeyID",
+					Name:       "Name",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "ApiKeyID2",
+					Name:       "Name",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			apiKeyToDelete: "ApiKeyID",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean API key database
+		cleanApiKeyTable(t, n)
+
+		// Insert previous data
+		for _, apiKey := range test.previousApiKeys {
+			insertApiKey(t, n, apiKey)
+		}
+		// Call to repository to remove API key
+		err := repoDB.RemoveApiKey(test.apiKeyToDelete)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		assert.Equal(t, 0, getApiKeysCountFiltered(t, n, test.apiKeyToDelete, "", "", 0), "Error in test case %v", n)
+		assert.Equal(t, 1, getApiKeysCountFiltered(t, n, "", "", "", 0), "Error in test case %v", n)
+	}
+}
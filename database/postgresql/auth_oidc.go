@@ -7,12 +7,14 @@ import (
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database"
+	"github.com/jinzhu/gorm"
 	"github.com/satori/go.uuid"
 )
 
 // AUTH OIDC PROVIDER REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.OidcProvider, error) {
+	defer pr.observe("AddOidcProvider")()
 	// Create OIDC Provider model
 	oidcProviderDB := &OidcProvider{
 		ID:        oidcProvider.ID,
@@ -23,6 +25,8 @@ func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.Oidc
 		Urn:       oidcProvider.Urn,
 		IssuerURL: oidcProvider.IssuerURL,
 	}
+	setDBUserProvisioning(oidcProviderDB, oidcProvider.UserProvisioning)
+	oidcProviderDB.GroupMappingMode = oidcProvider.GroupMappingMode
 
 	transaction := pr.Dbmap.Begin()
 
@@ -52,16 +56,24 @@ func (pr PostgresRepo) AddOidcProvider(oidcProvider api.OidcProvider) (*api.Oidc
 		}
 	}
 
+	// Create OIDC group mappings
+	if err := createOidcGroupMappings(transaction, oidcProvider.ID, oidcProvider.GroupMappings); err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
 	transaction.Commit()
 
 	// Create API OIDC Provider
 	oidcProviderApi := dbOidcProviderToAPIOidcProvider(oidcProviderDB)
 	oidcProviderApi.OidcClients = oidcProvider.OidcClients
+	oidcProviderApi.GroupMappings = oidcProvider.GroupMappings
 
 	return oidcProviderApi, nil
 }
 
 func (pr PostgresRepo) GetOidcProviderByName(name string) (*api.OidcProvider, error) {
+	defer pr.observe("GetOidcProviderByName")()
 	oidcProvider := &OidcProvider{}
 	query := pr.Dbmap.Where("name like ?", name).First(oidcProvider)
 
@@ -96,10 +108,18 @@ func (pr PostgresRepo) GetOidcProviderByName(name string) (*api.OidcProvider, er
 	oidcProviderApi := dbOidcProviderToAPIOidcProvider(oidcProvider)
 	oidcProviderApi.OidcClients = dbOidcClientsToAPIOidcClients(oidcClients)
 
+	// Retrieve associated OIDC group mappings
+	groupMappings, err := pr.getOidcGroupMappings(oidcProvider.ID)
+	if err != nil {
+		return nil, err
+	}
+	oidcProviderApi.GroupMappings = groupMappings
+
 	return oidcProviderApi, nil
 }
 
 func (pr PostgresRepo) GetOidcProvidersFiltered(filter *api.Filter) ([]api.OidcProvider, int, error) {
+	defer pr.observe("GetOidcProvidersFiltered")()
 	var total int
 	oidcProviders := []OidcProvider{}
 	query := pr.Dbmap
@@ -107,12 +127,11 @@ func (pr PostgresRepo) GetOidcProvidersFiltered(filter *api.Filter) ([]api.OidcP
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ?", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query = filterByNameAndDates(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&oidcProviders).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&oidcProviders).Error; err != nil {
+	total, err := findPage(query, &oidcProviders, filter, filter.OrderBy, "id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -140,6 +159,13 @@ func (pr PostgresRepo) GetOidcProvidersFiltered(filter *api.Filter) ([]api.OidcP
 
 			oidcProvider.OidcClients = dbOidcClientsToAPIOidcClients(oidcClients)
 
+			// Retrieve associated OIDC group mappings
+			groupMappings, err := pr.getOidcGroupMappings(oidcProvider.ID)
+			if err != nil {
+				return nil, total, err
+			}
+			oidcProvider.GroupMappings = groupMappings
+
 			// Assign OIDC Provider
 			apiOidcProviders[i] = *oidcProvider
 		}
@@ -150,6 +176,7 @@ func (pr PostgresRepo) GetOidcProvidersFiltered(filter *api.Filter) ([]api.OidcP
 }
 
 func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.OidcProvider, error) {
+	defer pr.observe("UpdateOidcProvider")()
 	oidcProviderDB := OidcProvider{
 		ID:        oidcProvider.ID,
 		Name:      oidcProvider.Name,
@@ -159,11 +186,23 @@ func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.O
 		Urn:       oidcProvider.Urn,
 		IssuerURL: oidcProvider.IssuerURL,
 	}
+	setDBUserProvisioning(&oidcProviderDB, oidcProvider.UserProvisioning)
+	oidcProviderDB.GroupMappingMode = oidcProvider.GroupMappingMode
 
 	transaction := pr.Dbmap.Begin()
 
-	// Update OIDC Provider
-	if err := transaction.Model(&OidcProvider{ID: oidcProvider.ID}).Update(oidcProviderDB).Error; err != nil {
+	// Update OIDC Provider, with a map so disabled user provisioning is stored too
+	if err := transaction.Model(&OidcProvider{ID: oidcProvider.ID}).Updates(map[string]interface{}{
+		"name":               oidcProviderDB.Name,
+		"path":               oidcProviderDB.Path,
+		"urn":                oidcProviderDB.Urn,
+		"create_at":          oidcProviderDB.CreateAt,
+		"update_at":          oidcProviderDB.UpdateAt,
+		"issuer_url":         oidcProviderDB.IssuerURL,
+		"provision_users":    oidcProviderDB.ProvisionUsers,
+		"user_path_template": oidcProviderDB.UserPathTemplate,
+		"group_mapping_mode": oidcProviderDB.GroupMappingMode,
+	}).Error; err != nil {
 		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
@@ -196,12 +235,26 @@ func (pr PostgresRepo) UpdateOidcProvider(oidcProvider api.OidcProvider) (*api.O
 		}
 	}
 
+	// Replace OIDC group mappings
+	if err := transaction.Where("oidc_provider_id like ?", oidcProvider.ID).Delete(OidcGroupMapping{}).Error; err != nil {
+		transaction.Rollback()
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if err := createOidcGroupMappings(transaction, oidcProvider.ID, oidcProvider.GroupMappings); err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
 	transaction.Commit()
 
 	return &oidcProvider, nil
 }
 
 func (pr PostgresRepo) RemoveOidcProvider(id string) error {
+	defer pr.observe("RemoveOidcProvider")()
 	transaction := pr.Dbmap.Begin()
 
 	// Delete OIDC Provider
@@ -225,22 +278,89 @@ func (pr PostgresRepo) RemoveOidcProvider(id string) error {
 
 	}
 
+	// Delete all OIDC group mappings
+	if err := transaction.Where("oidc_provider_id like ?", id).Delete(&OidcGroupMapping{}).Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	transaction.Commit()
 	return nil
 }
 
 // PRIVATE HELPER METHODS
 
+// Retrieve group mappings of a OIDC Provider for API
+func (pr PostgresRepo) getOidcGroupMappings(oidcProviderID string) ([]api.GroupMapping, error) {
+	groupMappings := []OidcGroupMapping{}
+	if err := pr.Dbmap.Where("oidc_provider_id like ?", oidcProviderID).Order("claim, value, org, group_name").Find(&groupMappings).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	var groupMappingsApi []api.GroupMapping
+	for _, gm := range groupMappings {
+		groupMappingsApi = append(groupMappingsApi, api.GroupMapping{
+			Claim: gm.Claim,
+			Value: gm.Value,
+			Org:   gm.Org,
+			Group: gm.GroupName,
+		})
+	}
+	return groupMappingsApi, nil
+}
+
+// Create group mappings of a OIDC Provider in transaction
+func createOidcGroupMappings(transaction *gorm.DB, oidcProviderID string, groupMappings []api.GroupMapping) error {
+	for _, gm := range groupMappings {
+		groupMappingDB := &OidcGroupMapping{
+			ID:             uuid.NewV4().String(),
+			OidcProviderID: oidcProviderID,
+			Claim:          gm.Claim,
+			Value:          gm.Value,
+			Org:            gm.Org,
+			GroupName:      gm.Group,
+		}
+		if err := transaction.Create(groupMappingDB).Error; err != nil {
+			return &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
 // Transform a OIDC Provider retrieved from db into a OIDC Provider for API
 func dbOidcProviderToAPIOidcProvider(oidcProvider *OidcProvider) *api.OidcProvider {
-	return &api.OidcProvider{
-		ID:        oidcProvider.ID,
-		Name:      oidcProvider.Name,
-		Path:      oidcProvider.Path,
-		CreateAt:  time.Unix(0, oidcProvider.CreateAt).UTC(),
-		UpdateAt:  time.Unix(0, oidcProvider.UpdateAt).UTC(),
-		Urn:       oidcProvider.Urn,
-		IssuerURL: oidcProvider.IssuerURL,
+	oidcProviderApi := &api.OidcProvider{
+		ID:               oidcProvider.ID,
+		Name:             oidcProvider.Name,
+		Path:             oidcProvider.Path,
+		CreateAt:         time.Unix(0, oidcProvider.CreateAt).UTC(),
+		UpdateAt:         time.Unix(0, oidcProvider.UpdateAt).UTC(),
+		Urn:              oidcProvider.Urn,
+		IssuerURL:        oidcProvider.IssuerURL,
+		GroupMappingMode: oidcProvider.GroupMappingMode,
+	}
+	if oidcProvider.ProvisionUsers {
+		oidcProviderApi.UserProvisioning = &api.UserProvisioning{
+			PathTemplate: oidcProvider.UserPathTemplate,
+		}
+	}
+	return oidcProviderApi
+}
+
+// Set user provisioning columns of a OIDC Provider for db
+func setDBUserProvisioning(oidcProvider *OidcProvider, userProvisioning *api.UserProvisioning) {
+	if userProvisioning != nil {
+		oidcProvider.ProvisionUsers = true
+		oidcProvider.UserPathTemplate = userProvisioning.PathTemplate
 	}
 }
 
@@ -63,6 +63,40 @@ func TestPostgresRepo_AddOidcProvider(t *testing.T) {
 				},
 			},
 		},
+		"OkCaseWithUserProvisioningAndGroupMappings": {
+			oidcProviderToCreate: &api.OidcProvider{
+				ID:        "OIDCProviderID",
+				Name:      "Name",
+				Path:      "Path",
+				Urn:       "urn",
+				CreateAt:  now,
+				UpdateAt:  now,
+				IssuerURL: "",
+				UserProvisioning: &api.UserProvisioning{
+					PathTemplate: "/employees/{department}/",
+				},
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_SYNC,
+			},
+			expectedResponse: &api.OidcProvider{
+				ID:        "OIDCProviderID",
+				Name:      "Name",
+				Path:      "Path",
+				Urn:       "urn",
+				CreateAt:  now,
+				UpdateAt:  now,
+				IssuerURL: "",
+				UserProvisioning: &api.UserProvisioning{
+					PathTemplate: "/employees/{department}/",
+				},
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_SYNC,
+			},
+		},
 		"ErrorCaseAlreadyExists": {
 			previousOidcProviders: []OidcProvider{
 				{
@@ -175,6 +209,7 @@ func TestPostgresRepo_AddOidcProvider(t *testing.T) {
 	for n, test := range testcases {
 		// Clean OIDC Provider databases
 		cleanOidcClientsTable(t, n)
+		cleanOidcGroupMappingsTable(t, n)
 		cleanOidcProvidersTable(t, n)
 
 		// Insert previous data
@@ -263,6 +298,7 @@ func TestPostgresRepo_GetOidcProviderByName(t *testing.T) {
 		// Clean OIDC Provider database
 		cleanOidcProvidersTable(t, n)
 		cleanOidcClientsTable(t, n)
+		cleanOidcGroupMappingsTable(t, n)
 
 		// Insert previous data
 		if test.oidcProvider != nil {
@@ -420,6 +456,7 @@ func TestPostgresRepo_GetOidcProvidersFiltered(t *testing.T) {
 		// Clean OIDC Provider database
 		cleanOidcProvidersTable(t, n)
 		cleanOidcClientsTable(t, n)
+		cleanOidcGroupMappingsTable(t, n)
 
 		// Insert previous data
 		for i, oidcProvider := range test.oidcProviders {
@@ -602,6 +639,7 @@ func TestPostgresRepo_UpdateOidcProvider(t *testing.T) {
 		// Clean OIDC Provider database
 		cleanOidcProvidersTable(t, n)
 		cleanOidcClientsTable(t, n)
+		cleanOidcGroupMappingsTable(t, n)
 
 		// Call to repository to add the OIDC Providers
 		if test.previousOidcProviders != nil {
@@ -680,6 +718,7 @@ func TestPostgresRepo_RemoveOidcProvider(t *testing.T) {
 		// Clean OIDC Provider database
 		cleanOidcProvidersTable(t, n)
 		cleanOidcClientsTable(t, n)
+		cleanOidcGroupMappingsTable(t, n)
 
 		// Insert previous data
 		if test.previousOidcProviders != nil {
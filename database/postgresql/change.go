@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/jinzhu/gorm"
+)
+
+// Key of the transaction advisory lock that serializes change feed writers
+const CHANGE_LOCK_KEY = 7382641
+
+// CHANGE REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) GetChanges(since int64, limit int) ([]api.Change, error) {
+	defer pr.observe("GetChanges")()
+	changes := []Change{}
+	query := pr.Dbmap.Where("seq > ?", since).Order("seq asc").Limit(limit).Find(&changes)
+
+	// Error handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform changes to API domain
+	apiChanges := make([]api.Change, len(changes), cap(changes))
+	for i, c := range changes {
+		apiChanges[i] = api.Change{
+			Seq:        c.Seq,
+			Type:       c.Type,
+			ResourceID: c.ResourceID,
+			RelatedID:  c.RelatedID,
+			Urn:        c.Urn,
+			CreateAt:   time.Unix(0, c.CreateAt).UTC(),
+		}
+	}
+
+	return apiChanges, nil
+}
+
+func (pr PostgresRepo) GetLastChangeSeq() (int64, error) {
+	defer pr.observe("GetLastChangeSeq")()
+	var seqs []int64
+	query := pr.Dbmap.Model(&Change{}).Order("seq desc").Limit(1).Pluck("seq", &seqs)
+
+	// Error handling
+	if err := query.Error; err != nil {
+		return 0, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	if len(seqs) == 0 {
+		return 0, nil
+	}
+	return seqs[0], nil
+}
+
+// PRIVATE HELPER METHODS
+
+// addChange stores a change of a resource inside the transaction that modifies it.
+// Writers hold an advisory lock until they commit, so sequence order is commit order
+// and readers never miss a change committed after another one with higher sequence.
+func addChange(transaction *gorm.DB, changeType string, resourceID string, urn string, relatedID string) error {
+	if err := transaction.Exec("SELECT pg_advisory_xact_lock(?)", CHANGE_LOCK_KEY).Error; err != nil {
+		return err
+	}
+
+	change := &Change{
+		Type:       changeType,
+		ResourceID: resourceID,
+		RelatedID:  relatedID,
+		Urn:        urn,
+		CreateAt:   time.Now().UTC().UnixNano(),
+	}
+	return transaction.Create(change).Error
+}
+
+// getUrnByID retrieves the urn of a resource inside a transaction, before it is removed
+func getUrnByID(transaction *gorm.DB, model interface{}, id string) (string, error) {
+	var urns []string
+	if err := transaction.Model(model).Where("id like ?", id).Pluck("urn", &urns).Error; err != nil {
+		return "", err
+	}
+	if len(urns) == 0 {
+		return "", nil
+	}
+	return urns[0], nil
+}
+
+// addGroupRelationChange stores a change of a relation between a group and a user or a policy,
+// with the urn of the group
+func addGroupRelationChange(transaction *gorm.DB, changeType string, groupID string, relatedID string) error {
+	urn, err := getUrnByID(transaction, &Group{}, groupID)
+	if err != nil {
+		return err
+	}
+	return addChange(transaction, changeType, groupID, urn, relatedID)
+}
@@ -0,0 +1,197 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepo_GetChanges(t *testing.T) {
+	now := time.Now().UTC()
+	previousChanges := []Change{
+		{
+			Seq:        1,
+			Type:       api.EVENT_USER_CREATED,
+			ResourceID: "UserID",
+			Urn:        "urn1",
+			CreateAt:   now.UnixNano(),
+		},
+		{
+			Seq:        2,
+			Type:       api.EVENT_GROUP_MEMBER_ADDED,
+			ResourceID: "GroupID",
+			RelatedID:  "UserID",
+			Urn:        "urn2",
+			CreateAt:   now.UnixNano(),
+		},
+		{
+			Seq:        4,
+			Type:       api.EVENT_USER_DELETED,
+			ResourceID: "UserID",
+			Urn:        "urn1",
+			CreateAt:   now.UnixNano(),
+		},
+	}
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		since int64
+		limit int
+		// Expected result
+		expectedResponse []api.Change
+	}{
+		"OkCaseFromBeginning": {
+			since: 0,
+			limit: 2,
+			expectedResponse: []api.Change{
+				{
+					Seq:        1,
+					Type:       api.EVENT_USER_CREATED,
+					ResourceID: "UserID",
+					Urn:        "urn1",
+					CreateAt:   now,
+				},
+				{
+					Seq:        2,
+					Type:       api.EVENT_GROUP_MEMBER_ADDED,
+					ResourceID: "GroupID",
+					RelatedID:  "UserID",
+					Urn:        "urn2",
+					CreateAt:   now,
+				},
+			},
+		},
+		"OkCaseSince": {
+			since: 2,
+			limit: 20,
+			expectedResponse: []api.Change{
+				{
+					Seq:        4,
+					Type:       api.EVENT_USER_DELETED,
+					ResourceID: "UserID",
+					Urn:        "urn1",
+					CreateAt:   now,
+				},
+			},
+		},
+		"OkCaseNoChanges": {
+			since:            4,
+			limit:            20,
+			expectedResponse: []api.Change{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean change database
+		cleanChangeTable(t, n)
+
+		// Insert previous data
+		for _, change := range previousChanges {
+			insertChange(t, n, change)
+		}
+
+		// Call to repository to get changes
+		changes, err := repoDB.GetChanges(test.since, test.limit)
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check response
+		assert.Equal(t, test.expectedResponse, changes, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetLastChangeSeq(t *testing.T) {
+	testcases := map[string]struct {
+		// Previous data
+		previousChanges []Change
+		// Expected result
+		expectedResponse int64
+	}{
+		"OkCase": {
+			previousChanges: []Change{
+				{
+					Seq:        3,
+					Type:       api.EVENT_USER_CREATED,
+					ResourceID: "UserID",
+				},
+				{
+					Seq:        7,
+					Type:       api.EVENT_USER_DELETED,
+					ResourceID: "UserID",
+				},
+			},
+			expectedResponse: 7,
+		},
+		"OkCaseNoChanges": {
+			expectedResponse: 0,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean change database
+		cleanChangeTable(t, n)
+
+		// Insert previous data
+		for _, change := range test.previousChanges {
+			insertChange(t, n, change)
+		}
+
+		// Call to repository to get last sequence
+		seq, err := repoDB.GetLastChangeSeq()
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check response
+		assert.Equal(t, test.expectedResponse, seq, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_ChangesAreRecorded(t *testing.T) {
+	now := time.Now().UTC()
+
+	// Clean databases
+	cleanChangeTable(t, "Setup")
+	cleanUserTable(t, "Setup")
+	cleanGroupTable(t, "Setup")
+	cleanGroupUserRelationTable(t, "Setup")
+
+	user := api.User{
+		ID:         "UserID",
+		ExternalID: "ExternalID",
+		Path:       "Path",
+		Urn:        "urnUser",
+		CreateAt:   now,
+		UpdateAt:   now,
+		Enabled:    true,
+	}
+	group := api.Group{
+		ID:       "GroupID",
+		Name:     "Name",
+		Path:     "Path",
+		Urn:      "urnGroup",
+		Org:      "Org",
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	// Modify resources
+	_, err := repoDB.AddUser(user)
+	assert.Nil(t, err, "Error in test")
+	_, err = repoDB.AddGroup(group)
+	assert.Nil(t, err, "Error in test")
+	err = repoDB.AddMember(user.ID, group.ID)
+	assert.Nil(t, err, "Error in test")
+	err = repoDB.RemoveUser(user.ID)
+	assert.Nil(t, err, "Error in test")
+
+	// Check changes are recorded in order
+	changes, err := repoDB.GetChanges(0, 20)
+	assert.Nil(t, err, "Error in test")
+	types := []string{}
+	for _, c := range changes {
+		types = append(types, c.Type)
+	}
+	assert.Equal(t, []string{api.EVENT_USER_CREATED, api.EVENT_GROUP_CREATED, api.EVENT_GROUP_MEMBER_ADDED, api.EVENT_USER_DELETED},
+		types, "Error in test")
+
+	// Check change data
+	assert.Equal(t, 1, getChangesCountFiltered(t, "Check", api.EVENT_GROUP_MEMBER_ADDED, group.ID, user.ID, group.Urn), "Error in test")
+	assert.Equal(t, 1, getChangesCountFiltered(t, "Check", api.EVENT_USER_DELETED, user.ID, "", user.Urn), "Error in test")
+}
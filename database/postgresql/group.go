@@ -6,11 +6,13 @@ import (
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database"
+	"github.com/jinzhu/gorm"
 )
 
 // GROUP REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
+	defer pr.observe("AddGroup")()
 	// Create group model
 	groupDB := &Group{
 		ID:       group.ID,
@@ -22,21 +24,33 @@ func (pr PostgresRepo) AddGroup(group api.Group) (*api.Group, error) {
 		Org:      group.Org,
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store group
-	err := pr.Dbmap.Create(groupDB).Error
+	err := transaction.Create(groupDB).Error
+	if err == nil {
+		err = addGroupAttributes(transaction, groupDB.ID, group.Attributes)
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_GROUP_CREATED, groupDB.ID, groupDB.Urn, "")
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	return dbGroupToAPIGroup(groupDB), nil
+	transaction.Commit()
+	apiGroup := dbGroupToAPIGroup(groupDB)
+	apiGroup.Attributes = group.Attributes
+	return apiGroup, nil
 }
 
 func (pr PostgresRepo) GetGroupByName(org string, name string) (*api.Group, error) {
+	defer pr.observe("GetGroupByName")()
 	group := &Group{}
 	query := pr.Dbmap.Where("org like ? AND name like ?", org, name).First(group)
 
@@ -56,10 +70,11 @@ func (pr PostgresRepo) GetGroupByName(org string, name string) (*api.Group, erro
 		}
 	}
 
-	return dbGroupToAPIGroup(group), nil
+	return pr.getGroupWithAttributes(group)
 }
 
 func (pr PostgresRepo) GetGroupById(id string) (*api.Group, error) {
+	defer pr.observe("GetGroupById")()
 	group := &Group{}
 	query := pr.Dbmap.Where("id like ?", id).First(group)
 
@@ -79,10 +94,11 @@ func (pr PostgresRepo) GetGroupById(id string) (*api.Group, error) {
 		}
 	}
 
-	return dbGroupToAPIGroup(group), nil
+	return pr.getGroupWithAttributes(group)
 }
 
 func (pr PostgresRepo) GetGroupsFiltered(filter *api.Filter) ([]api.Group, int, error) {
+	defer pr.observe("GetGroupsFiltered")()
 	var total int
 	groups := []Group{}
 	query := pr.Dbmap
@@ -93,12 +109,11 @@ func (pr PostgresRepo) GetGroupsFiltered(filter *api.Filter) ([]api.Group, int,
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ? ", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query = filterByNameAndDates(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&groups).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&groups).Error; err != nil {
+	total, err := findPage(query, &groups, filter, filter.OrderBy, "id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -119,6 +134,7 @@ func (pr PostgresRepo) GetGroupsFiltered(filter *api.Filter) ([]api.Group, int,
 }
 
 func (pr PostgresRepo) UpdateGroup(group api.Group) (*api.Group, error) {
+	defer pr.observe("UpdateGroup")()
 	groupDB := Group{
 		ID:       group.ID,
 		Name:     group.Name,
@@ -129,31 +145,57 @@ func (pr PostgresRepo) UpdateGroup(group api.Group) (*api.Group, error) {
 		Org:      group.Org,
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Update group
-	query := pr.Dbmap.Model(&Group{ID: group.ID}).Updates(groupDB)
+	query := transaction.Model(&Group{ID: group.ID}).Updates(groupDB)
 
 	// Check if group exist
 	if query.RecordNotFound() {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.GROUP_NOT_FOUND,
 			Message: fmt.Sprintf("Group with name %v not found", group.Name),
 		}
 	}
 
+	err := query.Error
+	if err == nil {
+		err = transaction.Where("group_id like ?", group.ID).Delete(&GroupAttribute{}).Error
+	}
+	if err == nil {
+		err = addGroupAttributes(transaction, group.ID, group.Attributes)
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_GROUP_UPDATED, group.ID, group.Urn, "")
+	}
+
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return &group, nil
 }
 
 func (pr PostgresRepo) RemoveGroup(id string) error {
+	defer pr.observe("RemoveGroup")()
 	transaction := pr.Dbmap.Begin()
 
+	// Retrieve urn before group is deleted
+	urn, err := getUrnByID(transaction, &Group{}, id)
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	// Delete group
 	transaction.Where("id like ?", id).Delete(&Group{})
 	if err := transaction.Error; err != nil {
@@ -174,6 +216,15 @@ func (pr PostgresRepo) RemoveGroup(id string) error {
 		}
 
 	}
+	// Delete all group attributes
+	if err := transaction.Where("group_id like ?", id).Delete(&GroupAttribute{}).Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	// Delete all policy relations
 	transaction.Where("group_id like ?", id).Delete(&GroupPolicyRelation{})
 	if err := transaction.Error; err != nil {
@@ -184,11 +235,21 @@ func (pr PostgresRepo) RemoveGroup(id string) error {
 		}
 	}
 
+	// Store change
+	if err := addChange(transaction, api.EVENT_GROUP_DELETED, id, urn, ""); err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) AddMember(userID string, groupID string) error {
+	defer pr.observe("AddMember")()
 	// Create relation
 	relation := &GroupUserRelation{
 		UserID:   userID,
@@ -196,34 +257,111 @@ func (pr PostgresRepo) AddMember(userID string, groupID string) error {
 		CreateAt: time.Now().UTC().UnixNano(),
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store relation
-	err := pr.Dbmap.Create(relation).Error
+	err := transaction.Create(relation).Error
+	if err == nil {
+		err = addGroupRelationChange(transaction, api.EVENT_GROUP_MEMBER_ADDED, groupID, userID)
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) RemoveMember(userID string, groupID string) error {
-	err := pr.Dbmap.Where("user_id like ? AND group_id like ?", userID, groupID).Delete(&GroupUserRelation{}).Error
+	defer pr.observe("RemoveMember")()
+	transaction := pr.Dbmap.Begin()
+	err := transaction.Where("user_id like ? AND group_id like ?", userID, groupID).Delete(&GroupUserRelation{}).Error
+	if err == nil {
+		err = addGroupRelationChange(transaction, api.EVENT_GROUP_MEMBER_REMOVED, groupID, userID)
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
+
+	transaction.Commit()
+	return nil
+}
+
+func (pr PostgresRepo) AddMembers(userIDs []string, groupID string) error {
+	defer pr.observe("AddMembers")()
+	createAt := time.Now().UTC().UnixNano()
+
+	transaction := pr.Dbmap.Begin()
+	// Store relations, with a change for each one
+	urn, err := getUrnByID(transaction, &Group{}, groupID)
+	for _, userID := range userIDs {
+		if err != nil {
+			break
+		}
+		err = transaction.Create(&GroupUserRelation{
+			UserID:   userID,
+			GroupID:  groupID,
+			CreateAt: createAt,
+		}).Error
+		if err == nil {
+			err = addChange(transaction, api.EVENT_GROUP_MEMBER_ADDED, groupID, urn, userID)
+		}
+	}
+
+	// Error handling
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	transaction.Commit()
+	return nil
+}
+
+func (pr PostgresRepo) RemoveMembers(userIDs []string, groupID string) error {
+	defer pr.observe("RemoveMembers")()
+	transaction := pr.Dbmap.Begin()
+	err := transaction.Where("user_id IN (?) AND group_id like ?", userIDs, groupID).Delete(&GroupUserRelation{}).Error
+	var urn string
+	if err == nil {
+		urn, err = getUrnByID(transaction, &Group{}, groupID)
+	}
+	for _, userID := range userIDs {
+		if err != nil {
+			break
+		}
+		err = addChange(transaction, api.EVENT_GROUP_MEMBER_REMOVED, groupID, urn, userID)
+	}
+
+	// Error handling
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) IsMemberOfGroup(userID string, groupID string) (bool, error) {
+	defer pr.observe("IsMemberOfGroup")()
 	relation := GroupUserRelation{}
 	query := pr.Dbmap.Where("user_id like ? AND group_id like ?", userID, groupID).First(&relation)
 
@@ -243,17 +381,35 @@ func (pr PostgresRepo) IsMemberOfGroup(userID string, groupID string) (bool, err
 	return true, nil
 }
 
+func (pr PostgresRepo) FilterMembersOfGroup(userIDs []string, groupID string) ([]string, error) {
+	defer pr.observe("FilterMembersOfGroup")()
+	members := []string{}
+	if len(userIDs) == 0 {
+		return members, nil
+	}
+	relations := []GroupUserRelation{}
+	if err := pr.Dbmap.Where("user_id IN (?) AND group_id like ?", userIDs, groupID).Find(&relations).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	for _, r := range relations {
+		members = append(members, r.UserID)
+	}
+
+	return members, nil
+}
+
 func (pr PostgresRepo) GetGroupMembers(groupID string, filter *api.Filter) ([]api.UserGroupRelation, int, error) {
+	defer pr.observe("GetGroupMembers")()
 	var total int
 	members := []GroupUserRelation{}
 	query := pr.Dbmap.Where("group_id like ?", groupID)
 
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
-
 	// Error handling
-	if err := query.Find(&members).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&members).Error; err != nil {
+	total, err := findPage(query, &members, filter, filter.OrderBy, "user_id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -286,6 +442,7 @@ func (pr PostgresRepo) GetGroupMembers(groupID string, filter *api.Filter) ([]ap
 }
 
 func (pr PostgresRepo) AttachPolicy(groupID string, policyID string) error {
+	defer pr.observe("AttachPolicy")()
 	// Create relation
 	relation := &GroupPolicyRelation{
 		GroupID:  groupID,
@@ -293,36 +450,113 @@ func (pr PostgresRepo) AttachPolicy(groupID string, policyID string) error {
 		CreateAt: time.Now().UTC().UnixNano(),
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store relation
-	err := pr.Dbmap.Create(relation).Error
+	err := transaction.Create(relation).Error
+	if err == nil {
+		err = addGroupRelationChange(transaction, api.EVENT_GROUP_POLICY_ATTACHED, groupID, policyID)
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) DetachPolicy(groupID string, policyID string) error {
+	defer pr.observe("DetachPolicy")()
+	transaction := pr.Dbmap.Begin()
 	// Remove relation
-	err := pr.Dbmap.Where("group_id like ? AND policy_id like ?", groupID, policyID).Delete(&GroupPolicyRelation{}).Error
+	err := transaction.Where("group_id like ? AND policy_id like ?", groupID, policyID).Delete(&GroupPolicyRelation{}).Error
+	if err == nil {
+		err = addGroupRelationChange(transaction, api.EVENT_GROUP_POLICY_DETACHED, groupID, policyID)
+	}
+
+	// Error handling
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	transaction.Commit()
+	return nil
+}
+
+func (pr PostgresRepo) AttachPolicies(groupID string, policyIDs []string) error {
+	defer pr.observe("AttachPolicies")()
+	createAt := time.Now().UTC().UnixNano()
+
+	transaction := pr.Dbmap.Begin()
+	// Store relations, with a change for each one
+	urn, err := getUrnByID(transaction, &Group{}, groupID)
+	for _, policyID := range policyIDs {
+		if err != nil {
+			break
+		}
+		err = transaction.Create(&GroupPolicyRelation{
+			GroupID:  groupID,
+			PolicyID: policyID,
+			CreateAt: createAt,
+		}).Error
+		if err == nil {
+			err = addChange(transaction, api.EVENT_GROUP_POLICY_ATTACHED, groupID, urn, policyID)
+		}
+	}
+
+	// Error handling
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	transaction.Commit()
+	return nil
+}
+
+func (pr PostgresRepo) DetachPolicies(groupID string, policyIDs []string) error {
+	defer pr.observe("DetachPolicies")()
+	transaction := pr.Dbmap.Begin()
+	// Remove relations
+	err := transaction.Where("group_id like ? AND policy_id IN (?)", groupID, policyIDs).Delete(&GroupPolicyRelation{}).Error
+	var urn string
+	if err == nil {
+		urn, err = getUrnByID(transaction, &Group{}, groupID)
+	}
+	for _, policyID := range policyIDs {
+		if err != nil {
+			break
+		}
+		err = addChange(transaction, api.EVENT_GROUP_POLICY_DETACHED, groupID, urn, policyID)
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) IsAttachedToGroup(groupID string, policyID string) (bool, error) {
+	defer pr.observe("IsAttachedToGroup")()
 	relation := GroupPolicyRelation{}
 	query := pr.Dbmap.Where("group_id like ? AND policy_id like ?", groupID, policyID).First(&relation)
 
@@ -342,17 +576,35 @@ func (pr PostgresRepo) IsAttachedToGroup(groupID string, policyID string) (bool,
 	return true, nil
 }
 
+func (pr PostgresRepo) FilterAttachedToGroup(groupID string, policyIDs []string) ([]string, error) {
+	defer pr.observe("FilterAttachedToGroup")()
+	attached := []string{}
+	if len(policyIDs) == 0 {
+		return attached, nil
+	}
+	relations := []GroupPolicyRelation{}
+	if err := pr.Dbmap.Where("group_id like ? AND policy_id IN (?)", groupID, policyIDs).Find(&relations).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	for _, r := range relations {
+		attached = append(attached, r.PolicyID)
+	}
+
+	return attached, nil
+}
+
 func (pr PostgresRepo) GetAttachedPolicies(groupID string, filter *api.Filter) ([]api.PolicyGroupRelation, int, error) {
+	defer pr.observe("GetAttachedPolicies")()
 	var total int
 	relations := []GroupPolicyRelation{}
 	query := pr.Dbmap.Where("group_id like ?", groupID)
 
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
-
 	// Error Handling
-	if err := query.Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations).Error; err != nil {
+	total, err := findPage(query, &relations, filter, filter.OrderBy, "policy_id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -384,6 +636,43 @@ func (pr PostgresRepo) GetAttachedPolicies(groupID string, filter *api.Filter) (
 
 // PRIVATE HELPER METHODS
 
+// Transform a group retrieved from db into a group for API, with its attributes
+func (pr PostgresRepo) getGroupWithAttributes(groupdb *Group) (*api.Group, error) {
+	attributes := []GroupAttribute{}
+	if err := pr.Dbmap.Where("group_id like ?", groupdb.ID).Find(&attributes).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	group := dbGroupToAPIGroup(groupdb)
+	if len(attributes) > 0 {
+		group.Attributes = make(map[string]string, len(attributes))
+		for _, a := range attributes {
+			group.Attributes[a.Key] = a.Value
+		}
+	}
+
+	return group, nil
+}
+
+// Store attributes of a group
+func addGroupAttributes(transaction *gorm.DB, groupID string, attributes map[string]string) error {
+	for key, value := range attributes {
+		attribute := &GroupAttribute{
+			GroupID: groupID,
+			Key:     key,
+			Value:   value,
+		}
+		if err := transaction.Create(attribute).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Transform a Group retrieved from db into a group for API
 func dbGroupToAPIGroup(groupdb *Group) *api.Group {
 	return &api.Group{
@@ -21,6 +21,32 @@ func TestPostgresRepo_AddGroup(t *testing.T) {
 		expectedResponse *api.Group
 		expectedError    *database.Error
 	}{
+		"OkCaseAttributes": {
+			groupToCreate: &api.Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+				Org:      "Org",
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+			},
+			expectedResponse: &api.Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+				Org:      "Org",
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+			},
+		},
 		"OkCase": {
 			groupToCreate: &api.Group{
 				ID:       "GroupID",
@@ -70,6 +96,7 @@ func TestPostgresRepo_AddGroup(t *testing.T) {
 	for n, test := range testcases {
 		// Clean user database
 		cleanGroupTable(t, n)
+		cleanGroupAttributeTable(t, n)
 
 		// Insert previous data
 		if test.previousGroup != nil {
@@ -92,6 +119,8 @@ func TestPostgresRepo_AddGroup(t *testing.T) {
 				t.Errorf("Test %v failed. Received different group number: %v", n, groupNumber)
 				continue
 			}
+			attributesNumber := getGroupAttributesCount(t, n, test.groupToCreate.ID)
+			assert.Equal(t, len(test.groupToCreate.Attributes), attributesNumber, "Error in test case %v", n)
 		}
 	}
 }
@@ -557,7 +586,8 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousGroups []Group
+		previousGroups     []Group
+		previousAttributes []GroupAttribute
 		// Postgres Repo Args
 		groupToUpdate *api.Group
 		// Expected result
@@ -595,6 +625,55 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				Org:      "Org",
 			},
 		},
+		"OkCaseReplaceAttributes": {
+			previousGroups: []Group{
+				{
+					ID:       "GroupID",
+					Name:     "Name",
+					Path:     "Path",
+					Urn:      "Urn",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Org:      "Org",
+				},
+			},
+			previousAttributes: []GroupAttribute{
+				{
+					GroupID: "GroupID",
+					Key:     "department",
+					Value:   "backend",
+				},
+				{
+					GroupID: "GroupID",
+					Key:     "cost-center",
+					Value:   "1000",
+				},
+			},
+			groupToUpdate: &api.Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "Urn",
+				CreateAt: now,
+				UpdateAt: now,
+				Org:      "Org",
+				Attributes: map[string]string{
+					"department": "frontend",
+				},
+			},
+			expectedResponse: &api.Group{
+				ID:       "GroupID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "Urn",
+				CreateAt: now,
+				UpdateAt: now,
+				Org:      "Org",
+				Attributes: map[string]string{
+					"department": "frontend",
+				},
+			},
+		},
 		"ErrorCaseDuplicateUrn": {
 			previousGroups: []Group{
 				{
@@ -634,6 +713,7 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 	for n, test := range testcases {
 		// Clean group database
 		cleanGroupTable(t, n)
+		cleanGroupAttributeTable(t, n)
 
 		// Insert previous data
 		if test.previousGroups != nil {
@@ -641,6 +721,9 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				insertGroup(t, n, previousGroup)
 			}
 		}
+		for _, attribute := range test.previousAttributes {
+			insertGroupAttribute(t, n, attribute)
+		}
 
 		// Call to repository to update group
 		updatedGroup, err := repoDB.UpdateGroup(*test.groupToUpdate)
@@ -656,6 +739,8 @@ func TestPostgresRepo_UpdateGroup(t *testing.T) {
 				test.expectedResponse.CreateAt.UnixNano(), test.expectedResponse.UpdateAt.UnixNano(), test.expectedResponse.Urn,
 				test.expectedResponse.Org)
 			assert.Equal(t, 1, groupNumber, "Error in test case %v", n)
+			attributesNumber := getGroupAttributesCount(t, n, test.expectedResponse.ID)
+			assert.Equal(t, len(test.expectedResponse.Attributes), attributesNumber, "Error in test case %v", n)
 		}
 	}
 }
@@ -867,6 +952,99 @@ func TestPostgresRepo_RemoveMember(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_AddMembers(t *testing.T) {
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		userIDs []string
+		groupID string
+		// Expected result
+		expectedError *database.Error
+	}{
+		"OkCase": {
+			userIDs: []string{"UserID1", "UserID2"},
+			groupID: "GroupID",
+		},
+		"ErrorCaseInternalError": {
+			userIDs: []string{"UserID1", ""},
+			groupID: "GroupID",
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: null value in column \"user_id\" violates not-null constraint",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupUserRelation database
+		cleanGroupUserRelationTable(t, n)
+
+		// Call to repository to store members
+		err := repoDB.AddMembers(test.userIDs, test.groupID)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+
+			// Check database, no member is stored
+			for _, userID := range test.userIDs {
+				relations := getGroupUserRelations(t, n, test.groupID, userID)
+				assert.Equal(t, 0, relations, "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check database
+			for _, userID := range test.userIDs {
+				relations := getGroupUserRelations(t, n, test.groupID, userID)
+				assert.Equal(t, 1, relations, "Error in test case %v", n)
+			}
+		}
+	}
+}
+
+func TestPostgresRepo_RemoveMembers(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUserIDs []string
+		// Postgres Repo Args
+		userIDs []string
+		groupID string
+		// Expected result
+		expectedRemaining []string
+	}{
+		"OkCase": {
+			previousUserIDs:   []string{"UserID1", "UserID2", "UserID3"},
+			userIDs:           []string{"UserID1", "UserID2"},
+			groupID:           "GroupID",
+			expectedRemaining: []string{"UserID3"},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupUserRelation database
+		cleanGroupUserRelationTable(t, n)
+
+		// Insert previous data
+		for _, userID := range test.previousUserIDs {
+			insertGroupUserRelation(t, n, userID, test.groupID, now.UnixNano())
+		}
+
+		// Call to repository to remove members
+		err := repoDB.RemoveMembers(test.userIDs, test.groupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		for _, userID := range test.userIDs {
+			relations := getGroupUserRelations(t, n, test.groupID, userID)
+			assert.Equal(t, 0, relations, "Error in test case %v", n)
+		}
+		for _, userID := range test.expectedRemaining {
+			relations := getGroupUserRelations(t, n, test.groupID, userID)
+			assert.Equal(t, 1, relations, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestPostgresRepo_IsMemberOfGroup(t *testing.T) {
 	type relation struct {
 		userID   string
@@ -916,6 +1094,67 @@ func TestPostgresRepo_IsMemberOfGroup(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_FilterMembersOfGroup(t *testing.T) {
+	type relation struct {
+		userID   string
+		groupID  string
+		createAt int64
+	}
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		relations []relation
+		// Postgres Repo Args
+		userIDs []string
+		groupID string
+		// Expected result
+		expectedResponse []string
+	}{
+		"OkCase": {
+			relations: []relation{
+				{
+					userID:   "UserID1",
+					groupID:  "GroupID",
+					createAt: now.UnixNano(),
+				},
+				{
+					userID:   "UserID2",
+					groupID:  "GroupID",
+					createAt: now.UnixNano(),
+				},
+				{
+					userID:   "UserID3",
+					groupID:  "GroupID2",
+					createAt: now.UnixNano(),
+				},
+			},
+			userIDs:          []string{"UserID1", "UserID3", "UserID4"},
+			groupID:          "GroupID",
+			expectedResponse: []string{"UserID1"},
+		},
+		"OkCaseEmpty": {
+			userIDs:          []string{},
+			groupID:          "GroupID",
+			expectedResponse: []string{},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupUserRelationTable(t, n)
+
+		// Insert previous data
+		for _, r := range test.relations {
+			insertGroupUserRelation(t, n, r.userID, r.groupID, r.createAt)
+		}
+
+		members, err := repoDB.FilterMembersOfGroup(test.userIDs, test.groupID)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedResponse, members, "Error in test case %v", n)
+	}
+}
+
 func TestPostgresRepo_GetGroupMembers(t *testing.T) {
 	type relations struct {
 		users        []User
@@ -968,6 +1207,7 @@ func TestPostgresRepo_GetGroupMembers(t *testing.T) {
 						Urn:        "urn1",
 						CreateAt:   now,
 						UpdateAt:   now,
+						Enabled:    true,
 					},
 					CreateAt: now,
 				},
@@ -979,6 +1219,7 @@ func TestPostgresRepo_GetGroupMembers(t *testing.T) {
 						Urn:        "urn2",
 						CreateAt:   now,
 						UpdateAt:   now,
+						Enabled:    true,
 					},
 					CreateAt: now,
 				},
@@ -1020,6 +1261,7 @@ func TestPostgresRepo_GetGroupMembers(t *testing.T) {
 						Urn:        "urn1",
 						CreateAt:   now,
 						UpdateAt:   now,
+						Enabled:    true,
 					},
 					CreateAt: now,
 				},
@@ -1031,6 +1273,7 @@ func TestPostgresRepo_GetGroupMembers(t *testing.T) {
 						Urn:        "urn2",
 						CreateAt:   now,
 						UpdateAt:   now,
+						Enabled:    true,
 					},
 					CreateAt: now.Add(-1),
 				},
@@ -1174,6 +1417,99 @@ func TestPostgresRepo_DetachPolicy(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_AttachPolicies(t *testing.T) {
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		policyIDs []string
+		groupID   string
+		// Expected result
+		expectedError *database.Error
+	}{
+		"OkCase": {
+			policyIDs: []string{"PolicyID1", "PolicyID2"},
+			groupID:   "GroupID",
+		},
+		"ErrorCaseInternalError": {
+			policyIDs: []string{"PolicyID1", ""},
+			groupID:   "GroupID",
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: null value in column \"policy_id\" violates not-null constraint",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupPolicyRelation database
+		cleanGroupPolicyRelationTable(t, n)
+
+		// Call to repository to attach policies
+		err := repoDB.AttachPolicies(test.groupID, test.policyIDs)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+
+			// Check database, no policy is attached
+			for _, policyID := range test.policyIDs {
+				relations := getGroupPolicyRelationCount(t, n, policyID, test.groupID)
+				assert.Equal(t, 0, relations, "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+
+			// Check database
+			for _, policyID := range test.policyIDs {
+				relations := getGroupPolicyRelationCount(t, n, policyID, test.groupID)
+				assert.Equal(t, 1, relations, "Error in test case %v", n)
+			}
+		}
+	}
+}
+
+func TestPostgresRepo_DetachPolicies(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousPolicyIDs []string
+		// Postgres Repo Args
+		policyIDs []string
+		groupID   string
+		// Expected result
+		expectedRemaining []string
+	}{
+		"OkCase": {
+			previousPolicyIDs: []string{"PolicyID1", "PolicyID2", "PolicyID3"},
+			policyIDs:         []string{"PolicyID1", "PolicyID2"},
+			groupID:           "GroupID",
+			expectedRemaining: []string{"PolicyID3"},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean GroupPolicyRelation database
+		cleanGroupPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for _, policyID := range test.previousPolicyIDs {
+			insertGroupPolicyRelation(t, n, test.groupID, policyID, now.UnixNano())
+		}
+
+		// Call to repository to detach policies
+		err := repoDB.DetachPolicies(test.groupID, test.policyIDs)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		for _, policyID := range test.policyIDs {
+			relations := getGroupPolicyRelationCount(t, n, policyID, test.groupID)
+			assert.Equal(t, 0, relations, "Error in test case %v", n)
+		}
+		for _, policyID := range test.expectedRemaining {
+			relations := getGroupPolicyRelationCount(t, n, policyID, test.groupID)
+			assert.Equal(t, 1, relations, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestPostgresRepo_IsAttachedToGroup(t *testing.T) {
 	type relation struct {
 		groupID  string
@@ -1228,6 +1564,67 @@ func TestPostgresRepo_IsAttachedToGroup(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_FilterAttachedToGroup(t *testing.T) {
+	type relation struct {
+		policyID string
+		groupID  string
+		createAt int64
+	}
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		relations []relation
+		// Postgres Repo Args
+		policyIDs []string
+		groupID   string
+		// Expected result
+		expectedResponse []string
+	}{
+		"OkCase": {
+			relations: []relation{
+				{
+					policyID: "PolicyID1",
+					groupID:  "GroupID",
+					createAt: now.UnixNano(),
+				},
+				{
+					policyID: "PolicyID2",
+					groupID:  "GroupID",
+					createAt: now.UnixNano(),
+				},
+				{
+					policyID: "PolicyID3",
+					groupID:  "GroupID2",
+					createAt: now.UnixNano(),
+				},
+			},
+			policyIDs:        []string{"PolicyID1", "PolicyID3", "PolicyID4"},
+			groupID:          "GroupID",
+			expectedResponse: []string{"PolicyID1"},
+		},
+		"OkCaseEmpty": {
+			policyIDs:        []string{},
+			groupID:          "GroupID",
+			expectedResponse: []string{},
+		},
+	}
+
+	for n, test := range testcases {
+		cleanGroupPolicyRelationTable(t, n)
+
+		// Insert previous data
+		for _, r := range test.relations {
+			insertGroupPolicyRelation(t, n, r.groupID, r.policyID, r.createAt)
+		}
+
+		policies, err := repoDB.FilterAttachedToGroup(test.groupID, test.policyIDs)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedResponse, policies, "Error in test case %v", n)
+	}
+}
+
 func TestPostgresRepo_GetAttachedPolicies(t *testing.T) {
 	type relations struct {
 		policies       []Policy
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 // POLICY REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
+	defer pr.observe("AddPolicy")()
 	// Create policy model
 	policyDB := &Policy{
 		ID:       policy.ID,
@@ -39,11 +41,12 @@ func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
 	for _, statementApi := range *policy.Statements {
 		// Create statement model
 		statementDB := &Statement{
-			ID:        uuid.NewV4().String(),
-			PolicyID:  policy.ID,
-			Effect:    statementApi.Effect,
-			Actions:   stringArrayToString(statementApi.Actions),
-			Resources: stringArrayToString(statementApi.Resources),
+			ID:         uuid.NewV4().String(),
+			PolicyID:   policy.ID,
+			Effect:     statementApi.Effect,
+			Actions:    stringArrayToString(statementApi.Actions),
+			Resources:  stringArrayToString(statementApi.Resources),
+			Conditions: conditionsToString(statementApi.Conditions),
 		}
 		if err := transaction.Create(statementDB).Error; err != nil {
 			transaction.Rollback()
@@ -54,6 +57,15 @@ func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
 		}
 	}
 
+	// Store change
+	if err := addChange(transaction, api.EVENT_POLICY_CREATED, policy.ID, policy.Urn, ""); err != nil {
+		transaction.Rollback()
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	transaction.Commit()
 
 	// Create API policy
@@ -64,6 +76,7 @@ func (pr PostgresRepo) AddPolicy(policy api.Policy) (*api.Policy, error) {
 }
 
 func (pr PostgresRepo) GetPolicyByName(org string, name string) (*api.Policy, error) {
+	defer pr.observe("GetPolicyByName")()
 	policy := &Policy{}
 	query := pr.Dbmap.Where("org like ? AND name like ?", org, name).First(policy)
 
@@ -101,7 +114,48 @@ func (pr PostgresRepo) GetPolicyByName(org string, name string) (*api.Policy, er
 	return policyApi, nil
 }
 
+func (pr PostgresRepo) GetPoliciesByNames(org string, names []string) ([]api.Policy, error) {
+	defer pr.observe("GetPoliciesByNames")()
+	policies := []Policy{}
+	if err := pr.Dbmap.Where("org like ? AND name IN (?)", org, names).Find(&policies).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	if len(policies) == 0 {
+		return []api.Policy{}, nil
+	}
+
+	// Retrieve statements of all the policies at once
+	policyIDs := make([]string, len(policies))
+	for i, p := range policies {
+		policyIDs[i] = p.ID
+	}
+	statements := []Statement{}
+	if err := pr.Dbmap.Where("policy_id IN (?)", policyIDs).Find(&statements).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	statementsByPolicy := map[string][]Statement{}
+	for _, s := range statements {
+		statementsByPolicy[s.PolicyID] = append(statementsByPolicy[s.PolicyID], s)
+	}
+
+	// Create API policies
+	apiPolicies := make([]api.Policy, len(policies))
+	for i, p := range policies {
+		apiPolicies[i] = *dbPolicyToAPIPolicy(&p)
+		apiPolicies[i].Statements = dbStatementsToAPIStatements(statementsByPolicy[p.ID])
+	}
+
+	return apiPolicies, nil
+}
+
 func (pr PostgresRepo) GetPolicyById(id string) (*api.Policy, error) {
+	defer pr.observe("GetPolicyById")()
 	policy := &Policy{}
 	query := pr.Dbmap.Where("id like ?", id).First(&policy)
 
@@ -140,6 +194,7 @@ func (pr PostgresRepo) GetPolicyById(id string) (*api.Policy, error) {
 }
 
 func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, int, error) {
+	defer pr.observe("GetPoliciesFiltered")()
 	var total int
 	policies := []Policy{}
 	query := pr.Dbmap
@@ -150,12 +205,20 @@ func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, in
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ?", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
+	query = filterByNameAndDates(query, filter, "name")
+	// Statement actions and resources end with * when they are prefixes
+	if len(filter.Action) > 0 {
+		query = query.Where("id IN (SELECT policy_id FROM statements, unnest(string_to_array(actions, ';')) AS a "+
+			"WHERE a = ? OR (a LIKE '%*' AND left(?::text, length(rtrim(a, '*'))) = rtrim(a, '*')))", filter.Action, filter.Action)
+	}
+	if len(filter.Resource) > 0 {
+		query = query.Where("id IN (SELECT policy_id FROM statements, unnest(string_to_array(resources, ';')) AS r "+
+			"WHERE r = ? OR (r LIKE '%*' AND left(?::text, length(rtrim(r, '*'))) = rtrim(r, '*')))", filter.Resource, filter.Resource)
 	}
 
 	// Error handling
-	if err := query.Find(&policies).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&policies).Error; err != nil {
+	total, err := findPage(query, &policies, filter, filter.OrderBy, "id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -193,6 +256,7 @@ func (pr PostgresRepo) GetPoliciesFiltered(filter *api.Filter) ([]api.Policy, in
 }
 
 func (pr PostgresRepo) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
+	defer pr.observe("UpdatePolicy")()
 
 	policyDB := Policy{
 		ID:       policy.ID,
@@ -227,11 +291,12 @@ func (pr PostgresRepo) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
 	// Create new statements
 	for _, s := range *policy.Statements {
 		statementDB := &Statement{
-			ID:        uuid.NewV4().String(),
-			PolicyID:  policy.ID,
-			Effect:    s.Effect,
-			Actions:   stringArrayToString(s.Actions),
-			Resources: stringArrayToString(s.Resources),
+			ID:         uuid.NewV4().String(),
+			PolicyID:   policy.ID,
+			Effect:     s.Effect,
+			Actions:    stringArrayToString(s.Actions),
+			Resources:  stringArrayToString(s.Resources),
+			Conditions: conditionsToString(s.Conditions),
 		}
 		if err := transaction.Create(statementDB).Error; err != nil {
 			transaction.Rollback()
@@ -242,15 +307,35 @@ func (pr PostgresRepo) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
 		}
 	}
 
+	// Store change
+	if err := addChange(transaction, api.EVENT_POLICY_UPDATED, policy.ID, policy.Urn, ""); err != nil {
+		transaction.Rollback()
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	transaction.Commit()
 
 	return &policy, nil
 }
 
 func (pr PostgresRepo) RemovePolicy(id string) error {
+	defer pr.observe("RemovePolicy")()
 
 	transaction := pr.Dbmap.Begin()
 
+	// Retrieve urn before policy is deleted
+	urn, err := getUrnByID(transaction, &Policy{}, id)
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	// Delete policy relations (group)
 	transaction.Where("policy_id like ?", id).Delete(&GroupPolicyRelation{})
 	if err := transaction.Error; err != nil {
@@ -278,24 +363,28 @@ func (pr PostgresRepo) RemovePolicy(id string) error {
 			Message: err.Error(),
 		}
 	}
+	// Store change
+	if err := addChange(transaction, api.EVENT_POLICY_DELETED, id, urn, ""); err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
 
 	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) GetAttachedGroups(policyID string, filter *api.Filter) ([]api.PolicyGroupRelation, int, error) {
+	defer pr.observe("GetAttachedGroups")()
 	var total int
 	relations := []GroupPolicyRelation{}
-	query := pr.Dbmap
-
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
-
-	query.Where("policy_id like ?", policyID).Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations)
+	query := pr.Dbmap.Where("policy_id like ?", policyID)
 
 	// Error Handling
-	if err := query.Error; err != nil {
+	total, err := findPage(query, &relations, filter, filter.OrderBy, "group_id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -346,9 +435,10 @@ func dbStatementsToAPIStatements(statements []Statement) *[]api.Statement {
 	statementsApi := make([]api.Statement, len(statements), cap(statements))
 	for i, s := range statements {
 		statementsApi[i] = api.Statement{
-			Actions:   strings.Split(s.Actions, ";"),
-			Effect:    s.Effect,
-			Resources: strings.Split(s.Resources, ";"),
+			Actions:    strings.Split(s.Actions, ";"),
+			Effect:     s.Effect,
+			Resources:  strings.Split(s.Resources, ";"),
+			Conditions: stringToConditions(s.Conditions),
 		}
 	}
 
@@ -368,3 +458,29 @@ func stringArrayToString(array []string) string {
 
 	return stringVal
 }
+
+// Transform statement conditions into a JSON string, empty if there aren't conditions
+func conditionsToString(conditions []api.Condition) string {
+	if len(conditions) < 1 {
+		return ""
+	}
+	stringVal, err := json.Marshal(conditions)
+	if err != nil {
+		return ""
+	}
+
+	return string(stringVal)
+}
+
+// Transform a JSON string into statement conditions
+func stringToConditions(stringVal string) []api.Condition {
+	if len(stringVal) == 0 {
+		return nil
+	}
+	conditions := []api.Condition{}
+	if err := json.Unmarshal([]byte(stringVal), &conditions); err != nil {
+		return nil
+	}
+
+	return conditions
+}
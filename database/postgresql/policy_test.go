@@ -196,6 +196,56 @@ func TestPostgresRepo_GetPolicyByName(t *testing.T) {
 				},
 			},
 		},
+		"OkCaseConditions": {
+			org:  "org1",
+			name: "test",
+			policy: &Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+			},
+			statements: []Statement{
+				{
+					ID:         "0123",
+					Effect:     "allow",
+					PolicyID:   "1234",
+					Actions:    api.USER_ACTION_GET_USER,
+					Resources:  api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+					Conditions: `[{"operator":"equals","attribute":"user:clearance","values":["high"]}]`,
+				},
+			},
+			expectedResponse: &api.Policy{
+				ID:       "1234",
+				Name:     "test",
+				Org:      "org1",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				Statements: &[]api.Statement{
+					{
+						Effect: "allow",
+						Actions: []string{
+							api.USER_ACTION_GET_USER,
+						},
+						Resources: []string{
+							api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+						},
+						Conditions: []api.Condition{
+							{
+								Operator:  api.CONDITION_OPERATOR_EQUALS,
+								Attribute: "user:clearance",
+								Values:    []string{"high"},
+							},
+						},
+					},
+				},
+			},
+		},
 		"ErrorCaseNotFound": {
 			org:  "org1",
 			name: "test",
@@ -228,6 +278,103 @@ func TestPostgresRepo_GetPolicyByName(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_GetPoliciesByNames(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		policies   []Policy
+		statements []Statement
+		// Postgres Repo Args
+		org   string
+		names []string
+		// Expected result
+		expectedResponse []api.Policy
+	}{
+		"OkCase": {
+			org:   "org1",
+			names: []string{"test", "notExist"},
+			policies: []Policy{
+				{
+					ID:       "1234",
+					Name:     "test",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+				},
+				{
+					ID:       "5678",
+					Name:     "test",
+					Org:      "org2",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org2", api.RESOURCE_POLICY, "/path/", "test"),
+				},
+			},
+			statements: []Statement{
+				{
+					ID:        "0123",
+					Effect:    "allow",
+					PolicyID:  "1234",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+				},
+			},
+			expectedResponse: []api.Policy{
+				{
+					ID:       "1234",
+					Name:     "test",
+					Org:      "org1",
+					Path:     "/path/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path/", "test"),
+					Statements: &[]api.Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								api.USER_ACTION_GET_USER,
+							},
+							Resources: []string{
+								api.GetUrnPrefix("", api.RESOURCE_USER, "/path/"),
+							},
+						},
+					},
+				},
+			},
+		},
+		"OkCaseNotFound": {
+			org:              "org1",
+			names:            []string{"notExist"},
+			expectedResponse: []api.Policy{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean policy database
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+
+		// Insert previous data
+		for _, policy := range test.policies {
+			statements := []Statement{}
+			for _, statement := range test.statements {
+				if statement.PolicyID == policy.ID {
+					statements = append(statements, statement)
+				}
+			}
+			insertPolicy(t, n, policy, statements)
+		}
+		// Call to repository to get policies
+		receivedPolicies, err := repoDB.GetPoliciesByNames(test.org, test.names)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedResponse, receivedPolicies, "Error in test case %v", n)
+	}
+}
+
 func TestPostgresRepo_GetPolicyById(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -408,6 +555,172 @@ func TestPostgresRepo_GetPoliciesFiltered(t *testing.T) {
 				},
 			},
 		},
+		"OkCaseAction": {
+			filter: &api.Filter{
+				Action:  api.USER_ACTION_DELETE_USER,
+				Limit:   20,
+				OrderBy: "name asc",
+			},
+			policies: []Policy{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Org:      "org1",
+					Path:     "/path1/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Org:      "org1",
+					Path:     "/path2/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+				},
+				{
+					ID:       "333",
+					Name:     "test3",
+					Org:      "org1",
+					Path:     "/path3/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path3/", "test3"),
+				},
+			},
+			statements: []Statement{
+				{
+					ID:        "1",
+					Effect:    "allow",
+					PolicyID:  "111",
+					Actions:   api.USER_ACTION_GET_USER + ";" + api.USER_ACTION_DELETE_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+				},
+				{
+					ID:        "2",
+					Effect:    "allow",
+					PolicyID:  "222",
+					Actions:   "iam:*",
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+				},
+				{
+					ID:        "3",
+					Effect:    "allow",
+					PolicyID:  "333",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path3/"),
+				},
+			},
+			expectedResponse: []api.Policy{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Org:      "org1",
+					Path:     "/path1/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+					Statements: &[]api.Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								api.USER_ACTION_GET_USER,
+								api.USER_ACTION_DELETE_USER,
+							},
+							Resources: []string{
+								api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+							},
+						},
+					},
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Org:      "org1",
+					Path:     "/path2/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+					Statements: &[]api.Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								"iam:*",
+							},
+							Resources: []string{
+								api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+							},
+						},
+					},
+				},
+			},
+		},
+		"OkCaseResource": {
+			filter: &api.Filter{
+				Resource: api.CreateUrn("", api.RESOURCE_USER, "/path1/", "user1"),
+				Limit:    20,
+			},
+			policies: []Policy{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Org:      "org1",
+					Path:     "/path1/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Org:      "org1",
+					Path:     "/path2/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path2/", "test2"),
+				},
+			},
+			statements: []Statement{
+				{
+					ID:        "1",
+					Effect:    "allow",
+					PolicyID:  "111",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+				},
+				{
+					ID:        "2",
+					Effect:    "allow",
+					PolicyID:  "222",
+					Actions:   api.USER_ACTION_GET_USER,
+					Resources: api.GetUrnPrefix("", api.RESOURCE_USER, "/path2/"),
+				},
+			},
+			expectedResponse: []api.Policy{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Org:      "org1",
+					Path:     "/path1/",
+					CreateAt: now,
+					UpdateAt: now,
+					Urn:      api.CreateUrn("org1", api.RESOURCE_POLICY, "/path1/", "test1"),
+					Statements: &[]api.Statement{
+						{
+							Effect: "allow",
+							Actions: []string{
+								api.USER_ACTION_GET_USER,
+							},
+							Resources: []string{
+								api.GetUrnPrefix("", api.RESOURCE_USER, "/path1/"),
+							},
+						},
+					},
+				},
+			},
+		},
 		"OKCaseNotFound": {
 			filter: &api.Filter{
 				PathPrefix: "test",
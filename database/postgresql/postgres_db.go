@@ -1,17 +1,49 @@
 package postgresql
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/metrics"
+	"github.com/Tecsisa/foulkon/tracing"
 	"github.com/jinzhu/gorm"
 	_ "github.com/lib/pq" //GORM needs to import the lib/pq driver
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PostgresRepo struct {
 	Dbmap *gorm.DB
+
+	// Context of the request whose operations are traced, if any
+	ctx context.Context
+}
+
+// WithContext returns a copy of the repository whose operations are traced as children of the span in ctx
+func (pr PostgresRepo) WithContext(ctx context.Context) interface{} {
+	pr.ctx = ctx
+	return pr
+}
+
+// observe records a span and query metrics of a repository method. It returns the function
+// that ends them, so it's intended to be deferred
+func (pr PostgresRepo) observe(method string) func() {
+	start := time.Now()
+	// Operations outside of a request, like proxy refresh, aren't traced
+	span := trace.SpanFromContext(context.Background())
+	if pr.ctx != nil {
+		_, span = tracing.Start(pr.ctx, "PostgresRepo."+method, trace.WithSpanKind(trace.SpanKindClient))
+		span.SetAttributes(attribute.String("db.system", "postgresql"))
+	}
+	return func() {
+		span.End()
+		metrics.ObserveDbQuery(method, start)
+	}
 }
 
 func InitDb(datasourcename string, idleConns string, maxOpenConns string, connTTL string) (*gorm.DB, error) {
@@ -45,8 +77,8 @@ func InitDb(datasourcename string, idleConns string, maxOpenConns string, connTT
 	}
 
 	// Create tables if not exist
-	err = db.AutoMigrate(&User{}, &Group{}, &Policy{}, &Statement{}, &GroupUserRelation{}, &GroupPolicyRelation{},
-		&ProxyResource{}, &OidcProvider{}, &OidcClient{}).Error
+	err = db.AutoMigrate(&User{}, &UserAttribute{}, &Group{}, &GroupAttribute{}, &Policy{}, &Statement{},
+		&GroupUserRelation{}, &GroupPolicyRelation{}, &ProxyResource{}, &OidcProvider{}, &OidcClient{}, &OidcGroupMapping{}, &Webhook{}, &WebhookDelivery{}, &ApiKey{}, &Change{}).Error
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +94,9 @@ type User struct {
 	CreateAt   int64  `gorm:"not null"`
 	UpdateAt   int64  `gorm:"not null"`
 	Urn        string `gorm:"not null;unique"`
+	// Existing users are enabled when the column is added
+	Enabled        bool   `gorm:"not null;default:true"`
+	DisabledReason string `gorm:"not null;default:''"`
 }
 
 // User's table name
@@ -69,6 +104,18 @@ func (User) TableName() string {
 	return "users"
 }
 
+// User attribute table
+type UserAttribute struct {
+	UserID string `gorm:"primary_key"`
+	Key    string `gorm:"primary_key"`
+	Value  string `gorm:"not null"`
+}
+
+// UserAttribute's table name
+func (UserAttribute) TableName() string {
+	return "user_attributes"
+}
+
 // Group table
 type Group struct {
 	ID       string `gorm:"primary_key"`
@@ -85,6 +132,18 @@ func (Group) TableName() string {
 	return "groups"
 }
 
+// Group attribute table
+type GroupAttribute struct {
+	GroupID string `gorm:"primary_key"`
+	Key     string `gorm:"primary_key"`
+	Value   string `gorm:"not null"`
+}
+
+// GroupAttribute's table name
+func (GroupAttribute) TableName() string {
+	return "group_attributes"
+}
+
 // Policy table
 type Policy struct {
 	ID       string `gorm:"primary_key"`
@@ -103,11 +162,12 @@ func (Policy) TableName() string {
 
 // Statement table
 type Statement struct {
-	ID        string `gorm:"primary_key"`
-	PolicyID  string `gorm:"not null"`
-	Effect    string `gorm:"not null"`
-	Actions   string `gorm:"not null"`
-	Resources string `gorm:"not null"`
+	ID         string `gorm:"primary_key"`
+	PolicyID   string `gorm:"not null"`
+	Effect     string `gorm:"not null"`
+	Actions    string `gorm:"not null"`
+	Resources  string `gorm:"not null"`
+	Conditions string `gorm:"not null;default:''"`
 }
 
 // Statement's table name
@@ -160,11 +220,138 @@ func (pr PostgresRepo) OrderByValidColumns(action string) []string {
 			"urn_resource", "urn", "action", "create_at", "update_at"}
 	case api.AUTH_OIDC_ACTION_LIST_PROVIDERS:
 		return []string{"name", "path", "create_at", "update_at", "urn"}
+	case api.WEBHOOK_ACTION_LIST_WEBHOOKS:
+		return []string{"name", "path", "url", "create_at", "update_at", "urn"}
+	case api.WEBHOOK_ACTION_LIST_DELIVERIES:
+		return []string{"event_type", "attempt", "status_code", "create_at"}
+	case api.API_KEY_ACTION_LIST_API_KEYS:
+		return []string{"name", "create_at", "update_at", "expire_at", "last_used_at"}
 	default:
 		return nil
 	}
 }
 
+// Add name search and date ranges of filter to query, searching names in nameColumn
+func filterByNameAndDates(query *gorm.DB, filter *api.Filter, nameColumn string) *gorm.DB {
+	if len(filter.NamePrefix) > 0 {
+		query = query.Where(nameColumn+" like ?", escapeLike(filter.NamePrefix)+"%")
+	}
+	if len(filter.NameContains) > 0 {
+		query = query.Where(nameColumn+" like ?", "%"+escapeLike(filter.NameContains)+"%")
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("create_at >= ?", filter.CreatedAfter.UnixNano())
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("create_at <= ?", filter.CreatedBefore.UnixNano())
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		query = query.Where("update_at >= ?", filter.UpdatedAfter.UnixNano())
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		query = query.Where("update_at <= ?", filter.UpdatedBefore.UnixNano())
+	}
+	return query
+}
+
+// Escape wildcards of like patterns, so names with underscores are searched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Find a page of the query results in out, a pointer to a slice of table structs, and return the total
+// number of results. Results are sorted by orderBy, if any, and then by keyColumn, that must be unique
+// in the results. The page is selected by filter cursor, or by offset if there isn't a cursor, and filter
+// is updated with the cursors of the next and previous pages, if there are more results.
+func findPage(query *gorm.DB, out interface{}, filter *api.Filter, orderBy string, keyColumn string) (int, error) {
+	var total int
+	if err := query.Model(out).Count(&total).Error; err != nil {
+		return total, err
+	}
+
+	column, desc := keyColumn, false
+	if fields := strings.Fields(orderBy); len(fields) > 0 {
+		column = fields[0]
+		desc = len(fields) > 1 && strings.ToLower(fields[1]) == "desc"
+	}
+
+	cursor := &api.Cursor{}
+	if len(filter.Cursor) > 0 {
+		var err error
+		if cursor, err = api.DecodeCursor(filter.Cursor); err != nil {
+			return total, err
+		}
+	} else {
+		query = query.Offset(filter.Offset)
+	}
+
+	// Previous pages are read backwards from the cursor, and then reversed
+	direction, operator := "asc", ">"
+	if desc != cursor.Prev {
+		direction, operator = "desc", "<"
+	}
+	if column == keyColumn {
+		query = query.Order(keyColumn + " " + direction)
+		if len(cursor.Key) > 0 {
+			query = query.Where(keyColumn+" "+operator+" ?", cursor.Key)
+		}
+	} else {
+		query = query.Order(column + " " + direction).Order(keyColumn + " " + direction)
+		if len(cursor.Key) > 0 {
+			query = query.Where("("+column+", "+keyColumn+") "+operator+" (?, ?)", cursor.Value, cursor.Key)
+		}
+	}
+
+	// Read an extra result to know if there are more pages. Without limit, all results are read.
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit + 1)
+	}
+	if err := query.Find(out).Error; err != nil {
+		return total, err
+	}
+	results := reflect.ValueOf(out).Elem()
+	more := filter.Limit > 0 && results.Len() > filter.Limit
+	if more {
+		results.Set(results.Slice(0, filter.Limit))
+	}
+	if results.Len() == 0 {
+		return total, nil
+	}
+	if cursor.Prev {
+		for i, j := 0, results.Len()-1; i < j; i, j = i+1, j-1 {
+			result := reflect.New(results.Type().Elem()).Elem()
+			result.Set(results.Index(i))
+			results.Index(i).Set(results.Index(j))
+			results.Index(j).Set(result)
+		}
+	}
+
+	// Create cursors from the first and last results of the page
+	cursorOf := func(result reflect.Value, prev bool) string {
+		scope := query.NewScope(result.Addr().Interface())
+		pageCursor := api.Cursor{
+			OrderBy: filter.OrderBy,
+			Prev:    prev,
+		}
+		if field, ok := scope.FieldByName(keyColumn); ok {
+			pageCursor.Key = fmt.Sprint(field.Field.Interface())
+		}
+		if field, ok := scope.FieldByName(column); ok && column != keyColumn {
+			pageCursor.Value = fmt.Sprint(field.Field.Interface())
+		}
+		return pageCursor.Encode()
+	}
+	// Pages read backwards always have a next page, the one where their cursor was created
+	paging := len(cursor.Key) > 0
+	if more && !cursor.Prev || paging && cursor.Prev {
+		filter.NextCursor = cursorOf(results.Index(results.Len()-1), false)
+	}
+	if more && cursor.Prev || paging && !cursor.Prev || !paging && filter.Offset > 0 {
+		filter.PrevCursor = cursorOf(results.Index(0), true)
+	}
+	return total, nil
+}
+
 // ProxyResource table
 type ProxyResource struct {
 	ID           string `gorm:"primary_key"`
@@ -177,6 +364,7 @@ type ProxyResource struct {
 	UrnResource  string `gorm:"not null;unique_index:idx_resource"`
 	Urn          string `gorm:"not null"`
 	Action       string `gorm:"not null;unique_index:idx_resource"`
+	Tags         string `gorm:"not null;default:''"`
 	CreateAt     int64  `gorm:"not null"`
 	UpdateAt     int64  `gorm:"not null"`
 }
@@ -188,13 +376,16 @@ func (ProxyResource) TableName() string {
 
 // Auth OIDC Provider table
 type OidcProvider struct {
-	ID        string `gorm:"primary_key"`
-	Name      string `gorm:"not null"`
-	Path      string `gorm:"not null"`
-	Urn       string `gorm:"not null;unique"`
-	CreateAt  int64  `gorm:"not null"`
-	UpdateAt  int64  `gorm:"not null"`
-	IssuerURL string `gorm:"not null"`
+	ID               string `gorm:"primary_key"`
+	Name             string `gorm:"not null"`
+	Path             string `gorm:"not null"`
+	Urn              string `gorm:"not null;unique"`
+	CreateAt         int64  `gorm:"not null"`
+	UpdateAt         int64  `gorm:"not null"`
+	IssuerURL        string `gorm:"not null"`
+	ProvisionUsers   bool   `gorm:"not null;default:false"`
+	UserPathTemplate string `gorm:"not null;default:''"`
+	GroupMappingMode string `gorm:"not null;default:''"`
 }
 
 // OidcProvider's table name
@@ -213,3 +404,86 @@ type OidcClient struct {
 func (OidcClient) TableName() string {
 	return "oidc_clients"
 }
+
+// Auth OIDC group mapping table
+type OidcGroupMapping struct {
+	ID             string `gorm:"primary_key"`
+	OidcProviderID string `gorm:"not null;index"`
+	Claim          string `gorm:"not null"`
+	Value          string `gorm:"not null"`
+	Org            string `gorm:"not null"`
+	GroupName      string `gorm:"not null"`
+}
+
+// OidcGroupMapping's table name
+func (OidcGroupMapping) TableName() string {
+	return "oidc_group_mappings"
+}
+
+// Webhook table
+type Webhook struct {
+	ID       string `gorm:"primary_key"`
+	Name     string `gorm:"not null;unique"`
+	Path     string `gorm:"not null"`
+	Urn      string `gorm:"not null;unique"`
+	CreateAt int64  `gorm:"not null"`
+	UpdateAt int64  `gorm:"not null"`
+	URL      string `gorm:"not null"`
+	Secret   string `gorm:"not null"`
+	Events   string `gorm:"not null"`
+}
+
+// Webhook's table name
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Webhook delivery attempt table
+type WebhookDelivery struct {
+	ID         string `gorm:"primary_key"`
+	WebhookID  string `gorm:"not null;index"`
+	EventID    string `gorm:"not null"`
+	EventType  string `gorm:"not null"`
+	Attempt    int    `gorm:"not null"`
+	StatusCode int
+	Error      string
+	Success    bool
+	CreateAt   int64 `gorm:"not null"`
+}
+
+// WebhookDelivery's table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// API key table. Expiration and last use are 0 if they aren't set
+type ApiKey struct {
+	ID         string `gorm:"primary_key"`
+	Name       string `gorm:"not null"`
+	UserID     string `gorm:"not null;index"`
+	SecretHash string `gorm:"not null"`
+	CreateAt   int64  `gorm:"not null"`
+	UpdateAt   int64  `gorm:"not null"`
+	ExpireAt   int64
+	LastUsedAt int64
+}
+
+// ApiKey's table name
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+// Change feed table. Seq is a bigserial column
+type Change struct {
+	Seq        int64  `gorm:"primary_key"`
+	Type       string `gorm:"not null"`
+	ResourceID string `gorm:"not null"`
+	RelatedID  string
+	Urn        string
+	CreateAt   int64 `gorm:"not null"`
+}
+
+// Change's table name
+func (Change) TableName() string {
+	return "changes"
+}
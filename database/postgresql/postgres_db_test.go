@@ -110,6 +110,18 @@ func TestPostgresRepo_OrderByValidColumns(t *testing.T) {
 			expectedColumns: []string{"name", "path", "org", "host", "path_resource", "method",
 				"urn_resource", "urn", "action", "create_at", "update_at"},
 		},
+		"OkCaseAction-" + api.WEBHOOK_ACTION_LIST_WEBHOOKS: {
+			action:          api.WEBHOOK_ACTION_LIST_WEBHOOKS,
+			expectedColumns: []string{"name", "path", "url", "create_at", "update_at", "urn"},
+		},
+		"OkCaseAction-" + api.WEBHOOK_ACTION_LIST_DELIVERIES: {
+			action:          api.WEBHOOK_ACTION_LIST_DELIVERIES,
+			expectedColumns: []string{"event_type", "attempt", "status_code", "create_at"},
+		},
+		"OkCaseAction-" + api.API_KEY_ACTION_LIST_API_KEYS: {
+			action:          api.API_KEY_ACTION_LIST_API_KEYS,
+			expectedColumns: []string{"name", "create_at", "update_at", "expire_at", "last_used_at"},
+		},
 		"OkCaseOtherActions": {
 			action:          "other",
 			expectedColumns: nil,
@@ -122,6 +134,104 @@ func TestPostgresRepo_OrderByValidColumns(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_FindPageWithoutLimit(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		userID string
+		groups int
+		// Postgres Repo Args
+		filter *api.Filter
+	}{
+		"OkCaseEmptyFilter": {
+			userID: "UserID",
+			groups: 3,
+			filter: &api.Filter{},
+		},
+		"OkCaseOffsetWithoutLimit": {
+			userID: "UserID",
+			groups: 3,
+			filter: &api.Filter{
+				Offset: 1,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean database
+		cleanUserTable(t, n)
+		cleanGroupTable(t, n)
+		cleanGroupUserRelationTable(t, n)
+		cleanPolicyTable(t, n)
+		cleanStatementTable(t, n)
+		cleanGroupPolicyRelationTable(t, n)
+		cleanProxyResourcesTable(t, n)
+
+		// Insert previous data, every group has a policy for each group of user
+		for i := 0; i < test.groups; i++ {
+			groupID := fmt.Sprintf("GroupID%v", i)
+			insertGroup(t, n, Group{
+				ID:       groupID,
+				Name:     fmt.Sprintf("Name%v", i),
+				Path:     "/path/",
+				Urn:      fmt.Sprintf("urn%v", i),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				Org:      "Org",
+			})
+			insertGroupUserRelation(t, n, test.userID, groupID, now.UnixNano())
+			for j := 0; j < test.groups; j++ {
+				policyID := fmt.Sprintf("PolicyID%v-%v", i, j)
+				insertPolicy(t, n, Policy{
+					ID:       policyID,
+					Name:     fmt.Sprintf("Name%v-%v", i, j),
+					Org:      "Org",
+					Path:     "/path/",
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					Urn:      fmt.Sprintf("urn%v-%v", i, j),
+				}, nil)
+				insertGroupPolicyRelation(t, n, groupID, policyID, now.UnixNano())
+			}
+			insertProxyResource(t, n, ProxyResource{
+				ID:           fmt.Sprintf("ID%v", i),
+				Name:         fmt.Sprintf("name%v", i),
+				Path:         "/path/",
+				Org:          "Org",
+				Host:         "host",
+				PathResource: "/path",
+				Method:       "GET",
+				UrnResource:  "urnr",
+				Action:       "action",
+				Urn:          fmt.Sprintf("urn%v", i),
+				CreateAt:     now.UnixNano(),
+				UpdateAt:     now.UnixNano(),
+			})
+		}
+		expectedLen := test.groups - test.filter.Offset
+
+		// All groups of user are returned
+		groups, total, err := repoDB.GetGroupsByUserID(test.userID, test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.groups, total, "Error in test case %v", n)
+		assert.Equal(t, expectedLen, len(groups), "Error in test case %v", n)
+
+		// All policies of each group are returned
+		for i := 0; i < test.groups; i++ {
+			policies, total, err := repoDB.GetAttachedPolicies(fmt.Sprintf("GroupID%v", i), test.filter)
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.groups, total, "Error in test case %v", n)
+			assert.Equal(t, expectedLen, len(policies), "Error in test case %v", n)
+		}
+
+		// All proxy resources are returned
+		resources, total, err := repoDB.GetProxyResources(test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.groups, total, "Error in test case %v", n)
+		assert.Equal(t, expectedLen, len(resources), "Error in test case %v", n)
+	}
+}
+
 // Aux methods
 
 func insertUser(t *testing.T, testcase string, user User) {
@@ -132,6 +242,13 @@ func insertUser(t *testing.T, testcase string, user User) {
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func disableUser(t *testing.T, testcase string, userID string, reason string) {
+	err := repoDB.Dbmap.Exec("UPDATE public.users SET enabled = false, disabled_reason = ? WHERE id = ?", reason, userID).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
 func insertGroupUserRelation(t *testing.T, testcase string, userID string, groupID string, createAt int64) {
 	err := repoDB.Dbmap.Exec("INSERT INTO public.group_user_relations (user_id, group_id, create_at) VALUES (?, ?, ?)",
 		userID, groupID, createAt).Error
@@ -140,6 +257,22 @@ func insertGroupUserRelation(t *testing.T, testcase string, userID string, group
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func insertUserAttribute(t *testing.T, testcase string, attribute UserAttribute) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.user_attributes (user_id, key, value) VALUES (?, ?, ?)",
+		attribute.UserID, attribute.Key, attribute.Value).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func getUserAttributesCount(t *testing.T, testcase string, userID string) int {
+	var number int
+	err := repoDB.Dbmap.Table(UserAttribute{}.TableName()).Where("user_id = ?", userID).Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
+
 func getUsersCountFiltered(t *testing.T, testcase string,
 	id string, externalID string, path string, createAt int64, updateAt int64, urn string, pathPrefix string) int {
 	query := repoDB.Dbmap.Table(User{}.TableName())
@@ -176,6 +309,11 @@ func cleanUserTable(t *testing.T, testcase string) {
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func cleanUserAttributeTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&UserAttribute{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
 func cleanGroupUserRelationTable(t *testing.T, testcase string) {
 	err := repoDB.Dbmap.Delete(&GroupUserRelation{}).Error
 	assert.Nil(t, err, "Error in test case %v", testcase)
@@ -190,6 +328,21 @@ func insertGroup(t *testing.T, testcase string, group Group) {
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func insertGroupAttribute(t *testing.T, testcase string, attribute GroupAttribute) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.group_attributes (group_id, key, value) VALUES (?, ?, ?)",
+		attribute.GroupID, attribute.Key, attribute.Value).Error
+
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func getGroupAttributesCount(t *testing.T, testcase string, groupID string) int {
+	var number int
+	err := repoDB.Dbmap.Table(GroupAttribute{}.TableName()).Where("group_id = ?", groupID).Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
+
 func getGroupsCountFiltered(t *testing.T, testcase string,
 	id string, name string, path string, createAt int64, updateAt int64, urn string, org string) int {
 	query := repoDB.Dbmap.Table(Group{}.TableName())
@@ -242,6 +395,11 @@ func cleanGroupTable(t *testing.T, testcase string) {
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func cleanGroupAttributeTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&GroupAttribute{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
 func cleanGroupPolicyRelationTable(t *testing.T, testcase string) {
 	err := repoDB.Dbmap.Delete(&GroupPolicyRelation{}).Error
 	assert.Nil(t, err, "Error in test case %v", testcase)
@@ -275,8 +433,8 @@ func insertPolicy(t *testing.T, testcase string, policy Policy, statements []Sta
 }
 
 func insertStatements(t *testing.T, testcase string, statement Statement) {
-	err := repoDB.Dbmap.Exec("INSERT INTO public.statements (id, policy_id, effect, actions, resources) VALUES (?, ?, ?, ?, ?)",
-		statement.ID, statement.PolicyID, statement.Effect, statement.Actions, statement.Resources).Error
+	err := repoDB.Dbmap.Exec("INSERT INTO public.statements (id, policy_id, effect, actions, resources, conditions) VALUES (?, ?, ?, ?, ?, ?)",
+		statement.ID, statement.PolicyID, statement.Effect, statement.Actions, statement.Resources, statement.Conditions).Error
 
 	// Error handling
 	assert.Nil(t, err, "Error in test case %v", testcase)
@@ -367,8 +525,9 @@ func cleanProxyResourcesTable(t *testing.T, testcase string) {
 
 func insertProxyResource(t *testing.T, testcase string, pr ProxyResource) {
 	err := repoDB.Dbmap.Exec("INSERT INTO public.proxy_resources (id, name, org, path, host, path_resource, method, urn_resource, "+
-		"urn, action, create_at, update_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		pr.ID, pr.Name, pr.Org, pr.Path, pr.Host, pr.PathResource, pr.Method, pr.UrnResource, pr.Urn, pr.Action, pr.CreateAt, pr.UpdateAt).Error
+		"urn, action, tags, create_at, update_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pr.ID, pr.Name, pr.Org, pr.Path, pr.Host, pr.PathResource, pr.Method, pr.UrnResource, pr.Urn, pr.Action, pr.Tags,
+		pr.CreateAt, pr.UpdateAt).Error
 
 	// Error handling
 	assert.Nil(t, err, "Error in testcase %v", testcase)
@@ -417,6 +576,11 @@ func cleanOidcClientsTable(t *testing.T, testcase string) {
 	assert.Nil(t, err, "Error in test case %v", testcase)
 }
 
+func cleanOidcGroupMappingsTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&OidcGroupMapping{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
 func insertOidcProvider(t *testing.T, testcase string, oidcProvider OidcProvider, oidcClients []OidcClient) {
 	err := repoDB.Dbmap.Exec("INSERT INTO public.oidc_providers (id, name, path, create_at, update_at, urn, issuer_url) VALUES (?, ?, ?, ?, ?, ?, ?)",
 		oidcProvider.ID, oidcProvider.Name, oidcProvider.Path, oidcProvider.CreateAt, oidcProvider.UpdateAt, oidcProvider.Urn, oidcProvider.IssuerURL).Error
@@ -489,3 +653,141 @@ func getOidcClientsCountFiltered(t *testing.T, testcase string,
 
 	return number
 }
+
+// WEBHOOK
+
+func cleanWebhookTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&Webhook{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func cleanWebhookDeliveryTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&WebhookDelivery{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func insertWebhook(t *testing.T, testcase string, webhook Webhook) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.webhooks (id, name, path, urn, create_at, update_at, url, secret, events) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		webhook.ID, webhook.Name, webhook.Path, webhook.Urn, webhook.CreateAt, webhook.UpdateAt, webhook.URL, webhook.Secret, webhook.Events).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func insertWebhookDelivery(t *testing.T, testcase string, delivery WebhookDelivery) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.webhook_deliveries (id, webhook_id, event_id, event_type, attempt, status_code, error, success, create_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Attempt, delivery.StatusCode,
+		delivery.Error, delivery.Success, delivery.CreateAt).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func getWebhooksCountFiltered(t *testing.T, testcase string,
+	id string, name string, path string, urn string, url string, secret string, events string) int {
+	query := repoDB.Dbmap.Table(Webhook{}.TableName())
+	if id != "" {
+		query = query.Where("id = ?", id)
+	}
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	if path != "" {
+		query = query.Where("path = ?", path)
+	}
+	if urn != "" {
+		query = query.Where("urn = ?", urn)
+	}
+	if url != "" {
+		query = query.Where("url = ?", url)
+	}
+	if secret != "" {
+		query = query.Where("secret = ?", secret)
+	}
+	if events != "" {
+		query = query.Where("events = ?", events)
+	}
+	var number int
+	err := query.Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
+
+func getWebhookDeliveriesCountFiltered(t *testing.T, testcase string, webhookID string) int {
+	query := repoDB.Dbmap.Table(WebhookDelivery{}.TableName())
+	if webhookID != "" {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+	var number int
+	err := query.Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
+
+// API KEY
+
+func cleanApiKeyTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&ApiKey{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func insertApiKey(t *testing.T, testcase string, apiKey ApiKey) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.api_keys (id, name, user_id, secret_hash, create_at, update_at, expire_at, last_used_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		apiKey.ID, apiKey.Name, apiKey.UserID, apiKey.SecretHash, apiKey.CreateAt, apiKey.UpdateAt, apiKey.ExpireAt, apiKey.LastUsedAt).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func getApiKeysCountFiltered(t *testing.T, testcase string, id string, userID string, secretHash string, lastUsedAt int64) int {
+	query := repoDB.Dbmap.Table(ApiKey{}.TableName())
+	if id != "" {
+		query = query.Where("id = ?", id)
+	}
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if secretHash != "" {
+		query = query.Where("secret_hash = ?", secretHash)
+	}
+	if lastUsedAt != 0 {
+		query = query.Where("last_used_at = ?", lastUsedAt)
+	}
+	var number int
+	err := query.Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
+
+// CHANGE
+
+func cleanChangeTable(t *testing.T, testcase string) {
+	err := repoDB.Dbmap.Delete(&Change{}).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func insertChange(t *testing.T, testcase string, change Change) {
+	err := repoDB.Dbmap.Exec("INSERT INTO public.changes (seq, type, resource_id, related_id, urn, create_at) VALUES (?, ?, ?, ?, ?, ?)",
+		change.Seq, change.Type, change.ResourceID, change.RelatedID, change.Urn, change.CreateAt).Error
+
+	// Error handling
+	assert.Nil(t, err, "Error in test case %v", testcase)
+}
+
+func getChangesCountFiltered(t *testing.T, testcase string, changeType string, resourceID string, relatedID string, urn string) int {
+	query := repoDB.Dbmap.Table(Change{}.TableName()).Where("type = ? AND resource_id = ?", changeType, resourceID)
+	if relatedID != "" {
+		query = query.Where("related_id = ?", relatedID)
+	}
+	if urn != "" {
+		query = query.Where("urn = ?", urn)
+	}
+	var number int
+	err := query.Count(&number).Error
+	assert.Nil(t, err, "Error in test case %v", testcase)
+
+	return number
+}
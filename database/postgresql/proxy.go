@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"time"
@@ -12,6 +13,7 @@ import (
 // PROXY REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) GetProxyResourceByName(org string, name string) (*api.ProxyResource, error) {
+	defer pr.observe("GetProxyResourceByName")()
 	proxyResource := &ProxyResource{}
 	query := pr.Dbmap.Where("org like ? AND name like ?", org, name).First(proxyResource)
 
@@ -34,6 +36,7 @@ func (pr PostgresRepo) GetProxyResourceByName(org string, name string) (*api.Pro
 }
 
 func (pr PostgresRepo) GetProxyResources(filter *api.Filter) ([]api.ProxyResource, int, error) {
+	defer pr.observe("GetProxyResources")()
 	var total int
 	resources := []ProxyResource{}
 	query := pr.Dbmap
@@ -44,12 +47,11 @@ func (pr PostgresRepo) GetProxyResources(filter *api.Filter) ([]api.ProxyResourc
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ? ", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
+	query = filterByNameAndDates(query, filter, "name")
 
 	// Error handling
-	if err := query.Find(&resources).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&resources).Error; err != nil {
+	total, err := findPage(query, &resources, filter, filter.OrderBy, "id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -69,6 +71,7 @@ func (pr PostgresRepo) GetProxyResources(filter *api.Filter) ([]api.ProxyResourc
 }
 
 func (pr PostgresRepo) AddProxyResource(proxyResource api.ProxyResource) (*api.ProxyResource, error) {
+	defer pr.observe("AddProxyResource")()
 	// Create proxyResource model
 	proxyResourceDB := &ProxyResource{
 		ID:           proxyResource.ID,
@@ -80,26 +83,34 @@ func (pr PostgresRepo) AddProxyResource(proxyResource api.ProxyResource) (*api.P
 		Method:       proxyResource.Resource.Method,
 		UrnResource:  proxyResource.Resource.Urn,
 		Action:       proxyResource.Resource.Action,
+		Tags:         tagsToString(proxyResource.Resource.Tags),
 		Urn:          proxyResource.Urn,
 		CreateAt:     proxyResource.CreateAt.UnixNano(),
 		UpdateAt:     proxyResource.UpdateAt.UnixNano(),
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store proxyResource
-	err := pr.Dbmap.Create(proxyResourceDB).Error
+	err := transaction.Create(proxyResourceDB).Error
+	if err == nil {
+		err = addChange(transaction, api.EVENT_PROXY_RESOURCE_CREATED, proxyResourceDB.ID, proxyResourceDB.Urn, "")
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return dbResourceToApiResource(proxyResourceDB), nil
 }
 
 func (pr PostgresRepo) UpdateProxyResource(proxyResource api.ProxyResource) (*api.ProxyResource, error) {
+	defer pr.observe("UpdateProxyResource")()
 	proxyResourceDB := &ProxyResource{
 		ID:           proxyResource.ID,
 		Name:         proxyResource.Name,
@@ -110,37 +121,59 @@ func (pr PostgresRepo) UpdateProxyResource(proxyResource api.ProxyResource) (*ap
 		Method:       proxyResource.Resource.Method,
 		UrnResource:  proxyResource.Resource.Urn,
 		Action:       proxyResource.Resource.Action,
+		Tags:         tagsToString(proxyResource.Resource.Tags),
 		Urn:          proxyResource.Urn,
 		CreateAt:     proxyResource.CreateAt.UnixNano(),
 		UpdateAt:     proxyResource.UpdateAt.UnixNano(),
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store proxyResource
-	query := pr.Dbmap.Model(&ProxyResource{ID: proxyResource.ID}).Updates(proxyResourceDB)
+	err := transaction.Model(&ProxyResource{ID: proxyResource.ID}).Updates(proxyResourceDB).Error
+	if err == nil {
+		// Updates skips empty fields, so tags are always set to allow removing all of them
+		err = transaction.Model(&ProxyResource{ID: proxyResource.ID}).Update("tags", proxyResourceDB.Tags).Error
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_PROXY_RESOURCE_UPDATED, proxyResource.ID, proxyResource.Urn, "")
+	}
 
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return &proxyResource, nil
 }
 
 func (pr PostgresRepo) RemoveProxyResource(id string) error {
-	// Remove proxy resource
-	query := pr.Dbmap.Where("id like ?", id).Delete(&ProxyResource{})
+	defer pr.observe("RemoveProxyResource")()
+	transaction := pr.Dbmap.Begin()
+	// Retrieve urn before proxy resource is deleted
+	urn, err := getUrnByID(transaction, &ProxyResource{}, id)
+	if err == nil {
+		// Remove proxy resource
+		err = transaction.Where("id like ?", id).Delete(&ProxyResource{}).Error
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_PROXY_RESOURCE_DELETED, id, urn, "")
+	}
 
 	// Error handling
-	if err := query.Error; err != nil {
+	if err != nil {
+		transaction.Rollback()
 		return &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return nil
 }
 
@@ -159,9 +192,36 @@ func dbResourceToApiResource(pr *ProxyResource) *api.ProxyResource {
 			Method: pr.Method,
 			Urn:    pr.UrnResource,
 			Action: pr.Action,
+			Tags:   stringToTags(pr.Tags),
 		},
 		Urn:      pr.Urn,
 		CreateAt: time.Unix(0, pr.CreateAt).UTC(),
 		UpdateAt: time.Unix(0, pr.UpdateAt).UTC(),
 	}
 }
+
+// Transform proxy resource tags into a JSON string, empty if there aren't tags
+func tagsToString(tags map[string]string) string {
+	if len(tags) < 1 {
+		return ""
+	}
+	stringVal, err := json.Marshal(tags)
+	if err != nil {
+		return ""
+	}
+
+	return string(stringVal)
+}
+
+// Transform a JSON string into proxy resource tags
+func stringToTags(stringVal string) map[string]string {
+	if len(stringVal) == 0 {
+		return nil
+	}
+	tags := map[string]string{}
+	if err := json.Unmarshal([]byte(stringVal), &tags); err != nil {
+		return nil
+	}
+
+	return tags
+}
@@ -328,6 +328,46 @@ func TestPostgresRepo_AddProxyResource(t *testing.T) {
 				UpdateAt: now,
 			},
 		},
+		"OkCaseWithTags": {
+			proxyResource: &api.ProxyResource{
+				ID:   "ID",
+				Name: "name",
+				Path: "path",
+				Org:  "org",
+				Resource: api.ResourceEntity{
+					Host:   "host",
+					Path:   "/path",
+					Method: "Method",
+					Urn:    "urn2",
+					Action: "action",
+					Tags: map[string]string{
+						"env": "prod",
+					},
+				},
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			expectedResponse: &api.ProxyResource{
+				ID:   "ID",
+				Name: "name",
+				Path: "path",
+				Org:  "org",
+				Resource: api.ResourceEntity{
+					Host:   "host",
+					Path:   "/path",
+					Method: "Method",
+					Urn:    "urn2",
+					Action: "action",
+					Tags: map[string]string{
+						"env": "prod",
+					},
+				},
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
 		"ErrorCaseUserAlreadyExist": {
 			previousResource: &ProxyResource{
 				ID:           "ID",
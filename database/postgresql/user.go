@@ -6,36 +6,52 @@ import (
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database"
+	"github.com/jinzhu/gorm"
 )
 
 // USER REPOSITORY IMPLEMENTATION
 
 func (pr PostgresRepo) AddUser(user api.User) (*api.User, error) {
+	defer pr.observe("AddUser")()
 	// Create user model
 	userDB := &User{
-		ID:         user.ID,
-		ExternalID: user.ExternalID,
-		Path:       user.Path,
-		CreateAt:   user.CreateAt.UnixNano(),
-		UpdateAt:   user.UpdateAt.UnixNano(),
-		Urn:        user.Urn,
+		ID:             user.ID,
+		ExternalID:     user.ExternalID,
+		Path:           user.Path,
+		CreateAt:       user.CreateAt.UnixNano(),
+		UpdateAt:       user.UpdateAt.UnixNano(),
+		Urn:            user.Urn,
+		Enabled:        user.Enabled,
+		DisabledReason: user.DisabledReason,
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Store user
-	err := pr.Dbmap.Create(userDB).Error
+	err := transaction.Create(userDB).Error
+	if err == nil {
+		err = addUserAttributes(transaction, userDB.ID, user.Attributes)
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_USER_CREATED, userDB.ID, userDB.Urn, "")
+	}
 
 	// Error handling
 	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
-	return dbUserToAPIUser(userDB), nil
+	transaction.Commit()
+	apiUser := dbUserToAPIUser(userDB)
+	apiUser.Attributes = user.Attributes
+	return apiUser, nil
 }
 
 func (pr PostgresRepo) GetUserByExternalID(id string) (*api.User, error) {
+	defer pr.observe("GetUserByExternalID")()
 	user := &User{}
 	query := pr.Dbmap.Where("external_id like ?", id).First(user)
 
@@ -55,10 +71,11 @@ func (pr PostgresRepo) GetUserByExternalID(id string) (*api.User, error) {
 		}
 	}
 
-	return dbUserToAPIUser(user), nil
+	return pr.getUserWithAttributes(user)
 }
 
 func (pr PostgresRepo) GetUserByID(id string) (*api.User, error) {
+	defer pr.observe("GetUserByID")()
 	user := &User{}
 	query := pr.Dbmap.Where("id like ?", id).First(user)
 
@@ -78,10 +95,11 @@ func (pr PostgresRepo) GetUserByID(id string) (*api.User, error) {
 		}
 	}
 
-	return dbUserToAPIUser(user), nil
+	return pr.getUserWithAttributes(user)
 }
 
 func (pr PostgresRepo) GetUsersFiltered(filter *api.Filter) ([]api.User, int, error) {
+	defer pr.observe("GetUsersFiltered")()
 	var total int
 	users := []User{}
 	query := pr.Dbmap
@@ -89,12 +107,17 @@ func (pr PostgresRepo) GetUsersFiltered(filter *api.Filter) ([]api.User, int, er
 	if len(filter.PathPrefix) > 0 {
 		query = query.Where("path like ?", filter.PathPrefix+"%")
 	}
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
+	for key, value := range filter.Attributes {
+		query = query.Where("id IN (SELECT user_id FROM user_attributes WHERE key = ? AND value = ?)", key, value)
+	}
+	if len(filter.State) > 0 {
+		query = query.Where("enabled = ?", filter.State == api.USER_STATE_ENABLED)
 	}
+	query = filterByNameAndDates(query, filter, "external_id")
 
 	// Error handling
-	if err := query.Find(&users).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+	total, err := findPage(query, &users, filter, filter.OrderBy, "id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -113,7 +136,50 @@ func (pr PostgresRepo) GetUsersFiltered(filter *api.Filter) ([]api.User, int, er
 	return apiusers, total, nil
 }
 
+func (pr PostgresRepo) GetUsersByExternalIDs(ids []string) ([]api.User, error) {
+	defer pr.observe("GetUsersByExternalIDs")()
+	users := []User{}
+	if err := pr.Dbmap.Where("external_id IN (?)", ids).Find(&users).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform users for API
+	apiusers := make([]api.User, len(users))
+	userIDs := make([]string, len(users))
+	indexes := make(map[string]int, len(users))
+	for i, u := range users {
+		apiusers[i] = *dbUserToAPIUser(&u)
+		userIDs[i] = u.ID
+		indexes[u.ID] = i
+	}
+	if len(users) == 0 {
+		return apiusers, nil
+	}
+
+	// Retrieve attributes of all the users at once
+	attributes := []UserAttribute{}
+	if err := pr.Dbmap.Where("user_id IN (?)", userIDs).Find(&attributes).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+	for _, a := range attributes {
+		user := &apiusers[indexes[a.UserID]]
+		if user.Attributes == nil {
+			user.Attributes = map[string]string{}
+		}
+		user.Attributes[a.Key] = a.Value
+	}
+
+	return apiusers, nil
+}
+
 func (pr PostgresRepo) UpdateUser(user api.User) (*api.User, error) {
+	defer pr.observe("UpdateUser")()
 	userDB := User{
 		ID:         user.ID,
 		ExternalID: user.ExternalID,
@@ -123,22 +189,52 @@ func (pr PostgresRepo) UpdateUser(user api.User) (*api.User, error) {
 		Urn:        user.Urn,
 	}
 
+	transaction := pr.Dbmap.Begin()
 	// Update user
-	query := pr.Dbmap.Model(&User{ID: user.ID}).Updates(userDB)
+	err := transaction.Model(&User{ID: user.ID}).Updates(userDB).Error
+	if err == nil {
+		// Updates skips empty fields, so state is always set to allow disabling users
+		err = transaction.Model(&User{ID: user.ID}).Updates(map[string]interface{}{
+			"enabled":         user.Enabled,
+			"disabled_reason": user.DisabledReason,
+		}).Error
+	}
+	if err == nil {
+		err = transaction.Where("user_id like ?", user.ID).Delete(&UserAttribute{}).Error
+	}
+	if err == nil {
+		err = addUserAttributes(transaction, user.ID, user.Attributes)
+	}
+	if err == nil {
+		err = addChange(transaction, api.EVENT_USER_UPDATED, user.ID, user.Urn, "")
+	}
 
 	// Error Handling
-	if err := query.Error; err != nil {
+	if err != nil {
+		transaction.Rollback()
 		return nil, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
 		}
 	}
 
+	transaction.Commit()
 	return &user, nil
 }
 
 func (pr PostgresRepo) RemoveUser(id string) error {
+	defer pr.observe("RemoveUser")()
 	transaction := pr.Dbmap.Begin()
+	// Retrieve urn before user is deleted
+	urn, err := getUrnByID(transaction, &User{}, id)
+	if err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	// Delete user
 	transaction.Where("id like ?", id).Delete(&User{})
 
@@ -163,23 +259,49 @@ func (pr PostgresRepo) RemoveUser(id string) error {
 		}
 	}
 
+	// Delete all user attributes
+	if err := transaction.Where("user_id like ?", id).Delete(&UserAttribute{}).Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Delete all user API keys
+	transaction.Where("user_id like ?", id).Delete(&ApiKey{})
+
+	// Error handling
+	if err := transaction.Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Store change
+	if err := addChange(transaction, api.EVENT_USER_DELETED, id, urn, ""); err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
 	transaction.Commit()
 	return nil
 }
 
 func (pr PostgresRepo) GetGroupsByUserID(id string, filter *api.Filter) ([]api.UserGroupRelation, int, error) {
+	defer pr.observe("GetGroupsByUserID")()
 	var total int
 	relations := []GroupUserRelation{}
-	query := pr.Dbmap
-
-	if len(filter.OrderBy) > 0 {
-		query = query.Order(filter.OrderBy)
-	}
-
-	query.Where("user_id like ?", id).Find(&relations).Count(&total).Offset(filter.Offset).Limit(filter.Limit).Find(&relations)
+	query := pr.Dbmap.Where("user_id like ?", id)
 
 	// Error Handling
-	if err := query.Error; err != nil {
+	total, err := findPage(query, &relations, filter, filter.OrderBy, "group_id")
+	if err != nil {
 		return nil, total, &database.Error{
 			Code:    database.INTERNAL_ERROR,
 			Message: err.Error(),
@@ -211,14 +333,53 @@ func (pr PostgresRepo) GetGroupsByUserID(id string, filter *api.Filter) ([]api.U
 
 // PRIVATE HELPER METHODS
 
+// Transform a user retrieved from db into a user for API, with its attributes
+func (pr PostgresRepo) getUserWithAttributes(userdb *User) (*api.User, error) {
+	attributes := []UserAttribute{}
+	if err := pr.Dbmap.Where("user_id like ?", userdb.ID).Find(&attributes).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	user := dbUserToAPIUser(userdb)
+	if len(attributes) > 0 {
+		user.Attributes = make(map[string]string, len(attributes))
+		for _, a := range attributes {
+			user.Attributes[a.Key] = a.Value
+		}
+	}
+
+	return user, nil
+}
+
+// Store attributes of a user
+func addUserAttributes(transaction *gorm.DB, userID string, attributes map[string]string) error {
+	for key, value := range attributes {
+		attribute := &UserAttribute{
+			UserID: userID,
+			Key:    key,
+			Value:  value,
+		}
+		if err := transaction.Create(attribute).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Transform a user retrieved from db into a user for API
 func dbUserToAPIUser(userdb *User) *api.User {
 	return &api.User{
-		ID:         userdb.ID,
-		ExternalID: userdb.ExternalID,
-		Path:       userdb.Path,
-		CreateAt:   time.Unix(0, userdb.CreateAt).UTC(),
-		UpdateAt:   time.Unix(0, userdb.UpdateAt).UTC(),
-		Urn:        userdb.Urn,
+		ID:             userdb.ID,
+		ExternalID:     userdb.ExternalID,
+		Path:           userdb.Path,
+		CreateAt:       time.Unix(0, userdb.CreateAt).UTC(),
+		UpdateAt:       time.Unix(0, userdb.UpdateAt).UTC(),
+		Urn:            userdb.Urn,
+		Enabled:        userdb.Enabled,
+		DisabledReason: userdb.DisabledReason,
 	}
 }
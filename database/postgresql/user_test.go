@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -20,6 +21,34 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 		expectedResponse *api.User
 		expectedError    *database.Error
 	}{
+		"OkCaseAttributes": {
+			userToCreate: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"department": "backend",
+					"clearance":  "high",
+				},
+				Enabled: true,
+			},
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"department": "backend",
+					"clearance":  "high",
+				},
+				Enabled: true,
+			},
+		},
 		"OkCase": {
 			userToCreate: &api.User{
 				ID:         "UserID",
@@ -28,6 +57,7 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
 			},
 			expectedResponse: &api.User{
 				ID:         "UserID",
@@ -36,6 +66,7 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUserAlreadyExist": {
@@ -54,6 +85,7 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
 			},
 			expectedError: &database.Error{
 				Code:    database.INTERNAL_ERROR,
@@ -65,6 +97,7 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 	for n, test := range testcases {
 		// Clean user database
 		cleanUserTable(t, n)
+		cleanUserAttributeTable(t, n)
 
 		// Insert previous data
 		if test.previousUser != nil {
@@ -84,6 +117,8 @@ func TestPostgresRepo_AddUser(t *testing.T) {
 			userNumber := getUsersCountFiltered(t, n, test.expectedResponse.ID, test.expectedResponse.ExternalID, test.expectedResponse.Path,
 				test.expectedResponse.CreateAt.UnixNano(), test.expectedResponse.UpdateAt.UnixNano(), test.expectedResponse.Urn, "")
 			assert.Equal(t, 1, userNumber, "Error in test case %v", n)
+			attributesNumber := getUserAttributesCount(t, n, test.expectedResponse.ID)
+			assert.Equal(t, len(test.expectedResponse.Attributes), attributesNumber, "Error in test case %v", n)
 		}
 	}
 }
@@ -92,7 +127,8 @@ func TestPostgresRepo_GetUserByExternalID(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousUser *User
+		previousUser       *User
+		previousAttributes []UserAttribute
 		// Postgres Repo Args
 		externalID string
 		// Expected result
@@ -116,6 +152,37 @@ func TestPostgresRepo_GetUserByExternalID(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"OkCaseAttributes": {
+			previousUser: &User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+			},
+			previousAttributes: []UserAttribute{
+				{
+					UserID: "UserID",
+					Key:    "clearance",
+					Value:  "high",
+				},
+			},
+			externalID: "ExternalID",
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+				Enabled: true,
 			},
 		},
 		"ErrorCaseUserNotExist": {
@@ -139,10 +206,15 @@ func TestPostgresRepo_GetUserByExternalID(t *testing.T) {
 		// Clean user database
 		cleanUserTable(t, n)
 
+		cleanUserAttributeTable(t, n)
+
 		// Insert previous data
 		if test.previousUser != nil {
 			insertUser(t, n, *test.previousUser)
 		}
+		for _, attribute := range test.previousAttributes {
+			insertUserAttribute(t, n, attribute)
+		}
 		// Call to repository to get an user
 		receivedUser, err := repoDB.GetUserByExternalID(test.externalID)
 		if test.expectedError != nil {
@@ -158,6 +230,87 @@ func TestPostgresRepo_GetUserByExternalID(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_GetUsersByExternalIDs(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousUsers      []User
+		previousAttributes []UserAttribute
+		// Postgres Repo Args
+		externalIDs []string
+		// Expected result
+		expectedResponse []api.User
+	}{
+		"OkCase": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path",
+					Urn:        "urn1",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path",
+					Urn:        "urn2",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			previousAttributes: []UserAttribute{
+				{
+					UserID: "UserID1",
+					Key:    "clearance",
+					Value:  "high",
+				},
+			},
+			externalIDs: []string{"ExternalID1", "NotExist"},
+			expectedResponse: []api.User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path",
+					Urn:        "urn1",
+					CreateAt:   now,
+					UpdateAt:   now,
+					Attributes: map[string]string{
+						"clearance": "high",
+					},
+					Enabled: true,
+				},
+			},
+		},
+		"OkCaseNotFound": {
+			externalIDs:      []string{"NotExist"},
+			expectedResponse: []api.User{},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean user database
+		cleanUserTable(t, n)
+
+		cleanUserAttributeTable(t, n)
+
+		// Insert previous data
+		for _, user := range test.previousUsers {
+			insertUser(t, n, user)
+		}
+		for _, attribute := range test.previousAttributes {
+			insertUserAttribute(t, n, attribute)
+		}
+		// Call to repository to get users
+		receivedUsers, err := repoDB.GetUsersByExternalIDs(test.externalIDs)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedResponse, receivedUsers, "Error in test case %v", n)
+	}
+}
+
 func TestPostgresRepo_GetUserByID(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -186,6 +339,7 @@ func TestPostgresRepo_GetUserByID(t *testing.T) {
 				Urn:        "urn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
 			},
 		},
 		"ErrorCaseUserNotExist": {
@@ -231,7 +385,9 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousUsers []User
+		previousUsers      []User
+		previousAttributes []UserAttribute
+		disabledUsers      []string
 		// Postgres Repo Args
 		filter *api.Filter
 		// Expected result
@@ -270,6 +426,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 					Urn:        "urn2",
 					CreateAt:   now,
 					UpdateAt:   now,
+					Enabled:    true,
 				},
 				{
 					ID:         "UserID1",
@@ -278,6 +435,51 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 					Urn:        "urn1",
 					CreateAt:   now,
 					UpdateAt:   now,
+					Enabled:    true,
+				},
+			},
+		},
+		"OkCaseSearch": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "john_doe",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.Add(-time.Hour).UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "johnxdoe",
+					Path:       "Path123",
+					Urn:        "urn2",
+					CreateAt:   now.Add(-time.Hour).UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID3",
+					ExternalID: "mary_doe",
+					Path:       "Path123",
+					Urn:        "urn3",
+					CreateAt:   now.Add(-48 * time.Hour).UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			filter: &api.Filter{
+				NameContains: "_doe",
+				CreatedAfter: now.Add(-24 * time.Hour),
+				Limit:        20,
+			},
+			expectedResponse: []api.User{
+				{
+					ID:         "UserID1",
+					ExternalID: "john_doe",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.Add(-time.Hour),
+					UpdateAt:   now,
+					Enabled:    true,
 				},
 			},
 		},
@@ -313,6 +515,7 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 					Urn:        "urn1",
 					CreateAt:   now,
 					UpdateAt:   now,
+					Enabled:    true,
 				},
 			},
 		},
@@ -342,18 +545,121 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 			},
 			expectedResponse: []api.User{},
 		},
+		"OkCaseAttributes": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			previousAttributes: []UserAttribute{
+				{
+					UserID: "UserID1",
+					Key:    "clearance",
+					Value:  "high",
+				},
+				{
+					UserID: "UserID1",
+					Key:    "department",
+					Value:  "backend",
+				},
+				{
+					UserID: "UserID2",
+					Key:    "clearance",
+					Value:  "high",
+				},
+			},
+			filter: &api.Filter{
+				PathPrefix: "Path",
+				Attributes: map[string]string{
+					"clearance":  "high",
+					"department": "backend",
+				},
+				Offset: 0,
+				Limit:  20,
+			},
+			expectedResponse: []api.User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now,
+					UpdateAt:   now,
+					Enabled:    true,
+				},
+			},
+		},
+		"OkCaseDisabledState": {
+			previousUsers: []User{
+				{
+					ID:         "UserID1",
+					ExternalID: "ExternalID1",
+					Path:       "Path123",
+					Urn:        "urn1",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "UserID2",
+					ExternalID: "ExternalID2",
+					Path:       "Path456",
+					Urn:        "urn2",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
+			disabledUsers: []string{"UserID2"},
+			filter: &api.Filter{
+				PathPrefix: "Path",
+				State:      api.USER_STATE_DISABLED,
+				Offset:     0,
+				Limit:      20,
+			},
+			expectedResponse: []api.User{
+				{
+					ID:             "UserID2",
+					ExternalID:     "ExternalID2",
+					Path:           "Path456",
+					Urn:            "urn2",
+					CreateAt:       now,
+					UpdateAt:       now,
+					DisabledReason: "Offboarded",
+				},
+			},
+		},
 	}
 
 	for n, test := range testcases {
 		// Clean user database
 		cleanUserTable(t, n)
 
+		cleanUserAttributeTable(t, n)
+
 		// Insert previous data
 		if test.previousUsers != nil {
 			for _, previousUser := range test.previousUsers {
 				insertUser(t, n, previousUser)
 			}
 		}
+		for _, attribute := range test.previousAttributes {
+			insertUserAttribute(t, n, attribute)
+		}
+		for _, userID := range test.disabledUsers {
+			disableUser(t, n, userID, "Offboarded")
+		}
 		// Call to repository to get users
 		receivedUsers, total, err := repoDB.GetUsersFiltered(test.filter)
 		assert.Nil(t, err, "Error in test case %v", n)
@@ -366,6 +672,77 @@ func TestPostgresRepo_GetUsersFiltered(t *testing.T) {
 	}
 }
 
+func TestPostgresRepo_GetUsersFilteredWithCursor(t *testing.T) {
+	now := time.Now().UTC()
+	testcase := "PageThroughUsers"
+
+	// Clean user database
+	cleanUserTable(t, testcase)
+	cleanUserAttributeTable(t, testcase)
+
+	// Insert previous data
+	expectedUsers := []api.User{}
+	for i := 3; i > 0; i-- {
+		user := User{
+			ID:         fmt.Sprintf("UserID%v", i),
+			ExternalID: fmt.Sprintf("ExternalID%v", i),
+			Path:       "Path",
+			Urn:        fmt.Sprintf("urn%v", i),
+			CreateAt:   now.UnixNano(),
+			UpdateAt:   now.UnixNano(),
+		}
+		insertUser(t, testcase, user)
+		expectedUsers = append(expectedUsers, *dbUserToAPIUser(&user))
+		expectedUsers[len(expectedUsers)-1].Enabled = true
+	}
+
+	// First page
+	filter := &api.Filter{
+		Limit:   2,
+		OrderBy: "external_id desc",
+	}
+	receivedUsers, total, err := repoDB.GetUsersFiltered(filter)
+	assert.Nil(t, err, "Error in test case %v", testcase)
+	assert.Equal(t, 3, total, "Error in test case %v", testcase)
+	assert.Equal(t, expectedUsers[0:2], receivedUsers, "Error in test case %v", testcase)
+	assert.NotEmpty(t, filter.NextCursor, "Error in test case %v", testcase)
+	assert.Empty(t, filter.PrevCursor, "Error in test case %v", testcase)
+
+	// Next page, with a new user before the cursor that must be skipped
+	insertUser(t, testcase, User{
+		ID:         "UserID4",
+		ExternalID: "ExternalID4",
+		Path:       "Path",
+		Urn:        "urn4",
+		CreateAt:   now.UnixNano(),
+		UpdateAt:   now.UnixNano(),
+	})
+	filter = &api.Filter{
+		Limit:   2,
+		OrderBy: "external_id desc",
+		Cursor:  filter.NextCursor,
+	}
+	receivedUsers, total, err = repoDB.GetUsersFiltered(filter)
+	assert.Nil(t, err, "Error in test case %v", testcase)
+	assert.Equal(t, 4, total, "Error in test case %v", testcase)
+	assert.Equal(t, expectedUsers[2:], receivedUsers, "Error in test case %v", testcase)
+	assert.Empty(t, filter.NextCursor, "Error in test case %v", testcase)
+	assert.NotEmpty(t, filter.PrevCursor, "Error in test case %v", testcase)
+
+	// Previous page
+	filter = &api.Filter{
+		Limit:   2,
+		OrderBy: "external_id desc",
+		Cursor:  filter.PrevCursor,
+	}
+	receivedUsers, total, err = repoDB.GetUsersFiltered(filter)
+	assert.Nil(t, err, "Error in test case %v", testcase)
+	assert.Equal(t, 4, total, "Error in test case %v", testcase)
+	assert.Equal(t, expectedUsers[0:2], receivedUsers, "Error in test case %v", testcase)
+	assert.NotEmpty(t, filter.NextCursor, "Error in test case %v", testcase)
+	assert.NotEmpty(t, filter.PrevCursor, "Error in test case %v", testcase)
+}
+
 func TestPostgresRepo_UpdateUser(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -392,6 +769,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 				Urn:        "NewUrn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
 			},
 			expectedResponse: &api.User{
 				ID:         "UserID",
@@ -400,6 +778,35 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 				Urn:        "NewUrn",
 				CreateAt:   now,
 				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"OkCaseDisable": {
+			previousUser: &User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now.UnixNano(),
+				UpdateAt:   now.UnixNano(),
+			},
+			userToUpdate: &api.User{
+				ID:             "UserID",
+				ExternalID:     "ExternalID",
+				Path:           "Path",
+				Urn:            "urn",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: "Offboarded",
+			},
+			expectedResponse: &api.User{
+				ID:             "UserID",
+				ExternalID:     "ExternalID",
+				Path:           "Path",
+				Urn:            "urn",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: "Offboarded",
 			},
 		},
 	}
@@ -418,6 +825,10 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 
 		// Check response
 		assert.Equal(t, test.expectedResponse, updatedUser, "Error in test case %v", n)
+		// Check stored state
+		storedUser, err := repoDB.GetUserByExternalID(test.expectedResponse.ExternalID)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, storedUser, "Error in test case %v", n)
 		// Check database
 		userNumber := getUsersCountFiltered(t, n, test.expectedResponse.ID, test.expectedResponse.ExternalID, test.expectedResponse.Path,
 			test.expectedResponse.CreateAt.UnixNano(), test.expectedResponse.UpdateAt.UnixNano(), test.expectedResponse.Urn, "")
@@ -435,8 +846,9 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
 		// Previous data
-		previousUsers []User
-		relations     []relation
+		previousUsers   []User
+		relations       []relation
+		previousApiKeys []ApiKey
 		// Postgres Repo Args
 		userToDelete string
 	}{
@@ -471,6 +883,24 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 					createAt: now.UnixNano(),
 				},
 			},
+			previousApiKeys: []ApiKey{
+				{
+					ID:         "ApiKeyID",
+					Name:       "Name",
+					UserID:     "UserID",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "ApiKeyID2",
+					Name:       "Name",
+					UserID:     "UserID2",
+					SecretHash: "hash",
+					CreateAt:   now.UnixNano(),
+					UpdateAt:   now.UnixNano(),
+				},
+			},
 			userToDelete: "UserID",
 		},
 	}
@@ -479,6 +909,7 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 		// Clean user database
 		cleanUserTable(t, n)
 		cleanGroupUserRelationTable(t, n)
+		cleanApiKeyTable(t, n)
 
 		// Insert previous data
 		if test.previousUsers != nil {
@@ -491,6 +922,9 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 				insertGroupUserRelation(t, n, rel.userID, rel.groupID, rel.createAt)
 			}
 		}
+		for _, apiKey := range test.previousApiKeys {
+			insertApiKey(t, n, apiKey)
+		}
 		// Call to repository to remove user
 		err := repoDB.RemoveUser(test.userToDelete)
 		assert.Nil(t, err, "Error in test case %v", n)
@@ -507,6 +941,11 @@ func TestPostgresRepo_RemoveUser(t *testing.T) {
 		relations := getGroupUserRelations(t, n, "", test.userToDelete)
 		assert.Equal(t, 0, relations, "Error in test case %v", n)
 
+		// Check user deleted API keys
+		apiKeys := getApiKeysCountFiltered(t, n, "", test.userToDelete, "", 0)
+		assert.Equal(t, 0, apiKeys, "Error in test case %v", n)
+		assert.Equal(t, 1, getApiKeysCountFiltered(t, n, "", "", "", 0), "Error in test case %v", n)
+
 		// Check total user relations
 		totalRelations := getGroupUserRelations(t, n, "", "")
 		assert.Equal(t, 1, totalRelations, "Error in test case %v", n)
@@ -20,6 +20,7 @@ func TestGroupUser_GetUser(t *testing.T) {
 					ExternalID: "user1",
 					Path:       "Path",
 					Urn:        "urn",
+					Enabled:    true,
 				},
 			},
 			expectedResult: &api.User{
@@ -27,6 +28,7 @@ func TestGroupUser_GetUser(t *testing.T) {
 				ExternalID: "user1",
 				Path:       "Path",
 				Urn:        "urn",
+				Enabled:    true,
 			},
 		},
 	}
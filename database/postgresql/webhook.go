@@ -0,0 +1,238 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+)
+
+// WEBHOOK REPOSITORY IMPLEMENTATION
+
+func (pr PostgresRepo) AddWebhook(webhook api.Webhook) (*api.Webhook, error) {
+	defer pr.observe("AddWebhook")()
+	// Create webhook model
+	webhookDB := &Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		Path:     webhook.Path,
+		CreateAt: webhook.CreateAt.UnixNano(),
+		UpdateAt: webhook.UpdateAt.UnixNano(),
+		Urn:      webhook.Urn,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   stringArrayToString(webhook.Events),
+	}
+
+	// Store webhook
+	err := pr.Dbmap.Create(webhookDB).Error
+
+	// Error handling
+	if err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbWebhookToAPIWebhook(webhookDB), nil
+}
+
+func (pr PostgresRepo) GetWebhookByName(name string) (*api.Webhook, error) {
+	defer pr.observe("GetWebhookByName")()
+	webhook := &Webhook{}
+	query := pr.Dbmap.Where("name like ?", name).First(webhook)
+
+	// Check if webhook exists
+	if query.RecordNotFound() {
+		return nil, &database.Error{
+			Code:    database.WEBHOOK_NOT_FOUND,
+			Message: fmt.Sprintf("Webhook with name %v not found", name),
+		}
+	}
+
+	// Error Handling
+	if err := query.Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbWebhookToAPIWebhook(webhook), nil
+}
+
+func (pr PostgresRepo) GetWebhooksFiltered(filter *api.Filter) ([]api.Webhook, int, error) {
+	defer pr.observe("GetWebhooksFiltered")()
+	var total int
+	webhooks := []Webhook{}
+	query := pr.Dbmap
+
+	if len(filter.PathPrefix) > 0 {
+		query = query.Where("path like ?", filter.PathPrefix+"%")
+	}
+	query = filterByNameAndDates(query, filter, "name")
+
+	// Error handling
+	total, err := findPage(query, &webhooks, filter, filter.OrderBy, "id")
+	if err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform webhooks to API domain
+	var apiWebhooks []api.Webhook
+	if webhooks != nil {
+		apiWebhooks = make([]api.Webhook, len(webhooks), cap(webhooks))
+		for i, w := range webhooks {
+			apiWebhooks[i] = *dbWebhookToAPIWebhook(&w)
+		}
+	}
+
+	return apiWebhooks, total, nil
+}
+
+func (pr PostgresRepo) UpdateWebhook(webhook api.Webhook) (*api.Webhook, error) {
+	defer pr.observe("UpdateWebhook")()
+	webhookDB := Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		Path:     webhook.Path,
+		CreateAt: webhook.CreateAt.UTC().UnixNano(),
+		UpdateAt: webhook.UpdateAt.UTC().UnixNano(),
+		Urn:      webhook.Urn,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   stringArrayToString(webhook.Events),
+	}
+
+	// Update webhook
+	if err := pr.Dbmap.Model(&Webhook{ID: webhook.ID}).Update(webhookDB).Error; err != nil {
+		return nil, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return dbWebhookToAPIWebhook(&webhookDB), nil
+}
+
+func (pr PostgresRepo) RemoveWebhook(id string) error {
+	defer pr.observe("RemoveWebhook")()
+	transaction := pr.Dbmap.Begin()
+
+	// Delete webhook
+	transaction.Where("id like ?", id).Delete(&Webhook{})
+	if err := transaction.Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Delete all delivery attempts
+	transaction.Where("webhook_id like ?", id).Delete(&WebhookDelivery{})
+	if err := transaction.Error; err != nil {
+		transaction.Rollback()
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	transaction.Commit()
+	return nil
+}
+
+func (pr PostgresRepo) AddWebhookDelivery(delivery api.WebhookDelivery) error {
+	defer pr.observe("AddWebhookDelivery")()
+	// Create delivery model
+	deliveryDB := &WebhookDelivery{
+		ID:         delivery.ID,
+		WebhookID:  delivery.WebhookID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Error:      delivery.Error,
+		Success:    delivery.Success,
+		CreateAt:   delivery.CreateAt.UnixNano(),
+	}
+
+	// Store delivery
+	err := pr.Dbmap.Create(deliveryDB).Error
+
+	// Error handling
+	if err != nil {
+		return &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (pr PostgresRepo) GetWebhookDeliveries(webhookID string, filter *api.Filter) ([]api.WebhookDelivery, int, error) {
+	defer pr.observe("GetWebhookDeliveries")()
+	var total int
+	deliveries := []WebhookDelivery{}
+	query := pr.Dbmap.Where("webhook_id like ?", webhookID)
+
+	orderBy := filter.OrderBy
+	if len(orderBy) == 0 {
+		orderBy = "create_at desc"
+	}
+
+	// Error handling
+	total, err := findPage(query, &deliveries, filter, orderBy, "id")
+	if err != nil {
+		return nil, total, &database.Error{
+			Code:    database.INTERNAL_ERROR,
+			Message: err.Error(),
+		}
+	}
+
+	// Transform deliveries to API domain
+	var apiDeliveries []api.WebhookDelivery
+	if deliveries != nil {
+		apiDeliveries = make([]api.WebhookDelivery, len(deliveries), cap(deliveries))
+		for i, d := range deliveries {
+			apiDeliveries[i] = api.WebhookDelivery{
+				ID:         d.ID,
+				WebhookID:  d.WebhookID,
+				EventID:    d.EventID,
+				EventType:  d.EventType,
+				Attempt:    d.Attempt,
+				StatusCode: d.StatusCode,
+				Error:      d.Error,
+				Success:    d.Success,
+				CreateAt:   time.Unix(0, d.CreateAt).UTC(),
+			}
+		}
+	}
+
+	return apiDeliveries, total, nil
+}
+
+// PRIVATE HELPER METHODS
+
+// Transform a webhook retrieved from db into a webhook for API
+func dbWebhookToAPIWebhook(webhook *Webhook) *api.Webhook {
+	return &api.Webhook{
+		ID:       webhook.ID,
+		Name:     webhook.Name,
+		Path:     webhook.Path,
+		CreateAt: time.Unix(0, webhook.CreateAt).UTC(),
+		UpdateAt: time.Unix(0, webhook.UpdateAt).UTC(),
+		Urn:      webhook.Urn,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+		Events:   strings.Split(webhook.Events, ";"),
+	}
+}
@@ -0,0 +1,564 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepo_AddWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhook *Webhook
+		// Postgres Repo Args
+		webhookToCreate *api.Webhook
+		// Expected result
+		expectedResponse *api.Webhook
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			webhookToCreate: &api.Webhook{
+				ID:       "WebhookID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_USER_CREATED, api.EVENT_USER_DELETED},
+			},
+			expectedResponse: &api.Webhook{
+				ID:       "WebhookID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_USER_CREATED, api.EVENT_USER_DELETED},
+			},
+		},
+		"ErrorCaseAlreadyExists": {
+			previousWebhook: &Webhook{
+				ID:       "WebhookID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   api.EVENT_ALL,
+			},
+			webhookToCreate: &api.Webhook{
+				ID:       "WebhookID",
+				Name:     "Name",
+				Path:     "Path",
+				Urn:      "urn",
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_ALL},
+			},
+			expectedError: &database.Error{
+				Code:    database.INTERNAL_ERROR,
+				Message: "pq: duplicate key value violates unique constraint \"webhooks_pkey\"",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhookTable(t, n)
+
+		// Insert previous data
+		if test.previousWebhook != nil {
+			insertWebhook(t, n, *test.previousWebhook)
+		}
+		// Call to repository to store the webhook
+		storedWebhook, err := repoDB.AddWebhook(*test.webhookToCreate)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check response
+			assert.Equal(t, test.expectedResponse, storedWebhook, "Error in test case %v", n)
+			// Check database
+			webhookNumber := getWebhooksCountFiltered(t, n, test.webhookToCreate.ID, test.webhookToCreate.Name,
+				test.webhookToCreate.Path, test.webhookToCreate.Urn, test.webhookToCreate.URL, test.webhookToCreate.Secret,
+				stringArrayToString(test.webhookToCreate.Events))
+			assert.Equal(t, 1, webhookNumber, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetWebhookByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhook *Webhook
+		// Postgres Repo Args
+		name string
+		// Expected result
+		expectedResponse *api.Webhook
+		expectedError    *database.Error
+	}{
+		"OkCase": {
+			name: "test",
+			previousWebhook: &Webhook{
+				ID:       "1234",
+				Name:     "test",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   api.EVENT_ALL,
+			},
+			expectedResponse: &api.Webhook{
+				ID:       "1234",
+				Name:     "test",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_ALL},
+			},
+		},
+		"ErrorCaseNotFound": {
+			name: "test",
+			expectedError: &database.Error{
+				Code:    database.WEBHOOK_NOT_FOUND,
+				Message: "Webhook with name test not found",
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhookTable(t, n)
+
+		// Insert previous data
+		if test.previousWebhook != nil {
+			insertWebhook(t, n, *test.previousWebhook)
+		}
+		// Call to repository to get the webhook
+		receivedWebhook, err := repoDB.GetWebhookByName(test.name)
+		if test.expectedError != nil {
+			dbError, _ := err.(*database.Error)
+			assert.Equal(t, test.expectedError, dbError, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check response
+			assert.Equal(t, test.expectedResponse, receivedWebhook, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestPostgresRepo_GetWebhooksFiltered(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhooks []Webhook
+		// Postgres Repo Args
+		filter *api.Filter
+		// Expected result
+		expectedResponse []api.Webhook
+		expectedTotal    int
+	}{
+		"OkCaseFilterByPath": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path1/", "test1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test1.com/hook",
+					Secret:   "secret1",
+					Events:   api.EVENT_ALL,
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path2/", "test2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test2.com/hook",
+					Secret:   "secret2",
+					Events:   api.EVENT_USER_CREATED,
+				},
+			},
+			filter: &api.Filter{
+				PathPrefix: "/path1/",
+			},
+			expectedResponse: []api.Webhook{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path1/", "test1"),
+					CreateAt: now,
+					UpdateAt: now,
+					URL:      "https://test1.com/hook",
+					Secret:   "secret1",
+					Events:   []string{api.EVENT_ALL},
+				},
+			},
+			expectedTotal: 1,
+		},
+		"OkCaseOrderAndLimit": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test1.com/hook",
+					Secret:   "secret1",
+					Events:   api.EVENT_ALL,
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test2.com/hook",
+					Secret:   "secret2",
+					Events:   api.EVENT_USER_CREATED,
+				},
+			},
+			filter: &api.Filter{
+				OrderBy: "name desc",
+				Limit:   1,
+			},
+			expectedResponse: []api.Webhook{
+				{
+					ID:       "222",
+					Name:     "test2",
+					Path:     "/path/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test2"),
+					CreateAt: now,
+					UpdateAt: now,
+					URL:      "https://test2.com/hook",
+					Secret:   "secret2",
+					Events:   []string{api.EVENT_USER_CREATED},
+				},
+			},
+			expectedTotal: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhookTable(t, n)
+
+		// Insert previous data
+		for _, w := range test.previousWebhooks {
+			insertWebhook(t, n, w)
+		}
+		// Call to repository to get webhooks
+		webhooks, total, err := repoDB.GetWebhooksFiltered(test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check response
+		assert.Equal(t, test.expectedResponse, webhooks, "Error in test case %v", n)
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_UpdateWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhook *Webhook
+		// Postgres Repo Args
+		webhookToUpdate *api.Webhook
+		// Expected result
+		expectedResponse *api.Webhook
+	}{
+		"OkCase": {
+			previousWebhook: &Webhook{
+				ID:       "1234",
+				Name:     "test",
+				Path:     "/path/",
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				CreateAt: now.UnixNano(),
+				UpdateAt: now.UnixNano(),
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   api.EVENT_ALL,
+			},
+			webhookToUpdate: &api.Webhook{
+				ID:       "1234",
+				Name:     "newName",
+				Path:     "/newPath/",
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/newPath/", "newName"),
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://new.com/hook",
+				Secret:   "newSecret",
+				Events:   []string{api.EVENT_GROUP_MEMBER_ADDED, api.EVENT_GROUP_MEMBER_REMOVED},
+			},
+			expectedResponse: &api.Webhook{
+				ID:       "1234",
+				Name:     "newName",
+				Path:     "/newPath/",
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/newPath/", "newName"),
+				CreateAt: now,
+				UpdateAt: now,
+				URL:      "https://new.com/hook",
+				Secret:   "newSecret",
+				Events:   []string{api.EVENT_GROUP_MEMBER_ADDED, api.EVENT_GROUP_MEMBER_REMOVED},
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhookTable(t, n)
+
+		// Insert previous data
+		if test.previousWebhook != nil {
+			insertWebhook(t, n, *test.previousWebhook)
+		}
+		// Call to repository to update the webhook
+		updatedWebhook, err := repoDB.UpdateWebhook(*test.webhookToUpdate)
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check response
+		assert.Equal(t, test.expectedResponse, updatedWebhook, "Error in test case %v", n)
+		// Check database
+		webhookNumber := getWebhooksCountFiltered(t, n, test.webhookToUpdate.ID, test.webhookToUpdate.Name,
+			test.webhookToUpdate.Path, test.webhookToUpdate.Urn, test.webhookToUpdate.URL, test.webhookToUpdate.Secret,
+			stringArrayToString(test.webhookToUpdate.Events))
+		assert.Equal(t, 1, webhookNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_RemoveWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousWebhooks   []Webhook
+		previousDeliveries []WebhookDelivery
+		webhookToDelete    string
+	}{
+		"OkCase": {
+			previousWebhooks: []Webhook{
+				{
+					ID:       "111",
+					Name:     "test1",
+					Path:     "/path1/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path1/", "test1"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test1.com/hook",
+					Secret:   "secret1",
+					Events:   api.EVENT_ALL,
+				},
+				{
+					ID:       "222",
+					Name:     "test2",
+					Path:     "/path2/",
+					Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path2/", "test2"),
+					CreateAt: now.UnixNano(),
+					UpdateAt: now.UnixNano(),
+					URL:      "https://test2.com/hook",
+					Secret:   "secret2",
+					Events:   api.EVENT_ALL,
+				},
+			},
+			previousDeliveries: []WebhookDelivery{
+				{
+					ID:        "1",
+					WebhookID: "111",
+					EventID:   "event1",
+					EventType: api.EVENT_USER_CREATED,
+					Attempt:   1,
+					CreateAt:  now.UnixNano(),
+				},
+				{
+					ID:        "2",
+					WebhookID: "222",
+					EventID:   "event1",
+					EventType: api.EVENT_USER_CREATED,
+					Attempt:   1,
+					CreateAt:  now.UnixNano(),
+				},
+			},
+			webhookToDelete: "111",
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean webhook database
+		cleanWebhookTable(t, n)
+		cleanWebhookDeliveryTable(t, n)
+
+		// Insert previous data
+		for _, w := range test.previousWebhooks {
+			insertWebhook(t, n, w)
+		}
+		for _, d := range test.previousDeliveries {
+			insertWebhookDelivery(t, n, d)
+		}
+
+		// Call to repository to remove webhook
+		err := repoDB.RemoveWebhook(test.webhookToDelete)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		webhookNumber := getWebhooksCountFiltered(t, n, test.webhookToDelete, "", "", "", "", "", "")
+		assert.Equal(t, 0, webhookNumber, "Error in test case %v", n)
+
+		// Check total webhooks
+		totalWebhookNumber := getWebhooksCountFiltered(t, n, "", "", "", "", "", "", "")
+		assert.Equal(t, 1, totalWebhookNumber, "Error in test case %v", n)
+
+		// Check deliveries
+		deliveryNumber := getWebhookDeliveriesCountFiltered(t, n, test.webhookToDelete)
+		assert.Equal(t, 0, deliveryNumber, "Error in test case %v", n)
+		totalDeliveryNumber := getWebhookDeliveriesCountFiltered(t, n, "")
+		assert.Equal(t, 1, totalDeliveryNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_AddWebhookDelivery(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Postgres Repo Args
+		deliveryToCreate api.WebhookDelivery
+	}{
+		"OkCase": {
+			deliveryToCreate: api.WebhookDelivery{
+				ID:         "1",
+				WebhookID:  "111",
+				EventID:    "event1",
+				EventType:  api.EVENT_USER_CREATED,
+				Attempt:    1,
+				StatusCode: 500,
+				Error:      "Unexpected status code 500",
+				CreateAt:   now,
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean delivery database
+		cleanWebhookDeliveryTable(t, n)
+
+		// Call to repository to store the delivery
+		err := repoDB.AddWebhookDelivery(test.deliveryToCreate)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check database
+		deliveryNumber := getWebhookDeliveriesCountFiltered(t, n, test.deliveryToCreate.WebhookID)
+		assert.Equal(t, 1, deliveryNumber, "Error in test case %v", n)
+	}
+}
+
+func TestPostgresRepo_GetWebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// Previous data
+		previousDeliveries []WebhookDelivery
+		// Postgres Repo Args
+		webhookID string
+		filter    *api.Filter
+		// Expected result
+		expectedResponse []api.WebhookDelivery
+		expectedTotal    int
+	}{
+		"OkCaseDefaultOrder": {
+			previousDeliveries: []WebhookDelivery{
+				{
+					ID:         "1",
+					WebhookID:  "111",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    1,
+					StatusCode: 500,
+					Error:      "Unexpected status code 500",
+					CreateAt:   now.UnixNano(),
+				},
+				{
+					ID:         "2",
+					WebhookID:  "111",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    2,
+					StatusCode: 200,
+					Success:    true,
+					CreateAt:   now.Add(time.Second).UnixNano(),
+				},
+				{
+					ID:         "3",
+					WebhookID:  "222",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    1,
+					StatusCode: 200,
+					Success:    true,
+					CreateAt:   now.UnixNano(),
+				},
+			},
+			webhookID: "111",
+			filter:    testFilter,
+			expectedResponse: []api.WebhookDelivery{
+				{
+					ID:         "2",
+					WebhookID:  "111",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    2,
+					StatusCode: 200,
+					Success:    true,
+					CreateAt:   now.Add(time.Second),
+				},
+				{
+					ID:         "1",
+					WebhookID:  "111",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    1,
+					StatusCode: 500,
+					Error:      "Unexpected status code 500",
+					CreateAt:   now,
+				},
+			},
+			expectedTotal: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		// Clean delivery database
+		cleanWebhookDeliveryTable(t, n)
+
+		// Insert previous data
+		for _, d := range test.previousDeliveries {
+			insertWebhookDelivery(t, n, d)
+		}
+		// Call to repository to get deliveries
+		deliveries, total, err := repoDB.GetWebhookDeliveries(test.webhookID, test.filter)
+		assert.Nil(t, err, "Error in test case %v", n)
+		// Check response
+		assert.Equal(t, test.expectedResponse, deliveries, "Error in test case %v", n)
+		assert.Equal(t, test.expectedTotal, total, "Error in test case %v", n)
+	}
+}
@@ -0,0 +1,158 @@
+package foulkon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/pelletier/go-toml"
+)
+
+var decisionLogfile *rotatingFile
+
+// initDecisionLogger configures the authorization decision logger using configuration values
+func initDecisionLogger(config *toml.TomlTree) error {
+	var out io.Writer
+	loggerType := getDefaultValue(config, "logger.decision.type", "none")
+	switch loggerType {
+	case "none":
+		api.DecisionLog = nil
+		return nil
+	case "default":
+		out = os.Stdout
+	case "file":
+		fileDir, err := getMandatoryValue(config, "logger.decision.file.dir")
+		if err != nil {
+			return err
+		}
+		maxSize, err := strconv.ParseInt(getDefaultValue(config, "logger.decision.file.maxsize", "100"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid decision logger maxsize: %v", err)
+		}
+		maxBackups, err := strconv.Atoi(getDefaultValue(config, "logger.decision.file.maxbackups", "5"))
+		if err != nil {
+			return fmt.Errorf("Invalid decision logger maxbackups: %v", err)
+		}
+		decisionLogfile, err = newRotatingFile(fileDir, maxSize*1024*1024, maxBackups)
+		if err != nil {
+			return err
+		}
+		out = decisionLogfile
+	default:
+		return fmt.Errorf("Unexpected decision logger type value in configuration file: '%s'", loggerType)
+	}
+
+	sampleRateValue := getDefaultValue(config, "logger.decision.samplerate", "1.0")
+	sampleRate, err := strconv.ParseFloat(sampleRateValue, 64)
+	if err != nil || sampleRate < 0 || sampleRate > 1 {
+		return fmt.Errorf("Invalid decision logger samplerate %v, it must be a number between 0 and 1", sampleRateValue)
+	}
+	api.DecisionLogSampleRate = sampleRate
+
+	api.DecisionLog = &logrus.Logger{
+		Out:       out,
+		Formatter: &logrus.JSONFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+	}
+	api.Log.Infof("Decision logger type: %v, sample rate: %v", loggerType, sampleRate)
+
+	return nil
+}
+
+// closeDecisionLogger closes decision log file if exists
+func closeDecisionLogger() int {
+	if decisionLogfile != nil {
+		if err := decisionLogfile.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't close decision logfile: %v", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// rotatingFile is a file writer that rotates the file when it reaches its max size,
+// keeping a number of old files renamed with a numeric suffix
+type rotatingFile struct {
+	sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.Lock()
+	defer rf.Unlock()
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.Lock()
+	defer rf.Unlock()
+	return rf.file.Close()
+}
+
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate renames current file to path.1, shifting older ones, and opens a new file
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	if rf.maxBackups < 1 {
+		if err := os.Remove(rf.path); err != nil {
+			return err
+		}
+		return rf.open()
+	}
+	for i := rf.maxBackups - 1; i > 0; i-- {
+		oldPath := fmt.Sprintf("%v.%v", rf.path, i)
+		if _, err := os.Stat(oldPath); err == nil {
+			if err := os.Rename(oldPath, fmt.Sprintf("%v.%v", rf.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+		return err
+	}
+	return rf.open()
+}
@@ -0,0 +1,141 @@
+package foulkon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/pelletier/go-toml"
+)
+
+// loggerOutput formats and writes entries of api.Log. Worker and proxy run in their own process,
+// so only one of them uses it.
+var loggerOutput *logOutput
+
+// initLogger creates api.Log using configuration values. If it was already created, its output
+// is updated in place instead, because api.Log is used by requests being served at the same
+// time. It returns logger type, level and log file name, if any.
+func initLogger(config *toml.TomlTree) (string, logrus.Level, string, error) {
+	output, loggerType, err := newLogOutput(config)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	if loggerOutput == nil {
+		loggerOutput = output
+		// Level is checked by output, so it can change while logging
+		api.Log = &logrus.Logger{
+			Out:       output,
+			Formatter: output,
+			Hooks:     make(logrus.LevelHooks),
+			Level:     logrus.DebugLevel,
+		}
+	} else if err := loggerOutput.update(output); err != nil {
+		api.Log.Errorf("Couldn't close previous logfile: %v", err)
+	}
+
+	var logfileName string
+	if output.logfile != nil {
+		logfileName = output.logfile.Name()
+	}
+	api.Log.Infof("Logger type: %v, LogLevel: %v", loggerType, output.level.String())
+	return loggerType, output.level, logfileName, nil
+}
+
+// closeLogger closes log file if exists
+func closeLogger() int {
+	if loggerOutput != nil {
+		if err := loggerOutput.close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't close logfile: %v", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// newLogOutput creates the output of logger using configuration values. If logger type is file,
+// log file is opened too.
+func newLogOutput(config *toml.TomlTree) (*logOutput, string, error) {
+	var out io.Writer
+	var logfile *os.File
+	var err error
+	out = os.Stdout
+	loggerType := getDefaultValue(config, "logger.type", "Stdout")
+	if loggerType == "file" {
+		logFileDir := getDefaultValue(config, "logger.file.dir", "/tmp/foulkon.log")
+		logfile, err = os.OpenFile(logFileDir, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
+		if err != nil {
+			return nil, "", err
+		}
+		out = logfile
+	}
+
+	// Logger level. Defaults to INFO
+	loglevel, err := logrus.ParseLevel(getDefaultValue(config, "logger.level", "info"))
+	if err != nil {
+		loglevel = logrus.InfoLevel
+	}
+
+	return &logOutput{
+		out:       out,
+		logfile:   logfile,
+		level:     loglevel,
+		formatter: &logrus.JSONFormatter{},
+	}, loggerType, nil
+}
+
+// logOutput is the formatter and writer of a logger, whose level and writer can be replaced
+// while logging
+type logOutput struct {
+	sync.RWMutex
+	out       io.Writer
+	logfile   *os.File
+	level     logrus.Level
+	formatter logrus.Formatter
+}
+
+// Format formats entries up to output level. Entries above it are discarded.
+func (lo *logOutput) Format(entry *logrus.Entry) ([]byte, error) {
+	lo.RLock()
+	defer lo.RUnlock()
+	if entry.Level > lo.level {
+		return nil, nil
+	}
+	return lo.formatter.Format(entry)
+}
+
+func (lo *logOutput) Write(p []byte) (int, error) {
+	// Discarded entries
+	if len(p) == 0 {
+		return 0, nil
+	}
+	lo.RLock()
+	defer lo.RUnlock()
+	return lo.out.Write(p)
+}
+
+// update replaces writer and level with the ones of output, and closes previous log file once
+// no entry is being written to it
+func (lo *logOutput) update(output *logOutput) error {
+	lo.Lock()
+	oldLogfile := lo.logfile
+	lo.out, lo.logfile, lo.level = output.out, output.logfile, output.level
+	lo.Unlock()
+
+	if oldLogfile != nil {
+		return oldLogfile.Close()
+	}
+	return nil
+}
+
+func (lo *logOutput) close() error {
+	lo.Lock()
+	defer lo.Unlock()
+	if lo.logfile != nil {
+		return lo.logfile.Close()
+	}
+	return nil
+}
@@ -1,8 +1,7 @@
 package foulkon
 
 import (
-	"io"
-	"os"
+	"database/sql"
 
 	"errors"
 
@@ -10,15 +9,13 @@ import (
 
 	"time"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/pelletier/go-toml"
 
 	"github.com/Tecsisa/foulkon/database/postgresql"
+	"github.com/Tecsisa/foulkon/tracing"
 )
 
-var proxyLogfile *os.File
-
 // Proxy - Authorize resources using definitions in proxy config file
 type Proxy struct {
 	// Server config
@@ -28,44 +25,49 @@ type Proxy struct {
 	// Worker location
 	WorkerHost string
 
+	// Client certificate presented to worker, and CA bundle that verifies worker certificate
+	WorkerCertFile string
+	WorkerKeyFile  string
+	WorkerCAFile   string
+
 	// TLS configuration
 	CertFile string
 	KeyFile  string
 
+	// Max time to wait for in-flight requests when shutting down
+	ShutdownTimeout time.Duration
+
 	// API
 	ProxyApi api.InternalProxyAPI
 
+	// Database connection, used to check readiness
+	DB *sql.DB
+
 	// Refresh time
 	RefreshTime time.Duration
+
+	// Loaded configuration file, compared on reload
+	config *toml.TomlTree
 }
 
 func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 	// Create logger
-	var logOut io.Writer
 	var err error
-	logOut = os.Stdout
-	loggerType := getDefaultValue(config, "logger.type", "Stdout")
-	if loggerType == "file" {
-		logFileDir := getDefaultValue(config, "logger.file.dir", "/tmp/foulkon.log")
-		proxyLogfile, err = os.OpenFile(logFileDir, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
-		if err != nil {
-			return nil, err
-		}
-		logOut = proxyLogfile
+	if _, _, _, err = initLogger(config); err != nil {
+		return nil, err
 	}
-	// Loglevel. defaults to INFO
-	loglevel, err := logrus.ParseLevel(getDefaultValue(config, "logger.level", "info"))
-	if err != nil {
-		loglevel = logrus.InfoLevel
+
+	// Authorization decision logger
+	if err := initDecisionLogger(config); err != nil {
+		api.Log.Error(err)
+		return nil, err
 	}
 
-	api.Log = &logrus.Logger{
-		Out:       logOut,
-		Formatter: &logrus.JSONFormatter{},
-		Hooks:     make(logrus.LevelHooks),
-		Level:     loglevel,
+	// Tracing
+	if err := initTracer(config, tracing.SERVICE_PROXY); err != nil {
+		api.Log.Error(err)
+		return nil, err
 	}
-	api.Log.Infof("Logger type: %v, LogLevel: %v", loggerType, api.Log.Level.String())
 
 	// Start DB with API
 	var prApi api.ProxyAPI
@@ -100,7 +102,8 @@ func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 			Dbmap: gormDB,
 		}
 		prApi = api.ProxyAPI{
-			ProxyRepo: repoDB,
+			ProxyRepo:  repoDB,
+			ChangeRepo: repoDB,
 		}
 
 	default:
@@ -125,34 +128,120 @@ func NewProxy(config *toml.TomlTree) (*Proxy, error) {
 		return nil, err
 	}
 
-	refresh, err := time.ParseDuration(getDefaultValue(config, "resources.refresh", "10s"))
+	workerCertFile, workerKeyFile, err := getWorkerClientCertificate(config)
+	if err != nil {
+		api.Log.Error(err)
+		return nil, err
+	}
+
+	refresh, err := getRefreshTime(config)
+	if err != nil {
+		api.Log.Error(err)
+		return nil, err
+	}
+
+	shutdownTimeout, err := getShutdownTimeout(config)
 	if err != nil {
 		api.Log.Error(err)
 		return nil, err
 	}
 
 	return &Proxy{
-		Host:        host,
-		Port:        port,
-		WorkerHost:  workerHost,
-		CertFile:    getDefaultValue(config, "server.certfile", ""),
-		KeyFile:     getDefaultValue(config, "server.keyfile", ""),
-		ProxyApi:    prApi,
-		RefreshTime: refresh,
+		Host:            host,
+		Port:            port,
+		WorkerHost:      workerHost,
+		WorkerCertFile:  workerCertFile,
+		WorkerKeyFile:   workerKeyFile,
+		WorkerCAFile:    getDefaultValue(config, "server.workercafile", ""),
+		CertFile:        getDefaultValue(config, "server.certfile", ""),
+		KeyFile:         getDefaultValue(config, "server.keyfile", ""),
+		ShutdownTimeout: shutdownTimeout,
+		ProxyApi:        prApi,
+		DB:              db,
+		RefreshTime:     refresh,
+		config:          config,
 	}, nil
 }
 
+// Reload applies settings of config that can change while running: logger, refresh time, TLS
+// certificate files, worker client certificate files and shutdown timeout. Settings that need a
+// restart are logged. Nothing is applied if any value is invalid or any certificate can't be loaded.
+func (p *Proxy) Reload(config *toml.TomlTree) error {
+	refresh, err := getRefreshTime(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	shutdownTimeout, err := getShutdownTimeout(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	workerCertFile, workerKeyFile, err := getWorkerClientCertificate(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	if err := checkKeyPair(workerCertFile, workerKeyFile); err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	certFile := getDefaultValue(config, "server.certfile", "")
+	keyFile := getDefaultValue(config, "server.keyfile", "")
+	if err := checkKeyPair(certFile, keyFile); err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	// Logger is updated in place, closing previous log file
+	if _, _, _, err := initLogger(config); err != nil {
+		api.Log.Error(err)
+		return err
+	}
+
+	p.RefreshTime = refresh
+	p.CertFile, p.KeyFile = certFile, keyFile
+	p.WorkerCertFile, p.WorkerKeyFile = workerCertFile, workerKeyFile
+	p.ShutdownTimeout = shutdownTimeout
+	api.Log.Infof("Reloaded proxy with resources refresh time %v", refresh)
+
+	logRestartRequired(p.config, config)
+	p.config = config
+	return nil
+}
+
+// getRefreshTime returns time between proxy resources refreshes of configuration
+func getRefreshTime(config *toml.TomlTree) (time.Duration, error) {
+	refresh, err := time.ParseDuration(getDefaultValue(config, "resources.refresh", "10s"))
+	if err != nil || refresh <= 0 {
+		return 0, fmt.Errorf("Invalid resources refresh value, it must be a positive duration (e.g. 10s)")
+	}
+	return refresh, nil
+}
+
+// getWorkerClientCertificate returns certificate and key files that proxy presents to worker, if any
+func getWorkerClientCertificate(config *toml.TomlTree) (string, string, error) {
+	certFile := getDefaultValue(config, "server.workercertfile", "")
+	keyFile := getDefaultValue(config, "server.workerkeyfile", "")
+	if (certFile == "") != (keyFile == "") {
+		return "", "", errors.New("Invalid server workercertfile and workerkeyfile values, both or none must be set")
+	}
+	return certFile, keyFile, nil
+}
+
 func CloseProxy() int {
 	status := 0
 	if err := db.Close(); err != nil {
 		api.Log.Errorf("Couldn't close DB connection: %v", err)
 		status = 1
 	}
-	if proxyLogfile != nil {
-		if err := proxyLogfile.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Couldn't close logfile: %v", err)
-			status = 1
-		}
+	if closeLogger() != 0 {
+		status = 1
+	}
+	if closeDecisionLogger() != 0 {
+		status = 1
+	}
+	if closeTracer() != 0 {
+		status = 1
 	}
 	return status
 }
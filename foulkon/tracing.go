@@ -0,0 +1,47 @@
+package foulkon
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/tracing"
+	"github.com/pelletier/go-toml"
+)
+
+// initTracer configures the exporter of request spans of service using configuration values
+func initTracer(config *toml.TomlTree, service string) error {
+	exporterType := getDefaultValue(config, "tracing.type", tracing.EXPORTER_NONE)
+	sampleRateValue := getDefaultValue(config, "tracing.samplerate", "1.0")
+	sampleRate, err := strconv.ParseFloat(sampleRateValue, 64)
+	if err != nil || sampleRate < 0 || sampleRate > 1 {
+		return fmt.Errorf("Invalid tracing samplerate %v, it must be a number between 0 and 1", sampleRateValue)
+	}
+
+	var endpoint string
+	var insecure bool
+	if exporterType == tracing.EXPORTER_OTLP {
+		endpoint = getDefaultValue(config, "tracing.otlp.endpoint", "localhost:4318")
+		insecure, err = strconv.ParseBool(getDefaultValue(config, "tracing.otlp.insecure", "false"))
+		if err != nil {
+			return fmt.Errorf("Invalid tracing otlp insecure value, it must be true or false")
+		}
+	}
+
+	if err := tracing.Init(service, FOULKON_VERSION, exporterType, endpoint, insecure, sampleRate); err != nil {
+		return err
+	}
+	api.Log.Infof("Tracing exporter type: %v, sample rate: %v", exporterType, sampleRate)
+
+	return nil
+}
+
+// closeTracer exports pending spans
+func closeTracer() int {
+	if err := tracing.Shutdown(); err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't export pending spans: %v", err)
+		return 1
+	}
+	return 0
+}
@@ -1,28 +1,35 @@
 package foulkon
 
 import (
-	"io"
+	"crypto/tls"
 	"regexp"
 
 	"errors"
 	"os"
-	"strings"
 
 	"fmt"
 
 	"database/sql"
 
 	"strconv"
+	"strings"
+	"sync"
+
+	"time"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/database/postgresql"
 	"github.com/Tecsisa/foulkon/middleware"
 	"github.com/Tecsisa/foulkon/middleware/auth"
+	"github.com/Tecsisa/foulkon/middleware/auth/apikey"
 	"github.com/Tecsisa/foulkon/middleware/auth/header"
+	"github.com/Tecsisa/foulkon/middleware/auth/jwt"
+	"github.com/Tecsisa/foulkon/middleware/auth/mtls"
 	"github.com/Tecsisa/foulkon/middleware/auth/oidc"
 	"github.com/Tecsisa/foulkon/middleware/logger"
 	"github.com/Tecsisa/foulkon/middleware/xrequestid"
+	"github.com/Tecsisa/foulkon/notify"
+	"github.com/Tecsisa/foulkon/tracing"
 	"github.com/pelletier/go-toml"
 )
 
@@ -33,7 +40,7 @@ const (
 // aux var for ${OS_ENV_VAR} regex
 var rEnvVar, _ = regexp.Compile(`^\$\{(\w+)\}$`)
 var db *sql.DB
-var workerLogfile *os.File
+var webhookDispatcher *notify.WebhookDispatcher
 
 // Worker is the Authorization server.
 type Worker struct {
@@ -41,10 +48,21 @@ type Worker struct {
 	Host string
 	Port string
 
-	// TLS configuration
+	// TLS configuration, read with GetCertFiles since it changes on reload
 	CertFile string
 	KeyFile  string
 
+	// CA bundle that verifies client certificates, and if they are required
+	ClientCAFile      string
+	RequireClientCert bool
+
+	// Max time to wait for in-flight requests when shutting down, read with GetShutdownTimeout
+	// since it changes on reload
+	ShutdownTimeout time.Duration
+
+	// Organization of groups managed with SCIM, SCIM groups are disabled if empty
+	ScimOrg string
+
 	// APIs
 	UserApi     api.UserAPI
 	GroupApi    api.GroupAPI
@@ -52,12 +70,28 @@ type Worker struct {
 	AuthzApi    api.AuthzAPI
 	ProxyApi    api.ProxyResourcesAPI
 	AuthOidcAPI api.AuthOidcAPI
+	WebhookApi  api.WebhookAPI
+	ApiKeyApi   api.ApiKeyAPI
+	ChangeApi   api.ChangeAPI
 
 	//  Middleware handler
 	MiddlewareHandler *middleware.MiddlewareHandler
 
-	// Current Foulkon configuration
+	// Failed admin logins, by source IP and username
+	AdminLockout *auth.AdminLockout
+
+	// Database connection, used to check readiness
+	DB *sql.DB
+
+	// Current Foulkon configuration, read with GetConfig since it changes on reload
 	Config WorkerConfig
+
+	// Guards settings changed on reload while requests are served
+	reloadLock sync.RWMutex
+	// Loaded configuration file, compared on reload
+	config *toml.TomlTree
+	// Repository used to load OIDC providers of authenticator, updated on reload
+	authOidcRepo api.AuthOidcRepo
 }
 
 // WorkerConfig
@@ -75,7 +109,7 @@ type WorkerConfig struct {
 
 	// Authenticator Config
 	AuthType      string
-	OidcProviders []api.OidcProvider
+	OidcProviders oidc.OidcProvidersGetter
 
 	Version string
 }
@@ -85,35 +119,26 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 	var wc WorkerConfig
 
 	// Create logger
-	var logOut io.Writer
 	var err error
-	logOut = os.Stdout
-	loggerType := getDefaultValue(config, "logger.type", "Stdout")
-	if loggerType == "file" {
-		logFileDir := getDefaultValue(config, "logger.file.dir", "/tmp/foulkon.log")
-		workerLogfile, err = os.OpenFile(logFileDir, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
-		if err != nil {
-			return nil, err
-		}
-		wc.FileDirectory = logFileDir
-		logOut = workerLogfile
+	loggerType, loggerLevel, logfileName, err := initLogger(config)
+	if err != nil {
+		return nil, err
 	}
 	wc.LoggerType = loggerType
+	wc.LoggerLevel = loggerLevel.String()
+	wc.FileDirectory = logfileName
 
-	// Logger level. Defaults to INFO
-	loglevel, err := logrus.ParseLevel(getDefaultValue(config, "logger.level", "info"))
-	if err != nil {
-		loglevel = logrus.InfoLevel
+	// Authorization decision logger
+	if err := initDecisionLogger(config); err != nil {
+		api.Log.Error(err)
+		return nil, err
 	}
-	wc.LoggerLevel = loglevel.String()
 
-	api.Log = &logrus.Logger{
-		Out:       logOut,
-		Formatter: &logrus.JSONFormatter{},
-		Hooks:     make(logrus.LevelHooks),
-		Level:     loglevel,
+	// Tracing
+	if err := initTracer(config, tracing.SERVICE_WORKER); err != nil {
+		api.Log.Error(err)
+		return nil, err
 	}
-	api.Log.Infof("Logger type: %v, LogLevel: %v", loggerType, api.Log.Level.String())
 
 	// Start DB with API
 	var authApi api.WorkerAPI
@@ -154,6 +179,9 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 			PolicyRepo:   repoDB,
 			ProxyRepo:    repoDB,
 			AuthOidcRepo: repoDB,
+			WebhookRepo:  repoDB,
+			ApiKeyRepo:   repoDB,
+			ChangeRepo:   repoDB,
 		}
 		wc.IdleConns, _ = strconv.Atoi(dbIdleconns)
 		wc.MaxOpenConns, _ = strconv.Atoi(dbMaxopenconns)
@@ -165,72 +193,43 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 		return nil, err
 	}
 
-	// Instantiate Auth Connector
-	var authConnector auth.AuthConnector
-	authType, err := getMandatoryValue(config, "authenticator.type")
+	// Webhook dispatcher
+	webhookDispatcher, err = newWebhookDispatcher(config, authApi.WebhookRepo)
 	if err != nil {
-		return nil, err
-	}
-	wc.AuthType = authType
-
-	switch authType {
-	case "header":
-		headerName, err := getMandatoryValue(config, "authenticator.header.name")
-		if err != nil {
-			api.Log.Warn("Header authenticator configured, but no header provided - only admin access allowed")
-		} else {
-			authConnector = header.InitHeaderConnector(headerName)
-			api.Log.Infof("Header authenticator configured with header: %v", headerName)
-		}
-	case "oidc":
-		oidcProviders, total, err := authApi.AuthOidcRepo.GetOidcProvidersFiltered(&api.Filter{})
-		if err != nil {
-			return nil, err
-		}
-
-		if total > 0 {
-			wc.OidcProviders = oidcProviders
-			api.Log.Infof("OIDC connectors retrieved %v", total)
-
-			authOidcConnector, err := oidc.InitOIDCConnector(oidcProviders)
-			if err != nil {
-				api.Log.Error(err)
-				return nil, err
-			}
-			authConnector = authOidcConnector
-			api.Log.Infof("OIDC connector configured with %v OIDC Providers: %v", total, oidcProviders)
-		} else {
-			api.Log.Warn("No OIDC connectors retrieved, only admin access allowed")
-		}
-	default:
-		err := fmt.Errorf("Unexpected auth_connector_type value in configuration file: '%s' (maybe it is empty)", authType)
 		api.Log.Error(err)
 		return nil, err
 	}
+	authApi.Notifier = webhookDispatcher
 
-	adminUser, err := getMandatoryValue(config, "admin.username")
+	// Instantiate Auth Connector
+	authConnector, authType, oidcProviders, err := newAuthConnector(config, authApi.AuthOidcRepo, authApi, authApi)
 	if err != nil {
 		api.Log.Error(err)
 		return nil, err
 	}
-	adminPassword, err := getMandatoryValue(config, "admin.password")
+	wc.AuthType = authType
+	wc.OidcProviders = oidcProviders
+
+	admins, err := getAdminAccounts(config)
 	if err != nil {
 		api.Log.Error(err)
 		return nil, err
 	}
-	if len(strings.TrimSpace(adminUser)) < 1 || len(strings.TrimSpace(adminPassword)) < 1 {
-		err := fmt.Errorf("Admin user config unexpected adminUser:%v, adminpassword:%v", adminUser, adminPassword)
+	lockoutAttempts, lockout, maxLockout, err := getAdminLockout(config)
+	if err != nil {
 		api.Log.Error(err)
 		return nil, err
 	}
+	adminLockout := auth.NewAdminLockout(lockoutAttempts, lockout, maxLockout)
 
 	// Middlewares
 	middlewares := make(map[string]middleware.Middleware)
 
 	// Authenticator middleware
-	authenticatorMiddleware := auth.NewAuthenticatorMiddleware(authConnector, adminUser, adminPassword)
+	authenticatorMiddleware := auth.NewAuthenticatorMiddleware(authConnector, admins, adminLockout)
 	middlewares[middleware.AUTHENTICATOR_MIDDLEWARE] = authenticatorMiddleware
-	api.Log.Infof("Created authenticator with admin username %v", adminUser)
+	api.Log.Infof("Created authenticator with admin usernames %v, locked out after %v failed logins for %v up to %v",
+		admins.Usernames(), lockoutAttempts, lockout, maxLockout)
 
 	// X-Request-Id middleware
 	xrequestidMiddleware := xrequestid.NewXRequestIdMiddleware()
@@ -251,6 +250,24 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 		return nil, err
 	}
 
+	clientCAFile, requireClientCert, err := getClientCertConfig(config)
+	if err != nil {
+		api.Log.Error(err)
+		return nil, err
+	}
+
+	shutdownTimeout, err := getShutdownTimeout(config)
+	if err != nil {
+		api.Log.Error(err)
+		return nil, err
+	}
+
+	scimOrg, err := getScimOrg(config)
+	if err != nil {
+		api.Log.Error(err)
+		return nil, err
+	}
+
 	wc.Version = FOULKON_VERSION
 
 	return &Worker{
@@ -258,6 +275,10 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 		Port:              port,
 		CertFile:          getDefaultValue(config, "server.certfile", ""),
 		KeyFile:           getDefaultValue(config, "server.keyfile", ""),
+		ClientCAFile:      clientCAFile,
+		RequireClientCert: requireClientCert,
+		ShutdownTimeout:   shutdownTimeout,
+		ScimOrg:           scimOrg,
 		MiddlewareHandler: &middleware.MiddlewareHandler{Middlewares: middlewares},
 		UserApi:           authApi,
 		GroupApi:          authApi,
@@ -265,25 +286,439 @@ func NewWorker(config *toml.TomlTree) (*Worker, error) {
 		AuthzApi:          authApi,
 		ProxyApi:          authApi,
 		AuthOidcAPI:       authApi,
+		WebhookApi:        authApi,
+		ApiKeyApi:         authApi,
+		ChangeApi:         authApi,
+		DB:                db,
 		Config:            wc,
+		config:            config,
+		AdminLockout:      adminLockout,
+		authOidcRepo:      authApi.AuthOidcRepo,
 	}, nil
 }
 
+// Reload applies settings of config that can change while running: logger, admin accounts,
+// authenticator connector with its OIDC providers, TLS certificate files and shutdown timeout.
+// Settings that need a restart are logged. Nothing is applied if any value is invalid or TLS
+// certificate can't be loaded.
+func (w *Worker) Reload(config *toml.TomlTree) error {
+	shutdownTimeout, err := getShutdownTimeout(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	admins, err := getAdminAccounts(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	lockoutAttempts, lockout, maxLockout, err := getAdminLockout(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	authConnector, authType, oidcProviders, err := newAuthConnector(config, w.authOidcRepo, w.ApiKeyApi, w.AuthOidcAPI)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	certFile := getDefaultValue(config, "server.certfile", "")
+	keyFile := getDefaultValue(config, "server.keyfile", "")
+	if err := checkKeyPair(certFile, keyFile); err != nil {
+		api.Log.Error(err)
+		return err
+	}
+	// Logger is updated in place, closing previous log file
+	loggerType, loggerLevel, logfileName, err := initLogger(config)
+	if err != nil {
+		api.Log.Error(err)
+		return err
+	}
+
+	w.reloadLock.Lock()
+	defer w.reloadLock.Unlock()
+	w.Config.LoggerType = loggerType
+	w.Config.LoggerLevel = loggerLevel.String()
+	w.Config.FileDirectory = logfileName
+
+	authenticator := w.MiddlewareHandler.Middlewares[middleware.AUTHENTICATOR_MIDDLEWARE].(*auth.AuthenticatorMiddleware)
+	authenticator.Update(authConnector, admins)
+	w.AdminLockout.Update(lockoutAttempts, lockout, maxLockout)
+	w.Config.AuthType = authType
+	w.Config.OidcProviders = oidcProviders
+	api.Log.Infof("Reloaded authenticator with admin usernames %v, locked out after %v failed logins for %v up to %v",
+		admins.Usernames(), lockoutAttempts, lockout, maxLockout)
+
+	w.CertFile, w.KeyFile = certFile, keyFile
+	w.ShutdownTimeout = shutdownTimeout
+
+	if w.config != nil {
+		logRestartRequired(w.config, config)
+	}
+	w.config = config
+	return nil
+}
+
+// GetConfig returns a copy of current Foulkon configuration
+func (w *Worker) GetConfig() WorkerConfig {
+	w.reloadLock.RLock()
+	defer w.reloadLock.RUnlock()
+	return w.Config
+}
+
+// GetCertFiles returns current TLS certificate and key files
+func (w *Worker) GetCertFiles() (string, string) {
+	w.reloadLock.RLock()
+	defer w.reloadLock.RUnlock()
+	return w.CertFile, w.KeyFile
+}
+
+// GetShutdownTimeout returns current max time to wait for in-flight requests when shutting down
+func (w *Worker) GetShutdownTimeout() time.Duration {
+	w.reloadLock.RLock()
+	defer w.reloadLock.RUnlock()
+	return w.ShutdownTimeout
+}
+
 func CloseWorker() int {
 	status := 0
+	if webhookDispatcher != nil {
+		webhookDispatcher.Stop()
+	}
 	if err := db.Close(); err != nil {
 		api.Log.Errorf("Couldn't close DB connection: %v", err)
 		status = 1
 	}
-	if workerLogfile != nil {
-		if err := workerLogfile.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Couldn't close logfile: %v", err)
-			status = 1
-		}
+	if closeLogger() != 0 {
+		status = 1
+	}
+	if closeDecisionLogger() != 0 {
+		status = 1
+	}
+	if closeTracer() != 0 {
+		status = 1
 	}
 	return status
 }
 
+// newAuthConnector creates the authenticator connector using configuration values. Type can be a comma
+// separated list of connector types, tried in order. OIDC providers are retrieved from repo, and refreshed
+// periodically, and users of providers with user provisioning are created with provisioner. API keys of
+// service accounts are validated with apiKeys. Connector is nil if only admin access is allowed.
+func newAuthConnector(config *toml.TomlTree, repo api.AuthOidcRepo, apiKeys apikey.Authenticator,
+	provisioner oidc.UserProvisioner) (auth.AuthConnector, string, oidc.OidcProvidersGetter, error) {
+	authType, err := getMandatoryValue(config, "authenticator.type")
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	connectors := []auth.NamedConnector{}
+	var oidcProviders oidc.OidcProvidersGetter
+	for _, connectorType := range strings.Split(authType, ",") {
+		connectorType = strings.TrimSpace(connectorType)
+		for _, connector := range connectors {
+			if connector.Name == connectorType {
+				return nil, "", nil, fmt.Errorf("Authenticator type %v is defined more than once", connectorType)
+			}
+		}
+		connector, providers, err := newTypeConnector(config, connectorType, repo, apiKeys, provisioner)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if providers != nil {
+			oidcProviders = providers
+		}
+		if connector != nil {
+			connectors = append(connectors, auth.NamedConnector{Name: connectorType, Connector: connector})
+		}
+	}
+	if len(connectors) < 1 {
+		return nil, authType, oidcProviders, nil
+	}
+
+	chain := auth.NewChainConnector(connectors...)
+	if len(connectors) > 1 {
+		api.Log.Infof("Authenticator connectors tried in order: %v", chain.Names())
+	}
+	return chain, authType, oidcProviders, nil
+}
+
+// newTypeConnector creates the authenticator connector of a type using configuration values, with the
+// OIDC providers it accepts if it's an OIDC connector. Connector is nil if it isn't configured.
+func newTypeConnector(config *toml.TomlTree, authType string, repo api.AuthOidcRepo, apiKeys apikey.Authenticator,
+	provisioner oidc.UserProvisioner) (auth.AuthConnector, oidc.OidcProvidersGetter, error) {
+	var err error
+	switch authType {
+	case "header":
+		headerName, err := getMandatoryValue(config, "authenticator.header.name")
+		if err != nil {
+			api.Log.Warn("Header authenticator configured, but no header provided - only admin access allowed")
+			return nil, nil, nil
+		}
+		api.Log.Infof("Header authenticator configured with header: %v", headerName)
+		return header.InitHeaderConnector(headerName), nil, nil
+	case "oidc":
+		refresh, err := time.ParseDuration(getDefaultValue(config, "authenticator.oidc.refresh", "10s"))
+		if err != nil || refresh <= 0 {
+			return nil, nil, fmt.Errorf("Invalid authenticator oidc refresh value, it must be a positive duration (e.g. 10s)")
+		}
+		providerCache := oidc.NewProviderCache(repo, refresh)
+		oidcProviders, err := providerCache.GetOidcProviders()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(oidcProviders) < 1 {
+			api.Log.Warn("No OIDC connectors retrieved, only admin access allowed until one is added")
+		}
+
+		authOidcConnector, err := oidc.InitOIDCConnector(providerCache.GetOidcProviders, provisioner)
+		if err != nil {
+			return nil, nil, err
+		}
+		api.Log.Infof("OIDC connector configured with %v OIDC Providers, refreshed every %v: %v",
+			len(oidcProviders), refresh, oidcProviders)
+		return authOidcConnector, providerCache.GetOidcProviders, nil
+	case "jwt":
+		keySet, err := newJWTKeySet(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		issuer := getDefaultValue(config, "authenticator.jwt.issuer", "")
+		audience := getDefaultValue(config, "authenticator.jwt.audience", "")
+		userClaim := getDefaultValue(config, "authenticator.jwt.userclaim", jwt.DEFAULT_USER_CLAIM)
+		api.Log.Infof("JWT authenticator configured with issuer: %v, audience: %v, user claim: %v", issuer, audience, userClaim)
+		return jwt.InitJWTConnector(keySet, issuer, audience, userClaim), nil, nil
+	case "apikey":
+		api.Log.Infof("API key authenticator configured, only service accounts with path %v are allowed", api.SERVICE_ACCOUNT_PATH)
+		return apikey.InitApiKeyConnector(apiKeys), nil, nil
+	case "mtls":
+		if getDefaultValue(config, "server.clientcafile", "") == "" {
+			return nil, nil, errors.New("mtls authenticator needs server clientcafile to verify client certificates")
+		}
+		userField := getDefaultValue(config, "authenticator.mtls.userfield", mtls.USER_FIELD_CN)
+		var userPattern *regexp.Regexp
+		if pattern := getDefaultValue(config, "authenticator.mtls.userpattern", ""); pattern != "" {
+			if userPattern, err = regexp.Compile(pattern); err != nil {
+				return nil, nil, fmt.Errorf("Invalid authenticator mtls userpattern value: %v", err)
+			}
+		}
+		connector, err := mtls.InitMTLSConnector(userField, userPattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		api.Log.Infof("mTLS authenticator configured with user field: %v, user pattern: %v", userField, userPattern)
+		return connector, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("Unexpected auth_connector_type value in configuration file: '%s' (maybe it is empty)", authType)
+	}
+}
+
+// newJWTKeySet loads public keys of JWT authenticator from the PEM file, JWKS file or JWKS URL of configuration
+func newJWTKeySet(config *toml.TomlTree) (*jwt.KeySet, error) {
+	publicKey := getDefaultValue(config, "authenticator.jwt.publickey", "")
+	jwksFile := getDefaultValue(config, "authenticator.jwt.jwksfile", "")
+	jwksURL := getDefaultValue(config, "authenticator.jwt.jwksurl", "")
+
+	switch {
+	case publicKey != "" && jwksFile == "" && jwksURL == "":
+		api.Log.Infof("JWT authenticator loading keys from PEM file %v", publicKey)
+		return jwt.NewPEMKeySet(publicKey)
+	case publicKey == "" && jwksFile != "" && jwksURL == "":
+		api.Log.Infof("JWT authenticator loading keys from JWKS file %v", jwksFile)
+		return jwt.NewJWKSFileKeySet(jwksFile)
+	case publicKey == "" && jwksFile == "" && jwksURL != "":
+		refresh, err := time.ParseDuration(getDefaultValue(config, "authenticator.jwt.refresh", "10m"))
+		if err != nil || refresh <= 0 {
+			return nil, fmt.Errorf("Invalid authenticator jwt refresh value, it must be a positive duration (e.g. 10m)")
+		}
+		api.Log.Infof("JWT authenticator loading keys from JWKS URL %v, refreshed every %v", jwksURL, refresh)
+		return jwt.NewJWKSURLKeySet(jwksURL, refresh)
+	default:
+		return nil, fmt.Errorf("JWT authenticator needs one of publickey, jwksfile or jwksurl values in configuration file")
+	}
+}
+
+// getAdminAccounts returns admin accounts of configuration: admin username with its password hash
+// or plain text password, and admins of accounts file
+func getAdminAccounts(config *toml.TomlTree) (*auth.AdminAccounts, error) {
+	admins := auth.NewAdminAccounts()
+	if config.Has("admin.username") {
+		adminUser, err := getMandatoryValue(config, "admin.username")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case config.Has("admin.passwordhash"):
+			passwordHash, err := getMandatoryValue(config, "admin.passwordhash")
+			if err != nil {
+				return nil, err
+			}
+			if err := admins.AddPasswordHash(adminUser, passwordHash); err != nil {
+				return nil, err
+			}
+		case config.Has("admin.password"):
+			api.Log.Warnf("Password of admin %v is in plain text, use passwordhash instead", adminUser)
+			adminPassword, err := getMandatoryValue(config, "admin.password")
+			if err != nil {
+				return nil, err
+			}
+			if err := admins.AddPassword(adminUser, adminPassword); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("Admin %v needs a passwordhash or password value in configuration file", adminUser)
+		}
+	}
+	if config.Has("admin.accountsfile") {
+		accountsFile, err := getMandatoryValue(config, "admin.accountsfile")
+		if err != nil {
+			return nil, err
+		}
+		if err := admins.AddAccountsFile(accountsFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(admins.Usernames()) < 1 {
+		return nil, errors.New("No admin configured, admin needs username or accountsfile values in configuration file")
+	}
+	return admins, nil
+}
+
+// getAdminLockout returns failed admin logins before lockout, lockout time and max lockout time of configuration
+func getAdminLockout(config *toml.TomlTree) (int, time.Duration, time.Duration, error) {
+	attempts, err := strconv.Atoi(getDefaultValue(config, "admin.lockoutattempts", "5"))
+	if err != nil || attempts < 0 {
+		return 0, 0, 0, fmt.Errorf("Invalid admin lockoutattempts value, it must be a number greater or equal than 0")
+	}
+	lockout, err := time.ParseDuration(getDefaultValue(config, "admin.lockoutduration", "1m"))
+	if err != nil || lockout <= 0 {
+		return 0, 0, 0, fmt.Errorf("Invalid admin lockoutduration value, it must be a positive duration (e.g. 1m)")
+	}
+	maxLockout, err := time.ParseDuration(getDefaultValue(config, "admin.lockoutmaxduration", "1h"))
+	if err != nil || maxLockout < lockout {
+		return 0, 0, 0, fmt.Errorf("Invalid admin lockoutmaxduration value, it must be a duration greater or equal than lockoutduration (e.g. 1h)")
+	}
+	return attempts, lockout, maxLockout, nil
+}
+
+// getClientCertConfig returns CA bundle file that verifies client certificates of configuration, and if
+// clients without certificate are rejected. Client certificates need TLS enabled.
+func getClientCertConfig(config *toml.TomlTree) (string, bool, error) {
+	clientCAFile := getDefaultValue(config, "server.clientcafile", "")
+	if clientCAFile == "" {
+		return "", false, nil
+	}
+	if getDefaultValue(config, "server.certfile", "") == "" || getDefaultValue(config, "server.keyfile", "") == "" {
+		return "", false, errors.New("Invalid server clientcafile value, client certificates need server certfile and keyfile")
+	}
+	switch clientAuth := getDefaultValue(config, "server.clientauth", "optional"); clientAuth {
+	case "optional":
+		return clientCAFile, false, nil
+	case "require":
+		return clientCAFile, true, nil
+	default:
+		return "", false, fmt.Errorf("Invalid server clientauth value %v, it must be optional or require", clientAuth)
+	}
+}
+
+// getShutdownTimeout returns max time to wait for in-flight requests of configuration
+func getShutdownTimeout(config *toml.TomlTree) (time.Duration, error) {
+	shutdownTimeout, err := time.ParseDuration(getDefaultValue(config, "server.shutdowntimeout", "30s"))
+	if err != nil || shutdownTimeout <= 0 {
+		return 0, fmt.Errorf("Invalid server shutdowntimeout value, it must be a positive duration (e.g. 30s)")
+	}
+	return shutdownTimeout, nil
+}
+
+// checkKeyPair returns an error if TLS certificate and key files, when set, can't be loaded
+func checkKeyPair(certFile string, keyFile string) error {
+	if certFile == "" && keyFile == "" {
+		return nil
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return fmt.Errorf("Invalid TLS certificate %v and key %v: %v", certFile, keyFile, err)
+	}
+	return nil
+}
+
+func getScimOrg(config *toml.TomlTree) (string, error) {
+	org := getDefaultValue(config, "scim.org", "")
+	if org != "" && !api.IsValidOrg(org) {
+		return "", fmt.Errorf("Invalid scim org value %v", org)
+	}
+	return org, nil
+}
+
+// Configuration keys that are only applied when a server starts
+var restartKeys = []string{
+	"server.host",
+	"server.port",
+	"server.worker-host",
+	"server.clientcafile",
+	"server.clientauth",
+	"server.workercafile",
+	"database.type",
+	"database.postgres.datasourcename",
+	"database.postgres.idleconns",
+	"database.postgres.maxopenconns",
+	"database.postgres.connttl",
+	"webhooks.workers",
+	"webhooks.queuesize",
+	"webhooks.retries",
+	"webhooks.backoff",
+	"webhooks.timeout",
+	"logger.decision.type",
+	"logger.decision.file.dir",
+	"logger.decision.file.maxsize",
+	"logger.decision.file.maxbackups",
+	"logger.decision.samplerate",
+	"tracing.type",
+	"tracing.samplerate",
+	"tracing.otlp.endpoint",
+	"tracing.otlp.insecure",
+	"scim.org",
+}
+
+// logRestartRequired warns about settings changed from previous configuration that aren't applied until restart
+func logRestartRequired(previous *toml.TomlTree, config *toml.TomlTree) {
+	for _, key := range restartKeys {
+		if fmt.Sprint(previous.Get(key)) != fmt.Sprint(config.Get(key)) {
+			api.Log.Warnf("Configuration value %v changed, it needs a restart to be applied", key)
+		}
+	}
+	if previous.Has("server.certfile") != config.Has("server.certfile") {
+		api.Log.Warn("TLS enabled or disabled, it needs a restart to be applied")
+	}
+}
+
+// newWebhookDispatcher creates the webhook dispatcher using configuration values
+func newWebhookDispatcher(config *toml.TomlTree, repo api.WebhookRepo) (*notify.WebhookDispatcher, error) {
+	workers, err := strconv.Atoi(getDefaultValue(config, "webhooks.workers", "2"))
+	if err != nil || workers < 1 {
+		return nil, fmt.Errorf("Invalid webhooks workers value, it must be a positive number")
+	}
+	queueSize, err := strconv.Atoi(getDefaultValue(config, "webhooks.queuesize", "1000"))
+	if err != nil || queueSize < 1 {
+		return nil, fmt.Errorf("Invalid webhooks queuesize value, it must be a positive number")
+	}
+	retries, err := strconv.Atoi(getDefaultValue(config, "webhooks.retries", "5"))
+	if err != nil || retries < 0 {
+		return nil, fmt.Errorf("Invalid webhooks retries value, it must be a number greater or equal than 0")
+	}
+	backoff, err := time.ParseDuration(getDefaultValue(config, "webhooks.backoff", "1s"))
+	if err != nil || backoff <= 0 {
+		return nil, fmt.Errorf("Invalid webhooks backoff value, it must be a positive duration (e.g. 1s)")
+	}
+	timeout, err := time.ParseDuration(getDefaultValue(config, "webhooks.timeout", "10s"))
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("Invalid webhooks timeout value, it must be a positive duration (e.g. 10s)")
+	}
+	api.Log.Infof("Webhook dispatcher configured with %v workers, %v retries, backoff %v and timeout %v",
+		workers, retries, backoff, timeout)
+
+	return notify.NewWebhookDispatcher(repo, workers, queueSize, retries, backoff, timeout), nil
+}
+
 // This aux method returns mandatory config value or any error occurred
 func getMandatoryValue(config *toml.TomlTree, key string) (string, error) {
 	if !config.Has(key) {
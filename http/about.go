@@ -54,7 +54,7 @@ func (wh *WorkerHandler) HandleGetCurrentConfig(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	wc := wh.worker.Config
+	wc := wh.worker.GetConfig()
 	// Get Logger config
 	logger := LoggerConfig{
 		Type:          wc.LoggerType,
@@ -70,10 +70,17 @@ func (wh *WorkerHandler) HandleGetCurrentConfig(w http.ResponseWriter, r *http.R
 		ConnTtl:      wc.ConnTtl,
 	}
 
-	// Get Authenticator config
+	// Get Authenticator config, with OIDC providers in use
 	auth := AuthConnectorConfig{
-		Type:          wc.AuthType,
-		OidcProviders: wc.OidcProviders,
+		Type: wc.AuthType,
+	}
+	if wc.OidcProviders != nil {
+		oidcProviders, err := wc.OidcProviders()
+		if err != nil {
+			wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusInternalServerError)
+			return
+		}
+		auth.OidcProviders = oidcProviders
 	}
 
 	// Config Response
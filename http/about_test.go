@@ -3,11 +3,20 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/foulkon"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	"github.com/Tecsisa/foulkon/middleware/logger"
+	"github.com/Tecsisa/foulkon/middleware/xrequestid"
+	"github.com/pelletier/go-toml"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -109,3 +118,93 @@ func TestWorkerHandler_HandleGetCurrentConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerHandler_HandleGetCurrentConfigWhileReloading(t *testing.T) {
+	config, err := toml.Load(`
+[server]
+certfile = ""
+keyfile = ""
+shutdowntimeout = "10s"
+
+[logger]
+type = "Stdout"
+level = "error"
+
+[admin]
+username = "admin"
+passwordhash = "$2a$04$7hj2DUjfZHHK5Yr2R5U6NeQ2hUnnhzdlW5ZmQBGhDhjaIRzF4CjNq"
+lockoutattempts = "5"
+lockoutduration = "1m"
+lockoutmaxduration = "1h"
+
+[authenticator]
+type = "header"
+
+[authenticator.header]
+name = "X-User"
+`)
+	assert.Nil(t, err, "Error in test")
+
+	// Reload replaces logger of tests
+	defer func(log *logrus.Logger) {
+		api.Log = log
+	}(api.Log)
+
+	adminLockout := auth.NewAdminLockout(5, time.Minute, time.Hour)
+	middlewares := map[string]middleware.Middleware{
+		middleware.AUTHENTICATOR_MIDDLEWARE:  auth.NewAuthenticatorMiddleware(authConnector, auth.NewAdminAccounts(), adminLockout),
+		middleware.XREQUESTID_MIDDLEWARE:     xrequestid.NewXRequestIdMiddleware(),
+		middleware.REQUEST_LOGGER_MIDDLEWARE: logger.NewRequestLoggerMiddleware(),
+	}
+	worker := &foulkon.Worker{
+		MiddlewareHandler: &middleware.MiddlewareHandler{Middlewares: middlewares},
+		AdminLockout:      adminLockout,
+		Config:            foulkon.WorkerConfig{Version: "test"},
+	}
+	// First reload creates logger, before requests use it
+	assert.Nil(t, worker.Reload(config), "Error in test")
+	handler := WorkerHandlerRouter(worker)
+
+	// Request configuration from several clients until reloads end
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			assert.Nil(t, worker.Reload(config), "Error in test")
+		}
+	}()
+	var clients sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		clients.Add(1)
+		go func() {
+			defer clients.Done()
+			for reloading := true; reloading; {
+				select {
+				case <-done:
+					reloading = false
+				default:
+				}
+				req := httptest.NewRequest(http.MethodGet, "/about", nil)
+				req.SetBasicAuth("admin", "admin")
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code, "Error in test")
+				response := Config{}
+				err := json.NewDecoder(w.Body).Decode(&response)
+				assert.Nil(t, err, "Error in test")
+				assert.Equal(t, Config{
+					Logger: LoggerConfig{
+						Type:  "Stdout",
+						Level: "error",
+					},
+					AuthConnector: AuthConnectorConfig{
+						Type: "header",
+					},
+					Version: "test",
+				}, response, "Error in test")
+			}
+		}()
+	}
+	clients.Wait()
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	"github.com/julienschmidt/httprouter"
+)
+
+// RESPONSES
+
+type ListAdminLockoutsResponse struct {
+	Lockouts []auth.LockoutState `json:"lockouts"`
+}
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleListAdminLockouts(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, err := wh.processHttpRequest(r, w, ps, nil)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	// Only admin is authorized
+	if !requestInfo.Admin {
+		err = &api.Error{
+			Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+			Message: "Unauthorized, user is not admin",
+		}
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusForbidden)
+		return
+	}
+
+	// Failed admin logins by source IP and username
+	response := &ListAdminLockoutsResponse{
+		Lockouts: wh.worker.AdminLockout.States(),
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, nil, http.StatusOK)
+}
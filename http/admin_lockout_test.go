@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleListAdminLockouts(t *testing.T) {
+	// Failed admin logins from another source IP, so test requests aren't locked out
+	for i := 0; i < 5; i++ {
+		adminLockout.Fail("10.0.0.1", "attacker")
+	}
+	defer adminLockout.Succeed("10.0.0.1", "attacker")
+
+	testcases := map[string]struct {
+		adminUser     string
+		adminPassword string
+		// Expected result
+		expectedStatusCode int
+		expectedLockouts   []auth.LockoutState
+		expectedError      api.Error
+	}{
+		"OkCase": {
+			adminUser:          "admin",
+			adminPassword:      "admin",
+			expectedStatusCode: http.StatusOK,
+			expectedLockouts: []auth.LockoutState{
+				{
+					Type:     auth.LOCKOUT_TYPE_IP,
+					Value:    "10.0.0.1",
+					Failures: 5,
+				},
+				{
+					Type:     auth.LOCKOUT_TYPE_USERNAME,
+					Value:    "attacker",
+					Failures: 5,
+				},
+			},
+		},
+		"ErrorCaseNotAdmin": {
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized, user is not admin",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		req, err := http.NewRequest(http.MethodGet, server.URL+ADMIN_LOCKOUTS_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.adminUser != "" {
+			req.SetBasicAuth(test.adminUser, test.adminPassword)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := ListAdminLockoutsResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result, ignoring times
+			if assert.Len(t, response.Lockouts, len(test.expectedLockouts), "Error in test case %v", n) {
+				for i, lockout := range response.Lockouts {
+					assert.NotNil(t, lockout.LockedUntil, "Error in test case %v", n)
+					lockout.LastFailure = test.expectedLockouts[i].LastFailure
+					lockout.LockedUntil = nil
+					assert.Equal(t, test.expectedLockouts[i], lockout, "Error in test case %v", n)
+				}
+			}
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
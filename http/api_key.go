@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+// REQUESTS
+
+type CreateApiKeyRequest struct {
+	Name     string     `json:"name,omitempty"`
+	ExpireAt *time.Time `json:"expireAt,omitempty"`
+}
+
+// RESPONSES
+
+type ListApiKeysResponse struct {
+	ApiKeys []api.ApiKey `json:"apiKeys,omitempty"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+	Total   int          `json:"total"`
+	Next    string       `json:"next,omitempty"`
+	Prev    string       `json:"prev,omitempty"`
+}
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleAddApiKey(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &CreateApiKeyRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call API key API to create the new API key
+	response, err := wh.worker.ApiKeyApi.AddApiKey(requestInfo, filterData.ExternalID, request.Name, request.ExpireAt)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
+}
+
+func (wh *WorkerHandler) HandleGetApiKey(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call API key API to get the API key
+	response, err := wh.worker.ApiKeyApi.GetApiKey(requestInfo, filterData.ExternalID, filterData.ApiKeyID)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleListApiKeys(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call API key API to list the API keys
+	result, total, err := wh.worker.ApiKeyApi.ListApiKeys(requestInfo, filterData)
+	// Create response
+	response := &ListApiKeysResponse{
+		ApiKeys: result,
+		Offset:  filterData.Offset,
+		Limit:   filterData.Limit,
+		Total:   total,
+		Next:    filterData.NextCursor,
+		Prev:    filterData.PrevCursor,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleRotateApiKey(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call API key API to rotate the API key
+	response, err := wh.worker.ApiKeyApi.RotateApiKey(requestInfo, filterData.ExternalID, filterData.ApiKeyID)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleRemoveApiKey(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call API key API to delete the API key
+	err := wh.worker.ApiKeyApi.RemoveApiKey(requestInfo, filterData.ExternalID, filterData.ApiKeyID)
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
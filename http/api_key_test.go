@@ -0,0 +1,611 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleAddApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	expireAt := now.Add(time.Hour)
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		request    *CreateApiKeyRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.ApiKey
+		expectedError      api.Error
+		// Manager Results
+		addApiKeyResult *api.ApiKey
+		// Manager Errors
+		addApiKeyErr error
+	}{
+		"OkCase": {
+			externalID: "ci-job",
+			request: &CreateApiKeyRequest{
+				Name:     "deploy",
+				ExpireAt: &expireAt,
+			},
+			addApiKeyResult: &api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				UserID:     "UserID",
+				ExternalID: "ci-job",
+				Key:        "KeyID.secret",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+				ExpireAt:   &expireAt,
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				ExternalID: "ci-job",
+				Key:        "KeyID.secret",
+				CreateAt:   now,
+				UpdateAt:   now,
+				ExpireAt:   &expireAt,
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			externalID:         "ci-job",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			externalID: "ci-job",
+			request: &CreateApiKeyRequest{
+				Name: "deploy",
+			},
+			addApiKeyErr: &api.Error{
+				Code: api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code: api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+		},
+		"ErrorCaseInvalidParameter": {
+			externalID: "user1",
+			request: &CreateApiKeyRequest{
+				Name: "deploy",
+			},
+			addApiKeyErr: &api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			externalID: "ci-job",
+			request: &CreateApiKeyRequest{
+				Name: "deploy",
+			},
+			addApiKeyErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			externalID: "ci-job",
+			request: &CreateApiKeyRequest{
+				Name: "deploy",
+			},
+			addApiKeyErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AddApiKeyMethod][0] = test.addApiKeyResult
+		testApi.ArgsOut[AddApiKeyMethod][1] = test.addApiKeyErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/api-keys", test.externalID)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.externalID, testApi.ArgsIn[AddApiKeyMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Name, testApi.ArgsIn[AddApiKeyMethod][2], "Error in test case %v", n)
+			if test.request.ExpireAt != nil {
+				receivedExpireAt, _ := testApi.ArgsIn[AddApiKeyMethod][3].(*time.Time)
+				if assert.NotNil(t, receivedExpireAt, "Error in test case %v", n) {
+					assert.True(t, test.request.ExpireAt.Equal(*receivedExpireAt), "Error in test case %v", n)
+				}
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := api.ApiKey{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result, secret hash is never returned
+			assert.Equal(t, test.expectedResponse.Key, response.Key, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse.ID, response.ID, "Error in test case %v", n)
+			assert.Empty(t, response.SecretHash, "Error in test case %v", n)
+			assert.Empty(t, response.UserID, "Error in test case %v", n)
+			assert.True(t, test.expectedResponse.ExpireAt.Equal(*response.ExpireAt), "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID   string
+		id           string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.ApiKey
+		expectedError      api.Error
+		// Manager Results
+		getApiKeyResult *api.ApiKey
+		// Manager Errors
+		getApiKeyErr error
+	}{
+		"OkCase": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				ExternalID: "ci-job",
+				CreateAt:   now,
+				UpdateAt:   now,
+				LastUsedAt: &now,
+			},
+			getApiKeyResult: &api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				UserID:     "UserID",
+				ExternalID: "ci-job",
+				SecretHash: "hash",
+				CreateAt:   now,
+				UpdateAt:   now,
+				LastUsedAt: &now,
+			},
+		},
+		"ErrorCaseInvalidRequest": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseApiKeyNotFound": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusNotFound,
+			getApiKeyErr: &api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusForbidden,
+			getApiKeyErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusInternalServerError,
+			getApiKeyErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetApiKeyMethod][0] = test.getApiKeyResult
+		testApi.ArgsOut[GetApiKeyMethod][1] = test.getApiKeyErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/api-keys/%v", test.externalID, test.id)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.externalID, testApi.ArgsIn[GetApiKeyMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.id, testApi.ArgsIn[GetApiKeyMethod][2], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.ApiKey{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListApiKeys(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID   string
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListApiKeysResponse
+		expectedError      api.Error
+		// Manager Results
+		listApiKeysResult []api.ApiKey
+		listApiKeysTotal  int
+		// Manager Errors
+		listApiKeysErr error
+	}{
+		"OkCase": {
+			externalID:         "ci-job",
+			filter:             testFilter,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListApiKeysResponse{
+				ApiKeys: []api.ApiKey{
+					{
+						ID:         "KeyID",
+						Name:       "deploy",
+						ExternalID: "ci-job",
+						CreateAt:   now,
+						UpdateAt:   now,
+					},
+				},
+				Total: 1,
+			},
+			listApiKeysResult: []api.ApiKey{
+				{
+					ID:         "KeyID",
+					Name:       "deploy",
+					ExternalID: "ci-job",
+					SecretHash: "hash",
+					CreateAt:   now,
+					UpdateAt:   now,
+				},
+			},
+			listApiKeysTotal: 1,
+		},
+		"ErrorCaseInvalidRequest": {
+			externalID: "ci-job",
+			filter: &api.Filter{
+				Offset: -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			externalID:         "ci-job",
+			filter:             testFilter,
+			expectedStatusCode: http.StatusNotFound,
+			listApiKeysErr: &api.Error{
+				Code: api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			externalID:         "ci-job",
+			filter:             testFilter,
+			expectedStatusCode: http.StatusInternalServerError,
+			listApiKeysErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListApiKeysMethod][0] = test.listApiKeysResult
+		testApi.ArgsOut[ListApiKeysMethod][1] = test.listApiKeysTotal
+		testApi.ArgsOut[ListApiKeysMethod][2] = test.listApiKeysErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/api-keys", test.externalID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			filter := testApi.ArgsIn[ListApiKeysMethod][1].(*api.Filter)
+			assert.Equal(t, test.externalID, filter.ExternalID, "Error in test case %v", n)
+			assert.Equal(t, test.filter.Offset, filter.Offset, "Error in test case %v", n)
+			assert.Equal(t, test.filter.Limit, filter.Limit, "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := ListApiKeysResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRotateApiKey(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		id         string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.ApiKey
+		expectedError      api.Error
+		// Manager Results
+		rotateApiKeyResult *api.ApiKey
+		// Manager Errors
+		rotateApiKeyErr error
+	}{
+		"OkCase": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				ExternalID: "ci-job",
+				Key:        "KeyID.newsecret",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+			rotateApiKeyResult: &api.ApiKey{
+				ID:         "KeyID",
+				Name:       "deploy",
+				UserID:     "UserID",
+				ExternalID: "ci-job",
+				Key:        "KeyID.newsecret",
+				SecretHash: "newhash",
+				CreateAt:   now,
+				UpdateAt:   now,
+			},
+		},
+		"ErrorCaseApiKeyNotFound": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusNotFound,
+			rotateApiKeyErr: &api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusForbidden,
+			rotateApiKeyErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusInternalServerError,
+			rotateApiKeyErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RotateApiKeyMethod][0] = test.rotateApiKeyResult
+		testApi.ArgsOut[RotateApiKeyMethod][1] = test.rotateApiKeyErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/api-keys/%v/rotate", test.externalID, test.id)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[RotateApiKeyMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.id, testApi.ArgsIn[RotateApiKeyMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.ApiKey{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRemoveApiKey(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		id         string
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		removeApiKeyErr error
+	}{
+		"OkCase": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseApiKeyNotFound": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusNotFound,
+			removeApiKeyErr: &api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.API_KEY_BY_ID_NOT_FOUND,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			externalID:         "ci-job",
+			id:                 "KeyID",
+			expectedStatusCode: http.StatusInternalServerError,
+			removeApiKeyErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveApiKeyMethod][0] = test.removeApiKeyErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/api-keys/%v", test.externalID, test.id)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[RemoveApiKeyMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.id, testApi.ArgsIn[RemoveApiKeyMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent, http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
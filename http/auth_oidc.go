@@ -3,23 +3,30 @@ package http
 import (
 	"net/http"
 
+	"github.com/Tecsisa/foulkon/api"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
 type CreateOidcProviderRequest struct {
-	Name        string   `json:"name,omitempty"`
-	Path        string   `json:"path,omitempty"`
-	IssuerURL   string   `json:"issuerUrl,omitempty"`
-	OidcClients []string `json:"clients,omitempty"`
+	Name             string                `json:"name,omitempty"`
+	Path             string                `json:"path,omitempty"`
+	IssuerURL        string                `json:"issuerUrl,omitempty"`
+	OidcClients      []string              `json:"clients,omitempty"`
+	UserProvisioning *api.UserProvisioning `json:"userProvisioning,omitempty"`
+	GroupMappings    []api.GroupMapping    `json:"groupMappings,omitempty"`
+	GroupMappingMode string                `json:"groupMappingMode,omitempty"`
 }
 
 type UpdateOidcProviderRequest struct {
-	Name        string   `json:"name,omitempty"`
-	Path        string   `json:"path,omitempty"`
-	IssuerURL   string   `json:"issuerUrl,omitempty"`
-	OidcClients []string `json:"clients,omitempty"`
+	Name             string                `json:"name,omitempty"`
+	Path             string                `json:"path,omitempty"`
+	IssuerURL        string                `json:"issuerUrl,omitempty"`
+	OidcClients      []string              `json:"clients,omitempty"`
+	UserProvisioning *api.UserProvisioning `json:"userProvisioning,omitempty"`
+	GroupMappings    []api.GroupMapping    `json:"groupMappings,omitempty"`
+	GroupMappingMode string                `json:"groupMappingMode,omitempty"`
 }
 
 // RESPONSES
@@ -29,6 +36,8 @@ type ListOidcProvidersResponse struct {
 	Limit     int      `json:"limit"`
 	Offset    int      `json:"offset"`
 	Total     int      `json:"total"`
+	Next      string   `json:"next,omitempty"`
+	Prev      string   `json:"prev,omitempty"`
 }
 
 // HANDLERS
@@ -43,7 +52,8 @@ func (wh *WorkerHandler) HandleAddOidcProvider(w http.ResponseWriter, r *http.Re
 	}
 
 	// Call Auth Provider API to create the new OIDC provider
-	response, err := wh.worker.AuthOidcAPI.AddOidcProvider(requestInfo, request.Name, request.Path, request.IssuerURL, request.OidcClients)
+	response, err := wh.worker.AuthOidcAPI.AddOidcProvider(requestInfo, request.Name, request.Path, request.IssuerURL, request.OidcClients,
+		request.UserProvisioning, request.GroupMappings, request.GroupMappingMode)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
 }
 
@@ -76,6 +86,8 @@ func (wh *WorkerHandler) HandleListOidcProviders(w http.ResponseWriter, r *http.
 		Offset:    filterData.Offset,
 		Limit:     filterData.Limit,
 		Total:     total,
+		Next:      filterData.NextCursor,
+		Prev:      filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -91,7 +103,8 @@ func (wh *WorkerHandler) HandleUpdateOidcProvider(w http.ResponseWriter, r *http
 
 	// Call Auth Provider API to update the OIDC Provider
 	response, err := wh.worker.AuthOidcAPI.UpdateOidcProvider(requestInfo, filterData.AuthProviderName,
-		request.Name, request.Path, request.IssuerURL, request.OidcClients)
+		request.Name, request.Path, request.IssuerURL, request.OidcClients, request.UserProvisioning, request.GroupMappings,
+		request.GroupMappingMode)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
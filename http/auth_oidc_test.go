@@ -65,6 +65,54 @@ func TestWorkerHandler_HandleAddOidcProvider(t *testing.T) {
 				},
 			},
 		},
+		"OkCaseWithUserProvisioning": {
+			request: &CreateOidcProviderRequest{
+				Name: "test",
+				Path: "/path/",
+				OidcClients: []string{
+					"client1",
+				},
+				IssuerURL: "https://test.com",
+				UserProvisioning: &api.UserProvisioning{
+					PathTemplate: "/employees/{department}/",
+				},
+			},
+			addOidcProviderResult: &api.OidcProvider{
+				ID:        "test1",
+				Name:      "test",
+				Path:      "/path/",
+				CreateAt:  now,
+				UpdateAt:  now,
+				Urn:       api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "test"),
+				IssuerURL: "https://test.com",
+				OidcClients: []api.OidcClient{
+					{
+						Name: "client1",
+					},
+				},
+				UserProvisioning: &api.UserProvisioning{
+					PathTemplate: "/employees/{department}/",
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: api.OidcProvider{
+				ID:        "test1",
+				Name:      "test",
+				Path:      "/path/",
+				CreateAt:  now,
+				UpdateAt:  now,
+				Urn:       api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "test"),
+				IssuerURL: "https://test.com",
+				OidcClients: []api.OidcClient{
+					{
+						Name: "client1",
+					},
+				},
+				UserProvisioning: &api.UserProvisioning{
+					PathTemplate: "/employees/{department}/",
+				},
+			},
+		},
 		"ErrorCaseMalformedRequest": {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
@@ -172,6 +220,9 @@ func TestWorkerHandler_HandleAddOidcProvider(t *testing.T) {
 			assert.Equal(t, test.request.Path, testApi.ArgsIn[AddOidcProviderMethod][2], "Error in test case %v", n)
 			assert.Equal(t, test.request.IssuerURL, testApi.ArgsIn[AddOidcProviderMethod][3], "Error in test case %v", n)
 			assert.Equal(t, test.request.OidcClients, testApi.ArgsIn[AddOidcProviderMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.request.UserProvisioning, testApi.ArgsIn[AddOidcProviderMethod][5], "Error in test case %v", n)
+			assert.Equal(t, test.request.GroupMappings, testApi.ArgsIn[AddOidcProviderMethod][6], "Error in test case %v", n)
+			assert.Equal(t, test.request.GroupMappingMode, testApi.ArgsIn[AddOidcProviderMethod][7], "Error in test case %v", n)
 		}
 
 		// check status code
@@ -496,6 +547,10 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 				Path:        "NewPath",
 				IssuerURL:   "http://test.com",
 				OidcClients: []string{"client1", "client2"},
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_SYNC,
 			},
 			oidcProviderName:   "oidcProviderName",
 			expectedStatusCode: http.StatusOK,
@@ -651,6 +706,9 @@ func TestWorkerHandler_HandleUpdateOidcProvider(t *testing.T) {
 			assert.Equal(t, test.request.Path, testApi.ArgsIn[UpdateOidcProviderMethod][3], "Error in test case %v", n)
 			assert.Equal(t, test.request.IssuerURL, testApi.ArgsIn[UpdateOidcProviderMethod][4], "Error in test case %v", n)
 			assert.Equal(t, test.request.OidcClients, testApi.ArgsIn[UpdateOidcProviderMethod][5], "Error in test case %v", n)
+			assert.Equal(t, test.request.UserProvisioning, testApi.ArgsIn[UpdateOidcProviderMethod][6], "Error in test case %v", n)
+			assert.Equal(t, test.request.GroupMappings, testApi.ArgsIn[UpdateOidcProviderMethod][7], "Error in test case %v", n)
+			assert.Equal(t, test.request.GroupMappingMode, testApi.ArgsIn[UpdateOidcProviderMethod][8], "Error in test case %v", n)
 		}
 
 		// check status code
@@ -1,16 +1,44 @@
 package http
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
 	"github.com/julienschmidt/httprouter"
 )
 
 // REQUESTS
 
 type AuthorizeResourcesRequest struct {
-	Action    string   `json:"action,omitempty"`
-	Resources []string `json:"resources,omitempty"`
+	Action    string              `json:"action,omitempty"`
+	Resources []AuthorizeResource `json:"resources,omitempty"`
+}
+
+// AuthorizeResource is a resource to authorize, passed as an urn string or as an object with urn and tags
+type AuthorizeResource struct {
+	Urn  string            `json:"urn,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func (ar *AuthorizeResource) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		ar.Tags = nil
+		return json.Unmarshal(data, &ar.Urn)
+	}
+	// Use an alias type to avoid recursion
+	type authorizeResource AuthorizeResource
+	return json.Unmarshal(data, (*authorizeResource)(ar))
+}
+
+func (ar AuthorizeResource) MarshalJSON() ([]byte, error) {
+	// Resources without tags are sent as urn strings, understood by all workers
+	if len(ar.Tags) < 1 {
+		return json.Marshal(ar.Urn)
+	}
+	type authorizeResource AuthorizeResource
+	return json.Marshal(authorizeResource(ar))
 }
 
 // RESPONSES
@@ -31,8 +59,21 @@ func (wh *WorkerHandler) HandleGetAuthorizedExternalResources(w http.ResponseWri
 		return
 	}
 
+	resources := []api.ExternalResource{}
+	for _, res := range request.Resources {
+		resources = append(resources, api.ExternalResource{
+			Urn:  res.Urn,
+			Tags: res.Tags,
+		})
+	}
+
+	// Return authenticated user, so proxy decisions are logged with it
+	if requestInfo.Identifier != "" {
+		w.Header().Set(middleware.USER_ID_HEADER, requestInfo.Identifier)
+	}
+
 	// Retrieve allowed resources
-	result, err := wh.worker.AuthzApi.GetAuthorizedExternalResources(requestInfo, request.Action, request.Resources)
+	result, err := wh.worker.AuthzApi.GetAuthorizedExternalResources(requestInfo, request.Action, resources)
 	response := AuthorizeResourcesResponse{
 		ResourcesAllowed: result,
 	}
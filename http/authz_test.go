@@ -14,8 +14,11 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
 		request *AuthorizeResourcesRequest
+		// Raw JSON request, used instead of request if passed
+		rawRequest string
 		// Expected result
 		expectedStatusCode int
+		expectedResources  []api.ExternalResource
 		expectedResponse   AuthorizeResourcesResponse
 		expectedError      api.Error
 		// Manager Results
@@ -25,7 +28,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 	}{
 		"OkCase": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []AuthorizeResource{},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusOK,
@@ -34,6 +37,58 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 			},
 			getAuthorizedExternalResourcesResult: []string{"resource1", "resource2"},
 		},
+		"OkCaseWithTags": {
+			request: &AuthorizeResourcesRequest{
+				Resources: []AuthorizeResource{
+					{
+						Urn: "urn:ews:product:instance:resource/resource1",
+					},
+					{
+						Urn: "urn:ews:product:instance:resource/resource2",
+						Tags: map[string]string{
+							"env": "prod",
+						},
+					},
+				},
+				Action: "product:DoAction",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: AuthorizeResourcesResponse{
+				ResourcesAllowed: []string{"urn:ews:product:instance:resource/resource2"},
+			},
+			expectedResources: []api.ExternalResource{
+				{
+					Urn: "urn:ews:product:instance:resource/resource1",
+				},
+				{
+					Urn: "urn:ews:product:instance:resource/resource2",
+					Tags: map[string]string{
+						"env": "prod",
+					},
+				},
+			},
+			getAuthorizedExternalResourcesResult: []string{"urn:ews:product:instance:resource/resource2"},
+		},
+		"OkCaseMixedUrnsAndObjects": {
+			rawRequest: `{"action":"product:DoAction","resources":["urn:ews:product:instance:resource/resource1",` +
+				`{"urn":"urn:ews:product:instance:resource/resource2","tags":{"env":"prod"}}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: AuthorizeResourcesResponse{
+				ResourcesAllowed: []string{"urn:ews:product:instance:resource/resource1"},
+			},
+			expectedResources: []api.ExternalResource{
+				{
+					Urn: "urn:ews:product:instance:resource/resource1",
+				},
+				{
+					Urn: "urn:ews:product:instance:resource/resource2",
+					Tags: map[string]string{
+						"env": "prod",
+					},
+				},
+			},
+			getAuthorizedExternalResourcesResult: []string{"urn:ews:product:instance:resource/resource1"},
+		},
 		"ErrorCaseMalformedRequest": {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
@@ -43,7 +98,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseInvalidParameter": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []AuthorizeResource{},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -58,7 +113,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseUnauthorizedError": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []AuthorizeResource{},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusForbidden,
@@ -73,7 +128,7 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 		},
 		"ErrorCaseUnknownApiError": {
 			request: &AuthorizeResourcesRequest{
-				Resources: []string{},
+				Resources: []AuthorizeResource{},
 				Action:    api.USER_ACTION_GET_USER,
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -97,6 +152,9 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 			assert.Nil(t, err, "Error in test case %v", n)
 			body = bytes.NewBuffer(jsonObject)
 		}
+		if test.rawRequest != "" {
+			body = bytes.NewBufferString(test.rawRequest)
+		}
 		if body == nil {
 			body = bytes.NewBuffer([]byte{})
 		}
@@ -116,6 +174,9 @@ func TestWorkerHandler_HandleGetAuthorizedExternalResources(t *testing.T) {
 			assert.Nil(t, err, "Error in test case %v", n)
 			// Check result
 			assert.Equal(t, test.expectedResponse, authorizeResourcesResponse, "Error in test case %v", n)
+			if test.expectedResources != nil {
+				assert.Equal(t, test.expectedResources, testApi.ArgsIn[GetAuthorizedExternalResourcesMethod][2], "Error in test case %v", n)
+			}
 		case http.StatusInternalServerError: // Empty message so continue
 			continue
 		default:
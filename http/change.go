@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// Max time that a request waits for new changes
+	MAX_CHANGES_WAIT = 60 * time.Second
+
+	// Interval between reads of the change feed while a request is waiting
+	CHANGES_POLL_INTERVAL = 1 * time.Second
+
+	// Content type requested by Server-Sent Events clients
+	EVENT_STREAM_CONTENT_TYPE = "text/event-stream"
+)
+
+// RESPONSES
+
+type ListChangesResponse struct {
+	Changes []api.Change `json:"changes,omitempty"`
+	Cursor  string       `json:"cursor"`
+}
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleListChanges(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	var wait time.Duration
+	if apiErr == nil {
+		wait, apiErr = getChangesWait(r)
+	}
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Resume from last event received if client is reconnecting
+	cursor := r.URL.Query().Get("since")
+	if cursor == "" {
+		cursor = r.Header.Get("Last-Event-ID")
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), EVENT_STREAM_CONTENT_TYPE) {
+		wh.streamChanges(w, r, requestInfo, cursor, filterData.Limit)
+		return
+	}
+
+	// Call change API to wait for changes
+	changes, nextCursor, err := wh.waitChanges(r, requestInfo, cursor, filterData.Limit, wait)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	response := &ListChangesResponse{
+		Changes: changes,
+		Cursor:  nextCursor,
+	}
+
+	wh.processHttpResponse(r, w, requestInfo, response, nil, http.StatusOK)
+}
+
+// PRIVATE HELPER METHODS
+
+// waitChanges reads the change feed until there are changes, wait time expires, client goes away
+// or server shuts down
+func (wh *WorkerHandler) waitChanges(r *http.Request, requestInfo api.RequestInfo, cursor string, limit int, wait time.Duration) ([]api.Change, string, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		changes, nextCursor, err := wh.worker.ChangeApi.ListChanges(requestInfo, cursor, limit)
+		if err != nil || len(changes) > 0 || !time.Now().Add(CHANGES_POLL_INTERVAL).Before(deadline) {
+			return changes, nextCursor, err
+		}
+		cursor = nextCursor
+
+		select {
+		case <-r.Context().Done():
+			return nil, cursor, nil
+		case <-stopWaiting(r):
+			return nil, cursor, nil
+		case <-time.After(CHANGES_POLL_INTERVAL):
+		}
+	}
+}
+
+// streamChanges sends changes as Server-Sent Events until client goes away or server shuts down
+func (wh *WorkerHandler) streamChanges(w http.ResponseWriter, r *http.Request, requestInfo api.RequestInfo, cursor string, limit int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		wh.processHttpResponse(r, w, requestInfo, nil, &api.Error{
+			Code:    api.UNKNOWN_API_ERROR,
+			Message: "Streaming is not supported",
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	// First read is done before writing headers, to return request errors as usual
+	changes, nextCursor, err := wh.worker.ChangeApi.ListChanges(requestInfo, cursor, limit)
+	if err != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", EVENT_STREAM_CONTENT_TYPE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		for _, change := range changes {
+			b, err := json.Marshal(change)
+			if err != nil {
+				api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, &api.Error{
+					Code:    api.UNKNOWN_API_ERROR,
+					Message: err.Error(),
+				})
+				return
+			}
+			fmt.Fprintf(w, "id: %v\nevent: %v\ndata: %s\n\n", change.Cursor(), change.Type, b)
+		}
+		if len(changes) > 0 {
+			flusher.Flush()
+		}
+		cursor = nextCursor
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-stopWaiting(r):
+			return
+		case <-time.After(CHANGES_POLL_INTERVAL):
+		}
+
+		changes, nextCursor, err = wh.worker.ChangeApi.ListChanges(requestInfo, cursor, limit)
+		if err != nil {
+			// Headers are already sent, so error is logged and stream is closed
+			api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, err.(*api.Error))
+			return
+		}
+	}
+}
+
+// getChangesWait retrieves the optional time that a request waits for new changes
+func getChangesWait(r *http.Request) (time.Duration, *api.Error) {
+	wt := r.URL.Query().Get("wait")
+	if len(wt) == 0 {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(wt)
+	if err != nil || d < 0 || d > MAX_CHANGES_WAIT {
+		return 0, &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: fmt.Sprintf("Invalid parameter: wait %v, max wait allowed: %v", wt, MAX_CHANGES_WAIT),
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleListChanges(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		since       string
+		lastEventID string
+		limit       string
+		wait        string
+		// Expected result
+		expectedStatusCode int
+		expectedCursor     string
+		expectedLimit      int
+		expectedResponse   ListChangesResponse
+		expectedError      api.Error
+		// Manager Results
+		listChangesResult []api.Change
+		listChangesCursor string
+		// Manager Errors
+		listChangesErr error
+	}{
+		"OKCase": {
+			since:              "10",
+			limit:              "2",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "10",
+			expectedLimit:      2,
+			listChangesResult: []api.Change{
+				{Seq: 11, Type: api.EVENT_USER_CREATED, ResourceID: "USER1", Urn: "urn1"},
+				{Seq: 12, Type: api.EVENT_USER_DELETED, ResourceID: "USER1", Urn: "urn1"},
+			},
+			listChangesCursor: "12",
+			expectedResponse: ListChangesResponse{
+				Changes: []api.Change{
+					{Seq: 11, Type: api.EVENT_USER_CREATED, ResourceID: "USER1", Urn: "urn1"},
+					{Seq: 12, Type: api.EVENT_USER_DELETED, ResourceID: "USER1", Urn: "urn1"},
+				},
+				Cursor: "12",
+			},
+		},
+		"OKCaseLastEventID": {
+			lastEventID:        "7",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "7",
+			listChangesCursor:  "7",
+			expectedResponse: ListChangesResponse{
+				Cursor: "7",
+			},
+		},
+		"OKCaseWaitWithoutChanges": {
+			since:              "7",
+			wait:               "500ms",
+			expectedStatusCode: http.StatusOK,
+			expectedCursor:     "7",
+			listChangesCursor:  "7",
+			expectedResponse: ListChangesResponse{
+				Cursor: "7",
+			},
+		},
+		"ErrorCaseInvalidWait": {
+			wait:               "2h",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: wait 2h, max wait allowed: 1m0s",
+			},
+		},
+		"ErrorCaseInvalidLimit": {
+			limit:              "-1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Limit -1",
+			},
+		},
+		"ErrorCaseInvalidCursor": {
+			since:              "abc",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCursor:     "abc",
+			listChangesErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor abc",
+			},
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: cursor abc",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			expectedStatusCode: http.StatusInternalServerError,
+			listChangesErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[ListChangesMethod][1] = nil
+		testApi.ArgsIn[ListChangesMethod][2] = nil
+		testApi.ArgsOut[ListChangesMethod][0] = test.listChangesResult
+		testApi.ArgsOut[ListChangesMethod][1] = test.listChangesCursor
+		testApi.ArgsOut[ListChangesMethod][2] = test.listChangesErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+CHANGES_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		if test.since != "" {
+			q.Add("since", test.since)
+		}
+		if test.limit != "" {
+			q.Add("Limit", test.limit)
+		}
+		if test.wait != "" {
+			q.Add("wait", test.wait)
+		}
+		req.URL.RawQuery = q.Encode()
+		if test.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", test.lastEventID)
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Check received parameters
+			assert.Equal(t, test.expectedCursor, testApi.ArgsIn[ListChangesMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedLimit, testApi.ArgsIn[ListChangesMethod][2], "Error in test case %v", n)
+			listChangesResponse := ListChangesResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listChangesResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listChangesResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListChangesEventStream(t *testing.T) {
+	testApi.ArgsOut[ListChangesMethod][0] = []api.Change{
+		{Seq: 11, Type: api.EVENT_GROUP_MEMBER_ADDED, ResourceID: "GROUP1", RelatedID: "USER1", Urn: "urn1"},
+	}
+	testApi.ArgsOut[ListChangesMethod][1] = "11"
+	testApi.ArgsOut[ListChangesMethod][2] = nil
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+CHANGES_URL+"?since=10", nil)
+	assert.Nil(t, err, "Error in test")
+	req.Header.Set("Accept", EVENT_STREAM_CONTENT_TYPE)
+
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err, "Error in test")
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Error in test")
+	assert.Equal(t, EVENT_STREAM_CONTENT_TYPE, res.Header.Get("Content-Type"), "Error in test")
+
+	// Read first event
+	reader := bufio.NewReader(res.Body)
+	lines := make([]string, 3)
+	for i := range lines {
+		lines[i], err = reader.ReadString('\n')
+		assert.Nil(t, err, "Error in test")
+	}
+	assert.Equal(t, "id: 11\n", lines[0], "Error in test")
+	assert.Equal(t, "event: "+api.EVENT_GROUP_MEMBER_ADDED+"\n", lines[1], "Error in test")
+
+	change := api.Change{}
+	err = json.Unmarshal([]byte(lines[2][len("data: "):]), &change)
+	assert.Nil(t, err, "Error in test")
+	assert.Equal(t, testApi.ArgsOut[ListChangesMethod][0].([]api.Change)[0], change, "Error in test")
+}
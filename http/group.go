@@ -10,13 +10,25 @@ import (
 // REQUESTS
 
 type CreateGroupRequest struct {
-	Name string `json:"name,omitempty"`
-	Path string `json:"path,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 type UpdateGroupRequest struct {
-	Name string `json:"name,omitempty"`
-	Path string `json:"path,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type BulkMembersRequest struct {
+	Users  []string `json:"users,omitempty"`
+	Atomic bool     `json:"atomic,omitempty"`
+}
+
+type BulkPoliciesRequest struct {
+	Policies []string `json:"policies,omitempty"`
+	Atomic   bool     `json:"atomic,omitempty"`
 }
 
 // RESPONSES
@@ -26,6 +38,8 @@ type ListGroupsResponse struct {
 	Limit  int      `json:"limit"`
 	Offset int      `json:"offset"`
 	Total  int      `json:"total"`
+	Next   string   `json:"next,omitempty"`
+	Prev   string   `json:"prev,omitempty"`
 }
 
 type ListAllGroupsResponse struct {
@@ -33,6 +47,21 @@ type ListAllGroupsResponse struct {
 	Limit  int                 `json:"limit"`
 	Offset int                 `json:"offset"`
 	Total  int                 `json:"total"`
+	Next   string              `json:"next,omitempty"`
+	Prev   string              `json:"prev,omitempty"`
+}
+
+type ListGroupsExpandedResponse struct {
+	Groups []api.Group `json:"groups,omitempty"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	Total  int         `json:"total"`
+	Next   string      `json:"next,omitempty"`
+	Prev   string      `json:"prev,omitempty"`
+}
+
+type BulkOperationResponse struct {
+	Results []api.BulkResult `json:"results"`
 }
 
 type ListMembersResponse struct {
@@ -40,6 +69,8 @@ type ListMembersResponse struct {
 	Limit   int                `json:"limit"`
 	Offset  int                `json:"offset"`
 	Total   int                `json:"total"`
+	Next    string             `json:"next,omitempty"`
+	Prev    string             `json:"prev,omitempty"`
 }
 
 type ListAttachedGroupPoliciesResponse struct {
@@ -47,6 +78,8 @@ type ListAttachedGroupPoliciesResponse struct {
 	Limit            int                 `json:"limit"`
 	Offset           int                 `json:"offset"`
 	Total            int                 `json:"total"`
+	Next             string              `json:"next,omitempty"`
+	Prev             string              `json:"prev,omitempty"`
 }
 
 // HANDLERS
@@ -60,7 +93,7 @@ func (wh *WorkerHandler) HandleAddGroup(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	// Call group API to create group
-	response, err := wh.worker.GroupApi.AddGroup(requestInfo, filterData.Org, request.Name, request.Path)
+	response, err := wh.worker.GroupApi.AddGroup(requestInfo, filterData.Org, request.Name, request.Path, request.Attributes)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
 }
 
@@ -83,6 +116,20 @@ func (wh *WorkerHandler) HandleListGroups(w http.ResponseWriter, r *http.Request
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call group API to list full groups
+		result, total, err := wh.worker.GroupApi.ListGroupsExpanded(requestInfo, filterData)
+		response := &ListGroupsExpandedResponse{
+			Groups: result,
+			Offset: filterData.Offset,
+			Limit:  filterData.Limit,
+			Total:  total,
+			Next:   filterData.NextCursor,
+			Prev:   filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 	// Call group API to retrieve group list
 	result, total, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
 	groups := []string{}
@@ -95,6 +142,8 @@ func (wh *WorkerHandler) HandleListGroups(w http.ResponseWriter, r *http.Request
 		Offset: filterData.Offset,
 		Limit:  filterData.Limit,
 		Total:  total,
+		Next:   filterData.NextCursor,
+		Prev:   filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -106,6 +155,20 @@ func (wh *WorkerHandler) HandleListAllGroups(w http.ResponseWriter, r *http.Requ
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call group API to list full groups
+		result, total, err := wh.worker.GroupApi.ListGroupsExpanded(requestInfo, filterData)
+		response := &ListGroupsExpandedResponse{
+			Groups: result,
+			Offset: filterData.Offset,
+			Limit:  filterData.Limit,
+			Total:  total,
+			Next:   filterData.NextCursor,
+			Prev:   filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 	// Call group API to get all groups
 	result, total, err := wh.worker.GroupApi.ListGroups(requestInfo, filterData)
 	// Create response
@@ -114,6 +177,8 @@ func (wh *WorkerHandler) HandleListAllGroups(w http.ResponseWriter, r *http.Requ
 		Offset: filterData.Offset,
 		Limit:  filterData.Limit,
 		Total:  total,
+		Next:   filterData.NextCursor,
+		Prev:   filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -127,7 +192,8 @@ func (wh *WorkerHandler) HandleUpdateGroup(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	// Call group API to update group
-	response, err := wh.worker.GroupApi.UpdateGroup(requestInfo, filterData.Org, filterData.GroupName, request.Name, request.Path)
+	response, err := wh.worker.GroupApi.UpdateGroup(requestInfo, filterData.Org, filterData.GroupName, request.Name, request.Path,
+		request.Attributes)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -167,6 +233,38 @@ func (wh *WorkerHandler) HandleRemoveMember(w http.ResponseWriter, r *http.Reque
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleAddMembers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &BulkMembersRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to add members to group
+	results, err := wh.worker.GroupApi.AddMembers(requestInfo, request.Users, filterData.GroupName, filterData.Org, request.Atomic)
+	response := &BulkOperationResponse{
+		Results: results,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleRemoveMembers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &BulkMembersRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to remove members from group
+	results, err := wh.worker.GroupApi.RemoveMembers(requestInfo, request.Users, filterData.GroupName, filterData.Org, request.Atomic)
+	response := &BulkOperationResponse{
+		Results: results,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleListMembers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -181,6 +279,8 @@ func (wh *WorkerHandler) HandleListMembers(w http.ResponseWriter, r *http.Reques
 		Offset:  filterData.Offset,
 		Limit:   filterData.Limit,
 		Total:   total,
+		Next:    filterData.NextCursor,
+		Prev:    filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -209,6 +309,38 @@ func (wh *WorkerHandler) HandleDetachPolicyToGroup(w http.ResponseWriter, r *htt
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
+func (wh *WorkerHandler) HandleAttachPoliciesToGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &BulkPoliciesRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to attach policies to group
+	results, err := wh.worker.GroupApi.AttachPoliciesToGroup(requestInfo, filterData.Org, filterData.GroupName, request.Policies, request.Atomic)
+	response := &BulkOperationResponse{
+		Results: results,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleDetachPoliciesToGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &BulkPoliciesRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	// Call group API to detach policies from group
+	results, err := wh.worker.GroupApi.DetachPoliciesToGroup(requestInfo, filterData.Org, filterData.GroupName, request.Policies, request.Atomic)
+	response := &BulkOperationResponse{
+		Results: results,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
 func (wh *WorkerHandler) HandleListAttachedGroupPolicies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// Process request
 	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
@@ -224,6 +356,8 @@ func (wh *WorkerHandler) HandleListAttachedGroupPolicies(w http.ResponseWriter,
 		Offset:           filterData.Offset,
 		Limit:            filterData.Limit,
 		Total:            total,
+		Next:             filterData.NextCursor,
+		Prev:             filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
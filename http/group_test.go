@@ -614,6 +614,155 @@ func TestWorkerHandler_HandleListAllGroups(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleListGroupsExpanded(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		expand       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListGroupsExpandedResponse
+		expectedError      api.Error
+		// Manager Results
+		listGroupResult  []api.Group
+		totalGroupResult int
+		// Manager Errors
+		listGroupErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListGroupsExpandedResponse{
+				Groups: []api.Group{
+					{
+						ID:   "GroupID",
+						Name: "group1",
+						Org:  "org1",
+						Path: "path",
+						Urn:  "urn",
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			listGroupResult: []api.Group{
+				{
+					ID:   "GroupID",
+					Name: "group1",
+					Org:  "org1",
+					Path: "path",
+					Urn:  "urn",
+				},
+			},
+			totalGroupResult: 1,
+		},
+		"ErrorCaseInvalidExpand": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+			},
+			expand:             "maybe",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Expand maybe",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listGroupErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listGroupErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListGroupsExpandedMethod][0] = test.listGroupResult
+		testApi.ArgsOut[ListGroupsExpandedMethod][1] = test.totalGroupResult
+		testApi.ArgsOut[ListGroupsExpandedMethod][2] = test.listGroupErr
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups", test.filter.Org), nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+		if test.expand != "" {
+			q := req.URL.Query()
+			q.Set("Expand", test.expand)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListGroupsExpandedMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listResponse := ListGroupsExpandedResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleUpdateGroup(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -1234,116 +1383,135 @@ func TestWorkerHandler_HandleRemoveMember(t *testing.T) {
 	}
 }
 
-func TestWorkerHandler_HandleListMembers(t *testing.T) {
-	now := time.Now().UTC()
+func TestWorkerHandler_HandleAddMembers(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
-		filter       *api.Filter
-		ignoreArgsIn bool
+		org       string
+		groupName string
+		request   *BulkMembersRequest
 		// Expected result
 		expectedStatusCode int
-		expectedResponse   ListMembersResponse
+		expectedResponse   BulkOperationResponse
 		expectedError      api.Error
 		// Manager Results
-		getListMembersResult []api.GroupMembers
-		totalGroupsResult    int
+		addMembersResult []api.BulkResult
 		// Manager Errors
-		getListMembersErr error
+		addMembersErr error
 	}{
 		"OkCase": {
-			filter: &api.Filter{
-				Org:       "org1",
-				GroupName: "group1",
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users:  []string{"user1", "user2"},
+				Atomic: true,
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: ListMembersResponse{
-				Members: []api.GroupMembers{
-					{
-						User:     "member1",
-						CreateAt: now,
-					},
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "user1"},
+					{ID: "user2"},
+				},
+			},
+			addMembersResult: []api.BulkResult{
+				{ID: "user1"},
+				{ID: "user2"},
+			},
+		},
+		"OkCaseItemError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1", "user2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "user1"},
 					{
-						User:     "member2",
-						CreateAt: now,
+						ID: "user2",
+						Error: &api.Error{
+							Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+							Message: "User is already a member of group",
+						},
 					},
 				},
-				Offset: 0,
-				Limit:  0,
-				Total:  2,
 			},
-			getListMembersResult: []api.GroupMembers{
-				{
-					User:     "member1",
-					CreateAt: now,
-				},
+			addMembersResult: []api.BulkResult{
+				{ID: "user1"},
 				{
-					User:     "member2",
-					CreateAt: now,
+					ID: "user2",
+					Error: &api.Error{
+						Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+						Message: "User is already a member of group",
+					},
 				},
 			},
-			totalGroupsResult: 2,
 		},
-		"ErrorCaseInvalidFilterParams": {
-			filter: &api.Filter{
-				PathPrefix: "",
-				Offset:     -1,
-			},
-			ignoreArgsIn:       true,
+		"ErrorCaseMalformedRequest": {
+			org:                "org1",
+			groupName:          "group1",
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
 				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: "Invalid parameter: Offset -1",
+				Message: "EOF",
 			},
 		},
 		"ErrorCaseGroupNotFoundErr": {
-			filter: &api.Filter{
-				Org:       "org1",
-				GroupName: "group1",
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedError: api.Error{
 				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
 				Message: "Group Not Found",
 			},
-			getListMembersErr: &api.Error{
+			addMembersErr: &api.Error{
 				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
 				Message: "Group Not Found",
 			},
 		},
-		"ErrorCaseInvalidParameterErr": {
-			filter: &api.Filter{
-				Org:       "org1",
-				GroupName: "group1",
-			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedError: api.Error{
-				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: "Invalid Parameter",
-			},
-			getListMembersErr: &api.Error{
-				Code:    api.INVALID_PARAMETER_ERROR,
-				Message: "Invalid Parameter",
-			},
-		},
 		"ErrorCaseUnauthorizedError": {
-			filter: &api.Filter{
-				Org:       "org1",
-				GroupName: "group1",
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
 			},
 			expectedStatusCode: http.StatusForbidden,
 			expectedError: api.Error{
 				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
 				Message: "Unauthorized",
 			},
-			getListMembersErr: &api.Error{
+			addMembersErr: &api.Error{
 				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
 				Message: "Unauthorized",
 			},
 		},
+		"ErrorCaseInvalidParameterErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+			addMembersErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+		},
 		"ErrorCaseUnknownApiError": {
-			filter:             testFilter,
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
+			},
 			expectedStatusCode: http.StatusInternalServerError,
-			getListMembersErr: &api.Error{
+			addMembersErr: &api.Error{
 				Code:    api.UNKNOWN_API_ERROR,
 				Message: "Error",
 			},
@@ -1354,27 +1522,32 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 
 	for n, test := range testcases {
 
-		testApi.ArgsOut[ListMembersMethod][0] = test.getListMembersResult
-		testApi.ArgsOut[ListMembersMethod][1] = test.totalGroupsResult
-		testApi.ArgsOut[ListMembersMethod][2] = test.getListMembersErr
+		testApi.ArgsOut[AddMembersMethod][0] = test.addMembersResult
+		testApi.ArgsOut[AddMembersMethod][1] = test.addMembersErr
 
-		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.filter.Org, test.filter.GroupName)
-		req, err := http.NewRequest(http.MethodGet, url, nil)
-		assert.Nil(t, err, "Error in test case %v", n)
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
 
-		addQueryParams(test.filter, req)
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
 
 		res, err := client.Do(req)
 		assert.Nil(t, err, "Error in test case %v", n)
 
-		if !test.ignoreArgsIn {
-			// Check received parameter
-			filterData, ok := testApi.ArgsIn[ListMembersMethod][1].(*api.Filter)
-			if ok {
-				// Check result
-				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
-			}
-
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.request.Users, testApi.ArgsIn[AddMembersMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[AddMembersMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.org, testApi.ArgsIn[AddMembersMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Atomic, testApi.ArgsIn[AddMembersMethod][4], "Error in test case %v", n)
 		}
 
 		// check status code
@@ -1382,11 +1555,11 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 
 		switch res.StatusCode {
 		case http.StatusOK:
-			getGroupMembersResponse := ListMembersResponse{}
-			err = json.NewDecoder(res.Body).Decode(&getGroupMembersResponse)
+			response := BulkOperationResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
 			assert.Nil(t, err, "Error in test case %v", n)
 			// Check result
-			assert.Equal(t, test.expectedResponse, getGroupMembersResponse, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
 		case http.StatusInternalServerError: // Empty message so continue
 			continue
 		default:
@@ -1399,30 +1572,333 @@ func TestWorkerHandler_HandleListMembers(t *testing.T) {
 	}
 }
 
-func TestWorkerHandler_HandleAttachPolicyToGroup(t *testing.T) {
+func TestWorkerHandler_HandleRemoveMembers(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
-		org          string
-		groupName    string
-		policyName   string
-		offset       string
-		ignoreArgsIn bool
+		org       string
+		groupName string
+		request   *BulkMembersRequest
 		// Expected result
 		expectedStatusCode int
+		expectedResponse   BulkOperationResponse
 		expectedError      api.Error
+		// Manager Results
+		removeMembersResult []api.BulkResult
 		// Manager Errors
-		attachGroupPolicyErr error
+		removeMembersErr error
 	}{
 		"OkCase": {
-			org:                "org1",
-			groupName:          "group1",
-			policyName:         "policy1",
-			expectedStatusCode: http.StatusNoContent,
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1", "user2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "user1"},
+					{
+						ID: "user2",
+						Error: &api.Error{
+							Code:    api.USER_IS_NOT_A_MEMBER_OF_GROUP,
+							Message: "User is not a member of group",
+						},
+					},
+				},
+			},
+			removeMembersResult: []api.BulkResult{
+				{ID: "user1"},
+				{
+					ID: "user2",
+					Error: &api.Error{
+						Code:    api.USER_IS_NOT_A_MEMBER_OF_GROUP,
+						Message: "User is not a member of group",
+					},
+				},
+			},
 		},
-		"ErrorCaseInvalidRequest": {
+		"ErrorCaseMalformedRequest": {
 			org:                "org1",
 			groupName:          "group1",
-			policyName:         "policy1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			removeMembersErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkMembersRequest{
+				Users: []string{"user1"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			removeMembersErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveMembersMethod][0] = test.removeMembersResult
+		testApi.ArgsOut[RemoveMembersMethod][1] = test.removeMembersErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodDelete, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.request.Users, testApi.ArgsIn[RemoveMembersMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[RemoveMembersMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.org, testApi.ArgsIn[RemoveMembersMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Atomic, testApi.ArgsIn[RemoveMembersMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := BulkOperationResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListMembers(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListMembersResponse
+		expectedError      api.Error
+		// Manager Results
+		getListMembersResult []api.GroupMembers
+		totalGroupsResult    int
+		// Manager Errors
+		getListMembersErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListMembersResponse{
+				Members: []api.GroupMembers{
+					{
+						User:     "member1",
+						CreateAt: now,
+					},
+					{
+						User:     "member2",
+						CreateAt: now,
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  2,
+			},
+			getListMembersResult: []api.GroupMembers{
+				{
+					User:     "member1",
+					CreateAt: now,
+				},
+				{
+					User:     "member2",
+					CreateAt: now,
+				},
+			},
+			totalGroupsResult: 2,
+		},
+		"ErrorCaseInvalidFilterParams": {
+			filter: &api.Filter{
+				PathPrefix: "",
+				Offset:     -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			getListMembersErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseInvalidParameterErr": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+			getListMembersErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				Org:       "org1",
+				GroupName: "group1",
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			getListMembersErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter:             testFilter,
+			expectedStatusCode: http.StatusInternalServerError,
+			getListMembersErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListMembersMethod][0] = test.getListMembersResult
+		testApi.ArgsOut[ListMembersMethod][1] = test.totalGroupsResult
+		testApi.ArgsOut[ListMembersMethod][2] = test.getListMembersErr
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/users", test.filter.Org, test.filter.GroupName)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListMembersMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			getGroupMembersResponse := ListMembersResponse{}
+			err = json.NewDecoder(res.Body).Decode(&getGroupMembersResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, getGroupMembersResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleAttachPolicyToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org          string
+		groupName    string
+		policyName   string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		attachGroupPolicyErr error
+	}{
+		"OkCase": {
+			org:                "org1",
+			groupName:          "group1",
+			policyName:         "policy1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseInvalidRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			policyName:         "policy1",
 			offset:             "-1",
 			ignoreArgsIn:       true,
 			expectedStatusCode: http.StatusBadRequest,
@@ -1721,6 +2197,333 @@ func TestWorkerHandler_HandleDetachPolicyToGroup(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleAttachPoliciesToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org       string
+		groupName string
+		request   *BulkPoliciesRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   BulkOperationResponse
+		expectedError      api.Error
+		// Manager Results
+		attachPoliciesResult []api.BulkResult
+		// Manager Errors
+		attachPoliciesErr error
+	}{
+		"OkCase": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1", "policy2"},
+				Atomic:   true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "policy1"},
+					{ID: "policy2"},
+				},
+			},
+			attachPoliciesResult: []api.BulkResult{
+				{ID: "policy1"},
+				{ID: "policy2"},
+			},
+		},
+		"OkCaseItemError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1", "policy2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "policy1"},
+					{
+						ID: "policy2",
+						Error: &api.Error{
+							Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+							Message: "Policy is already attached to group",
+						},
+					},
+				},
+			},
+			attachPoliciesResult: []api.BulkResult{
+				{ID: "policy1"},
+				{
+					ID: "policy2",
+					Error: &api.Error{
+						Code:    api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP,
+						Message: "Policy is already attached to group",
+					},
+				},
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			attachPoliciesErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			attachPoliciesErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseInvalidParameterErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+			attachPoliciesErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid Parameter",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			attachPoliciesErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AttachPoliciesToGroupMethod][0] = test.attachPoliciesResult
+		testApi.ArgsOut[AttachPoliciesToGroupMethod][1] = test.attachPoliciesErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/policies", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[AttachPoliciesToGroupMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[AttachPoliciesToGroupMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Policies, testApi.ArgsIn[AttachPoliciesToGroupMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Atomic, testApi.ArgsIn[AttachPoliciesToGroupMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := BulkOperationResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleDetachPoliciesToGroup(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		org       string
+		groupName string
+		request   *BulkPoliciesRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   BulkOperationResponse
+		expectedError      api.Error
+		// Manager Results
+		detachPoliciesResult []api.BulkResult
+		// Manager Errors
+		detachPoliciesErr error
+	}{
+		"OkCase": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1", "policy2"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: BulkOperationResponse{
+				Results: []api.BulkResult{
+					{ID: "policy1"},
+					{
+						ID: "policy2",
+						Error: &api.Error{
+							Code:    api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
+							Message: "Policy is not attached to group",
+						},
+					},
+				},
+			},
+			detachPoliciesResult: []api.BulkResult{
+				{ID: "policy1"},
+				{
+					ID: "policy2",
+					Error: &api.Error{
+						Code:    api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
+						Message: "Policy is not attached to group",
+					},
+				},
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			org:                "org1",
+			groupName:          "group1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseGroupNotFoundErr": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+			detachPoliciesErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group Not Found",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			org:       "org1",
+			groupName: "group1",
+			request: &BulkPoliciesRequest{
+				Policies: []string{"policy1"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			detachPoliciesErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[DetachPoliciesToGroupMethod][0] = test.detachPoliciesResult
+		testApi.ArgsOut[DetachPoliciesToGroupMethod][1] = test.detachPoliciesErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/groups/%v/policies", test.org, test.groupName)
+		req, err := http.NewRequest(http.MethodDelete, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.org, testApi.ArgsIn[DetachPoliciesToGroupMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.groupName, testApi.ArgsIn[DetachPoliciesToGroupMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Policies, testApi.ArgsIn[DetachPoliciesToGroupMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Atomic, testApi.ArgsIn[DetachPoliciesToGroupMethod][4], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := BulkOperationResponse{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleListAttachedGroupPolicies(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
@@ -6,9 +6,12 @@ import (
 
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/foulkon"
+	"github.com/Tecsisa/foulkon/metrics"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -19,7 +22,10 @@ const (
 	POLICY_NAME         = "policyname"
 	PROXY_RESOURCE_NAME = "proxyresourcename"
 	AUTH_PROVIDER_NAME  = "authprovidername"
+	WEBHOOK_NAME        = "webhookname"
+	API_KEY_ID          = "apikeyid"
 	ORG_NAME            = "orgname"
+	SCIM_ID             = "scimid"
 
 	// URI Path param prefix
 	URI_PATH_PREFIX = "/:"
@@ -36,6 +42,14 @@ const (
 	USER_ID_URL        = USER_ROOT_URL + URI_PATH_PREFIX + USER_ID
 	USER_ID_GROUPS_URL = USER_ID_URL + "/groups"
 
+	USER_ID_SUSPEND_URL    = USER_ID_URL + "/suspend"
+	USER_ID_REACTIVATE_URL = USER_ID_URL + "/reactivate"
+
+	// User API key API urls
+	USER_ID_API_KEYS_URL           = USER_ID_URL + "/api-keys"
+	USER_ID_API_KEYS_ID_URL        = USER_ID_API_KEYS_URL + URI_PATH_PREFIX + API_KEY_ID
+	USER_ID_API_KEYS_ID_ROTATE_URL = USER_ID_API_KEYS_ID_URL + "/rotate"
+
 	// Group organization API urls
 	GROUP_ORG_ROOT_URL       = API_VERSION_1 + ORG_ROOT + "/groups"
 	GROUP_ID_URL             = GROUP_ORG_ROOT_URL + URI_PATH_PREFIX + GROUP_NAME
@@ -63,6 +77,24 @@ const (
 	OIDC_AUTH_ROOT_URL = API_VERSION_1 + ADMIN_ROOT + "/auth/oidc/providers"
 	OIDC_AUTH_ID_URL   = OIDC_AUTH_ROOT_URL + URI_PATH_PREFIX + AUTH_PROVIDER_NAME
 
+	// Admin lockout URL, with failed admin logins
+	ADMIN_LOCKOUTS_URL = API_VERSION_1 + ADMIN_ROOT + "/auth/lockouts"
+
+	// Admin webhook API URLs
+	WEBHOOK_ROOT_URL          = API_VERSION_1 + ADMIN_ROOT + "/webhooks"
+	WEBHOOK_ID_URL            = WEBHOOK_ROOT_URL + URI_PATH_PREFIX + WEBHOOK_NAME
+	WEBHOOK_ID_DELIVERIES_URL = WEBHOOK_ID_URL + "/deliveries"
+
+	// Change feed URL
+	CHANGES_URL = API_VERSION_1 + "/changes"
+
+	// SCIM 2.0 provisioning URLs
+	SCIM_ROOT         = "/scim/v2"
+	SCIM_USERS_URL    = SCIM_ROOT + "/Users"
+	SCIM_USER_ID_URL  = SCIM_USERS_URL + URI_PATH_PREFIX + SCIM_ID
+	SCIM_GROUPS_URL   = SCIM_ROOT + "/Groups"
+	SCIM_GROUP_ID_URL = SCIM_GROUPS_URL + URI_PATH_PREFIX + SCIM_ID
+
 	// Foulkon configuration URL
 	ABOUT = "/about"
 )
@@ -72,6 +104,8 @@ const (
 type ProxyHandler struct {
 	proxy  *foulkon.Proxy
 	client *http.Client
+	// Client that calls worker to check authorization
+	workerClient *http.Client
 }
 
 // WORKER
@@ -108,32 +142,7 @@ func (wh *WorkerHandler) processHttpResponse(r *http.Request, w http.ResponseWri
 		// Transform to API errors
 		apiError := err.(*api.Error)
 		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
-		var statusCode int
-		switch apiError.Code {
-		case api.USER_ALREADY_EXIST, api.GROUP_ALREADY_EXIST,
-			api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
-			api.PROXY_RESOURCE_ALREADY_EXIST,
-			api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP, api.POLICY_ALREADY_EXIST,
-			api.PROXY_RESOURCES_ROUTES_CONFLICT,
-			api.AUTH_OIDC_PROVIDER_ALREADY_EXIST:
-			// A conflict occurs
-			statusCode = http.StatusConflict
-		case api.UNAUTHORIZED_RESOURCES_ERROR:
-			// No authorization success
-			statusCode = http.StatusForbidden
-		case api.USER_BY_EXTERNAL_ID_NOT_FOUND, api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
-			api.USER_IS_NOT_A_MEMBER_OF_GROUP, api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
-			api.POLICY_BY_ORG_AND_NAME_NOT_FOUND, api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND,
-			api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND:
-			// Resource or relation not found
-			statusCode = http.StatusNotFound
-		case api.INVALID_PARAMETER_ERROR, api.REGEX_NO_MATCH:
-			// Unexpected input in validation parameters
-			statusCode = http.StatusBadRequest
-		default: // Unexpected API error
-			statusCode = http.StatusInternalServerError
-		}
-		WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, statusCode, apiError)
+		WriteHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, getStatusCode(apiError), apiError)
 		return
 	}
 
@@ -144,17 +153,25 @@ func (wh *WorkerHandler) processHttpResponse(r *http.Request, w http.ResponseWri
 func (wh *WorkerHandler) getRequestInfo(r *http.Request) api.RequestInfo {
 	// Retrieve request information from middleware context
 	mc := wh.worker.MiddlewareHandler.GetMiddlewareContext(r)
+	var mappedGroups []api.GroupIdentity
+	for _, group := range mc.MappedGroups {
+		if orgName := strings.SplitN(group, "/", 2); len(orgName) == 2 {
+			mappedGroups = append(mappedGroups, api.GroupIdentity{Org: orgName[0], Name: orgName[1]})
+		}
+	}
 	return api.RequestInfo{
-		Identifier: mc.UserId,
-		Admin:      mc.Admin,
-		RequestID:  mc.XRequestId,
+		Identifier:   mc.UserId,
+		Admin:        mc.Admin,
+		RequestID:    mc.XRequestId,
+		MappedGroups: mappedGroups,
+		Context:      r.Context(),
 	}
 }
 
 // WorkerHandlerRouter returns http.Handler for the APIs.
 func WorkerHandlerRouter(worker *foulkon.Worker) http.Handler {
 	// Create the muxer to handle the actual endpoints
-	router := httprouter.New()
+	router := newInstrumentedRouter()
 
 	workerHandler := WorkerHandler{worker: worker}
 
@@ -168,6 +185,18 @@ func WorkerHandlerRouter(worker *foulkon.Worker) http.Handler {
 
 	router.GET(USER_ID_GROUPS_URL, workerHandler.HandleListGroupsByUser)
 
+	router.POST(USER_ID_SUSPEND_URL, workerHandler.HandleSuspendUser)
+	router.POST(USER_ID_REACTIVATE_URL, workerHandler.HandleReactivateUser)
+
+	// API key api
+	router.GET(USER_ID_API_KEYS_URL, workerHandler.HandleListApiKeys)
+	router.POST(USER_ID_API_KEYS_URL, workerHandler.HandleAddApiKey)
+
+	router.GET(USER_ID_API_KEYS_ID_URL, workerHandler.HandleGetApiKey)
+	router.DELETE(USER_ID_API_KEYS_ID_URL, workerHandler.HandleRemoveApiKey)
+
+	router.POST(USER_ID_API_KEYS_ID_ROTATE_URL, workerHandler.HandleRotateApiKey)
+
 	// Group api
 	router.POST(GROUP_ORG_ROOT_URL, workerHandler.HandleAddGroup)
 	router.GET(GROUP_ORG_ROOT_URL, workerHandler.HandleListGroups)
@@ -177,11 +206,15 @@ func WorkerHandlerRouter(worker *foulkon.Worker) http.Handler {
 	router.PUT(GROUP_ID_URL, workerHandler.HandleUpdateGroup)
 
 	router.GET(GROUP_ID_USERS_URL, workerHandler.HandleListMembers)
+	router.POST(GROUP_ID_USERS_URL, workerHandler.HandleAddMembers)
+	router.DELETE(GROUP_ID_USERS_URL, workerHandler.HandleRemoveMembers)
 
 	router.POST(GROUP_ID_USERS_ID_URL, workerHandler.HandleAddMember)
 	router.DELETE(GROUP_ID_USERS_ID_URL, workerHandler.HandleRemoveMember)
 
 	router.GET(GROUP_ID_POLICIES_URL, workerHandler.HandleListAttachedGroupPolicies)
+	router.POST(GROUP_ID_POLICIES_URL, workerHandler.HandleAttachPoliciesToGroup)
+	router.DELETE(GROUP_ID_POLICIES_URL, workerHandler.HandleDetachPoliciesToGroup)
 
 	router.POST(GROUP_ID_POLICIES_ID_URL, workerHandler.HandleAttachPolicyToGroup)
 	router.DELETE(GROUP_ID_POLICIES_ID_URL, workerHandler.HandleDetachPolicyToGroup)
@@ -224,14 +257,61 @@ func WorkerHandlerRouter(worker *foulkon.Worker) http.Handler {
 	router.GET(OIDC_AUTH_ID_URL, workerHandler.HandleGetOidcProviderByName)
 	router.PUT(OIDC_AUTH_ID_URL, workerHandler.HandleUpdateOidcProvider)
 
+	// Admin lockout api
+	router.GET(ADMIN_LOCKOUTS_URL, workerHandler.HandleListAdminLockouts)
+
+	// Webhook api
+	router.GET(WEBHOOK_ROOT_URL, workerHandler.HandleListWebhooks)
+	router.POST(WEBHOOK_ROOT_URL, workerHandler.HandleAddWebhook)
+
+	router.DELETE(WEBHOOK_ID_URL, workerHandler.HandleRemoveWebhook)
+
+	router.GET(WEBHOOK_ID_URL, workerHandler.HandleGetWebhookByName)
+	router.PUT(WEBHOOK_ID_URL, workerHandler.HandleUpdateWebhook)
+
+	router.GET(WEBHOOK_ID_DELIVERIES_URL, workerHandler.HandleListWebhookDeliveries)
+
+	// Change feed api
+	router.GET(CHANGES_URL, workerHandler.HandleListChanges)
+
+	// SCIM provisioning api
+	router.GET(SCIM_USERS_URL, workerHandler.HandleScimListUsers)
+	router.POST(SCIM_USERS_URL, workerHandler.HandleScimAddUser)
+
+	router.DELETE(SCIM_USER_ID_URL, workerHandler.HandleScimRemoveUser)
+
+	router.GET(SCIM_USER_ID_URL, workerHandler.HandleScimGetUser)
+	router.PATCH(SCIM_USER_ID_URL, workerHandler.HandleScimUpdateUser)
+
+	// SCIM groups are only served if there is an organization for them
+	if worker.ScimOrg != "" {
+		router.GET(SCIM_GROUPS_URL, workerHandler.HandleScimListGroups)
+		router.POST(SCIM_GROUPS_URL, workerHandler.HandleScimAddGroup)
+
+		router.DELETE(SCIM_GROUP_ID_URL, workerHandler.HandleScimRemoveGroup)
+
+		router.GET(SCIM_GROUP_ID_URL, workerHandler.HandleScimGetGroup)
+		router.PATCH(SCIM_GROUP_ID_URL, workerHandler.HandleScimUpdateGroup)
+	}
+
 	// Current Foulkon configuration
 	router.GET(ABOUT, workerHandler.HandleGetCurrentConfig)
 
-	return workerHandler.worker.MiddlewareHandler.Handle(router)
+	// Probes and metrics bypass middlewares
+	handler := withMetricsEndpoint(instrumentRequests(metrics.SERVER_WORKER, router.resolveRoute,
+		workerHandler.worker.MiddlewareHandler.Handle(router.Router)))
+	return withProbeEndpoints(handler, map[string]readinessCheck{
+		"database": checkDatabase(workerHandler.worker.DB),
+	})
 }
 
 // WriteHttpResponse fill a http response with data, controlling marshalling errors
 func WriteHttpResponse(r *http.Request, w http.ResponseWriter, requestId string, userId string, statusCode int, value interface{}) {
+	writeHttpResponse(r, w, requestId, userId, statusCode, "application/json", value)
+}
+
+// writeHttpResponse fill a http response with data encoded as JSON, using contentType
+func writeHttpResponse(r *http.Request, w http.ResponseWriter, requestId string, userId string, statusCode int, contentType string, value interface{}) {
 	if value != nil {
 		b, err := json.Marshal(value)
 		if err != nil {
@@ -243,7 +323,7 @@ func WriteHttpResponse(r *http.Request, w http.ResponseWriter, requestId string,
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("Content-Type", contentType)
 		// Set status code
 		w.WriteHeader(statusCode)
 		w.Write(b)
@@ -256,6 +336,37 @@ func WriteHttpResponse(r *http.Request, w http.ResponseWriter, requestId string,
 
 // Private Helper Methods
 
+// getStatusCode returns the HTTP status code that matches an API error
+func getStatusCode(apiError *api.Error) int {
+	var statusCode int
+	switch apiError.Code {
+	case api.USER_ALREADY_EXIST, api.GROUP_ALREADY_EXIST,
+		api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+		api.PROXY_RESOURCE_ALREADY_EXIST,
+		api.POLICY_IS_ALREADY_ATTACHED_TO_GROUP, api.POLICY_ALREADY_EXIST,
+		api.PROXY_RESOURCES_ROUTES_CONFLICT,
+		api.AUTH_OIDC_PROVIDER_ALREADY_EXIST, api.WEBHOOK_ALREADY_EXIST:
+		// A conflict occurs
+		statusCode = http.StatusConflict
+	case api.UNAUTHORIZED_RESOURCES_ERROR:
+		// No authorization success
+		statusCode = http.StatusForbidden
+	case api.USER_BY_EXTERNAL_ID_NOT_FOUND, api.GROUP_BY_ORG_AND_NAME_NOT_FOUND, api.GROUP_BY_ID_NOT_FOUND,
+		api.USER_IS_NOT_A_MEMBER_OF_GROUP, api.POLICY_IS_NOT_ATTACHED_TO_GROUP,
+		api.POLICY_BY_ORG_AND_NAME_NOT_FOUND, api.PROXY_RESOURCE_BY_ORG_AND_NAME_NOT_FOUND,
+		api.AUTH_OIDC_PROVIDER_BY_NAME_NOT_FOUND, api.WEBHOOK_BY_NAME_NOT_FOUND,
+		api.API_KEY_BY_ID_NOT_FOUND:
+		// Resource or relation not found
+		statusCode = http.StatusNotFound
+	case api.INVALID_PARAMETER_ERROR, api.REGEX_NO_MATCH:
+		// Unexpected input in validation parameters
+		statusCode = http.StatusBadRequest
+	default: // Unexpected API error
+		statusCode = http.StatusInternalServerError
+	}
+	return statusCode
+}
+
 func getFilterData(r *http.Request, ps httprouter.Params) (*api.Filter, error) {
 	var err error
 	// Retrieve Offset
@@ -289,6 +400,50 @@ func getFilterData(r *http.Request, ps httprouter.Params) (*api.Filter, error) {
 		org = r.URL.Query().Get("Org")
 	}
 
+	// Retrieve Attributes, in key:value format
+	var attributes map[string]string
+	for _, attr := range r.URL.Query()["Attribute"] {
+		keyValue := strings.SplitN(attr, ":", 2)
+		if len(keyValue) != 2 || len(keyValue[0]) == 0 {
+			return nil, &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: Attribute %v", attr),
+			}
+		}
+		if attributes == nil {
+			attributes = map[string]string{}
+		}
+		attributes[keyValue[0]] = keyValue[1]
+	}
+
+	// Retrieve date ranges, in RFC 3339 format
+	dates := map[string]time.Time{}
+	for _, param := range []string{"CreatedAfter", "CreatedBefore", "UpdatedAfter", "UpdatedBefore"} {
+		date := time.Time{}
+		if value := r.URL.Query().Get(param); len(value) != 0 {
+			date, err = time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, &api.Error{
+					Code:    api.INVALID_PARAMETER_ERROR,
+					Message: fmt.Sprintf("Invalid parameter: %v %v", param, value),
+				}
+			}
+		}
+		dates[param] = date
+	}
+
+	// Retrieve Expand, to list full objects
+	var expand bool
+	if exp := r.URL.Query().Get("Expand"); len(exp) != 0 {
+		expand, err = strconv.ParseBool(exp)
+		if err != nil {
+			return nil, &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: fmt.Sprintf("Invalid parameter: Expand %v", exp),
+			}
+		}
+	}
+
 	return &api.Filter{
 		PathPrefix:        r.URL.Query().Get("PathPrefix"),
 		Org:               org,
@@ -297,8 +452,22 @@ func getFilterData(r *http.Request, ps httprouter.Params) (*api.Filter, error) {
 		GroupName:         ps.ByName(GROUP_NAME),
 		ProxyResourceName: ps.ByName(PROXY_RESOURCE_NAME),
 		AuthProviderName:  ps.ByName(AUTH_PROVIDER_NAME),
+		WebhookName:       ps.ByName(WEBHOOK_NAME),
+		ApiKeyID:          ps.ByName(API_KEY_ID),
+		Attributes:        attributes,
+		State:             r.URL.Query().Get("State"),
+		NamePrefix:        r.URL.Query().Get("NamePrefix"),
+		NameContains:      r.URL.Query().Get("NameContains"),
+		CreatedAfter:      dates["CreatedAfter"],
+		CreatedBefore:     dates["CreatedBefore"],
+		UpdatedAfter:      dates["UpdatedAfter"],
+		UpdatedBefore:     dates["UpdatedBefore"],
+		Action:            r.URL.Query().Get("Action"),
+		Resource:          r.URL.Query().Get("Resource"),
 		Offset:            offset,
 		Limit:             limit,
+		Cursor:            r.URL.Query().Get("Cursor"),
 		OrderBy:           r.URL.Query().Get("OrderBy"),
+		Expand:            expand,
 	}, nil
 }
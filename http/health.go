@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// Probe URLs, served by worker and proxy without authentication
+	HEALTH_URL = "/healthz"
+	READY_URL  = "/readyz"
+
+	// Max time for each readiness check
+	READINESS_CHECK_TIMEOUT = 2 * time.Second
+
+	// Probe status
+	PROBE_STATUS_OK          = "ok"
+	PROBE_STATUS_UNAVAILABLE = "unavailable"
+)
+
+// RESPONSES
+
+type ProbeResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// readinessCheck returns an error if a dependency isn't ready
+type readinessCheck func() error
+
+// withProbeEndpoints serves health and readiness URLs before calling handler. Health URL only
+// shows that server is running, readiness URL runs all checks by name.
+func withProbeEndpoints(handler http.Handler, checks map[string]readinessCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		switch r.URL.Path {
+		case HEALTH_URL:
+			WriteHttpResponse(r, w, "", "", http.StatusOK, &ProbeResponse{Status: PROBE_STATUS_OK})
+		case READY_URL:
+			statusCode, response := runReadinessChecks(checks)
+			WriteHttpResponse(r, w, "", "", statusCode, response)
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+// runReadinessChecks runs checks in order by name, returning unavailable status if any of them fails
+func runReadinessChecks(checks map[string]readinessCheck) (int, *ProbeResponse) {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	statusCode := http.StatusOK
+	response := &ProbeResponse{
+		Status: PROBE_STATUS_OK,
+		Checks: make(map[string]string, len(checks)),
+	}
+	for _, name := range names {
+		if err := checks[name](); err != nil {
+			statusCode = http.StatusServiceUnavailable
+			response.Status = PROBE_STATUS_UNAVAILABLE
+			response.Checks[name] = err.Error()
+		} else {
+			response.Checks[name] = PROBE_STATUS_OK
+		}
+	}
+	return statusCode, response
+}
+
+// checkDatabase returns a check of database connectivity
+func checkDatabase(db *sql.DB) readinessCheck {
+	return func() error {
+		if db == nil {
+			return errors.New("Database not connected")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), READINESS_CHECK_TIMEOUT)
+		defer cancel()
+		return db.PingContext(ctx)
+	}
+}
+
+// checkHealth returns a check of health URL of a Foulkon server in host, called with transport or the default one if it's nil
+func checkHealth(host string, transport http.RoundTripper) readinessCheck {
+	client := &http.Client{Transport: transport, Timeout: READINESS_CHECK_TIMEOUT}
+	return func() error {
+		res, err := client.Get(host + HEALTH_URL)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("Unexpected status code %v", res.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_Probes(t *testing.T) {
+	testcases := map[string]struct {
+		url string
+
+		expectedStatusCode int
+		expectedResponse   ProbeResponse
+	}{
+		"OKCaseHealth": {
+			url:                HEALTH_URL,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ProbeResponse{
+				Status: PROBE_STATUS_OK,
+			},
+		},
+		"ErrorCaseReadyWithoutDatabase": {
+			url:                READY_URL,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse: ProbeResponse{
+				Status: PROBE_STATUS_UNAVAILABLE,
+				Checks: map[string]string{
+					"database": "Database not connected",
+				},
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		// Authenticator would reject request
+		authConnector.statusCode = http.StatusUnauthorized
+
+		res, err := http.Get(server.URL + test.url)
+		assert.Nil(t, err, "Error in test case %v", n)
+		response := ProbeResponse{}
+		err = json.NewDecoder(res.Body).Decode(&response)
+		res.Body.Close()
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+	}
+	authConnector.statusCode = 0
+}
+
+func TestWithProbeEndpoints(t *testing.T) {
+	okCheck := func() error { return nil }
+	failCheck := func() error { return errors.New("Unreachable") }
+
+	testcases := map[string]struct {
+		method string
+		path   string
+		checks map[string]readinessCheck
+
+		expectedNextCalled bool
+		expectedStatusCode int
+		expectedResponse   *ProbeResponse
+	}{
+		"OKCaseHealth": {
+			method: http.MethodGet,
+			path:   HEALTH_URL,
+			checks: map[string]readinessCheck{
+				"database": failCheck,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ProbeResponse{
+				Status: PROBE_STATUS_OK,
+			},
+		},
+		"OKCaseReady": {
+			method: http.MethodGet,
+			path:   READY_URL,
+			checks: map[string]readinessCheck{
+				"database": okCheck,
+				"worker":   okCheck,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ProbeResponse{
+				Status: PROBE_STATUS_OK,
+				Checks: map[string]string{
+					"database": PROBE_STATUS_OK,
+					"worker":   PROBE_STATUS_OK,
+				},
+			},
+		},
+		"OKCaseOtherPath": {
+			method:             http.MethodGet,
+			path:               "/other",
+			expectedNextCalled: true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"OKCaseOtherMethod": {
+			method:             http.MethodPost,
+			path:               READY_URL,
+			expectedNextCalled: true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"ErrorCaseNotReady": {
+			method: http.MethodGet,
+			path:   READY_URL,
+			checks: map[string]readinessCheck{
+				"database": okCheck,
+				"worker":   failCheck,
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse: &ProbeResponse{
+				Status: PROBE_STATUS_UNAVAILABLE,
+				Checks: map[string]string{
+					"database": PROBE_STATUS_OK,
+					"worker":   "Unreachable",
+				},
+			},
+		},
+	}
+
+	for n, test := range testcases {
+		nextCalled := false
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nextCalled = true
+			w.WriteHeader(http.StatusNotFound)
+		})
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(test.method, test.path, nil)
+
+		withProbeEndpoints(next, test.checks).ServeHTTP(w, r)
+
+		// Check response
+		assert.Equal(t, test.expectedNextCalled, nextCalled, "Error in test case %v", n)
+		assert.Equal(t, test.expectedStatusCode, w.Code, "Error in test case %v", n)
+		if test.expectedResponse != nil {
+			response := &ProbeResponse{}
+			err := json.NewDecoder(w.Body).Decode(response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	testcases := map[string]struct {
+		statusCode int
+		closed     bool
+
+		expectedError bool
+	}{
+		"OKCase": {
+			statusCode: http.StatusOK,
+		},
+		"ErrorCaseUnexpectedStatusCode": {
+			statusCode:    http.StatusInternalServerError,
+			expectedError: true,
+		},
+		"ErrorCaseUnreachable": {
+			closed:        true,
+			expectedError: true,
+		},
+	}
+
+	for n, test := range testcases {
+		host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, HEALTH_URL, r.URL.Path, "Error in test case %v", n)
+			w.WriteHeader(test.statusCode)
+		}))
+		if test.closed {
+			host.Close()
+		}
+
+		err := checkHealth(host.URL, nil)()
+
+		// Check result
+		assert.Equal(t, test.expectedError, err != nil, "Error in test case %v", n)
+		host.Close()
+	}
+}
+
+func TestProxyServer_checkResources(t *testing.T) {
+	testcases := map[string]struct {
+		changesCursor string
+
+		expectedError bool
+	}{
+		"OKCaseLoaded": {
+			changesCursor: "5",
+		},
+		"ErrorCaseNotLoaded": {
+			changesCursor: "",
+			expectedError: true,
+		},
+	}
+
+	for n, test := range testcases {
+		ps := &ProxyServer{changesCursor: test.changesCursor}
+
+		err := ps.checkResources()
+
+		// Check result
+		assert.Equal(t, test.expectedError, err != nil, "Error in test case %v", n)
+	}
+}
@@ -25,30 +25,40 @@ const (
 	AddUserMethod             = "AddUser"
 	GetUserByExternalIdMethod = "GetUserByExternalId"
 	ListUsersMethod           = "ListUsers"
+	ListUsersExpandedMethod   = "ListUsersExpanded"
 	UpdateUserMethod          = "UpdateUser"
 	RemoveUserMethod          = "RemoveUser"
 	ListGroupsByUserMethod    = "ListGroupsByUser"
+	SuspendUserMethod         = "SuspendUser"
+	ReactivateUserMethod      = "ReactivateUser"
 
 	// GROUP API METHODS
 	AddGroupMethod                  = "AddGroup"
 	GetGroupByNameMethod            = "GetGroupByName"
+	GetGroupByIDMethod              = "GetGroupByID"
 	ListGroupsMethod                = "ListGroups"
+	ListGroupsExpandedMethod        = "ListGroupsExpanded"
 	UpdateGroupMethod               = "UpdateGroup"
 	RemoveGroupMethod               = "RemoveGroup"
 	AddMemberMethod                 = "AddMember"
+	AddMembersMethod                = "AddMembers"
 	RemoveMemberMethod              = "RemoveMember"
+	RemoveMembersMethod             = "RemoveMembers"
 	ListMembersMethod               = "ListMembers"
 	AttachPolicyToGroupMethod       = "AttachPolicyToGroup"
+	AttachPoliciesToGroupMethod     = "AttachPoliciesToGroup"
 	DetachPolicyToGroupMethod       = "DetachPolicyToGroup"
+	DetachPoliciesToGroupMethod     = "DetachPoliciesToGroup"
 	ListAttachedGroupPoliciesMethod = "ListAttachedGroupPolicies"
 
 	// POLICY API METHODS
-	AddPolicyMethod          = "AddPolicy"
-	GetPolicyByNameMethod    = "GetPolicyByName"
-	ListPoliciesMethod       = "ListPolicies"
-	UpdatePolicyMethod       = "UpdatePolicy"
-	RemovePolicyMethod       = "RemovePolicy"
-	ListAttachedGroupsMethod = "ListAttachedGroups"
+	AddPolicyMethod            = "AddPolicy"
+	GetPolicyByNameMethod      = "GetPolicyByName"
+	ListPoliciesMethod         = "ListPolicies"
+	ListPoliciesExpandedMethod = "ListPoliciesExpanded"
+	UpdatePolicyMethod         = "UpdatePolicy"
+	RemovePolicyMethod         = "RemovePolicy"
+	ListAttachedGroupsMethod   = "ListAttachedGroups"
 
 	// AUTHZ API
 	GetAuthorizedUsersMethod             = "GetAuthorizedUsers"
@@ -58,12 +68,13 @@ const (
 	GetAuthorizedProxyResources          = "GetAuthorizedProxyResources"
 
 	// PROXY API
-	AddProxyResourceMethod       = "AddProxyResource"
-	GetProxyResourceByNameMethod = "GetProxyResourceByName"
-	GetProxyResourcesMethod      = "GetProxyResources"
-	UpdateProxyResourceMethod    = "UpdateProxyResource"
-	RemoveProxyResourceMethod    = "RemoveProxyResource"
-	ListProxyResourcesMethod     = "ListProxyResources"
+	AddProxyResourceMethod           = "AddProxyResource"
+	GetProxyResourceByNameMethod     = "GetProxyResourceByName"
+	GetProxyResourcesMethod          = "GetProxyResources"
+	UpdateProxyResourceMethod        = "UpdateProxyResource"
+	RemoveProxyResourceMethod        = "RemoveProxyResource"
+	ListProxyResourcesMethod         = "ListProxyResources"
+	ListProxyResourcesExpandedMethod = "ListProxyResourcesExpanded"
 
 	// AUTH OIDC PROVIDER API
 	AddOidcProviderMethod       = "AddOidcProvider"
@@ -71,6 +82,29 @@ const (
 	ListOidcProvidersMethod     = "ListOidcProviders"
 	UpdateOidcProviderMethod    = "UpdateOidcProvider"
 	RemoveOidcProviderMethod    = "RemoveOidcProvider"
+	ProvisionOidcUserMethod     = "ProvisionOidcUser"
+	SyncOidcUserGroupsMethod    = "SyncOidcUserGroups"
+
+	// WEBHOOK API
+	AddWebhookMethod            = "AddWebhook"
+	GetWebhookByNameMethod      = "GetWebhookByName"
+	ListWebhooksMethod          = "ListWebhooks"
+	UpdateWebhookMethod         = "UpdateWebhook"
+	RemoveWebhookMethod         = "RemoveWebhook"
+	ListWebhookDeliveriesMethod = "ListWebhookDeliveries"
+
+	// API KEY API
+	AddApiKeyMethod          = "AddApiKey"
+	GetApiKeyMethod          = "GetApiKey"
+	ListApiKeysMethod        = "ListApiKeys"
+	RotateApiKeyMethod       = "RotateApiKey"
+	RemoveApiKeyMethod       = "RemoveApiKey"
+	AuthenticateApiKeyMethod = "AuthenticateApiKey"
+
+	// CHANGE API
+	ListChangesMethod         = "ListChanges"
+	GetChangesMethod          = "GetChanges"
+	GetLastChangeCursorMethod = "GetLastChangeCursor"
 )
 
 // Test server used to test handlers
@@ -79,6 +113,7 @@ var proxy *httptest.Server
 var testApi *TestAPI
 var hook *logrusTest.Hook
 var authConnector *TestConnector
+var adminLockout *auth.AdminLockout
 var testFilter = &api.Filter{
 	PathPrefix: "",
 	Org:        "",
@@ -139,14 +174,17 @@ func TestMain(m *testing.M) {
 		userID: "userID",
 	}
 
-	adminUser := "admin"
-	adminPassword := "admin"
+	admins := auth.NewAdminAccounts()
+	if err := admins.AddPassword("admin", "admin"); err != nil {
+		panic(err)
+	}
+	adminLockout = auth.NewAdminLockout(5, time.Minute, time.Hour)
 
 	// Middlewares
 	middlewares := make(map[string]middleware.Middleware)
 
 	// Authenticator middleware
-	authenticatorMiddleware := auth.NewAuthenticatorMiddleware(authConnector, adminUser, adminPassword)
+	authenticatorMiddleware := auth.NewAuthenticatorMiddleware(authConnector, admins, adminLockout)
 	middlewares[middleware.AUTHENTICATOR_MIDDLEWARE] = authenticatorMiddleware
 
 	// X-Request-Id middleware
@@ -166,21 +204,23 @@ func TestMain(m *testing.M) {
 		MaxOpenConns:  0,
 		ConnTtl:       0,
 		AuthType:      "oidc",
-		OidcProviders: []api.OidcProvider{
-			{
-				ID:        "test1",
-				Name:      "test",
-				Path:      "/path/",
-				Urn:       api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "test"),
-				IssuerURL: "https://test.com",
-				CreateAt:  time.Now().UTC().Truncate(time.Hour),
-				UpdateAt:  time.Now().UTC().Truncate(time.Hour),
-				OidcClients: []api.OidcClient{
-					{
-						Name: "client1",
+		OidcProviders: func() ([]api.OidcProvider, error) {
+			return []api.OidcProvider{
+				{
+					ID:        "test1",
+					Name:      "test",
+					Path:      "/path/",
+					Urn:       api.CreateUrn("", api.RESOURCE_AUTH_OIDC_PROVIDER, "/path/", "test"),
+					IssuerURL: "https://test.com",
+					CreateAt:  time.Now().UTC().Truncate(time.Hour),
+					UpdateAt:  time.Now().UTC().Truncate(time.Hour),
+					OidcClients: []api.OidcClient{
+						{
+							Name: "client1",
+						},
 					},
 				},
-			},
+			}, nil
 		},
 		Version: "test",
 	}
@@ -194,6 +234,11 @@ func TestMain(m *testing.M) {
 		AuthzApi:          testApi,
 		ProxyApi:          testApi,
 		AuthOidcAPI:       testApi,
+		WebhookApi:        testApi,
+		ApiKeyApi:         testApi,
+		ChangeApi:         testApi,
+		AdminLockout:      adminLockout,
+		ScimOrg:           "scim",
 		Config:            config,
 	}
 
@@ -221,28 +266,38 @@ func makeTestApi() *TestAPI {
 		SpecialFuncs: make(map[string]interface{}),
 	}
 
-	testApi.ArgsIn[AddUserMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[AddUserMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[GetUserByExternalIdMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[ListUsersMethod] = make([]interface{}, 2)
-	testApi.ArgsIn[UpdateUserMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[ListUsersExpandedMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateUserMethod] = make([]interface{}, 4)
 	testApi.ArgsIn[RemoveUserMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[ListGroupsByUserMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[SuspendUserMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[ReactivateUserMethod] = make([]interface{}, 2)
 
-	testApi.ArgsIn[AddGroupMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[AddGroupMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[GetGroupByNameMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[GetGroupByIDMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[ListGroupsMethod] = make([]interface{}, 2)
-	testApi.ArgsIn[UpdateGroupMethod] = make([]interface{}, 5)
+	testApi.ArgsIn[ListGroupsExpandedMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateGroupMethod] = make([]interface{}, 6)
 	testApi.ArgsIn[RemoveGroupMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[AddMemberMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[AddMembersMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[RemoveMemberMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[RemoveMembersMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[ListMembersMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[AttachPolicyToGroupMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[AttachPoliciesToGroupMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[DetachPolicyToGroupMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[DetachPoliciesToGroupMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[ListAttachedGroupPoliciesMethod] = make([]interface{}, 2)
 
 	testApi.ArgsIn[AddPolicyMethod] = make([]interface{}, 5)
 	testApi.ArgsIn[GetPolicyByNameMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListPoliciesMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListPoliciesExpandedMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[UpdatePolicyMethod] = make([]interface{}, 6)
 	testApi.ArgsIn[RemovePolicyMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListAttachedGroupsMethod] = make([]interface{}, 2)
@@ -259,35 +314,66 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsIn[UpdateProxyResourceMethod] = make([]interface{}, 6)
 	testApi.ArgsIn[RemoveProxyResourceMethod] = make([]interface{}, 3)
 	testApi.ArgsIn[ListProxyResourcesMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[ListProxyResourcesExpandedMethod] = make([]interface{}, 2)
 
-	testApi.ArgsIn[AddOidcProviderMethod] = make([]interface{}, 5)
+	testApi.ArgsIn[AddOidcProviderMethod] = make([]interface{}, 8)
 	testApi.ArgsIn[GetOidcProviderByNameMethod] = make([]interface{}, 2)
 	testApi.ArgsIn[ListOidcProvidersMethod] = make([]interface{}, 2)
-	testApi.ArgsIn[UpdateOidcProviderMethod] = make([]interface{}, 6)
+	testApi.ArgsIn[UpdateOidcProviderMethod] = make([]interface{}, 9)
 	testApi.ArgsIn[RemoveOidcProviderMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ProvisionOidcUserMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[SyncOidcUserGroupsMethod] = make([]interface{}, 4)
+
+	testApi.ArgsIn[AddWebhookMethod] = make([]interface{}, 6)
+	testApi.ArgsIn[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListWebhooksMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[UpdateWebhookMethod] = make([]interface{}, 7)
+	testApi.ArgsIn[RemoveWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[ListWebhookDeliveriesMethod] = make([]interface{}, 2)
+
+	testApi.ArgsIn[AddApiKeyMethod] = make([]interface{}, 4)
+	testApi.ArgsIn[GetApiKeyMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[ListApiKeysMethod] = make([]interface{}, 2)
+	testApi.ArgsIn[RotateApiKeyMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[RemoveApiKeyMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[AuthenticateApiKeyMethod] = make([]interface{}, 2)
+
+	testApi.ArgsIn[ListChangesMethod] = make([]interface{}, 3)
+	testApi.ArgsIn[GetChangesMethod] = make([]interface{}, 1)
+	testApi.ArgsIn[GetLastChangeCursorMethod] = make([]interface{}, 0)
 
 	testApi.ArgsOut[AddUserMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetUserByExternalIdMethod] = make([]interface{}, 2)
-	testApi.ArgsOut[ListUsersMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListUsersMethod] = make([]interface{}, 5)
+	testApi.ArgsOut[ListUsersExpandedMethod] = make([]interface{}, 3)
 	testApi.ArgsOut[UpdateUserMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveUserMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[ListGroupsByUserMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[SuspendUserMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ReactivateUserMethod] = make([]interface{}, 2)
 
 	testApi.ArgsOut[AddGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetGroupByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[GetGroupByIDMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListGroupsMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListGroupsExpandedMethod] = make([]interface{}, 3)
 	testApi.ArgsOut[UpdateGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveGroupMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[AddMemberMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[AddMembersMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveMemberMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[RemoveMembersMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListMembersMethod] = make([]interface{}, 3)
 	testApi.ArgsOut[AttachPolicyToGroupMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[AttachPoliciesToGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[DetachPolicyToGroupMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[DetachPoliciesToGroupMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListAttachedGroupPoliciesMethod] = make([]interface{}, 3)
 
 	testApi.ArgsOut[AddPolicyMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetPolicyByNameMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListPoliciesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListPoliciesExpandedMethod] = make([]interface{}, 3)
 	testApi.ArgsOut[UpdatePolicyMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemovePolicyMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[ListAttachedGroupsMethod] = make([]interface{}, 3)
@@ -304,22 +390,44 @@ func makeTestApi() *TestAPI {
 	testApi.ArgsOut[UpdateProxyResourceMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveProxyResourceMethod] = make([]interface{}, 1)
 	testApi.ArgsOut[ListProxyResourcesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[ListProxyResourcesExpandedMethod] = make([]interface{}, 3)
 
 	testApi.ArgsOut[AddOidcProviderMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[GetOidcProviderByNameMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[ListOidcProvidersMethod] = make([]interface{}, 3)
 	testApi.ArgsOut[UpdateOidcProviderMethod] = make([]interface{}, 2)
 	testApi.ArgsOut[RemoveOidcProviderMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[ProvisionOidcUserMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[SyncOidcUserGroupsMethod] = make([]interface{}, 1)
+
+	testApi.ArgsOut[AddWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[GetWebhookByNameMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ListWebhooksMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[UpdateWebhookMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[RemoveWebhookMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[ListWebhookDeliveriesMethod] = make([]interface{}, 3)
+
+	testApi.ArgsOut[AddApiKeyMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[GetApiKeyMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[ListApiKeysMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[RotateApiKeyMethod] = make([]interface{}, 2)
+	testApi.ArgsOut[RemoveApiKeyMethod] = make([]interface{}, 1)
+	testApi.ArgsOut[AuthenticateApiKeyMethod] = make([]interface{}, 2)
+
+	testApi.ArgsOut[ListChangesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[GetChangesMethod] = make([]interface{}, 3)
+	testApi.ArgsOut[GetLastChangeCursorMethod] = make([]interface{}, 2)
 
 	return testApi
 }
 
 // USER API
 
-func (t TestAPI) AddUser(authenticatedUser api.RequestInfo, externalID string, path string) (*api.User, error) {
+func (t TestAPI) AddUser(authenticatedUser api.RequestInfo, externalID string, path string, attributes map[string]string) (*api.User, error) {
 	t.ArgsIn[AddUserMethod][0] = authenticatedUser
 	t.ArgsIn[AddUserMethod][1] = externalID
 	t.ArgsIn[AddUserMethod][2] = path
+	t.ArgsIn[AddUserMethod][3] = attributes
 	var user *api.User
 	if t.ArgsOut[AddUserMethod][0] != nil {
 		user = t.ArgsOut[AddUserMethod][0].(*api.User)
@@ -360,13 +468,39 @@ func (t TestAPI) ListUsers(authenticatedUser api.RequestInfo, filter *api.Filter
 	if t.ArgsOut[ListUsersMethod][2] != nil {
 		err = t.ArgsOut[ListUsersMethod][2].(error)
 	}
+	if t.ArgsOut[ListUsersMethod][3] != nil {
+		filter.NextCursor = t.ArgsOut[ListUsersMethod][3].(string)
+	}
+	if t.ArgsOut[ListUsersMethod][4] != nil {
+		filter.PrevCursor = t.ArgsOut[ListUsersMethod][4].(string)
+	}
 	return externalIDs, total, err
 }
 
-func (t TestAPI) UpdateUser(authenticatedUser api.RequestInfo, externalID string, newPath string) (*api.User, error) {
+func (t TestAPI) ListUsersExpanded(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.User, int, error) {
+	t.ArgsIn[ListUsersExpandedMethod][0] = authenticatedUser
+	t.ArgsIn[ListUsersExpandedMethod][1] = filter
+
+	var users []api.User
+	var total int
+	if t.ArgsOut[ListUsersExpandedMethod][1] != nil {
+		total = t.ArgsOut[ListUsersExpandedMethod][1].(int)
+	}
+	if t.ArgsOut[ListUsersExpandedMethod][0] != nil {
+		users = t.ArgsOut[ListUsersExpandedMethod][0].([]api.User)
+	}
+	var err error
+	if t.ArgsOut[ListUsersExpandedMethod][2] != nil {
+		err = t.ArgsOut[ListUsersExpandedMethod][2].(error)
+	}
+	return users, total, err
+}
+
+func (t TestAPI) UpdateUser(authenticatedUser api.RequestInfo, externalID string, newPath string, newAttributes map[string]string) (*api.User, error) {
 	t.ArgsIn[UpdateUserMethod][0] = authenticatedUser
 	t.ArgsIn[UpdateUserMethod][1] = externalID
 	t.ArgsIn[UpdateUserMethod][2] = newPath
+	t.ArgsIn[UpdateUserMethod][3] = newAttributes
 	var user *api.User
 	if t.ArgsOut[UpdateUserMethod][0] != nil {
 		user = t.ArgsOut[UpdateUserMethod][0].(*api.User)
@@ -378,6 +512,35 @@ func (t TestAPI) UpdateUser(authenticatedUser api.RequestInfo, externalID string
 	return user, err
 }
 
+func (t TestAPI) SuspendUser(authenticatedUser api.RequestInfo, externalID string, reason string) (*api.User, error) {
+	t.ArgsIn[SuspendUserMethod][0] = authenticatedUser
+	t.ArgsIn[SuspendUserMethod][1] = externalID
+	t.ArgsIn[SuspendUserMethod][2] = reason
+	var user *api.User
+	if t.ArgsOut[SuspendUserMethod][0] != nil {
+		user = t.ArgsOut[SuspendUserMethod][0].(*api.User)
+	}
+	var err error
+	if t.ArgsOut[SuspendUserMethod][1] != nil {
+		err = t.ArgsOut[SuspendUserMethod][1].(error)
+	}
+	return user, err
+}
+
+func (t TestAPI) ReactivateUser(authenticatedUser api.RequestInfo, externalID string) (*api.User, error) {
+	t.ArgsIn[ReactivateUserMethod][0] = authenticatedUser
+	t.ArgsIn[ReactivateUserMethod][1] = externalID
+	var user *api.User
+	if t.ArgsOut[ReactivateUserMethod][0] != nil {
+		user = t.ArgsOut[ReactivateUserMethod][0].(*api.User)
+	}
+	var err error
+	if t.ArgsOut[ReactivateUserMethod][1] != nil {
+		err = t.ArgsOut[ReactivateUserMethod][1].(error)
+	}
+	return user, err
+}
+
 func (t TestAPI) RemoveUser(authenticatedUser api.RequestInfo, id string) error {
 	t.ArgsIn[RemoveUserMethod][0] = authenticatedUser
 	t.ArgsIn[RemoveUserMethod][1] = id
@@ -409,11 +572,12 @@ func (t TestAPI) ListGroupsByUser(authenticatedUser api.RequestInfo, filter *api
 
 // GROUP API
 
-func (t TestAPI) AddGroup(authenticatedUser api.RequestInfo, org string, name string, path string) (*api.Group, error) {
+func (t TestAPI) AddGroup(authenticatedUser api.RequestInfo, org string, name string, path string, attributes map[string]string) (*api.Group, error) {
 	t.ArgsIn[AddGroupMethod][0] = authenticatedUser
 	t.ArgsIn[AddGroupMethod][1] = org
 	t.ArgsIn[AddGroupMethod][2] = name
 	t.ArgsIn[AddGroupMethod][3] = path
+	t.ArgsIn[AddGroupMethod][4] = attributes
 	var group *api.Group
 	if t.ArgsOut[AddGroupMethod][0] != nil {
 		group = t.ArgsOut[AddGroupMethod][0].(*api.Group)
@@ -440,6 +604,20 @@ func (t TestAPI) GetGroupByName(authenticatedUser api.RequestInfo, org string, n
 	return group, err
 }
 
+func (t TestAPI) GetGroupByID(authenticatedUser api.RequestInfo, id string) (*api.Group, error) {
+	t.ArgsIn[GetGroupByIDMethod][0] = authenticatedUser
+	t.ArgsIn[GetGroupByIDMethod][1] = id
+	var group *api.Group
+	if t.ArgsOut[GetGroupByIDMethod][0] != nil {
+		group = t.ArgsOut[GetGroupByIDMethod][0].(*api.Group)
+	}
+	var err error
+	if t.ArgsOut[GetGroupByIDMethod][1] != nil {
+		err = t.ArgsOut[GetGroupByIDMethod][1].(error)
+	}
+	return group, err
+}
+
 func (t TestAPI) ListGroups(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupIdentity, int, error) {
 	t.ArgsIn[ListGroupsMethod][0] = authenticatedUser
 	t.ArgsIn[ListGroupsMethod][1] = filter
@@ -459,12 +637,33 @@ func (t TestAPI) ListGroups(authenticatedUser api.RequestInfo, filter *api.Filte
 	return groups, total, err
 }
 
-func (t TestAPI) UpdateGroup(authenticatedUser api.RequestInfo, org string, groupName string, newName string, newPath string) (*api.Group, error) {
+func (t TestAPI) ListGroupsExpanded(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.Group, int, error) {
+	t.ArgsIn[ListGroupsExpandedMethod][0] = authenticatedUser
+	t.ArgsIn[ListGroupsExpandedMethod][1] = filter
+
+	var groups []api.Group
+	var total int
+	if t.ArgsOut[ListGroupsExpandedMethod][1] != nil {
+		total = t.ArgsOut[ListGroupsExpandedMethod][1].(int)
+	}
+	if t.ArgsOut[ListGroupsExpandedMethod][0] != nil {
+		groups = t.ArgsOut[ListGroupsExpandedMethod][0].([]api.Group)
+	}
+	var err error
+	if t.ArgsOut[ListGroupsExpandedMethod][2] != nil {
+		err = t.ArgsOut[ListGroupsExpandedMethod][2].(error)
+	}
+	return groups, total, err
+}
+
+func (t TestAPI) UpdateGroup(authenticatedUser api.RequestInfo, org string, groupName string, newName string, newPath string,
+	newAttributes map[string]string) (*api.Group, error) {
 	t.ArgsIn[UpdateGroupMethod][0] = authenticatedUser
 	t.ArgsIn[UpdateGroupMethod][1] = org
 	t.ArgsIn[UpdateGroupMethod][2] = groupName
 	t.ArgsIn[UpdateGroupMethod][3] = newName
 	t.ArgsIn[UpdateGroupMethod][4] = newPath
+	t.ArgsIn[UpdateGroupMethod][5] = newAttributes
 	var group *api.Group
 	if t.ArgsOut[UpdateGroupMethod][0] != nil {
 		group = t.ArgsOut[UpdateGroupMethod][0].(*api.Group)
@@ -511,6 +710,40 @@ func (t TestAPI) RemoveMember(authenticatedUser api.RequestInfo, userID string,
 	return err
 }
 
+func (t TestAPI) AddMembers(authenticatedUser api.RequestInfo, userIDs []string, groupName string, org string, atomic bool) ([]api.BulkResult, error) {
+	t.ArgsIn[AddMembersMethod][0] = authenticatedUser
+	t.ArgsIn[AddMembersMethod][1] = userIDs
+	t.ArgsIn[AddMembersMethod][2] = groupName
+	t.ArgsIn[AddMembersMethod][3] = org
+	t.ArgsIn[AddMembersMethod][4] = atomic
+	var results []api.BulkResult
+	if t.ArgsOut[AddMembersMethod][0] != nil {
+		results = t.ArgsOut[AddMembersMethod][0].([]api.BulkResult)
+	}
+	var err error
+	if t.ArgsOut[AddMembersMethod][1] != nil {
+		err = t.ArgsOut[AddMembersMethod][1].(error)
+	}
+	return results, err
+}
+
+func (t TestAPI) RemoveMembers(authenticatedUser api.RequestInfo, userIDs []string, groupName string, org string, atomic bool) ([]api.BulkResult, error) {
+	t.ArgsIn[RemoveMembersMethod][0] = authenticatedUser
+	t.ArgsIn[RemoveMembersMethod][1] = userIDs
+	t.ArgsIn[RemoveMembersMethod][2] = groupName
+	t.ArgsIn[RemoveMembersMethod][3] = org
+	t.ArgsIn[RemoveMembersMethod][4] = atomic
+	var results []api.BulkResult
+	if t.ArgsOut[RemoveMembersMethod][0] != nil {
+		results = t.ArgsOut[RemoveMembersMethod][0].([]api.BulkResult)
+	}
+	var err error
+	if t.ArgsOut[RemoveMembersMethod][1] != nil {
+		err = t.ArgsOut[RemoveMembersMethod][1].(error)
+	}
+	return results, err
+}
+
 func (t TestAPI) ListMembers(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupMembers, int, error) {
 	t.ArgsIn[ListMembersMethod][0] = authenticatedUser
 	t.ArgsIn[ListMembersMethod][1] = filter
@@ -554,6 +787,40 @@ func (t TestAPI) DetachPolicyToGroup(authenticatedUser api.RequestInfo, org stri
 	return err
 }
 
+func (t TestAPI) AttachPoliciesToGroup(authenticatedUser api.RequestInfo, org string, groupName string, policyNames []string, atomic bool) ([]api.BulkResult, error) {
+	t.ArgsIn[AttachPoliciesToGroupMethod][0] = authenticatedUser
+	t.ArgsIn[AttachPoliciesToGroupMethod][1] = org
+	t.ArgsIn[AttachPoliciesToGroupMethod][2] = groupName
+	t.ArgsIn[AttachPoliciesToGroupMethod][3] = policyNames
+	t.ArgsIn[AttachPoliciesToGroupMethod][4] = atomic
+	var results []api.BulkResult
+	if t.ArgsOut[AttachPoliciesToGroupMethod][0] != nil {
+		results = t.ArgsOut[AttachPoliciesToGroupMethod][0].([]api.BulkResult)
+	}
+	var err error
+	if t.ArgsOut[AttachPoliciesToGroupMethod][1] != nil {
+		err = t.ArgsOut[AttachPoliciesToGroupMethod][1].(error)
+	}
+	return results, err
+}
+
+func (t TestAPI) DetachPoliciesToGroup(authenticatedUser api.RequestInfo, org string, groupName string, policyNames []string, atomic bool) ([]api.BulkResult, error) {
+	t.ArgsIn[DetachPoliciesToGroupMethod][0] = authenticatedUser
+	t.ArgsIn[DetachPoliciesToGroupMethod][1] = org
+	t.ArgsIn[DetachPoliciesToGroupMethod][2] = groupName
+	t.ArgsIn[DetachPoliciesToGroupMethod][3] = policyNames
+	t.ArgsIn[DetachPoliciesToGroupMethod][4] = atomic
+	var results []api.BulkResult
+	if t.ArgsOut[DetachPoliciesToGroupMethod][0] != nil {
+		results = t.ArgsOut[DetachPoliciesToGroupMethod][0].([]api.BulkResult)
+	}
+	var err error
+	if t.ArgsOut[DetachPoliciesToGroupMethod][1] != nil {
+		err = t.ArgsOut[DetachPoliciesToGroupMethod][1].(error)
+	}
+	return results, err
+}
+
 func (t TestAPI) ListAttachedGroupPolicies(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.GroupPolicies, int, error) {
 	t.ArgsIn[ListAttachedGroupPoliciesMethod][0] = authenticatedUser
 	t.ArgsIn[ListAttachedGroupPoliciesMethod][1] = filter
@@ -626,6 +893,25 @@ func (t TestAPI) ListPolicies(authenticatedUser api.RequestInfo, filter *api.Fil
 	return policies, total, err
 }
 
+func (t TestAPI) ListPoliciesExpanded(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.Policy, int, error) {
+	t.ArgsIn[ListPoliciesExpandedMethod][0] = authenticatedUser
+	t.ArgsIn[ListPoliciesExpandedMethod][1] = filter
+
+	var policies []api.Policy
+	var total int
+	if t.ArgsOut[ListPoliciesExpandedMethod][1] != nil {
+		total = t.ArgsOut[ListPoliciesExpandedMethod][1].(int)
+	}
+	if t.ArgsOut[ListPoliciesExpandedMethod][0] != nil {
+		policies = t.ArgsOut[ListPoliciesExpandedMethod][0].([]api.Policy)
+	}
+	var err error
+	if t.ArgsOut[ListPoliciesExpandedMethod][2] != nil {
+		err = t.ArgsOut[ListPoliciesExpandedMethod][2].(error)
+	}
+	return policies, total, err
+}
+
 func (t TestAPI) UpdatePolicy(authenticatedUser api.RequestInfo, org string, policyName string, newName string, newPath string,
 	newStatements []api.Statement) (*api.Policy, error) {
 	t.ArgsIn[UpdatePolicyMethod][0] = authenticatedUser
@@ -690,7 +976,7 @@ func (t TestAPI) GetAuthorizedPolicies(authenticatedUser api.RequestInfo, resour
 	return nil, nil
 }
 
-func (t TestAPI) GetAuthorizedExternalResources(authenticatedUser api.RequestInfo, action string, resources []string) ([]string, error) {
+func (t TestAPI) GetAuthorizedExternalResources(authenticatedUser api.RequestInfo, action string, resources []api.ExternalResource) ([]string, error) {
 	t.ArgsIn[GetAuthorizedExternalResourcesMethod][0] = authenticatedUser
 	t.ArgsIn[GetAuthorizedExternalResourcesMethod][1] = action
 	t.ArgsIn[GetAuthorizedExternalResourcesMethod][2] = resources
@@ -773,6 +1059,25 @@ func (t TestAPI) ListProxyResources(authenticatedUser api.RequestInfo, filter *a
 	return proxyResources, total, err
 }
 
+func (t TestAPI) ListProxyResourcesExpanded(authenticatedUser api.RequestInfo, filter *api.Filter) ([]api.ProxyResource, int, error) {
+	t.ArgsIn[ListProxyResourcesExpandedMethod][0] = authenticatedUser
+	t.ArgsIn[ListProxyResourcesExpandedMethod][1] = filter
+
+	var proxyResources []api.ProxyResource
+	var total int
+	if t.ArgsOut[ListProxyResourcesExpandedMethod][1] != nil {
+		total = t.ArgsOut[ListProxyResourcesExpandedMethod][1].(int)
+	}
+	if t.ArgsOut[ListProxyResourcesExpandedMethod][0] != nil {
+		proxyResources = t.ArgsOut[ListProxyResourcesExpandedMethod][0].([]api.ProxyResource)
+	}
+	var err error
+	if t.ArgsOut[ListProxyResourcesExpandedMethod][2] != nil {
+		err = t.ArgsOut[ListProxyResourcesExpandedMethod][2].(error)
+	}
+	return proxyResources, total, err
+}
+
 func (t TestAPI) UpdateProxyResource(authenticatedUser api.RequestInfo, org string, name string, newName string, newPath string,
 	newResource api.ResourceEntity) (*api.ProxyResource, error) {
 	t.ArgsIn[UpdateProxyResourceMethod][0] = authenticatedUser
@@ -804,12 +1109,16 @@ func (t TestAPI) RemoveProxyResource(authenticatedUser api.RequestInfo, org stri
 	return err
 }
 
-func (t TestAPI) AddOidcProvider(requestInfo api.RequestInfo, name string, path string, issuerURL string, oidcClients []string) (*api.OidcProvider, error) {
+func (t TestAPI) AddOidcProvider(requestInfo api.RequestInfo, name string, path string, issuerURL string, oidcClients []string,
+	userProvisioning *api.UserProvisioning, groupMappings []api.GroupMapping, groupMappingMode string) (*api.OidcProvider, error) {
 	t.ArgsIn[AddOidcProviderMethod][0] = requestInfo
 	t.ArgsIn[AddOidcProviderMethod][1] = name
 	t.ArgsIn[AddOidcProviderMethod][2] = path
 	t.ArgsIn[AddOidcProviderMethod][3] = issuerURL
 	t.ArgsIn[AddOidcProviderMethod][4] = oidcClients
+	t.ArgsIn[AddOidcProviderMethod][5] = userProvisioning
+	t.ArgsIn[AddOidcProviderMethod][6] = groupMappings
+	t.ArgsIn[AddOidcProviderMethod][7] = groupMappingMode
 	var oidcProvider *api.OidcProvider
 	if t.ArgsOut[AddOidcProviderMethod][0] != nil {
 		oidcProvider = t.ArgsOut[AddOidcProviderMethod][0].(*api.OidcProvider)
@@ -855,7 +1164,8 @@ func (t TestAPI) ListOidcProviders(requestInfo api.RequestInfo, filter *api.Filt
 }
 
 func (t TestAPI) UpdateOidcProvider(requestInfo api.RequestInfo, oidcProviderName string, newName string, newPath string, newIssuerUrl string,
-	newClients []string) (*api.OidcProvider, error) {
+	newClients []string, newUserProvisioning *api.UserProvisioning, newGroupMappings []api.GroupMapping,
+	newGroupMappingMode string) (*api.OidcProvider, error) {
 
 	t.ArgsIn[UpdateOidcProviderMethod][0] = requestInfo
 	t.ArgsIn[UpdateOidcProviderMethod][1] = oidcProviderName
@@ -863,6 +1173,9 @@ func (t TestAPI) UpdateOidcProvider(requestInfo api.RequestInfo, oidcProviderNam
 	t.ArgsIn[UpdateOidcProviderMethod][3] = newPath
 	t.ArgsIn[UpdateOidcProviderMethod][4] = newIssuerUrl
 	t.ArgsIn[UpdateOidcProviderMethod][5] = newClients
+	t.ArgsIn[UpdateOidcProviderMethod][6] = newUserProvisioning
+	t.ArgsIn[UpdateOidcProviderMethod][7] = newGroupMappings
+	t.ArgsIn[UpdateOidcProviderMethod][8] = newGroupMappingMode
 
 	var oidcProvider *api.OidcProvider
 	if t.ArgsOut[UpdateOidcProviderMethod][0] != nil {
@@ -885,6 +1198,277 @@ func (t TestAPI) RemoveOidcProvider(requestInfo api.RequestInfo, name string) er
 	return err
 }
 
+func (t TestAPI) ProvisionOidcUser(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+	claims map[string]interface{}) (*api.User, error) {
+	t.ArgsIn[ProvisionOidcUserMethod][0] = requestInfo
+	t.ArgsIn[ProvisionOidcUserMethod][1] = oidcProvider
+	t.ArgsIn[ProvisionOidcUserMethod][2] = externalId
+	t.ArgsIn[ProvisionOidcUserMethod][3] = claims
+	var user *api.User
+	if t.ArgsOut[ProvisionOidcUserMethod][0] != nil {
+		user = t.ArgsOut[ProvisionOidcUserMethod][0].(*api.User)
+	}
+	var err error
+	if t.ArgsOut[ProvisionOidcUserMethod][1] != nil {
+		err = t.ArgsOut[ProvisionOidcUserMethod][1].(error)
+	}
+	return user, err
+}
+
+func (t TestAPI) SyncOidcUserGroups(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+	claims map[string]interface{}) error {
+	t.ArgsIn[SyncOidcUserGroupsMethod][0] = requestInfo
+	t.ArgsIn[SyncOidcUserGroupsMethod][1] = oidcProvider
+	t.ArgsIn[SyncOidcUserGroupsMethod][2] = externalId
+	t.ArgsIn[SyncOidcUserGroupsMethod][3] = claims
+	var err error
+	if t.ArgsOut[SyncOidcUserGroupsMethod][0] != nil {
+		err = t.ArgsOut[SyncOidcUserGroupsMethod][0].(error)
+	}
+	return err
+}
+
+// WEBHOOK API
+
+func (t TestAPI) AddWebhook(requestInfo api.RequestInfo, name string, path string, url string, secret string, events []string) (*api.Webhook, error) {
+	t.ArgsIn[AddWebhookMethod][0] = requestInfo
+	t.ArgsIn[AddWebhookMethod][1] = name
+	t.ArgsIn[AddWebhookMethod][2] = path
+	t.ArgsIn[AddWebhookMethod][3] = url
+	t.ArgsIn[AddWebhookMethod][4] = secret
+	t.ArgsIn[AddWebhookMethod][5] = events
+	var webhook *api.Webhook
+	if t.ArgsOut[AddWebhookMethod][0] != nil {
+		webhook = t.ArgsOut[AddWebhookMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[AddWebhookMethod][1] != nil {
+		err = t.ArgsOut[AddWebhookMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) GetWebhookByName(requestInfo api.RequestInfo, name string) (*api.Webhook, error) {
+	t.ArgsIn[GetWebhookByNameMethod][0] = requestInfo
+	t.ArgsIn[GetWebhookByNameMethod][1] = name
+	var webhook *api.Webhook
+	if t.ArgsOut[GetWebhookByNameMethod][0] != nil {
+		webhook = t.ArgsOut[GetWebhookByNameMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[GetWebhookByNameMethod][1] != nil {
+		err = t.ArgsOut[GetWebhookByNameMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) ListWebhooks(requestInfo api.RequestInfo, filter *api.Filter) ([]string, int, error) {
+	t.ArgsIn[ListWebhooksMethod][0] = requestInfo
+	t.ArgsIn[ListWebhooksMethod][1] = filter
+	var webhooks []string
+	if t.ArgsOut[ListWebhooksMethod][0] != nil {
+		webhooks = t.ArgsOut[ListWebhooksMethod][0].([]string)
+	}
+	var total int
+	if t.ArgsOut[ListWebhooksMethod][1] != nil {
+		total = t.ArgsOut[ListWebhooksMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListWebhooksMethod][2] != nil {
+		err = t.ArgsOut[ListWebhooksMethod][2].(error)
+	}
+	return webhooks, total, err
+}
+
+func (t TestAPI) UpdateWebhook(requestInfo api.RequestInfo, webhookName string, newName string, newPath string, newURL string,
+	newSecret string, newEvents []string) (*api.Webhook, error) {
+	t.ArgsIn[UpdateWebhookMethod][0] = requestInfo
+	t.ArgsIn[UpdateWebhookMethod][1] = webhookName
+	t.ArgsIn[UpdateWebhookMethod][2] = newName
+	t.ArgsIn[UpdateWebhookMethod][3] = newPath
+	t.ArgsIn[UpdateWebhookMethod][4] = newURL
+	t.ArgsIn[UpdateWebhookMethod][5] = newSecret
+	t.ArgsIn[UpdateWebhookMethod][6] = newEvents
+	var webhook *api.Webhook
+	if t.ArgsOut[UpdateWebhookMethod][0] != nil {
+		webhook = t.ArgsOut[UpdateWebhookMethod][0].(*api.Webhook)
+	}
+	var err error
+	if t.ArgsOut[UpdateWebhookMethod][1] != nil {
+		err = t.ArgsOut[UpdateWebhookMethod][1].(error)
+	}
+	return webhook, err
+}
+
+func (t TestAPI) RemoveWebhook(requestInfo api.RequestInfo, name string) error {
+	t.ArgsIn[RemoveWebhookMethod][0] = requestInfo
+	t.ArgsIn[RemoveWebhookMethod][1] = name
+	var err error
+	if t.ArgsOut[RemoveWebhookMethod][0] != nil {
+		err = t.ArgsOut[RemoveWebhookMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) ListWebhookDeliveries(requestInfo api.RequestInfo, filter *api.Filter) ([]api.WebhookDelivery, int, error) {
+	t.ArgsIn[ListWebhookDeliveriesMethod][0] = requestInfo
+	t.ArgsIn[ListWebhookDeliveriesMethod][1] = filter
+	var deliveries []api.WebhookDelivery
+	if t.ArgsOut[ListWebhookDeliveriesMethod][0] != nil {
+		deliveries = t.ArgsOut[ListWebhookDeliveriesMethod][0].([]api.WebhookDelivery)
+	}
+	var total int
+	if t.ArgsOut[ListWebhookDeliveriesMethod][1] != nil {
+		total = t.ArgsOut[ListWebhookDeliveriesMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListWebhookDeliveriesMethod][2] != nil {
+		err = t.ArgsOut[ListWebhookDeliveriesMethod][2].(error)
+	}
+	return deliveries, total, err
+}
+
+// API KEY API
+
+func (t TestAPI) AddApiKey(requestInfo api.RequestInfo, externalId string, name string, expireAt *time.Time) (*api.ApiKey, error) {
+	t.ArgsIn[AddApiKeyMethod][0] = requestInfo
+	t.ArgsIn[AddApiKeyMethod][1] = externalId
+	t.ArgsIn[AddApiKeyMethod][2] = name
+	t.ArgsIn[AddApiKeyMethod][3] = expireAt
+	var apiKey *api.ApiKey
+	if t.ArgsOut[AddApiKeyMethod][0] != nil {
+		apiKey = t.ArgsOut[AddApiKeyMethod][0].(*api.ApiKey)
+	}
+	var err error
+	if t.ArgsOut[AddApiKeyMethod][1] != nil {
+		err = t.ArgsOut[AddApiKeyMethod][1].(error)
+	}
+	return apiKey, err
+}
+
+func (t TestAPI) GetApiKey(requestInfo api.RequestInfo, externalId string, id string) (*api.ApiKey, error) {
+	t.ArgsIn[GetApiKeyMethod][0] = requestInfo
+	t.ArgsIn[GetApiKeyMethod][1] = externalId
+	t.ArgsIn[GetApiKeyMethod][2] = id
+	var apiKey *api.ApiKey
+	if t.ArgsOut[GetApiKeyMethod][0] != nil {
+		apiKey = t.ArgsOut[GetApiKeyMethod][0].(*api.ApiKey)
+	}
+	var err error
+	if t.ArgsOut[GetApiKeyMethod][1] != nil {
+		err = t.ArgsOut[GetApiKeyMethod][1].(error)
+	}
+	return apiKey, err
+}
+
+func (t TestAPI) ListApiKeys(requestInfo api.RequestInfo, filter *api.Filter) ([]api.ApiKey, int, error) {
+	t.ArgsIn[ListApiKeysMethod][0] = requestInfo
+	t.ArgsIn[ListApiKeysMethod][1] = filter
+	var apiKeys []api.ApiKey
+	if t.ArgsOut[ListApiKeysMethod][0] != nil {
+		apiKeys = t.ArgsOut[ListApiKeysMethod][0].([]api.ApiKey)
+	}
+	var total int
+	if t.ArgsOut[ListApiKeysMethod][1] != nil {
+		total = t.ArgsOut[ListApiKeysMethod][1].(int)
+	}
+	var err error
+	if t.ArgsOut[ListApiKeysMethod][2] != nil {
+		err = t.ArgsOut[ListApiKeysMethod][2].(error)
+	}
+	return apiKeys, total, err
+}
+
+func (t TestAPI) RotateApiKey(requestInfo api.RequestInfo, externalId string, id string) (*api.ApiKey, error) {
+	t.ArgsIn[RotateApiKeyMethod][0] = requestInfo
+	t.ArgsIn[RotateApiKeyMethod][1] = externalId
+	t.ArgsIn[RotateApiKeyMethod][2] = id
+	var apiKey *api.ApiKey
+	if t.ArgsOut[RotateApiKeyMethod][0] != nil {
+		apiKey = t.ArgsOut[RotateApiKeyMethod][0].(*api.ApiKey)
+	}
+	var err error
+	if t.ArgsOut[RotateApiKeyMethod][1] != nil {
+		err = t.ArgsOut[RotateApiKeyMethod][1].(error)
+	}
+	return apiKey, err
+}
+
+func (t TestAPI) RemoveApiKey(requestInfo api.RequestInfo, externalId string, id string) error {
+	t.ArgsIn[RemoveApiKeyMethod][0] = requestInfo
+	t.ArgsIn[RemoveApiKeyMethod][1] = externalId
+	t.ArgsIn[RemoveApiKeyMethod][2] = id
+	var err error
+	if t.ArgsOut[RemoveApiKeyMethod][0] != nil {
+		err = t.ArgsOut[RemoveApiKeyMethod][0].(error)
+	}
+	return err
+}
+
+func (t TestAPI) AuthenticateApiKey(id string, secret string) (string, error) {
+	t.ArgsIn[AuthenticateApiKeyMethod][0] = id
+	t.ArgsIn[AuthenticateApiKeyMethod][1] = secret
+	var externalID string
+	if t.ArgsOut[AuthenticateApiKeyMethod][0] != nil {
+		externalID = t.ArgsOut[AuthenticateApiKeyMethod][0].(string)
+	}
+	var err error
+	if t.ArgsOut[AuthenticateApiKeyMethod][1] != nil {
+		err = t.ArgsOut[AuthenticateApiKeyMethod][1].(error)
+	}
+	return externalID, err
+}
+
+// CHANGE API
+
+func (t TestAPI) ListChanges(requestInfo api.RequestInfo, cursor string, limit int) ([]api.Change, string, error) {
+	t.ArgsIn[ListChangesMethod][0] = requestInfo
+	t.ArgsIn[ListChangesMethod][1] = cursor
+	t.ArgsIn[ListChangesMethod][2] = limit
+	var changes []api.Change
+	if t.ArgsOut[ListChangesMethod][0] != nil {
+		changes = t.ArgsOut[ListChangesMethod][0].([]api.Change)
+	}
+	var nextCursor string
+	if t.ArgsOut[ListChangesMethod][1] != nil {
+		nextCursor = t.ArgsOut[ListChangesMethod][1].(string)
+	}
+	var err error
+	if t.ArgsOut[ListChangesMethod][2] != nil {
+		err = t.ArgsOut[ListChangesMethod][2].(error)
+	}
+	return changes, nextCursor, err
+}
+
+func (t TestAPI) GetChanges(cursor string) ([]api.Change, string, error) {
+	t.ArgsIn[GetChangesMethod][0] = cursor
+	var changes []api.Change
+	if t.ArgsOut[GetChangesMethod][0] != nil {
+		changes = t.ArgsOut[GetChangesMethod][0].([]api.Change)
+	}
+	var nextCursor string
+	if t.ArgsOut[GetChangesMethod][1] != nil {
+		nextCursor = t.ArgsOut[GetChangesMethod][1].(string)
+	}
+	var err error
+	if t.ArgsOut[GetChangesMethod][2] != nil {
+		err = t.ArgsOut[GetChangesMethod][2].(error)
+	}
+	return changes, nextCursor, err
+}
+
+func (t TestAPI) GetLastChangeCursor() (string, error) {
+	var cursor string
+	if t.ArgsOut[GetLastChangeCursorMethod][0] != nil {
+		cursor = t.ArgsOut[GetLastChangeCursorMethod][0].(string)
+	}
+	var err error
+	if t.ArgsOut[GetLastChangeCursorMethod][1] != nil {
+		err = t.ArgsOut[GetLastChangeCursorMethod][1].(error)
+	}
+	return cursor, err
+}
+
 // Private helper methods
 
 func addQueryParams(filter *api.Filter, r *http.Request) {
@@ -893,6 +1477,42 @@ func addQueryParams(filter *api.Filter, r *http.Request) {
 		if filter.PathPrefix != "" {
 			q.Add("PathPrefix", filter.PathPrefix)
 		}
+		for key, value := range filter.Attributes {
+			q.Add("Attribute", key+":"+value)
+		}
+		if filter.State != "" {
+			q.Add("State", filter.State)
+		}
+		if filter.NamePrefix != "" {
+			q.Add("NamePrefix", filter.NamePrefix)
+		}
+		if filter.NameContains != "" {
+			q.Add("NameContains", filter.NameContains)
+		}
+		if !filter.CreatedAfter.IsZero() {
+			q.Add("CreatedAfter", filter.CreatedAfter.Format(time.RFC3339))
+		}
+		if !filter.CreatedBefore.IsZero() {
+			q.Add("CreatedBefore", filter.CreatedBefore.Format(time.RFC3339))
+		}
+		if !filter.UpdatedAfter.IsZero() {
+			q.Add("UpdatedAfter", filter.UpdatedAfter.Format(time.RFC3339))
+		}
+		if !filter.UpdatedBefore.IsZero() {
+			q.Add("UpdatedBefore", filter.UpdatedBefore.Format(time.RFC3339))
+		}
+		if filter.Action != "" {
+			q.Add("Action", filter.Action)
+		}
+		if filter.Resource != "" {
+			q.Add("Resource", filter.Resource)
+		}
+		if filter.Cursor != "" {
+			q.Add("Cursor", filter.Cursor)
+		}
+		if filter.Expand {
+			q.Add("Expand", "true")
+		}
 		q.Add("Offset", fmt.Sprintf("%v", filter.Offset))
 		q.Add("Limit", fmt.Sprintf("%v", filter.Limit))
 		r.URL.RawQuery = q.Encode()
@@ -903,7 +1523,7 @@ func proxyHandlerRouter(proxy *foulkon.Proxy) http.Handler {
 	// Create the muxer to handle the actual endpoints
 	router := httprouter.New()
 
-	proxyHandler := ProxyHandler{proxy: proxy, client: http.DefaultClient}
+	proxyHandler := ProxyHandler{proxy: proxy, client: http.DefaultClient, workerClient: http.DefaultClient}
 
 	APIResources := []api.ProxyResource{
 		{
@@ -914,6 +1534,9 @@ func proxyHandlerRouter(proxy *foulkon.Proxy) http.Handler {
 				Method: "GET",
 				Urn:    "urn:ews:example:instance1:resource/{userid}",
 				Action: "example:user",
+				Tags: map[string]string{
+					"env": "test",
+				},
 			},
 		},
 		{
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Tecsisa/foulkon/metrics"
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Prometheus metrics URL, served by worker and proxy without authentication
+	METRICS_URL = "/metrics"
+
+	// Route label of requests that don't match any route
+	ROUTE_UNMATCHED = "unmatched"
+)
+
+// instrumentedRouter registers handlers in router naming request spans by route pattern. It keeps the patterns
+// in another router, so route of a request is known before middlewares run
+type instrumentedRouter struct {
+	*httprouter.Router
+	patterns *httprouter.Router
+}
+
+func newInstrumentedRouter() *instrumentedRouter {
+	return &instrumentedRouter{
+		Router:   httprouter.New(),
+		patterns: httprouter.New(),
+	}
+}
+
+func (ir *instrumentedRouter) Handle(method string, path string, handle httprouter.Handle) {
+	ir.Router.Handle(method, path, instrumentHandle(path, handle))
+	ir.patterns.Handle(method, path, func(_ http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		setRequestRoute(r, path)
+	})
+}
+
+// resolveRoute sets route pattern of request if it matches any route
+func (ir *instrumentedRouter) resolveRoute(r *http.Request) {
+	if handle, _, _ := ir.patterns.Lookup(r.Method, r.URL.Path); handle != nil {
+		handle(nil, r, nil)
+	}
+}
+
+func (ir *instrumentedRouter) GET(path string, handle httprouter.Handle) {
+	ir.Handle(http.MethodGet, path, handle)
+}
+
+func (ir *instrumentedRouter) POST(path string, handle httprouter.Handle) {
+	ir.Handle(http.MethodPost, path, handle)
+}
+
+func (ir *instrumentedRouter) PUT(path string, handle httprouter.Handle) {
+	ir.Handle(http.MethodPut, path, handle)
+}
+
+func (ir *instrumentedRouter) PATCH(path string, handle httprouter.Handle) {
+	ir.Handle(http.MethodPatch, path, handle)
+}
+
+func (ir *instrumentedRouter) DELETE(path string, handle httprouter.Handle) {
+	ir.Handle(http.MethodDelete, path, handle)
+}
+
+// instrumentHandle sets route of requests served by handle, to label their metrics, and names request span after route
+func instrumentHandle(route string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		setRequestRoute(r, route)
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		handle(recorder, r, ps)
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(attribute.Int("http.status_code", recorder.statusCode))
+	}
+}
+
+// requestRoute holds route pattern matched by a request
+type requestRoute struct {
+	pattern string
+}
+
+// requestRouteKey is the context key of request route
+type requestRouteKey struct{}
+
+func setRequestRoute(r *http.Request, pattern string) {
+	if route, ok := r.Context().Value(requestRouteKey{}).(*requestRoute); ok {
+		route.pattern = pattern
+	}
+}
+
+// instrumentRequests records count and latency of all requests served by handler, including requests rejected
+// by middlewares and requests that don't match any route. Optional resolve func sets route before handler runs
+func instrumentRequests(server string, resolve func(r *http.Request), handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := &requestRoute{}
+		r = r.WithContext(context.WithValue(r.Context(), requestRouteKey{}, route))
+		if resolve != nil {
+			resolve(r)
+		}
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		if route.pattern == "" {
+			route.pattern = ROUTE_UNMATCHED
+		}
+		metrics.ObserveHttpRequest(server, route.pattern, r.Method, recorder.statusCode, start)
+	})
+}
+
+// withMetricsEndpoint serves metrics URL before calling handler
+func withMetricsEndpoint(handler http.Handler) http.Handler {
+	metricsHandler := metrics.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == METRICS_URL && r.Method == http.MethodGet {
+			metricsHandler.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder keeps status code written in response
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	sr.statusCode = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+// Flush allows streaming responses through recorder
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
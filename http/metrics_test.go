@@ -0,0 +1,187 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/metrics"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_Metrics(t *testing.T) {
+	testcases := map[string]struct {
+		path           string
+		authStatusCode int
+		// Expected result
+		expectedStatusCode int
+		expectedMetric     string
+	}{
+		"OKCase": {
+			path:               USER_ROOT_URL,
+			expectedStatusCode: http.StatusOK,
+			expectedMetric:     `foulkon_http_requests_total{code="200",method="GET",route="/api/v1/users",server="worker"}`,
+		},
+		"OKCaseUnauthenticated": {
+			path:               USER_ROOT_URL,
+			authStatusCode:     http.StatusUnauthorized,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedMetric:     `foulkon_http_requests_total{code="401",method="GET",route="/api/v1/users",server="worker"}`,
+		},
+		"OKCaseUnmatched": {
+			path:               "/api/v1/unknown",
+			expectedStatusCode: http.StatusNotFound,
+			expectedMetric:     `foulkon_http_requests_total{code="404",method="GET",route="unmatched",server="worker"}`,
+		},
+	}
+
+	for n, test := range testcases {
+		testApi.ArgsOut[ListUsersMethod][0] = []string{"user1"}
+		testApi.ArgsOut[ListUsersMethod][1] = 1
+		testApi.ArgsOut[ListUsersMethod][2] = nil
+		if test.authStatusCode != 0 {
+			authConnector.statusCode = test.authStatusCode
+		}
+
+		// Call to worker
+		req, err := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+		res.Body.Close()
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		// Retrieve metrics
+		res, err = http.Get(server.URL + METRICS_URL)
+		assert.Nil(t, err, "Error in test case %v", n)
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check response
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Error in test case %v", n)
+		assert.True(t, strings.Contains(string(body), test.expectedMetric), "Error in test case %v", n)
+	}
+}
+
+func TestInstrumentHandle(t *testing.T) {
+	testcases := map[string]struct {
+		handle httprouter.Handle
+
+		expectedStatusCode int
+		expectedFlush      bool
+	}{
+		"OKCaseDefaultStatusCode": {
+			handle: func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				w.Write([]byte("ok"))
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseStatusCode": {
+			handle: func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+			expectedStatusCode: http.StatusTeapot,
+		},
+		"OKCaseFlush": {
+			handle: func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				w.(http.Flusher).Flush()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedFlush:      true,
+		},
+	}
+
+	for n, test := range testcases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/route", nil)
+
+		instrumentHandle("/route", test.handle)(w, r, nil)
+
+		// Check response
+		assert.Equal(t, test.expectedStatusCode, w.Code, "Error in test case %v", n)
+		assert.Equal(t, test.expectedFlush, w.Flushed, "Error in test case %v", n)
+	}
+}
+
+func TestInstrumentRequests(t *testing.T) {
+	testcases := map[string]struct {
+		path string
+		// Expected result
+		expectedStatusCode int
+		expectedMetric     string
+	}{
+		"OKCaseRoute": {
+			path:               "/instrumented/route",
+			expectedStatusCode: http.StatusTeapot,
+			expectedMetric:     `foulkon_http_requests_total{code="418",method="GET",route="/instrumented/:id",server="proxy"}`,
+		},
+		"OKCaseUnmatched": {
+			path:               "/other",
+			expectedStatusCode: http.StatusNotFound,
+			expectedMetric:     `foulkon_http_requests_total{code="404",method="GET",route="unmatched",server="proxy"}`,
+		},
+	}
+
+	router := httprouter.New()
+	router.GET("/instrumented/:id", instrumentHandle("/instrumented/:id", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	handler := withMetricsEndpoint(instrumentRequests(metrics.SERVER_PROXY, nil, router))
+
+	for n, test := range testcases {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, test.path, nil)
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, test.expectedStatusCode, w.Code, "Error in test case %v", n)
+
+		// Check metrics
+		w = httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, METRICS_URL, nil)
+		handler.ServeHTTP(w, r)
+		assert.True(t, strings.Contains(w.Body.String(), test.expectedMetric), "Error in test case %v", n)
+	}
+}
+
+func TestWithMetricsEndpoint(t *testing.T) {
+	testcases := map[string]struct {
+		method string
+		path   string
+
+		expectedNextCalled bool
+	}{
+		"OKCaseMetrics": {
+			method: http.MethodGet,
+			path:   METRICS_URL,
+		},
+		"OKCaseOtherPath": {
+			method:             http.MethodGet,
+			path:               "/other",
+			expectedNextCalled: true,
+		},
+		"OKCaseOtherMethod": {
+			method:             http.MethodPost,
+			path:               METRICS_URL,
+			expectedNextCalled: true,
+		},
+	}
+
+	for n, test := range testcases {
+		nextCalled := false
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nextCalled = true
+			WriteHttpResponse(r, w, "", "", http.StatusNotFound, &api.Error{})
+		})
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(test.method, test.path, nil)
+
+		withMetricsEndpoint(next).ServeHTTP(w, r)
+
+		// Check response
+		assert.Equal(t, test.expectedNextCalled, nextCalled, "Error in test case %v", n)
+	}
+}
@@ -28,6 +28,8 @@ type ListPoliciesResponse struct {
 	Limit    int      `json:"limit"`
 	Offset   int      `json:"offset"`
 	Total    int      `json:"total"`
+	Next     string   `json:"next,omitempty"`
+	Prev     string   `json:"prev,omitempty"`
 }
 
 type ListAllPoliciesResponse struct {
@@ -35,6 +37,17 @@ type ListAllPoliciesResponse struct {
 	Limit    int                  `json:"limit"`
 	Offset   int                  `json:"offset"`
 	Total    int                  `json:"total"`
+	Next     string               `json:"next,omitempty"`
+	Prev     string               `json:"prev,omitempty"`
+}
+
+type ListPoliciesExpandedResponse struct {
+	Policies []api.Policy `json:"policies,omitempty"`
+	Limit    int          `json:"limit"`
+	Offset   int          `json:"offset"`
+	Total    int          `json:"total"`
+	Next     string       `json:"next,omitempty"`
+	Prev     string       `json:"prev,omitempty"`
 }
 
 type ListAttachedGroupsResponse struct {
@@ -42,6 +55,8 @@ type ListAttachedGroupsResponse struct {
 	Limit  int                `json:"limit"`
 	Offset int                `json:"offset"`
 	Total  int                `json:"total"`
+	Next   string             `json:"next,omitempty"`
+	Prev   string             `json:"prev,omitempty"`
 }
 
 // HANDLERS
@@ -80,6 +95,20 @@ func (wh *WorkerHandler) HandleListPolicies(w http.ResponseWriter, r *http.Reque
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call policy API to list full policies
+		result, total, err := wh.worker.PolicyApi.ListPoliciesExpanded(requestInfo, filterData)
+		response := &ListPoliciesExpandedResponse{
+			Policies: result,
+			Offset:   filterData.Offset,
+			Limit:    filterData.Limit,
+			Total:    total,
+			Next:     filterData.NextCursor,
+			Prev:     filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 	// Call policy API to list policies
 	result, total, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
 	// Create response
@@ -92,6 +121,8 @@ func (wh *WorkerHandler) HandleListPolicies(w http.ResponseWriter, r *http.Reque
 		Offset:   filterData.Offset,
 		Limit:    filterData.Limit,
 		Total:    total,
+		Next:     filterData.NextCursor,
+		Prev:     filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -103,6 +134,20 @@ func (wh *WorkerHandler) HandleListAllPolicies(w http.ResponseWriter, r *http.Re
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call policy API to list full policies
+		result, total, err := wh.worker.PolicyApi.ListPoliciesExpanded(requestInfo, filterData)
+		response := &ListPoliciesExpandedResponse{
+			Policies: result,
+			Offset:   filterData.Offset,
+			Limit:    filterData.Limit,
+			Total:    total,
+			Next:     filterData.NextCursor,
+			Prev:     filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 	// Call policy API to list all policies
 	result, total, err := wh.worker.PolicyApi.ListPolicies(requestInfo, filterData)
 	// Create response
@@ -111,6 +156,8 @@ func (wh *WorkerHandler) HandleListAllPolicies(w http.ResponseWriter, r *http.Re
 		Offset:   filterData.Offset,
 		Limit:    filterData.Limit,
 		Total:    total,
+		Next:     filterData.NextCursor,
+		Prev:     filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -155,6 +202,8 @@ func (wh *WorkerHandler) HandleListAttachedGroups(w http.ResponseWriter, r *http
 		Offset: filterData.Offset,
 		Limit:  filterData.Limit,
 		Total:  total,
+		Next:   filterData.NextCursor,
+		Prev:   filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -456,6 +456,26 @@ func TestWorkerHandler_HandleListPolicies(t *testing.T) {
 			},
 			totalPoliciesResult: 1,
 		},
+		"OkCaseActionAndResource": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+				Org:        "org1",
+				Action:     "iam:DeleteUser",
+				Resource:   "urn:iws:iam::user/path/user1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListPoliciesResponse{
+				Policies: []string{"policy1"},
+				Total:    1,
+			},
+			getPolicyListResult: []api.PolicyIdentity{
+				{
+					Org:  "org1",
+					Name: "policy1",
+				},
+			},
+			totalPoliciesResult: 1,
+		},
 		"ErrorCaseInvalidFilterParams": {
 			filter: &api.Filter{
 				PathPrefix: "",
@@ -727,6 +747,169 @@ func TestWorkerHandler_HandleListAllPolicies(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleListPoliciesExpanded(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		expand       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListPoliciesExpandedResponse
+		expectedError      api.Error
+		// Manager Results
+		listPolicyResult  []api.Policy
+		totalPolicyResult int
+		// Manager Errors
+		listPolicyErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListPoliciesExpandedResponse{
+				Policies: []api.Policy{
+					{
+						ID:   "PolicyID",
+						Name: "policy1",
+						Org:  "org1",
+						Path: "path",
+						Urn:  "urn",
+						Statements: &[]api.Statement{
+							{
+								Effect:    "allow",
+								Actions:   []string{api.USER_ACTION_GET_USER},
+								Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+							},
+						},
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			listPolicyResult: []api.Policy{
+				{
+					ID:   "PolicyID",
+					Name: "policy1",
+					Org:  "org1",
+					Path: "path",
+					Urn:  "urn",
+					Statements: &[]api.Statement{
+						{
+							Effect:    "allow",
+							Actions:   []string{api.USER_ACTION_GET_USER},
+							Resources: []string{api.GetUrnPrefix("", api.RESOURCE_USER, "/path/")},
+						},
+					},
+				},
+			},
+			totalPolicyResult: 1,
+		},
+		"ErrorCaseInvalidExpand": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+			},
+			expand:             "maybe",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Expand maybe",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listPolicyErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listPolicyErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListPoliciesExpandedMethod][0] = test.listPolicyResult
+		testApi.ArgsOut[ListPoliciesExpandedMethod][1] = test.totalPolicyResult
+		testApi.ArgsOut[ListPoliciesExpandedMethod][2] = test.listPolicyErr
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/policies", test.filter.Org), nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+		if test.expand != "" {
+			q := req.URL.Query()
+			q.Set("Expand", test.expand)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListPoliciesExpandedMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listResponse := ListPoliciesExpandedResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleUpdatePolicy(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
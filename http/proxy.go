@@ -8,11 +8,16 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/metrics"
 	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/tracing"
 	"github.com/julienschmidt/httprouter"
 	"github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -49,6 +54,17 @@ type ListProxyResourcesResponse struct {
 	Limit     int      `json:"limit"`
 	Offset    int      `json:"offset"`
 	Total     int      `json:"total"`
+	Next      string   `json:"next,omitempty"`
+	Prev      string   `json:"prev,omitempty"`
+}
+
+type ListProxyResourcesExpandedResponse struct {
+	Resources []api.ProxyResource `json:"resources,omitempty"`
+	Limit     int                 `json:"limit"`
+	Offset    int                 `json:"offset"`
+	Total     int                 `json:"total"`
+	Next      string              `json:"next,omitempty"`
+	Prev      string              `json:"prev,omitempty"`
 }
 
 var rUrnParam, _ = regexp.Compile(`\{(\w+)\}`)
@@ -57,15 +73,27 @@ func (ph *ProxyHandler) HandleRequest(proxyResource api.ProxyResource) httproute
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		requestID := uuid.NewV4().String()
 		w.Header().Set(middleware.REQUEST_ID_HEADER, requestID)
+		ctx, span := tracing.StartServer(r, r.Method+" "+proxyResource.Resource.Path)
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("foulkon.request_id", requestID),
+			attribute.String("foulkon.proxy_resource", proxyResource.Org+"/"+proxyResource.Name),
+		)
+		r = r.WithContext(ctx)
 		// Retrieve parameters to replace in URN
 		parameters := getUrnParameters(proxyResource.Resource.Urn)
 		urn := proxyResource.Resource.Urn
 		for _, p := range parameters {
 			urn = strings.Replace(urn, p[0], ps.ByName(p[1]), -1)
 		}
-		if workerRequestID, err := ph.checkAuthorization(r, urn, proxyResource.Resource.Action); err == nil {
+		start := time.Now()
+		workerRequestID, userID, err := ph.checkAuthorization(r, urn, proxyResource.Resource.Action, proxyResource.Resource.Tags)
+		span.SetAttributes(attribute.String("foulkon.worker_request_id", workerRequestID))
+		logProxyDecision(requestID, workerRequestID, userID, proxyResource.Resource.Action, urn, err, time.Since(start))
+		if err == nil {
 			destURL, err := url.Parse(proxyResource.Resource.Host)
 			if err != nil {
+				metrics.ProxyUpstreamErrors.WithLabelValues(proxyResource.Org, proxyResource.Name, INVALID_DEST_HOST_URL).Inc()
 				apiErr := getErrorMessage(INVALID_DEST_HOST_URL, fmt.Sprintf("Error creating destination host URL: %v", err.Error()))
 				api.TransactionProxyErrorLogWithStatus(requestID, workerRequestID, r, http.StatusInternalServerError, apiErr)
 				WriteHttpResponse(r, w, requestID, "", http.StatusInternalServerError, getErrorMessage(INVALID_DEST_HOST_URL, "Error creating destination host"))
@@ -76,8 +104,15 @@ func (ph *ProxyHandler) HandleRequest(proxyResource api.ProxyResource) httproute
 			// Clean request URI because net/http send method force this
 			r.RequestURI = ""
 			// Retrieve requested resource
-			res, err := ph.client.Do(r)
+			upstreamStart := time.Now()
+			upstreamCtx, upstreamSpan := tracing.Start(ctx, "proxy.upstream", trace.WithSpanKind(trace.SpanKindClient))
+			upstreamSpan.SetAttributes(attribute.String("http.host", destURL.Host))
+			defer upstreamSpan.End()
+			tracing.Inject(upstreamCtx, r.Header)
+			res, err := ph.client.Do(r.WithContext(upstreamCtx))
 			if err != nil {
+				tracing.SetError(upstreamSpan, err)
+				metrics.ProxyUpstreamErrors.WithLabelValues(proxyResource.Org, proxyResource.Name, HOST_UNREACHABLE).Inc()
 				apiErr := getErrorMessage(HOST_UNREACHABLE, fmt.Sprintf("Error calling to destination host resource: %v", err.Error()))
 				api.TransactionProxyErrorLogWithStatus(requestID, workerRequestID, r, http.StatusInternalServerError, apiErr)
 				WriteHttpResponse(r, w, requestID, "", http.StatusInternalServerError, getErrorMessage(HOST_UNREACHABLE, "Error calling destination resource"))
@@ -97,17 +132,22 @@ func (ph *ProxyHandler) HandleRequest(proxyResource api.ProxyResource) httproute
 
 			defer res.Body.Close()
 			buffer := new(bytes.Buffer)
-			if _, err := buffer.ReadFrom(res.Body); err != nil {
+			_, err = buffer.ReadFrom(res.Body)
+			metrics.ProxyUpstreamDuration.WithLabelValues(proxyResource.Org, proxyResource.Name).Observe(time.Since(upstreamStart).Seconds())
+			if err != nil {
+				metrics.ProxyUpstreamErrors.WithLabelValues(proxyResource.Org, proxyResource.Name, INTERNAL_SERVER_ERROR).Inc()
 				apiErr := getErrorMessage(INTERNAL_SERVER_ERROR, fmt.Sprintf("Error reading response from destination: %v", err.Error()))
 				api.TransactionProxyErrorLogWithStatus(requestID, workerRequestID, r, http.StatusInternalServerError, apiErr)
 				WriteHttpResponse(r, w, requestID, "", http.StatusInternalServerError, apiErr)
 				return
 			}
+			upstreamSpan.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
 			w.WriteHeader(res.StatusCode)
 			w.Write(buffer.Bytes())
 			api.TransactionProxyLog(requestID, workerRequestID, r, "Request accepted")
 		} else {
 			apiError := err.(*api.Error)
+			tracing.SetError(span, apiError)
 			var statusCode int
 			var responseErr *api.Error
 			switch apiError.Code {
@@ -164,6 +204,20 @@ func (wh *WorkerHandler) HandleListProxyResource(w http.ResponseWriter, r *http.
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call proxy resource API to list full proxy resources
+		result, total, err := wh.worker.ProxyApi.ListProxyResourcesExpanded(requestInfo, filterData)
+		response := &ListProxyResourcesExpandedResponse{
+			Resources: result,
+			Offset:    filterData.Offset,
+			Limit:     filterData.Limit,
+			Total:     total,
+			Next:      filterData.NextCursor,
+			Prev:      filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 	// Call proxy Resource API to create proxyResource
 	result, total, err := wh.worker.ProxyApi.ListProxyResources(requestInfo, filterData)
 	proxyResources := []string{}
@@ -176,6 +230,8 @@ func (wh *WorkerHandler) HandleListProxyResource(w http.ResponseWriter, r *http.
 		Offset:    filterData.Offset,
 		Limit:     filterData.Limit,
 		Total:     total,
+		Next:      filterData.NextCursor,
+		Prev:      filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -205,55 +261,62 @@ func (wh *WorkerHandler) HandleRemoveProxyResource(w http.ResponseWriter, r *htt
 	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
 }
 
-func (ph *ProxyHandler) checkAuthorization(r *http.Request, urn string, action string) (string, error) {
+func (ph *ProxyHandler) checkAuthorization(r *http.Request, urn string, action string, tags map[string]string) (string, string, error) {
+	ctx, span := tracing.Start(r.Context(), "proxy.checkAuthorization", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	span.SetAttributes(attribute.String("foulkon.action", action), attribute.String("foulkon.urn", urn))
 	workerRequestID := "None"
+	userID := ""
 	if !isFullUrn(urn) {
-		return workerRequestID,
+		return workerRequestID, userID,
 			getErrorMessage(api.INVALID_PARAMETER_ERROR, fmt.Sprintf("Urn %v is a prefix, it would be a full urn resource", urn))
 	}
 	if err := api.AreValidResources([]string{urn}, api.RESOURCE_EXTERNAL); err != nil {
-		return workerRequestID, err
+		return workerRequestID, userID, err
 	}
 	if err := api.AreValidActions([]string{action}); err != nil {
-		return workerRequestID, err
+		return workerRequestID, userID, err
 	}
 
 	body, err := json.Marshal(AuthorizeResourcesRequest{
 		Action:    action,
-		Resources: []string{urn},
+		Resources: []AuthorizeResource{{Urn: urn, Tags: tags}},
 	})
 	if err != nil {
-		return workerRequestID, getErrorMessage(api.UNKNOWN_API_ERROR, err.Error())
+		return workerRequestID, userID, getErrorMessage(api.UNKNOWN_API_ERROR, err.Error())
 	}
 
 	req, err := http.NewRequest(http.MethodPost, ph.proxy.WorkerHost+RESOURCE_URL, bytes.NewBuffer(body))
 	if err != nil {
-		return workerRequestID, getErrorMessage(api.UNKNOWN_API_ERROR, err.Error())
+		return workerRequestID, userID, getErrorMessage(api.UNKNOWN_API_ERROR, err.Error())
 	}
-	// Add all headers from original request
+	// Add all headers from original request, with traceparent of this call
 	req.Header = r.Header
+	tracing.Inject(ctx, req.Header)
+	req = req.WithContext(ctx)
 	// Call worker to retrieve authorization
-	res, err := ph.client.Do(req)
+	res, err := ph.workerClient.Do(req)
 	if err != nil {
-		return workerRequestID, getErrorMessage(HOST_UNREACHABLE, err.Error())
+		return workerRequestID, userID, getErrorMessage(HOST_UNREACHABLE, err.Error())
 	}
 
 	defer res.Body.Close()
 
 	workerRequestID = res.Header.Get(middleware.REQUEST_ID_HEADER)
+	userID = res.Header.Get(middleware.USER_ID_HEADER)
 
 	switch res.StatusCode {
 	case http.StatusUnauthorized:
-		return workerRequestID, getErrorMessage(FORBIDDEN_ERROR, "Unauthenticated user")
+		return workerRequestID, userID, getErrorMessage(FORBIDDEN_ERROR, "Unauthenticated user")
 	case http.StatusForbidden:
-		return workerRequestID, getErrorMessage(FORBIDDEN_ERROR, fmt.Sprintf("Restricted access to urn %v", urn))
+		return workerRequestID, userID, getErrorMessage(FORBIDDEN_ERROR, fmt.Sprintf("Restricted access to urn %v", urn))
 	case http.StatusBadRequest:
-		return workerRequestID, getErrorMessage(BAD_REQUEST, "Invalid request")
+		return workerRequestID, userID, getErrorMessage(BAD_REQUEST, "Invalid request")
 	case http.StatusOK:
 		authzResponse := AuthorizeResourcesResponse{}
 		err = json.NewDecoder(res.Body).Decode(&authzResponse)
 		if err != nil {
-			return workerRequestID, getErrorMessage(api.UNKNOWN_API_ERROR, fmt.Sprintf("Error parsing foulkon response %v", err.Error()))
+			return workerRequestID, userID, getErrorMessage(api.UNKNOWN_API_ERROR, fmt.Sprintf("Error parsing foulkon response %v", err.Error()))
 		}
 
 		// Check urns allowed to find target urn
@@ -266,17 +329,41 @@ func (ph *ProxyHandler) checkAuthorization(r *http.Request, urn string, action s
 		}
 
 		if !allowed {
-			return workerRequestID,
+			return workerRequestID, userID,
 				getErrorMessage(FORBIDDEN_ERROR, fmt.Sprintf("No access for urn %v received from server", urn))
 		}
 
-		return workerRequestID, nil
+		return workerRequestID, userID, nil
 	default:
-		return workerRequestID,
+		return workerRequestID, userID,
 			getErrorMessage(INTERNAL_SERVER_ERROR, fmt.Sprintf("There was a problem retrieving authorization, status code %v", res.StatusCode))
 	}
 }
 
+// logProxyDecision logs authorization decision received from worker. Other errors aren't decisions so they are skipped
+func logProxyDecision(requestID string, workerRequestID string, userID string, action string, urn string, err error, latency time.Duration) {
+	decision := &api.AuthorizationDecision{
+		Source:          api.DECISION_SOURCE_PROXY,
+		RequestID:       requestID,
+		WorkerRequestID: workerRequestID,
+		User:            userID,
+		Action:          action,
+		Resource:        urn,
+		RequestedUrns:   []string{urn},
+		AllowedUrns:     []string{},
+		Latency:         latency,
+	}
+	if err == nil {
+		decision.Effect = api.DECISION_ALLOW
+		decision.AllowedUrns = []string{urn}
+	} else if apiError, ok := err.(*api.Error); ok && apiError.Code == FORBIDDEN_ERROR {
+		decision.Effect = api.DECISION_DENY
+	} else {
+		return
+	}
+	api.LogAuthorizationDecision(decision)
+}
+
 // Check parameters in URN to replace with URI parameters
 func getUrnParameters(urn string) [][]string {
 	match := rUrnParam.FindAllStringSubmatch(urn, -1)
@@ -9,8 +9,13 @@ import (
 	"bytes"
 	"fmt"
 
+	logtest "github.com/Sirupsen/logrus/hooks/test"
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestProxyHandler_HandleRequest(t *testing.T) {
@@ -19,6 +24,7 @@ func TestProxyHandler_HandleRequest(t *testing.T) {
 		expectedStatusCode int
 		expectedError      *api.Error
 		expectedResponse   api.User
+		expectedResources  []api.ExternalResource
 		resource           string
 		// Manager Results
 		getListUsersResult                   []string
@@ -50,6 +56,14 @@ func TestProxyHandler_HandleRequest(t *testing.T) {
 				CreateAt:   now,
 				UpdateAt:   now,
 			},
+			expectedResources: []api.ExternalResource{
+				{
+					Urn: "urn:ews:example:instance1:resource/user",
+					Tags: map[string]string{
+						"env": "test",
+					},
+				},
+			},
 			getAuthorizedExternalResourcesResult: []string{"urn:ews:example:instance1:resource/user"},
 		},
 		"ErrorCaseInvalidParameter": {
@@ -202,6 +216,8 @@ func TestProxyHandler_HandleRequest(t *testing.T) {
 			}
 			// Check result
 			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+			// Check resources sent to authorize, with static tags of proxy resource
+			assert.Equal(t, test.expectedResources, testApi.ArgsIn[GetAuthorizedExternalResourcesMethod][2], "Error in test case %v", n)
 		default:
 			if test.expectedError != nil {
 				apiError := &api.Error{}
@@ -1022,6 +1038,169 @@ func TestWorkerHandler_HandleListProxyResource(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleListProxyResourceExpanded(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		expand       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListProxyResourcesExpandedResponse
+		expectedError      api.Error
+		// Manager Results
+		listProxyResourceResult  []api.ProxyResource
+		totalProxyResourceResult int
+		// Manager Errors
+		listProxyResourceErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListProxyResourcesExpandedResponse{
+				Resources: []api.ProxyResource{
+					{
+						ID:   "ProxyResourceID",
+						Name: "resource1",
+						Org:  "org1",
+						Path: "path",
+						Urn:  "urn",
+						Resource: api.ResourceEntity{
+							Host:   "http://host.com",
+							Path:   "/path",
+							Method: "GET",
+							Urn:    "urn:ews:example:instance1:resource/get",
+							Action: "example:get",
+						},
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			listProxyResourceResult: []api.ProxyResource{
+				{
+					ID:   "ProxyResourceID",
+					Name: "resource1",
+					Org:  "org1",
+					Path: "path",
+					Urn:  "urn",
+					Resource: api.ResourceEntity{
+						Host:   "http://host.com",
+						Path:   "/path",
+						Method: "GET",
+						Urn:    "urn:ews:example:instance1:resource/get",
+						Action: "example:get",
+					},
+				},
+			},
+			totalProxyResourceResult: 1,
+		},
+		"ErrorCaseInvalidExpand": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+			},
+			expand:             "maybe",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Expand maybe",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listProxyResourceErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Org:        "org1",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listProxyResourceErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListProxyResourcesExpandedMethod][0] = test.listProxyResourceResult
+		testApi.ArgsOut[ListProxyResourcesExpandedMethod][1] = test.totalProxyResourceResult
+		testApi.ArgsOut[ListProxyResourcesExpandedMethod][2] = test.listProxyResourceErr
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(server.URL+API_VERSION_1+"/organizations/%v/proxy-resources", test.filter.Org), nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+		if test.expand != "" {
+			q := req.URL.Query()
+			q.Set("Expand", test.expand)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListProxyResourcesExpandedMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listResponse := ListProxyResourcesExpandedResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleRemoveProxyResource(t *testing.T) {
 	testcases := map[string]struct {
 		// API method args
@@ -1145,3 +1324,127 @@ func TestWorkerHandler_HandleRemoveProxyResource(t *testing.T) {
 		}
 	}
 }
+
+func TestProxyHandler_HandleRequestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	testApi.ArgsOut[GetAuthorizedExternalResourcesMethod][0] = []string{"urn:ews:example:instance1:resource/user"}
+	testApi.ArgsOut[GetAuthorizedExternalResourcesMethod][1] = nil
+	testApi.ArgsOut[GetUserByExternalIdMethod][0] = &api.User{ID: "UserID"}
+	testApi.ArgsOut[GetUserByExternalIdMethod][1] = nil
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanID := "00f067aa0ba902b7"
+	req, err := http.NewRequest(http.MethodGet, proxy.URL+USER_ROOT_URL+"/user", nil)
+	assert.Nil(t, err, "Error in test")
+	req.Header.Set("traceparent", fmt.Sprintf("00-%v-%v-01", traceID, parentSpanID))
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err, "Error in test")
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Error in test")
+
+	// Server spans end after response is written, so wait for proxy span
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	assert.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			if span.SpanContext().TraceID().String() != traceID {
+				continue
+			}
+			name := span.Name()
+			if span.Parent().SpanID().String() == parentSpanID {
+				name = "proxy"
+			}
+			spans[name] = span
+		}
+		_, ok := spans["proxy"]
+		return ok
+	}, time.Second, 10*time.Millisecond, "Error in test")
+
+	testcases := map[string]struct {
+		span       string
+		parentSpan string
+	}{
+		"OKCaseCheckAuthorization": {
+			span:       "proxy.checkAuthorization",
+			parentSpan: "proxy",
+		},
+		"OKCaseWorkerAuthorization": {
+			span:       http.MethodPost + " " + RESOURCE_URL,
+			parentSpan: "proxy.checkAuthorization",
+		},
+		"OKCaseUpstream": {
+			span:       "proxy.upstream",
+			parentSpan: "proxy",
+		},
+		"OKCaseUpstreamRequest": {
+			span:       http.MethodGet + " " + USER_ID_URL,
+			parentSpan: "proxy.upstream",
+		},
+	}
+
+	for n, test := range testcases {
+		span, ok := spans[test.span]
+		if !ok {
+			t.Errorf("Test case %v. Span %v not found", n, test.span)
+			continue
+		}
+		assert.Equal(t, spans[test.parentSpan].SpanContext().SpanID(), span.Parent().SpanID(), "Error in test case %v", n)
+	}
+}
+
+func TestProxyHandler_HandleRequestDecisionLog(t *testing.T) {
+	testcases := map[string]struct {
+		// Manager Results
+		getAuthorizedExternalResourcesResult []string
+		// Manager Errors
+		getAuthorizedExternalResourcesErr error
+		// Expected result
+		expectedStatusCode int
+		expectedEffect     string
+	}{
+		"OKCaseAllow": {
+			getAuthorizedExternalResourcesResult: []string{"urn:ews:example:instance1:resource/user"},
+			expectedStatusCode:                   http.StatusOK,
+			expectedEffect:                       api.DECISION_ALLOW,
+		},
+		"OKCaseDeny": {
+			getAuthorizedExternalResourcesErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedEffect:     api.DECISION_DENY,
+		},
+	}
+
+	testLogger, hook := logtest.NewNullLogger()
+	api.DecisionLog = testLogger
+	defer func() {
+		api.DecisionLog = nil
+	}()
+
+	for n, test := range testcases {
+		hook.Reset()
+		testApi.ArgsOut[GetAuthorizedExternalResourcesMethod][0] = test.getAuthorizedExternalResourcesResult
+		testApi.ArgsOut[GetAuthorizedExternalResourcesMethod][1] = test.getAuthorizedExternalResourcesErr
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = &api.User{ID: "UserID"}
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = nil
+
+		res, err := http.Get(proxy.URL + USER_ROOT_URL + "/user")
+		assert.Nil(t, err, "Error in test case %v", n)
+		res.Body.Close()
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		// Check proxy decision has the user authenticated by worker
+		entry := hook.LastEntry()
+		if entry == nil {
+			t.Errorf("Test case %v. Decision not logged", n)
+			continue
+		}
+		assert.Equal(t, api.DECISION_SOURCE_PROXY, entry.Data["source"], "Error in test case %v", n)
+		assert.Equal(t, test.expectedEffect, entry.Data["effect"], "Error in test case %v", n)
+		assert.Equal(t, authConnector.userID, entry.Data["user"], "Error in test case %v", n)
+	}
+}
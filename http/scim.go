@@ -0,0 +1,869 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// Content type of SCIM requests and responses
+	SCIM_CONTENT_TYPE = "application/scim+json"
+
+	// SCIM schemas
+	SCIM_USER_SCHEMA          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIM_GROUP_SCHEMA         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIM_FOULKON_USER_SCHEMA  = "urn:ietf:params:scim:schemas:extension:foulkon:2.0:User"
+	SCIM_LIST_RESPONSE_SCHEMA = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIM_PATCH_OP_SCHEMA      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIM_ERROR_SCHEMA         = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// SCIM error types
+	SCIM_INVALID_FILTER = "invalidFilter"
+	SCIM_INVALID_PATH   = "invalidPath"
+	SCIM_INVALID_SYNTAX = "invalidSyntax"
+	SCIM_INVALID_VALUE  = "invalidValue"
+	SCIM_MUTABILITY     = "mutability"
+	SCIM_UNIQUENESS     = "uniqueness"
+
+	// SCIM patch operations
+	SCIM_OP_ADD     = "add"
+	SCIM_OP_REMOVE  = "remove"
+	SCIM_OP_REPLACE = "replace"
+
+	// Reason of users disabled by a provisioning client
+	SCIM_INACTIVE_REASON = "Deactivated by SCIM provisioning"
+)
+
+var (
+	// Only equality filters are supported, e.g. userName eq "john"
+	rScimFilter = regexp.MustCompile(`^\s*([\w.:]+)\s+(?i:eq)\s+"([^"]*)"\s*$`)
+	// Path of a single member, e.g. members[value eq "john"]
+	rScimMemberPath = regexp.MustCompile(`^(?i:members)\[\s*(?i:value)\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+)
+
+// REQUESTS AND RESPONSES
+
+type ScimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type ScimUserExtension struct {
+	Path       string            `json:"path,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type ScimUser struct {
+	Schemas  []string           `json:"schemas"`
+	ID       string             `json:"id,omitempty"`
+	UserName string             `json:"userName"`
+	Active   *bool              `json:"active,omitempty"`
+	Foulkon  *ScimUserExtension `json:"urn:ietf:params:scim:schemas:extension:foulkon:2.0:User,omitempty"`
+	Meta     *ScimMeta          `json:"meta,omitempty"`
+}
+
+type ScimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+type ScimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []ScimMember `json:"members,omitempty"`
+	Meta        *ScimMeta    `json:"meta,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// scimRequestError is an invalid parameter error with the SCIM error type that describes it
+type scimRequestError struct {
+	scimType string
+	apiError *api.Error
+}
+
+func (e *scimRequestError) Error() string {
+	return e.apiError.Error()
+}
+
+func newScimRequestError(scimType string, message string) *scimRequestError {
+	return &scimRequestError{
+		scimType: scimType,
+		apiError: &api.Error{
+			Code:    api.INVALID_PARAMETER_ERROR,
+			Message: message,
+		},
+	}
+}
+
+// USER HANDLERS
+
+func (wh *WorkerHandler) HandleScimAddUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Process request
+	request := &ScimUser{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, nil, request)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, &scimRequestError{scimType: SCIM_INVALID_SYNTAX, apiError: apiErr}, http.StatusBadRequest)
+		return
+	}
+	path := "/"
+	var attributes map[string]string
+	if request.Foulkon != nil {
+		if request.Foulkon.Path != "" {
+			path = request.Foulkon.Path
+		}
+		attributes = request.Foulkon.Attributes
+	}
+
+	// Call user API to create user
+	user, err := wh.worker.UserApi.AddUser(requestInfo, request.UserName, path, attributes)
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	// Inactive users are created disabled
+	if request.Active != nil && !*request.Active {
+		suspendedUser, err := wh.worker.UserApi.SuspendUser(requestInfo, user.ExternalID, SCIM_INACTIVE_REASON)
+		if err != nil {
+			// Enabled user isn't kept, so the request can be retried
+			if removeErr := wh.worker.UserApi.RemoveUser(requestInfo, user.ExternalID); removeErr != nil {
+				api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, removeErr.(*api.Error))
+			}
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		user = suspendedUser
+	}
+	wh.processScimResponse(r, w, requestInfo, newScimUser(user), nil, http.StatusCreated)
+}
+
+func (wh *WorkerHandler) HandleScimGetUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to get user
+	user, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, ps.ByName(SCIM_ID))
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	wh.processScimResponse(r, w, requestInfo, newScimUser(user), nil, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleScimListUsers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	startIndex, count, err := getScimPagination(r)
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	response := newScimListResponse(startIndex)
+
+	// Filter users by userName, that is the user external identifier
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		userName, err := getScimFilterValue(filter, "userName")
+		if err != nil {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		user, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, userName)
+		if err != nil && !isApiErrorCode(err, api.USER_BY_EXTERNAL_ID_NOT_FOUND) {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		if user != nil {
+			response.TotalResults = 1
+			if startIndex == 1 && count > 0 {
+				response.Resources = append(response.Resources, newScimUser(user))
+			}
+		}
+		response.ItemsPerPage = len(response.Resources)
+		wh.processScimResponse(r, w, requestInfo, response, nil, http.StatusOK)
+		return
+	}
+
+	// Call user API to list users
+	externalIDs, total, err := wh.worker.UserApi.ListUsers(requestInfo, &api.Filter{
+		Offset: startIndex - 1,
+		Limit:  getScimLimit(count),
+	})
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	response.TotalResults = total
+	if count > 0 {
+		for _, externalID := range externalIDs {
+			user, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, externalID)
+			if err != nil {
+				wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+				return
+			}
+			response.Resources = append(response.Resources, newScimUser(user))
+		}
+	}
+	response.ItemsPerPage = len(response.Resources)
+	wh.processScimResponse(r, w, requestInfo, response, nil, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleScimUpdateUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &ScimPatchRequest{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, &scimRequestError{scimType: SCIM_INVALID_SYNTAX, apiError: apiErr}, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to get user
+	user, err := wh.worker.UserApi.GetUserByExternalID(requestInfo, ps.ByName(SCIM_ID))
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	// Only user path, attributes and state can change
+	newPath := user.Path
+	var newAttributes map[string]string
+	newActive := user.Enabled
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != SCIM_OP_ADD && op != SCIM_OP_REPLACE {
+			wh.processScimResponse(r, w, requestInfo, nil,
+				newScimRequestError(SCIM_INVALID_SYNTAX, fmt.Sprintf("Unsupported operation %v", operation.Op)), http.StatusBadRequest)
+			return
+		}
+		values, err := getScimPatchValues(operation)
+		if err != nil {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		for attribute, value := range values {
+			switch {
+			case strings.EqualFold(attribute, "active"):
+				if json.Unmarshal(value, &newActive) != nil {
+					err = newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid value of attribute %v", attribute))
+				}
+			case strings.EqualFold(attribute, "userName"):
+				var userName string
+				if json.Unmarshal(value, &userName) != nil || userName != user.ExternalID {
+					err = newScimRequestError(SCIM_MUTABILITY, "Attribute userName can't be changed")
+				}
+			case strings.EqualFold(attribute, SCIM_FOULKON_USER_SCHEMA):
+				extension := ScimUserExtension{}
+				if json.Unmarshal(value, &extension) != nil {
+					err = newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid value of attribute %v", attribute))
+				} else {
+					if extension.Path != "" {
+						newPath = extension.Path
+					}
+					if extension.Attributes != nil {
+						newAttributes = extension.Attributes
+					}
+				}
+			case strings.EqualFold(attribute, SCIM_FOULKON_USER_SCHEMA+":path"):
+				if json.Unmarshal(value, &newPath) != nil {
+					err = newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid value of attribute %v", attribute))
+				}
+			case strings.EqualFold(attribute, SCIM_FOULKON_USER_SCHEMA+":attributes"):
+				if json.Unmarshal(value, &newAttributes) != nil || newAttributes == nil {
+					err = newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid value of attribute %v", attribute))
+				}
+			default:
+				err = newScimRequestError(SCIM_INVALID_PATH, fmt.Sprintf("Unsupported attribute %v", attribute))
+			}
+			if err != nil {
+				wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	oldUser := user
+	updated := newPath != user.Path || newAttributes != nil
+	if updated {
+		// Call user API to update user
+		user, err = wh.worker.UserApi.UpdateUser(requestInfo, user.ExternalID, newPath, newAttributes)
+		if err != nil {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+	}
+	if newActive != user.Enabled {
+		// Call user API to disable or enable user
+		var changedUser *api.User
+		if newActive {
+			changedUser, err = wh.worker.UserApi.ReactivateUser(requestInfo, user.ExternalID)
+		} else {
+			changedUser, err = wh.worker.UserApi.SuspendUser(requestInfo, user.ExternalID, SCIM_INACTIVE_REASON)
+		}
+		if err != nil {
+			// Update isn't kept without the change of state, so the request can be retried
+			if updated {
+				// Nil attributes would keep the updated ones
+				oldAttributes := oldUser.Attributes
+				if oldAttributes == nil {
+					oldAttributes = map[string]string{}
+				}
+				if _, updateErr := wh.worker.UserApi.UpdateUser(requestInfo, oldUser.ExternalID, oldUser.Path, oldAttributes); updateErr != nil {
+					api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, updateErr.(*api.Error))
+				}
+			}
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		user = changedUser
+	}
+	wh.processScimResponse(r, w, requestInfo, newScimUser(user), nil, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleScimRemoveUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to delete user
+	err := wh.worker.UserApi.RemoveUser(requestInfo, ps.ByName(SCIM_ID))
+	wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+// GROUP HANDLERS
+
+func (wh *WorkerHandler) HandleScimAddGroup(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Process request
+	request := &ScimGroup{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, nil, request)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, &scimRequestError{scimType: SCIM_INVALID_SYNTAX, apiError: apiErr}, http.StatusBadRequest)
+		return
+	}
+
+	// Call group API to create group with its members
+	org := wh.worker.ScimOrg
+	group, err := wh.worker.GroupApi.AddGroup(requestInfo, org, request.DisplayName, "/", nil)
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	var members []string
+	for _, member := range request.Members {
+		members = append(members, member.Value)
+	}
+	if err := wh.addScimGroupMembers(requestInfo, org, group.Name, members); err != nil {
+		// Group isn't kept without all its members, so the request can be retried
+		if removeErr := wh.worker.GroupApi.RemoveGroup(requestInfo, org, group.Name); removeErr != nil {
+			api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, removeErr.(*api.Error))
+		}
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	wh.processScimResponse(r, w, requestInfo, newScimGroup(group, members), nil, http.StatusCreated)
+}
+
+func (wh *WorkerHandler) HandleScimGetGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call group API to get group
+	group, err := wh.getScimGroupByID(requestInfo, ps.ByName(SCIM_ID))
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	response, err := wh.getScimGroup(r, requestInfo, group)
+	wh.processScimResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleScimListGroups(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+	startIndex, count, err := getScimPagination(r)
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	org := wh.worker.ScimOrg
+	response := newScimListResponse(startIndex)
+
+	// Filter groups by displayName, that is the group name
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		displayName, err := getScimFilterValue(filter, "displayName")
+		if err != nil {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		group, err := wh.worker.GroupApi.GetGroupByName(requestInfo, org, displayName)
+		if err != nil && !isApiErrorCode(err, api.GROUP_BY_ORG_AND_NAME_NOT_FOUND) {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+		if group != nil {
+			response.TotalResults = 1
+			if startIndex == 1 && count > 0 {
+				scimGroup, err := wh.getScimGroup(r, requestInfo, group)
+				if err != nil {
+					wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+					return
+				}
+				response.Resources = append(response.Resources, scimGroup)
+			}
+		}
+		response.ItemsPerPage = len(response.Resources)
+		wh.processScimResponse(r, w, requestInfo, response, nil, http.StatusOK)
+		return
+	}
+
+	// Call group API to list groups of SCIM organization
+	groupIDs, total, err := wh.worker.GroupApi.ListGroups(requestInfo, &api.Filter{
+		Org:    org,
+		Offset: startIndex - 1,
+		Limit:  getScimLimit(count),
+	})
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	response.TotalResults = total
+	if count > 0 {
+		for _, groupID := range groupIDs {
+			group, err := wh.worker.GroupApi.GetGroupByName(requestInfo, groupID.Org, groupID.Name)
+			if err != nil {
+				wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+				return
+			}
+			scimGroup, err := wh.getScimGroup(r, requestInfo, group)
+			if err != nil {
+				wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+				return
+			}
+			response.Resources = append(response.Resources, scimGroup)
+		}
+	}
+	response.ItemsPerPage = len(response.Resources)
+	wh.processScimResponse(r, w, requestInfo, response, nil, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleScimUpdateGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &ScimPatchRequest{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, &scimRequestError{scimType: SCIM_INVALID_SYNTAX, apiError: apiErr}, http.StatusBadRequest)
+		return
+	}
+
+	// Call group API to get group
+	group, err := wh.getScimGroupByID(requestInfo, ps.ByName(SCIM_ID))
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+
+	// Apply operations in order
+	for _, operation := range request.Operations {
+		if group, err = wh.applyScimGroupOperation(requestInfo, group, operation); err != nil {
+			wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+			return
+		}
+	}
+	wh.processScimResponse(r, w, requestInfo, nil, nil, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleScimRemoveGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call group API to delete group
+	group, err := wh.getScimGroupByID(requestInfo, ps.ByName(SCIM_ID))
+	if err != nil {
+		wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusBadRequest)
+		return
+	}
+	err = wh.worker.GroupApi.RemoveGroup(requestInfo, group.Org, group.Name)
+	wh.processScimResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+// PRIVATE HELPER METHODS
+
+// processScimResponse writes response, or err as a SCIM error, with SCIM content type
+func (wh *WorkerHandler) processScimResponse(r *http.Request, w http.ResponseWriter, requestInfo api.RequestInfo, response interface{}, err error, responseCode int) {
+	if err != nil {
+		var apiError *api.Error
+		var scimType string
+		switch e := err.(type) {
+		case *scimRequestError:
+			apiError = e.apiError
+			scimType = e.scimType
+		case *api.Error:
+			apiError = e
+		default:
+			apiError = &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: err.Error(),
+			}
+		}
+		api.LogOperationError(requestInfo.RequestID, requestInfo.Identifier, apiError)
+		statusCode := getStatusCode(apiError)
+		if scimType == "" {
+			switch statusCode {
+			case http.StatusConflict:
+				scimType = SCIM_UNIQUENESS
+			case http.StatusBadRequest:
+				scimType = SCIM_INVALID_VALUE
+			}
+		}
+		scimError := &ScimError{
+			Schemas:  []string{SCIM_ERROR_SCHEMA},
+			Status:   strconv.Itoa(statusCode),
+			ScimType: scimType,
+			Detail:   apiError.Message,
+		}
+		writeHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, statusCode, SCIM_CONTENT_TYPE, scimError)
+		return
+	}
+
+	// Write response data if everything is ok
+	writeHttpResponse(r, w, requestInfo.RequestID, requestInfo.Identifier, responseCode, SCIM_CONTENT_TYPE, response)
+}
+
+// getScimGroupByID retrieves group of SCIM organization by its identifier, that is kept when group is renamed
+func (wh *WorkerHandler) getScimGroupByID(requestInfo api.RequestInfo, id string) (*api.Group, error) {
+	group, err := wh.worker.GroupApi.GetGroupByID(requestInfo, id)
+	if err != nil {
+		return nil, err
+	}
+	if group.Org != wh.worker.ScimOrg {
+		return nil, &api.Error{
+			Code:    api.GROUP_BY_ID_NOT_FOUND,
+			Message: fmt.Sprintf("Group with id %v not found", id),
+		}
+	}
+	return group, nil
+}
+
+// getScimGroup returns group as SCIM resource, with its members unless they are excluded in request
+func (wh *WorkerHandler) getScimGroup(r *http.Request, requestInfo api.RequestInfo, group *api.Group) (*ScimGroup, error) {
+	for _, attribute := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return newScimGroup(group, nil), nil
+		}
+	}
+	members, err := wh.listScimGroupMembers(requestInfo, group.Org, group.Name)
+	if err != nil {
+		return nil, err
+	}
+	return newScimGroup(group, members), nil
+}
+
+// listScimGroupMembers returns external identifiers of all group members
+func (wh *WorkerHandler) listScimGroupMembers(requestInfo api.RequestInfo, org string, name string) ([]string, error) {
+	var externalIDs []string
+	for {
+		members, total, err := wh.worker.GroupApi.ListMembers(requestInfo, &api.Filter{
+			Org:       org,
+			GroupName: name,
+			Offset:    len(externalIDs),
+			Limit:     api.MAX_LIMIT_SIZE,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			externalIDs = append(externalIDs, member.User)
+		}
+		if len(members) == 0 || len(externalIDs) >= total {
+			return externalIDs, nil
+		}
+	}
+}
+
+// addScimGroupMembers adds users to group, ignoring users that are already members
+func (wh *WorkerHandler) addScimGroupMembers(requestInfo api.RequestInfo, org string, name string, externalIDs []string) error {
+	for _, externalID := range externalIDs {
+		err := wh.worker.GroupApi.AddMember(requestInfo, externalID, name, org)
+		if err != nil && !isApiErrorCode(err, api.USER_IS_ALREADY_A_MEMBER_OF_GROUP) {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeScimGroupMembers removes users from group, ignoring users that aren't members
+func (wh *WorkerHandler) removeScimGroupMembers(requestInfo api.RequestInfo, org string, name string, externalIDs []string) error {
+	for _, externalID := range externalIDs {
+		err := wh.worker.GroupApi.RemoveMember(requestInfo, externalID, name, org)
+		if err != nil && !isApiErrorCode(err, api.USER_IS_NOT_A_MEMBER_OF_GROUP) {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyScimGroupOperation applies a patch operation to group, and returns the group updated
+func (wh *WorkerHandler) applyScimGroupOperation(requestInfo api.RequestInfo, group *api.Group, operation ScimPatchOperation) (*api.Group, error) {
+	op := strings.ToLower(operation.Op)
+	if op != SCIM_OP_ADD && op != SCIM_OP_REMOVE && op != SCIM_OP_REPLACE {
+		return nil, newScimRequestError(SCIM_INVALID_SYNTAX, fmt.Sprintf("Unsupported operation %v", operation.Op))
+	}
+
+	// Remove a single member
+	if member := rScimMemberPath.FindStringSubmatch(operation.Path); member != nil {
+		if op != SCIM_OP_REMOVE {
+			return nil, newScimRequestError(SCIM_INVALID_PATH, fmt.Sprintf("Unsupported operation %v of path %v", operation.Op, operation.Path))
+		}
+		return group, wh.removeScimGroupMembers(requestInfo, group.Org, group.Name, []string{member[1]})
+	}
+	if op == SCIM_OP_REMOVE && operation.Path == "" {
+		return nil, newScimRequestError(SCIM_INVALID_PATH, "Path is required to remove")
+	}
+
+	values, err := getScimPatchValues(operation)
+	if err != nil {
+		return nil, err
+	}
+	for attribute, value := range values {
+		switch {
+		case strings.EqualFold(attribute, "displayName"):
+			var displayName string
+			if op == SCIM_OP_REMOVE || json.Unmarshal(value, &displayName) != nil {
+				return nil, newScimRequestError(SCIM_INVALID_VALUE, "Attribute displayName must be a group name")
+			}
+			if displayName != group.Name {
+				// Call group API to rename group
+				if group, err = wh.worker.GroupApi.UpdateGroup(requestInfo, group.Org, group.Name, displayName, group.Path, nil); err != nil {
+					return nil, err
+				}
+			}
+		case strings.EqualFold(attribute, "members"):
+			var members []ScimMember
+			if len(value) > 0 && json.Unmarshal(value, &members) != nil {
+				return nil, newScimRequestError(SCIM_INVALID_VALUE, "Attribute members must be a list of members")
+			}
+			var externalIDs []string
+			for _, member := range members {
+				externalIDs = append(externalIDs, member.Value)
+			}
+			if err := wh.patchScimGroupMembers(requestInfo, group, op, externalIDs); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, newScimRequestError(SCIM_INVALID_PATH, fmt.Sprintf("Unsupported attribute %v", attribute))
+		}
+	}
+	return group, nil
+}
+
+// patchScimGroupMembers adds or removes members of group. Without members, remove operation removes all
+// members. Replace operation removes members that aren't in externalIDs.
+func (wh *WorkerHandler) patchScimGroupMembers(requestInfo api.RequestInfo, group *api.Group, op string, externalIDs []string) error {
+	if op == SCIM_OP_ADD {
+		return wh.addScimGroupMembers(requestInfo, group.Org, group.Name, externalIDs)
+	}
+	if op == SCIM_OP_REMOVE && len(externalIDs) > 0 {
+		return wh.removeScimGroupMembers(requestInfo, group.Org, group.Name, externalIDs)
+	}
+
+	current, err := wh.listScimGroupMembers(requestInfo, group.Org, group.Name)
+	if err != nil {
+		return err
+	}
+	var removed []string
+	for _, member := range current {
+		if op == SCIM_OP_REMOVE || !isContainedIn(member, externalIDs) {
+			removed = append(removed, member)
+		}
+	}
+	if err := wh.removeScimGroupMembers(requestInfo, group.Org, group.Name, removed); err != nil {
+		return err
+	}
+	if op == SCIM_OP_REPLACE {
+		var added []string
+		for _, externalID := range externalIDs {
+			if !isContainedIn(externalID, current) {
+				added = append(added, externalID)
+			}
+		}
+		return wh.addScimGroupMembers(requestInfo, group.Org, group.Name, added)
+	}
+	return nil
+}
+
+func newScimUser(user *api.User) *ScimUser {
+	active := user.Enabled
+	return &ScimUser{
+		Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+		ID:       user.ExternalID,
+		UserName: user.ExternalID,
+		Active:   &active,
+		Foulkon: &ScimUserExtension{
+			Path:       user.Path,
+			Attributes: user.Attributes,
+		},
+		Meta: &ScimMeta{
+			ResourceType: "User",
+			Created:      user.CreateAt,
+			LastModified: user.UpdateAt,
+			Location:     SCIM_USERS_URL + "/" + user.ExternalID,
+		},
+	}
+}
+
+func newScimGroup(group *api.Group, members []string) *ScimGroup {
+	scimGroup := &ScimGroup{
+		Schemas:     []string{SCIM_GROUP_SCHEMA},
+		ID:          group.ID,
+		DisplayName: group.Name,
+		Meta: &ScimMeta{
+			ResourceType: "Group",
+			Created:      group.CreateAt,
+			LastModified: group.UpdateAt,
+			Location:     SCIM_GROUPS_URL + "/" + group.ID,
+		},
+	}
+	for _, member := range members {
+		scimGroup.Members = append(scimGroup.Members, ScimMember{
+			Value:   member,
+			Display: member,
+		})
+	}
+	return scimGroup
+}
+
+func newScimListResponse(startIndex int) *ScimListResponse {
+	return &ScimListResponse{
+		Schemas:    []string{SCIM_LIST_RESPONSE_SCHEMA},
+		StartIndex: startIndex,
+		Resources:  []interface{}{},
+	}
+}
+
+// getScimPagination returns 1-based startIndex and count query params. Count is limited to max limit size.
+func getScimPagination(r *http.Request) (int, int, error) {
+	startIndex := 1
+	if value := r.URL.Query().Get("startIndex"); value != "" {
+		index, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid parameter: startIndex %v", value))
+		}
+		if index > 1 {
+			startIndex = index
+		}
+	}
+	count := api.DEFAULT_LIMIT_SIZE
+	if value := r.URL.Query().Get("count"); value != "" {
+		var err error
+		count, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, newScimRequestError(SCIM_INVALID_VALUE, fmt.Sprintf("Invalid parameter: count %v", value))
+		}
+	}
+	if count < 0 {
+		count = 0
+	} else if count > api.MAX_LIMIT_SIZE {
+		count = api.MAX_LIMIT_SIZE
+	}
+	return startIndex, count, nil
+}
+
+// getScimLimit returns the limit to list resources, at least one to know the total without resources
+func getScimLimit(count int) int {
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// getScimFilterValue returns the value of an equality filter of attribute
+func getScimFilterValue(filter string, attribute string) (string, error) {
+	match := rScimFilter.FindStringSubmatch(filter)
+	if match == nil || !strings.EqualFold(match[1], attribute) {
+		return "", newScimRequestError(SCIM_INVALID_FILTER,
+			fmt.Sprintf("Unsupported filter %v, only %v eq filter is allowed", filter, attribute))
+	}
+	return match[2], nil
+}
+
+// getScimPatchValues returns attribute values of a patch operation, by attribute path
+func getScimPatchValues(operation ScimPatchOperation) (map[string]json.RawMessage, error) {
+	if operation.Path != "" {
+		return map[string]json.RawMessage{operation.Path: operation.Value}, nil
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(operation.Value, &values); err != nil {
+		return nil, newScimRequestError(SCIM_INVALID_VALUE, "Value of operation without path must be an object")
+	}
+	return values, nil
+}
+
+func isApiErrorCode(err error, code string) bool {
+	apiError, ok := err.(*api.Error)
+	return ok && apiError.Code == code
+}
+
+func isContainedIn(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1889 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleScimAddUser(t *testing.T) {
+	now := time.Now().UTC()
+	active := true
+	inactive := false
+	testcases := map[string]struct {
+		// API method args
+		request            *ScimUser
+		expectedPath       string
+		expectedAttributes map[string]string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *ScimUser
+		expectedError      ScimError
+		// User removed when it can't be suspended
+		expectedRemovedUser string
+		// Manager Results
+		addUserResult     *api.User
+		suspendUserResult *api.User
+		// Manager Errors
+		addUserErr     error
+		suspendUserErr error
+		removeUserErr  error
+	}{
+		"OkCase": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/employees/",
+				},
+			},
+			expectedPath:       "/employees/",
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/employees/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/employees/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"OkCaseAttributes": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				UserName: "john@example.com",
+				Foulkon: &ScimUserExtension{
+					Path: "/employees/",
+					Attributes: map[string]string{
+						"department": "backend",
+					},
+				},
+			},
+			expectedPath: "/employees/",
+			expectedAttributes: map[string]string{
+				"department": "backend",
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/employees/",
+					Attributes: map[string]string{
+						"department": "backend",
+					},
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/employees/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"department": "backend",
+				},
+				Enabled: true,
+			},
+		},
+		"OkCaseDefaultPath": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_SYNTAX,
+				Detail:   "EOF",
+			},
+		},
+		"OkCaseInactiveUser": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+				Active:   &inactive,
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &inactive,
+				Foulkon: &ScimUserExtension{
+					Path: "/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			suspendUserResult: &api.User{
+				ID:             "UserID",
+				ExternalID:     "john@example.com",
+				Path:           "/",
+				Urn:            "urn",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: SCIM_INACTIVE_REASON,
+			},
+		},
+		"ErrorCaseInactiveUserSuspendError": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+				Active:   &inactive,
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			expectedRemovedUser: "john@example.com",
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseInactiveUserRemoveError": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+				Active:   &inactive,
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			expectedRemovedUser: "john@example.com",
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			removeUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseUserAlreadyExist": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusConflict,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "409",
+				ScimType: SCIM_UNIQUENESS,
+				Detail:   "User already exist",
+			},
+			addUserErr: &api.Error{
+				Code:    api.USER_ALREADY_EXIST,
+				Message: "User already exist",
+			},
+		},
+		"ErrorCaseInvalidParameterError": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john doe",
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_VALUE,
+				Detail:   "Invalid parameter: externalId john doe",
+			},
+			addUserErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: externalId john doe",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			addUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			request: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA},
+				UserName: "john@example.com",
+			},
+			expectedPath:       "/",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "500",
+				Detail:  "Error",
+			},
+			addUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[AddUserMethod] = make([]interface{}, 4)
+		testApi.ArgsIn[SuspendUserMethod] = make([]interface{}, 3)
+		testApi.ArgsIn[RemoveUserMethod] = make([]interface{}, 2)
+		testApi.ArgsOut[AddUserMethod][0] = test.addUserResult
+		testApi.ArgsOut[AddUserMethod][1] = test.addUserErr
+		testApi.ArgsOut[SuspendUserMethod][0] = test.suspendUserResult
+		testApi.ArgsOut[SuspendUserMethod][1] = test.suspendUserErr
+		testApi.ArgsOut[RemoveUserMethod][0] = test.removeUserErr
+
+		body := bytes.NewBuffer([]byte{})
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+SCIM_USERS_URL, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", SCIM_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.expectedPath != "" {
+			// Check received parameters
+			assert.Equal(t, test.request.UserName, testApi.ArgsIn[AddUserMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedPath, testApi.ArgsIn[AddUserMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.expectedAttributes, testApi.ArgsIn[AddUserMethod][3], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[AddUserMethod][1], "Error in test case %v", n)
+		}
+		if test.suspendUserResult != nil || test.suspendUserErr != nil {
+			assert.Equal(t, test.request.UserName, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+			assert.Equal(t, SCIM_INACTIVE_REASON, testApi.ArgsIn[SuspendUserMethod][2], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+		}
+		if test.expectedRemovedUser != "" {
+			assert.Equal(t, test.expectedRemovedUser, testApi.ArgsIn[RemoveUserMethod][1], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[RemoveUserMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code and content type
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+		assert.Equal(t, SCIM_CONTENT_TYPE, res.Header.Get("Content-Type"), "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := &ScimUser{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimGetUser(t *testing.T) {
+	now := time.Now().UTC()
+	active := true
+	testcases := map[string]struct {
+		// API method args
+		id string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *ScimUser
+		expectedError      ScimError
+		// Manager Results
+		getUserByExternalIdResult *api.User
+		// Manager Errors
+		getUserByExternalIdErr error
+	}{
+		"OkCase": {
+			id:                 "john@example.com",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/path/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			getUserByExternalIdResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			id:                 "john@example.com",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			getUserByExternalIdErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = test.getUserByExternalIdResult
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = test.getUserByExternalIdErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+SCIM_USERS_URL+"/"+test.id, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.id, testApi.ArgsIn[GetUserByExternalIdMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &ScimUser{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimListUsers(t *testing.T) {
+	now := time.Now().UTC()
+	user := &api.User{
+		ID:         "UserID",
+		ExternalID: "john@example.com",
+		Path:       "/path/",
+		Urn:        "urn",
+		CreateAt:   now,
+		UpdateAt:   now,
+		Enabled:    true,
+	}
+	testcases := map[string]struct {
+		// API method args
+		query          url.Values
+		expectedFilter *api.Filter
+		// Expected result
+		expectedStatusCode   int
+		expectedTotalResults int
+		expectedStartIndex   int
+		expectedUserNames    []string
+		expectedError        ScimError
+		// Manager Results
+		listUsersResult           []string
+		totalListUsersResult      int
+		getUserByExternalIdResult *api.User
+		// Manager Errors
+		listUsersErr           error
+		getUserByExternalIdErr error
+	}{
+		"OkCase": {
+			query: url.Values{
+				"startIndex": []string{"11"},
+				"count":      []string{"10"},
+			},
+			expectedFilter: &api.Filter{
+				Offset: 10,
+				Limit:  10,
+			},
+			expectedStatusCode:        http.StatusOK,
+			expectedTotalResults:      12,
+			expectedStartIndex:        11,
+			expectedUserNames:         []string{"john@example.com", "john@example.com"},
+			listUsersResult:           []string{"john@example.com", "john@example.com"},
+			totalListUsersResult:      12,
+			getUserByExternalIdResult: user,
+		},
+		"OkCaseDefaultPagination": {
+			expectedFilter: &api.Filter{
+				Offset: 0,
+				Limit:  api.DEFAULT_LIMIT_SIZE,
+			},
+			expectedStatusCode:        http.StatusOK,
+			expectedTotalResults:      1,
+			expectedStartIndex:        1,
+			expectedUserNames:         []string{"john@example.com"},
+			listUsersResult:           []string{"john@example.com"},
+			totalListUsersResult:      1,
+			getUserByExternalIdResult: user,
+		},
+		"OkCaseCountZero": {
+			query: url.Values{
+				"count": []string{"0"},
+			},
+			expectedFilter: &api.Filter{
+				Offset: 0,
+				Limit:  1,
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedTotalResults: 5,
+			expectedStartIndex:   1,
+			listUsersResult:      []string{"john@example.com"},
+			totalListUsersResult: 5,
+		},
+		"OkCaseFilter": {
+			query: url.Values{
+				"filter": []string{`userName eq "john@example.com"`},
+			},
+			expectedStatusCode:        http.StatusOK,
+			expectedTotalResults:      1,
+			expectedStartIndex:        1,
+			expectedUserNames:         []string{"john@example.com"},
+			getUserByExternalIdResult: user,
+		},
+		"OkCaseFilterUserNotFound": {
+			query: url.Values{
+				"filter": []string{`userName eq "john@example.com"`},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedStartIndex: 1,
+			getUserByExternalIdErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+		"ErrorCaseUnsupportedFilter": {
+			query: url.Values{
+				"filter": []string{`emails co "example.com"`},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_FILTER,
+				Detail:   `Unsupported filter emails co "example.com", only userName eq filter is allowed`,
+			},
+		},
+		"ErrorCaseInvalidStartIndex": {
+			query: url.Values{
+				"startIndex": []string{"first"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_VALUE,
+				Detail:   "Invalid parameter: startIndex first",
+			},
+		},
+		"ErrorCaseListUsersError": {
+			expectedFilter: &api.Filter{
+				Offset: 0,
+				Limit:  api.DEFAULT_LIMIT_SIZE,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			listUsersErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[ListUsersMethod] = make([]interface{}, 2)
+		testApi.ArgsOut[ListUsersMethod][0] = test.listUsersResult
+		testApi.ArgsOut[ListUsersMethod][1] = test.totalListUsersResult
+		testApi.ArgsOut[ListUsersMethod][2] = test.listUsersErr
+		testApi.ArgsOut[ListUsersMethod][3] = nil
+		testApi.ArgsOut[ListUsersMethod][4] = nil
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = test.getUserByExternalIdResult
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = test.getUserByExternalIdErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+SCIM_USERS_URL+"?"+test.query.Encode(), nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		if test.expectedFilter != nil {
+			assert.Equal(t, test.expectedFilter, testApi.ArgsIn[ListUsersMethod][1], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[ListUsersMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := struct {
+				ScimListResponse
+				Resources []ScimUser `json:"Resources"`
+			}{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, []string{SCIM_LIST_RESPONSE_SCHEMA}, response.Schemas, "Error in test case %v", n)
+			assert.Equal(t, test.expectedTotalResults, response.TotalResults, "Error in test case %v", n)
+			assert.Equal(t, test.expectedStartIndex, response.StartIndex, "Error in test case %v", n)
+			assert.Equal(t, len(test.expectedUserNames), response.ItemsPerPage, "Error in test case %v", n)
+			var userNames []string
+			for _, user := range response.Resources {
+				userNames = append(userNames, user.UserName)
+			}
+			assert.Equal(t, test.expectedUserNames, userNames, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimUpdateUser(t *testing.T) {
+	now := time.Now().UTC()
+	active := true
+	inactive := false
+	testcases := map[string]struct {
+		// API method args
+		id      string
+		request string
+		// Expected result
+		expectedStatusCode int
+		// Path of last update, which restores previous path if state can't change
+		expectedNewPath  string
+		expectedResponse *ScimUser
+		expectedError    ScimError
+		// Manager Results
+		getUserByExternalIdResult *api.User
+		updateUserResult          *api.User
+		suspendUserResult         *api.User
+		reactivateUserResult      *api.User
+		// Manager Errors
+		getUserByExternalIdErr error
+		updateUserErr          error
+		suspendUserErr         error
+		reactivateUserErr      error
+	}{
+		"OkCaseReplacePath": {
+			id: "john@example.com",
+			request: `{"schemas": ["` + SCIM_PATCH_OP_SCHEMA + `"], "Operations": [
+				{"op": "Replace", "path": "` + SCIM_FOULKON_USER_SCHEMA + `:path", "value": "/new/"}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedNewPath:    "/new/",
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/new/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			updateUserResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/new/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"OkCaseReplaceWithoutPath": {
+			id: "john@example.com",
+			request: `{"schemas": ["` + SCIM_PATCH_OP_SCHEMA + `"], "Operations": [
+				{"op": "replace", "value": {"active": true, "userName": "john@example.com"}}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/path/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			id:                 "john@example.com",
+			request:            "{",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_SYNTAX,
+				Detail:   "unexpected EOF",
+			},
+		},
+		"ErrorCaseUserNotFound": {
+			id:                 "john@example.com",
+			request:            `{"Operations": []}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			getUserByExternalIdErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+		"OkCaseDeactivateUser": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "replace", "path": "active", "value": false}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &inactive,
+				Foulkon: &ScimUserExtension{
+					Path: "/path/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			suspendUserResult: &api.User{
+				ExternalID:     "john@example.com",
+				Path:           "/path/",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: SCIM_INACTIVE_REASON,
+			},
+		},
+		"OkCaseReactivateUser": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "replace", "value": {"active": true}}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimUser{
+				Schemas:  []string{SCIM_USER_SCHEMA, SCIM_FOULKON_USER_SCHEMA},
+				ID:       "john@example.com",
+				UserName: "john@example.com",
+				Active:   &active,
+				Foulkon: &ScimUserExtension{
+					Path: "/path/",
+				},
+				Meta: &ScimMeta{
+					ResourceType: "User",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_USERS_URL + "/john@example.com",
+				},
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID:     "john@example.com",
+				Path:           "/path/",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: SCIM_INACTIVE_REASON,
+			},
+			reactivateUserResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseDeactivateUserSuspendError": {
+			id: "john@example.com",
+			request: `{"Operations": [{"op": "replace", "value": {"active": false, "` +
+				SCIM_FOULKON_USER_SCHEMA + `": {"path": "/new/"}}}]}`,
+			expectedStatusCode: http.StatusForbidden,
+			expectedNewPath:    "/path/",
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			updateUserResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/new/",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseChangeUserName": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "replace", "path": "userName", "value": "jane@example.com"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_MUTABILITY,
+				Detail:   "Attribute userName can't be changed",
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUnsupportedAttribute": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "add", "path": "nickName", "value": "john"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_PATH,
+				Detail:   "Unsupported attribute nickName",
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUnsupportedOperation": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "remove", "path": "active"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_SYNTAX,
+				Detail:   "Unsupported operation remove",
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUpdateUserError": {
+			id:                 "john@example.com",
+			request:            `{"Operations": [{"op": "replace", "value": {"` + SCIM_FOULKON_USER_SCHEMA + `": {"path": "invalid"}}}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedNewPath:    "invalid",
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_VALUE,
+				Detail:   "Invalid parameter: path invalid",
+			},
+			getUserByExternalIdResult: &api.User{
+				ExternalID: "john@example.com",
+				Path:       "/path/",
+				Enabled:    true,
+			},
+			updateUserErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: path invalid",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[UpdateUserMethod] = make([]interface{}, 4)
+		testApi.ArgsIn[SuspendUserMethod] = make([]interface{}, 3)
+		testApi.ArgsIn[ReactivateUserMethod] = make([]interface{}, 2)
+		testApi.ArgsOut[GetUserByExternalIdMethod][0] = test.getUserByExternalIdResult
+		testApi.ArgsOut[GetUserByExternalIdMethod][1] = test.getUserByExternalIdErr
+		testApi.ArgsOut[UpdateUserMethod][0] = test.updateUserResult
+		testApi.ArgsOut[UpdateUserMethod][1] = test.updateUserErr
+		testApi.ArgsOut[SuspendUserMethod][0] = test.suspendUserResult
+		testApi.ArgsOut[SuspendUserMethod][1] = test.suspendUserErr
+		testApi.ArgsOut[ReactivateUserMethod][0] = test.reactivateUserResult
+		testApi.ArgsOut[ReactivateUserMethod][1] = test.reactivateUserErr
+
+		req, err := http.NewRequest(http.MethodPatch, server.URL+SCIM_USERS_URL+"/"+test.id, bytes.NewBufferString(test.request))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", SCIM_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		if test.expectedNewPath != "" {
+			assert.Equal(t, test.id, testApi.ArgsIn[UpdateUserMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.expectedNewPath, testApi.ArgsIn[UpdateUserMethod][2], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[UpdateUserMethod][1], "Error in test case %v", n)
+		}
+		if test.suspendUserResult != nil || test.suspendUserErr != nil {
+			assert.Equal(t, test.id, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+		}
+		if test.reactivateUserResult != nil {
+			assert.Equal(t, test.id, testApi.ArgsIn[ReactivateUserMethod][1], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[ReactivateUserMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &ScimUser{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimRemoveUser(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		id string
+		// Expected result
+		expectedStatusCode int
+		expectedError      ScimError
+		// Manager Errors
+		removeUserErr error
+	}{
+		"OkCase": {
+			id:                 "john@example.com",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseUserNotFound": {
+			id:                 "john@example.com",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			removeUserErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsOut[RemoveUserMethod][0] = test.removeUserErr
+
+		req, err := http.NewRequest(http.MethodDelete, server.URL+SCIM_USERS_URL+"/"+test.id, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.id, testApi.ArgsIn[RemoveUserMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		if res.StatusCode != http.StatusNoContent {
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimAddGroup(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		request *ScimGroup
+		// Expected result
+		expectedStatusCode   int
+		expectedMember       interface{}
+		expectedRemovedGroup interface{}
+		expectedResponse     *ScimGroup
+		expectedError        ScimError
+		// Manager Results
+		addGroupResult *api.Group
+		// Manager Errors
+		addGroupErr    error
+		addMemberErr   error
+		removeGroupErr error
+	}{
+		"OkCase": {
+			request: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com"},
+					{Value: "jane@example.com"},
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedMember:     "jane@example.com",
+			expectedResponse: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				ID:          "GroupID",
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com", Display: "john@example.com"},
+					{Value: "jane@example.com", Display: "jane@example.com"},
+				},
+				Meta: &ScimMeta{
+					ResourceType: "Group",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_GROUPS_URL + "/GroupID",
+				},
+			},
+			addGroupResult: &api.Group{
+				ID:       "GroupID",
+				Name:     "backend",
+				Path:     "/",
+				Org:      "scim",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		},
+		"OkCaseMemberAlreadyInGroup": {
+			request: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com"},
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedMember:     "john@example.com",
+			expectedResponse: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				ID:          "GroupID",
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com", Display: "john@example.com"},
+				},
+				Meta: &ScimMeta{
+					ResourceType: "Group",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_GROUPS_URL + "/GroupID",
+				},
+			},
+			addGroupResult: &api.Group{
+				ID:       "GroupID",
+				Name:     "backend",
+				Path:     "/",
+				Org:      "scim",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			addMemberErr: &api.Error{
+				Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: "User is already a member",
+			},
+		},
+		"ErrorCaseGroupAlreadyExist": {
+			request: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				DisplayName: "backend",
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "409",
+				ScimType: SCIM_UNIQUENESS,
+				Detail:   "Group already exist",
+			},
+			addGroupErr: &api.Error{
+				Code:    api.GROUP_ALREADY_EXIST,
+				Message: "Group already exist",
+			},
+		},
+		"ErrorCaseMemberNotFound": {
+			request: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com"},
+				},
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedMember:       "john@example.com",
+			expectedRemovedGroup: "backend",
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			addGroupResult: &api.Group{
+				Name: "backend",
+				Org:  "scim",
+			},
+			addMemberErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+		"ErrorCaseMemberNotFoundRemoveGroupError": {
+			request: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com"},
+				},
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedMember:       "john@example.com",
+			expectedRemovedGroup: "backend",
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			addGroupResult: &api.Group{
+				Name: "backend",
+				Org:  "scim",
+			},
+			addMemberErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+			removeGroupErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[AddMemberMethod] = make([]interface{}, 4)
+		testApi.ArgsIn[RemoveGroupMethod] = make([]interface{}, 3)
+		testApi.ArgsOut[AddGroupMethod][0] = test.addGroupResult
+		testApi.ArgsOut[AddGroupMethod][1] = test.addGroupErr
+		testApi.ArgsOut[AddMemberMethod][0] = test.addMemberErr
+		testApi.ArgsOut[RemoveGroupMethod][0] = test.removeGroupErr
+
+		jsonObject, err := json.Marshal(test.request)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+SCIM_GROUPS_URL, bytes.NewBuffer(jsonObject))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", SCIM_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, "scim", testApi.ArgsIn[AddGroupMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.request.DisplayName, testApi.ArgsIn[AddGroupMethod][2], "Error in test case %v", n)
+		assert.Equal(t, "/", testApi.ArgsIn[AddGroupMethod][3], "Error in test case %v", n)
+		assert.Equal(t, test.expectedMember, testApi.ArgsIn[AddMemberMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedRemovedGroup, testApi.ArgsIn[RemoveGroupMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := &ScimGroup{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimGetGroup(t *testing.T) {
+	now := time.Now().UTC()
+	group := &api.Group{
+		ID:       "GroupID",
+		Name:     "backend",
+		Path:     "/",
+		Org:      "scim",
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	testcases := map[string]struct {
+		// API method args
+		id    string
+		query string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *ScimGroup
+		expectedError      ScimError
+		// Manager Results
+		getGroupByIDResult *api.Group
+		listMembersResult  []api.GroupMembers
+		totalMembersResult int
+		// Manager Errors
+		getGroupByIDErr error
+		listMembersErr  error
+	}{
+		"OkCase": {
+			id:                 "GroupID",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				ID:          "GroupID",
+				DisplayName: "backend",
+				Members: []ScimMember{
+					{Value: "john@example.com", Display: "john@example.com"},
+					{Value: "jane@example.com", Display: "jane@example.com"},
+				},
+				Meta: &ScimMeta{
+					ResourceType: "Group",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_GROUPS_URL + "/GroupID",
+				},
+			},
+			getGroupByIDResult: group,
+			listMembersResult: []api.GroupMembers{
+				{User: "john@example.com"},
+				{User: "jane@example.com"},
+			},
+			totalMembersResult: 2,
+		},
+		"OkCaseExcludedMembers": {
+			id:                 "GroupID",
+			query:              "?excludedAttributes=members",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &ScimGroup{
+				Schemas:     []string{SCIM_GROUP_SCHEMA},
+				ID:          "GroupID",
+				DisplayName: "backend",
+				Meta: &ScimMeta{
+					ResourceType: "Group",
+					Created:      now,
+					LastModified: now,
+					Location:     SCIM_GROUPS_URL + "/GroupID",
+				},
+			},
+			getGroupByIDResult: group,
+			listMembersErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			id:                 "GroupID",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "Group not found",
+			},
+			getGroupByIDErr: &api.Error{
+				Code:    api.GROUP_BY_ID_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseGroupOfOtherOrg": {
+			id:                 "GroupID",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "Group with id GroupID not found",
+			},
+			getGroupByIDResult: &api.Group{
+				ID:   "GroupID",
+				Name: "backend",
+				Path: "/",
+				Org:  "org1",
+			},
+		},
+		"ErrorCaseListMembersError": {
+			id:                 "GroupID",
+			expectedStatusCode: http.StatusForbidden,
+			getGroupByIDResult: group,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			listMembersErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsOut[GetGroupByIDMethod][0] = test.getGroupByIDResult
+		testApi.ArgsOut[GetGroupByIDMethod][1] = test.getGroupByIDErr
+		testApi.ArgsOut[ListMembersMethod][0] = test.listMembersResult
+		testApi.ArgsOut[ListMembersMethod][1] = test.totalMembersResult
+		testApi.ArgsOut[ListMembersMethod][2] = test.listMembersErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+SCIM_GROUPS_URL+"/"+test.id+test.query, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.id, testApi.ArgsIn[GetGroupByIDMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &ScimGroup{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimListGroups(t *testing.T) {
+	now := time.Now().UTC()
+	group := &api.Group{
+		ID:       "GroupID",
+		Name:     "backend",
+		Path:     "/",
+		Org:      "scim",
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	testcases := map[string]struct {
+		// API method args
+		query          url.Values
+		expectedFilter *api.Filter
+		// Expected result
+		expectedStatusCode   int
+		expectedTotalResults int
+		expectedDisplayNames []string
+		expectedError        ScimError
+		// Manager Results
+		listGroupsResult      []api.GroupIdentity
+		totalListGroupsResult int
+		getGroupByNameResult  *api.Group
+		// Manager Errors
+		listGroupsErr     error
+		getGroupByNameErr error
+	}{
+		"OkCase": {
+			query: url.Values{
+				"count":              []string{"5"},
+				"excludedAttributes": []string{"members"},
+			},
+			expectedFilter: &api.Filter{
+				Org:    "scim",
+				Offset: 0,
+				Limit:  5,
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedTotalResults: 1,
+			expectedDisplayNames: []string{"backend"},
+			listGroupsResult: []api.GroupIdentity{
+				{Org: "scim", Name: "backend"},
+			},
+			totalListGroupsResult: 1,
+			getGroupByNameResult:  group,
+		},
+		"OkCaseFilter": {
+			query: url.Values{
+				"filter":             []string{`displayName eq "backend"`},
+				"excludedAttributes": []string{"members"},
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedTotalResults: 1,
+			expectedDisplayNames: []string{"backend"},
+			getGroupByNameResult: group,
+		},
+		"OkCaseFilterGroupNotFound": {
+			query: url.Values{
+				"filter": []string{`displayName eq "backend"`},
+			},
+			expectedStatusCode: http.StatusOK,
+			getGroupByNameErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseUnsupportedFilter": {
+			query: url.Values{
+				"filter": []string{`userName eq "backend"`},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_FILTER,
+				Detail:   `Unsupported filter userName eq "backend", only displayName eq filter is allowed`,
+			},
+		},
+		"ErrorCaseListGroupsError": {
+			expectedFilter: &api.Filter{
+				Org:    "scim",
+				Offset: 0,
+				Limit:  api.DEFAULT_LIMIT_SIZE,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "500",
+				Detail:  "Error",
+			},
+			listGroupsErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[ListGroupsMethod] = make([]interface{}, 2)
+		testApi.ArgsOut[ListGroupsMethod][0] = test.listGroupsResult
+		testApi.ArgsOut[ListGroupsMethod][1] = test.totalListGroupsResult
+		testApi.ArgsOut[ListGroupsMethod][2] = test.listGroupsErr
+		testApi.ArgsOut[GetGroupByNameMethod][0] = test.getGroupByNameResult
+		testApi.ArgsOut[GetGroupByNameMethod][1] = test.getGroupByNameErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+SCIM_GROUPS_URL+"?"+test.query.Encode(), nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		if test.expectedFilter != nil {
+			assert.Equal(t, test.expectedFilter, testApi.ArgsIn[ListGroupsMethod][1], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[ListGroupsMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := struct {
+				ScimListResponse
+				Resources []ScimGroup `json:"Resources"`
+			}{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedTotalResults, response.TotalResults, "Error in test case %v", n)
+			assert.Equal(t, len(test.expectedDisplayNames), response.ItemsPerPage, "Error in test case %v", n)
+			var displayNames []string
+			for _, group := range response.Resources {
+				displayNames = append(displayNames, group.DisplayName)
+			}
+			assert.Equal(t, test.expectedDisplayNames, displayNames, "Error in test case %v", n)
+		default:
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimUpdateGroup(t *testing.T) {
+	group := &api.Group{
+		ID:   "GroupID",
+		Name: "backend",
+		Path: "/",
+		Org:  "scim",
+	}
+	members := []api.GroupMembers{
+		{User: "john@example.com"},
+		{User: "jane@example.com"},
+	}
+	testcases := map[string]struct {
+		// API method args
+		request string
+		// Expected result
+		expectedStatusCode    int
+		expectedAddedMember   interface{}
+		expectedRemovedMember interface{}
+		expectedNewName       interface{}
+		expectedError         ScimError
+		// Manager Results
+		getGroupByIDResult *api.Group
+		updateGroupResult  *api.Group
+		listMembersResult  []api.GroupMembers
+		totalMembersResult int
+		// Manager Errors
+		getGroupByIDErr error
+		updateGroupErr  error
+		addMemberErr    error
+		removeMemberErr error
+	}{
+		"OkCaseAddMembers": {
+			request:             `{"Operations": [{"op": "add", "path": "members", "value": [{"value": "bob@example.com"}]}]}`,
+			expectedStatusCode:  http.StatusNoContent,
+			expectedAddedMember: "bob@example.com",
+			getGroupByIDResult:  group,
+			addMemberErr: &api.Error{
+				Code:    api.USER_IS_ALREADY_A_MEMBER_OF_GROUP,
+				Message: "User is already a member",
+			},
+		},
+		"OkCaseAddMembersWithoutPath": {
+			request:             `{"Operations": [{"op": "Add", "value": {"members": [{"value": "bob@example.com"}]}}]}`,
+			expectedStatusCode:  http.StatusNoContent,
+			expectedAddedMember: "bob@example.com",
+			getGroupByIDResult:  group,
+		},
+		"OkCaseRemoveMember": {
+			request:               `{"Operations": [{"op": "remove", "path": "members[value eq \"john@example.com\"]"}]}`,
+			expectedStatusCode:    http.StatusNoContent,
+			expectedRemovedMember: "john@example.com",
+			getGroupByIDResult:    group,
+			removeMemberErr: &api.Error{
+				Code:    api.USER_IS_NOT_A_MEMBER_OF_GROUP,
+				Message: "User isn't a member",
+			},
+		},
+		"OkCaseRemoveAllMembers": {
+			request:               `{"Operations": [{"op": "remove", "path": "members"}]}`,
+			expectedStatusCode:    http.StatusNoContent,
+			expectedRemovedMember: "jane@example.com",
+			getGroupByIDResult:    group,
+			listMembersResult:     members,
+			totalMembersResult:    2,
+		},
+		"OkCaseReplaceMembers": {
+			request: `{"Operations": [{"op": "replace", "path": "members", "value": [
+				{"value": "john@example.com"}, {"value": "bob@example.com"}]}]}`,
+			expectedStatusCode:    http.StatusNoContent,
+			expectedAddedMember:   "bob@example.com",
+			expectedRemovedMember: "jane@example.com",
+			getGroupByIDResult:    group,
+			listMembersResult:     members,
+			totalMembersResult:    2,
+		},
+		"OkCaseRenameGroup": {
+			request:            `{"Operations": [{"op": "replace", "value": {"displayName": "frontend"}}]}`,
+			expectedStatusCode: http.StatusNoContent,
+			expectedNewName:    "frontend",
+			getGroupByIDResult: group,
+			updateGroupResult: &api.Group{
+				Name: "frontend",
+				Path: "/",
+				Org:  "scim",
+			},
+		},
+		"ErrorCaseGroupNotFound": {
+			request:            `{"Operations": []}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "Group not found",
+			},
+			getGroupByIDErr: &api.Error{
+				Code:    api.GROUP_BY_ORG_AND_NAME_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseRenameGroupAlreadyExist": {
+			request:            `{"Operations": [{"op": "replace", "path": "displayName", "value": "frontend"}]}`,
+			expectedStatusCode: http.StatusConflict,
+			expectedNewName:    "frontend",
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "409",
+				ScimType: SCIM_UNIQUENESS,
+				Detail:   "Group already exist",
+			},
+			getGroupByIDResult: group,
+			updateGroupErr: &api.Error{
+				Code:    api.GROUP_ALREADY_EXIST,
+				Message: "Group already exist",
+			},
+		},
+		"ErrorCaseRemoveWithoutPath": {
+			request:            `{"Operations": [{"op": "remove", "value": {"members": []}}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_PATH,
+				Detail:   "Path is required to remove",
+			},
+			getGroupByIDResult: group,
+		},
+		"ErrorCaseInvalidMembers": {
+			request:            `{"Operations": [{"op": "add", "path": "members", "value": "bob@example.com"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_VALUE,
+				Detail:   "Attribute members must be a list of members",
+			},
+			getGroupByIDResult: group,
+		},
+		"ErrorCaseUnsupportedAttribute": {
+			request:            `{"Operations": [{"op": "add", "path": "externalId", "value": "backend"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: ScimError{
+				Schemas:  []string{SCIM_ERROR_SCHEMA},
+				Status:   "400",
+				ScimType: SCIM_INVALID_PATH,
+				Detail:   "Unsupported attribute externalId",
+			},
+			getGroupByIDResult: group,
+		},
+		"ErrorCaseAddMemberNotFound": {
+			request:             `{"Operations": [{"op": "add", "path": "members", "value": [{"value": "bob@example.com"}]}]}`,
+			expectedStatusCode:  http.StatusNotFound,
+			expectedAddedMember: "bob@example.com",
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "User not found",
+			},
+			getGroupByIDResult: group,
+			addMemberErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not found",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[AddMemberMethod] = make([]interface{}, 4)
+		testApi.ArgsIn[RemoveMemberMethod] = make([]interface{}, 4)
+		testApi.ArgsIn[UpdateGroupMethod] = make([]interface{}, 6)
+		testApi.ArgsOut[GetGroupByIDMethod][0] = test.getGroupByIDResult
+		testApi.ArgsOut[GetGroupByIDMethod][1] = test.getGroupByIDErr
+		testApi.ArgsOut[UpdateGroupMethod][0] = test.updateGroupResult
+		testApi.ArgsOut[UpdateGroupMethod][1] = test.updateGroupErr
+		testApi.ArgsOut[ListMembersMethod][0] = test.listMembersResult
+		testApi.ArgsOut[ListMembersMethod][1] = test.totalMembersResult
+		testApi.ArgsOut[ListMembersMethod][2] = nil
+		testApi.ArgsOut[AddMemberMethod][0] = test.addMemberErr
+		testApi.ArgsOut[RemoveMemberMethod][0] = test.removeMemberErr
+
+		req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v%v/GroupID", server.URL, SCIM_GROUPS_URL),
+			bytes.NewBufferString(test.request))
+		assert.Nil(t, err, "Error in test case %v", n)
+		req.Header.Set("Content-Type", SCIM_CONTENT_TYPE)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, "GroupID", testApi.ArgsIn[GetGroupByIDMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedAddedMember, testApi.ArgsIn[AddMemberMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedRemovedMember, testApi.ArgsIn[RemoveMemberMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedNewName, testApi.ArgsIn[UpdateGroupMethod][3], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		if res.StatusCode != http.StatusNoContent {
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleScimRemoveGroup(t *testing.T) {
+	group := &api.Group{
+		ID:   "GroupID",
+		Name: "backend",
+		Path: "/",
+		Org:  "scim",
+	}
+	testcases := map[string]struct {
+		// Expected result
+		expectedStatusCode int
+		expectedError      ScimError
+		expectedGroupName  interface{}
+		// Manager Results
+		getGroupByIDResult *api.Group
+		// Manager Errors
+		getGroupByIDErr error
+		removeGroupErr  error
+	}{
+		"OkCase": {
+			expectedStatusCode: http.StatusNoContent,
+			expectedGroupName:  "backend",
+			getGroupByIDResult: group,
+		},
+		"ErrorCaseGroupNotFound": {
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "404",
+				Detail:  "Group not found",
+			},
+			getGroupByIDErr: &api.Error{
+				Code:    api.GROUP_BY_ID_NOT_FOUND,
+				Message: "Group not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			expectedStatusCode: http.StatusForbidden,
+			expectedGroupName:  "backend",
+			getGroupByIDResult: group,
+			expectedError: ScimError{
+				Schemas: []string{SCIM_ERROR_SCHEMA},
+				Status:  "403",
+				Detail:  "Unauthorized",
+			},
+			removeGroupErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[RemoveGroupMethod] = make([]interface{}, 3)
+		testApi.ArgsOut[GetGroupByIDMethod][0] = test.getGroupByIDResult
+		testApi.ArgsOut[GetGroupByIDMethod][1] = test.getGroupByIDErr
+		testApi.ArgsOut[RemoveGroupMethod][0] = test.removeGroupErr
+
+		req, err := http.NewRequest(http.MethodDelete, server.URL+SCIM_GROUPS_URL+"/GroupID", nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, "GroupID", testApi.ArgsIn[GetGroupByIDMethod][1], "Error in test case %v", n)
+		assert.Equal(t, test.expectedGroupName, testApi.ArgsIn[RemoveGroupMethod][2], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		if res.StatusCode != http.StatusNoContent {
+			scimError := ScimError{}
+			err = json.NewDecoder(res.Body).Decode(&scimError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, scimError, "Error in test case %v", n)
+		}
+	}
+}
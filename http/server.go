@@ -1,6 +1,11 @@
 package http
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"time"
@@ -9,34 +14,62 @@ import (
 	"net"
 
 	"sync"
+	"sync/atomic"
 
 	"github.com/Tecsisa/foulkon/api"
 	"github.com/Tecsisa/foulkon/foulkon"
+	"github.com/Tecsisa/foulkon/metrics"
 	"github.com/julienschmidt/httprouter"
-	"github.com/kylelemons/godebug/pretty"
 )
 
 type ReloadHandlerFunc func(watch *ProxyServer) bool
 
 // ProxyServer struct with reload Handler extension
 type ProxyServer struct {
-	certFile string
-	keyFile  string
+	certFile    string
+	keyFile     string
+	certificate certificateLoader
+
+	// Proxy configuration, read again on reload
+	proxy *foulkon.Proxy
+
+	// Client that calls worker, with TLS configuration of worker client certificate and CA bundle, if any
+	workerClient      *http.Client
+	workerTLSConfig   *tls.Config
+	workerCertificate certificateLoader
 
 	resourceLock sync.Mutex
 	reloadFunc   ReloadHandlerFunc
 	refreshTime  time.Duration
 
-	reloadServe      chan struct{}
 	currentResources []api.ProxyResource
+	// Router of current proxy resources, replaced on each refresh while requests are served
+	router atomic.Value
+	// Cursor of the last change read from the change feed
+	changesCursor string
+	// Checks of readiness URL
+	readinessChecks map[string]readinessCheck
+
+	// Closed to stop refreshing resources
+	stopRefresh chan struct{}
+	stopOnce    sync.Once
+	refreshes   sync.WaitGroup
 	http.Server
 }
 
 // WorkerServer struct
 type WorkerServer struct {
-	certFile string
-	keyFile  string
+	certFile    string
+	keyFile     string
+	certificate certificateLoader
 
+	// Worker configuration, read again on reload
+	worker *foulkon.Worker
+
+	// Closed on shutdown, so requests waiting for changes answer without delaying it
+	stopWaiting chan struct{}
+	// Cancels context of requests still in-flight when shutdown timeout expires
+	cancelRequests context.CancelFunc
 	http.Server
 }
 
@@ -44,13 +77,18 @@ type WorkerServer struct {
 type Server interface {
 	Run() error
 	Configuration() error
+	// Stop accepting connections and wait for in-flight requests until ctx is done
+	Shutdown(ctx context.Context) error
+	// Apply settings of its configuration that can change while running
+	Reload() error
 }
 
 // Run starts an HTTP WorkerServer
 func (ws *WorkerServer) Run() error {
 	var err error
 	if ws.certFile != "" || ws.keyFile != "" {
-		err = ws.ListenAndServeTLS(ws.certFile, ws.keyFile)
+		// Certificate is loaded by Configuration
+		err = ws.ListenAndServeTLS("", "")
 	} else {
 		err = ws.ListenAndServe()
 	}
@@ -69,66 +107,183 @@ func (ps *ProxyServer) Configuration() error {
 			ps.TLSConfig.NextProtos = append(ps.TLSConfig.NextProtos, "http/1.1")
 		}
 
-		configHasCert := len(ps.TLSConfig.Certificates) > 0 || ps.TLSConfig.GetCertificate != nil
+		if err := ps.certificate.load(ps.certFile, ps.keyFile); err != nil {
+			return err
+		}
+		ps.TLSConfig.GetCertificate = ps.certificate.getCertificate
+	}
 
-		if !configHasCert || ps.certFile != "" || ps.keyFile != "" {
-			var err error
-			ps.TLSConfig.Certificates = make([]tls.Certificate, 1)
-			ps.TLSConfig.Certificates[0], err = tls.LoadX509KeyPair(ps.certFile, ps.keyFile)
+	if ps.Addr == "" {
+		ps.Addr = ":http"
+	}
+
+	// TLS configuration of worker client is only used once worker is called
+	if ps.workerTLSConfig != nil {
+		if ps.proxy.WorkerCertFile != "" || ps.proxy.WorkerKeyFile != "" {
+			if err := ps.workerCertificate.load(ps.proxy.WorkerCertFile, ps.proxy.WorkerKeyFile); err != nil {
+				return err
+			}
+			ps.workerTLSConfig.GetClientCertificate = ps.workerCertificate.getClientCertificate
+		}
+		if ps.proxy.WorkerCAFile != "" {
+			rootCAs, err := loadCertPool(ps.proxy.WorkerCAFile)
 			if err != nil {
 				return err
 			}
+			ps.workerTLSConfig.RootCAs = rootCAs
 		}
 	}
 
-	if ps.Addr == "" {
-		ps.Addr = ":http"
-	}
+	return nil
+}
 
+// Configuration loads TLS certificate of an HTTP WorkerServer, if any, and the CA bundle that
+// verifies client certificates
+func (ws *WorkerServer) Configuration() error {
+	if ws.certFile != "" || ws.keyFile != "" {
+		if err := ws.certificate.load(ws.certFile, ws.keyFile); err != nil {
+			return err
+		}
+		ws.TLSConfig = &tls.Config{GetCertificate: ws.certificate.getCertificate}
+
+		if ws.worker.ClientCAFile != "" {
+			clientCAs, err := loadCertPool(ws.worker.ClientCAFile)
+			if err != nil {
+				return err
+			}
+			ws.TLSConfig.ClientCAs = clientCAs
+			// Clients without certificate can still authenticate as admin or with the authenticator
+			ws.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			if ws.worker.RequireClientCert {
+				ws.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+		}
+	}
 	return nil
 }
 
-// Configuration an HTTP WorkerServer
-func (ws *WorkerServer) Configuration() error { return nil }
+// Reload loads TLS certificate files of worker configuration again. Enabling or
+// disabling TLS needs a restart.
+func (ws *WorkerServer) Reload() error {
+	if ws.TLSConfig == nil || ws.TLSConfig.GetCertificate == nil {
+		return nil
+	}
+	ws.certFile, ws.keyFile = ws.worker.GetCertFiles()
+	return ws.certificate.load(ws.certFile, ws.keyFile)
+}
+
+// Shutdown gracefully shuts down the WorkerServer. Requests waiting for changes stop
+// waiting, so they answer with changes read so far instead of delaying shutdown. Other
+// requests are drained, and only cancelled if they are still running when ctx is done.
+func (ws *WorkerServer) Shutdown(ctx context.Context) error {
+	close(ws.stopWaiting)
+	err := ws.Server.Shutdown(ctx)
+	ws.cancelRequests()
+	return err
+}
 
-// Run starts an HTTP ProxyServer
+// Run starts an HTTP ProxyServer. It returns http.ErrServerClosed after Shutdown
 func (ps *ProxyServer) Run() error {
-	// Call reloadFunc every refreshTime
-	timer := time.NewTicker(ps.refreshTime)
-	// now wait for the other times when we needed to
+	// Call reloadFunc every refreshTime until shutdown. Handler reads the router on
+	// each request, so new routes are served without restarting server.
+	ps.refreshes.Add(1)
 	go func() {
-		for range timer.C {
-			// change the handler
-			if ps.reloadFunc(ps) {
-				ps.reloadServe <- struct{}{} // reset the listening binding
+		defer ps.refreshes.Done()
+		for {
+			// Refresh time is read on each wait, so it can be changed on reload
+			timer := time.NewTimer(ps.getRefreshTime())
+			select {
+			case <-timer.C:
+				ps.reloadFunc(ps)
+			case <-ps.stopRefresh:
+				timer.Stop()
+				return
 			}
 		}
 	}()
 
-	var err error
 	ln, err := net.Listen("tcp", ps.Addr)
 	if err != nil {
+		ps.stopRefreshing()
 		return err
 	}
+	if ps.TLSConfig != nil && ps.TLSConfig.GetCertificate != nil {
+		ln = tls.NewListener(ln, ps.TLSConfig)
+	}
+	return ps.Serve(ln)
+}
 
-	for {
-		l := ln.(*net.TCPListener)
-		defer l.Close()
-		go func(l net.Listener) {
-			err = ps.Serve(l)
-		}(l)
-		if err != nil {
+// Reload applies refresh time and loads TLS certificate files and worker client certificate files
+// of proxy configuration again. Nothing is applied if any certificate can't be loaded. Enabling or
+// disabling TLS needs a restart.
+func (ps *ProxyServer) Reload() error {
+	var workerCertificate, certificate tls.Certificate
+	reloadWorkerCertificate := ps.workerTLSConfig != nil && ps.workerTLSConfig.GetClientCertificate != nil
+	if reloadWorkerCertificate {
+		var err error
+		if workerCertificate, err = tls.LoadX509KeyPair(ps.proxy.WorkerCertFile, ps.proxy.WorkerKeyFile); err != nil {
 			return err
 		}
-		<-ps.reloadServe
+	}
+	reloadCertificate := ps.TLSConfig != nil && ps.TLSConfig.GetCertificate != nil
+	if reloadCertificate {
+		var err error
+		if certificate, err = tls.LoadX509KeyPair(ps.proxy.CertFile, ps.proxy.KeyFile); err != nil {
+			return err
+		}
+	}
+
+	ps.resourceLock.Lock()
+	ps.refreshTime = ps.proxy.RefreshTime
+	ps.resourceLock.Unlock()
+
+	if reloadWorkerCertificate {
+		ps.workerCertificate.set(&workerCertificate)
+	}
+	if reloadCertificate {
+		ps.certFile, ps.keyFile = ps.proxy.CertFile, ps.proxy.KeyFile
+		ps.certificate.set(&certificate)
+	}
+	return nil
+}
+
+func (ps *ProxyServer) getRefreshTime() time.Duration {
+	ps.resourceLock.Lock()
+	defer ps.resourceLock.Unlock()
+	return ps.refreshTime
+}
+
+// Shutdown stops refreshing resources and gracefully shuts down the ProxyServer
+func (ps *ProxyServer) Shutdown(ctx context.Context) error {
+	ps.stopRefreshing()
+	if err := ps.Server.Shutdown(ctx); err != nil {
+		return err
+	}
+	// Wait for a refresh in progress
+	done := make(chan struct{})
+	go func() {
+		ps.refreshes.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
+func (ps *ProxyServer) stopRefreshing() {
+	ps.stopOnce.Do(func() {
+		close(ps.stopRefresh)
+	})
+}
+
 // NewProxy returns a new ProxyServer
 func NewProxy(proxy *foulkon.Proxy) Server {
 	// Initialization
 	ps := new(ProxyServer)
-	ps.reloadServe = make(chan struct{}, 1)
+	ps.stopRefresh = make(chan struct{})
 	ps.TLSConfig = &tls.Config{}
 
 	// Set Proxy parameters
@@ -136,8 +291,25 @@ func NewProxy(proxy *foulkon.Proxy) Server {
 	ps.keyFile = proxy.KeyFile
 
 	ps.Addr = proxy.Host + ":" + proxy.Port
+	ps.proxy = proxy
 	ps.refreshTime = proxy.RefreshTime
+	ps.workerClient = http.DefaultClient
+	if proxy.WorkerCertFile != "" || proxy.WorkerKeyFile != "" || proxy.WorkerCAFile != "" {
+		// Certificate and CA bundle are loaded by Configuration
+		ps.workerTLSConfig = &tls.Config{}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = ps.workerTLSConfig
+		ps.workerClient = &http.Client{Transport: transport}
+	}
 	ps.reloadFunc = ps.RefreshResources(proxy)
+	ps.readinessChecks = map[string]readinessCheck{
+		"database":  checkDatabase(proxy.DB),
+		"resources": ps.checkResources,
+		"worker":    checkHealth(proxy.WorkerHost, ps.workerClient.Transport),
+	}
+	// Probes are served until first load of resources succeeds
+	ps.router.Store(httprouter.New())
+	ps.Server.Handler = ps.handler(http.HandlerFunc(ps.serveResources))
 
 	ps.reloadFunc(ps)
 
@@ -147,19 +319,50 @@ func NewProxy(proxy *foulkon.Proxy) Server {
 // NewWorker returns a new WorkerServer
 func NewWorker(worker *foulkon.Worker, h http.Handler) Server {
 	ws := new(WorkerServer)
-	ws.certFile = worker.CertFile
-	ws.keyFile = worker.KeyFile
+	ws.certFile, ws.keyFile = worker.GetCertFiles()
 	ws.Addr = worker.Host + ":" + worker.Port
+	ws.worker = worker
 
 	ws.Handler = h
+	ws.stopWaiting = make(chan struct{})
+	baseContext, cancel := context.WithCancel(context.WithValue(context.Background(), stopWaitingKey{}, ws.stopWaiting))
+	ws.BaseContext = func(net.Listener) context.Context {
+		return baseContext
+	}
+	ws.cancelRequests = cancel
 
 	return ws
 }
 
-// RefreshResources implements reloadFunc
+// RefreshResources implements reloadFunc. First call loads all proxy resources, next calls
+// read the change feed and only load them again if a proxy resource has changed.
 func (ps *ProxyServer) RefreshResources(proxy *foulkon.Proxy) func(s *ProxyServer) bool {
 	return func(srv *ProxyServer) bool {
-		proxyHandler := ProxyHandler{proxy: proxy, client: http.DefaultClient}
+		proxyHandler := ProxyHandler{proxy: proxy, client: http.DefaultClient, workerClient: srv.workerClient}
+
+		var cursor string
+		var err error
+		if srv.changesCursor == "" {
+			// Take cursor before loading resources, so changes made meanwhile are read later
+			cursor, err = proxy.ProxyApi.GetLastChangeCursor()
+			if err != nil {
+				api.Log.Errorf("Unexpected error reading change feed from database %v", err)
+				return false
+			}
+		} else {
+			var changes []api.Change
+			changes, cursor, err = proxy.ProxyApi.GetChanges(srv.changesCursor)
+			if err != nil {
+				api.Log.Errorf("Unexpected error reading change feed from database %v", err)
+				return false
+			}
+			if !hasProxyResourceChanges(changes) {
+				srv.resourceLock.Lock()
+				srv.changesCursor = cursor
+				srv.resourceLock.Unlock()
+				return false
+			}
+		}
 
 		// Get proxy resources
 		newProxyResources, err := proxy.ProxyApi.GetProxyResources()
@@ -168,31 +371,61 @@ func (ps *ProxyServer) RefreshResources(proxy *foulkon.Proxy) func(s *ProxyServe
 			return false
 		}
 
-		if diff := pretty.Compare(srv.currentResources, newProxyResources); diff != "" {
-			router := httprouter.New()
+		router := httprouter.New()
 
-			defer srv.resourceLock.Unlock()
-			srv.resourceLock.Lock()
+		defer srv.resourceLock.Unlock()
+		srv.resourceLock.Lock()
 
-			// writer lock
-			ps.currentResources = newProxyResources
+		// writer lock
+		ps.currentResources = newProxyResources
+		srv.changesCursor = cursor
+		metrics.ProxyReloads.Inc()
+		metrics.ProxyResources.Set(float64(len(newProxyResources)))
 
-			api.Log.Info("Updating resources ...")
-			for _, pr := range newProxyResources {
-				// Clean path
-				pr.Resource.Path = httprouter.CleanPath(pr.Resource.Path)
+		api.Log.Info("Updating resources ...")
+		for _, pr := range newProxyResources {
+			// Clean path
+			pr.Resource.Path = httprouter.CleanPath(pr.Resource.Path)
 
-				// Attach resource
-				safeRouterAdderHandler(router, pr, &proxyHandler)
-			}
-			// TODO: test when resources are empty
-			// If we had resources and those were deleted then handler must be
-			// created with empty router.
-			ps.Server.Handler = router
+			// Attach resource
+			safeRouterAdderHandler(router, pr, &proxyHandler)
+		}
+		// If we had resources and those were deleted then handler is
+		// served with empty router.
+		ps.router.Store(router)
+		return true
+	}
+}
+
+// serveResources serves request with router of current proxy resources
+func (ps *ProxyServer) serveResources(w http.ResponseWriter, r *http.Request) {
+	ps.router.Load().(*httprouter.Router).ServeHTTP(w, r)
+}
+
+// handler returns proxy handler serving router, with probes and metrics before proxy resources
+func (ps *ProxyServer) handler(router http.Handler) http.Handler {
+	return withProbeEndpoints(withMetricsEndpoint(instrumentRequests(metrics.SERVER_PROXY, nil, router)), ps.readinessChecks)
+}
+
+// checkResources returns an error until first load of proxy resources succeeds
+func (ps *ProxyServer) checkResources() error {
+	ps.resourceLock.Lock()
+	defer ps.resourceLock.Unlock()
+	if ps.changesCursor == "" {
+		return errors.New("Proxy resources not loaded yet")
+	}
+	return nil
+}
+
+// Check if any change of the feed modifies proxy resources
+func hasProxyResourceChanges(changes []api.Change) bool {
+	for _, c := range changes {
+		switch c.Type {
+		case api.EVENT_PROXY_RESOURCE_CREATED, api.EVENT_PROXY_RESOURCE_UPDATED, api.EVENT_PROXY_RESOURCE_DELETED:
 			return true
 		}
-		return false
 	}
+	return false
 }
 
 // Method to control when router has a resource already defined that collides with another
@@ -202,7 +435,17 @@ func safeRouterAdderHandler(router *httprouter.Router, pr api.ProxyResource, ph
 			api.Log.Errorf("There was a problem adding proxy resource with name %v and org %v: %v", pr.Name, pr.Org, r)
 		}
 	}()
-	router.Handle(pr.Resource.Method, pr.Resource.Path, ph.HandleRequest(pr))
+	router.Handle(pr.Resource.Method, pr.Resource.Path, instrumentHandle(pr.Resource.Path, ph.HandleRequest(pr)))
+}
+
+// stopWaitingKey is the context key of the channel closed when WorkerServer shuts down
+type stopWaitingKey struct{}
+
+// stopWaiting returns a channel closed when server of request starts shutting down. Requests
+// of servers that don't shut down gracefully get a nil channel, so they wait as usual.
+func stopWaiting(r *http.Request) <-chan struct{} {
+	ch, _ := r.Context().Value(stopWaitingKey{}).(chan struct{})
+	return ch
 }
 
 func strSliceContains(ss []string, s string) bool {
@@ -213,3 +456,49 @@ func strSliceContains(ss []string, s string) bool {
 	}
 	return false
 }
+
+// certificateLoader keeps the TLS certificate of a server, so it can be loaded again while serving
+type certificateLoader struct {
+	sync.RWMutex
+	certificate *tls.Certificate
+}
+
+func (cl *certificateLoader) load(certFile string, keyFile string) error {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	cl.set(&certificate)
+	return nil
+}
+
+func (cl *certificateLoader) set(certificate *tls.Certificate) {
+	cl.Lock()
+	cl.certificate = certificate
+	cl.Unlock()
+}
+
+func (cl *certificateLoader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cl.RLock()
+	defer cl.RUnlock()
+	return cl.certificate, nil
+}
+
+func (cl *certificateLoader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cl.RLock()
+	defer cl.RUnlock()
+	return cl.certificate, nil
+}
+
+// loadCertPool returns a pool with the PEM certificates of a CA bundle file
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("No PEM certificates found in CA file %v", caFile)
+	}
+	return pool, nil
+}
@@ -1,12 +1,16 @@
 package http
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"testing"
 
 	"crypto/tls"
 	"path/filepath"
 
 	"net/http"
+	"net/http/httptest"
 
 	"time"
 
@@ -43,10 +47,13 @@ func TestNewProxy(t *testing.T) {
 	testcases := map[string]struct {
 		proxy *foulkon.Proxy
 
-		getProxyResourcesMethod []api.ProxyResource
-		getProxyResourcesError  error
+		getLastChangeCursorMethod string
+		getLastChangeCursorError  error
+		getProxyResourcesMethod   []api.ProxyResource
+		getProxyResourcesError    error
 
 		expectedResources []api.ProxyResource
+		expectedCursor    string
 		expectedError     string
 		panicError        string
 	}{
@@ -127,7 +134,25 @@ func TestNewProxy(t *testing.T) {
 				RefreshTime: 10,
 				ProxyApi:    testApi,
 			},
-			getProxyResourcesMethod: []api.ProxyResource{},
+			getLastChangeCursorMethod: "5",
+			getProxyResourcesMethod:   []api.ProxyResource{},
+			expectedResources:         []api.ProxyResource{},
+			expectedCursor:            "5",
+		},
+		"ErrorCaseGetLastChangeCursor": {
+			proxy: &foulkon.Proxy{
+				Host:        "host",
+				Port:        "port",
+				CertFile:    "cert",
+				KeyFile:     "key",
+				RefreshTime: 10,
+				ProxyApi:    testApi,
+			},
+			getLastChangeCursorError: api.Error{
+				Code:    INTERNAL_SERVER_ERROR,
+				Message: "Unknow error",
+			},
+			expectedError: "Unexpected error reading change feed from database Code: InternalServerError, Message: Unknow error",
 		},
 		"ErrorCaseGetProxyResources": {
 			proxy: &foulkon.Proxy{
@@ -259,6 +284,8 @@ func TestNewProxy(t *testing.T) {
 	}
 	for n, test := range testcases {
 
+		testApi.ArgsOut[GetLastChangeCursorMethod][0] = test.getLastChangeCursorMethod
+		testApi.ArgsOut[GetLastChangeCursorMethod][1] = test.getLastChangeCursorError
 		testApi.ArgsOut[GetProxyResourcesMethod][0] = test.getProxyResourcesMethod
 		testApi.ArgsOut[GetProxyResourcesMethod][1] = test.getProxyResourcesError
 
@@ -279,6 +306,7 @@ func TestNewProxy(t *testing.T) {
 			assert.Equal(t, test.proxy.KeyFile, ps.keyFile, "Error in test case %v", n)
 			assert.Equal(t, test.proxy.RefreshTime, ps.refreshTime, "Error in test case %v", n)
 			assert.Equal(t, test.expectedResources, ps.currentResources, "Error in test case %v", n)
+			assert.Equal(t, test.expectedCursor, ps.changesCursor, "Error in test case %v", n)
 			// Check if panic errors where caught
 			if test.panicError != "" {
 				assert.Equal(t, test.panicError, hook.LastEntry().Message, "Error in test case %v", n)
@@ -287,6 +315,143 @@ func TestNewProxy(t *testing.T) {
 	}
 }
 
+func TestProxyServer_RefreshResources(t *testing.T) {
+	testApi := makeTestApi()
+	testcases := map[string]struct {
+		getChangesMethod []api.Change
+		getChangesCursor string
+		getChangesError  error
+
+		expectedReload    bool
+		expectedResources []api.ProxyResource
+		expectedCursor    string
+		expectedError     string
+	}{
+		"OKCaseProxyResourceChanged": {
+			getChangesMethod: []api.Change{
+				{Seq: 6, Type: api.EVENT_USER_CREATED, ResourceID: "USER1"},
+				{Seq: 7, Type: api.EVENT_PROXY_RESOURCE_UPDATED, ResourceID: "ID2"},
+			},
+			getChangesCursor: "7",
+			expectedReload:   true,
+			expectedResources: []api.ProxyResource{
+				{
+					ID: "ID2",
+					Resource: api.ResourceEntity{
+						Host:   "host2",
+						Path:   "/path2",
+						Method: "Method2",
+						Urn:    "urn2",
+						Action: "action2",
+					},
+				},
+			},
+			expectedCursor: "7",
+		},
+		"OKCaseOtherResourcesChanged": {
+			getChangesMethod: []api.Change{
+				{Seq: 6, Type: api.EVENT_GROUP_MEMBER_ADDED, ResourceID: "GROUP1", RelatedID: "USER1"},
+			},
+			getChangesCursor: "6",
+			expectedReload:   false,
+			expectedCursor:   "6",
+		},
+		"OKCaseNoChanges": {
+			getChangesCursor: "5",
+			expectedReload:   false,
+			expectedCursor:   "5",
+		},
+		"ErrorCaseGetChanges": {
+			getChangesError: api.Error{
+				Code:    INTERNAL_SERVER_ERROR,
+				Message: "Unknow error",
+			},
+			expectedReload: false,
+			expectedCursor: "5",
+			expectedError:  "Unexpected error reading change feed from database Code: InternalServerError, Message: Unknow error",
+		},
+	}
+
+	for n, test := range testcases {
+		proxy := &foulkon.Proxy{
+			ProxyApi: testApi,
+		}
+		ps := &ProxyServer{
+			changesCursor: "5",
+		}
+
+		testApi.ArgsOut[GetChangesMethod][0] = test.getChangesMethod
+		testApi.ArgsOut[GetChangesMethod][1] = test.getChangesCursor
+		testApi.ArgsOut[GetChangesMethod][2] = test.getChangesError
+		testApi.ArgsOut[GetProxyResourcesMethod][0] = []api.ProxyResource{
+			{
+				ID: "ID2",
+				Resource: api.ResourceEntity{
+					Host:   "host2",
+					Path:   "/path2",
+					Method: "Method2",
+					Urn:    "urn2",
+					Action: "action2",
+				},
+			},
+		}
+
+		reload := ps.RefreshResources(proxy)(ps)
+
+		// Check responses
+		assert.Equal(t, "5", testApi.ArgsIn[GetChangesMethod][0], "Error in test case %v", n)
+		assert.Equal(t, test.expectedReload, reload, "Error in test case %v", n)
+		assert.Equal(t, test.expectedResources, ps.currentResources, "Error in test case %v", n)
+		assert.Equal(t, test.expectedCursor, ps.changesCursor, "Error in test case %v", n)
+		if test.expectedError != "" {
+			assert.Equal(t, test.expectedError, hook.LastEntry().Message, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestProxyServer_ServeWhileRefreshing(t *testing.T) {
+	testApi := makeTestApi()
+	testApi.ArgsOut[GetLastChangeCursorMethod][0] = "5"
+	testApi.ArgsOut[GetProxyResourcesMethod][0] = []api.ProxyResource{}
+	ps := NewProxy(&foulkon.Proxy{ProxyApi: testApi}).(*ProxyServer)
+
+	testApi.ArgsOut[GetChangesMethod][0] = []api.Change{
+		{Seq: 6, Type: api.EVENT_PROXY_RESOURCE_CREATED, ResourceID: "ID2"},
+	}
+	testApi.ArgsOut[GetChangesMethod][1] = "6"
+	testApi.ArgsOut[GetProxyResourcesMethod][0] = []api.ProxyResource{
+		{
+			ID: "ID2",
+			Resource: api.ResourceEntity{
+				Host:   "host2",
+				Path:   "/path2",
+				Method: "GET",
+				Urn:    "urn2",
+				Action: "action2",
+			},
+		},
+	}
+
+	// Serve requests while resources are refreshed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			w := httptest.NewRecorder()
+			ps.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+			assert.Equal(t, http.StatusNotFound, w.Code, "Error in test")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		ps.reloadFunc(ps)
+	}
+	<-done
+
+	// Handler serves the refreshed router
+	handle, _, _ := ps.router.Load().(*httprouter.Router).Lookup(http.MethodGet, "/path2")
+	assert.NotNil(t, handle, "Error in test")
+}
+
 func Test_strSliceContains(t *testing.T) {
 	testcases := map[string]struct {
 		ss             []string
@@ -312,9 +477,82 @@ func Test_strSliceContains(t *testing.T) {
 }
 
 func TestWorkerServer_Configuration(t *testing.T) {
-	ws := WorkerServer{}
-	err := ws.Configuration()
-	assert.Nil(t, err, "Error in test")
+	certFile, _ := filepath.Abs("../dist/test/cert.pem")
+	keyFile, _ := filepath.Abs("../dist/test/key.pem")
+	testcases := map[string]struct {
+		worker *foulkon.Worker
+
+		expectedTLS        bool
+		expectedClientAuth tls.ClientAuthType
+		expectedError      string
+	}{
+		"OKCase": {
+			worker: &foulkon.Worker{},
+		},
+		"OKCaseTLS": {
+			worker: &foulkon.Worker{
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+			expectedTLS:        true,
+			expectedClientAuth: tls.NoClientCert,
+		},
+		"OKCaseClientCA": {
+			worker: &foulkon.Worker{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				ClientCAFile: certFile,
+			},
+			expectedTLS:        true,
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		"OKCaseRequireClientCert": {
+			worker: &foulkon.Worker{
+				CertFile:          certFile,
+				KeyFile:           keyFile,
+				ClientCAFile:      certFile,
+				RequireClientCert: true,
+			},
+			expectedTLS:        true,
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		"ErrorCaseClientCAWithoutCertificates": {
+			worker: &foulkon.Worker{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				ClientCAFile: keyFile,
+			},
+			expectedError: fmt.Sprintf("No PEM certificates found in CA file %v", keyFile),
+		},
+		"ErrorCaseClientCANotFound": {
+			worker: &foulkon.Worker{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				ClientCAFile: "/nonexistent/ca.pem",
+			},
+			expectedError: "open /nonexistent/ca.pem: no such file or directory",
+		},
+	}
+
+	for n, test := range testcases {
+		ws := NewWorker(test.worker, httprouter.New()).(*WorkerServer)
+		err := ws.Configuration()
+		if test.expectedError != "" {
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, test.expectedError, err.Error(), "Error in test case %v", n)
+			}
+			continue
+		}
+		assert.Nil(t, err, "Error in test case %v", n)
+		if test.expectedTLS {
+			if assert.NotNil(t, ws.TLSConfig, "Error in test case %v", n) {
+				assert.Equal(t, test.expectedClientAuth, ws.TLSConfig.ClientAuth, "Error in test case %v", n)
+				assert.Equal(t, test.worker.ClientCAFile != "", ws.TLSConfig.ClientCAs != nil, "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, ws.TLSConfig, "Error in test case %v", n)
+		}
+	}
 }
 
 func TestProxyServer_Configuration(t *testing.T) {
@@ -347,6 +585,36 @@ func TestProxyServer_Configuration(t *testing.T) {
 			},
 			expectedError: "open : no such file or directory",
 		},
+		"OKcaseWorkerClientCertificate": {
+			ps: &ProxyServer{
+				proxy: &foulkon.Proxy{
+					WorkerCertFile: certFile,
+					WorkerKeyFile:  keyFile,
+					WorkerCAFile:   certFile,
+				},
+				workerTLSConfig: &tls.Config{},
+			},
+			expectedAddr: ":http",
+		},
+		"ErrorCaseWorkerClientCertificate": {
+			ps: &ProxyServer{
+				proxy: &foulkon.Proxy{
+					WorkerCertFile: certFile,
+					WorkerKeyFile:  "",
+				},
+				workerTLSConfig: &tls.Config{},
+			},
+			expectedError: "open : no such file or directory",
+		},
+		"ErrorCaseWorkerCA": {
+			ps: &ProxyServer{
+				proxy: &foulkon.Proxy{
+					WorkerCAFile: keyFile,
+				},
+				workerTLSConfig: &tls.Config{},
+			},
+			expectedError: fmt.Sprintf("No PEM certificates found in CA file %v", keyFile),
+		},
 	}
 
 	for n, test := range testcases {
@@ -360,7 +628,146 @@ func TestProxyServer_Configuration(t *testing.T) {
 			}
 		} else {
 			assert.Equal(t, test.expectedAddr, test.ps.Addr, "Error in test case %v", n)
+			if test.ps.workerTLSConfig != nil {
+				cert, err := test.ps.workerTLSConfig.GetClientCertificate(nil)
+				assert.Nil(t, err, "Error in test case %v", n)
+				assert.NotNil(t, cert, "Error in test case %v", n)
+				assert.NotNil(t, test.ps.workerTLSConfig.RootCAs, "Error in test case %v", n)
+			}
+		}
+	}
+}
+
+func TestWorkerServer_Reload(t *testing.T) {
+	certFile, _ := filepath.Abs("../dist/test/cert.pem")
+	keyFile, _ := filepath.Abs("../dist/test/key.pem")
+	testcases := map[string]struct {
+		worker *foulkon.Worker
+		// Certificate files after reload
+		certFile string
+		keyFile  string
+
+		expectedTLS   bool
+		expectedError string
+	}{
+		"OKCase": {
+			worker:   &foulkon.Worker{},
+			certFile: certFile,
+			keyFile:  keyFile,
+		},
+		"OKCaseTLS": {
+			worker: &foulkon.Worker{
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+			certFile:    certFile,
+			keyFile:     keyFile,
+			expectedTLS: true,
+		},
+		"ErrorCaseTLS": {
+			worker: &foulkon.Worker{
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+			certFile:      certFile,
+			keyFile:       "",
+			expectedTLS:   true,
+			expectedError: "open : no such file or directory",
+		},
+	}
+
+	for n, test := range testcases {
+		srv := NewWorker(test.worker, httprouter.New())
+		assert.Nil(t, srv.Configuration(), "Error in test case %v", n)
+		test.worker.CertFile = test.certFile
+		test.worker.KeyFile = test.keyFile
+		err := srv.Reload()
+		if test.expectedError != "" {
+			ok := assert.NotNil(t, err, "Error in test case %v", n)
+			if ok {
+				assert.Equal(t, test.expectedError, err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+		}
+
+		// Check previous certificate is kept on error
+		ws := srv.(*WorkerServer)
+		if test.expectedTLS {
+			cert, err := ws.TLSConfig.GetCertificate(nil)
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.NotNil(t, cert, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, ws.TLSConfig, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestProxyServer_Reload(t *testing.T) {
+	certFile, _ := filepath.Abs("../dist/test/cert.pem")
+	keyFile, _ := filepath.Abs("../dist/test/key.pem")
+	testApi := makeTestApi()
+	testApi.ArgsOut[GetLastChangeCursorMethod][0] = "5"
+	testcases := map[string]struct {
+		proxy *foulkon.Proxy
+		// Proxy values after reload
+		refreshTime time.Duration
+		keyFile     string
+
+		expectedRefreshTime time.Duration
+		expectedError       string
+	}{
+		"OKCase": {
+			proxy: &foulkon.Proxy{
+				RefreshTime: 10 * time.Second,
+				ProxyApi:    testApi,
+			},
+			refreshTime:         1 * time.Second,
+			expectedRefreshTime: 1 * time.Second,
+		},
+		"OKCaseTLS": {
+			proxy: &foulkon.Proxy{
+				CertFile:    certFile,
+				KeyFile:     keyFile,
+				RefreshTime: 10 * time.Second,
+				ProxyApi:    testApi,
+			},
+			refreshTime:         1 * time.Second,
+			keyFile:             keyFile,
+			expectedRefreshTime: 1 * time.Second,
+		},
+		"ErrorCaseTLS": {
+			proxy: &foulkon.Proxy{
+				CertFile:    certFile,
+				KeyFile:     keyFile,
+				RefreshTime: 10 * time.Second,
+				ProxyApi:    testApi,
+			},
+			refreshTime:         1 * time.Second,
+			keyFile:             "",
+			expectedRefreshTime: 10 * time.Second,
+			expectedError:       "open : no such file or directory",
+		},
+	}
+
+	for n, test := range testcases {
+		srv := NewProxy(test.proxy)
+		assert.Nil(t, srv.Configuration(), "Error in test case %v", n)
+		test.proxy.RefreshTime = test.refreshTime
+		test.proxy.KeyFile = test.keyFile
+		err := srv.Reload()
+		if test.expectedError != "" {
+			ok := assert.NotNil(t, err, "Error in test case %v", n)
+			if ok {
+				assert.Equal(t, test.expectedError, err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
 		}
+
+		// Check refresh time is kept on error
+		ps := srv.(*ProxyServer)
+		assert.Equal(t, test.expectedRefreshTime, ps.getRefreshTime(), "Error in test case %v", n)
 	}
 }
 
@@ -437,6 +844,7 @@ func TestProxyServer_Run(t *testing.T) {
 	}
 	for n, test := range testcases {
 		var err error
+		testApi.ArgsOut[GetLastChangeCursorMethod][0] = "5"
 		srv := NewProxy(test.proxy)
 		srv.Configuration()
 
@@ -446,6 +854,10 @@ func TestProxyServer_Run(t *testing.T) {
 				assert.True(t, strings.Contains(err.Error(), test.expectedError), "Error in test case %v", n)
 			}
 		} else {
+			testApi.ArgsOut[GetChangesMethod][0] = []api.Change{
+				{Seq: 6, Type: api.EVENT_PROXY_RESOURCE_CREATED, ResourceID: "ID2"},
+			}
+			testApi.ArgsOut[GetChangesMethod][1] = "6"
 			testApi.ArgsOut[GetProxyResourcesMethod][0] = []api.ProxyResource{
 				{
 					ID: "ID2",
@@ -471,6 +883,132 @@ func TestProxyServer_Run(t *testing.T) {
 			ps.resourceLock.Lock()
 			assert.Equal(t, test.expectedResources, ps.currentResources, "Error in test case %v", n)
 			ps.resourceLock.Unlock()
+
+			assert.Nil(t, srv.Shutdown(context.Background()), "Error in test case %v", n)
 		}
 	}
 }
+
+func TestWorkerServer_Shutdown(t *testing.T) {
+	testcases := map[string]struct {
+		// Handler waits for shutdown, like change feed requests
+		waitShutdown bool
+		// Handler waits for request context to be cancelled
+		waitCancel bool
+		timeout    time.Duration
+		// Expected result
+		expectedStatusCode int
+		expectedError      error
+	}{
+		"OKCaseDrainRequest": {
+			timeout:            5 * time.Second,
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseStopWaitingRequest": {
+			waitShutdown:       true,
+			timeout:            5 * time.Second,
+			expectedStatusCode: http.StatusOK,
+		},
+		"ErrorCaseCancelRequestAfterTimeout": {
+			waitCancel:         true,
+			timeout:            100 * time.Millisecond,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedError:      context.DeadlineExceeded,
+		},
+	}
+
+	for n, test := range testcases {
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			switch {
+			case test.waitShutdown:
+				<-stopWaiting(r)
+			case test.waitCancel:
+				<-r.Context().Done()
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			default:
+				// Request context isn't cancelled while draining
+				select {
+				case <-r.Context().Done():
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		port := getFreePort(t)
+		srv := NewWorker(&foulkon.Worker{Host: "127.0.0.1", Port: port}, handler)
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- srv.Run()
+		}()
+
+		// Start in-flight request
+		resCode := make(chan int, 1)
+		go func() {
+			var res *http.Response
+			var err error
+			for i := 0; i < 50; i++ {
+				if res, err = http.Get("http://127.0.0.1:" + port); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil {
+				resCode <- 0
+				return
+			}
+			res.Body.Close()
+			resCode <- res.StatusCode
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+		err := srv.Shutdown(ctx)
+		cancel()
+
+		// Check in-flight request finished before shutdown, or was cancelled after timeout
+		assert.Equal(t, test.expectedError, err, "Error in test case %v", n)
+		assert.Equal(t, http.ErrServerClosed, <-runErr, "Error in test case %v", n)
+		assert.Equal(t, test.expectedStatusCode, <-resCode, "Error in test case %v", n)
+	}
+}
+
+func TestProxyServer_Shutdown(t *testing.T) {
+	testApi := makeTestApi()
+	testApi.ArgsOut[GetLastChangeCursorMethod][0] = "5"
+	testApi.ArgsOut[GetChangesMethod][1] = "5"
+
+	srv := NewProxy(&foulkon.Proxy{
+		Host:        "127.0.0.1",
+		Port:        getFreePort(t),
+		RefreshTime: 1 * time.Millisecond,
+		ProxyApi:    testApi,
+	})
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- srv.Run()
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Check server and refresh loop are stopped
+	assert.Nil(t, srv.Shutdown(ctx), "Error in test")
+	assert.Equal(t, http.ErrServerClosed, <-runErr, "Error in test")
+}
+
+// Aux method that returns a port free to listen
+func getFreePort(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error getting free port %v", err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
@@ -10,12 +10,18 @@ import (
 // REQUESTS
 
 type CreateUserRequest struct {
-	ExternalID string `json:"externalId,omitempty"`
-	Path       string `json:"path,omitempty"`
+	ExternalID string            `json:"externalId,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 type UpdateUserRequest struct {
-	Path string `json:"path,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason,omitempty"`
 }
 
 // RESPONSES
@@ -25,6 +31,17 @@ type GetUserExternalIDsResponse struct {
 	Limit       int      `json:"limit"`
 	Offset      int      `json:"offset"`
 	Total       int      `json:"total"`
+	Next        string   `json:"next,omitempty"`
+	Prev        string   `json:"prev,omitempty"`
+}
+
+type ListUsersExpandedResponse struct {
+	Users  []api.User `json:"users,omitempty"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+	Total  int        `json:"total"`
+	Next   string     `json:"next,omitempty"`
+	Prev   string     `json:"prev,omitempty"`
 }
 
 type GetGroupsByUserIdResponse struct {
@@ -32,6 +49,8 @@ type GetGroupsByUserIdResponse struct {
 	Limit  int              `json:"limit"`
 	Offset int              `json:"offset"`
 	Total  int              `json:"total"`
+	Next   string           `json:"next,omitempty"`
+	Prev   string           `json:"prev,omitempty"`
 }
 
 // HANDLERS
@@ -46,7 +65,7 @@ func (wh *WorkerHandler) HandleAddUser(w http.ResponseWriter, r *http.Request, _
 	}
 
 	// Call user API to create user
-	response, err := wh.worker.UserApi.AddUser(requestInfo, request.ExternalID, request.Path)
+	response, err := wh.worker.UserApi.AddUser(requestInfo, request.ExternalID, request.Path, request.Attributes)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
 }
 
@@ -70,6 +89,20 @@ func (wh *WorkerHandler) HandleListUsers(w http.ResponseWriter, r *http.Request,
 		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
 		return
 	}
+	if filterData.Expand {
+		// Call user API to list full users
+		result, total, err := wh.worker.UserApi.ListUsersExpanded(requestInfo, filterData)
+		response := &ListUsersExpandedResponse{
+			Users:  result,
+			Offset: filterData.Offset,
+			Limit:  filterData.Limit,
+			Total:  total,
+			Next:   filterData.NextCursor,
+			Prev:   filterData.PrevCursor,
+		}
+		wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+		return
+	}
 
 	// Call user API to list users
 	result, total, err := wh.worker.UserApi.ListUsers(requestInfo, filterData)
@@ -79,6 +112,8 @@ func (wh *WorkerHandler) HandleListUsers(w http.ResponseWriter, r *http.Request,
 		Offset:      filterData.Offset,
 		Limit:       filterData.Limit,
 		Total:       total,
+		Next:        filterData.NextCursor,
+		Prev:        filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
@@ -93,7 +128,34 @@ func (wh *WorkerHandler) HandleUpdateUser(w http.ResponseWriter, r *http.Request
 	}
 
 	// Call user API to update user
-	response, err := wh.worker.UserApi.UpdateUser(requestInfo, filterData.ExternalID, request.Path)
+	response, err := wh.worker.UserApi.UpdateUser(requestInfo, filterData.ExternalID, request.Path, request.Attributes)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleSuspendUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &SuspendUserRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to suspend user
+	response, err := wh.worker.UserApi.SuspendUser(requestInfo, filterData.ExternalID, request.Reason)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleReactivateUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call user API to reactivate user
+	response, err := wh.worker.UserApi.ReactivateUser(requestInfo, filterData.ExternalID)
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
 
@@ -125,6 +187,8 @@ func (wh *WorkerHandler) HandleListGroupsByUser(w http.ResponseWriter, r *http.R
 		Offset: filterData.Offset,
 		Limit:  filterData.Limit,
 		Total:  total,
+		Next:   filterData.NextCursor,
+		Prev:   filterData.PrevCursor,
 	}
 	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
 }
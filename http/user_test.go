@@ -51,6 +51,38 @@ func TestWorkerHandler_HandleAddUser(t *testing.T) {
 				UpdateAt:   now,
 			},
 		},
+		"OkCaseAttributes": {
+			request: &CreateUserRequest{
+				ExternalID: "UserID",
+				Path:       "Path",
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+			},
+			addUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Attributes: map[string]string{
+					"clearance": "high",
+				},
+			},
+		},
 		"ErrorCaseMalformedRequest": {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedError: api.Error{
@@ -144,6 +176,7 @@ func TestWorkerHandler_HandleAddUser(t *testing.T) {
 			// Check received parameters
 			assert.Equal(t, test.request.ExternalID, testApi.ArgsIn[AddUserMethod][1], "Error in test case %v", n)
 			assert.Equal(t, test.request.Path, testApi.ArgsIn[AddUserMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Attributes, testApi.ArgsIn[AddUserMethod][3], "Error in test case %v", n)
 		}
 
 		// check status code
@@ -317,6 +350,8 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 		// Manager Results
 		getUserListResult []string
 		totalResult       int
+		nextCursorResult  string
+		prevCursorResult  string
 		// Manager Errors
 		getUserListErr error
 	}{
@@ -336,6 +371,87 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 			getUserListResult: []string{"userId1", "userId2"},
 			totalResult:       2,
 		},
+		"OkCaseCursor": {
+			filter: &api.Filter{
+				PathPrefix: "myPath",
+				Limit:      1,
+				Cursor:     "cursor1",
+				NextCursor: "cursor2",
+				PrevCursor: "cursor0",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId2"},
+				Limit:       1,
+				Total:       3,
+				Next:        "cursor2",
+				Prev:        "cursor0",
+			},
+			getUserListResult: []string{"userId2"},
+			totalResult:       3,
+			nextCursorResult:  "cursor2",
+			prevCursorResult:  "cursor0",
+		},
+		"OkCaseAttributes": {
+			filter: &api.Filter{
+				PathPrefix: "myPath",
+				Attributes: map[string]string{
+					"department": "backend",
+					"clearance":  "high",
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId1"},
+				Total:       1,
+			},
+			getUserListResult: []string{"userId1"},
+			totalResult:       1,
+		},
+		"OkCaseState": {
+			filter: &api.Filter{
+				PathPrefix: "myPath",
+				State:      api.USER_STATE_DISABLED,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId2"},
+				Total:       1,
+			},
+			getUserListResult: []string{"userId2"},
+			totalResult:       1,
+		},
+		"OkCaseSearch": {
+			filter: &api.Filter{
+				PathPrefix:    "myPath",
+				NamePrefix:    "user",
+				NameContains:  "Id",
+				CreatedAfter:  time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2017, time.February, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore: time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: GetUserExternalIDsResponse{
+				ExternalIDs: []string{"userId1"},
+				Total:       1,
+			},
+			getUserListResult: []string{"userId1"},
+			totalResult:       1,
+		},
+		"ErrorCaseInvalidAttributeParam": {
+			filter: &api.Filter{
+				Attributes: map[string]string{
+					"": "backend",
+				},
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Attribute :backend",
+			},
+		},
 		"ErrorCaseInvalidFilterParams": {
 			filter: &api.Filter{
 				PathPrefix: "",
@@ -395,6 +511,8 @@ func TestWorkerHandler_HandleListUsers(t *testing.T) {
 		testApi.ArgsOut[ListUsersMethod][0] = test.getUserListResult
 		testApi.ArgsOut[ListUsersMethod][1] = test.totalResult
 		testApi.ArgsOut[ListUsersMethod][2] = test.getUserListErr
+		testApi.ArgsOut[ListUsersMethod][3] = test.nextCursorResult
+		testApi.ArgsOut[ListUsersMethod][4] = test.prevCursorResult
 
 		url := fmt.Sprintf(server.URL + USER_ROOT_URL)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -702,6 +820,407 @@ func TestWorkerHandler_HandleRemoveUser(t *testing.T) {
 	}
 }
 
+func TestWorkerHandler_HandleSuspendUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		request    *SuspendUserRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.User
+		expectedError      api.Error
+		// Manager Results
+		suspendUserResult *api.User
+		// Manager Errors
+		suspendUserErr error
+	}{
+		"OkCase": {
+			externalID: "UserID",
+			request: &SuspendUserRequest{
+				Reason: "Offboarded",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.User{
+				ID:             "UserID",
+				ExternalID:     "ExternalID",
+				Path:           "Path",
+				Urn:            "urn",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: "Offboarded",
+			},
+			suspendUserResult: &api.User{
+				ID:             "UserID",
+				ExternalID:     "ExternalID",
+				Path:           "Path",
+				Urn:            "urn",
+				CreateAt:       now,
+				UpdateAt:       now,
+				DisabledReason: "Offboarded",
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseUserNotExist": {
+			externalID:         "UserID",
+			request:            &SuspendUserRequest{},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not exist",
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not exist",
+			},
+		},
+		"ErrorCaseInvalidParameterError": {
+			externalID:         "UserID",
+			request:            &SuspendUserRequest{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter",
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			externalID:         "UserID",
+			request:            &SuspendUserRequest{},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			suspendUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			externalID:         "UserID",
+			request:            &SuspendUserRequest{},
+			expectedStatusCode: http.StatusInternalServerError,
+			suspendUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsIn[SuspendUserMethod] = make([]interface{}, 3)
+		testApi.ArgsOut[SuspendUserMethod][0] = test.suspendUserResult
+		testApi.ArgsOut[SuspendUserMethod][1] = test.suspendUserErr
+
+		body := bytes.NewBuffer([]byte{})
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/suspend", test.externalID)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.externalID, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Reason, testApi.ArgsIn[SuspendUserMethod][2], "Error in test case %v", n)
+		} else {
+			assert.Nil(t, testApi.ArgsIn[SuspendUserMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.User{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleReactivateUser(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		externalID string
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   *api.User
+		expectedError      api.Error
+		// Manager Results
+		reactivateUserResult *api.User
+		// Manager Errors
+		reactivateUserErr error
+	}{
+		"OkCase": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+			reactivateUserResult: &api.User{
+				ID:         "UserID",
+				ExternalID: "ExternalID",
+				Path:       "Path",
+				Urn:        "urn",
+				CreateAt:   now,
+				UpdateAt:   now,
+				Enabled:    true,
+			},
+		},
+		"ErrorCaseUserNotExist": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not exist",
+			},
+			reactivateUserErr: &api.Error{
+				Code:    api.USER_BY_EXTERNAL_ID_NOT_FOUND,
+				Message: "User not exist",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			reactivateUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			externalID:         "UserID",
+			expectedStatusCode: http.StatusInternalServerError,
+			reactivateUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+		testApi.ArgsOut[ReactivateUserMethod][0] = test.reactivateUserResult
+		testApi.ArgsOut[ReactivateUserMethod][1] = test.reactivateUserErr
+
+		url := fmt.Sprintf(server.URL+USER_ROOT_URL+"/%v/reactivate", test.externalID)
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		// Check received parameters
+		assert.Equal(t, test.externalID, testApi.ArgsIn[ReactivateUserMethod][1], "Error in test case %v", n)
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := &api.User{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListUsersExpanded(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		expand       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListUsersExpandedResponse
+		expectedError      api.Error
+		// Manager Results
+		listUserResult  []api.User
+		totalUserResult int
+		// Manager Errors
+		listUserErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListUsersExpandedResponse{
+				Users: []api.User{
+					{
+						ID:         "UserID",
+						ExternalID: "user1",
+						Path:       "path",
+						Urn:        "urn",
+						Enabled:    true,
+					},
+				},
+				Offset: 0,
+				Limit:  0,
+				Total:  1,
+			},
+			listUserResult: []api.User{
+				{
+					ID:         "UserID",
+					ExternalID: "user1",
+					Path:       "path",
+					Urn:        "urn",
+					Enabled:    true,
+				},
+			},
+			totalUserResult: 1,
+		},
+		"ErrorCaseInvalidExpand": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+			},
+			expand:             "maybe",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Expand maybe",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listUserErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				PathPrefix: "path",
+				Offset:     0,
+				Limit:      0,
+				Expand:     true,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listUserErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListUsersExpandedMethod][0] = test.listUserResult
+		testApi.ArgsOut[ListUsersExpandedMethod][1] = test.totalUserResult
+		testApi.ArgsOut[ListUsersExpandedMethod][2] = test.listUserErr
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+USER_ROOT_URL, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+		if test.expand != "" {
+			q := req.URL.Query()
+			q.Set("Expand", test.expand)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameter
+			filterData, ok := testApi.ArgsIn[ListUsersExpandedMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listResponse := ListUsersExpandedResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
 func TestWorkerHandler_HandleListGroupsByUser(t *testing.T) {
 	now := time.Now().UTC()
 	testcases := map[string]struct {
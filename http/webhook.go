@@ -0,0 +1,147 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/julienschmidt/httprouter"
+)
+
+// REQUESTS
+
+type CreateWebhookRequest struct {
+	Name   string   `json:"name,omitempty"`
+	Path   string   `json:"path,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	Name   string   `json:"name,omitempty"`
+	Path   string   `json:"path,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// RESPONSES
+
+type ListWebhooksResponse struct {
+	Webhooks []string `json:"webhooks,omitempty"`
+	Limit    int      `json:"limit"`
+	Offset   int      `json:"offset"`
+	Total    int      `json:"total"`
+	Next     string   `json:"next,omitempty"`
+	Prev     string   `json:"prev,omitempty"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []api.WebhookDelivery `json:"deliveries,omitempty"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	Total      int                   `json:"total"`
+	Next       string                `json:"next,omitempty"`
+	Prev       string                `json:"prev,omitempty"`
+}
+
+// HANDLERS
+
+func (wh *WorkerHandler) HandleAddWebhook(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Process request
+	request := &CreateWebhookRequest{}
+	requestInfo, _, apiErr := wh.processHttpRequest(r, w, nil, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to create the new webhook
+	response, err := wh.worker.WebhookApi.AddWebhook(requestInfo, request.Name, request.Path, request.URL, request.Secret, request.Events)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusCreated)
+}
+
+func (wh *WorkerHandler) HandleGetWebhookByName(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to get the webhook
+	response, err := wh.worker.WebhookApi.GetWebhookByName(requestInfo, filterData.WebhookName)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleListWebhooks(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to list the webhooks
+	result, total, err := wh.worker.WebhookApi.ListWebhooks(requestInfo, filterData)
+	// Create response
+	response := &ListWebhooksResponse{
+		Webhooks: result,
+		Offset:   filterData.Offset,
+		Limit:    filterData.Limit,
+		Total:    total,
+		Next:     filterData.NextCursor,
+		Prev:     filterData.PrevCursor,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleUpdateWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	request := &UpdateWebhookRequest{}
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, request)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to update the webhook
+	response, err := wh.worker.WebhookApi.UpdateWebhook(requestInfo, filterData.WebhookName,
+		request.Name, request.Path, request.URL, request.Secret, request.Events)
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
+
+func (wh *WorkerHandler) HandleRemoveWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to delete the webhook
+	err := wh.worker.WebhookApi.RemoveWebhook(requestInfo, filterData.WebhookName)
+	wh.processHttpResponse(r, w, requestInfo, nil, err, http.StatusNoContent)
+}
+
+func (wh *WorkerHandler) HandleListWebhookDeliveries(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	// Process request
+	requestInfo, filterData, apiErr := wh.processHttpRequest(r, w, ps, nil)
+	if apiErr != nil {
+		wh.processHttpResponse(r, w, requestInfo, nil, apiErr, http.StatusBadRequest)
+		return
+	}
+
+	// Call webhook API to list the delivery attempts
+	result, total, err := wh.worker.WebhookApi.ListWebhookDeliveries(requestInfo, filterData)
+	// Create response
+	response := &ListWebhookDeliveriesResponse{
+		Deliveries: result,
+		Offset:     filterData.Offset,
+		Limit:      filterData.Limit,
+		Total:      total,
+		Next:       filterData.NextCursor,
+		Prev:       filterData.PrevCursor,
+	}
+	wh.processHttpResponse(r, w, requestInfo, response, err, http.StatusOK)
+}
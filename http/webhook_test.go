@@ -0,0 +1,820 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerHandler_HandleAddWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		request *CreateWebhookRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		addWebhookResult *api.Webhook
+		// Manager Errors
+		addWebhookErr error
+	}{
+		"OkCase": {
+			request: &CreateWebhookRequest{
+				Name:   "test",
+				Path:   "/path/",
+				URL:    "https://test.com/hook",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			addWebhookResult: &api.Webhook{
+				ID:       "test1",
+				Name:     "test",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_ALL},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: api.Webhook{
+				ID:       "test1",
+				Name:     "test",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:      "https://test.com/hook",
+				Events:   []string{api.EVENT_ALL},
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			request: &CreateWebhookRequest{
+				Name:   "test",
+				Path:   "/path/",
+				URL:    "https://test.com/hook",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code: api.WEBHOOK_ALREADY_EXIST,
+			},
+		},
+		"ErrorCaseInvalidParameter": {
+			request: &CreateWebhookRequest{
+				Name:   "test",
+				Path:   "/path/",
+				URL:    "ftp://test.com/hook",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code: api.INVALID_PARAMETER_ERROR,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			request: &CreateWebhookRequest{
+				Name:   "test",
+				Path:   "/path/",
+				URL:    "https://test.com/hook",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			request: &CreateWebhookRequest{
+				Name:   "test",
+				Path:   "/path/",
+				URL:    "https://test.com/hook",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			addWebhookErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[AddWebhookMethod][0] = test.addWebhookResult
+		testApi.ArgsOut[AddWebhookMethod][1] = test.addWebhookErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL + WEBHOOK_ROOT_URL)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.request.Name, testApi.ArgsIn[AddWebhookMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Path, testApi.ArgsIn[AddWebhookMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.URL, testApi.ArgsIn[AddWebhookMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.Secret, testApi.ArgsIn[AddWebhookMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.request.Events, testApi.ArgsIn[AddWebhookMethod][5], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusCreated:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result, secret is never returned
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleGetWebhookByName(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		webhookName  string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		getWebhookByNameResult *api.Webhook
+		// Manager Errors
+		getWebhookByNameErr error
+	}{
+		"OkCase": {
+			webhookName:        "test",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Webhook{
+				ID:       "test1",
+				Name:     "test",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:      "https://test.com/hook",
+				Events:   []string{api.EVENT_ALL},
+			},
+			getWebhookByNameResult: &api.Webhook{
+				ID:       "test1",
+				Name:     "test",
+				Path:     "/path/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/path/", "test"),
+				URL:      "https://test.com/hook",
+				Secret:   "secret",
+				Events:   []string{api.EVENT_ALL},
+			},
+		},
+		"ErrorCaseInvalidRequest": {
+			webhookName:        "test",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			webhookName:        "test",
+			expectedStatusCode: http.StatusNotFound,
+			getWebhookByNameErr: &api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+			expectedError: api.Error{
+				Code: api.WEBHOOK_BY_NAME_NOT_FOUND,
+			},
+		},
+		"ErrorCaseUnauthorized": {
+			webhookName:        "test",
+			expectedStatusCode: http.StatusForbidden,
+			getWebhookByNameErr: &api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+			expectedError: api.Error{
+				Code: api.UNAUTHORIZED_RESOURCES_ERROR,
+			},
+		},
+		"ErrorCaseInternalServerError": {
+			webhookName:        "test",
+			expectedStatusCode: http.StatusInternalServerError,
+			getWebhookByNameErr: &api.Error{
+				Code: api.UNKNOWN_API_ERROR,
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[GetWebhookByNameMethod][0] = test.getWebhookByNameResult
+		testApi.ArgsOut[GetWebhookByNameMethod][1] = test.getWebhookByNameErr
+
+		url := fmt.Sprintf(server.URL+WEBHOOK_ROOT_URL+"/%v", test.webhookName)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.webhookName, testApi.ArgsIn[GetWebhookByNameMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListWebhooks(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListWebhooksResponse
+		expectedError      api.Error
+		// Manager Results
+		listWebhooksResult []string
+		listWebhooksTotal  int
+		// Manager Errors
+		listWebhooksErr error
+	}{
+		"OkCase": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListWebhooksResponse{
+				Webhooks: []string{"webhook1"},
+				Total:    1,
+			},
+			listWebhooksResult: []string{"webhook1"},
+			listWebhooksTotal:  1,
+		},
+		"ErrorCaseInvalidFilterParams": {
+			filter: &api.Filter{
+				Limit: -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Limit -1",
+			},
+		},
+		"ErrorCaseUnauthorizedError": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			listWebhooksErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			filter: &api.Filter{
+				PathPrefix: "/path/",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listWebhooksErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListWebhooksMethod][0] = test.listWebhooksResult
+		testApi.ArgsOut[ListWebhooksMethod][1] = test.listWebhooksTotal
+		testApi.ArgsOut[ListWebhooksMethod][2] = test.listWebhooksErr
+
+		url := fmt.Sprintf(server.URL + WEBHOOK_ROOT_URL)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			filterData, ok := testApi.ArgsIn[ListWebhooksMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listWebhooksResponse := ListWebhooksResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listWebhooksResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listWebhooksResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleUpdateWebhook(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		webhookName string
+		request     *UpdateWebhookRequest
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   api.Webhook
+		expectedError      api.Error
+		// Manager Results
+		updateWebhookResult *api.Webhook
+		// Manager Errors
+		updateWebhookErr error
+	}{
+		"OkCase": {
+			webhookName: "test",
+			request: &UpdateWebhookRequest{
+				Name:   "newName",
+				Path:   "/newpath/",
+				URL:    "https://new.com/hook",
+				Events: []string{api.EVENT_USER_CREATED},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: api.Webhook{
+				ID:       "test1",
+				Name:     "newName",
+				Path:     "/newpath/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/newpath/", "newName"),
+				URL:      "https://new.com/hook",
+				Events:   []string{api.EVENT_USER_CREATED},
+			},
+			updateWebhookResult: &api.Webhook{
+				ID:       "test1",
+				Name:     "newName",
+				Path:     "/newpath/",
+				CreateAt: now,
+				UpdateAt: now,
+				Urn:      api.CreateUrn("", api.RESOURCE_WEBHOOK, "/newpath/", "newName"),
+				URL:      "https://new.com/hook",
+				Events:   []string{api.EVENT_USER_CREATED},
+			},
+		},
+		"ErrorCaseMalformedRequest": {
+			webhookName:        "test",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "EOF",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			webhookName: "test",
+			request: &UpdateWebhookRequest{
+				Name:   "newName",
+				Path:   "/newpath/",
+				URL:    "https://new.com/hook",
+				Events: []string{api.EVENT_ALL},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+			updateWebhookErr: &api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Not found",
+			},
+		},
+		"ErrorCaseWebhookAlreadyExists": {
+			webhookName: "test",
+			request: &UpdateWebhookRequest{
+				Name:   "newName",
+				Path:   "/newpath/",
+				URL:    "https://new.com/hook",
+				Events: []string{api.EVENT_ALL},
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedError: api.Error{
+				Code:    api.WEBHOOK_ALREADY_EXIST,
+				Message: "Already exists",
+			},
+			updateWebhookErr: &api.Error{
+				Code:    api.WEBHOOK_ALREADY_EXIST,
+				Message: "Already exists",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			webhookName: "test",
+			request: &UpdateWebhookRequest{
+				Name:   "newName",
+				Path:   "/newpath/",
+				URL:    "https://new.com/hook",
+				Events: []string{api.EVENT_ALL},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			updateWebhookErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[UpdateWebhookMethod][0] = test.updateWebhookResult
+		testApi.ArgsOut[UpdateWebhookMethod][1] = test.updateWebhookErr
+
+		var body *bytes.Buffer
+		if test.request != nil {
+			jsonObject, err := json.Marshal(test.request)
+			assert.Nil(t, err, "Error in test case %v", n)
+			body = bytes.NewBuffer(jsonObject)
+		}
+		if body == nil {
+			body = bytes.NewBuffer([]byte{})
+		}
+
+		url := fmt.Sprintf(server.URL+WEBHOOK_ROOT_URL+"/%v", test.webhookName)
+		req, err := http.NewRequest(http.MethodPut, url, body)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if test.request != nil {
+			// Check received parameters
+			assert.Equal(t, test.webhookName, testApi.ArgsIn[UpdateWebhookMethod][1], "Error in test case %v", n)
+			assert.Equal(t, test.request.Name, testApi.ArgsIn[UpdateWebhookMethod][2], "Error in test case %v", n)
+			assert.Equal(t, test.request.Path, testApi.ArgsIn[UpdateWebhookMethod][3], "Error in test case %v", n)
+			assert.Equal(t, test.request.URL, testApi.ArgsIn[UpdateWebhookMethod][4], "Error in test case %v", n)
+			assert.Equal(t, test.request.Secret, testApi.ArgsIn[UpdateWebhookMethod][5], "Error in test case %v", n)
+			assert.Equal(t, test.request.Events, testApi.ArgsIn[UpdateWebhookMethod][6], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			response := api.Webhook{}
+			err = json.NewDecoder(res.Body).Decode(&response)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, response, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleRemoveWebhook(t *testing.T) {
+	testcases := map[string]struct {
+		// API method args
+		name         string
+		offset       string
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedError      api.Error
+		// Manager Errors
+		removeWebhookErr error
+	}{
+		"OkCase": {
+			name:               "webhook1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"ErrorCaseInvalidRequest": {
+			name:               "webhook1",
+			offset:             "-1",
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Offset -1",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			name:               "webhook1",
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook not found",
+			},
+			removeWebhookErr: &api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook not found",
+			},
+		},
+		"ErrorCaseUnauthorizedResourcesError": {
+			name:               "webhook1",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError: api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+			removeWebhookErr: &api.Error{
+				Code:    api.UNAUTHORIZED_RESOURCES_ERROR,
+				Message: "Unauthorized",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			name:               "webhook1",
+			expectedStatusCode: http.StatusInternalServerError,
+			removeWebhookErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[RemoveWebhookMethod][0] = test.removeWebhookErr
+
+		url := fmt.Sprintf(server.URL+WEBHOOK_ROOT_URL+"/%v", test.name)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		q := req.URL.Query()
+		q.Add("Offset", test.offset)
+		req.URL.RawQuery = q.Encode()
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			assert.Equal(t, test.name, testApi.ArgsIn[RemoveWebhookMethod][1], "Error in test case %v", n)
+		}
+
+		// check status code
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusNoContent:
+			// No message expected
+			continue
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestWorkerHandler_HandleListWebhookDeliveries(t *testing.T) {
+	now := time.Now().UTC()
+	testcases := map[string]struct {
+		// API method args
+		webhookName  string
+		filter       *api.Filter
+		ignoreArgsIn bool
+		// Expected result
+		expectedStatusCode int
+		expectedResponse   ListWebhookDeliveriesResponse
+		expectedError      api.Error
+		// Manager Results
+		listWebhookDeliveriesResult []api.WebhookDelivery
+		listWebhookDeliveriesTotal  int
+		// Manager Errors
+		listWebhookDeliveriesErr error
+	}{
+		"OkCase": {
+			webhookName: "webhook1",
+			filter: &api.Filter{
+				WebhookName: "webhook1",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ListWebhookDeliveriesResponse{
+				Deliveries: []api.WebhookDelivery{
+					{
+						ID:         "delivery1",
+						WebhookID:  "1",
+						EventID:    "event1",
+						EventType:  api.EVENT_USER_CREATED,
+						Attempt:    1,
+						StatusCode: http.StatusOK,
+						Success:    true,
+						CreateAt:   now,
+					},
+				},
+				Total: 1,
+			},
+			listWebhookDeliveriesResult: []api.WebhookDelivery{
+				{
+					ID:         "delivery1",
+					WebhookID:  "1",
+					EventID:    "event1",
+					EventType:  api.EVENT_USER_CREATED,
+					Attempt:    1,
+					StatusCode: http.StatusOK,
+					Success:    true,
+					CreateAt:   now,
+				},
+			},
+			listWebhookDeliveriesTotal: 1,
+		},
+		"ErrorCaseInvalidFilterParams": {
+			webhookName: "webhook1",
+			filter: &api.Filter{
+				Limit: -1,
+			},
+			ignoreArgsIn:       true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError: api.Error{
+				Code:    api.INVALID_PARAMETER_ERROR,
+				Message: "Invalid parameter: Limit -1",
+			},
+		},
+		"ErrorCaseWebhookNotFound": {
+			webhookName: "webhook1",
+			filter: &api.Filter{
+				WebhookName: "webhook1",
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError: api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook not found",
+			},
+			listWebhookDeliveriesErr: &api.Error{
+				Code:    api.WEBHOOK_BY_NAME_NOT_FOUND,
+				Message: "Webhook not found",
+			},
+		},
+		"ErrorCaseUnknownApiError": {
+			webhookName: "webhook1",
+			filter: &api.Filter{
+				WebhookName: "webhook1",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			listWebhookDeliveriesErr: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+		},
+	}
+
+	client := http.DefaultClient
+
+	for n, test := range testcases {
+
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][0] = test.listWebhookDeliveriesResult
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][1] = test.listWebhookDeliveriesTotal
+		testApi.ArgsOut[ListWebhookDeliveriesMethod][2] = test.listWebhookDeliveriesErr
+
+		url := fmt.Sprintf(server.URL+WEBHOOK_ROOT_URL+"/%v/deliveries", test.webhookName)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		addQueryParams(test.filter, req)
+
+		res, err := client.Do(req)
+		assert.Nil(t, err, "Error in test case %v", n)
+
+		if !test.ignoreArgsIn {
+			// Check received parameters
+			filterData, ok := testApi.ArgsIn[ListWebhookDeliveriesMethod][1].(*api.Filter)
+			if ok {
+				// Check result
+				assert.Equal(t, test.filter, filterData, "Error in test case %v", n)
+			}
+		}
+
+		assert.Equal(t, test.expectedStatusCode, res.StatusCode, "Error in test case %v", n)
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			listWebhookDeliveriesResponse := ListWebhookDeliveriesResponse{}
+			err = json.NewDecoder(res.Body).Decode(&listWebhookDeliveriesResponse)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check result
+			assert.Equal(t, test.expectedResponse, listWebhookDeliveriesResponse, "Error in test case %v", n)
+		case http.StatusInternalServerError: // Empty message so continue
+			continue
+		default:
+			apiError := api.Error{}
+			err = json.NewDecoder(res.Body).Decode(&apiError)
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Check error
+			assert.Equal(t, test.expectedError, apiError, "Error in test case %v", n)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// Namespace of all Foulkon metrics
+	NAMESPACE = "foulkon"
+
+	// Server label values
+	SERVER_WORKER = "worker"
+	SERVER_PROXY  = "proxy"
+
+	// Admin login result label values
+	ADMIN_LOGIN_SUCCESS    = "success"
+	ADMIN_LOGIN_FAILURE    = "failure"
+	ADMIN_LOGIN_LOCKED_OUT = "lockedout"
+)
+
+var (
+	// HTTP requests by server, route pattern, method and status code
+	HttpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by server, route, method and status code.",
+	}, []string{"server", "route", "method", "code"})
+
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by server, route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "route", "method", "code"})
+
+	// Authorization decisions by source and effect (allow, partial or deny)
+	AuthzDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "authz",
+		Name:      "decisions_total",
+		Help:      "Number of authorization decisions by source and effect.",
+	}, []string{"source", "effect"})
+
+	// Admin basic auth logins by result (success, failure or lockedout)
+	AdminLogins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "auth",
+		Name:      "admin_logins_total",
+		Help:      "Number of admin logins by result.",
+	}, []string{"result"})
+
+	// Database queries by repository method
+	DbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of database queries by repository method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// Proxy calls to upstream hosts by proxy resource
+	ProxyUpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "proxy",
+		Name:      "upstream_duration_seconds",
+		Help:      "Latency of upstream calls by proxy resource.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"org", "resource"})
+
+	ProxyUpstreamErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "proxy",
+		Name:      "upstream_errors_total",
+		Help:      "Number of failed upstream calls by proxy resource and error code.",
+	}, []string{"org", "resource", "code"})
+
+	// Proxy route table
+	ProxyReloads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "proxy",
+		Name:      "route_reloads_total",
+		Help:      "Number of proxy route table reloads.",
+	})
+
+	ProxyResources = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "proxy",
+		Name:      "resources",
+		Help:      "Number of proxy resources in current route table.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		HttpRequests,
+		HttpRequestDuration,
+		AuthzDecisions,
+		AdminLogins,
+		DbQueryDuration,
+		ProxyUpstreamDuration,
+		ProxyUpstreamErrors,
+		ProxyReloads,
+		ProxyResources,
+	)
+}
+
+// Handler returns the HTTP handler that exposes metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveHttpRequest records a request served since start
+func ObserveHttpRequest(server string, route string, method string, code int, start time.Time) {
+	statusCode := strconv.Itoa(code)
+	HttpRequests.WithLabelValues(server, route, method, statusCode).Inc()
+	HttpRequestDuration.WithLabelValues(server, route, method, statusCode).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDbQuery records a repository method call started at start. It's intended to be deferred
+func ObserveDbQuery(method string, start time.Time) {
+	DbQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
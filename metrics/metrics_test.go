@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	ObserveHttpRequest(SERVER_WORKER, "/api/v1/users", http.MethodGet, http.StatusOK, time.Now())
+	ObserveDbQuery("GetUserByExternalID", time.Now())
+	AuthzDecisions.WithLabelValues("worker", "allow").Inc()
+	AdminLogins.WithLabelValues(ADMIN_LOGIN_FAILURE).Inc()
+	ProxyReloads.Inc()
+	ProxyResources.Set(3)
+
+	testcases := map[string]struct {
+		expectedLines []string
+	}{
+		"OKCaseHttpRequests": {
+			expectedLines: []string{
+				`foulkon_http_requests_total{code="200",method="GET",route="/api/v1/users",server="worker"} 1`,
+				`foulkon_http_request_duration_seconds_count{code="200",method="GET",route="/api/v1/users",server="worker"} 1`,
+			},
+		},
+		"OKCaseDbQueries": {
+			expectedLines: []string{
+				`foulkon_db_query_duration_seconds_count{method="GetUserByExternalID"} 1`,
+			},
+		},
+		"OKCaseAuthzDecisions": {
+			expectedLines: []string{
+				`foulkon_authz_decisions_total{effect="allow",source="worker"} 1`,
+			},
+		},
+		"OKCaseAdminLogins": {
+			expectedLines: []string{
+				`foulkon_auth_admin_logins_total{result="failure"} 1`,
+			},
+		},
+		"OKCaseProxy": {
+			expectedLines: []string{
+				`foulkon_proxy_route_reloads_total 1`,
+				`foulkon_proxy_resources 3`,
+			},
+		},
+	}
+
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	for n, test := range testcases {
+		res, err := http.Get(server.URL)
+		assert.Nil(t, err, "Error in test case %v", n)
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Error in test case %v", n)
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Nil(t, err, "Error in test case %v", n)
+		for _, line := range test.expectedLines {
+			assert.True(t, strings.Contains(string(body), line), "Error in test case %v, line %v not found", n, line)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// Prefix of password hashes in argon2id PHC format: $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+	ARGON2ID_PREFIX = "$argon2id$"
+
+	// Time to keep verified admin credentials before computing their password hash again
+	ADMIN_CREDENTIALS_CACHE_TTL = time.Minute
+
+	// Max verified admin credentials kept
+	ADMIN_CREDENTIALS_CACHE_SIZE = 100
+)
+
+var (
+	// Hash compared when username isn't an admin, so response time doesn't reveal admin usernames
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+// AdminAccounts holds admin users that authenticate with basic auth, with their bcrypt or argon2id
+// password hashes. Passwords are never kept in plain text.
+type AdminAccounts struct {
+	hashes map[string]string
+
+	// Verified credentials, keyed by their SHA-256, so password hash isn't computed in each request
+	lock     sync.Mutex
+	verified map[[sha256.Size]byte]time.Time
+}
+
+// NewAdminAccounts returns an empty set of admin accounts
+func NewAdminAccounts() *AdminAccounts {
+	return &AdminAccounts{
+		hashes:   make(map[string]string),
+		verified: make(map[[sha256.Size]byte]time.Time),
+	}
+}
+
+// AddPasswordHash adds an admin with a bcrypt or argon2id password hash
+func (a *AdminAccounts) AddPasswordHash(username string, hash string) error {
+	if strings.TrimSpace(username) == "" {
+		return errors.New("Admin username can't be empty")
+	}
+	if _, ok := a.hashes[username]; ok {
+		return fmt.Errorf("Admin %v is defined more than once", username)
+	}
+	if err := validatePasswordHash(hash); err != nil {
+		return fmt.Errorf("Invalid password hash for admin %v: %v", username, err)
+	}
+	a.hashes[username] = hash
+	return nil
+}
+
+// AddPassword adds an admin with a plain text password, that is hashed with bcrypt
+func (a *AdminAccounts) AddPassword(username string, password string) error {
+	if strings.TrimSpace(password) == "" {
+		return fmt.Errorf("Admin %v password can't be empty", username)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return a.AddPasswordHash(username, string(hash))
+}
+
+// AddAccountsFile adds the admins of a file with a "username:hash" line for each one, like htpasswd
+// files created with bcrypt. Empty lines and lines starting with # are ignored.
+func (a *AdminAccounts) AddAccountsFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		separator := strings.Index(line, ":")
+		if separator < 0 {
+			return fmt.Errorf("Invalid admin accounts file %v, line %v must be username:hash", path, lineNumber)
+		}
+		if err := a.AddPasswordHash(line[:separator], line[separator+1:]); err != nil {
+			return fmt.Errorf("Invalid admin accounts file %v, line %v: %v", path, lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Authenticate checks password of an admin. Password hash comparison is constant time, and it's
+// computed for unknown usernames too.
+func (a *AdminAccounts) Authenticate(username string, password string) bool {
+	key := sha256.Sum256([]byte(username + "\x00" + password))
+	now := time.Now()
+
+	a.lock.Lock()
+	expireAt, ok := a.verified[key]
+	a.lock.Unlock()
+	if ok && now.Before(expireAt) {
+		return true
+	}
+
+	hash, ok := a.hashes[username]
+	if !ok {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("foulkon"), bcrypt.DefaultCost)
+		})
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	if !verifyPassword(hash, password) {
+		return false
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if len(a.verified) >= ADMIN_CREDENTIALS_CACHE_SIZE {
+		a.verified = make(map[[sha256.Size]byte]time.Time)
+	}
+	a.verified[key] = now.Add(ADMIN_CREDENTIALS_CACHE_TTL)
+	return true
+}
+
+// Usernames returns sorted usernames of admins
+func (a *AdminAccounts) Usernames() []string {
+	usernames := make([]string, 0, len(a.hashes))
+	for username := range a.hashes {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
+
+// PRIVATE HELPER METHODS
+
+// validatePasswordHash checks hash is a bcrypt or argon2id password hash
+func validatePasswordHash(hash string) error {
+	if strings.HasPrefix(hash, ARGON2ID_PREFIX) {
+		_, err := parseArgon2id(hash)
+		return err
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return errors.New("it must be a bcrypt or argon2id hash")
+	}
+	return nil
+}
+
+// verifyPassword compares password with a bcrypt or argon2id hash
+func verifyPassword(hash string, password string) bool {
+	if strings.HasPrefix(hash, ARGON2ID_PREFIX) {
+		params, err := parseArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+		return subtle.ConstantTimeCompare(key, params.key) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// argon2idParams are the values of an argon2id hash
+type argon2idParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// parseArgon2id parses an argon2id hash in PHC format
+func parseArgon2id(hash string) (*argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return nil, errors.New("argon2id hash must be $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2id version %v", parts[2])
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, fmt.Errorf("invalid argon2id parameters %v", parts[3])
+	}
+	if params.time < 1 || params.threads < 1 {
+		return nil, fmt.Errorf("invalid argon2id parameters %v", parts[3])
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errors.New("invalid argon2id salt")
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return nil, errors.New("invalid argon2id key")
+	}
+	return params, nil
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Aux method that hashes a password with bcrypt
+func bcryptHash(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Unexpected error hashing password %v", err)
+	}
+	return string(hash)
+}
+
+// Aux method that hashes a password with argon2id in PHC format
+func argon2idHash(password string) string {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte(password), salt, 1, 1024, 1, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=1024,t=1,p=1$%v$%v", argon2.Version,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestAdminAccounts_AddPasswordHash(t *testing.T) {
+	testcases := map[string]struct {
+		username string
+		hash     string
+		// Expected result
+		expectedError string
+	}{
+		"OkCaseBcrypt": {
+			username: "alice",
+			hash:     bcryptHash(t, "password"),
+		},
+		"OkCaseArgon2id": {
+			username: "alice",
+			hash:     argon2idHash("password"),
+		},
+		"ErrorCaseEmptyUsername": {
+			username:      " ",
+			hash:          bcryptHash(t, "password"),
+			expectedError: "Admin username can't be empty",
+		},
+		"ErrorCaseDuplicatedAdmin": {
+			username:      "admin",
+			hash:          bcryptHash(t, "password"),
+			expectedError: "Admin admin is defined more than once",
+		},
+		"ErrorCasePlainTextPassword": {
+			username:      "alice",
+			hash:          "password",
+			expectedError: "Invalid password hash for admin alice: it must be a bcrypt or argon2id hash",
+		},
+		"ErrorCaseArgon2idMalformed": {
+			username:      "alice",
+			hash:          "$argon2id$v=19$m=1024,t=1,p=1$salt",
+			expectedError: "Invalid password hash for admin alice: argon2id hash must be $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>",
+		},
+		"ErrorCaseArgon2idVersion": {
+			username:      "alice",
+			hash:          "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+			expectedError: "Invalid password hash for admin alice: unsupported argon2id version v=16",
+		},
+		"ErrorCaseArgon2idParams": {
+			username:      "alice",
+			hash:          "$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+			expectedError: "Invalid password hash for admin alice: invalid argon2id parameters m=1024,t=0,p=1",
+		},
+		"ErrorCaseArgon2idSalt": {
+			username:      "alice",
+			hash:          "$argon2id$v=19$m=1024,t=1,p=1$%%%$a2V5",
+			expectedError: "Invalid password hash for admin alice: invalid argon2id salt",
+		},
+	}
+
+	for n, testcase := range testcases {
+		admins := NewAdminAccounts()
+		assert.Nil(t, admins.AddPasswordHash("admin", bcryptHash(t, "admin")), "Error in test case %v", n)
+		err := admins.AddPasswordHash(testcase.username, testcase.hash)
+		if testcase.expectedError != "" {
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedError, err.Error(), "Error in test case %v", n)
+			}
+			assert.Equal(t, []string{"admin"}, admins.Usernames(), "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, []string{"admin", testcase.username}, admins.Usernames(), "Error in test case %v", n)
+		}
+	}
+}
+
+func TestAdminAccounts_Authenticate(t *testing.T) {
+	admins := NewAdminAccounts()
+	assert.Nil(t, admins.AddPasswordHash("alice", bcryptHash(t, "alicepassword")))
+	assert.Nil(t, admins.AddPasswordHash("bob", argon2idHash("bobpassword")))
+	assert.Nil(t, admins.AddPassword("carol", "carolpassword"))
+
+	testcases := map[string]struct {
+		username string
+		password string
+		// Expected result
+		expectedResult bool
+	}{
+		"OkCaseBcrypt": {
+			username:       "alice",
+			password:       "alicepassword",
+			expectedResult: true,
+		},
+		"OkCaseArgon2id": {
+			username:       "bob",
+			password:       "bobpassword",
+			expectedResult: true,
+		},
+		"OkCasePlainTextPassword": {
+			username:       "carol",
+			password:       "carolpassword",
+			expectedResult: true,
+		},
+		"ErrorCaseInvalidBcryptPassword": {
+			username: "alice",
+			password: "bobpassword",
+		},
+		"ErrorCaseInvalidArgon2idPassword": {
+			username: "bob",
+			password: "alicepassword",
+		},
+		"ErrorCaseUnknownAdmin": {
+			username: "dave",
+			password: "alicepassword",
+		},
+		"ErrorCaseEmptyPassword": {
+			username: "alice",
+		},
+	}
+
+	for n, testcase := range testcases {
+		// Check twice, so verified credentials are checked too
+		for i := 0; i < 2; i++ {
+			result := admins.Authenticate(testcase.username, testcase.password)
+			assert.Equal(t, testcase.expectedResult, result, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestAdminAccounts_AddAccountsFile(t *testing.T) {
+	testcases := map[string]struct {
+		content string
+		// Expected result
+		expectedUsernames []string
+		expectedError     string
+	}{
+		"OkCase": {
+			content:           fmt.Sprintf("# Foulkon admins\nalice:%v\n\nbob:%v\n", bcryptHash(t, "alicepassword"), argon2idHash("bobpassword")),
+			expectedUsernames: []string{"alice", "bob"},
+		},
+		"ErrorCaseMissingSeparator": {
+			content:       "alice\n",
+			expectedError: "Invalid admin accounts file %v, line 1 must be username:hash",
+		},
+		"ErrorCaseInvalidHash": {
+			content:       fmt.Sprintf("alice:%v\nbob:bobpassword\n", bcryptHash(t, "alicepassword")),
+			expectedError: "Invalid admin accounts file %v, line 2: Invalid password hash for admin bob: it must be a bcrypt or argon2id hash",
+		},
+	}
+
+	for n, testcase := range testcases {
+		file, err := ioutil.TempFile("", "foulkon-admins")
+		if err != nil {
+			t.Fatalf("Unexpected error creating file %v", err)
+		}
+		_, err = file.WriteString(testcase.content)
+		assert.Nil(t, err, "Error in test case %v", n)
+		file.Close()
+
+		admins := NewAdminAccounts()
+		err = admins.AddAccountsFile(file.Name())
+		os.Remove(file.Name())
+		if testcase.expectedError != "" {
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, fmt.Sprintf(testcase.expectedError, file.Name()), err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, testcase.expectedUsernames, admins.Usernames(), "Error in test case %v", n)
+		}
+	}
+
+	// Missing file
+	err := NewAdminAccounts().AddAccountsFile("/nonexistent/foulkon-admins")
+	assert.NotNil(t, err)
+}
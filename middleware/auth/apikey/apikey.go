@@ -0,0 +1,87 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+)
+
+const (
+	// Authorization scheme of API keys
+	AUTHORIZATION_SCHEME = "ApiKey "
+)
+
+// Authenticator validates an API key, returning the externalId of its service account
+type Authenticator interface {
+	AuthenticateApiKey(id string, secret string) (string, error)
+}
+
+// ApiKeyAuthConnector represents a connector that implements interface of auth connector, validating
+// API keys of service accounts sent as "Authorization: ApiKey <id>.<secret>"
+type ApiKeyAuthConnector struct {
+	authenticator Authenticator
+}
+
+// InitApiKeyConnector initializes API key connector configuration
+func InitApiKeyConnector(authenticator Authenticator) auth.AuthConnector {
+	return &ApiKeyAuthConnector{
+		authenticator: authenticator,
+	}
+}
+
+// Authenticate validates API key of request and adds its service account to request
+func (c ApiKeyAuthConnector) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		userID, err := c.validate(r)
+		if err != nil {
+			apiError := &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: fmt.Sprintf("apikey authenticator: %v", err),
+			}
+			requestID := r.Header.Get(middleware.REQUEST_ID_HEADER)
+			api.LogOperationError(requestID, "", apiError)
+			http.Error(rw, fmt.Sprintf("Error %v", apiError.Message), http.StatusUnauthorized)
+			return
+		}
+		r.Header.Add(middleware.USER_ID_HEADER, userID)
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Recognizes checks request has an API key authorization header
+func (c ApiKeyAuthConnector) Recognizes(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), AUTHORIZATION_SCHEME)
+}
+
+// RetrieveUserID retrieves user from validated API key
+func (c ApiKeyAuthConnector) RetrieveUserID(r http.Request) string {
+	return r.Header.Get(middleware.USER_ID_HEADER)
+}
+
+// validate checks API key of request, returning the externalId of its service account
+func (c ApiKeyAuthConnector) validate(r *http.Request) (string, error) {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, AUTHORIZATION_SCHEME) {
+		return "", errors.New("no API key found")
+	}
+
+	key := strings.TrimPrefix(authorization, AUTHORIZATION_SCHEME)
+	separator := strings.Index(key, ".")
+	if separator <= 0 || separator == len(key)-1 {
+		return "", errors.New("malformed API key, it must be <id>.<secret>")
+	}
+
+	userID, err := c.authenticator.AuthenticateApiKey(key[:separator], key[separator+1:])
+	if err != nil {
+		if apiError, ok := err.(*api.Error); ok {
+			return "", errors.New(apiError.Message)
+		}
+		return "", err
+	}
+	return userID, nil
+}
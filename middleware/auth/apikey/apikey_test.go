@@ -0,0 +1,121 @@
+package apikey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthenticator struct {
+	id     string
+	secret string
+	userID string
+	err    error
+}
+
+func (a *testAuthenticator) AuthenticateApiKey(id string, secret string) (string, error) {
+	a.id = id
+	a.secret = secret
+	return a.userID, a.err
+}
+
+func TestApiKeyAuthConnector_Authenticate(t *testing.T) {
+	testcases := map[string]struct {
+		authorization string
+		// Authenticator results
+		userID string
+		err    error
+		// Expected results
+		expectedID         string
+		expectedSecret     string
+		expectedStatusCode int
+		expectedUserID     string
+		expectedLog        string
+	}{
+		"OkCase": {
+			authorization:      "ApiKey keyid.secret",
+			userID:             "ci-job",
+			expectedID:         "keyid",
+			expectedSecret:     "secret",
+			expectedStatusCode: http.StatusOK,
+			expectedUserID:     "ci-job",
+		},
+		"OkCaseSecretWithSeparator": {
+			authorization:      "ApiKey keyid.sec.ret",
+			userID:             "ci-job",
+			expectedID:         "keyid",
+			expectedSecret:     "sec.ret",
+			expectedStatusCode: http.StatusOK,
+			expectedUserID:     "ci-job",
+		},
+		"ErrorCaseNoApiKey": {
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "apikey authenticator: no API key found",
+		},
+		"ErrorCaseOtherScheme": {
+			authorization:      "Bearer token",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "apikey authenticator: no API key found",
+		},
+		"ErrorCaseMalformedApiKey": {
+			authorization:      "ApiKey keyid",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "apikey authenticator: malformed API key, it must be <id>.<secret>",
+		},
+		"ErrorCaseEmptySecret": {
+			authorization:      "ApiKey keyid.",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "apikey authenticator: malformed API key, it must be <id>.<secret>",
+		},
+		"ErrorCaseAuthenticatorError": {
+			authorization: "ApiKey keyid.secret",
+			err: &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: "Invalid API key keyid",
+			},
+			expectedID:         "keyid",
+			expectedSecret:     "secret",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "apikey authenticator: Invalid API key keyid",
+		},
+	}
+	for n, testcase := range testcases {
+		testLogger, hook := test.NewNullLogger()
+		api.Log = testLogger
+		authenticator := &testAuthenticator{
+			userID: testcase.userID,
+			err:    testcase.err,
+		}
+		connector := InitApiKeyConnector(authenticator)
+		var userID string
+		handler := connector.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID = connector.RetrieveUserID(*r)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if testcase.authorization != "" {
+			req.Header.Set("Authorization", testcase.authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		// Check authenticator args
+		assert.Equal(t, testcase.expectedID, authenticator.id, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedSecret, authenticator.secret, "Error in test case %v", n)
+		// Check status code
+		assert.Equal(t, testcase.expectedStatusCode, w.Code, "Error in test case %v", n)
+		if testcase.expectedStatusCode == http.StatusOK {
+			assert.Equal(t, testcase.expectedUserID, userID, "Error in test case %v", n)
+			assert.Equal(t, testcase.expectedUserID, req.Header.Get(middleware.USER_ID_HEADER), "Error in test case %v", n)
+		} else {
+			// Check logger
+			if assert.NotNil(t, hook.LastEntry(), "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedLog, hook.LastEntry().Message, "Error in test case %v", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Tecsisa/foulkon/middleware"
+)
+
+const (
+	// Name of authenticator connector recorded for admin users
+	ADMIN_CONNECTOR = "admin"
+)
+
+// Recognizer is implemented by connectors that can tell if a request has credentials for them,
+// without validating them. Connectors that don't implement it recognize every request.
+type Recognizer interface {
+	Recognizes(r *http.Request) bool
+}
+
+// NamedConnector is an authenticator connector with the name of its type
+type NamedConnector struct {
+	Name      string
+	Connector AuthConnector
+}
+
+// ChainConnector represents a connector that implements interface of auth connector with an ordered
+// list of connectors. The first one that recognizes the request authenticates it, and its name is
+// added to request. If none recognizes it, the last one rejects it.
+type ChainConnector struct {
+	connectors []NamedConnector
+}
+
+// NewChainConnector returns a connector that tries connectors in order. There must be at least one.
+func NewChainConnector(connectors ...NamedConnector) *ChainConnector {
+	return &ChainConnector{
+		connectors: connectors,
+	}
+}
+
+// Authenticate delegates request to the first connector that recognizes it
+func (c ChainConnector) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connector := c.recognizer(r)
+		authenticated := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set(middleware.AUTH_CONNECTOR_HEADER, connector.Name)
+			next.ServeHTTP(w, r)
+		})
+		connector.Connector.Authenticate(authenticated).ServeHTTP(w, r)
+	})
+}
+
+// RetrieveUserID retrieves user from the connector that authenticated request
+func (c ChainConnector) RetrieveUserID(r http.Request) string {
+	name := r.Header.Get(middleware.AUTH_CONNECTOR_HEADER)
+	for _, connector := range c.connectors {
+		if connector.Name == name {
+			return connector.Connector.RetrieveUserID(r)
+		}
+	}
+	return ""
+}
+
+// Names returns names of connectors in order
+func (c ChainConnector) Names() []string {
+	names := make([]string, 0, len(c.connectors))
+	for _, connector := range c.connectors {
+		names = append(names, connector.Name)
+	}
+	return names
+}
+
+// recognizer returns the first connector that recognizes request, or the last one
+func (c ChainConnector) recognizer(r *http.Request) NamedConnector {
+	for _, connector := range c.connectors {
+		recognizer, ok := connector.Connector.(Recognizer)
+		if !ok || recognizer.Recognizes(r) {
+			return connector
+		}
+	}
+	return c.connectors[len(c.connectors)-1]
+}
+
+// BearerTokenIssuer returns the "iss" claim of the bearer JWT of request, without verifying it.
+// It's empty if request has no bearer JWT or it has no issuer.
+func BearerTokenIssuer(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ""
+	}
+	claims := struct {
+		Issuer string `json:"iss"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
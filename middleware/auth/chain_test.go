@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// Aux connector that recognizes requests with a header
+type TestRecognizerConnector struct {
+	header string
+}
+
+func (tc TestRecognizerConnector) Authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get(tc.header)
+		if userID == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.Header.Set(middleware.USER_ID_HEADER, userID)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (tc TestRecognizerConnector) RetrieveUserID(r http.Request) string {
+	return r.Header.Get(middleware.USER_ID_HEADER)
+}
+
+func (tc TestRecognizerConnector) Recognizes(r *http.Request) bool {
+	return r.Header.Get(tc.header) != ""
+}
+
+// Aux method that returns an unsigned JWT with a payload
+func testBearerToken(payload string) string {
+	return "Bearer e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+func TestChainConnector_Authenticate(t *testing.T) {
+	testcases := map[string]struct {
+		connectors []NamedConnector
+		// Request headers
+		headers map[string]string
+
+		expectedStatusCode    int
+		expectedUserID        string
+		expectedAuthConnector string
+	}{
+		"OkCaseFirstConnector": {
+			connectors: []NamedConnector{
+				{Name: "first", Connector: TestRecognizerConnector{header: "X-First"}},
+				{Name: "second", Connector: TestRecognizerConnector{header: "X-Second"}},
+			},
+			headers:               map[string]string{"X-First": "user1", "X-Second": "user2"},
+			expectedStatusCode:    http.StatusOK,
+			expectedUserID:        "user1",
+			expectedAuthConnector: "first",
+		},
+		"OkCaseSecondConnector": {
+			connectors: []NamedConnector{
+				{Name: "first", Connector: TestRecognizerConnector{header: "X-First"}},
+				{Name: "second", Connector: TestRecognizerConnector{header: "X-Second"}},
+			},
+			headers:               map[string]string{"X-Second": "user2"},
+			expectedStatusCode:    http.StatusOK,
+			expectedUserID:        "user2",
+			expectedAuthConnector: "second",
+		},
+		"OkCaseConnectorWithoutRecognizer": {
+			connectors: []NamedConnector{
+				{Name: "first", Connector: &TestConnector{userID: "user3"}},
+				{Name: "second", Connector: TestRecognizerConnector{header: "X-Second"}},
+			},
+			headers:               map[string]string{"X-Second": "user2"},
+			expectedStatusCode:    http.StatusOK,
+			expectedUserID:        "user3",
+			expectedAuthConnector: "first",
+		},
+		"ErrorCaseNotRecognized": {
+			connectors: []NamedConnector{
+				{Name: "first", Connector: TestRecognizerConnector{header: "X-First"}},
+				{Name: "second", Connector: TestRecognizerConnector{header: "X-Second"}},
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for n, testcase := range testcases {
+		connector := NewChainConnector(testcase.connectors...)
+		var userID, authConnector string
+		handler := connector.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID = connector.RetrieveUserID(*r)
+			authConnector = r.Header.Get(middleware.AUTH_CONNECTOR_HEADER)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for key, value := range testcase.headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, testcase.expectedStatusCode, w.Code, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedUserID, userID, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedAuthConnector, authConnector, "Error in test case %v", n)
+	}
+}
+
+func TestChainConnector_Names(t *testing.T) {
+	connector := NewChainConnector(
+		NamedConnector{Name: "oidc", Connector: &TestConnector{}},
+		NamedConnector{Name: "apikey", Connector: &TestConnector{}},
+	)
+	assert.Equal(t, []string{"oidc", "apikey"}, connector.Names())
+}
+
+func TestBearerTokenIssuer(t *testing.T) {
+	testcases := map[string]struct {
+		authorization string
+
+		expectedIssuer string
+	}{
+		"OkCase": {
+			authorization:  testBearerToken(`{"iss":"https://tokens.example.com","sub":"user1"}`),
+			expectedIssuer: "https://tokens.example.com",
+		},
+		"OkCaseNoIssuer": {
+			authorization: testBearerToken(`{"sub":"user1"}`),
+		},
+		"OkCaseNoBearer": {
+			authorization: "ApiKey id.secret",
+		},
+		"OkCaseNotJWT": {
+			authorization: "Bearer opaque-token",
+		},
+		"OkCaseInvalidPayload": {
+			authorization: testBearerToken(`{"iss":`),
+		},
+	}
+
+	for n, testcase := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", testcase.authorization)
+		assert.Equal(t, testcase.expectedIssuer, BearerTokenIssuer(req), "Error in test case %v", n)
+	}
+}
@@ -1,28 +1,54 @@
 package auth
 
 import (
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/metrics"
 	"github.com/Tecsisa/foulkon/middleware"
 )
 
 // Authenticator middleware system, with connector and basic admin authentication
 type AuthenticatorMiddleware struct {
-	connector     AuthConnector
-	adminUser     string
-	adminPassword string
+	// Lock to change connector and admin accounts while serving
+	lock      sync.RWMutex
+	connector AuthConnector
+	admins    *AdminAccounts
+
+	// Failed admin logins, kept when connector and admin accounts change
+	lockout *AdminLockout
 }
 
 // NewAuthenticator returns a configured AuthenticatorMiddleware with associated connector
-func NewAuthenticatorMiddleware(connector AuthConnector, adminUser string, adminPassword string) *AuthenticatorMiddleware {
+func NewAuthenticatorMiddleware(connector AuthConnector, admins *AdminAccounts, lockout *AdminLockout) *AuthenticatorMiddleware {
 	return &AuthenticatorMiddleware{
-		connector:     connector,
-		adminUser:     adminUser,
-		adminPassword: adminPassword,
+		connector: connector,
+		admins:    admins,
+		lockout:   lockout,
 	}
 }
 
+// Update replaces connector and admin accounts, used when configuration is reloaded
+func (a *AuthenticatorMiddleware) Update(connector AuthConnector, admins *AdminAccounts) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.connector = connector
+	a.admins = admins
+}
+
+// current returns connector and admin accounts in use
+func (a *AuthenticatorMiddleware) current() (AuthConnector, *AdminAccounts) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.connector, a.admins
+}
+
 // Interface for authentication that connectors implement
 type AuthConnector interface {
 	Authenticate(next http.Handler) http.Handler
@@ -31,51 +57,78 @@ type AuthConnector interface {
 
 func (a *AuthenticatorMiddleware) Action(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var handler http.Handler
 		requestID := r.Header.Get(middleware.REQUEST_ID_HEADER)
-		if isAdmin(r, a.adminUser, a.adminPassword) {
-			// Admin check
-			r.Header.Add(middleware.USER_ID_HEADER, a.adminUser)
-			handler = next
-		} else {
-			if a.connector != nil {
-				// Connector
-				handler = a.connector.Authenticate(next)
-			} else {
-				// Error response when there isn't any authentication connector
+		// User ID, connector and groups headers are only set by authenticators, never trusted from client
+		r.Header.Del(middleware.USER_ID_HEADER)
+		r.Header.Del(middleware.AUTH_CONNECTOR_HEADER)
+		r.Header.Del(middleware.AUTH_GROUPS_HEADER)
+		connector, admins := a.current()
+		if username, password, ok := r.BasicAuth(); ok {
+			// Admin check, rejected while source IP or username are locked out
+			ip := sourceIP(r)
+			if retryAfter, locked := a.lockout.Check(ip, username); locked {
+				metrics.AdminLogins.WithLabelValues(metrics.ADMIN_LOGIN_LOCKED_OUT).Inc()
 				apiError := &api.Error{
-					Code:    api.AUTHENTICATION_API_ERROR,
-					Message: "No Authenticator Provider configured",
+					Code: api.ADMIN_LOCKED_OUT_ERROR,
+					Message: fmt.Sprintf("Too many failed admin logins from %v or for admin %v, locked out for %v",
+						ip, username, retryAfter),
 				}
-				api.LogOperationError(requestID, "", apiError)
-				http.Error(w, "Authentication failed", http.StatusUnauthorized)
+				api.LogOperationError(requestID, username, apiError)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, "Too many failed admin logins, try again later", http.StatusTooManyRequests)
+				return
+			}
+			if admins.Authenticate(username, password) {
+				a.lockout.Succeed(ip, username)
+				metrics.AdminLogins.WithLabelValues(metrics.ADMIN_LOGIN_SUCCESS).Inc()
+				r.Header.Add(middleware.USER_ID_HEADER, username)
+				r.Header.Set(middleware.AUTH_CONNECTOR_HEADER, ADMIN_CONNECTOR)
+				next.ServeHTTP(w, r)
 				return
 			}
+			a.lockout.Fail(ip, username)
+			metrics.AdminLogins.WithLabelValues(metrics.ADMIN_LOGIN_FAILURE).Inc()
+			api.LogOperationError(requestID, username, &api.Error{
+				Code:    api.ADMIN_AUTHENTICATION_ERROR,
+				Message: "Trying to connect as admin, admin user/password invalid, delegating to connector...",
+			})
 		}
 
-		handler.ServeHTTP(w, r)
+		if connector == nil {
+			// Error response when there isn't any authentication connector
+			apiError := &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: "No Authenticator Provider configured",
+			}
+			api.LogOperationError(requestID, "", apiError)
+			http.Error(w, "Authentication failed", http.StatusUnauthorized)
+			return
+		}
+
+		// Connector
+		connector.Authenticate(next).ServeHTTP(w, r)
 	})
 }
 
 func (a *AuthenticatorMiddleware) GetInfo(r *http.Request, mc *middleware.MiddlewareContext) {
-	mc.UserId, mc.Admin = a.getAuthenticatedUser(r)
-}
-
-// getAuthenticatedUser retrieves user from request
-func (a *AuthenticatorMiddleware) getAuthenticatedUser(r *http.Request) (string, bool) {
-	if isAdmin(r, a.adminUser, a.adminPassword) {
-		return a.adminUser, true
+	mc.AuthConnector = r.Header.Get(middleware.AUTH_CONNECTOR_HEADER)
+	// Admin credentials are only checked by Action, that counts failed logins
+	if mc.AuthConnector == ADMIN_CONNECTOR {
+		mc.UserId, mc.Admin = r.Header.Get(middleware.USER_ID_HEADER), true
+		return
+	}
+	connector, _ := a.current()
+	mc.UserId = connector.RetrieveUserID(*r)
+	if groups := r.Header.Get(middleware.AUTH_GROUPS_HEADER); groups != "" {
+		mc.MappedGroups = strings.Split(groups, ",")
 	}
-	return a.connector.RetrieveUserID(*r), false
 }
 
-func isAdmin(r *http.Request, adminUser string, adminPassword string) bool {
-	username, password, ok := r.BasicAuth()
-	isAdmin := username == adminUser && password == adminPassword
-	if ok && !isAdmin {
-		msg := "Trying to connect as admin, admin user/password invalid, delegating to connector..."
-		api.LogOperationWarn(r.Header.Get(middleware.REQUEST_ID_HEADER), username, msg)
+// sourceIP returns IP address of request connection
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
-	// Password is never stored in DB
-	return ok && isAdmin
+	return host
 }
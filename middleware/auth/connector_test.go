@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Sirupsen/logrus/hooks/test"
 	"github.com/Tecsisa/foulkon/api"
@@ -36,6 +37,15 @@ func (tc TestConnector) RetrieveUserID(r http.Request) string {
 	return tc.userID
 }
 
+// Aux method that returns admin accounts with an admin user
+func newTestAdminAccounts(t *testing.T, username string, password string) *AdminAccounts {
+	admins := NewAdminAccounts()
+	if err := admins.AddPassword(username, password); err != nil {
+		t.Fatalf("Unexpected error adding admin %v", err)
+	}
+	return admins
+}
+
 func TestAuthenticatorMiddleware_Action(t *testing.T) {
 	testMessage := "TestMessage"
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -49,6 +59,7 @@ func TestAuthenticatorMiddleware_Action(t *testing.T) {
 		// Middleware args
 		userID             string
 		password           string
+		userIDHeader       string
 		unauthenticated    bool
 		admin              bool
 		expectedLog        string
@@ -67,6 +78,14 @@ func TestAuthenticatorMiddleware_Action(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			admin:              true,
 		},
+		"OkCaseAdminWithUserIDHeader": {
+			userID:             "admin",
+			password:           "admin",
+			userIDHeader:       "other",
+			unauthenticated:    false,
+			expectedStatusCode: http.StatusOK,
+			admin:              true,
+		},
 		"OkCaseInvalidAdmin": {
 			userID:             "admin",
 			password:           "fail",
@@ -88,17 +107,21 @@ func TestAuthenticatorMiddleware_Action(t *testing.T) {
 		},
 	}
 
+	admins := newTestAdminAccounts(t, "admin", "admin")
 	for n, testcase := range testcases {
 		var mw *AuthenticatorMiddleware
 		if testcase.testConnectorNull {
-			mw = NewAuthenticatorMiddleware(nil, "admin", "admin")
+			mw = NewAuthenticatorMiddleware(nil, admins, NewAdminLockout(5, time.Minute, time.Hour))
 		} else {
-			mw = NewAuthenticatorMiddleware(&TestConnector{userID: testcase.userID, unauthenticated: testcase.unauthenticated}, "admin", "admin")
+			mw = NewAuthenticatorMiddleware(&TestConnector{userID: testcase.userID, unauthenticated: testcase.unauthenticated}, admins, NewAdminLockout(5, time.Minute, time.Hour))
 		}
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		if testcase.admin {
 			req.SetBasicAuth(testcase.userID, testcase.password)
 		}
+		if testcase.userIDHeader != "" {
+			req.Header.Set(middleware.USER_ID_HEADER, testcase.userIDHeader)
+		}
 		w := httptest.NewRecorder()
 		mw.Action(testHandler).ServeHTTP(w, req)
 		res := w.Result()
@@ -136,28 +159,54 @@ func TestAuthenticatorMiddleware_GetInfo(t *testing.T) {
 		password           string
 		unauthenticated    bool
 		admin              bool
+		chain              bool
+		groupsHeader       string
 		expectedStatusCode int
+
+		expectedAuthConnector string
+		expectedMappedGroups  []string
 	}{
 		"OkCase": {
 			userID:             "UserId",
 			unauthenticated:    false,
 			expectedStatusCode: http.StatusOK,
 		},
-		"OkCaseAdmin": {
-			userID:             "admin",
-			password:           "admin",
-			unauthenticated:    false,
+		"OkCaseChainConnector": {
+			userID:                "UserId",
+			chain:                 true,
+			expectedStatusCode:    http.StatusOK,
+			expectedAuthConnector: "test",
+		},
+		"OkCaseClientGroupsHeaderDropped": {
+			userID:             "UserId",
+			groupsHeader:       "acme/admins",
 			expectedStatusCode: http.StatusOK,
-			admin:              true,
+		},
+		"OkCaseAdmin": {
+			userID:                "admin",
+			password:              "admin",
+			unauthenticated:       false,
+			chain:                 true,
+			expectedStatusCode:    http.StatusOK,
+			admin:                 true,
+			expectedAuthConnector: ADMIN_CONNECTOR,
 		},
 	}
 
+	admins := newTestAdminAccounts(t, "admin", "admin")
 	for n, testcase := range testcases {
-		mw := NewAuthenticatorMiddleware(&TestConnector{userID: testcase.userID, unauthenticated: testcase.unauthenticated}, "admin", "admin")
+		var connector AuthConnector = &TestConnector{userID: testcase.userID, unauthenticated: testcase.unauthenticated}
+		if testcase.chain {
+			connector = NewChainConnector(NamedConnector{Name: "test", Connector: connector})
+		}
+		mw := NewAuthenticatorMiddleware(connector, admins, NewAdminLockout(5, time.Minute, time.Hour))
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		if testcase.admin {
 			req.SetBasicAuth(testcase.userID, testcase.password)
 		}
+		if testcase.groupsHeader != "" {
+			req.Header.Set(middleware.AUTH_GROUPS_HEADER, testcase.groupsHeader)
+		}
 		w := httptest.NewRecorder()
 		mw.Action(testHandler).ServeHTTP(w, req)
 		mc := new(middleware.MiddlewareContext)
@@ -167,5 +216,132 @@ func TestAuthenticatorMiddleware_GetInfo(t *testing.T) {
 		assert.Equal(t, testcase.userID, mc.UserId, "Error in test case %v", n)
 		// Check admin privilege
 		assert.Equal(t, testcase.admin, mc.Admin, "Error in test case %v", n)
+		// Check connector that authenticated user
+		assert.Equal(t, testcase.expectedAuthConnector, mc.AuthConnector, "Error in test case %v", n)
+		// Check groups mapped by connector
+		assert.Equal(t, testcase.expectedMappedGroups, mc.MappedGroups, "Error in test case %v", n)
+	}
+}
+
+func TestAuthenticatorMiddleware_Update(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	testcases := map[string]struct {
+		// Request args
+		userID   string
+		password string
+		admin    bool
+		// Expected result
+		expectedUserID string
+		expectedAdmin  bool
+	}{
+		"OkCaseNewAdmin": {
+			userID:         "newadmin",
+			password:       "newpassword",
+			admin:          true,
+			expectedUserID: "newadmin",
+			expectedAdmin:  true,
+		},
+		"OkCaseOldAdmin": {
+			userID:         "admin",
+			password:       "admin",
+			admin:          true,
+			expectedUserID: "NewUserId",
+		},
+		"OkCaseNewConnector": {
+			expectedUserID: "NewUserId",
+		},
+	}
+
+	admins := newTestAdminAccounts(t, "admin", "admin")
+	newAdmins := newTestAdminAccounts(t, "newadmin", "newpassword")
+	for n, testcase := range testcases {
+		mw := NewAuthenticatorMiddleware(&TestConnector{userID: "UserId"}, admins, NewAdminLockout(5, time.Minute, time.Hour))
+		mw.Update(&TestConnector{userID: "NewUserId"}, newAdmins)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if testcase.admin {
+			req.SetBasicAuth(testcase.userID, testcase.password)
+		}
+		mw.Action(testHandler).ServeHTTP(httptest.NewRecorder(), req)
+		mc := new(middleware.MiddlewareContext)
+		mw.GetInfo(req, mc)
+
+		// Check user id
+		assert.Equal(t, testcase.expectedUserID, mc.UserId, "Error in test case %v", n)
+		// Check admin privilege
+		assert.Equal(t, testcase.expectedAdmin, mc.Admin, "Error in test case %v", n)
+	}
+}
+
+func TestAuthenticatorMiddleware_ActionLockout(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	testLogger, hook := test.NewNullLogger()
+	api.Log = testLogger
+	admins := newTestAdminAccounts(t, "admin", "admin")
+	mw := NewAuthenticatorMiddleware(&TestConnector{userID: "UserId"}, admins, NewAdminLockout(2, time.Minute, time.Hour))
+
+	// Requests in order, from same source IP
+	testcases := []struct {
+		username string
+		password string
+		// Expected result
+		expectedStatusCode int
+		expectedErrorCode  string
+		expectedRetryAfter string
+	}{
+		{
+			username:           "admin",
+			password:           "fail",
+			expectedStatusCode: http.StatusOK,
+			expectedErrorCode:  api.ADMIN_AUTHENTICATION_ERROR,
+		},
+		{
+			username:           "admin",
+			password:           "admin",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			username:           "admin",
+			password:           "fail",
+			expectedStatusCode: http.StatusOK,
+			expectedErrorCode:  api.ADMIN_AUTHENTICATION_ERROR,
+		},
+		{
+			username:           "other",
+			password:           "fail",
+			expectedStatusCode: http.StatusOK,
+			expectedErrorCode:  api.ADMIN_AUTHENTICATION_ERROR,
+		},
+		{
+			username:           "admin",
+			password:           "admin",
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedErrorCode:  api.ADMIN_LOCKED_OUT_ERROR,
+			expectedRetryAfter: "60",
+		},
+	}
+
+	for n, testcase := range testcases {
+		hook.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(testcase.username, testcase.password)
+		w := httptest.NewRecorder()
+		mw.Action(testHandler).ServeHTTP(w, req)
+
+		// Check status code
+		assert.Equal(t, testcase.expectedStatusCode, w.Code, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedRetryAfter, w.Header().Get("Retry-After"), "Error in test case %v", n)
+		// Check logger
+		if testcase.expectedErrorCode != "" {
+			if assert.NotNil(t, hook.LastEntry(), "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedErrorCode, hook.LastEntry().Data["errorCode"], "Error in test case %v", n)
+				assert.Equal(t, testcase.username, hook.LastEntry().Data["user"], "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, hook.LastEntry(), "Error in test case %v", n)
+		}
 	}
 }
@@ -40,6 +40,11 @@ func (h HeaderAuthConnector) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// Recognizes checks request has auth header
+func (h HeaderAuthConnector) Recognizes(r *http.Request) bool {
+	return r.Header.Get(h.header) != ""
+}
+
 // RetrieveUserID retrieves user from header
 func (h HeaderAuthConnector) RetrieveUserID(r http.Request) string {
 	return r.Header.Get(h.header)
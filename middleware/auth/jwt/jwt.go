@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// Default claim with user ID
+	DEFAULT_USER_CLAIM = "sub"
+)
+
+// JWTAuthConnector represents a connector that implements interface of auth connector, validating
+// bearer JWTs with public keys of a KeySet, without OIDC discovery
+type JWTAuthConnector struct {
+	keySet    *KeySet
+	issuer    string
+	audience  string
+	userClaim string
+	parser    *jwtgo.Parser
+}
+
+// InitJWTConnector initializes JWT connector configuration. Tokens must be signed with a key of keySet,
+// and have the user ID in userClaim. Issuer and audience are only checked if they aren't empty.
+func InitJWTConnector(keySet *KeySet, issuer string, audience string, userClaim string) auth.AuthConnector {
+	return &JWTAuthConnector{
+		keySet:    keySet,
+		issuer:    issuer,
+		audience:  audience,
+		userClaim: userClaim,
+		parser: &jwtgo.Parser{
+			ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"},
+		},
+	}
+}
+
+// Authenticate validates bearer token of request and adds its user ID to request
+func (c JWTAuthConnector) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		userID, err := c.validate(r)
+		if err != nil {
+			apiError := &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: fmt.Sprintf("jwt authenticator: %v", err),
+			}
+			requestID := r.Header.Get(middleware.REQUEST_ID_HEADER)
+			api.LogOperationError(requestID, "", apiError)
+			http.Error(rw, fmt.Sprintf("Error %v", apiError.Message), http.StatusUnauthorized)
+			return
+		}
+		r.Header.Add(middleware.USER_ID_HEADER, userID)
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Recognizes checks request has a bearer token, from the expected issuer if there is one
+func (c JWTAuthConnector) Recognizes(r *http.Request) bool {
+	if c.issuer != "" {
+		return auth.BearerTokenIssuer(r) == c.issuer
+	}
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// RetrieveUserID retrieves user from validated token
+func (c JWTAuthConnector) RetrieveUserID(r http.Request) string {
+	return r.Header.Get(middleware.USER_ID_HEADER)
+}
+
+// validate checks bearer token of request, returning its user ID
+func (c JWTAuthConnector) validate(r *http.Request) (string, error) {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return "", errors.New("no bearer token found")
+	}
+
+	claims := jwtgo.MapClaims{}
+	if _, err := c.parser.ParseWithClaims(strings.TrimPrefix(authorization, "Bearer "), claims, c.getKey); err != nil {
+		return "", err
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return "", errors.New("token has no expiration time")
+	}
+	if c.issuer != "" && !claims.VerifyIssuer(c.issuer, true) {
+		return "", fmt.Errorf("unexpected issuer %v", claims["iss"])
+	}
+	if c.audience != "" && !hasAudience(claims, c.audience) {
+		return "", fmt.Errorf("unexpected audience %v", claims["aud"])
+	}
+
+	userID, _ := claims[c.userClaim].(string)
+	if userID == "" {
+		return "", fmt.Errorf("no user ID found in claim %v", c.userClaim)
+	}
+	return userID, nil
+}
+
+// getKey returns public key that verifies token, checking it matches token signing method
+func (c JWTAuthConnector) getKey(token *jwtgo.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	key, err := c.keySet.Key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch token.Method.(type) {
+		case *jwtgo.SigningMethodRSA, *jwtgo.SigningMethodRSAPSS:
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := token.Method.(*jwtgo.SigningMethodECDSA); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method %v for key %v", token.Method.Alg(), keyID)
+}
+
+// hasAudience checks audience claim, a string or a list of strings, contains audience
+func hasAudience(claims jwtgo.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, value := range aud {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
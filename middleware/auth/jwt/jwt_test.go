@@ -0,0 +1,241 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var rsaKey *rsa.PrivateKey
+var ecKey *ecdsa.PrivateKey
+
+func TestMain(m *testing.M) {
+	testLogger, _ := test.NewNullLogger()
+	api.Log = testLogger
+
+	var err error
+	if rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		panic(err)
+	}
+	if ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// Aux method that signs a token with claims
+func signToken(t *testing.T, method jwtgo.SigningMethod, key interface{}, keyID string, claims jwtgo.MapClaims) string {
+	token := jwtgo.NewWithClaims(method, claims)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Unexpected error signing token %v", err)
+	}
+	return signed
+}
+
+func TestJWTAuthConnector_Authenticate(t *testing.T) {
+	keySet := &KeySet{
+		keys: map[string]interface{}{
+			"rsa": &rsaKey.PublicKey,
+			"ec":  &ecKey.PublicKey,
+		},
+	}
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	exp := time.Now().Add(time.Hour).Unix()
+	testcases := map[string]struct {
+		// Connector args
+		issuer    string
+		audience  string
+		userClaim string
+		// Request token
+		authorization string
+
+		expectedUserID     string
+		expectedStatusCode int
+		expectedLog        string
+	}{
+		"OKCaseRSA": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedUserID:     "user1",
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseECWithIssuerAndAudience": {
+			issuer:    "https://tokens.example.com",
+			audience:  "foulkon",
+			userClaim: "email",
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodES256, ecKey, "ec", jwtgo.MapClaims{
+				"email": "user1@example.com", "exp": exp, "iss": "https://tokens.example.com", "aud": []string{"other", "foulkon"},
+			}),
+			expectedUserID:     "user1@example.com",
+			expectedStatusCode: http.StatusOK,
+		},
+		"ErrorCaseNoToken": {
+			userClaim:          DEFAULT_USER_CLAIM,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: no bearer token found",
+		},
+		"ErrorCaseExpired": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": time.Now().Add(-time.Hour).Unix(),
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: Token is expired",
+		},
+		"ErrorCaseNoExpiration": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1",
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: token has no expiration time",
+		},
+		"ErrorCaseInvalidSignature": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, otherKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: crypto/rsa: verification error",
+		},
+		"ErrorCaseUnknownKey": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "unknown", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: Unknown signing key unknown",
+		},
+		"ErrorCaseSigningMethodMismatch": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodES256, ecKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: unexpected signing method ES256 for key rsa",
+		},
+		"ErrorCaseHMAC": {
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodHS256, []byte("secret"), "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: signing method HS256 is invalid",
+		},
+		"ErrorCaseIssuer": {
+			issuer:    "https://tokens.example.com",
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp, "iss": "https://other.example.com",
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: unexpected issuer https://other.example.com",
+		},
+		"ErrorCaseAudience": {
+			audience:  "foulkon",
+			userClaim: DEFAULT_USER_CLAIM,
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp, "aud": "other",
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: unexpected audience other",
+		},
+		"ErrorCaseNoUserClaim": {
+			userClaim: "email",
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp,
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "jwt authenticator: no user ID found in claim email",
+		},
+	}
+
+	for n, testcase := range testcases {
+		testLogger, hook := test.NewNullLogger()
+		api.Log = testLogger
+
+		connector := InitJWTConnector(keySet, testcase.issuer, testcase.audience, testcase.userClaim)
+		var userID string
+		handler := connector.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID = connector.RetrieveUserID(*r)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if testcase.authorization != "" {
+			req.Header.Set("Authorization", testcase.authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		// Check status code
+		assert.Equal(t, testcase.expectedStatusCode, w.Code, "Error in test case %v", n)
+		if testcase.expectedStatusCode == http.StatusOK {
+			assert.Equal(t, testcase.expectedUserID, userID, "Error in test case %v", n)
+			assert.Equal(t, testcase.expectedUserID, req.Header.Get(middleware.USER_ID_HEADER), "Error in test case %v", n)
+		} else {
+			// Check logger
+			if assert.NotNil(t, hook.LastEntry(), "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedLog, hook.LastEntry().Message, "Error in test case %v", n)
+			}
+		}
+	}
+}
+
+func TestJWTAuthConnector_Recognizes(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	testcases := map[string]struct {
+		// Connector args
+		issuer string
+		// Request token
+		authorization string
+
+		expectedResult bool
+	}{
+		"OKCaseBearerWithoutIssuer": {
+			authorization:  "Bearer opaque-token",
+			expectedResult: true,
+		},
+		"OKCaseIssuer": {
+			issuer: "https://tokens.example.com",
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp, "iss": "https://tokens.example.com",
+			}),
+			expectedResult: true,
+		},
+		"OKCaseOtherIssuer": {
+			issuer: "https://tokens.example.com",
+			authorization: "Bearer " + signToken(t, jwtgo.SigningMethodRS256, rsaKey, "rsa", jwtgo.MapClaims{
+				"sub": "user1", "exp": exp, "iss": "https://other.example.com",
+			}),
+		},
+		"OKCaseNoBearer": {
+			authorization: "ApiKey id.secret",
+		},
+	}
+
+	for n, testcase := range testcases {
+		connector := InitJWTConnector(&KeySet{}, testcase.issuer, "", DEFAULT_USER_CLAIM)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", testcase.authorization)
+		result := connector.(auth.Recognizer).Recognizes(req)
+		assert.Equal(t, testcase.expectedResult, result, "Error in test case %v", n)
+	}
+}
@@ -0,0 +1,250 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// Max time to retrieve a JWKS from URL
+	JWKS_REQUEST_TIMEOUT = 10 * time.Second
+)
+
+// KeySet holds public keys that verify token signatures, by key ID. A key without ID verifies
+// tokens signed with any key ID.
+type KeySet struct {
+	load    func() (map[string]interface{}, error)
+	refresh time.Duration
+
+	lock       sync.Mutex
+	keys       map[string]interface{}
+	expiration time.Time
+	// Keys are being loaded again
+	refreshing bool
+}
+
+// JSON Web Key, only fields of RSA and EC public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// NewPEMKeySet returns a KeySet with the RSA or EC public key of a PEM file
+func NewPEMKeySet(file string) (*KeySet, error) {
+	return newKeySet(func() (map[string]interface{}, error) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parsePEMPublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"": key}, nil
+	}, 0)
+}
+
+// NewJWKSFileKeySet returns a KeySet with the public keys of a JWKS file
+func NewJWKSFileKeySet(file string) (*KeySet, error) {
+	return newKeySet(func() (map[string]interface{}, error) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return parseJWKS(data)
+	}, 0)
+}
+
+// NewJWKSURLKeySet returns a KeySet with the public keys of a JWKS retrieved from url, that is
+// retrieved again after refresh time
+func NewJWKSURLKeySet(url string, refresh time.Duration) (*KeySet, error) {
+	client := &http.Client{Timeout: JWKS_REQUEST_TIMEOUT}
+	return newKeySet(func() (map[string]interface{}, error) {
+		res, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Unexpected status code %v retrieving JWKS from %v", res.StatusCode, url)
+		}
+		data, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return parseJWKS(data)
+	}, refresh)
+}
+
+func newKeySet(load func() (map[string]interface{}, error), refresh time.Duration) (*KeySet, error) {
+	keys, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{
+		load:       load,
+		refresh:    refresh,
+		keys:       keys,
+		expiration: time.Now().Add(refresh),
+	}, nil
+}
+
+// Key returns public key with keyID. If refresh time has passed, keys are loaded again, keeping
+// previous ones if load fails.
+func (ks *KeySet) Key(keyID string) (interface{}, error) {
+	keys := ks.currentKeys()
+	if key, ok := keys[keyID]; ok {
+		return key, nil
+	}
+	if key, ok := keys[""]; ok {
+		return key, nil
+	}
+	if keyID == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown signing key %v", keyID)
+}
+
+// currentKeys returns keys of KeySet. Only the first caller after refresh time loads them again,
+// without holding the lock, and other callers get previous keys until they are loaded.
+func (ks *KeySet) currentKeys() map[string]interface{} {
+	ks.lock.Lock()
+	if ks.refresh <= 0 || ks.refreshing || !time.Now().After(ks.expiration) {
+		defer ks.lock.Unlock()
+		return ks.keys
+	}
+	ks.refreshing = true
+	ks.lock.Unlock()
+
+	keys, err := ks.load()
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	if err != nil {
+		api.Log.Warnf("Couldn't refresh JWT keys, using previous ones: %v", err)
+	} else {
+		ks.keys = keys
+	}
+	ks.expiration = time.Now().Add(ks.refresh)
+	ks.refreshing = false
+	return ks.keys
+}
+
+// parsePEMPublicKey returns RSA or EC public key of PEM data
+func parsePEMPublicKey(data []byte) (interface{}, error) {
+	if key, err := jwtgo.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwtgo.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("Invalid PEM public key, it must be an RSA or EC public key")
+}
+
+// parseJWKS returns signature public keys of a JWKS by key ID. Keys of other types or uses are ignored.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	jwks := new(jsonWebKeySet)
+	if err := json.Unmarshal(data, jwks); err != nil {
+		return nil, fmt.Errorf("Invalid JWKS: %v", err)
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaPublicKey()
+		case "EC":
+			key, err = jwk.ecPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JWKS key %v: %v", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) < 1 {
+		return nil, errors.New("Invalid JWKS, it has no RSA or EC signature keys")
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (jwk jsonWebKey) ecPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %v", jwk.Crv)
+	}
+	x, err := decodeBigInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeBigInt decodes a base64url encoded big-endian number
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) < 1 {
+		return nil, errors.New("invalid base64url number")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,196 @@
+package jwt
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Aux method that returns a JWKS with RSA test key
+func rsaJWKS(keyID string) string {
+	return fmt.Sprintf(`{"keys": [{"kty": "RSA", "kid": "%v", "use": "sig", "n": "%v", "e": "%v"}]}`, keyID,
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()))
+}
+
+func TestParseJWKS(t *testing.T) {
+	ecJWK := fmt.Sprintf(`{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "%v", "y": "%v"}`,
+		base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+		base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()))
+	testcases := map[string]struct {
+		jwks string
+
+		expectedKeys  map[string]interface{}
+		expectedError string
+	}{
+		"OKCase": {
+			jwks: rsaJWKS("rsa"),
+			expectedKeys: map[string]interface{}{
+				"rsa": &rsaKey.PublicKey,
+			},
+		},
+		"OKCaseIgnoredKeys": {
+			jwks: `{"keys": [` + ecJWK + `, {"kty": "RSA", "kid": "enc", "use": "enc"}, {"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}]}`,
+			expectedKeys: map[string]interface{}{
+				"ec": &ecKey.PublicKey,
+			},
+		},
+		"ErrorCaseInvalidJSON": {
+			jwks:          `{"keys": `,
+			expectedError: "Invalid JWKS: unexpected end of JSON input",
+		},
+		"ErrorCaseNoKeys": {
+			jwks:          `{"keys": [{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}]}`,
+			expectedError: "Invalid JWKS, it has no RSA or EC signature keys",
+		},
+		"ErrorCaseInvalidCurve": {
+			jwks:          `{"keys": [{"kty": "EC", "kid": "ec", "crv": "P-192", "x": "AQ", "y": "AQ"}]}`,
+			expectedError: "Invalid JWKS key ec: unsupported curve P-192",
+		},
+		"ErrorCaseInvalidNumber": {
+			jwks:          `{"keys": [{"kty": "RSA", "kid": "rsa", "n": "", "e": "AQAB"}]}`,
+			expectedError: "Invalid JWKS key rsa: invalid base64url number",
+		},
+	}
+
+	for n, test := range testcases {
+		keys, err := parseJWKS([]byte(test.jwks))
+		if test.expectedError != "" {
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, test.expectedError, err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedKeys, keys, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestNewPEMKeySet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "foulkon-jwt")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	der, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	keyFile := filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	ioutil.WriteFile(invalidFile, []byte("invalid"), 0600)
+
+	testcases := map[string]struct {
+		file string
+
+		expectedError string
+	}{
+		"OKCase": {
+			file: keyFile,
+		},
+		"ErrorCaseInvalidKey": {
+			file:          invalidFile,
+			expectedError: "Invalid PEM public key, it must be an RSA or EC public key",
+		},
+		"ErrorCaseNoFile": {
+			file:          filepath.Join(dir, "none.pem"),
+			expectedError: "open " + filepath.Join(dir, "none.pem") + ": no such file or directory",
+		},
+	}
+
+	for n, test := range testcases {
+		keySet, err := NewPEMKeySet(test.file)
+		if test.expectedError != "" {
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, test.expectedError, err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			// Static key verifies any key ID
+			key, err := keySet.Key("any")
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, &ecKey.PublicKey, key, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestNewJWKSURLKeySet(t *testing.T) {
+	jwks := rsaJWKS("key1")
+	statusCode := http.StatusOK
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(jwks))
+	}))
+	defer jwksServer.Close()
+
+	keySet, err := NewJWKSURLKeySet(jwksServer.URL, time.Millisecond)
+	assert.Nil(t, err, "Error in test")
+	_, err = keySet.Key("key1")
+	assert.Nil(t, err, "Error in test")
+
+	// Check rotated keys are retrieved again after refresh time
+	jwks = rsaJWKS("key2")
+	time.Sleep(5 * time.Millisecond)
+	_, err = keySet.Key("key2")
+	assert.Nil(t, err, "Error in test")
+	_, err = keySet.Key("key1")
+	assert.Equal(t, "Unknown signing key key1", err.Error(), "Error in test")
+
+	// Check previous keys are kept if JWKS can't be retrieved
+	statusCode = http.StatusInternalServerError
+	time.Sleep(5 * time.Millisecond)
+	_, err = keySet.Key("key2")
+	assert.Nil(t, err, "Error in test")
+
+	// Check initial retrieval error
+	_, err = NewJWKSURLKeySet(jwksServer.URL, time.Millisecond)
+	assert.Equal(t, fmt.Sprintf("Unexpected status code 500 retrieving JWKS from %v", jwksServer.URL), err.Error(), "Error in test")
+}
+
+func TestKeySet_KeyWhileRefreshing(t *testing.T) {
+	loads := make(chan struct{}, 10)
+	release := make(chan struct{})
+	keySet, err := newKeySet(func() (map[string]interface{}, error) {
+		loads <- struct{}{}
+		if len(loads) > 1 {
+			<-release
+			return map[string]interface{}{"key2": &rsaKey.PublicKey}, nil
+		}
+		return map[string]interface{}{"key1": &rsaKey.PublicKey}, nil
+	}, time.Millisecond)
+	assert.Nil(t, err, "Error in test")
+	time.Sleep(5 * time.Millisecond)
+
+	// Start refresh, that waits for release
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := keySet.Key("key2")
+		refreshed <- err
+	}()
+	for len(loads) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Check previous keys are returned without waiting for refresh, and keys are loaded once
+	for i := 0; i < 3; i++ {
+		_, err = keySet.Key("key1")
+		assert.Nil(t, err, "Error in test")
+	}
+	assert.Equal(t, 2, len(loads), "Error in test")
+
+	// Check refreshed keys are returned once loaded
+	close(release)
+	assert.Nil(t, <-refreshed, "Error in test")
+	_, err = keySet.Key("key1")
+	assert.Equal(t, "Unknown signing key key1", err.Error(), "Error in test")
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Lockout types
+	LOCKOUT_TYPE_IP       = "ip"
+	LOCKOUT_TYPE_USERNAME = "username"
+
+	// Max source IPs and usernames tracked, the ones with oldest failures are forgotten first
+	ADMIN_LOCKOUT_MAX_ENTRIES = 10000
+)
+
+// LockoutState is the failed admin logins of a source IP or username
+type LockoutState struct {
+	Type        string     `json:"type"`
+	Value       string     `json:"value"`
+	Failures    int        `json:"failures"`
+	LastFailure time.Time  `json:"lastFailure"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+}
+
+// lockoutKey identifies a source IP or username
+type lockoutKey struct {
+	kind  string
+	value string
+}
+
+// lockoutEntry counts consecutive failed admin logins
+type lockoutEntry struct {
+	failures    int
+	lastFailure time.Time
+}
+
+// AdminLockout tracks failed admin logins by source IP and by username. After maxAttempts consecutive
+// failures they are locked out for lockout time, doubled with each new failure up to maxLockout time.
+// Failures are forgotten after a successful login or maxLockout time without failures.
+type AdminLockout struct {
+	lock        sync.Mutex
+	maxAttempts int
+	lockout     time.Duration
+	maxLockout  time.Duration
+	entries     map[lockoutKey]*lockoutEntry
+	now         func() time.Time
+}
+
+// NewAdminLockout returns an admin lockout with its configuration. Lockout is disabled if maxAttempts is 0.
+func NewAdminLockout(maxAttempts int, lockout time.Duration, maxLockout time.Duration) *AdminLockout {
+	return &AdminLockout{
+		maxAttempts: maxAttempts,
+		lockout:     lockout,
+		maxLockout:  maxLockout,
+		entries:     make(map[lockoutKey]*lockoutEntry),
+		now:         time.Now,
+	}
+}
+
+// Update replaces lockout configuration, keeping current failures. Used when configuration is reloaded
+func (l *AdminLockout) Update(maxAttempts int, lockout time.Duration, maxLockout time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.maxAttempts = maxAttempts
+	l.lockout = lockout
+	l.maxLockout = maxLockout
+}
+
+// Check returns if source IP or username are locked out, with the time left
+func (l *AdminLockout) Check(ip string, username string) (time.Duration, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	var retryAfter time.Duration
+	for _, key := range lockoutKeys(ip, username) {
+		if entry, ok := l.entries[key]; ok {
+			if lockedUntil := l.lockedUntil(entry); lockedUntil != nil && lockedUntil.After(now) {
+				if left := lockedUntil.Sub(now); left > retryAfter {
+					retryAfter = left
+				}
+			}
+		}
+	}
+	return retryAfter, retryAfter > 0
+}
+
+// Fail records a failed admin login of source IP and username
+func (l *AdminLockout) Fail(ip string, username string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	for _, key := range lockoutKeys(ip, username) {
+		entry, ok := l.entries[key]
+		if !ok || l.expired(entry, now) {
+			if !ok && len(l.entries) >= ADMIN_LOCKOUT_MAX_ENTRIES {
+				l.evict(now)
+			}
+			entry = &lockoutEntry{}
+			l.entries[key] = entry
+		}
+		entry.failures++
+		entry.lastFailure = now
+	}
+}
+
+// Succeed forgets failed admin logins of source IP and username
+func (l *AdminLockout) Succeed(ip string, username string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, key := range lockoutKeys(ip, username) {
+		delete(l.entries, key)
+	}
+}
+
+// States returns failed admin logins not forgotten yet, sorted by type and value
+func (l *AdminLockout) States() []LockoutState {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	states := []LockoutState{}
+	for key, entry := range l.entries {
+		if l.expired(entry, now) {
+			continue
+		}
+		states = append(states, LockoutState{
+			Type:        key.kind,
+			Value:       key.value,
+			Failures:    entry.failures,
+			LastFailure: entry.lastFailure.UTC(),
+			LockedUntil: l.lockedUntil(entry),
+		})
+	}
+	sort.Sort(lockoutStates(states))
+	return states
+}
+
+// PRIVATE HELPER METHODS
+
+// lockoutStates sorts lockout states by type and value
+type lockoutStates []LockoutState
+
+func (s lockoutStates) Len() int      { return len(s) }
+func (s lockoutStates) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s lockoutStates) Less(i, j int) bool {
+	if s[i].Type != s[j].Type {
+		return s[i].Type < s[j].Type
+	}
+	return s[i].Value < s[j].Value
+}
+
+// lockoutKeys returns keys of source IP and username
+func lockoutKeys(ip string, username string) []lockoutKey {
+	return []lockoutKey{
+		{kind: LOCKOUT_TYPE_IP, value: ip},
+		{kind: LOCKOUT_TYPE_USERNAME, value: username},
+	}
+}
+
+// lockedUntil returns end of lockout of entry, nil if it isn't locked out
+func (l *AdminLockout) lockedUntil(entry *lockoutEntry) *time.Time {
+	if l.maxAttempts < 1 || entry.failures < l.maxAttempts {
+		return nil
+	}
+	lockout := l.lockout
+	for i := l.maxAttempts; i < entry.failures && lockout < l.maxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > l.maxLockout {
+		lockout = l.maxLockout
+	}
+	lockedUntil := entry.lastFailure.Add(lockout).UTC()
+	return &lockedUntil
+}
+
+// expired checks if failures of entry are forgotten
+func (l *AdminLockout) expired(entry *lockoutEntry, now time.Time) bool {
+	return now.Sub(entry.lastFailure) > l.maxLockout
+}
+
+// evict removes forgotten entries, or the one with oldest failure if there isn't any
+func (l *AdminLockout) evict(now time.Time) {
+	var oldestKey *lockoutKey
+	var oldest time.Time
+	for key, entry := range l.entries {
+		if l.expired(entry, now) {
+			delete(l.entries, key)
+			continue
+		}
+		if oldestKey == nil || entry.lastFailure.Before(oldest) {
+			k := key
+			oldestKey, oldest = &k, entry.lastFailure
+		}
+	}
+	if len(l.entries) >= ADMIN_LOCKOUT_MAX_ENTRIES && oldestKey != nil {
+		delete(l.entries, *oldestKey)
+	}
+}
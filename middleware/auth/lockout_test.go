@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Aux method that returns an admin lockout with a clock set to now
+func newTestAdminLockout(maxAttempts int, now *time.Time) *AdminLockout {
+	lockout := NewAdminLockout(maxAttempts, time.Minute, 10*time.Minute)
+	lockout.now = func() time.Time {
+		return *now
+	}
+	return lockout
+}
+
+func TestAdminLockout_Check(t *testing.T) {
+	testcases := map[string]struct {
+		maxAttempts int
+		// Failed logins of ip 10.0.0.1 and username admin
+		failures int
+		// Time since last failure
+		elapsed time.Duration
+		// Checked ip and username
+		ip       string
+		username string
+		// Expected result
+		expectedLocked     bool
+		expectedRetryAfter time.Duration
+	}{
+		"OkCaseBelowMaxAttempts": {
+			maxAttempts: 5,
+			failures:    4,
+			ip:          "10.0.0.1",
+			username:    "admin",
+		},
+		"OkCaseLockedOut": {
+			maxAttempts:        5,
+			failures:           5,
+			elapsed:            10 * time.Second,
+			ip:                 "10.0.0.1",
+			username:           "admin",
+			expectedLocked:     true,
+			expectedRetryAfter: 50 * time.Second,
+		},
+		"OkCaseLockedOutByIP": {
+			maxAttempts:        5,
+			failures:           5,
+			ip:                 "10.0.0.1",
+			username:           "other",
+			expectedLocked:     true,
+			expectedRetryAfter: time.Minute,
+		},
+		"OkCaseLockedOutByUsername": {
+			maxAttempts:        5,
+			failures:           5,
+			ip:                 "10.0.0.2",
+			username:           "admin",
+			expectedLocked:     true,
+			expectedRetryAfter: time.Minute,
+		},
+		"OkCaseOtherIPAndUsername": {
+			maxAttempts: 5,
+			failures:    5,
+			ip:          "10.0.0.2",
+			username:    "other",
+		},
+		"OkCaseLockoutEnded": {
+			maxAttempts: 5,
+			failures:    5,
+			elapsed:     time.Minute,
+			ip:          "10.0.0.1",
+			username:    "admin",
+		},
+		"OkCaseBackoff": {
+			maxAttempts:        5,
+			failures:           7,
+			ip:                 "10.0.0.1",
+			username:           "admin",
+			expectedLocked:     true,
+			expectedRetryAfter: 4 * time.Minute,
+		},
+		"OkCaseMaxLockout": {
+			maxAttempts:        5,
+			failures:           20,
+			ip:                 "10.0.0.1",
+			username:           "admin",
+			expectedLocked:     true,
+			expectedRetryAfter: 10 * time.Minute,
+		},
+		"OkCaseDisabled": {
+			maxAttempts: 0,
+			failures:    20,
+			ip:          "10.0.0.1",
+			username:    "admin",
+		},
+	}
+
+	for n, testcase := range testcases {
+		now := time.Now()
+		lockout := newTestAdminLockout(testcase.maxAttempts, &now)
+		for i := 0; i < testcase.failures; i++ {
+			lockout.Fail("10.0.0.1", "admin")
+		}
+		now = now.Add(testcase.elapsed)
+
+		retryAfter, locked := lockout.Check(testcase.ip, testcase.username)
+		assert.Equal(t, testcase.expectedLocked, locked, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedRetryAfter, retryAfter, "Error in test case %v", n)
+	}
+}
+
+func TestAdminLockout_Succeed(t *testing.T) {
+	now := time.Now()
+	lockout := newTestAdminLockout(5, &now)
+	for i := 0; i < 5; i++ {
+		lockout.Fail("10.0.0.1", "admin")
+	}
+	lockout.Succeed("10.0.0.1", "admin")
+
+	_, locked := lockout.Check("10.0.0.1", "admin")
+	assert.False(t, locked)
+	assert.Equal(t, []LockoutState{}, lockout.States())
+}
+
+func TestAdminLockout_States(t *testing.T) {
+	now := time.Now()
+	lockout := newTestAdminLockout(2, &now)
+	lockout.Fail("10.0.0.2", "bob")
+	lockout.Fail("10.0.0.1", "alice")
+	lockout.Fail("10.0.0.1", "alice")
+	lockedUntil := now.Add(time.Minute).UTC()
+
+	assert.Equal(t, []LockoutState{
+		{Type: LOCKOUT_TYPE_IP, Value: "10.0.0.1", Failures: 2, LastFailure: now.UTC(), LockedUntil: &lockedUntil},
+		{Type: LOCKOUT_TYPE_IP, Value: "10.0.0.2", Failures: 1, LastFailure: now.UTC()},
+		{Type: LOCKOUT_TYPE_USERNAME, Value: "alice", Failures: 2, LastFailure: now.UTC(), LockedUntil: &lockedUntil},
+		{Type: LOCKOUT_TYPE_USERNAME, Value: "bob", Failures: 1, LastFailure: now.UTC()},
+	}, lockout.States())
+
+	// Failures are forgotten after max lockout time
+	now = now.Add(11 * time.Minute)
+	assert.Equal(t, []LockoutState{}, lockout.States())
+	lockout.Fail("10.0.0.1", "alice")
+	states := lockout.States()
+	if assert.Len(t, states, 2) {
+		assert.Equal(t, 1, states[0].Failures)
+		assert.Equal(t, 1, states[1].Failures)
+	}
+}
@@ -0,0 +1,116 @@
+package mtls
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+)
+
+const (
+	// Certificate fields with the user ID
+	USER_FIELD_CN    = "cn"
+	USER_FIELD_EMAIL = "email"
+	USER_FIELD_DNS   = "dns"
+	USER_FIELD_URI   = "uri"
+)
+
+// MTLSAuthConnector represents a connector that implements interface of auth connector, identifying
+// callers by the client certificate verified in the TLS handshake
+type MTLSAuthConnector struct {
+	userField   string
+	userPattern *regexp.Regexp
+}
+
+// InitMTLSConnector initializes mTLS connector configuration. User ID is the subject CN or the first
+// SAN of userField type. If userPattern isn't nil, user ID is the first value matching it, or its first
+// capture group if it has one.
+func InitMTLSConnector(userField string, userPattern *regexp.Regexp) (auth.AuthConnector, error) {
+	switch userField {
+	case USER_FIELD_CN, USER_FIELD_EMAIL, USER_FIELD_DNS, USER_FIELD_URI:
+	default:
+		return nil, fmt.Errorf("Invalid mtls user field %v, it must be one of %v, %v, %v or %v",
+			userField, USER_FIELD_CN, USER_FIELD_EMAIL, USER_FIELD_DNS, USER_FIELD_URI)
+	}
+	return &MTLSAuthConnector{
+		userField:   userField,
+		userPattern: userPattern,
+	}, nil
+}
+
+// Authenticate maps verified client certificate of request to a user ID and adds it to request
+func (c MTLSAuthConnector) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		userID, err := c.validate(r)
+		if err != nil {
+			apiError := &api.Error{
+				Code:    api.AUTHENTICATION_API_ERROR,
+				Message: fmt.Sprintf("mtls authenticator: %v", err),
+			}
+			requestID := r.Header.Get(middleware.REQUEST_ID_HEADER)
+			api.LogOperationError(requestID, "", apiError)
+			http.Error(rw, fmt.Sprintf("Error %v", apiError.Message), http.StatusUnauthorized)
+			return
+		}
+		r.Header.Add(middleware.USER_ID_HEADER, userID)
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Recognizes checks request has a verified client certificate
+func (c MTLSAuthConnector) Recognizes(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
+// RetrieveUserID retrieves user from validated certificate
+func (c MTLSAuthConnector) RetrieveUserID(r http.Request) string {
+	return r.Header.Get(middleware.USER_ID_HEADER)
+}
+
+// validate checks request has a verified client certificate, returning its user ID
+func (c MTLSAuthConnector) validate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", errors.New("no verified client certificate found")
+	}
+	certificate := r.TLS.VerifiedChains[0][0]
+
+	for _, value := range certificateValues(certificate, c.userField) {
+		if value == "" {
+			continue
+		}
+		if c.userPattern == nil {
+			return value, nil
+		}
+		match := c.userPattern.FindStringSubmatch(value)
+		if len(match) == 1 {
+			return value, nil
+		}
+		if len(match) > 1 && match[1] != "" {
+			return match[1], nil
+		}
+	}
+	return "", fmt.Errorf("no user ID found in %v of client certificate with subject %v", c.userField, certificate.Subject.CommonName)
+}
+
+// certificateValues returns values of a certificate field
+func certificateValues(certificate *x509.Certificate, field string) []string {
+	switch field {
+	case USER_FIELD_EMAIL:
+		return certificate.EmailAddresses
+	case USER_FIELD_DNS:
+		return certificate.DNSNames
+	case USER_FIELD_URI:
+		values := []string{}
+		for _, uri := range certificate.URIs {
+			values = append(values, uri.String())
+		}
+		return values
+	default:
+		return []string{certificate.Subject.CommonName}
+	}
+}
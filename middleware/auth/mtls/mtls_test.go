@@ -0,0 +1,153 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitMTLSConnector(t *testing.T) {
+	testcases := map[string]struct {
+		userField string
+		// Expected result
+		expectedError string
+	}{
+		"OKCaseCN": {
+			userField: USER_FIELD_CN,
+		},
+		"OKCaseURI": {
+			userField: USER_FIELD_URI,
+		},
+		"ErrorCaseInvalidUserField": {
+			userField:     "serial",
+			expectedError: "Invalid mtls user field serial, it must be one of cn, email, dns or uri",
+		},
+	}
+
+	for n, testcase := range testcases {
+		connector, err := InitMTLSConnector(testcase.userField, nil)
+		if testcase.expectedError != "" {
+			assert.Nil(t, connector, "Error in test case %v", n)
+			if assert.NotNil(t, err, "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedError, err.Error(), "Error in test case %v", n)
+			}
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.NotNil(t, connector, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestMTLSAuthConnector_Authenticate(t *testing.T) {
+	spiffeURI, _ := url.Parse("spiffe://example.com/ns/prod/sa/billing")
+	certificate := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "billing-service"},
+		EmailAddresses: []string{"billing@example.com"},
+		DNSNames:       []string{"billing.internal", "billing.example.com"},
+		URIs:           []*url.URL{spiffeURI},
+	}
+	testcases := map[string]struct {
+		// Connector args
+		userField   string
+		userPattern *regexp.Regexp
+		// Request TLS connection
+		tlsState *tls.ConnectionState
+
+		expectedUserID     string
+		expectedStatusCode int
+		expectedLog        string
+	}{
+		"OKCaseCN": {
+			userField:          USER_FIELD_CN,
+			tlsState:           &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedUserID:     "billing-service",
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseEmail": {
+			userField:          USER_FIELD_EMAIL,
+			tlsState:           &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedUserID:     "billing@example.com",
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseDNSWithPattern": {
+			userField:          USER_FIELD_DNS,
+			userPattern:        regexp.MustCompile(`\.example\.com$`),
+			tlsState:           &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedUserID:     "billing.example.com",
+			expectedStatusCode: http.StatusOK,
+		},
+		"OKCaseURIWithCaptureGroup": {
+			userField:          USER_FIELD_URI,
+			userPattern:        regexp.MustCompile(`^spiffe://example\.com/ns/prod/sa/(.+)$`),
+			tlsState:           &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedUserID:     "billing",
+			expectedStatusCode: http.StatusOK,
+		},
+		"ErrorCaseNoTLS": {
+			userField:          USER_FIELD_CN,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "mtls authenticator: no verified client certificate found",
+		},
+		"ErrorCaseUnverifiedCertificate": {
+			userField:          USER_FIELD_CN,
+			tlsState:           &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "mtls authenticator: no verified client certificate found",
+		},
+		"ErrorCaseNoMatchingValue": {
+			userField:          USER_FIELD_URI,
+			userPattern:        regexp.MustCompile(`^spiffe://example\.com/ns/dev/sa/(.+)$`),
+			tlsState:           &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "mtls authenticator: no user ID found in uri of client certificate with subject billing-service",
+		},
+		"ErrorCaseEmptyCN": {
+			userField: USER_FIELD_CN,
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+				{DNSNames: []string{"billing.internal"}},
+			}}},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLog:        "mtls authenticator: no user ID found in cn of client certificate with subject ",
+		},
+	}
+
+	for n, testcase := range testcases {
+		testLogger, hook := test.NewNullLogger()
+		api.Log = testLogger
+
+		connector, err := InitMTLSConnector(testcase.userField, testcase.userPattern)
+		if !assert.Nil(t, err, "Error in test case %v", n) {
+			continue
+		}
+		var userID string
+		handler := connector.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID = connector.RetrieveUserID(*r)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.TLS = testcase.tlsState
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		// Check status code
+		assert.Equal(t, testcase.expectedStatusCode, w.Code, "Error in test case %v", n)
+		if testcase.expectedStatusCode == http.StatusOK {
+			assert.Equal(t, testcase.expectedUserID, userID, "Error in test case %v", n)
+			assert.Equal(t, testcase.expectedUserID, req.Header.Get(middleware.USER_ID_HEADER), "Error in test case %v", n)
+		} else {
+			// Check logger
+			if assert.NotNil(t, hook.LastEntry(), "Error in test case %v", n) {
+				assert.Equal(t, testcase.expectedLog, hook.LastEntry().Message, "Error in test case %v", n)
+			}
+		}
+	}
+}
@@ -2,23 +2,104 @@ package oidc
 
 import (
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"fmt"
 
 	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
 	"github.com/Tecsisa/foulkon/middleware"
 	"github.com/Tecsisa/foulkon/middleware/auth"
 	"github.com/emanoelxavier/openid2go/openid"
 )
 
+const (
+	// Max users whose last group sync is kept
+	SYNCED_GROUPS_CACHE_SIZE = 10000
+)
+
 // OIDCAuthConnector represents an OIDC connector that implements interface of auth connector
 type OIDCAuthConnector struct {
-	configuration openid.Configuration
+	configuration    openid.Configuration
+	getOidcProviders OidcProvidersGetter
+	provisioner      UserProvisioner
+	syncedGroups     *syncedGroupsCache
+}
+
+// OidcProvidersGetter returns OIDC providers accepted by the connector
+type OidcProvidersGetter func() ([]api.OidcProvider, error)
+
+// UserProvisioner creates users authenticated by OIDC providers with user provisioning, and syncs
+// their memberships to groups mapped from claims
+type UserProvisioner interface {
+	ProvisionOidcUser(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+		claims map[string]interface{}) (*api.User, error)
+	SyncOidcUserGroups(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+		claims map[string]interface{}) error
+}
+
+// ProviderCache retrieves OIDC providers from repository and keeps them during refresh time,
+// so created, updated and removed providers are applied without restarting the worker
+type ProviderCache struct {
+	repo    api.AuthOidcRepo
+	refresh time.Duration
+
+	lock       sync.Mutex
+	loaded     bool
+	providers  []api.OidcProvider
+	expiration time.Time
+}
+
+// NewProviderCache returns a ProviderCache that retrieves providers from repo after refresh time
+func NewProviderCache(repo api.AuthOidcRepo, refresh time.Duration) *ProviderCache {
+	return &ProviderCache{
+		repo:    repo,
+		refresh: refresh,
+	}
+}
+
+// GetOidcProviders returns current OIDC providers, retrieving them from repository if refresh time
+// has passed. If repository fails, previous providers are returned until next refresh.
+func (pc *ProviderCache) GetOidcProviders() ([]api.OidcProvider, error) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	now := time.Now()
+	if pc.loaded && now.Before(pc.expiration) {
+		return pc.providers, nil
+	}
+
+	oidcProviders, _, err := pc.repo.GetOidcProvidersFiltered(&api.Filter{})
+	if err != nil {
+		//Transform to DB error
+		dbError := err.(*database.Error)
+		if !pc.loaded {
+			return nil, &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: dbError.Message,
+			}
+		}
+		api.Log.Warnf("Couldn't refresh OIDC providers, using previous ones: %v", dbError.Message)
+	} else {
+		pc.providers = oidcProviders
+		pc.loaded = true
+	}
+	pc.expiration = now.Add(pc.refresh)
+
+	return pc.providers, nil
 }
 
-// InitOIDCConnector initializes OIDC connector configuration
-func InitOIDCConnector(oidcProviders []api.OidcProvider) (auth.AuthConnector, error) {
+// InitOIDCConnector initializes OIDC connector configuration. Providers are read with getOidcProviders
+// on each authentication. If provisioner isn't nil, users of providers with user provisioning are created
+// on their first authentication, and memberships of groups mapped in sync mode are updated.
+func InitOIDCConnector(getOidcProviders OidcProvidersGetter, provisioner UserProvisioner) (auth.AuthConnector, error) {
 	getProviders := func() ([]openid.Provider, error) {
+		oidcProviders, err := getOidcProviders()
+		if err != nil {
+			return nil, err
+		}
 		providers := []openid.Provider{}
 		for _, oc := range oidcProviders {
 			clientIds := []string{}
@@ -46,7 +127,7 @@ func InitOIDCConnector(oidcProviders []api.OidcProvider) (auth.AuthConnector, er
 		} else {
 			apiError := &api.Error{
 				Code:    api.AUTHENTICATION_API_ERROR,
-				Message: validationErr.Message,
+				Message: e.Error(),
 			}
 			api.LogOperationError(requestID, "", apiError)
 			http.Error(rw, "Unexpected error", http.StatusInternalServerError)
@@ -56,7 +137,10 @@ func InitOIDCConnector(oidcProviders []api.OidcProvider) (auth.AuthConnector, er
 	}
 	configuration, _ := openid.NewConfiguration(openid.ProvidersGetter(getProviders), openid.ErrorHandler(errorHandler))
 	return &OIDCAuthConnector{
-		configuration: *configuration,
+		configuration:    *configuration,
+		getOidcProviders: getOidcProviders,
+		provisioner:      provisioner,
+		syncedGroups:     newSyncedGroupsCache(),
 	}, nil
 
 }
@@ -65,6 +149,15 @@ func InitOIDCConnector(oidcProviders []api.OidcProvider) (auth.AuthConnector, er
 func (c OIDCAuthConnector) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userHandler := func(u *openid.User, w http.ResponseWriter, r *http.Request) {
+			if err := c.provisionUser(u, r); err != nil {
+				apiError := &api.Error{
+					Code:    api.AUTHENTICATION_API_ERROR,
+					Message: fmt.Sprintf("oidc authenticator: unable to provision user %v: %v", u.ID, err),
+				}
+				api.LogOperationError(r.Header.Get(middleware.REQUEST_ID_HEADER), u.ID, apiError)
+				http.Error(w, "Error unable to provision user", http.StatusUnauthorized)
+				return
+			}
 			r.Header.Add(middleware.USER_ID_HEADER, u.ID)
 			next.ServeHTTP(w, r)
 		}
@@ -74,6 +167,115 @@ func (c OIDCAuthConnector) Authenticate(next http.Handler) http.Handler {
 
 }
 
+// Recognizes checks request has a bearer token issued by one of the OIDC providers
+func (c OIDCAuthConnector) Recognizes(r *http.Request) bool {
+	issuer := auth.BearerTokenIssuer(r)
+	if issuer == "" {
+		return false
+	}
+	oidcProviders, err := c.getOidcProviders()
+	if err != nil {
+		return false
+	}
+	for _, oidcProvider := range oidcProviders {
+		if oidcProvider.IssuerURL == issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// provisionUser creates authenticated user if its provider has user provisioning and it doesn't exist,
+// and applies groups mapped from its claims, adding them to request or syncing its memberships
+func (c OIDCAuthConnector) provisionUser(u *openid.User, r *http.Request) error {
+	oidcProviders, err := c.getOidcProviders()
+	if err != nil {
+		return err
+	}
+	for _, oidcProvider := range oidcProviders {
+		if oidcProvider.IssuerURL != u.Issuer {
+			continue
+		}
+		requestInfo := api.RequestInfo{
+			Identifier: u.ID,
+			RequestID:  r.Header.Get(middleware.REQUEST_ID_HEADER),
+			Context:    r.Context(),
+		}
+		if oidcProvider.UserProvisioning != nil && c.provisioner != nil {
+			if _, err := c.provisioner.ProvisionOidcUser(requestInfo, oidcProvider, u.ID, u.Claims); err != nil {
+				return err
+			}
+		}
+		switch oidcProvider.GroupMappingMode {
+		case api.GROUP_MAPPING_MODE_REQUEST:
+			groups := []string{}
+			for _, group := range oidcProvider.MappedGroups(u.Claims) {
+				groups = append(groups, group.Org+"/"+group.Name)
+			}
+			if len(groups) > 0 {
+				r.Header.Set(middleware.AUTH_GROUPS_HEADER, strings.Join(groups, ","))
+			}
+		case api.GROUP_MAPPING_MODE_SYNC:
+			if c.provisioner == nil {
+				return nil
+			}
+			// Memberships are only synced again with a new token or when mapped groups change
+			key := oidcProvider.ID + "/" + u.ID
+			synced := syncedGroupsValue(oidcProvider, u.Claims)
+			if c.syncedGroups.isSynced(key, synced) {
+				return nil
+			}
+			if err := c.provisioner.SyncOidcUserGroups(requestInfo, oidcProvider, u.ID, u.Claims); err != nil {
+				return err
+			}
+			c.syncedGroups.setSynced(key, synced)
+		}
+		return nil
+	}
+	return nil
+}
+
+// syncedGroupsCache keeps, by provider and user, the token and mapped groups of the last group sync
+type syncedGroupsCache struct {
+	lock   sync.Mutex
+	synced map[string]string
+}
+
+func newSyncedGroupsCache() *syncedGroupsCache {
+	return &syncedGroupsCache{
+		synced: make(map[string]string),
+	}
+}
+
+// isSynced checks last group sync of key was done with value
+func (sc *syncedGroupsCache) isSynced(key string, value string) bool {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	synced, ok := sc.synced[key]
+	return ok && synced == value
+}
+
+// setSynced records group sync of key with value. Cache is emptied when it's full.
+func (sc *syncedGroupsCache) setSynced(key string, value string) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	if _, ok := sc.synced[key]; !ok && len(sc.synced) >= SYNCED_GROUPS_CACHE_SIZE {
+		sc.synced = make(map[string]string)
+	}
+	sc.synced[key] = value
+}
+
+// syncedGroupsValue identifies a group sync by provider update time, token issue time and ID, and
+// groups mapped from claims
+func syncedGroupsValue(oidcProvider api.OidcProvider, claims map[string]interface{}) string {
+	groups := []string{}
+	for _, group := range oidcProvider.MappedGroups(claims) {
+		groups = append(groups, group.Org+"/"+group.Name)
+	}
+	return fmt.Sprintf("%v|%v|%v|%v", oidcProvider.UpdateAt.UnixNano(), claims["iat"], claims["jti"],
+		strings.Join(groups, ","))
+}
+
 // Retrieve user from OIDC token
 func (c OIDCAuthConnector) RetrieveUserID(r http.Request) string {
 	userID := r.Header.Get(middleware.USER_ID_HEADER)
@@ -0,0 +1,336 @@
+package oidc
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/Tecsisa/foulkon/database"
+	"github.com/Tecsisa/foulkon/middleware"
+	"github.com/Tecsisa/foulkon/middleware/auth"
+	"github.com/emanoelxavier/openid2go/openid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Aux repo that returns configured providers, counting calls
+type testOidcRepo struct {
+	api.AuthOidcRepo
+	providers []api.OidcProvider
+	err       error
+	calls     int
+}
+
+func (r *testOidcRepo) GetOidcProvidersFiltered(filter *api.Filter) ([]api.OidcProvider, int, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return r.providers, len(r.providers), nil
+}
+
+func TestProviderCache_GetOidcProviders(t *testing.T) {
+	testLogger, _ := test.NewNullLogger()
+	api.Log = testLogger
+
+	provider1 := api.OidcProvider{ID: "ID1", Name: "provider1", IssuerURL: "https://issuer1"}
+	provider2 := api.OidcProvider{ID: "ID2", Name: "provider2", IssuerURL: "https://issuer2"}
+	dbError := &database.Error{
+		Code:    database.INTERNAL_ERROR,
+		Message: "Error",
+	}
+	testcases := map[string]struct {
+		// Repo values on first and second call
+		providers    []api.OidcProvider
+		err          error
+		newProviders []api.OidcProvider
+		newErr       error
+		// Wait before second call
+		refresh time.Duration
+		wait    time.Duration
+
+		expectedProviders []api.OidcProvider
+		expectedCalls     int
+		expectedError     *api.Error
+	}{
+		"OKCaseCached": {
+			providers:         []api.OidcProvider{provider1},
+			newProviders:      []api.OidcProvider{provider1, provider2},
+			refresh:           time.Hour,
+			expectedProviders: []api.OidcProvider{provider1},
+			expectedCalls:     1,
+		},
+		"OKCaseRefreshed": {
+			providers:         []api.OidcProvider{provider1},
+			newProviders:      []api.OidcProvider{provider2},
+			refresh:           time.Millisecond,
+			wait:              5 * time.Millisecond,
+			expectedProviders: []api.OidcProvider{provider2},
+			expectedCalls:     2,
+		},
+		"OKCaseRepoErrorKeepsPrevious": {
+			providers:         []api.OidcProvider{provider1},
+			newErr:            dbError,
+			refresh:           time.Millisecond,
+			wait:              5 * time.Millisecond,
+			expectedProviders: []api.OidcProvider{provider1},
+			expectedCalls:     2,
+		},
+		"ErrorCaseNotLoaded": {
+			err:    dbError,
+			newErr: dbError,
+			expectedError: &api.Error{
+				Code:    api.UNKNOWN_API_ERROR,
+				Message: "Error",
+			},
+			refresh:       time.Hour,
+			expectedCalls: 2,
+		},
+	}
+
+	for n, test := range testcases {
+		repo := &testOidcRepo{providers: test.providers, err: test.err}
+		cache := NewProviderCache(repo, test.refresh)
+		cache.GetOidcProviders()
+
+		repo.providers, repo.err = test.newProviders, test.newErr
+		time.Sleep(test.wait)
+		providers, err := cache.GetOidcProviders()
+		if test.expectedError != nil {
+			assert.Equal(t, test.expectedError, err, "Error in test case %v", n)
+		} else {
+			assert.Nil(t, err, "Error in test case %v", n)
+			assert.Equal(t, test.expectedProviders, providers, "Error in test case %v", n)
+		}
+		assert.Equal(t, test.expectedCalls, repo.calls, "Error in test case %v", n)
+	}
+}
+
+func TestOIDCAuthConnector_Recognizes(t *testing.T) {
+	getOidcProviders := func() ([]api.OidcProvider, error) {
+		return []api.OidcProvider{{ID: "ID1", Name: "provider1", IssuerURL: "https://issuer1"}}, nil
+	}
+	testcases := map[string]struct {
+		authorization string
+
+		expectedResult bool
+	}{
+		"OkCaseProviderIssuer": {
+			authorization:  testBearerToken(`{"iss":"https://issuer1"}`),
+			expectedResult: true,
+		},
+		"OkCaseUnknownIssuer": {
+			authorization: testBearerToken(`{"iss":"https://issuer2"}`),
+		},
+		"OkCaseNoBearer": {
+			authorization: "ApiKey id.secret",
+		},
+	}
+
+	connector, err := InitOIDCConnector(getOidcProviders, nil)
+	assert.Nil(t, err)
+	for n, testcase := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", testcase.authorization)
+		result := connector.(auth.Recognizer).Recognizes(req)
+		assert.Equal(t, testcase.expectedResult, result, "Error in test case %v", n)
+	}
+}
+
+// Aux provisioner that records provisioned users and synced providers
+type testProvisioner struct {
+	provider     string
+	externalID   string
+	err          error
+	syncProvider string
+	syncs        int
+}
+
+func (tp *testProvisioner) ProvisionOidcUser(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+	claims map[string]interface{}) (*api.User, error) {
+	tp.provider = oidcProvider.Name
+	tp.externalID = externalId
+	return nil, tp.err
+}
+
+func (tp *testProvisioner) SyncOidcUserGroups(requestInfo api.RequestInfo, oidcProvider api.OidcProvider, externalId string,
+	claims map[string]interface{}) error {
+	tp.syncProvider = oidcProvider.Name
+	tp.syncs++
+	return tp.err
+}
+
+func TestOIDCAuthConnector_provisionUser(t *testing.T) {
+	getOidcProviders := func() ([]api.OidcProvider, error) {
+		return []api.OidcProvider{
+			{Name: "provider1", IssuerURL: "https://issuer1"},
+			{Name: "provider2", IssuerURL: "https://issuer2", UserProvisioning: &api.UserProvisioning{}},
+			{
+				Name:      "provider3",
+				IssuerURL: "https://issuer3",
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+					{Claim: "groups", Value: "eng-frontend", Org: "acme", Group: "frontend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_REQUEST,
+			},
+			{
+				Name:      "provider4",
+				IssuerURL: "https://issuer4",
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_SYNC,
+			},
+		}, nil
+	}
+	testcases := map[string]struct {
+		user           *openid.User
+		provisionerErr error
+
+		expectedProvider     string
+		expectedSyncProvider string
+		expectedGroups       string
+		expectedError        error
+	}{
+		"OkCaseProvisioningProvider": {
+			user:             &openid.User{Issuer: "https://issuer2", ID: "user1"},
+			expectedProvider: "provider2",
+		},
+		"OkCaseProviderWithoutProvisioning": {
+			user: &openid.User{Issuer: "https://issuer1", ID: "user1"},
+		},
+		"OkCaseRequestGroupMappings": {
+			user: &openid.User{
+				Issuer: "https://issuer3",
+				ID:     "user1",
+				Claims: map[string]interface{}{"groups": []interface{}{"eng-backend", "eng-frontend"}},
+			},
+			expectedGroups: "acme/backend,acme/frontend",
+		},
+		"OkCaseSyncGroupMappings": {
+			user: &openid.User{
+				Issuer: "https://issuer4",
+				ID:     "user1",
+				Claims: map[string]interface{}{"groups": []interface{}{"eng-backend"}},
+			},
+			expectedSyncProvider: "provider4",
+		},
+		"ErrorCaseProvisionerError": {
+			user:             &openid.User{Issuer: "https://issuer2", ID: "user1"},
+			provisionerErr:   &api.Error{Code: api.INVALID_PARAMETER_ERROR},
+			expectedProvider: "provider2",
+			expectedError:    &api.Error{Code: api.INVALID_PARAMETER_ERROR},
+		},
+	}
+
+	for n, testcase := range testcases {
+		provisioner := &testProvisioner{err: testcase.provisionerErr}
+		connector := OIDCAuthConnector{
+			getOidcProviders: getOidcProviders,
+			provisioner:      provisioner,
+			syncedGroups:     newSyncedGroupsCache(),
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		err := connector.provisionUser(testcase.user, req)
+		assert.Equal(t, testcase.expectedError, err, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedProvider, provisioner.provider, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedSyncProvider, provisioner.syncProvider, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedGroups, req.Header.Get(middleware.AUTH_GROUPS_HEADER), "Error in test case %v", n)
+		if testcase.expectedProvider != "" {
+			assert.Equal(t, testcase.user.ID, provisioner.externalID, "Error in test case %v", n)
+		}
+	}
+}
+
+func TestOIDCAuthConnector_provisionUserSyncedGroups(t *testing.T) {
+	getOidcProviders := func() ([]api.OidcProvider, error) {
+		return []api.OidcProvider{
+			{
+				ID:        "provider1",
+				Name:      "provider1",
+				IssuerURL: "https://issuer1",
+				GroupMappings: []api.GroupMapping{
+					{Claim: "groups", Value: "eng-backend", Org: "acme", Group: "backend"},
+					{Claim: "groups", Value: "eng-frontend", Org: "acme", Group: "frontend"},
+				},
+				GroupMappingMode: api.GROUP_MAPPING_MODE_SYNC,
+			},
+		}, nil
+	}
+	provisioner := &testProvisioner{}
+	connector := OIDCAuthConnector{
+		getOidcProviders: getOidcProviders,
+		provisioner:      provisioner,
+		syncedGroups:     newSyncedGroupsCache(),
+	}
+
+	// Requests in order
+	testcases := []struct {
+		userID         string
+		claims         map[string]interface{}
+		provisionerErr error
+		// Expected result
+		expectedSyncs int
+		expectedError error
+	}{
+		{
+			userID:        "user1",
+			claims:        map[string]interface{}{"iat": 1, "groups": []interface{}{"eng-backend"}},
+			expectedSyncs: 1,
+		},
+		// Same token
+		{
+			userID:        "user1",
+			claims:        map[string]interface{}{"iat": 1, "groups": []interface{}{"eng-backend"}},
+			expectedSyncs: 1,
+		},
+		// Other user
+		{
+			userID:        "user2",
+			claims:        map[string]interface{}{"iat": 1, "groups": []interface{}{"eng-backend"}},
+			expectedSyncs: 2,
+		},
+		// Changed groups
+		{
+			userID:        "user1",
+			claims:        map[string]interface{}{"iat": 1, "groups": []interface{}{"eng-backend", "eng-frontend"}},
+			expectedSyncs: 3,
+		},
+		// New token
+		{
+			userID:        "user1",
+			claims:        map[string]interface{}{"iat": 2, "groups": []interface{}{"eng-backend", "eng-frontend"}},
+			expectedSyncs: 4,
+		},
+		// Failed sync is retried
+		{
+			userID:         "user1",
+			claims:         map[string]interface{}{"iat": 3, "groups": []interface{}{"eng-backend"}},
+			provisionerErr: &api.Error{Code: api.UNKNOWN_API_ERROR},
+			expectedSyncs:  5,
+			expectedError:  &api.Error{Code: api.UNKNOWN_API_ERROR},
+		},
+		{
+			userID:        "user1",
+			claims:        map[string]interface{}{"iat": 3, "groups": []interface{}{"eng-backend"}},
+			expectedSyncs: 6,
+		},
+	}
+
+	for n, testcase := range testcases {
+		provisioner.err = testcase.provisionerErr
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		err := connector.provisionUser(&openid.User{Issuer: "https://issuer1", ID: testcase.userID, Claims: testcase.claims}, req)
+		assert.Equal(t, testcase.expectedError, err, "Error in test case %v", n)
+		assert.Equal(t, testcase.expectedSyncs, provisioner.syncs, "Error in test case %v", n)
+	}
+}
+
+// Aux method that returns an unsigned JWT with a payload
+func testBearerToken(payload string) string {
+	return "Bearer e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
@@ -18,7 +18,8 @@ func NewRequestLoggerMiddleware() *RequestLoggerMiddleware {
 // Log all request received
 func (reqLogger *RequestLoggerMiddleware) Action(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		api.TransactionRequestLog(r.Header.Get(middleware.REQUEST_ID_HEADER), r.Header.Get(middleware.USER_ID_HEADER), r)
+		api.TransactionRequestLog(r.Header.Get(middleware.REQUEST_ID_HEADER), r.Header.Get(middleware.USER_ID_HEADER),
+			r.Header.Get(middleware.AUTH_CONNECTOR_HEADER), r)
 		next.ServeHTTP(w, r)
 	})
 }
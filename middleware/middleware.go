@@ -1,11 +1,21 @@
 package middleware
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+
+	"github.com/Tecsisa/foulkon/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
 
 const (
 	// HTTP Header
 	REQUEST_ID_HEADER = "X-Request-Id"
 	USER_ID_HEADER    = "X-FOULKON-USER-ID"
+	// Name of authenticator connector that authenticated the user
+	AUTH_CONNECTOR_HEADER = "X-FOULKON-AUTH-CONNECTOR"
+	// Comma separated org/name of groups mapped from identity provider claims, effective only for the request
+	AUTH_GROUPS_HEADER = "X-FOULKON-AUTH-GROUPS"
 
 	// Middleware names
 	AUTHENTICATOR_MIDDLEWARE  = "AUTHENTICATOR"
@@ -21,8 +31,11 @@ type MiddlewareHandler struct {
 // MiddlewareContext struct contains all parameters used in the context of middlewares
 type MiddlewareContext struct {
 	// Authenticator middleware
-	UserId string
-	Admin  bool
+	UserId        string
+	Admin         bool
+	AuthConnector string
+	// Groups mapped from identity provider claims, as org/name
+	MappedGroups []string
 
 	// X-Request-Id middleware
 	XRequestId string
@@ -47,16 +60,16 @@ func (mwh *MiddlewareHandler) Handle(apiHandler http.Handler) http.Handler {
 	// Middleware execution order is upside-down because when adding an action
 	// it executes itself first, then the rest of the old handler.
 	if val, ok := mwh.Middlewares[REQUEST_LOGGER_MIDDLEWARE]; ok {
-		handler = val.Action(handler)
+		handler = traceMiddleware(REQUEST_LOGGER_MIDDLEWARE, val, handler)
 	}
 	if val, ok := mwh.Middlewares[AUTHENTICATOR_MIDDLEWARE]; ok {
-		handler = val.Action(handler)
+		handler = traceMiddleware(AUTHENTICATOR_MIDDLEWARE, val, handler)
 	}
 	if val, ok := mwh.Middlewares[XREQUESTID_MIDDLEWARE]; ok {
-		handler = val.Action(handler)
+		handler = traceMiddleware(XREQUESTID_MIDDLEWARE, val, handler)
 	}
 
-	return handler
+	return traceRequest(handler)
 }
 
 // GetMiddlewareContext method retrieves all information about middleware context applied to request
@@ -68,3 +81,33 @@ func (mwh *MiddlewareHandler) GetMiddlewareContext(r *http.Request) *MiddlewareC
 
 	return context
 }
+
+// parentContextKey is the context key of request context before a middleware span starts
+type parentContextKey struct{}
+
+// traceRequest starts the server span of the request, continuing the trace received in traceparent header
+func traceRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracing.StartServer(r, "HTTP "+r.Method)
+		defer span.End()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// traceMiddleware records middleware action in a span that ends when the middleware calls next handler,
+// so next handler continues in the request span
+func traceMiddleware(name string, m Middleware, next http.Handler) http.Handler {
+	handler := m.Action(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		trace.SpanFromContext(ctx).End()
+		if parent, ok := ctx.Value(parentContextKey{}).(context.Context); ok {
+			ctx = parent
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracing.Start(r.Context(), "middleware."+name)
+		defer span.End()
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(ctx, parentContextKey{}, r.Context())))
+	})
+}
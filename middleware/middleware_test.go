@@ -7,6 +7,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
@@ -119,6 +124,70 @@ func TestMiddlewareHandler_GetMiddlewareContext(t *testing.T) {
 
 }
 
+func TestMiddlewareHandler_HandleTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var handlerSpan trace.SpanContext
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	mwh := getMiddlewareHandler(map[string]Middleware{
+		AUTHENTICATOR_MIDDLEWARE: &TestMiddleware{
+			HeaderValue: AUTHENTICATOR_MIDDLEWARE,
+		},
+		XREQUESTID_MIDDLEWARE: &TestMiddleware{
+			HeaderValue: XREQUESTID_MIDDLEWARE,
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	mwh.Handle(testHandler).ServeHTTP(w, req)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	requestSpan, ok := spans["HTTP GET"]
+	if !ok {
+		t.Fatal("Request span not found")
+	}
+
+	testcases := map[string]struct {
+		span           string
+		expectedParent trace.SpanID
+	}{
+		"OKCaseRequest": {
+			span:           "HTTP GET",
+			expectedParent: requestSpan.Parent().SpanID(),
+		},
+		"OKCaseXRequestId": {
+			span:           "middleware." + XREQUESTID_MIDDLEWARE,
+			expectedParent: requestSpan.SpanContext().SpanID(),
+		},
+		"OKCaseAuthenticator": {
+			span:           "middleware." + AUTHENTICATOR_MIDDLEWARE,
+			expectedParent: requestSpan.SpanContext().SpanID(),
+		},
+	}
+
+	// Handler runs in request span, continuing received trace
+	assert.Equal(t, "00f067aa0ba902b7", requestSpan.Parent().SpanID().String(), "Error in test")
+	assert.Equal(t, requestSpan.SpanContext(), handlerSpan, "Error in test")
+	for n, test := range testcases {
+		span, ok := spans[test.span]
+		if !ok {
+			t.Errorf("Test case %v. Span %v not found", n, test.span)
+			continue
+		}
+		assert.Equal(t, requestSpan.SpanContext().TraceID(), span.SpanContext().TraceID(), "Error in test case %v", n)
+		assert.Equal(t, test.expectedParent, span.Parent().SpanID(), "Error in test case %v", n)
+	}
+}
+
 // Private helper methods
 func getMiddlewareHandler(middlewares map[string]Middleware) *MiddlewareHandler {
 	return &MiddlewareHandler{Middlewares: middlewares}
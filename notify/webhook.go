@@ -0,0 +1,219 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Webhook request headers
+	EVENT_HEADER     = "X-Foulkon-Event"
+	DELIVERY_HEADER  = "X-Foulkon-Delivery"
+	SIGNATURE_HEADER = "X-Foulkon-Signature"
+
+	// Signature prefix with HMAC algorithm
+	SIGNATURE_PREFIX = "sha256="
+)
+
+// WebhookDispatcher sends events to subscribed webhooks in background, retrying
+// failed deliveries with exponential backoff and storing every attempt.
+// Retries are scheduled with timers, so a failing webhook doesn't hold a worker while it waits.
+type WebhookDispatcher struct {
+	repo       api.WebhookRepo
+	client     *http.Client
+	queue      chan api.Event
+	retries    chan *webhookDelivery
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	mutex      sync.RWMutex
+	stopped    bool
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewWebhookDispatcher creates a dispatcher and starts its workers
+func NewWebhookDispatcher(repo api.WebhookRepo, workers int, queueSize int, maxRetries int,
+	backoff time.Duration, timeout time.Duration) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		repo:       repo,
+		client:     &http.Client{Timeout: timeout},
+		queue:      make(chan api.Event, queueSize),
+		retries:    make(chan *webhookDelivery),
+		stop:       make(chan struct{}),
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.run()
+	}
+	return d
+}
+
+// Notify queues an event. Event is discarded if queue is full or dispatcher is stopped.
+func (d *WebhookDispatcher) Notify(event api.Event) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if d.stopped {
+		return
+	}
+	select {
+	case d.queue <- event:
+	default:
+		api.Log.Warnf("Webhook queue is full, event %v with type %v discarded", event.ID, event.Type)
+	}
+}
+
+// Stop stops workers, aborting pending retries, and waits for them
+func (d *WebhookDispatcher) Stop() {
+	d.mutex.Lock()
+	if d.stopped {
+		d.mutex.Unlock()
+		return
+	}
+	d.stopped = true
+	close(d.stop)
+	close(d.queue)
+	d.mutex.Unlock()
+	d.wg.Wait()
+}
+
+// Sign returns the signature of a payload using secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+// PRIVATE HELPER METHODS
+
+// webhookDelivery is a pending delivery of an event to a webhook
+type webhookDelivery struct {
+	webhook api.Webhook
+	event   api.Event
+	payload []byte
+	attempt int
+	wait    time.Duration
+}
+
+func (d *WebhookDispatcher) run() {
+	defer d.wg.Done()
+	for {
+		select {
+		case event, ok := <-d.queue:
+			if !ok {
+				return
+			}
+			d.dispatch(event)
+		case delivery := <-d.retries:
+			d.deliver(delivery)
+		}
+	}
+}
+
+// dispatch sends event to all subscribed webhooks
+func (d *WebhookDispatcher) dispatch(event api.Event) {
+	webhooks, _, err := d.repo.GetWebhooksFiltered(&api.Filter{})
+	if err != nil {
+		api.Log.Errorf("Couldn't retrieve webhooks for event %v: %v", event.ID, err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		api.Log.Errorf("Couldn't serialize event %v: %v", event.ID, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.IsSubscribed(event.Type) {
+			d.deliver(&webhookDelivery{
+				webhook: webhook,
+				event:   event,
+				payload: payload,
+				attempt: 1,
+				wait:    d.backoff,
+			})
+		}
+	}
+}
+
+// deliver makes a delivery attempt, scheduling a retry with exponential backoff if it fails
+func (d *WebhookDispatcher) deliver(delivery *webhookDelivery) {
+	webhook, event := delivery.webhook, delivery.event
+	statusCode, err := d.send(webhook, event, delivery.payload)
+
+	attempt := api.WebhookDelivery{
+		ID:         uuid.NewV4().String(),
+		WebhookID:  webhook.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Attempt:    delivery.attempt,
+		StatusCode: statusCode,
+		Success:    err == nil,
+		CreateAt:   time.Now().UTC(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	if err := d.repo.AddWebhookDelivery(attempt); err != nil {
+		api.Log.Errorf("Couldn't store delivery of event %v to webhook %v: %v", event.ID, webhook.Name, err)
+	}
+
+	if err == nil {
+		return
+	}
+	api.Log.Warnf("Delivery attempt %v of event %v to webhook %v failed: %v", delivery.attempt, event.ID, webhook.Name, err)
+
+	if delivery.attempt > d.maxRetries {
+		api.Log.Errorf("Event %v couldn't be delivered to webhook %v", event.ID, webhook.Name)
+		return
+	}
+	retry := &webhookDelivery{
+		webhook: webhook,
+		event:   event,
+		payload: delivery.payload,
+		attempt: delivery.attempt + 1,
+		wait:    delivery.wait * 2,
+	}
+	time.AfterFunc(delivery.wait, func() {
+		// Retry is handed to next free worker, it is aborted if dispatcher is stopped
+		select {
+		case d.retries <- retry:
+		case <-d.stop:
+		}
+	})
+}
+
+// send makes the webhook request and returns the status code received
+func (d *WebhookDispatcher) send(webhook api.Webhook, event api.Event, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EVENT_HEADER, event.Type)
+	req.Header.Set(DELIVERY_HEADER, event.ID)
+	req.Header.Set(SIGNATURE_HEADER, Sign(webhook.Secret, payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("Unexpected status code %v", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
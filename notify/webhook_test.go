@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/Tecsisa/foulkon/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type testWebhookRepo struct {
+	api.WebhookRepo
+	webhooks   []api.Webhook
+	mutex      sync.Mutex
+	deliveries []api.WebhookDelivery
+}
+
+func (r *testWebhookRepo) GetWebhooksFiltered(filter *api.Filter) ([]api.Webhook, int, error) {
+	return r.webhooks, len(r.webhooks), nil
+}
+
+func (r *testWebhookRepo) AddWebhookDelivery(delivery api.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+type testRequest struct {
+	event     string
+	delivery  string
+	signature string
+	body      []byte
+}
+
+func init() {
+	api.Log = &logrus.Logger{
+		Out:       bytes.NewBuffer([]byte{}),
+		Formatter: &logrus.TextFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.DebugLevel,
+	}
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func TestWebhookDispatcher_Notify(t *testing.T) {
+	testcases := map[string]struct {
+		// Webhook server status codes by attempt
+		statusCodes []int
+		events      []string
+		// Expected result
+		expectedRequests   int
+		expectedDeliveries []bool
+	}{
+		"OKCase": {
+			statusCodes:        []int{http.StatusOK},
+			events:             []string{api.EVENT_USER_CREATED},
+			expectedRequests:   1,
+			expectedDeliveries: []bool{true},
+		},
+		"OKCaseRetry": {
+			statusCodes:        []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent},
+			events:             []string{api.EVENT_ALL},
+			expectedRequests:   3,
+			expectedDeliveries: []bool{false, false, true},
+		},
+		"OKCaseNotSubscribed": {
+			statusCodes:      []int{http.StatusOK},
+			events:           []string{api.EVENT_GROUP_CREATED},
+			expectedRequests: 0,
+		},
+		"ErrorCaseMaxRetries": {
+			statusCodes:        []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			events:             []string{api.EVENT_USER_CREATED},
+			expectedRequests:   3,
+			expectedDeliveries: []bool{false, false, false},
+		},
+	}
+
+	for x, testcase := range testcases {
+		var mutex sync.Mutex
+		requests := []testRequest{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			mutex.Lock()
+			requests = append(requests, testRequest{
+				event:     r.Header.Get(EVENT_HEADER),
+				delivery:  r.Header.Get(DELIVERY_HEADER),
+				signature: r.Header.Get(SIGNATURE_HEADER),
+				body:      body,
+			})
+			w.WriteHeader(testcase.statusCodes[len(requests)-1])
+			mutex.Unlock()
+		}))
+
+		repo := &testWebhookRepo{
+			webhooks: []api.Webhook{
+				{
+					ID:     "1",
+					Name:   "webhook",
+					URL:    server.URL,
+					Secret: "secret",
+					Events: testcase.events,
+				},
+			},
+		}
+		dispatcher := NewWebhookDispatcher(repo, 1, 10, 2, time.Millisecond, time.Second)
+		dispatcher.Notify(api.Event{
+			ID:   "event1",
+			Type: api.EVENT_USER_CREATED,
+		})
+		// Stop waits for queued events, but pending retries are aborted, so wait for deliveries first
+		for i := 0; i < 100; i++ {
+			repo.mutex.Lock()
+			n := len(repo.deliveries)
+			repo.mutex.Unlock()
+			if n >= len(testcase.expectedDeliveries) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		dispatcher.Stop()
+		server.Close()
+
+		assert.Len(t, requests, testcase.expectedRequests, "Error in test case %v", x)
+		for _, req := range requests {
+			assert.Equal(t, api.EVENT_USER_CREATED, req.event, "Error in test case %v", x)
+			assert.Equal(t, "event1", req.delivery, "Error in test case %v", x)
+			assert.Equal(t, Sign("secret", req.body), req.signature, "Error in test case %v", x)
+		}
+		assert.Len(t, repo.deliveries, len(testcase.expectedDeliveries), "Error in test case %v", x)
+		for i, success := range testcase.expectedDeliveries {
+			assert.Equal(t, i+1, repo.deliveries[i].Attempt, "Error in test case %v", x)
+			assert.Equal(t, success, repo.deliveries[i].Success, "Error in test case %v", x)
+			assert.Equal(t, testcase.statusCodes[i], repo.deliveries[i].StatusCode, "Error in test case %v", x)
+		}
+	}
+}
+
+func TestWebhookDispatcher_NotifyStopped(t *testing.T) {
+	repo := &testWebhookRepo{}
+	dispatcher := NewWebhookDispatcher(repo, 1, 1, 0, time.Millisecond, time.Second)
+	dispatcher.Stop()
+	// Notify after stop mustn't panic nor block
+	dispatcher.Notify(api.Event{ID: "event1", Type: api.EVENT_USER_CREATED})
+	dispatcher.Stop()
+}
+
+func TestWebhookDispatcher_NotifyRetryDoesntBlock(t *testing.T) {
+	var mutex sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests[r.URL.Path]++
+		mutex.Unlock()
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &testWebhookRepo{
+		webhooks: []api.Webhook{
+			{
+				ID:     "1",
+				Name:   "failing",
+				URL:    server.URL + "/failing",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+			{
+				ID:     "2",
+				Name:   "working",
+				URL:    server.URL + "/working",
+				Secret: "secret",
+				Events: []string{api.EVENT_ALL},
+			},
+		},
+	}
+	// Only one worker, and backoff much longer than the test
+	dispatcher := NewWebhookDispatcher(repo, 1, 10, 1, time.Hour, time.Second)
+	dispatcher.Notify(api.Event{ID: "event1", Type: api.EVENT_USER_CREATED})
+	dispatcher.Notify(api.Event{ID: "event2", Type: api.EVENT_USER_CREATED})
+
+	// Working webhook receives both events while failing webhook waits for its retries
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return requests["/working"] == 2 && requests["/failing"] == 2
+	}, time.Second, 10*time.Millisecond, "Error in test")
+
+	// Stop doesn't wait for pending retries
+	stopped := make(chan struct{})
+	go func() {
+		dispatcher.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("Dispatcher didn't stop with pending retries")
+	}
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Name of the tracer used by all Foulkon spans
+	TRACER_NAME = "github.com/Tecsisa/foulkon"
+
+	// Service names
+	SERVICE_WORKER = "foulkon-worker"
+	SERVICE_PROXY  = "foulkon-proxy"
+
+	// Exporter types
+	EXPORTER_NONE   = "none"
+	EXPORTER_OTLP   = "otlp"
+	EXPORTER_STDOUT = "stdout"
+)
+
+var provider *sdktrace.TracerProvider
+
+func init() {
+	// W3C traceparent header is propagated even if spans aren't exported
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Init registers the global tracer provider that exports spans of service with exporter type.
+// OTLP exporter sends spans over HTTP to endpoint. With type none, spans aren't recorded.
+func Init(service string, version string, exporterType string, endpoint string, insecure bool, sampleRate float64) error {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterType {
+	case EXPORTER_NONE:
+		return nil
+	case EXPORTER_OTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+		if insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), options...)
+	case EXPORTER_STDOUT:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return fmt.Errorf("Unexpected tracing exporter type: '%s'", exporterType)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", service),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return err
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+	)
+	otel.SetTracerProvider(provider)
+	return nil
+}
+
+// Shutdown exports pending spans and stops the tracer provider if it was initialized
+func Shutdown() error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(context.Background())
+}
+
+// Start creates a span with name as child of the span in ctx, if any
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(TRACER_NAME).Start(ctx, name, opts...)
+}
+
+// StartServer creates a server span with name for an incoming request, as child of the
+// remote span received in its traceparent header
+func StartServer(r *http.Request, name string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+	span.SetAttributes(
+		attribute.String("http.method", r.Method),
+		attribute.String("http.target", r.URL.Path),
+	)
+	return ctx, span
+}
+
+// Inject sets traceparent header of an outgoing request with the span in ctx
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// SetError marks span as failed with err
+func SetError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestInit(t *testing.T) {
+	testcases := map[string]struct {
+		exporterType string
+
+		expectedProvider bool
+		expectedError    bool
+	}{
+		"OKCaseNone": {
+			exporterType: EXPORTER_NONE,
+		},
+		"OKCaseStdout": {
+			exporterType:     EXPORTER_STDOUT,
+			expectedProvider: true,
+		},
+		"OKCaseOtlp": {
+			exporterType:     EXPORTER_OTLP,
+			expectedProvider: true,
+		},
+		"ErrorCaseUnknownExporter": {
+			exporterType:  "jaeger",
+			expectedError: true,
+		},
+	}
+
+	for n, test := range testcases {
+		provider = nil
+		err := Init(SERVICE_WORKER, "test", test.exporterType, "localhost:4318", true, 1)
+
+		// Check result
+		assert.Equal(t, test.expectedError, err != nil, "Error in test case %v", n)
+		assert.Equal(t, test.expectedProvider, provider != nil, "Error in test case %v", n)
+		assert.Nil(t, Shutdown(), "Error in test case %v", n)
+	}
+	provider = nil
+	otel.SetTracerProvider(noop.NewTracerProvider())
+}
+
+func TestInjectAndStartServer(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	testcases := map[string]struct {
+		traced bool
+	}{
+		"OKCaseTraceparent": {
+			traced: true,
+		},
+		"OKCaseNoTraceparent": {
+			traced: false,
+		},
+	}
+
+	for n, test := range testcases {
+		r, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+		clientCtx := context.Background()
+		if test.traced {
+			ctx, span := Start(clientCtx, "client")
+			span.End()
+			clientCtx = ctx
+			Inject(clientCtx, r.Header)
+		}
+
+		_, serverSpan := StartServer(r, "server")
+		serverSpan.End()
+
+		// Check server span continues client trace
+		parent := serverSpan.(sdktrace.ReadOnlySpan).Parent()
+		assert.Equal(t, test.traced, parent.IsValid(), "Error in test case %v", n)
+		assert.Equal(t, test.traced, parent.IsRemote(), "Error in test case %v", n)
+		if test.traced {
+			clientSpan := trace.SpanContextFromContext(clientCtx)
+			assert.Equal(t, clientSpan.TraceID(), serverSpan.SpanContext().TraceID(), "Error in test case %v", n)
+			assert.Equal(t, clientSpan.SpanID(), parent.SpanID(), "Error in test case %v", n)
+		}
+	}
+}